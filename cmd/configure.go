@@ -26,9 +26,14 @@ var configureCmd = &cobra.Command{
 This command will guide you through setting up a log source (Splunk, OpenSearch, 
 Kubernetes, Docker, SSH, or CloudWatch) and generate a ready-to-use config file.
 
+Secrets (tokens, passwords, API keys, Authorization headers) are written to the
+config as ${ENV} references, and their values to a separate secrets file, so the
+config is safe to commit. Use --keep-secrets to keep them inline instead.
+
 Example:
   logviewer configure
-  logviewer configure -c /path/to/config.yaml`,
+  logviewer configure -c /path/to/config.yaml
+  logviewer configure --keep-secrets`,
 	Run: func(_ *cobra.Command, _ []string) {
 		if err := runConfigWizard(configPath); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -37,7 +42,10 @@ Example:
 	},
 }
 
+var keepSecrets bool
+
 func init() {
+	configureCmd.Flags().BoolVar(&keepSecrets, "keep-secrets", false, "Keep secrets inline in the config file instead of replacing them with ${ENV} references")
 	rootCmd.AddCommand(configureCmd)
 }
 
@@ -177,6 +185,12 @@ func runConfigWizard(cfgPath string) error {
 		Search: searchConfig,
 	}
 
+	// Replace plaintext secrets with references before anything is shown or written
+	var secrets []config.RedactedSecret
+	if !keepSecrets {
+		secrets = config.RedactSecrets(&cfg)
+	}
+
 	// 4. Preview Configuration
 	out, err := yaml.Marshal(cfg)
 	if err != nil {
@@ -288,18 +302,45 @@ func runConfigWizard(cfgPath string) error {
 		for k, v := range cfg.Contexts {
 			existingCfg.Contexts[k] = v
 		}
+		// Existing clients would otherwise round-trip their plaintext secrets
+		if !keepSecrets {
+			secrets = append(secrets, config.RedactSecrets(existingCfg)...)
+		}
 		out, err = yaml.Marshal(existingCfg)
 		if err != nil {
 			return fmt.Errorf("failed to merge with existing config: %w", err)
 		}
 	}
 
+	secretsPath := ""
+	if len(secrets) > 0 {
+		secretsPath = filepath.Join(configDir, "secrets.env")
+		secretsForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("Where should the secrets be stored?").
+					Description(fmt.Sprintf("%d secret(s) are referenced as ${ENV} in the config; their values are written here as export lines", len(secrets))).
+					Value(&secretsPath),
+			),
+		)
+		if err := secretsForm.Run(); err != nil {
+			return err
+		}
+		if err := writeSecretsFile(secretsPath, secrets); err != nil {
+			return err
+		}
+	}
+
 	if err := os.WriteFile(configPath, out, 0600); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
 	// Success message with next steps
 	fmt.Printf("\n✅ Configuration saved to %s\n\n", configPath)
+	if secretsPath != "" {
+		fmt.Printf("🔐 Secrets saved to %s (keep this file out of version control)\n", secretsPath)
+		fmt.Printf("   Load them before running logviewer: source %s\n\n", secretsPath)
+	}
 	fmt.Println("🎉 You're all set! Try it now:")
 	fmt.Printf("   logviewer query -i %s\n\n", contextName)
 
@@ -313,6 +354,52 @@ func runConfigWizard(cfgPath string) error {
 	return nil
 }
 
+// writeSecretsFile stores redacted secrets as shell export lines. Variables
+// already present in the file are updated in place, other lines are kept.
+func writeSecretsFile(path string, secrets []config.RedactedSecret) error {
+	values := make(map[string]string, len(secrets))
+	for _, s := range secrets {
+		values[s.EnvVar] = s.Value
+	}
+
+	var lines []string
+	if existing, err := os.ReadFile(path); err == nil { //nolint:gosec // Path chosen by the user in the wizard
+		for _, line := range strings.Split(strings.TrimRight(string(existing), "\n"), "\n") {
+			name := strings.TrimPrefix(strings.TrimSpace(line), "export ")
+			if idx := strings.Index(name, "="); idx != -1 {
+				if v, ok := values[name[:idx]]; ok {
+					lines = append(lines, secretExportLine(name[:idx], v))
+					delete(values, name[:idx])
+					continue
+				}
+			}
+			lines = append(lines, line)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	for _, s := range secrets {
+		if v, ok := values[s.EnvVar]; ok {
+			lines = append(lines, secretExportLine(s.EnvVar, v))
+			delete(values, s.EnvVar)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to save secrets: %w", err)
+	}
+	return nil
+}
+
+// secretExportLine formats a single-quoted shell export.
+func secretExportLine(name, value string) string {
+	return fmt.Sprintf("export %s='%s'", name, strings.ReplaceAll(value, "'", `'\''`))
+}
+
 func configureSplunk(endpoint, authType, token, username, password *string) error {
 	form := huh.NewForm(
 		huh.NewGroup(
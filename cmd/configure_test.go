@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSecretsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	require.NoError(t, os.WriteFile(path, []byte("# logviewer\nexport LOGVIEWER_A='old'\nexport OTHER='x'\n"), 0600))
+
+	err := writeSecretsFile(path, []config.RedactedSecret{
+		{EnvVar: "LOGVIEWER_A", Value: "new"},
+		{EnvVar: "LOGVIEWER_B", Value: "it's"},
+	})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# logviewer\nexport LOGVIEWER_A='new'\nexport OTHER='x'\nexport LOGVIEWER_B='it'\\''s'\n", string(content))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
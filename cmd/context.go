@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"sort"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/bascanada/logviewer/pkg/log/client/config"
@@ -15,47 +18,104 @@ var contextCmd = &cobra.Command{
 	Short: "Manage configuration contexts",
 }
 
+// completeContextIDs autocompletes the first argument with the context IDs.
+func completeContextIDs(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	// We use configPath if set, otherwise default loading mechanism
+	cfg, _ := config.LoadContextConfig(configPath)
+	var suggestions []string
+	if cfg != nil {
+		for id := range cfg.Contexts {
+			suggestions = append(suggestions, id)
+		}
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
 var useContextCmd = &cobra.Command{
 	Use:   "use [context-id]",
 	Short: "Set the current active context",
 	// Autocomplete for context IDs
-	ValidArgsFunction: func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-		// We use configPath if set, otherwise default loading mechanism
-		cfg, _ := config.LoadContextConfig(configPath)
-		var suggestions []string
-		if cfg != nil {
-			for id := range cfg.Contexts {
-				suggestions = append(suggestions, id)
-			}
-		}
-		return suggestions, cobra.ShellCompDirectiveNoFileComp
-	},
+	ValidArgsFunction: completeContextIDs,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
 			_ = cmd.Help()
 			return
 		}
-		contextID := args[0]
-
-		cfg, err := config.LoadContextConfig(configPath)
-		if err != nil {
-			fmt.Printf("Error loading config: %v\n", err)
+		if err := RunContextUse(os.Stdout, configPath, args[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
+	},
+}
 
-		if _, ok := cfg.Contexts[contextID]; !ok {
-			fmt.Printf("Error: context '%s' not found in any loaded config.\n", contextID)
+var currentContextCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Print the current context and its client",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if err := RunContextCurrent(os.Stdout, configPath); err != nil {
+			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
+	},
+}
 
-		state := &config.State{CurrentContext: contextID}
-		if err := config.SaveState(state); err != nil {
-			fmt.Printf("Error saving state: %v\n", err)
-			os.Exit(1)
+// RunContextUse makes contextID the current context, once it is known to be
+// defined in a loaded config file. An unknown ID fails with the IDs close to
+// it.
+func RunContextUse(out io.Writer, path, contextID string) error {
+	cfg, err := config.LoadContextConfig(path)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	if _, ok := cfg.Contexts[contextID]; !ok {
+		all := make([]string, 0, len(cfg.Contexts))
+		for id := range cfg.Contexts {
+			all = append(all, id)
+		}
+		err := fmt.Errorf("%w: %s is not defined in any loaded config", config.ErrContextNotFound, contextID)
+		if suggestions := suggestSimilar(contextID, all, 3); len(suggestions) > 0 {
+			err = fmt.Errorf("%w; did you mean %s?", err, strings.Join(suggestions, ", "))
 		}
+		return err
+	}
 
-		fmt.Printf("Switched to context \"%s\".\n", contextID)
-	},
+	if err := config.SaveState(&config.State{CurrentContext: contextID}); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	_, _ = fmt.Fprintf(out, "Switched to context \"%s\".\n", contextID)
+	return nil
+}
+
+// RunContextCurrent prints the current context with its client and the
+// backend type of the client.
+func RunContextCurrent(out io.Writer, path string) error {
+	cfg, err := config.LoadContextConfig(path)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if cfg.CurrentContext == "" {
+		return errors.New("no current context; select one with 'logviewer context use'")
+	}
+
+	ctx, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return fmt.Errorf("%w: the current context %s is no longer defined; select another one with 'logviewer context use'",
+			config.ErrContextNotFound, cfg.CurrentContext)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Context:\t%s\n", cfg.CurrentContext)
+	_, _ = fmt.Fprintf(w, "Client:\t%s\n", ctx.Client)
+	_, _ = fmt.Fprintf(w, "Backend:\t%s\n", cfg.Clients[ctx.Client].Type)
+	if ctx.Description != "" {
+		_, _ = fmt.Fprintf(w, "Description:\t%s\n", ctx.Description)
+	}
+	return w.Flush()
 }
 
 var listContextsCmd = &cobra.Command{
@@ -90,8 +150,105 @@ var listContextsCmd = &cobra.Command{
 	},
 }
 
+var renameContextCmd = &cobra.Command{
+	Use:               "rename <old> <new>",
+	Short:             "Rename a context in the config file that defines it",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeContextIDs,
+	Run: func(_ *cobra.Command, args []string) {
+		if err := RunContextRename(os.Stdout, configPath, args[0], args[1]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var deleteContextCmd = &cobra.Command{
+	Use:               "delete <context-id>",
+	Short:             "Delete a context from the config file that defines it",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextIDs,
+	Run: func(_ *cobra.Command, args []string) {
+		if err := RunContextDelete(os.Stdout, configPath, args[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// RunContextRename renames oldID to newID in the loaded config file whose
+// definition is in effect, and follows the rename in the current context.
+func RunContextRename(out io.Writer, path, oldID, newID string) error {
+	cfg, err := config.LoadContextConfig(path)
+	if err != nil {
+		return err
+	}
+	if _, exists := cfg.Contexts[newID]; exists {
+		return fmt.Errorf("%w: %s, delete it first or pick another name", config.ErrContextExists, newID)
+	}
+	files, err := config.ResolveConfigPaths(path)
+	if err != nil {
+		return err
+	}
+	target, shadowed, err := config.FindContextFile(files, oldID)
+	if err != nil {
+		return err
+	}
+	if err := config.RenameContext(target, oldID, newID); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Renamed context \"%s\" to \"%s\" in %s.\n", oldID, newID, target)
+	if len(shadowed) > 0 {
+		_, _ = fmt.Fprintf(out, "Warning: \"%s\" is also defined in %s and keeps that name.\n", oldID, strings.Join(shadowed, ", "))
+	}
+
+	if cfg.CurrentContext == oldID && len(shadowed) == 0 {
+		if err := config.SaveState(&config.State{CurrentContext: newID}); err != nil {
+			return fmt.Errorf("renamed, but failed to update the current context: %w", err)
+		}
+		_, _ = fmt.Fprintf(out, "Current context is now \"%s\".\n", newID)
+	}
+	return nil
+}
+
+// RunContextDelete removes contextID from the loaded config file whose
+// definition is in effect, and clears the current context if it was it.
+func RunContextDelete(out io.Writer, path, contextID string) error {
+	cfg, err := config.LoadContextConfig(path)
+	if err != nil {
+		return err
+	}
+	files, err := config.ResolveConfigPaths(path)
+	if err != nil {
+		return err
+	}
+	target, shadowed, err := config.FindContextFile(files, contextID)
+	if err != nil {
+		return err
+	}
+	if err := config.DeleteContext(target, contextID); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Deleted context \"%s\" from %s.\n", contextID, target)
+	if len(shadowed) > 0 {
+		_, _ = fmt.Fprintf(out, "Warning: \"%s\" is still defined in %s, that definition is now used.\n", contextID, shadowed[len(shadowed)-1])
+		return nil
+	}
+
+	if cfg.CurrentContext == contextID {
+		if err := config.SaveState(&config.State{}); err != nil {
+			return fmt.Errorf("deleted, but failed to clear the current context: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, "Warning: it was the current context; select another one with 'logviewer context use'.")
+	}
+	return nil
+}
+
 func init() {
 	contextCmd.AddCommand(useContextCmd)
+	contextCmd.AddCommand(currentContextCmd)
 	contextCmd.AddCommand(listContextsCmd)
+	contextCmd.AddCommand(renameContextCmd)
+	contextCmd.AddCommand(deleteContextCmd)
 	rootCmd.AddCommand(contextCmd)
 }
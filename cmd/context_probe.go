@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/log/factory"
+	"github.com/spf13/cobra"
+)
+
+// defaultProbeTimeout bounds the check of each context, unless --timeout
+// changes it.
+const defaultProbeTimeout = 10 * time.Second
+
+var probeTimeout time.Duration
+
+var testContextCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Check that the backend of contexts answers",
+	Long: `Run the smallest search of each context, a single entry of the last
+5 minutes, and report whether its backend answered, with the latency and the
+endpoint of its client. Every context is checked unless -i selects some.`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		cfg, _, err := loadConfig(configPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		clientFactory, err := factory.GetLogBackendFactory(cfg.Clients)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		searchFactory, err := factory.GetLogSearchFactory(clientFactory, *cfg)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := RunContextTest(os.Stdout, cfg, searchFactory, contextIDs, parseRuntimeVars(), probeTimeout); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// RunContextTest probes the backend of each context in contextIDs, every
+// context of cfg when empty, and prints a status table. It fails when one of
+// them did not answer within timeout.
+func RunContextTest(out io.Writer, cfg *config.ContextConfig, sf factory.SearchFactory, contextIDs []string, runtimeVars map[string]string, timeout time.Duration) error {
+	ids := contextIDs
+	if len(ids) == 0 {
+		for id := range cfg.Contexts {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+	}
+	if len(ids) == 0 {
+		return errors.New("no contexts to test")
+	}
+
+	latencies := make(map[string]time.Duration, len(ids))
+	errs := make(map[string]error, len(ids))
+	var mu sync.Mutex
+	_ = client.FanOut(context.Background(), ids, 0, func(id string) {
+		var latency time.Duration
+		err := probeContext(cfg, id, runtimeVars)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			var probe factory.ProbeResult
+			probe, err = factory.Probe(ctx, cfg, sf, id, runtimeVars)
+			latency = probe.Latency
+			if errors.Is(err, context.DeadlineExceeded) {
+				err = fmt.Errorf("no answer within %s", timeout)
+			}
+			cancel()
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		latencies[id] = latency
+		errs[id] = err
+	})
+
+	failed := 0
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CONTEXT\tBACKEND\tENDPOINT\tLATENCY\tSTATUS")
+	for _, id := range ids {
+		backend, endpoint, latency, status := "-", "-", "-", "ok"
+		if ctx, ok := cfg.Contexts[id]; ok {
+			c := cfg.Clients[ctx.Client]
+			backend = c.Type
+			if e := factory.ClientEndpoint(c); e != "" {
+				endpoint = e
+			}
+		}
+		if latencies[id] > 0 {
+			latency = latencies[id].Round(time.Millisecond).String()
+		}
+		if err := errs[id]; err != nil {
+			failed++
+			status = "failed: " + strings.Join(strings.Fields(err.Error()), " ")
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", id, backend, endpoint, latency, status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d contexts failed", failed, len(ids))
+	}
+	return nil
+}
+
+// probeContext reports a context missing from cfg, whose client is, or that
+// lacks required variables, before any backend is called.
+func probeContext(cfg *config.ContextConfig, id string, runtimeVars map[string]string) error {
+	ctx, ok := cfg.Contexts[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", config.ErrContextNotFound, id)
+	}
+	if _, ok := cfg.Clients[ctx.Client]; !ok {
+		return fmt.Errorf("client %s is not defined", ctx.Client)
+	}
+	return checkRequiredVars(cfg, []string{id}, nil, runtimeVars)
+}
+
+func init() {
+	testContextCmd.Flags().StringArrayVarP(&contextIDs, "id", "i", []string{}, "Context id to test, every context when none is given")
+	testContextCmd.Flags().StringArrayVar(&vars, "var", []string{}, "Define a runtime variable for the search context (e.g., --var 'sessionId=abc-123')")
+	testContextCmd.Flags().DurationVar(&probeTimeout, "timeout", defaultProbeTimeout, "Maximum duration of the check of each context")
+	_ = testContextCmd.RegisterFlagCompletionFunc("id", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return completeContextIDs(nil, nil, "")
+	})
+	contextCmd.AddCommand(testContextCmd)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunContextTest(t *testing.T) {
+	cfg := &config.ContextConfig{
+		Clients: config.Clients{
+			"os":    config.Client{Type: "opensearch", Options: ty.MI{"endpoint": "http://os:9200"}},
+			"local": config.Client{Type: "local", Options: ty.MI{"token": "s3cr3t-token"}},
+		},
+		Contexts: config.Contexts{
+			"api":    config.SearchContext{Client: "os"},
+			"worker": config.SearchContext{Client: "local"},
+			"slow":   config.SearchContext{Client: "local"},
+		},
+	}
+	mockFactory := &MockSearchFactory{
+		OnGetSearchResult: func(ctx context.Context, contextID string, search client.LogSearch) (client.LogSearchResult, error) {
+			assert.Equal(t, 1, search.Size.Value)
+			switch contextID {
+			case "worker":
+				return nil, errors.New("connection\nrefused for s3cr3t-token")
+			case "slow":
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return &MockResult{}, nil
+		},
+	}
+
+	t.Run("tests every context", func(t *testing.T) {
+		var out bytes.Buffer
+		err := RunContextTest(&out, cfg, mockFactory, nil, nil, 50*time.Millisecond)
+		assert.EqualError(t, err, "2 of 3 contexts failed")
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 4)
+		assert.Equal(t, []string{"CONTEXT", "BACKEND", "ENDPOINT", "LATENCY", "STATUS"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"api", "opensearch", "http://os:9200"}, strings.Fields(lines[1])[:3])
+		assert.True(t, strings.HasSuffix(lines[1], "ok"))
+		assert.Contains(t, lines[2], "failed: no answer within 50ms")
+		assert.Contains(t, lines[3], "failed: connection refused for ********")
+	})
+
+	t.Run("tests the selected contexts", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, RunContextTest(&out, cfg, mockFactory, []string{"api"}, nil, time.Second))
+		assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 2)
+	})
+
+	t.Run("reports an unknown context", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.Error(t, RunContextTest(&out, cfg, mockFactory, []string{"missing"}, nil, time.Second))
+		assert.Contains(t, out.String(), "context not found")
+	})
+}
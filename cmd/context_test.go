@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupContextFiles writes two config files loaded through LOGVIEWER_CONFIG,
+// "shared" being defined in both, and sets the current context.
+func setupContextFiles(t *testing.T, current string) (string, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.yaml")
+	second := filepath.Join(dir, "second.json")
+	require.NoError(t, os.WriteFile(first, []byte(`
+clients:
+  local: {type: local}
+contexts:
+  app: {client: local, search: {size: 10}}
+  shared: {client: local}
+`), 0600))
+	require.NoError(t, os.WriteFile(second, []byte(`{
+  "contexts": {
+    "shared": {"client": "local", "description": "second"},
+    "worker": {"client": "local"}
+  }
+}`), 0600))
+	t.Setenv(config.EnvConfigPath, first+string(os.PathListSeparator)+second)
+	require.NoError(t, config.SaveState(&config.State{CurrentContext: current}))
+	return first, second
+}
+
+func TestRunContextRename(t *testing.T) {
+	t.Run("renames in the defining file and follows the current context", func(t *testing.T) {
+		first, _ := setupContextFiles(t, "app")
+		var out bytes.Buffer
+		require.NoError(t, RunContextRename(&out, "", "app", "api"))
+		assert.Contains(t, out.String(), first)
+
+		cfg, err := config.LoadContextConfig("")
+		require.NoError(t, err)
+		assert.NotContains(t, cfg.Contexts, "app")
+		assert.Equal(t, 10, cfg.Contexts["api"].Search.Size.Value)
+		assert.Equal(t, "api", cfg.CurrentContext)
+	})
+
+	t.Run("targets the file in effect", func(t *testing.T) {
+		first, second := setupContextFiles(t, "")
+		var out bytes.Buffer
+		require.NoError(t, RunContextRename(&out, "", "shared", "common"))
+		assert.Contains(t, out.String(), second)
+		assert.Contains(t, out.String(), "Warning")
+
+		data, err := os.ReadFile(second)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"common"`)
+		data, err = os.ReadFile(first)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "shared")
+	})
+
+	t.Run("refuses an existing name", func(t *testing.T) {
+		setupContextFiles(t, "")
+		err := RunContextRename(&bytes.Buffer{}, "", "app", "worker")
+		assert.ErrorIs(t, err, config.ErrContextExists)
+	})
+}
+
+func TestRunContextDelete(t *testing.T) {
+	t.Run("clears the current context", func(t *testing.T) {
+		setupContextFiles(t, "worker")
+		var out bytes.Buffer
+		require.NoError(t, RunContextDelete(&out, "", "worker"))
+		assert.Contains(t, out.String(), "current context")
+
+		cfg, err := config.LoadContextConfig("")
+		require.NoError(t, err)
+		assert.NotContains(t, cfg.Contexts, "worker")
+		assert.Empty(t, cfg.CurrentContext)
+	})
+
+	t.Run("warns when a shadowed definition remains", func(t *testing.T) {
+		setupContextFiles(t, "shared")
+		var out bytes.Buffer
+		require.NoError(t, RunContextDelete(&out, "", "shared"))
+		assert.True(t, strings.Contains(out.String(), "still defined"))
+
+		cfg, err := config.LoadContextConfig("")
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Contexts["shared"].Description, "first file definition is now used")
+		assert.Equal(t, "shared", cfg.CurrentContext)
+	})
+
+	t.Run("unknown context", func(t *testing.T) {
+		setupContextFiles(t, "")
+		assert.ErrorIs(t, RunContextDelete(&bytes.Buffer{}, "", "missing"), config.ErrContextNotFound)
+	})
+}
+
+func TestRunContextUse(t *testing.T) {
+	t.Run("switches to a defined context", func(t *testing.T) {
+		setupContextFiles(t, "app")
+		var out bytes.Buffer
+		require.NoError(t, RunContextUse(&out, "", "worker"))
+		assert.Contains(t, out.String(), "worker")
+
+		cfg, err := config.LoadContextConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, "worker", cfg.CurrentContext)
+	})
+
+	t.Run("refuses an unknown context with suggestions", func(t *testing.T) {
+		setupContextFiles(t, "app")
+		err := RunContextUse(&bytes.Buffer{}, "", "workr")
+		require.ErrorIs(t, err, config.ErrContextNotFound)
+		assert.Contains(t, err.Error(), "did you mean worker")
+
+		cfg, err := config.LoadContextConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, "app", cfg.CurrentContext, "the current context is kept")
+	})
+}
+
+func TestRunContextCurrent(t *testing.T) {
+	t.Run("prints the context, its client and backend", func(t *testing.T) {
+		setupContextFiles(t, "shared")
+		var out bytes.Buffer
+		require.NoError(t, RunContextCurrent(&out, ""))
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 4)
+		assert.Equal(t, []string{"Context:", "shared"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"Client:", "local"}, strings.Fields(lines[1]))
+		assert.Equal(t, []string{"Backend:", "local"}, strings.Fields(lines[2]))
+		assert.Equal(t, []string{"Description:", "second"}, strings.Fields(lines[3]))
+	})
+
+	t.Run("fails without a current context", func(t *testing.T) {
+		setupContextFiles(t, "")
+		assert.Error(t, RunContextCurrent(&bytes.Buffer{}, ""))
+	})
+
+	t.Run("fails on a context no longer defined", func(t *testing.T) {
+		setupContextFiles(t, "gone")
+		assert.ErrorIs(t, RunContextCurrent(&bytes.Buffer{}, ""), config.ErrContextNotFound)
+	})
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/log/factory"
+	"github.com/spf13/cobra"
+)
+
+// Sources reported by query explain for each setting of the resolved search.
+const (
+	explainSourceContext = "context"
+	explainSourceInherit = "inherit:"
+	explainSourceFlag    = "flag"
+)
+
+// SearchExplanation is the resolved search plan printed by query explain.
+type SearchExplanation struct {
+	Context  string            `json:"context"`
+	Client   ExplainedClient   `json:"client"`
+	Inherits []string          `json:"inherits,omitempty"`
+	Search   *client.LogSearch `json:"search"`
+	// Sources maps each setting of Search, as a dotted path, to the layer
+	// that set it: "context", "inherit:<name>" or "flag"
+	Sources map[string]string `json:"sources"`
+}
+
+// ExplainedClient is the backend a search would run on.
+type ExplainedClient struct {
+	Name    string         `json:"name"`
+	Type    string         `json:"type"`
+	Options map[string]any `json:"options,omitempty"`
+}
+
+var explainOutput string
+
+var queryExplainCommand = &cobra.Command{
+	Use:   "explain",
+	Short: "Show the resolved search plan of a context without running it",
+	Long: `Show the fully merged search of a context, after inherited searches,
+variables and command line flags are applied, and the backend it would run on.
+Each setting lists where it came from. Secrets are masked.
+
+Examples:
+  logviewer query explain -i prod --inherits errors --var env=prod
+  logviewer query explain -i prod -f level=ERROR --output json`,
+	PreRun: onCommandStart,
+	Run: func(_ *cobra.Command, _ []string) {
+		cfg, _, err := loadConfig(configPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		resolved := resolveContextIDsFromConfig(cfg)
+		if len(resolved) != 1 {
+			fmt.Fprintln(os.Stderr, "error: query explain needs exactly one context; use -i to select it")
+			os.Exit(1)
+		}
+
+		search, err := buildSearchRequest()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		asJSON := jsonOutput || explainOutput == "json"
+		if err := RunQueryExplain(os.Stdout, cfg, resolved[0], inherits, search, parseRuntimeVars(), asJSON); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// RunQueryExplain resolves the search of contextID the way a query would,
+// without executing it, and prints the plan as text or JSON.
+func RunQueryExplain(out io.Writer, cfg *config.ContextConfig, contextID string, inherits []string, search client.LogSearch, runtimeVars map[string]string, asJSON bool) error {
+	explanation, err := explainSearch(cfg, contextID, inherits, search, runtimeVars)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(explanation)
+	}
+
+	fmt.Fprintf(out, "Context:  %s\n", explanation.Context)
+	fmt.Fprintf(out, "Client:   %s (%s)\n", explanation.Client.Name, explanation.Client.Type)
+	if len(explanation.Inherits) > 0 {
+		fmt.Fprintf(out, "Inherits: %s\n", strings.Join(explanation.Inherits, ", "))
+	}
+	fmt.Fprintln(out, "Search:")
+	settings, err := flattenJSON(explanation.Search)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(settings))
+	width := 0
+	for path := range settings {
+		paths = append(paths, path)
+		width = max(width, len(path))
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(out, "  %-*s = %s  [%s]\n", width, path, settings[path], explanation.Sources[path])
+	}
+	if len(explanation.Client.Options) > 0 {
+		fmt.Fprintln(out, "Client options:")
+		options, err := flattenJSON(explanation.Client.Options)
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(options))
+		for k := range options {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(out, "  %s = %s\n", k, options[k])
+		}
+	}
+	return nil
+}
+
+// explainSearch builds the plan through SearchFactory.GetSearchContext and
+// replays the merge layers to find the source of each setting.
+func explainSearch(cfg *config.ContextConfig, contextID string, inherits []string, search client.LogSearch, runtimeVars map[string]string) (*SearchExplanation, error) {
+	clientFactory, err := factory.GetLogBackendFactory(cfg.Clients)
+	if err != nil {
+		return nil, err
+	}
+	searchFactory, err := factory.GetLogSearchFactory(clientFactory, *cfg)
+	if err != nil {
+		return nil, err
+	}
+	searchContext, err := searchFactory.GetSearchContext(context.Background(), contextID, inherits, search, runtimeVars)
+	if err != nil {
+		return nil, err
+	}
+
+	contextConfig := cfg.Contexts[contextID]
+	allInherits := append(append([]string{}, contextConfig.SearchInherit...), inherits...)
+
+	// Later layers override earlier ones, like GetSearchContext merges them
+	sources := make(map[string]string)
+	addLayer := func(layer *client.LogSearch, source string) error {
+		settings, err := flattenJSON(layer)
+		if err != nil {
+			return err
+		}
+		for path := range settings {
+			sources[path] = source
+		}
+		return nil
+	}
+	layerSearch := contextConfig.Search
+	if err := addLayer(&layerSearch, explainSourceContext); err != nil {
+		return nil, err
+	}
+	for _, inherit := range allInherits {
+		inheritSearch := cfg.Searches[inherit]
+		if err := addLayer(&inheritSearch, explainSourceInherit+inherit); err != nil {
+			return nil, err
+		}
+	}
+	if err := addLayer(&search, explainSourceFlag); err != nil {
+		return nil, err
+	}
+
+	resolved := searchContext.Search
+	resolved.Options = config.MaskSecrets(resolved.Options)
+	settings, err := flattenJSON(&resolved)
+	if err != nil {
+		return nil, err
+	}
+	for path := range sources {
+		if _, ok := settings[path]; !ok {
+			delete(sources, path)
+		}
+	}
+
+	backend := cfg.Clients[searchContext.Client]
+	return &SearchExplanation{
+		Context:  contextID,
+		Client:   ExplainedClient{Name: searchContext.Client, Type: backend.Type, Options: config.MaskSecrets(backend.Options)},
+		Inherits: allInherits,
+		Search:   &resolved,
+		Sources:  sources,
+	}, nil
+}
+
+// flattenJSON returns the non-empty leaves of v's JSON form keyed by dotted
+// path, e.g. "range.last" or "fields.level". Arrays are kept whole.
+func flattenJSON(v any) (map[string]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	flat := make(map[string]string)
+	var walk func(prefix string, node any) error
+	walk = func(prefix string, node any) error {
+		switch n := node.(type) {
+		case nil:
+			return nil
+		case map[string]any:
+			for k, child := range n {
+				path := k
+				if prefix != "" {
+					path = prefix + "." + k
+				}
+				if err := walk(path, child); err != nil {
+					return err
+				}
+			}
+			return nil
+		case string:
+			if n == "" {
+				return nil
+			}
+			flat[prefix] = n
+			return nil
+		case bool:
+			if !n {
+				return nil
+			}
+		case []any:
+			if len(n) == 0 {
+				return nil
+			}
+		}
+		if prefix == "" {
+			return errors.New("cannot flatten a scalar value")
+		}
+		encoded, err := json.Marshal(node)
+		if err != nil {
+			return err
+		}
+		flat[prefix] = string(encoded)
+		return nil
+	}
+	if err := walk("", tree); err != nil {
+		return nil, err
+	}
+	return flat, nil
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func explainTestConfig() *config.ContextConfig {
+	contextSearch := client.LogSearch{
+		Fields:    ty.MS{"app": "${app}"},
+		Options:   ty.MI{"index": "main", "token": "s3cr3t"},
+		Variables: map[string]client.VariableDefinition{"app": {Default: "api"}},
+	}
+	contextSearch.Range.Last.S("1h")
+	contextSearch.Size.S(100)
+
+	errorsSearch := client.LogSearch{Fields: ty.MS{"level": "ERROR"}}
+	errorsSearch.Size.S(50)
+
+	return &config.ContextConfig{
+		Clients: config.Clients{
+			"splunk": {Type: "splunk", Options: ty.MI{"url": "https://splunk", "headers": ty.MI{"Authorization": "Splunk abc123"}}},
+		},
+		Searches: config.Searches{"errors": errorsSearch},
+		Contexts: config.Contexts{
+			"prod": {Client: "splunk", Search: contextSearch},
+		},
+	}
+}
+
+func TestRunQueryExplain(t *testing.T) {
+	flags := client.LogSearch{Fields: ty.MS{"host": "web-1"}}
+	flags.Range.Last.S("15m")
+
+	t.Run("json output", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := RunQueryExplain(&buf, explainTestConfig(), "prod", []string{"errors"}, flags, map[string]string{"app": "worker"}, true)
+		require.NoError(t, err)
+
+		var got SearchExplanation
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Equal(t, "splunk", got.Client.Name)
+		assert.Equal(t, "splunk", got.Client.Type)
+		assert.Equal(t, []string{"errors"}, got.Inherits)
+
+		assert.Equal(t, "worker", got.Search.Fields["app"], "variables resolved")
+		assert.Equal(t, "ERROR", got.Search.Fields["level"])
+		assert.Equal(t, 50, got.Search.Size.Value)
+		assert.Equal(t, "15m", got.Search.Range.Last.Value)
+
+		assert.Equal(t, "context", got.Sources["fields.app"])
+		assert.Equal(t, "inherit:errors", got.Sources["fields.level"])
+		assert.Equal(t, "inherit:errors", got.Sources["size"])
+		assert.Equal(t, "flag", got.Sources["fields.host"])
+		assert.Equal(t, "flag", got.Sources["range.last"])
+
+		assert.Equal(t, config.MaskedSecret, got.Search.Options["token"])
+		assert.Equal(t, "main", got.Search.Options["index"])
+		assert.NotContains(t, buf.String(), "s3cr3t")
+		assert.NotContains(t, buf.String(), "abc123")
+	})
+
+	t.Run("text output", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := RunQueryExplain(&buf, explainTestConfig(), "prod", nil, flags, nil, false)
+		require.NoError(t, err)
+
+		out := buf.String()
+		assert.Contains(t, out, "Client:   splunk (splunk)")
+		assert.Regexp(t, `fields\.app\s+= api  \[context\]`, out)
+		assert.Regexp(t, `range\.last\s+= 15m  \[flag\]`, out)
+		assert.Contains(t, out, "headers.Authorization = Splunk "+config.MaskedSecret)
+		assert.NotContains(t, out, "s3cr3t")
+	})
+
+	t.Run("unknown context", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := RunQueryExplain(&buf, explainTestConfig(), "missing", nil, client.LogSearch{}, nil, false)
+		assert.ErrorIs(t, err, config.ErrContextNotFound)
+	})
+}
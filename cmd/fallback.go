@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+)
+
+var (
+	fallbackWarnedMu sync.Mutex
+	fallbackWarned   = map[string]bool{}
+)
+
+// withFallbackWarnings returns a ctx whose searches warn on stderr about the
+// filters their backend applies client-side, once per capability for the
+// run, unless --quiet is given.
+func withFallbackWarnings(ctx context.Context) context.Context {
+	if quiet {
+		return ctx
+	}
+	return client.WithFallbackWarnings(ctx, func(fallback client.Fallback) {
+		warnFallbackOnce(os.Stderr, fallback)
+	})
+}
+
+// warnFallbackOnce writes the warning of fallback to w unless one was already
+// written for its capability. Fan-out queries call it concurrently.
+func warnFallbackOnce(w io.Writer, fallback client.Fallback) {
+	fallbackWarnedMu.Lock()
+	defer fallbackWarnedMu.Unlock()
+	if fallbackWarned[fallback.Capability] {
+		return
+	}
+	fallbackWarned[fallback.Capability] = true
+	fmt.Fprintf(w, "warning: %s\n", fallback.Message)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarnFallbackOnce(t *testing.T) {
+	fallbackWarned = map[string]bool{}
+	t.Cleanup(func() { fallbackWarned = map[string]bool{} })
+
+	var buf bytes.Buffer
+	warnFallbackOnce(&buf, client.Fallback{Capability: "regex", Message: "regex applied client-side"})
+	warnFallbackOnce(&buf, client.Fallback{Capability: "regex", Message: "regex applied client-side"})
+	warnFallbackOnce(&buf, client.Fallback{Capability: "negation", Message: "negation applied client-side"})
+
+	assert.Equal(t, 2, strings.Count(buf.String(), "warning: "))
+	assert.Contains(t, buf.String(), "warning: regex applied client-side\n")
+	assert.Contains(t, buf.String(), "warning: negation applied client-side\n")
+}
@@ -3,12 +3,14 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	httpPkg "github.com/bascanada/logviewer/pkg/http"
 	"github.com/bascanada/logviewer/pkg/log"
 	"github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/log/client/config"
 	"github.com/bascanada/logviewer/pkg/log/impl/ssh"
+	"github.com/bascanada/logviewer/pkg/tui"
 	"github.com/spf13/cobra"
 )
 
@@ -38,6 +40,7 @@ var (
 	// ssh options
 	sshOptions ssh.LogClientOptions
 	cmd        string
+	localFile  string
 
 	// cloudwatch
 	cloudwatchLogGroup        string
@@ -59,7 +62,8 @@ var (
 	last string
 
 	// native query
-	nativeQuery string
+	nativeQuery     string
+	nativeQueryOnly bool
 
 	// hl-compatible query expression
 	queryExpr string
@@ -67,17 +71,27 @@ var (
 	// fields
 	fields     []string
 	fieldsOps  []string
+	fieldsFile string
 	inherits   []string
 	vars       []string
 	groupRegex string
 	kvRegex    string
 
+	maxMessageLength int
+
+	strictFields string
+	fieldsLogic  string
+	queryTimeout string
+
+	concurrency int
+
 	size int
 
 	duration string
 	refresh  bool
 
-	template string
+	template     string
+	templateFile string
 
 	contextIDs []string
 
@@ -86,9 +100,32 @@ var (
 	myLog     bool
 	debugHTTP bool
 
-	pageToken   string
-	jsonOutput  bool
-	colorOutput string
+	pageToken     string
+	decodeToken   string
+	pageAll       bool
+	numericValues bool
+	maxResults    int
+	jsonOutput    bool
+	nestFields    bool
+	colorOutput   string
+	noColor       bool
+	timezone      string
+
+	histogramInterval string
+	histogramGroupBy  string
+
+	highlightTerms []string
+	highlightCase  bool
+
+	restoreSession bool
+	noRestore      bool
+	fieldCacheTTL  time.Duration
+	errorLevel     string
+	sidebarOpen    bool
+	sidebarMode    string
+	splitRatio     float64
+
+	quiet bool
 )
 
 func onCommandStart(_ *cobra.Command, _ []string) {
@@ -158,6 +195,7 @@ func addSharedQueryFlags(cmd *cobra.Command) {
 
 	// QUERIES
 	cmd.PersistentFlags().StringVar(&nativeQuery, "native-query", "", "Raw query in backend's native syntax (Splunk SPL, OpenSearch Lucene)")
+	cmd.PersistentFlags().BoolVar(&nativeQueryOnly, "native-only", false, "Send --native-query as-is, without appending field filters (time range still applies)")
 	cmd.PersistentFlags().StringVarP(&queryExpr, "query", "q", "", "Complex filter expression with boolean logic (e.g., '(level=error OR status>=500) AND service=api')")
 
 	// SIZE
@@ -165,6 +203,15 @@ func addSharedQueryFlags(cmd *cobra.Command) {
 
 	// FIELD validation
 	cmd.PersistentFlags().StringArrayVarP(&fields, "fields", "f", []string{}, "Field for selection field=value")
+	cmd.PersistentFlags().StringVar(&fieldsFile, "fields-file", "", "File with one -f style condition per line (# comments allowed), combined with AND")
+	cmd.PersistentFlags().StringVar(&fieldsLogic, "fields-logic", "", "Combine the field=value -f conditions with and (default) or or")
+
+	cmd.PersistentFlags().StringVar(&strictFields, "strict-fields", "", "Fail when a filter uses a field the backend does not report (--strict-fields=false disables it for this query)")
+	cmd.PersistentFlags().Lookup("strict-fields").NoOptDefVal = "true"
+
+	cmd.PersistentFlags().StringVar(&queryTimeout, "query-timeout", "", "Maximum duration of each backend query (e.g. 30s), overriding the queryTimeout client option; follow queries are not bounded")
+
+	cmd.PersistentFlags().IntVar(&concurrency, "concurrency", client.DefaultConcurrency, "Maximum number of contexts queried at once when several -i are given")
 
 	// VARS & INHERITS
 	cmd.PersistentFlags().StringArrayVar(&vars, "var", []string{}, "Define a runtime variable for the search context (e.g., --var 'sessionId=abc-123')")
@@ -263,6 +310,8 @@ func init() {
 	queryCommand.PersistentFlags().StringVar(&sshOptions.User, "ssh-user", "", "SSH user")
 	queryCommand.PersistentFlags().StringVar(&sshOptions.PrivateKey, "ssh-identify", "", "SSH private key , by default $HOME/.ssh/id_rsa")
 	queryCommand.PersistentFlags().BoolVar(&sshOptions.DisablePTY, "ssh-disable-pty", false, "Disable requesting a PTY on SSH connections (useful for network devices)")
+	queryCommand.PersistentFlags().IntVar(&sshOptions.ReconnectMaxAttempts, "ssh-reconnect-attempts", 0, "Reconnections tried when a followed SSH connection drops (0 for the default, -1 to disable)")
+	queryCommand.PersistentFlags().StringVar(&sshOptions.ReconnectMaxDelay, "ssh-reconnect-max-delay", "", "Cap of the backoff between SSH reconnections, like 30s")
 
 	// CLOUDWATCH
 	queryCommand.PersistentFlags().StringVar(&cloudwatchLogGroup, "cloudwatch-log-group", "", "CloudWatch Logs log group name")
@@ -282,11 +331,13 @@ func init() {
 
 	// COMMAND
 	queryCommand.PersistentFlags().StringVar(&cmd, "cmd", "", "If using ssh or local , manual command to run")
+	queryCommand.PersistentFlags().StringVar(&localFile, "file", "", "Local file to read, and tail with --follow, without a shell command")
 
 	// Query-specific flags (not shared with TUI)
 
 	// PAGINATION
 	queryCommand.PersistentFlags().StringVar(&pageToken, "page-token", "", "Token for fetching the next page of results")
+	queryCommand.Flags().StringVar(&decodeToken, "decode-token", "", "Print the backend, context and cursor of a page token and exit")
 
 	// ADVANCED FIELD FILTERING
 	queryCommand.PersistentFlags().StringArrayVar(
@@ -310,6 +361,9 @@ func init() {
 	queryCommand.PersistentFlags().StringVar(
 		&kvRegex, "fields-kv-regex", "",
 		"Regex to extract key-value fields from log text, e.g. '(\\w+)=([^\\s]+)'")
+	queryCommand.PersistentFlags().IntVar(
+		&maxMessageLength, "max-message-length", 0,
+		"Truncate messages longer than this many characters (0 for unlimited)")
 
 	// OUTPUT FORMATTING (query-specific)
 	queryLogCommand.PersistentFlags().StringVar(
@@ -318,8 +372,21 @@ func init() {
 		&template,
 		"format",
 		"", "Format for the log entry")
+	queryLogCommand.PersistentFlags().StringVar(
+		&templateFile, "template-file", "", "File with the Go template of the log entry (cannot be used with --format)")
+	queryLogCommand.PersistentFlags().StringSliceVar(
+		&highlightTerms, "highlight", []string{}, "Comma-separated terms to color in printed messages (e.g. timeout,refused)")
+	queryLogCommand.PersistentFlags().BoolVar(
+		&highlightCase, "highlight-case", false, "Make --highlight matching case-sensitive")
+	queryLogCommand.PersistentFlags().BoolVar(
+		&pageAll, "page-all", false, "Follow the page tokens and output every page as one stream")
+	queryLogCommand.PersistentFlags().IntVar(
+		&maxResults, "max-results", 0, "Stop --page-all after this many entries (0 for no limit)")
 	queryCommand.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output logs in JSON format (NDJSON)")
+	queryLogCommand.PersistentFlags().BoolVar(
+		&nestFields, "nest-fields", false, "With --json, nest dotted field names (e.g. http.method) into objects")
 	queryCommand.PersistentFlags().StringVar(&colorOutput, "color", "auto", "Color output mode: auto (detect TTY), always, never")
+	queryCommand.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output (same as --color never or NO_COLOR=1)")
 
 	// Register completion function for the --color flag
 	_ = queryCommand.RegisterFlagCompletionFunc("color", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
@@ -328,8 +395,27 @@ func init() {
 
 	queryCommand.AddCommand(queryLogCommand)
 	queryCommand.AddCommand(queryFieldCommand)
+	queryValuesCommand.Flags().BoolVar(&numericValues, "numeric", false, "Summarize numeric fields (min, max, avg, p50, p95, p99) over the matching entries")
 	queryCommand.AddCommand(queryValuesCommand)
+	queryCommand.AddCommand(queryCountCommand)
+	queryHistogramCommand.Flags().StringVar(&histogramInterval, "interval", "5m", "Width of the time buckets, a duration like 10m or 1h")
+	queryHistogramCommand.Flags().StringVar(&histogramGroupBy, "group-by", "", "Split each bucket by the value of this field (e.g. level)")
+	queryCommand.AddCommand(queryHistogramCommand)
+
+	queryExplainCommand.Flags().StringVarP(&explainOutput, "output", "o", "text", "Output format: text or json")
+	_ = queryExplainCommand.RegisterFlagCompletionFunc("output", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	queryCommand.AddCommand(queryExplainCommand)
 
 	// TUI command - add shared flags
 	addSharedQueryFlags(tuiCmd)
+	tuiCmd.Flags().BoolVar(&restoreSession, "restore", false, "Restore the tabs open when the TUI was last closed (default when no -i is given)")
+	tuiCmd.Flags().BoolVar(&noRestore, "no-restore", false, "Do not restore the previous TUI session")
+	tuiCmd.Flags().DurationVar(&fieldCacheTTL, "field-cache-ttl", tui.DefaultFieldValueCacheTTL, "How long field values for autocomplete are reused across tabs (0 disables the cache)")
+	tuiCmd.Flags().StringVar(&templateFile, "template-file", "", "File with the Go template of the log lines")
+	tuiCmd.Flags().BoolVar(&sidebarOpen, "sidebar", false, "Open the sidebar at launch (overrides tui.detailsVisible)")
+	tuiCmd.Flags().StringVar(&sidebarMode, "sidebar-mode", "", "Sidebar content at launch: entry, fields or json (overrides tui.sidebarMode)")
+	tuiCmd.Flags().Float64Var(&splitRatio, "split-ratio", 0, "Share of the width given to the log list, 0.3 to 0.9 (overrides tui.splitRatio)")
+	tuiCmd.Flags().StringVar(&errorLevel, "error-level", tui.DefaultErrorLevel, "Lowest level the ]e and [e keys jump to (e.g. WARN)")
 }
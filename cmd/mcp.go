@@ -9,32 +9,33 @@ package cmd
 //      loop). Would require MCP extension for incremental results or chunked
 //      output handling.
 // 2. Summarization / Analytics Tool:
-//    - Provide a "summarize_logs" tool that groups by level, extracts top error
-//      signatures, counts occurrences, and surfaces anomaly hints. Could accept
-//      parameters: contextID, last, groupBy (level/service), topN.
+//    - summarize_logs covers levels, top error signatures and a groupBy
+//      (e.g. service) breakdown; anomaly hints are still open.
 // 3. Explicit Time Range Parameters:
 //    - Support gte / lte absolute timestamps (RFC3339) alongside "last" to allow
 //      precise investigations and reproducibility of queries.
 // 4. Aggregation / Facet Tool:
-//    - Expose a "facet_fields" tool returning counts for selected fields
-//      (e.g. level, service, host) to guide targeted filtering.
+//    - facet_fields counts the values of selected fields (e.g. level, service,
+//      host) over a capped scan; backend-native aggregations are still open.
 // 5. Structured Error Codes:
-//    - Standardize JSON error envelope with machine-friendly codes
-//      (e.g. CONTEXT_NOT_FOUND, BACKEND_UNAVAILABLE, VALIDATION_ERROR) instead of
-//      returning plain text or heuristic detection.
+//    - Every tool returns errors as mcpError envelopes with a code (e.g.
+//      CONTEXT_NOT_FOUND, BACKEND_UNAVAILABLE, VALIDATION_ERROR); codes
+//      specific to each backend are still open.
 // 6. Field Discovery Caching:
-//    - Cache get_fields results per context + time window (LRU / TTL) to reduce
-//      backend load when agents probe frequently.
-// 7. Partial / Sample Queries:
-//    - Allow a lightweight "sample_logs" tool that fetches a very small set
-//      (e.g. size=5) quickly for faster iterative refinement.
+//    - get_fields values go through the search factory's LRU / TTL cache per
+//      context + resolved window; the logviewer://context/<id>/fields
+//      resources and the names-only get_fields still keep their own paths.
+// 7. Partial / Sample Queries: ✅ COMPLETED
+//    - sample_logs runs query_logs on a few entries (size=5, last=5m by
+//      default) for faster iterative refinement.
 // 8. Query DSL / Expression Language:
 //    - Introduce a simple expression syntax (level=ERROR AND message~"timeout")
 //      parsed into backend-specific filters to expand flexibility beyond
 //      strict equality.
-// 9. Security / Multi-Tenancy:
-//    - Context-level ACLs and redaction hooks before returning entries
-//      (mask secrets, PII, tokens).
+// 9. Security / Multi-Tenancy: ✅ COMPLETED
+//    - Entries are masked by the context's redact patterns and fields before
+//      being returned, and mcp.exposeContexts / mcp.hideContexts limit the
+//      contexts agents can see.
 // 10. Metrics & Instrumentation:
 //     - Emit internal metrics (query latency, error rate, cache hit ratio) and
 //       optionally expose via a "diagnostics" tool.
@@ -52,15 +53,16 @@ package cmd
 //     - Allow custom transformers (timestamp normalization, field remapping,
 //       enrichment) prior to returning entries.
 // 15. Rate Limiting / Circuit Breaking:
-//     - Prevent costly repeated queries (same context/filters) in tight loops.
+//     - query_logs answers a request repeated within --dedup-window from its
+//       first result (meta.cached=true); circuit breaking is still open.
 // 16. Advanced Prompt Templates:
 //     - Additional prompts: error_investigation, performance_degradation,
 //       release_regression to accelerate LLM-driven diagnostics.
 // 17. Cross-Context Correlation Tool:
-//     - Tool that executes the same search across multiple contexts and merges
-//       aligned timelines (e.g. by traceId / requestId).
-// 18. Output Formatting Options:
-//     - Allow user to request minimal, pretty, or raw JSON for entries.
+//     - correlate_trace merges the timelines of several contexts for one
+//       traceId / requestId value; correlating on several fields is still open.
+// 18. Output Formatting Options: ✅ COMPLETED
+//     - query_logs takes output=full|minimal|raw to shape the entries.
 // 19. Pluggable Authentication to External Backends:
 //     - Support dynamic credentials injection or rotation for Splunk/ELK.
 // 20. Test Coverage Expansion:
@@ -75,6 +77,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"reflect"
+	"regexp/syntax"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -82,6 +87,7 @@ import (
 
 	"github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
 	"github.com/bascanada/logviewer/pkg/log/factory"
 	"github.com/bascanada/logviewer/pkg/ty"
 	"github.com/fsnotify/fsnotify"
@@ -210,7 +216,16 @@ Filtering is done client-side on extracted fields.`,
 	},
 }
 
-var mcpPort int
+var (
+	mcpPort         int
+	mcpEnableTools  []string
+	mcpDisableTools []string
+	mcpDedupWindow  time.Duration
+)
+
+// coreTools stay registered with --enable-tools unless --disable-tools names
+// them, since agents cannot do anything without them.
+var coreTools = []string{"list_contexts", "query_logs"}
 
 var mcpCmd = &cobra.Command{
 	Use:   "mcp",
@@ -234,6 +249,9 @@ var mcpCmd = &cobra.Command{
 		if err != nil {
 			log.Fatalf("failed to build MCP server: %v", err)
 		}
+		if err := bundle.RestrictTools(mcpEnableTools, mcpDisableTools); err != nil {
+			log.Fatal(err)
+		}
 
 		if err := server.ServeStdio(bundle.Server); err != nil {
 			log.Fatalf("failed to start server: %v", err)
@@ -266,7 +284,7 @@ func NewConfigManager(path string) (*ConfigManager, error) {
 	}
 	cm.watcher = watcher
 
-	if err := cm.Reload(); err != nil {
+	if _, err := cm.Reload(); err != nil {
 		_ = watcher.Close()
 		return nil, err
 	}
@@ -279,6 +297,7 @@ func NewConfigManager(path string) (*ConfigManager, error) {
 // NewConfigManagerForTest creates a ConfigManager from an in-memory config (for testing).
 // Does not set up file watching.
 func NewConfigManagerForTest(cfg *config.ContextConfig) (*ConfigManager, error) {
+	cfg = exposedConfig(cfg)
 	clientFactory, err := factory.GetLogBackendFactory(cfg.Clients)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build client factory: %w", err)
@@ -317,9 +336,12 @@ func (cm *ConfigManager) watch() {
 					cm.debounceTimer.Stop()
 				}
 				cm.debounceTimer = time.AfterFunc(debounceDelay, func() {
-					if err := cm.Reload(); err != nil {
+					diff, err := cm.Reload()
+					if err != nil {
 						log.Printf("Error reloading config: %v", err)
+						return
 					}
+					log.Printf("Config reloaded: %s", diff)
 				})
 			}
 		case err, ok := <-cm.watcher.Errors:
@@ -333,8 +355,78 @@ func (cm *ConfigManager) watch() {
 	}
 }
 
+// ConfigSectionDiff lists the entries of one config section (contexts,
+// clients or searches) that changed on reload.
+type ConfigSectionDiff struct {
+	Count   int      `json:"count"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+// ConfigDiff summarizes a reload versus the previous configuration. On the
+// first load there is nothing to compare, so only the counts are set.
+type ConfigDiff struct {
+	FirstLoad bool              `json:"firstLoad,omitempty"`
+	Contexts  ConfigSectionDiff `json:"contexts"`
+	Clients   ConfigSectionDiff `json:"clients"`
+	Searches  ConfigSectionDiff `json:"searches"`
+}
+
+func (d *ConfigDiff) String() string {
+	return fmt.Sprintf("contexts %s, clients %s, searches %s", d.Contexts, d.Clients, d.Searches)
+}
+
+func (s ConfigSectionDiff) String() string {
+	return fmt.Sprintf("%d (+%d -%d ~%d)", s.Count, len(s.Added), len(s.Removed), len(s.Changed))
+}
+
+// diffSection compares two config maps by key, using a deep comparison to
+// find the entries that were modified.
+func diffSection[V any](oldMap, newMap map[string]V, firstLoad bool) ConfigSectionDiff {
+	d := ConfigSectionDiff{Count: len(newMap)}
+	if firstLoad {
+		return d
+	}
+	for id, v := range newMap {
+		old, ok := oldMap[id]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, id)
+		case !reflect.DeepEqual(old, v):
+			d.Changed = append(d.Changed, id)
+		}
+	}
+	for id := range oldMap {
+		if _, ok := newMap[id]; !ok {
+			d.Removed = append(d.Removed, id)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+	return d
+}
+
+// diffConfig compares the new configuration with the previous one, which is
+// nil on the first load.
+func diffConfig(oldCfg, newCfg *config.ContextConfig) *ConfigDiff {
+	firstLoad := oldCfg == nil
+	if firstLoad {
+		oldCfg = &config.ContextConfig{}
+	}
+	return &ConfigDiff{
+		FirstLoad: firstLoad,
+		Contexts:  diffSection(oldCfg.Contexts, newCfg.Contexts, firstLoad),
+		Clients:   diffSection(oldCfg.Clients, newCfg.Clients, firstLoad),
+		Searches:  diffSection(oldCfg.Searches, newCfg.Searches, firstLoad),
+	}
+}
+
 // Reload reloads the configuration from disk and updates the search factory.
-func (cm *ConfigManager) Reload() error {
+// It returns what changed versus the previous configuration. On error the
+// previous configuration stays live.
+func (cm *ConfigManager) Reload() (*ConfigDiff, error) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
@@ -347,21 +439,26 @@ func (cm *ConfigManager) Reload() error {
 	// 1. Reload file from disk
 	newCfg, files, err := loadConfig(cm.configPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	// Contexts not exposed over MCP are left out, so every tool and
+	// resource treats them as missing
+	newCfg = exposedConfig(newCfg)
+
 	// 2. Rebuild factories
 	clientFactory, err := factory.GetLogBackendFactory(newCfg.Clients)
 	if err != nil {
-		return fmt.Errorf("failed to build client factory: %w", err)
+		return nil, fmt.Errorf("failed to build client factory: %w", err)
 	}
 
 	searchFactory, err := factory.GetLogSearchFactory(clientFactory, *newCfg)
 	if err != nil {
-		return fmt.Errorf("failed to build search factory: %w", err)
+		return nil, fmt.Errorf("failed to build search factory: %w", err)
 	}
 
 	// 3. Update state
+	diff := diffConfig(cm.currentCfg, newCfg)
 	cm.currentCfg = newCfg
 	cm.searchFactory = searchFactory
 
@@ -379,7 +476,23 @@ func (cm *ConfigManager) Reload() error {
 	}
 	cm.loadedFiles = files
 
-	return nil
+	return diff, nil
+}
+
+// exposedConfig returns cfg without the contexts its MCP settings do not
+// expose. cfg is returned as is when they expose every context.
+func exposedConfig(cfg *config.ContextConfig) *config.ContextConfig {
+	if len(cfg.MCP.ExposeContexts) == 0 && len(cfg.MCP.HideContexts) == 0 {
+		return cfg
+	}
+	exposed := *cfg
+	exposed.Contexts = make(config.Contexts, len(cfg.Contexts))
+	for id, searchContext := range cfg.Contexts {
+		if cfg.MCP.Exposes(id) {
+			exposed.Contexts[id] = searchContext
+		}
+	}
+	return &exposed
 }
 
 // Get returns a thread-safe snapshot of the current configuration and search factory.
@@ -405,6 +518,36 @@ type MCPServerBundle struct {
 	ToolHandlers map[string]func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
 }
 
+// RestrictTools unregisters the tools not selected by enable and disable.
+// With enable, only the tools it names and the core tools are kept; disable
+// then removes the tools it names, core tools included. Unknown tool names
+// are an error and leave the tools unchanged.
+func (b *MCPServerBundle) RestrictTools(enable, disable []string) error {
+	for _, name := range slices.Concat(enable, disable) {
+		if _, ok := b.ToolHandlers[name]; !ok {
+			known := make([]string, 0, len(b.ToolHandlers))
+			for tool := range b.ToolHandlers {
+				known = append(known, tool)
+			}
+			sort.Strings(known)
+			return fmt.Errorf("unknown MCP tool %q (available: %s)", name, strings.Join(known, ", "))
+		}
+	}
+
+	var removed []string
+	for name := range b.ToolHandlers {
+		keep := len(enable) == 0 || slices.Contains(enable, name) || slices.Contains(coreTools, name)
+		if !keep || slices.Contains(disable, name) {
+			removed = append(removed, name)
+		}
+	}
+	for _, name := range removed {
+		delete(b.ToolHandlers, name)
+	}
+	b.Server.DeleteTools(removed...)
+	return nil
+}
+
 // BuildMCPServer creates an MCP server instance with all tools/resources/prompts registered.
 func BuildMCPServer(configPath string) (*MCPServerBundle, error) {
 	// Initialize config manager
@@ -415,6 +558,253 @@ func BuildMCPServer(configPath string) (*MCPServerBundle, error) {
 	return buildMCPServerWithManager(cm)
 }
 
+// fieldValuesSample is a get_fields entry when maxValuesPerField is set.
+type fieldValuesSample struct {
+	Values    []string `json:"values"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+// limitFieldValues caps the values of each field to maxValues, marking the
+// fields that were cut. With maxValues <= 0 the fields are returned as is.
+func limitFieldValues(fields ty.UniSet[string], maxValues int) interface{} {
+	if maxValues <= 0 {
+		return fields
+	}
+	limited := make(map[string]fieldValuesSample, len(fields))
+	for name, values := range fields {
+		sorted := append([]string(nil), values...)
+		sort.Strings(sorted)
+		sample := fieldValuesSample{Values: sorted}
+		if len(sorted) > maxValues {
+			sample.Values = sorted[:maxValues]
+			sample.Truncated = true
+		}
+		limited[name] = sample
+	}
+	return limited
+}
+
+// fieldCountsScanSize caps the entries scanned by get_field_values withCounts
+// and facet_fields.
+const fieldCountsScanSize = 1000
+
+// fieldCountsMaxValues caps the values listed per field by get_field_values
+// withCounts and facet_fields.
+const fieldCountsMaxValues = 100
+
+// scanFieldCounts counts the values of fields over at most
+// fieldCountsScanSize entries of search, the ones of get_field_values
+// withCounts and facet_fields. Counts are approximate when more entries
+// matched.
+func scanFieldCounts(ctx context.Context, searchFactory factory.SearchFactory, contextID string, search client.LogSearch, runtimeVars map[string]string, fields []string, alpha bool) (map[string]fieldValueCounts, error) {
+	// Backends have no common way to count values, so scan a capped number
+	// of entries
+	search.Size.S(fieldCountsScanSize)
+	sr, err := searchFactory.GetSearchResult(ctx, contextID, []string{}, search, runtimeVars)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := consumeSearchResult(ctx, sr)
+	if err != nil {
+		return nil, err
+	}
+	approximate := len(entries) >= fieldCountsScanSize
+	if pagination := sr.GetPaginationInfo(); pagination != nil && pagination.HasMore {
+		approximate = true
+	}
+	return countFieldValues(entries, fields, alpha, approximate, fieldCountsMaxValues), nil
+}
+
+// fieldValueCounts is the get_field_values withCounts result for one field.
+type fieldValueCounts struct {
+	Values      []client.FieldCount `json:"values"`
+	Truncated   bool                `json:"truncated,omitempty"`
+	Approximate bool                `json:"approximate,omitempty"`
+}
+
+// countFieldValues counts the values of each field across entries, most
+// frequent first (ties alphabetically) or alphabetically with alpha, keeping
+// at most maxValues per field.
+func countFieldValues(entries []client.LogEntry, fields []string, alpha, approximate bool, maxValues int) map[string]fieldValueCounts {
+	result := make(map[string]fieldValueCounts, len(fields))
+	for _, field := range fields {
+		counts := client.GroupByField(entries, field)
+		if alpha {
+			sort.Slice(counts, func(i, j int) bool { return counts[i].Value < counts[j].Value })
+		}
+		fc := fieldValueCounts{Values: counts, Approximate: approximate}
+		if len(counts) > maxValues {
+			fc.Values = counts[:maxValues]
+			fc.Truncated = true
+		}
+		result[field] = fc
+	}
+	return result
+}
+
+// summarizeScanSize caps the entries scanned by summarize_logs.
+const summarizeScanSize = 1000
+
+// summarizeDefaultTopN and summarizeMaxTopN bound the signatures listed by summarize_logs.
+const (
+	summarizeDefaultTopN = 10
+	summarizeMaxTopN     = 50
+)
+
+// summarizeMaxExample caps the length of the example message of a signature.
+const summarizeMaxExample = 300
+
+// summaryErrorLevels are the levels whose entries summarize_logs groups by
+// signature unless allLevels is set.
+var summaryErrorLevels = map[string]bool{"ERROR": true, "ERR": true, "FATAL": true, "CRITICAL": true, "PANIC": true}
+
+// signatureSummary is one signature of a summarize_logs result.
+type signatureSummary struct {
+	Signature string    `json:"signature"`
+	Count     int       `json:"count"`
+	Level     string    `json:"level,omitempty"`
+	Example   string    `json:"example"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// logSummary is the summarize_logs result.
+type logSummary struct {
+	Scanned     int                 `json:"scanned"`
+	Approximate bool                `json:"approximate,omitempty"`
+	Levels      []client.FieldCount `json:"levels"`
+	Matched     int                 `json:"matched"`
+	Distinct    int                 `json:"distinct"`
+	Signatures  []signatureSummary  `json:"signatures"`
+	Truncated   bool                `json:"truncated,omitempty"`
+
+	GroupBy         string         `json:"groupBy,omitempty"`
+	Groups          []groupSummary `json:"groups,omitempty"`
+	GroupsTruncated bool           `json:"groupsTruncated,omitempty"`
+}
+
+// groupSummary summarizes the entries sharing a value of the groupBy field.
+type groupSummary struct {
+	Value      string             `json:"value"`
+	Count      int                `json:"count"`
+	Matched    int                `json:"matched"`
+	Distinct   int                `json:"distinct"`
+	Signatures []signatureSummary `json:"signatures"`
+	Truncated  bool               `json:"truncated,omitempty"`
+}
+
+// summarizeEntries groups the error entries, or all of them with allLevels, by
+// their signature at the given normalization level. Signatures are ordered by
+// count, then first occurrence, then signature so the order is stable, and at
+// most topN are kept. The example is the first message seen for a signature.
+func summarizeEntries(entries []client.LogEntry, normalization string, allLevels bool, topN int) logSummary {
+	summary := logSummary{Scanned: len(entries), Levels: client.GroupByField(entries, "level"), Signatures: []signatureSummary{}}
+
+	bySignature := map[string]*signatureSummary{}
+	for _, entry := range entries {
+		if !allLevels && !summaryErrorLevels[strings.ToUpper(entry.Level)] {
+			continue
+		}
+		summary.Matched++
+		signature := client.SignatureWithLevel(entry, normalization)
+		s, ok := bySignature[signature]
+		if !ok {
+			s = &signatureSummary{
+				Signature: signature,
+				Level:     entry.Level,
+				Example:   truncateRunes(entry.Message, summarizeMaxExample),
+				FirstSeen: entry.Timestamp,
+				LastSeen:  entry.Timestamp,
+			}
+			bySignature[signature] = s
+		}
+		s.Count++
+		// Entries are not always sorted, e.g. when merged from several backends
+		if entry.Timestamp.Before(s.FirstSeen) {
+			s.FirstSeen = entry.Timestamp
+		}
+		if entry.Timestamp.After(s.LastSeen) {
+			s.LastSeen = entry.Timestamp
+		}
+	}
+
+	for _, s := range bySignature {
+		summary.Signatures = append(summary.Signatures, *s)
+	}
+	sort.Slice(summary.Signatures, func(i, j int) bool {
+		a, b := summary.Signatures[i], summary.Signatures[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if !a.FirstSeen.Equal(b.FirstSeen) {
+			return a.FirstSeen.Before(b.FirstSeen)
+		}
+		return a.Signature < b.Signature
+	})
+	summary.Distinct = len(summary.Signatures)
+	if len(summary.Signatures) > topN {
+		summary.Signatures = summary.Signatures[:topN]
+		summary.Truncated = true
+	}
+	return summary
+}
+
+// summarizeGroups buckets entries by their value of field, "level" falling
+// back to the entry level, and summarizes each bucket like summarizeEntries.
+// Entries without the field are left out. Groups are ordered like
+// client.GroupByField and at most topN are kept, the second result reporting
+// whether some were dropped.
+func summarizeGroups(entries []client.LogEntry, field, normalization string, allLevels bool, topN int) ([]groupSummary, bool) {
+	buckets := map[string][]client.LogEntry{}
+	for _, entry := range entries {
+		v, ok := entry.Fields[field]
+		if !ok && field == "level" {
+			v, ok = entry.Level, true
+		}
+		if !ok || v == nil {
+			continue
+		}
+		if value := fmt.Sprint(v); value != "" {
+			buckets[value] = append(buckets[value], entry)
+		}
+	}
+
+	counts := client.GroupByField(entries, field)
+	truncated := len(counts) > topN
+	if truncated {
+		counts = counts[:topN]
+	}
+	groups := make([]groupSummary, 0, len(counts))
+	for _, c := range counts {
+		summary := summarizeEntries(buckets[c.Value], normalization, allLevels, topN)
+		groups = append(groups, groupSummary{
+			Value:      c.Value,
+			Count:      c.Count,
+			Matched:    summary.Matched,
+			Distinct:   summary.Distinct,
+			Signatures: summary.Signatures,
+			Truncated:  summary.Truncated,
+		})
+	}
+	return groups, truncated
+}
+
+// correlateDefaultSize and correlateMaxSize bound the entries correlate_trace
+// reads from each context.
+const (
+	correlateDefaultSize = 200
+	correlateMaxSize     = 1000
+)
+
+// truncateRunes cuts s to at most max runes, marking the cut with an ellipsis.
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "…"
+}
+
 // buildMCPServerWithManager creates the MCP server with a provided ConfigManager.
 // Internal function for testing.
 //
@@ -431,13 +821,24 @@ func buildMCPServerWithManager(cm *ConfigManager) (*MCPServerBundle, error) {
 
 	// --- Tool: reload_config ---
 	reloadTool := mcp.NewTool("reload_config",
-		mcp.WithDescription("Reload the configuration file from disk. Use this if you have modified the config.yaml file."),
+		mcp.WithDescription(`Reload the configuration file from disk. Use this if you have modified the config.yaml file.
+
+Returns: JSON summary of the reload versus the previous configuration, e.g.
+  {"contexts": {"count": 3, "added": ["new-ctx"], "removed": ["old-ctx"], "changed": ["prod"]}, "clients": {"count": 2}, "searches": {"count": 1}}
+If the file fails to parse, the error is returned and the previous configuration stays active.`),
 	)
 	reloadHandler := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		if err := cm.Reload(); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Reload failed: %v", err)), nil
+		diff, err := cm.Reload()
+		if err != nil {
+			return mcpError(codeConfigError, fmt.Sprintf("reload failed: %v", err), map[string]any{
+				"hint": "Fix the configuration file, the previous configuration stays active, then call reload_config again.",
+			}), nil
+		}
+		jsonBytes, err := json.Marshal(diff)
+		if err != nil {
+			return mcpError(codeInternalError, fmt.Sprintf("failed to marshal reload summary: %v", err), nil), nil
 		}
-		return mcp.NewToolResultText("Configuration successfully reloaded."), nil
+		return mcp.NewToolResultText(string(jsonBytes)), nil
 	}
 	s.AddTool(reloadTool, reloadHandler)
 	handlers["reload_config"] = reloadHandler
@@ -461,7 +862,7 @@ Note: You don't have to call this before every query. You can attempt query_logs
 		sort.Strings(contextIDs)
 		jsonBytes, err := json.Marshal(contextIDs)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal contexts: %v", err)), nil
+			return mcpError(codeInternalError, fmt.Sprintf("failed to marshal contexts: %v", err), nil), nil
 		}
 		return mcp.NewToolResultText(string(jsonBytes)), nil
 	}
@@ -475,21 +876,30 @@ Usage: get_fields contextID=<context>
 
 Parameters:
   contextID (string, required): Context identifier.
+  includeValues (bool, optional): Set to false to return only field names (much smaller payload). Defaults to true.
+  maxValuesPerField (number, optional): Cap the number of values returned per field.
+  refresh (bool, optional): Discover the fields again instead of reusing the ones found
+    for the same context and window in the last minute (fieldsCacheTTL option).
 
 Returns: JSON object mapping field names to arrays of distinct values.
+  - With includeValues=false: sorted JSON array of field names.
+  - With maxValuesPerField=N: each field maps to {"values": [...], "truncated": true} where truncated is set when values were dropped.
 
 You may skip this and directly call query_logs. If a query returns no results, consider then calling get_fields to validate field names or broaden the time window.
 `),
 		mcp.WithString("contextID", mcp.Required(), mcp.Description("Context identifier to inspect.")),
 		mcp.WithString("last", mcp.Description("Optional relative time window for field discovery (e.g. 30m, 2h). Defaults to 15m.")),
+		mcp.WithBoolean("includeValues", mcp.Description("Return field values (default true). Set to false for field names only.")),
+		mcp.WithNumber("maxValuesPerField", mcp.Description("Maximum number of values returned per field; truncated fields are marked.")),
+		mcp.WithBoolean("refresh", mcp.Description("Bypass the field cache and query the backend again.")),
 	)
 	getFieldsHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		_, searchFactory := cm.Get()
+		cfg, searchFactory := cm.Get()
 
 		// Extract required parameter contextID
 		contextID, err := request.RequireString("contextID")
 		if err != nil || contextID == "" {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid or missing contextID: %v", err)), nil
+			return mcpError(codeValidationError, fmt.Sprintf("invalid or missing contextID: %v", err), nil), nil
 		}
 
 		// Provide a small default time window unless user overrides with last
@@ -500,18 +910,36 @@ You may skip this and directly call query_logs. If a query returns no results, c
 			search.Range.Last.S("15m")
 		}
 
-		searchResult, err := searchFactory.GetSearchResult(ctx, contextID, []string{}, search, nil)
-		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+		includeValues := true
+		if v, err := request.RequireBool("includeValues"); err == nil {
+			includeValues = v
+		}
+		maxValues := 0
+		if v, err := request.RequireFloat("maxValuesPerField"); err == nil && int(v) > 0 {
+			maxValues = int(v)
 		}
 
-		fields, _, err := searchResult.GetFields(ctx)
-		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+		if refresh, err := request.RequireBool("refresh"); err == nil && refresh {
+			ctx = factory.WithFieldsRefresh(ctx)
+		}
+
+		var payload interface{}
+		if includeValues {
+			fields, err := searchFactory.GetFields(ctx, contextID, []string{}, search, nil)
+			if err != nil {
+				return handleSearchError(contextID, cfg, err), nil
+			}
+			payload = limitFieldValues(fields, maxValues)
+		} else {
+			names, err := searchFactory.GetFieldNames(ctx, contextID, []string{}, search, nil)
+			if err != nil {
+				return handleSearchError(contextID, cfg, err), nil
+			}
+			payload = names
 		}
-		jsonBytes, err := json.Marshal(fields)
+		jsonBytes, err := json.Marshal(payload)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal fields: %v", err)), nil
+			return mcpError(codeInternalError, fmt.Sprintf("failed to marshal fields: %v", err), nil), nil
 		}
 		return mcp.NewToolResultText(string(jsonBytes)), nil
 	}
@@ -553,6 +981,7 @@ Parameters:
 		  - Logic: AND, OR, NOT
 		  - Grouping: ( )
 		  - Functions: exists(fieldname)
+		  - Missing field: fieldname!exists
 
 		Field-less Search (searching text anywhere in log messages):
 		  - Substring match: _=Exception (finds logs containing "Exception")
@@ -564,6 +993,7 @@ Parameters:
 		  - Find errors with timeout: nativeQuery="level=ERROR AND _~=.*timeout.*"
 		  - Complex: nativeQuery="(level=ERROR OR level=WARN) AND service=api AND _~=.*retry.*"
 		  - Check field exists: nativeQuery="exists(trace_id) AND level=ERROR"
+		  - Check field is missing: nativeQuery="trace_id!exists AND level=ERROR"
 
 		Backend Translation:
 		  The "_" field is automatically translated to backend-specific full-text fields:
@@ -575,36 +1005,67 @@ Behavior:
 	- If contextID is invalid, the response includes suggestions (no need to pre-call list_contexts).
 	- If results are empty, meta.hints will recommend next actions (e.g. broaden last, call get_fields).
 	- If more results are available, meta.nextPageToken will be included for pagination.
-
-Returns: { "entries": [...], "meta": { resultCount, contextID, queryTime, hints?, nextPageToken? } }
+	- If the backend times out after returning some entries, they are returned with meta.partial=true and meta.error.
+	- Malformed context filters (a condition without a field, an unknown op) fail with code VALIDATION_ERROR and the path of the node, e.g. "filters[1]".
+	- Regex operands of the context filters are checked with Go's regexp syntax first: a pattern that does not compile fails with code VALIDATION_ERROR, and nested quantifiers like (a+)+ add a meta.warnings entry. The check is best-effort since backend regex dialects differ.
+	- The same request repeated within a couple of seconds returns the first result again with meta.cached=true instead of querying the backend.
+	- If the backend cannot apply some filters natively (e.g. regex on CloudWatch with useInsights=false), meta.warnings explains they were applied client-side.
+
+	select (array, optional): Fields to keep in each entry, e.g. ["message", "service"]. The id, timestamp and level are always kept unless excluded with a "-" prefix, e.g. "-level". A field missing from an entry is null.
+	output (string, optional): Shape of the entries, "full" (default), "minimal" or "raw":
+	  - full: { id, timestamp, message, level, fields, context_id }, or the select projection
+	  - minimal: { timestamp, level, message } only
+	  - raw: the log line as the backend returned it, a string per entry. Contexts with JSON extraction return the message, the line being parsed into fields.
+	  select only applies to full.
+
+Returns: { "entries": [...], "meta": { resultCount, contextID, queryTime, hints?, nextPageToken?, partial?, error?, warnings?, cached? } }
+With output=full, each entry has an "id" that get_entry accepts to fetch its full detail.
 `),
 		mcp.WithString("contextID", mcp.Required(), mcp.Description("Context identifier to query.")),
 		mcp.WithString("last", mcp.Description(`Relative time window like 15m, 2h, 1d.`)),
 		mcp.WithString("start_time", mcp.Description("Absolute start time (RFC3339).")),
 		mcp.WithString("end_time", mcp.Description("Absolute end time (RFC3339).")),
 		mcp.WithString("pageToken", mcp.Description("Token for pagination to fetch older logs (returned in previous response meta).")),
-		mcp.WithObject("fields", mcp.Description("Exact match key/value filters (JSON object).")),
+		mcp.WithObject("fields", mcp.Description(`Exact match key/value filters (JSON object). A value may be an array to match any of its items, e.g. {"level": ["ERROR", "FATAL"]}.`)),
 		mcp.WithNumber("size", mcp.Description("Maximum number of log entries to return.")),
 		mcp.WithString("nativeQuery", mcp.Description("Raw query in backend's native syntax (Splunk SPL, OpenSearch Lucene). Acts as base search with filters appended.")),
+		mcp.WithBoolean("nativeOnly", mcp.Description("Send nativeQuery exactly as written, ignoring fields and context filters. The time range is still applied unless the native query sets its own.")),
 		mcp.WithObject("variables", mcp.Description("Runtime variables for the context (JSON object).")),
+		mcp.WithArray("select", mcp.Description(`Fields to keep in each entry (array of strings), e.g. ["message", "service"]; id, timestamp and level are kept unless given as "-id", "-timestamp" or "-level".`)),
+		mcp.WithString("output", mcp.Enum(outputFull, outputMinimal, outputRaw), mcp.Description("Shape of the entries: full (default), minimal (timestamp, level and message) or raw (the log lines).")),
 	)
-	queryLogsHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	dedup := newQueryDedup(mcpDedupWindow)
+	// runQueryLogs serves query_logs, and sample_logs with sampled set
+	runQueryLogs := func(ctx context.Context, request mcp.CallToolRequest, sampled bool) (*mcp.CallToolResult, error) {
 		cfg, searchFactory := cm.Get()
 		start := time.Now()
+		progress := newProgressReporter(ctx, request)
 		contextID, err := request.RequireString("contextID")
 		if err != nil || contextID == "" {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid or missing contextID: %v", err)), nil
+			return mcpError(codeValidationError, fmt.Sprintf("invalid or missing contextID: %v", err), nil), nil
 		}
 
-		searchRequest := client.LogSearch{}
-		if last, err := request.RequireString("last"); err == nil && last != "" {
-			searchRequest.Range.Last.S(last)
-		}
-		if startTime, err := request.RequireString("start_time"); err == nil && startTime != "" {
-			searchRequest.Range.Gte.S(startTime)
+		// Answer a request repeated within the dedup window from its first
+		// result; partial results are not reused
+		var response map[string]any
+		if key, ok := dedupKey(request.GetArguments()); ok {
+			if sampled {
+				key = "sample_logs " + key
+			}
+			previous, hit, done := dedup.lookup(ctx, cfg, key)
+			if hit {
+				jsonBytes, err := json.Marshal(cachedResponse(previous))
+				if err != nil {
+					return mcpError(codeInternalError, fmt.Sprintf("failed to marshal response: %v", err), nil), nil
+				}
+				return mcp.NewToolResultText(string(jsonBytes)), nil
+			}
+			defer func() { done(response) }()
 		}
-		if endTime, err := request.RequireString("end_time"); err == nil && endTime != "" {
-			searchRequest.Range.Lte.S(endTime)
+
+		searchRequest := client.LogSearch{}
+		if err := parseTimeRangeArgs(request, &searchRequest.Range); err != nil {
+			return handleValidationError(err), nil
 		}
 		if token, err := request.RequireString("pageToken"); err == nil && token != "" {
 			searchRequest.PageToken.S(token)
@@ -615,38 +1076,35 @@ Returns: { "entries": [...], "meta": { resultCount, contextID, queryTime, hints?
 		if nativeQuery, err := request.RequireString("nativeQuery"); err == nil && nativeQuery != "" {
 			searchRequest.NativeQuery.S(nativeQuery)
 		}
+		if nativeOnly, err := request.RequireBool("nativeOnly"); err == nil {
+			searchRequest.NativeQueryOnly = nativeOnly
+		}
 
-		runtimeVars := make(map[string]string)
+		var selected []string
 		args := request.GetArguments()
 		if args != nil {
 			// Handle 'fields'
 			if rawFields, ok := args["fields"]; ok && rawFields != nil {
 				if fieldMap, ok := rawFields.(map[string]any); ok {
-					if searchRequest.Fields == nil {
-						searchRequest.Fields = ty.MS{}
-					}
-					for k, v := range fieldMap {
-						searchRequest.Fields[k] = fmt.Sprintf("%v", v)
-					}
-				}
-			}
-			// Handle 'variables'
-			if rawVars, ok := args["variables"]; ok && rawVars != nil {
-				if varMap, ok := rawVars.(map[string]any); ok {
-					for k, v := range varMap {
-						runtimeVars[k] = fmt.Sprintf("%v", v)
-					}
+					searchRequest.Fields, searchRequest.Filter = parseFieldArgs(fieldMap)
 				}
 			}
+			selected = stringsArg(args["select"])
+		}
+		runtimeVars := variablesArg(args)
+
+		output := outputFull
+		if o, err := request.RequireString("output"); err == nil && o != "" {
+			output = o
+		}
+		if err := checkOutput(output, selected); err != nil {
+			return handleValidationError(err), nil
 		}
 
 		// Pre-flight check for required variables
 		mergedContext, err := searchFactory.GetSearchContext(ctx, contextID, []string{}, searchRequest, runtimeVars)
 		if err != nil {
-			if errors.Is(err, config.ErrContextNotFound) {
-				return handleContextNotFound(contextID, cfg, err), nil
-			}
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get search context: %v", err)), nil
+			return handleSearchContextError(contextID, cfg, err), nil
 		}
 
 		for name, def := range mergedContext.Search.Variables {
@@ -654,33 +1112,64 @@ Returns: { "entries": [...], "meta": { resultCount, contextID, queryTime, hints?
 				if _, ok := runtimeVars[name]; !ok {
 					if _, ok := os.LookupEnv(name); !ok {
 						errMsg := fmt.Sprintf("Missing required variable '%s'. Please ask the user for '%s' and call the tool again.", name, def.Description)
-						return mcp.NewToolResultError(errMsg), nil
+						return mcpError(codeValidationError, errMsg, map[string]any{"variable": name}), nil
 					}
 				}
 			}
 		}
 
+		// Reject malformed filters and regex operands that do not compile
+		// before reaching the backend
+		if err := mergedContext.Search.ValidateFilter(); err != nil {
+			return handleValidationError(err), nil
+		}
+		warnings, err := checkRegexFilters(mergedContext.Search.GetEffectiveFilter())
+		if err != nil {
+			return handleValidationError(err), nil
+		}
+
 		// Fallback: ensure some time window is always specified to prevent backend errors
 		if !searchRequest.Range.Last.Set && !searchRequest.Range.Gte.Set {
 			searchRequest.Range.Last.S("15m")
 		}
 
-		searchResult, err := searchFactory.GetSearchResult(ctx, contextID, []string{}, searchRequest, runtimeVars)
-		if err != nil {
-			// This logic can be simplified now as we have a pre-flight check
-			return mcp.NewToolResultError(err.Error()), nil
+		if progress != nil && mergedContext.Search.Size.Set {
+			progress.total = float64(mergedContext.Search.Size.Value)
 		}
+		progress.Report(0, fmt.Sprintf("querying %s", contextID))
 
-		entries, _, err := searchResult.GetEntries(ctx)
+		// Backends report the entries as they receive them, from the request
+		// or from GetEntries
+		entriesCtx := client.WithEntryProgress(ctx, func(fetched int) {
+			progress.Report(float64(fetched), fmt.Sprintf("%d entries fetched", fetched))
+		})
+		searchCtx := client.WithFallbackWarnings(entriesCtx, func(fallback client.Fallback) {
+			warnings = append(warnings, fallback.Message)
+		})
+		searchResult, err := searchFactory.GetSearchResult(searchCtx, contextID, []string{}, searchRequest, runtimeVars)
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return handleSearchError(contextID, cfg, err), nil
+		}
+
+		entries, _, err := searchResult.GetEntries(entriesCtx)
+		if err != nil && !client.IsPartial(err) {
+			return handleSearchError(contextID, cfg, err), nil
 		}
+		progress.Done(float64(len(entries)), fmt.Sprintf("%d entries", len(entries)))
 
 		meta := map[string]any{
 			"resultCount": len(entries),
 			"contextID":   contextID,
 			"queryTime":   time.Since(start).String(),
 		}
+		if err != nil {
+			// The backend timed out after returning some entries
+			meta["partial"] = true
+			meta["error"] = err.Error()
+		}
+		if len(warnings) > 0 {
+			meta["warnings"] = warnings
+		}
 		if pagination := searchResult.GetPaginationInfo(); pagination != nil && pagination.NextPageToken != "" {
 			meta["nextPageToken"] = pagination.NextPageToken
 		}
@@ -690,16 +1179,149 @@ Returns: { "entries": [...], "meta": { resultCount, contextID, queryTime, hints?
 				"If you used filters, verify field names via get_fields",
 			}
 		}
-		response := map[string]any{"entries": entries, "meta": meta}
-		jsonBytes, err := json.Marshal(response)
+		if sampled {
+			meta["sampled"] = true
+		}
+		result := map[string]any{"entries": formatEntries(entries, output, selected), "meta": meta}
+		jsonBytes, err := json.Marshal(result)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal response: %v", err)), nil
+			return mcpError(codeInternalError, fmt.Sprintf("failed to marshal response: %v", err), nil), nil
+		}
+		if meta["partial"] == nil {
+			response = result
 		}
 		return mcp.NewToolResultText(string(jsonBytes)), nil
 	}
+	queryLogsHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return runQueryLogs(ctx, request, false)
+	}
 	s.AddTool(queryLogsTool, queryLogsHandler)
 	handlers["query_logs"] = queryLogsHandler
 
+	// --- Tool: sample_logs ---
+	sampleLogsTool := mcp.NewTool("sample_logs",
+		mcp.WithDescription(fmt.Sprintf(`Fetch a handful of recent entries to check a filter before a real query_logs.
+
+Usage: sample_logs contextID=prod-api fields={"level":"ERROR"}
+
+Behaves like query_logs, with the same contextID suggestions and errors, but returns at most %d entries (default %d) from the last %s unless a window is given. Iterate on fields or nativeQuery with it, then run query_logs for the full result.
+
+Returns: { "entries": [...], "meta": { resultCount, contextID, queryTime, sampled: true, hints?, warnings? } }`, sampleMaxSize, sampleDefaultSize, sampleDefaultLast)),
+		mcp.WithString("contextID", mcp.Required(), mcp.Description("Context identifier to sample.")),
+		mcp.WithObject("fields", mcp.Description("Exact match key/value filters (JSON object).")),
+		mcp.WithString("nativeQuery", mcp.Description("Raw query in the backend's native syntax.")),
+		mcp.WithString("last", mcp.Description(fmt.Sprintf("Relative time window like 15m (default %s).", sampleDefaultLast))),
+		mcp.WithNumber("size", mcp.Description(fmt.Sprintf("Number of entries, at most %d (default %d).", sampleMaxSize, sampleDefaultSize))),
+		mcp.WithObject("variables", mcp.Description("Runtime variables for the context (JSON object).")),
+	)
+	sampleLogsHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		sample := map[string]any{"last": sampleDefaultLast, "size": sampleDefaultSize}
+		for _, name := range []string{"contextID", "fields", "nativeQuery", "variables"} {
+			if v, ok := args[name]; ok {
+				sample[name] = v
+			}
+		}
+		if last, ok := args["last"].(string); ok && last != "" {
+			sample["last"] = last
+		}
+		if size, err := request.RequireFloat("size"); err == nil && int(size) > 0 {
+			sample["size"] = min(int(size), sampleMaxSize)
+		}
+		request.Params.Arguments = sample
+		return runQueryLogs(ctx, request, true)
+	}
+	s.AddTool(sampleLogsTool, sampleLogsHandler)
+	handlers["sample_logs"] = sampleLogsHandler
+
+	// --- Tool: get_entry ---
+	getEntryTool := mcp.NewTool("get_entry",
+		mcp.WithDescription(`Fetch the complete detail of one log entry by the id returned in query_logs entries.
+
+Usage: get_entry contextID=<context> id=<entry id>
+
+Parameters:
+  contextID (string, required): Context the entry was returned from.
+  id (string, required): Entry id from query_logs ("<unix nanoseconds>-<hash>").
+  last (string, optional): Window searched for entries without a timestamp (id starting with "0-"). Defaults to 15m.
+  variables (object, optional): Runtime variables for the context (JSON object).
+
+Behavior:
+  - The entry is looked up again in a narrow time range around the timestamp in its id.
+  - If it is not found, the response has code ENTRY_NOT_FOUND.
+
+Returns: { "entry": { id, timestamp, message, level, fields, context_id }, "raw"?: original line when the backend provides it }
+`),
+		mcp.WithString("contextID", mcp.Required(), mcp.Description("Context identifier the entry belongs to.")),
+		mcp.WithString("id", mcp.Required(), mcp.Description("Entry id returned by query_logs.")),
+		mcp.WithString("last", mcp.Description("Relative time window for entries without a timestamp, like 15m, 2h.")),
+		mcp.WithObject("variables", mcp.Description("Runtime variables for the context (JSON object).")),
+	)
+	getEntryHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cfg, searchFactory := cm.Get()
+		contextID, err := request.RequireString("contextID")
+		if err != nil || contextID == "" {
+			return mcpError(codeValidationError, fmt.Sprintf("invalid or missing contextID: %v", err), nil), nil
+		}
+		id, err := request.RequireString("id")
+		if err != nil || id == "" {
+			return mcpError(codeValidationError, fmt.Sprintf("invalid or missing id: %v", err), nil), nil
+		}
+		timestamp, err := client.ParseEntryID(id)
+		if err != nil {
+			return mcpError(codeValidationError, err.Error(), map[string]any{"id": id}), nil
+		}
+
+		searchRequest := client.LogSearch{}
+		if timestamp.IsZero() {
+			last := "15m"
+			if l, err := request.RequireString("last"); err == nil && l != "" {
+				last = l
+			}
+			searchRequest.Range.Last.S(last)
+		} else {
+			// Whole seconds on each side so backends with second precision still include it
+			second := timestamp.Truncate(time.Second)
+			searchRequest.Range.Gte.S(second.Add(-time.Second).Format(time.RFC3339))
+			searchRequest.Range.Lte.S(second.Add(2 * time.Second).Format(time.RFC3339))
+		}
+		searchRequest.Size.S(getEntryMaxScan)
+
+		runtimeVars := variablesArg(request.GetArguments())
+
+		if _, err := searchFactory.GetSearchContext(ctx, contextID, []string{}, searchRequest, runtimeVars); err != nil {
+			return handleSearchContextError(contextID, cfg, err), nil
+		}
+
+		searchResult, err := searchFactory.GetSearchResult(ctx, contextID, []string{}, searchRequest, runtimeVars)
+		if err != nil {
+			return handleSearchError(contextID, cfg, err), nil
+		}
+		entries, _, err := searchResult.GetEntries(ctx)
+		if err != nil {
+			return handleSearchError(contextID, cfg, err), nil
+		}
+
+		for _, entry := range entries {
+			if client.EntryID(entry) != id {
+				continue
+			}
+			response := map[string]any{"entry": mcpLogEntry{ID: id, LogEntry: entry}}
+			if raw, ok := entry.Fields["_raw"].(string); ok && raw != "" {
+				response["raw"] = raw
+			}
+			jsonBytes, err := json.MarshalIndent(response, "", "  ")
+			if err != nil {
+				return mcpError(codeInternalError, fmt.Sprintf("failed to marshal entry: %v", err), nil), nil
+			}
+			return mcp.NewToolResultText(string(jsonBytes)), nil
+		}
+
+		return handleEntryNotFound(contextID, id, len(entries)), nil
+	}
+	s.AddTool(getEntryTool, getEntryHandler)
+	handlers["get_entry"] = getEntryHandler
+
 	// --- Tool: get_field_values ---
 	getFieldValuesTool := mcp.NewTool("get_field_values",
 		mcp.WithDescription(`Get distinct values for specific log fields to understand data distribution or find specific values.
@@ -713,6 +1335,8 @@ Parameters:
   start_time (string, optional): Absolute start time (RFC3339).
   end_time (string, optional): Absolute end time (RFC3339).
   filters (object, optional): Additional key/value filters to apply.
+  withCounts (boolean, optional): Count entries per value, most frequent first.
+  sort (string, optional): "count" (implies withCounts) or "alpha".
 
 Returns: JSON object mapping field names to arrays of distinct values.
 
@@ -721,6 +1345,13 @@ Example response:
   "level": ["ERROR", "WARN", "INFO"],
   "error_code": ["TIMEOUT", "AUTH_FAILURE", "DB_CONN_ERR"]
 }
+
+With withCounts, values are counted over at most 1000 entries (marked
+"approximate" when more matched) and at most 100 values are listed per field
+(marked "truncated"). Ties are ordered alphabetically:
+{
+  "level": {"values": [{"value": "INFO", "count": 812}, {"value": "ERROR", "count": 97}], "approximate": true}
+}
 `),
 		mcp.WithString("contextID", mcp.Required(), mcp.Description("Context identifier to query.")),
 		mcp.WithArray("fields", mcp.Required(), mcp.Description("Field names to get distinct values for (array of strings).")),
@@ -729,69 +1360,44 @@ Example response:
 		mcp.WithString("end_time", mcp.Description("Absolute end time (RFC3339).")),
 		mcp.WithObject("filters", mcp.Description("Additional key/value filters to apply (JSON object).")),
 		mcp.WithObject("variables", mcp.Description("Runtime variables for the context (JSON object).")),
+		mcp.WithBoolean("withCounts", mcp.Description("Return the number of entries per value, most frequent first.")),
+		mcp.WithString("sort", mcp.Description(`Value order: "count" (implies withCounts) or "alpha".`)),
 	)
 	getFieldValuesHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		cfg, searchFactory := cm.Get()
 		contextID, err := request.RequireString("contextID")
 		if err != nil || contextID == "" {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid or missing contextID: %v", err)), nil
+			return mcpError(codeValidationError, fmt.Sprintf("invalid or missing contextID: %v", err), nil), nil
 		}
 
 		// Extract fields array
 		var fieldNames []string
 		args := request.GetArguments()
 		if args != nil {
-			if rawFields, ok := args["fields"]; ok && rawFields != nil {
-				switch v := rawFields.(type) {
-				case []interface{}:
-					for _, f := range v {
-						if s, ok := f.(string); ok {
-							fieldNames = append(fieldNames, s)
-						}
-					}
-				case []string:
-					fieldNames = v
-				}
-			}
+			fieldNames = stringsArg(args["fields"])
 		}
 		if len(fieldNames) == 0 {
-			return mcp.NewToolResultError("fields parameter is required and must be a non-empty array of field names"), nil
+			return mcpError(codeValidationError, "fields parameter is required and must be a non-empty array of field names", nil), nil
 		}
 
-		searchRequest := client.LogSearch{}
-		if last, err := request.RequireString("last"); err == nil && last != "" {
-			searchRequest.Range.Last.S(last)
-		}
-		if startTime, err := request.RequireString("start_time"); err == nil && startTime != "" {
-			searchRequest.Range.Gte.S(startTime)
-		}
-		if endTime, err := request.RequireString("end_time"); err == nil && endTime != "" {
-			searchRequest.Range.Lte.S(endTime)
+		withCounts, _ := request.RequireBool("withCounts")
+		sortBy, _ := request.RequireString("sort")
+		switch sortBy {
+		case "":
+		case "count":
+			withCounts = true
+		case "alpha":
+		default:
+			return mcpError(codeValidationError, fmt.Sprintf(`invalid sort %q: expected "count" or "alpha"`, sortBy), nil), nil
 		}
 
-		// Handle filters and variables
-		runtimeVars := make(map[string]string)
-		if args != nil {
-			if rawFilters, ok := args["filters"]; ok && rawFilters != nil {
-				if filterMap, ok := rawFilters.(map[string]any); ok {
-					if searchRequest.Fields == nil {
-						searchRequest.Fields = ty.MS{}
-					}
-					for k, v := range filterMap {
-						searchRequest.Fields[k] = fmt.Sprintf("%v", v)
-					}
-				}
-			}
-			// Parse variables
-			if rawVars, ok := args["variables"]; ok && rawVars != nil {
-				if varMap, ok := rawVars.(map[string]any); ok {
-					for k, v := range varMap {
-						runtimeVars[k] = fmt.Sprintf("%v", v)
-					}
-				}
-			}
+		searchRequest := client.LogSearch{}
+		if err := parseTimeRangeArgs(request, &searchRequest.Range); err != nil {
+			return handleValidationError(err), nil
 		}
 
+		runtimeVars := searchArgs(args, &searchRequest)
+
 		// Fallback: ensure some time window is always specified
 		if !searchRequest.Range.Last.Set && !searchRequest.Range.Gte.Set {
 			searchRequest.Range.Last.S("15m")
@@ -800,40 +1406,134 @@ Example response:
 		// Pre-flight check for context existence
 		_, err = searchFactory.GetSearchContext(ctx, contextID, []string{}, searchRequest, runtimeVars)
 		if err != nil {
-			if errors.Is(err, config.ErrContextNotFound) {
-				return handleContextNotFound(contextID, cfg, err), nil
+			return handleSearchContextError(contextID, cfg, err), nil
+		}
+
+		if withCounts {
+			counts, err := scanFieldCounts(ctx, searchFactory, contextID, searchRequest, runtimeVars, fieldNames, sortBy == "alpha")
+			if err != nil {
+				return handleSearchError(contextID, cfg, fmt.Errorf("failed to get field values: %w", err)), nil
 			}
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get search context: %v", err)), nil
+			jsonBytes, err := json.Marshal(counts)
+			if err != nil {
+				return mcpError(codeInternalError, fmt.Sprintf("failed to marshal field values: %v", err), nil), nil
+			}
+			return mcp.NewToolResultText(string(jsonBytes)), nil
 		}
 
 		fieldValues, err := searchFactory.GetFieldValues(ctx, contextID, []string{}, searchRequest, fieldNames, runtimeVars)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get field values: %v", err)), nil
+			return handleSearchError(contextID, cfg, fmt.Errorf("failed to get field values: %w", err)), nil
+		}
+		if sortBy == "alpha" {
+			for _, values := range fieldValues {
+				sort.Strings(values)
+			}
 		}
 
 		jsonBytes, err := json.Marshal(fieldValues)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal field values: %v", err)), nil
+			return mcpError(codeInternalError, fmt.Sprintf("failed to marshal field values: %v", err), nil), nil
 		}
 		return mcp.NewToolResultText(string(jsonBytes)), nil
 	}
 	s.AddTool(getFieldValuesTool, getFieldValuesHandler)
 	handlers["get_field_values"] = getFieldValuesHandler
 
-	getContextDetailsTool := mcp.NewTool("get_context_details",
-		mcp.WithDescription(`Inspect a context's configuration including required variables, backend type, and capabilities.
+	// --- Tool: facet_fields ---
+	facetFieldsTool := mcp.NewTool("facet_fields",
+		mcp.WithDescription(`Count the entries per distinct value of log fields, to decide what to filter on.
 
-Usage: get_context_details contextID=<context>
+Usage: facet_fields contextID=<context> fields=["level","service"] [last=15m]
 
 Parameters:
-  contextID (string, required): Context identifier to inspect.
+  contextID (string, required): Context identifier.
+  fields (array of strings, required): Field names to count values for.
+  last (string, optional): Relative time window (e.g. 15m, 2h). Defaults to 15m.
+  start_time (string, optional): Absolute start time (RFC3339).
+  end_time (string, optional): Absolute end time (RFC3339).
+  filters (object, optional): Additional key/value filters to apply.
+  variables (object, optional): Runtime variables for the context.
 
-Returns: JSON object with context configuration (backend type, variables, field mappings).
+Values are counted over at most 1000 entries (marked "approximate" when more
+matched), most frequent first with ties ordered alphabetically, and at most
+100 values are listed per field (marked "truncated"). Entries without a field
+are not counted for it.
 
-When to use:
-  - Before query_logs if you need to know what variables are required
-  - To discover backend-specific capabilities (e.g., native query syntax)
-  - To understand field mappings and available filters for a context
+Example response:
+{
+  "level": {"values": [{"value": "INFO", "count": 812}, {"value": "ERROR", "count": 97}], "approximate": true},
+  "service": {"values": [{"value": "api", "count": 640}, {"value": "worker", "count": 269}]}
+}
+`),
+		mcp.WithString("contextID", mcp.Required(), mcp.Description("Context identifier to query.")),
+		mcp.WithArray("fields", mcp.Required(), mcp.Description("Field names to count values for (array of strings).")),
+		mcp.WithString("last", mcp.Description("Relative time window like 15m, 2h, 1d.")),
+		mcp.WithString("start_time", mcp.Description("Absolute start time (RFC3339).")),
+		mcp.WithString("end_time", mcp.Description("Absolute end time (RFC3339).")),
+		mcp.WithObject("filters", mcp.Description("Additional key/value filters to apply (JSON object).")),
+		mcp.WithObject("variables", mcp.Description("Runtime variables for the context (JSON object).")),
+	)
+	facetFieldsHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cfg, searchFactory := cm.Get()
+		contextID, err := request.RequireString("contextID")
+		if err != nil || contextID == "" {
+			return mcpError(codeValidationError, fmt.Sprintf("invalid or missing contextID: %v", err), nil), nil
+		}
+
+		var fieldNames []string
+		args := request.GetArguments()
+		if args != nil {
+			fieldNames = stringsArg(args["fields"])
+		}
+		if len(fieldNames) == 0 {
+			return mcpError(codeValidationError, "fields parameter is required and must be a non-empty array of field names", nil), nil
+		}
+
+		searchRequest := client.LogSearch{}
+		if err := parseTimeRangeArgs(request, &searchRequest.Range); err != nil {
+			return handleValidationError(err), nil
+		}
+
+		runtimeVars := searchArgs(args, &searchRequest)
+
+		if !searchRequest.Range.Last.Set && !searchRequest.Range.Gte.Set {
+			searchRequest.Range.Last.S("15m")
+		}
+
+		// Pre-flight check for context existence
+		_, err = searchFactory.GetSearchContext(ctx, contextID, []string{}, searchRequest, runtimeVars)
+		if err != nil {
+			return handleSearchContextError(contextID, cfg, err), nil
+		}
+
+		counts, err := scanFieldCounts(ctx, searchFactory, contextID, searchRequest, runtimeVars, fieldNames, false)
+		if err != nil {
+			return handleSearchError(contextID, cfg, fmt.Errorf("failed to count field values: %w", err)), nil
+		}
+		jsonBytes, err := json.Marshal(counts)
+		if err != nil {
+			return mcpError(codeInternalError, fmt.Sprintf("failed to marshal field facets: %v", err), nil), nil
+		}
+		return mcp.NewToolResultText(string(jsonBytes)), nil
+	}
+	s.AddTool(facetFieldsTool, facetFieldsHandler)
+	handlers["facet_fields"] = facetFieldsHandler
+
+	getContextDetailsTool := mcp.NewTool("get_context_details",
+		mcp.WithDescription(`Inspect a context's configuration including required variables, backend type, and capabilities.
+
+Usage: get_context_details contextID=<context>
+
+Parameters:
+  contextID (string, required): Context identifier to inspect.
+
+Returns: JSON object with context configuration (backend type, variables, field mappings).
+
+When to use:
+  - Before query_logs if you need to know what variables are required
+  - To discover backend-specific capabilities (e.g., native query syntax)
+  - To understand field mappings and available filters for a context
 `),
 		mcp.WithString("contextID", mcp.Required(), mcp.Description("The context ID to inspect.")),
 	)
@@ -841,24 +1541,337 @@ When to use:
 		cfg, searchFactory := cm.Get()
 		contextID, err := request.RequireString("contextID")
 		if err != nil {
-			return mcp.NewToolResultError("contextID is required"), nil
+			return mcpError(codeValidationError, "contextID is required", nil), nil
 		}
 		searchContext, err := searchFactory.GetSearchContext(ctx, contextID, []string{}, client.LogSearch{}, nil)
 		if err != nil {
-			if errors.Is(err, config.ErrContextNotFound) {
-				return handleContextNotFound(contextID, cfg, err), nil
-			}
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get context details: %v", err)), nil
+			return handleSearchContextError(contextID, cfg, err), nil
 		}
 		jsonBytes, err := json.Marshal(searchContext.Search)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal context details: %v", err)), nil
+			return mcpError(codeInternalError, fmt.Sprintf("failed to marshal context details: %v", err), nil), nil
 		}
 		return mcp.NewToolResultText(string(jsonBytes)), nil
 	}
 	s.AddTool(getContextDetailsTool, getContextDetailsHandler)
 	handlers["get_context_details"] = getContextDetailsHandler
 
+	// --- Tool: ping_context ---
+	pingContextTool := mcp.NewTool("ping_context",
+		mcp.WithDescription(`Check that a context's backend is reachable and accepts the configured credentials.
+
+Usage: ping_context contextID=<context>
+
+Parameters:
+  contextID (string, required): Context identifier to check.
+  timeout (string, optional): Maximum duration of the check (e.g. 5s). Defaults to 10s.
+  variables (object, optional): Runtime variables for the context (JSON object).
+
+Behavior:
+  - Runs a one-entry query over the last 5 minutes; an empty result still means the backend is healthy.
+  - Secrets from the client configuration are masked in error details.
+  - If contextID is invalid, the response has code CONTEXT_NOT_FOUND with suggestions.
+
+Returns: { "contextID", "backendType", "status": "ok"|"error", "latencyMs", "sampleFound"?, "code"?: "TIMEOUT"|"BACKEND_UNAVAILABLE", "error"? }
+
+When to use:
+  - When query_logs returns nothing or fails, to tell a connectivity/auth problem from an empty result.
+`),
+		mcp.WithString("contextID", mcp.Required(), mcp.Description("The context ID to check.")),
+		mcp.WithString("timeout", mcp.Description("Maximum duration of the check (e.g. 5s). Defaults to 10s.")),
+		mcp.WithObject("variables", mcp.Description("Runtime variables for the context (JSON object).")),
+	)
+	pingContextHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cfg, searchFactory := cm.Get()
+		contextID, err := request.RequireString("contextID")
+		if err != nil || contextID == "" {
+			return mcpError(codeValidationError, "contextID is required", nil), nil
+		}
+
+		timeout := pingDefaultTimeout
+		if v, e := request.RequireString("timeout"); e == nil && v != "" {
+			if timeout, err = time.ParseDuration(v); err != nil || timeout <= 0 {
+				return mcpError(codeValidationError, fmt.Sprintf("invalid timeout %q: expected a positive duration like 5s", v), nil), nil
+			}
+		}
+
+		runtimeVars := variablesArg(request.GetArguments())
+
+		searchContext, err := searchFactory.GetSearchContext(ctx, contextID, []string{}, client.LogSearch{}, runtimeVars)
+		if err != nil {
+			return handleSearchContextError(contextID, cfg, err), nil
+		}
+		payload := map[string]any{
+			"contextID":   contextID,
+			"backendType": cfg.Clients[searchContext.Client].Type,
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		probe, err := factory.Probe(pingCtx, cfg, searchFactory, contextID, runtimeVars)
+		payload["latencyMs"] = probe.Latency.Milliseconds()
+		if err != nil {
+			payload["status"] = "error"
+			payload["code"] = "BACKEND_UNAVAILABLE"
+			if client.IsTimeout(err) || errors.Is(pingCtx.Err(), context.DeadlineExceeded) {
+				payload["code"] = "TIMEOUT"
+			}
+			payload["error"] = err.Error()
+		} else {
+			payload["status"] = "ok"
+			payload["sampleFound"] = probe.Entries > 0
+		}
+
+		jsonBytes, err := json.Marshal(payload)
+		if err != nil {
+			return mcpError(codeInternalError, fmt.Sprintf("failed to marshal ping result: %v", err), nil), nil
+		}
+		return mcp.NewToolResultText(string(jsonBytes)), nil
+	}
+	s.AddTool(pingContextTool, pingContextHandler)
+	handlers["ping_context"] = pingContextHandler
+
+	// --- Tool: summarize_logs ---
+	summarizeLogsTool := mcp.NewTool("summarize_logs",
+		mcp.WithDescription(`Summarize the logs of a context: counts per level and the top error signatures,
+each with an example message and its first/last occurrence.
+
+Usage: summarize_logs contextID=<context> [last=1h] [topN=10] [groupBy=service]
+
+Parameters:
+  contextID (string, required): Context identifier.
+  last (string, optional): Relative time window (e.g. 15m, 2h). Defaults to 15m.
+  start_time (string, optional): Absolute start time (RFC3339).
+  end_time (string, optional): Absolute end time (RFC3339).
+  filters (object, optional): Additional key/value filters to apply.
+  variables (object, optional): Runtime variables for the context.
+  topN (number, optional): Signatures to list, default 10, at most 50.
+  normalization (string, optional): "basic" (default) replaces numbers, UUIDs,
+    hex values and quoted literals; "aggressive" also replaces any word with a
+    digit, emails and URLs. Use "basic" if distinct errors are merged together.
+  allLevels (boolean, optional): Group every entry by signature, not only
+    ERROR/FATAL/CRITICAL/PANIC ones.
+  groupBy (string, optional): "level" or a field name. Adds "groups", one per
+    value of the field (entries without it are left out), each with its count
+    and its own top signatures. At most topN groups, the most frequent first.
+
+At most 1000 entries are scanned (marked "approximate" when more matched).
+Signatures are ordered by count, then first occurrence. "distinct" is the number
+of signatures before topN is applied.
+
+Example response:
+{
+  "scanned": 1000, "approximate": true,
+  "levels": [{"value": "INFO", "count": 903}, {"value": "ERROR", "count": 97}],
+  "matched": 97, "distinct": 4,
+  "signatures": [{"signature": "timeout calling <str> after <num> ms", "count": 80, "level": "ERROR",
+    "example": "timeout calling \"payments\" after 3000 ms",
+    "firstSeen": "2024-05-01T10:30:00Z", "lastSeen": "2024-05-01T10:44:12Z"}]
+}
+`),
+		mcp.WithString("contextID", mcp.Required(), mcp.Description("Context identifier to summarize.")),
+		mcp.WithString("last", mcp.Description("Relative time window like 15m, 2h, 1d.")),
+		mcp.WithString("start_time", mcp.Description("Absolute start time (RFC3339).")),
+		mcp.WithString("end_time", mcp.Description("Absolute end time (RFC3339).")),
+		mcp.WithObject("filters", mcp.Description("Additional key/value filters to apply (JSON object).")),
+		mcp.WithObject("variables", mcp.Description("Runtime variables for the context (JSON object).")),
+		mcp.WithNumber("topN", mcp.Description("Signatures to list (default 10, max 50).")),
+		mcp.WithString("normalization", mcp.Description(`Signature normalization: "basic" (default) or "aggressive".`)),
+		mcp.WithBoolean("allLevels", mcp.Description("Group all entries by signature, not only errors.")),
+		mcp.WithString("groupBy", mcp.Description(`Break the summary down by "level" or a field name, e.g. service.`)),
+	)
+	summarizeLogsHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cfg, searchFactory := cm.Get()
+		contextID, err := request.RequireString("contextID")
+		if err != nil || contextID == "" {
+			return mcpError(codeValidationError, fmt.Sprintf("invalid or missing contextID: %v", err), nil), nil
+		}
+
+		topN := summarizeDefaultTopN
+		if v, err := request.RequireFloat("topN"); err == nil && int(v) > 0 {
+			topN = min(int(v), summarizeMaxTopN)
+		}
+		normalization, _ := request.RequireString("normalization")
+		switch normalization {
+		case "":
+			normalization = client.SignatureBasic
+		case client.SignatureBasic, client.SignatureAggressive:
+		default:
+			return mcpError(codeValidationError, fmt.Sprintf(`invalid normalization %q: expected "basic" or "aggressive"`, normalization), nil), nil
+		}
+		allLevels, _ := request.RequireBool("allLevels")
+		groupBy, _ := request.RequireString("groupBy")
+
+		searchRequest := client.LogSearch{}
+		if err := parseTimeRangeArgs(request, &searchRequest.Range); err != nil {
+			return handleValidationError(err), nil
+		}
+
+		runtimeVars := searchArgs(request.GetArguments(), &searchRequest)
+
+		if !searchRequest.Range.Last.Set && !searchRequest.Range.Gte.Set {
+			searchRequest.Range.Last.S("15m")
+		}
+		searchRequest.Size.S(summarizeScanSize)
+
+		// Pre-flight check for context existence
+		_, err = searchFactory.GetSearchContext(ctx, contextID, []string{}, searchRequest, runtimeVars)
+		if err != nil {
+			return handleSearchContextError(contextID, cfg, err), nil
+		}
+
+		sr, err := searchFactory.GetSearchResult(ctx, contextID, []string{}, searchRequest, runtimeVars)
+		if err != nil {
+			return handleSearchError(contextID, cfg, fmt.Errorf("failed to summarize logs: %w", err)), nil
+		}
+		entries, err := consumeSearchResult(ctx, sr)
+		if err != nil {
+			return handleSearchError(contextID, cfg, fmt.Errorf("failed to summarize logs: %w", err)), nil
+		}
+
+		summary := summarizeEntries(entries, normalization, allLevels, topN)
+		summary.Approximate = len(entries) >= summarizeScanSize
+		if groupBy != "" {
+			summary.GroupBy = groupBy
+			summary.Groups, summary.GroupsTruncated = summarizeGroups(entries, groupBy, normalization, allLevels, topN)
+		}
+		if pagination := sr.GetPaginationInfo(); pagination != nil && pagination.HasMore {
+			summary.Approximate = true
+		}
+		jsonBytes, err := json.Marshal(summary)
+		if err != nil {
+			return mcpError(codeInternalError, fmt.Sprintf("failed to marshal summary: %v", err), nil), nil
+		}
+		return mcp.NewToolResultText(string(jsonBytes)), nil
+	}
+	s.AddTool(summarizeLogsTool, summarizeLogsHandler)
+	handlers["summarize_logs"] = summarizeLogsHandler
+
+	// --- Tool: correlate_trace ---
+	correlateTraceTool := mcp.NewTool("correlate_trace",
+		mcp.WithDescription(`Follow one trace or request id across several contexts: the same field filter runs
+on every context and the entries are merged into a single timeline.
+
+Usage: correlate_trace field=traceId value=<id> contextIDs=["api","worker"] [last=1h]
+
+Parameters:
+  field (string, required): Field holding the trace or request id (e.g. traceId).
+  value (string, required): Id to follow.
+  contextIDs (array of strings, required): Contexts to search.
+  last (string, optional): Relative time window (e.g. 15m, 2h). Defaults to 15m.
+  start_time (string, optional): Absolute start time (RFC3339).
+  end_time (string, optional): Absolute end time (RFC3339).
+  variables (object, optional): Runtime variables for the contexts.
+  size (number, optional): Entries per context, default 200, at most 1000.
+
+Entries are sorted by timestamp and carry their context in "context_id".
+"counts" has the number of entries of each context. A context that fails is
+listed in "errors" and the others are still returned; the call fails only when
+every context does.
+
+Example response:
+{
+  "entries": [{"id": "...", "timestamp": "2024-05-01T10:30:00Z", "message": "GET /orders", "context_id": "api", ...},
+              {"id": "...", "timestamp": "2024-05-01T10:30:01Z", "message": "order job started", "context_id": "worker", ...}],
+  "counts": {"api": 1, "worker": 1},
+  "errors": [{"contextId": "db", "error": "connection refused"}]
+}
+`),
+		mcp.WithString("field", mcp.Required(), mcp.Description("Field holding the trace or request id.")),
+		mcp.WithString("value", mcp.Required(), mcp.Description("Trace or request id to follow.")),
+		mcp.WithArray("contextIDs", mcp.Required(), mcp.Description("Contexts to search (array of strings).")),
+		mcp.WithString("last", mcp.Description("Relative time window like 15m, 2h, 1d.")),
+		mcp.WithString("start_time", mcp.Description("Absolute start time (RFC3339).")),
+		mcp.WithString("end_time", mcp.Description("Absolute end time (RFC3339).")),
+		mcp.WithObject("variables", mcp.Description("Runtime variables for the contexts (JSON object).")),
+		mcp.WithNumber("size", mcp.Description("Entries per context (default 200, max 1000).")),
+	)
+	correlateTraceHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cfg, searchFactory := cm.Get()
+		field, err := request.RequireString("field")
+		if err != nil || field == "" {
+			return mcpError(codeValidationError, fmt.Sprintf("invalid or missing field: %v", err), nil), nil
+		}
+		value, err := request.RequireString("value")
+		if err != nil || value == "" {
+			return mcpError(codeValidationError, fmt.Sprintf("invalid or missing value: %v", err), nil), nil
+		}
+		contextIDs := stringsArg(request.GetArguments()["contextIDs"])
+		if len(contextIDs) == 0 {
+			return mcpError(codeValidationError, "contextIDs parameter is required and must be a non-empty array of context ids", nil), nil
+		}
+		for _, cid := range contextIDs {
+			if _, ok := cfg.Contexts[cid]; !ok {
+				return handleContextNotFound(cid, cfg, fmt.Errorf("%w: %s", config.ErrContextNotFound, cid)), nil
+			}
+		}
+
+		searchRequest := client.LogSearch{Fields: ty.MS{field: value}}
+		if err := parseTimeRangeArgs(request, &searchRequest.Range); err != nil {
+			return handleValidationError(err), nil
+		}
+		if !searchRequest.Range.Last.Set && !searchRequest.Range.Gte.Set {
+			searchRequest.Range.Last.S("15m")
+		}
+		size := correlateDefaultSize
+		if v, err := request.RequireFloat("size"); err == nil && int(v) > 0 {
+			size = min(int(v), correlateMaxSize)
+		}
+		searchRequest.Size.S(size)
+
+		runtimeVars := variablesArg(request.GetArguments())
+
+		var (
+			mu       sync.Mutex
+			entries  []client.LogEntry
+			counts   = make(map[string]int, len(contextIDs))
+			failures = client.MultiError{Total: len(contextIDs)}
+		)
+		err = client.FanOut(ctx, contextIDs, client.DefaultConcurrency, func(cid string) {
+			reqCopy := searchRequest
+			reqCopy.Fields = ty.MergeM(make(ty.MS, 1), searchRequest.Fields)
+
+			var found []client.LogEntry
+			sr, err := searchFactory.GetSearchResult(ctx, cid, []string{}, reqCopy, runtimeVars)
+			if err == nil {
+				found, err = consumeSearchResult(ctx, sr)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures.Add(cid, err)
+				return
+			}
+			entries = append(entries, found...)
+			counts[cid] = len(found)
+		})
+		if err != nil {
+			return handleSearchError(strings.Join(contextIDs, ","), cfg, err), nil
+		}
+		if failures.AllFailed() {
+			return mcpError(codeBackendUnavailable, failures.Error(), map[string]any{
+				"errors": failures.Errors,
+				"hint":   "Call ping_context on the contexts to tell a connectivity or credentials problem from a failing query.",
+			}), nil
+		}
+
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		})
+		response := map[string]any{"entries": withEntryIDs(entries), "counts": counts}
+		if len(failures.Errors) > 0 {
+			response["errors"] = failures.Errors
+		}
+		jsonBytes, err := json.Marshal(response)
+		if err != nil {
+			return mcpError(codeInternalError, fmt.Sprintf("failed to marshal response: %v", err), nil), nil
+		}
+		return mcp.NewToolResultText(string(jsonBytes)), nil
+	}
+	s.AddTool(correlateTraceTool, correlateTraceHandler)
+	handlers["correlate_trace"] = correlateTraceHandler
+
 	// Resource providing context list (alternative to tool usage)
 	contextsResource := mcp.NewResource(
 		"logviewer://contexts",
@@ -880,6 +1893,9 @@ When to use:
 		return []mcp.ResourceContents{mcp.TextResourceContents{URI: "logviewer://contexts", MIMEType: "application/json", Text: string(b)}}, nil
 	})
 
+	// Resources with the fields and the search schema of each context
+	registerContextResources(s, cm)
+
 	// Register dynamic context-specific prompts
 	generateContextPrompts(s, cm)
 
@@ -920,9 +1936,43 @@ Return a short plan then perform tool calls.
 
 func init() {
 	mcpCmd.Flags().IntVar(&mcpPort, "port", 8081, "Port for the MCP server")
+	mcpCmd.Flags().StringSliceVar(&mcpEnableTools, "enable-tools", nil,
+		"Comma-separated tools to expose, plus list_contexts and query_logs (default all)")
+	mcpCmd.Flags().StringSliceVar(&mcpDisableTools, "disable-tools", nil,
+		"Comma-separated tools not to expose (e.g. reload_config)")
+	mcpCmd.Flags().DurationVar(&mcpDedupWindow, "dedup-window", defaultQueryDedupWindow,
+		"How long a query_logs result answers the same request again (0 disables)")
 	rootCmd.AddCommand(mcpCmd)
 }
 
+// Codes of the mcpError envelope.
+const (
+	codeValidationError    = "VALIDATION_ERROR"
+	codeContextNotFound    = "CONTEXT_NOT_FOUND"
+	codeEntryNotFound      = "ENTRY_NOT_FOUND"
+	codeBackendUnavailable = "BACKEND_UNAVAILABLE"
+	codeTimeout            = "TIMEOUT"
+	codeConfigError        = "CONFIG_ERROR"
+	codeInternalError      = "INTERNAL_ERROR"
+)
+
+// mcpError builds the error result every tool returns: a JSON object with a
+// machine-friendly code and the error message, plus the extra keys such as
+// hint or suggestions.
+func mcpError(code, msg string, extra map[string]any) *mcp.CallToolResult {
+	payload := make(map[string]any, len(extra)+2)
+	for k, v := range extra {
+		payload[k] = v
+	}
+	payload["code"] = code
+	payload["error"] = msg
+	b, err := json.Marshal(payload)
+	if err != nil {
+		b, _ = json.Marshal(map[string]string{"code": code, "error": msg})
+	}
+	return mcp.NewToolResultError(string(b))
+}
+
 // handleContextNotFound creates a standardized MCP response for context not found errors.
 // It includes suggestions for similar context names to help users correct typos.
 func handleContextNotFound(contextID string, cfg *config.ContextConfig, err error) *mcp.CallToolResult {
@@ -931,20 +1981,473 @@ func handleContextNotFound(contextID string, cfg *config.ContextConfig, err erro
 		all = append(all, id)
 	}
 	sort.Strings(all)
-	suggestions := suggestSimilar(contextID, all, 3)
-	payload := map[string]any{
-		"code":              "CONTEXT_NOT_FOUND",
-		"error":             err.Error(),
+	return mcpError(codeContextNotFound, err.Error(), map[string]any{
 		"invalidContext":    contextID,
 		"availableContexts": all,
-		"suggestions":       suggestions,
+		"suggestions":       suggestSimilar(contextID, all, 3),
 		"hint":              "Use a suggested contextID or call list_contexts for enumeration.",
+	})
+}
+
+// handleSearchContextError maps an error resolving the search context, before
+// the backend is reached, to its envelope.
+func handleSearchContextError(contextID string, cfg *config.ContextConfig, err error) *mcp.CallToolResult {
+	if errors.Is(err, config.ErrContextNotFound) {
+		return handleContextNotFound(contextID, cfg, err)
+	}
+	if isValidationError(err) {
+		return handleValidationError(err)
+	}
+	return mcpError(codeValidationError, fmt.Sprintf("failed to get search context: %v", err), map[string]any{
+		"contextID": contextID,
+		"hint":      "Call get_context_details to check the variables and configuration of the context.",
+	})
+}
+
+// handleSearchError maps an error of a search to its envelope; errors not
+// recognized are backend failures.
+func handleSearchError(contextID string, cfg *config.ContextConfig, err error) *mcp.CallToolResult {
+	switch {
+	case errors.Is(err, config.ErrContextNotFound):
+		return handleContextNotFound(contextID, cfg, err)
+	case isValidationError(err):
+		return handleValidationError(err)
+	case client.IsBackendUnavailable(err):
+		return handleBackendUnavailable(contextID, err)
+	case client.IsTimeout(err):
+		return mcpError(codeTimeout, err.Error(), map[string]any{
+			"contextID": contextID,
+			"hint":      "Narrow the query (shorter 'last', more filters, smaller size) and call the tool again.",
+		})
+	}
+	return mcpError(codeBackendUnavailable, err.Error(), map[string]any{
+		"contextID": contextID,
+		"hint":      "Call ping_context to tell a connectivity or credentials problem from a failing query.",
+	})
+}
+
+// pingDefaultTimeout bounds ping_context when no timeout is given.
+const pingDefaultTimeout = 10 * time.Second
+
+// getEntryMaxScan caps the entries fetched around a timestamp by get_entry.
+const getEntryMaxScan = 1000
+
+// parseFieldArgs converts the "fields" argument of query_logs into exact
+// match fields and a filter. Scalars become Fields entries; an array matches
+// any of its items, as an OR group ANDed with the other fields. Empty arrays
+// are ignored and items are stringified like scalars.
+func parseFieldArgs(fieldMap map[string]any) (ty.MS, *client.Filter) {
+	fields := ty.MS{}
+	var groups []client.Filter
+
+	keys := make([]string, 0, len(fieldMap))
+	for k := range fieldMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		items, ok := fieldMap[k].([]any)
+		if !ok {
+			fields[k] = fmt.Sprintf("%v", fieldMap[k])
+			continue
+		}
+		switch len(items) {
+		case 0:
+		case 1:
+			fields[k] = fmt.Sprintf("%v", items[0])
+		default:
+			group := client.Filter{Logic: client.LogicOr}
+			for _, item := range items {
+				group.Filters = append(group.Filters, client.Filter{Field: k, Op: operator.Equals, Value: fmt.Sprintf("%v", item)})
+			}
+			groups = append(groups, group)
+		}
+	}
+
+	switch len(groups) {
+	case 0:
+		return fields, nil
+	case 1:
+		return fields, &groups[0]
+	default:
+		return fields, &client.Filter{Logic: client.LogicAnd, Filters: groups}
+	}
+}
+
+// mcpLogEntry is a log entry with the id accepted by get_entry.
+type mcpLogEntry struct {
+	ID string `json:"id"`
+	client.LogEntry
+}
+
+// withEntryIDs attaches the get_entry id to each entry.
+func withEntryIDs(entries []client.LogEntry) []mcpLogEntry {
+	out := make([]mcpLogEntry, len(entries))
+	for i, entry := range entries {
+		out[i] = mcpLogEntry{ID: client.EntryID(entry), LogEntry: entry}
+	}
+	return out
+}
+
+// stringsArg returns the strings of an array argument, skipping other items.
+func stringsArg(raw any) []string {
+	switch v := raw.(type) {
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// searchArgs sets the "filters" argument of a tool on search, as exact
+// matches, and returns its "variables" argument as runtime variables. Values
+// are stringified.
+func searchArgs(args map[string]any, search *client.LogSearch) map[string]string {
+	if filters, ok := args["filters"].(map[string]any); ok {
+		if search.Fields == nil {
+			search.Fields = ty.MS{}
+		}
+		for k, v := range filters {
+			search.Fields[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return variablesArg(args)
+}
+
+// variablesArg returns the "variables" argument of a tool as runtime
+// variables, values being stringified.
+func variablesArg(args map[string]any) map[string]string {
+	runtimeVars := make(map[string]string)
+	if vars, ok := args["variables"].(map[string]any); ok {
+		for k, v := range vars {
+			runtimeVars[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return runtimeVars
+}
+
+// projectEntries reduces each entry to the id, timestamp and level, minus
+// the ones excluded with a "-" prefix, and the selected fields. message and
+// context_id select the entry's own, other names its fields, with a nil value
+// when the entry doesn't have it.
+func projectEntries(entries []client.LogEntry, selected []string) []map[string]any {
+	core := map[string]bool{"id": true, "timestamp": true, "level": true}
+	var names []string
+	for _, name := range selected {
+		name = strings.TrimSpace(name)
+		if excluded, ok := strings.CutPrefix(name, "-"); ok {
+			delete(core, excluded)
+		} else if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	out := make([]map[string]any, len(entries))
+	for i, entry := range entries {
+		projected := make(map[string]any, len(core)+len(names))
+		for _, name := range names {
+			switch name {
+			case "message":
+				projected[name] = entry.Message
+			case "context_id":
+				projected[name] = entry.ContextID
+			case "id":
+				projected[name] = client.EntryID(entry)
+			case "timestamp":
+				projected[name] = entry.Timestamp
+			case "level":
+				projected[name] = entry.Level
+			default:
+				projected[name] = entry.Fields[name] // nil when missing
+			}
+		}
+		if core["id"] {
+			projected["id"] = client.EntryID(entry)
+		}
+		if core["timestamp"] {
+			projected["timestamp"] = entry.Timestamp
+		}
+		if core["level"] {
+			projected["level"] = entry.Level
+		}
+		out[i] = projected
+	}
+	return out
+}
+
+// Bounds of sample_logs, kept small so the sample comes back quickly.
+const (
+	sampleDefaultSize = 5
+	sampleMaxSize     = 20
+	sampleDefaultLast = "5m"
+)
+
+// Values of the query_logs output parameter.
+const (
+	outputFull    = "full"
+	outputMinimal = "minimal"
+	outputRaw     = "raw"
+)
+
+// minimalLogEntry is an entry of query_logs with output=minimal.
+type minimalLogEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+}
+
+// checkOutput validates the output of query_logs, select only projecting
+// full entries.
+func checkOutput(output string, selected []string) error {
+	switch output {
+	case outputFull:
+		return nil
+	case outputMinimal, outputRaw:
+		if len(selected) > 0 {
+			return fmt.Errorf("select only applies to output=%s, not %s", outputFull, output)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid output %q: expected %s, %s or %s", output, outputFull, outputMinimal, outputRaw)
+	}
+}
+
+// formatEntries shapes the entries returned by query_logs for output.
+func formatEntries(entries []client.LogEntry, output string, selected []string) any {
+	switch output {
+	case outputMinimal:
+		out := make([]minimalLogEntry, len(entries))
+		for i, entry := range entries {
+			out[i] = minimalLogEntry{Timestamp: entry.Timestamp, Level: entry.Level, Message: entry.Message}
+		}
+		return out
+	case outputRaw:
+		// The message is the line received, unless JSON extraction parsed it
+		out := make([]string, len(entries))
+		for i, entry := range entries {
+			out[i] = entry.Message
+		}
+		return out
+	}
+	if len(selected) > 0 {
+		return projectEntries(entries, selected)
+	}
+	return withEntryIDs(entries)
+}
+
+// progressInterval is the minimum delay between two progress notifications
+// of a tool call.
+var progressInterval = 500 * time.Millisecond
+
+// progressReporter sends MCP progress notifications for one tool call. It is
+// nil when the client did not send a progress token, and a nil reporter
+// ignores every call.
+type progressReporter struct {
+	send     func(params map[string]any) error
+	token    mcp.ProgressToken
+	total    float64
+	interval time.Duration
+	now      func() time.Time
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// newProgressReporter returns the reporter of request, or nil when the client
+// did not ask for progress notifications.
+func newProgressReporter(ctx context.Context, request mcp.CallToolRequest) *progressReporter {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return nil
+	}
+	return &progressReporter{
+		send: func(params map[string]any) error {
+			return srv.SendNotificationToClient(ctx, "notifications/progress", params)
+		},
+		token:    request.Params.Meta.ProgressToken,
+		interval: progressInterval,
+		now:      time.Now,
+	}
+}
+
+// Report sends progress out of the expected total, skipping it when the
+// previous notification was sent less than the interval ago.
+func (p *progressReporter) Report(progress float64, message string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	now := p.now()
+	if !p.last.IsZero() && now.Sub(p.last) < p.interval {
+		p.mu.Unlock()
+		return
+	}
+	p.last = now
+	p.mu.Unlock()
+	p.notify(progress, p.total, message)
+}
+
+// Done always sends the final notification, with progress equal to total.
+func (p *progressReporter) Done(progress float64, message string) {
+	if p == nil {
+		return
+	}
+	p.notify(progress, progress, message)
+}
+
+func (p *progressReporter) notify(progress, total float64, message string) {
+	params := map[string]any{
+		"progressToken": p.token,
+		"progress":      progress,
+		"message":       message,
 	}
-	b, mErr := json.Marshal(payload)
-	if mErr != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal error payload: %v", mErr))
+	if total > 0 {
+		params["total"] = total
 	}
-	return mcp.NewToolResultText(string(b))
+	if err := p.send(params); err != nil {
+		log.Printf("failed to send progress notification: %v", err)
+	}
+}
+
+// regexError is a regex operand that does not compile.
+type regexError struct {
+	Field   string
+	Pattern string
+	Err     error
+}
+
+func (e *regexError) Error() string {
+	return fmt.Sprintf("invalid regex for field %q: %v", e.Field, e.Err)
+}
+
+// checkRegexFilters compiles the regex operands of f with Go's regexp and
+// returns the first that fails as a *regexError. Patterns nesting a quantifier
+// in another, which backtracking engines like Splunk's may take exponential
+// time on, give warnings instead: Go's dialect is only an approximation of the
+// backends', so valid patterns are never rejected for their shape.
+func checkRegexFilters(f *client.Filter) ([]string, error) {
+	if f == nil {
+		return nil, nil
+	}
+	if f.Logic != "" {
+		var warnings []string
+		for i := range f.Filters {
+			w, err := checkRegexFilters(&f.Filters[i])
+			if err != nil {
+				return nil, err
+			}
+			warnings = append(warnings, w...)
+		}
+		return warnings, nil
+	}
+	if f.Op != operator.Regex {
+		return nil, nil
+	}
+
+	re, err := syntax.Parse(f.Value, syntax.Perl)
+	if err != nil {
+		return nil, &regexError{Field: f.Field, Pattern: f.Value, Err: err}
+	}
+	if hasNestedQuantifier(re, false) {
+		return []string{fmt.Sprintf("regex %q for field %q nests quantifiers and may be slow on backtracking regex engines", f.Value, f.Field)}, nil
+	}
+	return nil, nil
+}
+
+// hasNestedQuantifier reports whether re repeats without bound a
+// sub-expression that is itself repeated without bound, as in (a+)+ or
+// (x*y)*; inRepeat is set under such a repeat. Bounded repeats like {1,3} are
+// not counted.
+func hasNestedQuantifier(re *syntax.Regexp, inRepeat bool) bool {
+	repeat := false
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		repeat = true
+	case syntax.OpRepeat:
+		repeat = re.Max == -1
+	}
+	if repeat && inRepeat {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasNestedQuantifier(sub, inRepeat || repeat) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidationError reports whether err comes from invalid arguments or
+// filters rather than from the backend.
+func isValidationError(err error) bool {
+	return errors.As(err, new(*regexError)) || errors.As(err, new(*client.FilterError)) || errors.As(err, new(*client.RangeError))
+}
+
+// handleValidationError builds the structured error returned for invalid
+// tool arguments.
+func handleValidationError(err error) *mcp.CallToolResult {
+	extra := map[string]any{}
+	var reErr *regexError
+	if errors.As(err, &reErr) {
+		extra["field"] = reErr.Field
+		extra["pattern"] = reErr.Pattern
+		extra["hint"] = "Fix the regex (checked with Go regexp syntax) or pass a different variable value, then call the tool again."
+	}
+	var filterErr *client.FilterError
+	if errors.As(err, &filterErr) {
+		extra["path"] = filterErr.Path
+		extra["hint"] = "Fix the filter node at path (every condition needs a field, a known op and a value), then call the tool again."
+	}
+	var rangeErr *client.RangeError
+	if errors.As(err, &rangeErr) {
+		extra["hint"] = "Pass either last or start_time, with start_time before end_time, then call the tool again."
+	}
+	return mcpError(codeValidationError, err.Error(), extra)
+}
+
+// parseTimeRangeArgs reads the last, start_time and end_time arguments into r
+// and checks they are consistent.
+func parseTimeRangeArgs(request mcp.CallToolRequest, r *client.SearchRange) error {
+	if last, err := request.RequireString("last"); err == nil && last != "" {
+		r.Last.S(last)
+	}
+	if startTime, err := request.RequireString("start_time"); err == nil && startTime != "" {
+		r.Gte.S(startTime)
+	}
+	if endTime, err := request.RequireString("end_time"); err == nil && endTime != "" {
+		r.Lte.S(endTime)
+	}
+	return r.Validate()
+}
+
+// handleBackendUnavailable builds the structured error returned when a
+// backend kept throttling a query after its retries.
+func handleBackendUnavailable(contextID string, err error) *mcp.CallToolResult {
+	return mcpError(codeBackendUnavailable, err.Error(), map[string]any{
+		"contextID": contextID,
+		"hint":      "The backend is rate limiting queries. Narrow the query (shorter 'last', more filters, smaller size) or wait before calling the tool again.",
+	})
+}
+
+// handleEntryNotFound builds the structured error returned by get_entry.
+func handleEntryNotFound(contextID, id string, scanned int) *mcp.CallToolResult {
+	return mcpError(codeEntryNotFound, fmt.Sprintf("entry %s not found in context %s", id, contextID), map[string]any{
+		"contextID": contextID,
+		"id":        id,
+		"scanned":   scanned,
+		"hint":      "The entry may have aged out or the id comes from another context; run query_logs again for fresh ids.",
+	})
 }
 
 // suggestSimilar returns up to maxCount suggestions ranked by simple edit distance (Levenshtein) and substring match boost.
@@ -1110,6 +2613,9 @@ func generatePromptContent(
 			if def.Default != nil {
 				defaultVal = fmt.Sprintf(" (default: %v)", def.Default)
 			}
+			if len(def.AllowedValues) > 0 {
+				defaultVal += fmt.Sprintf(" (one of: %s)", strings.Join(def.AllowedValues, ", "))
+			}
 			sb.WriteString(fmt.Sprintf("- `%s`%s: %s%s\n", name, required, def.Description, defaultVal))
 		}
 		sb.WriteString("\n")
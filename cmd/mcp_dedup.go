@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+)
+
+// defaultQueryDedupWindow is how long a query_logs result answers the same
+// request again, unless --dedup-window changes it
+const defaultQueryDedupWindow = 2 * time.Second
+
+// queryDedup answers a query_logs request repeated within window with the
+// result of the first one, so an agent looping on the same call does not hit
+// the backend each time. Identical requests arriving while the first runs
+// wait for its result. An entry belongs to the configuration it was made
+// with, so a reload of the config starts over.
+type queryDedup struct {
+	mu      sync.Mutex
+	window  time.Duration
+	now     func() time.Time
+	entries map[string]*dedupQuery
+}
+
+// dedupQuery is one request, done once its result is known. A nil response
+// means the request failed and is not reused.
+type dedupQuery struct {
+	cfg      *config.ContextConfig
+	done     chan struct{}
+	response map[string]any
+	at       time.Time
+}
+
+func newQueryDedup(window time.Duration) *queryDedup {
+	return &queryDedup{window: window, now: time.Now, entries: make(map[string]*dedupQuery)}
+}
+
+// dedupKey normalizes the arguments of a request, the JSON encoding sorting
+// the keys of objects.
+func dedupKey(args map[string]any) (string, bool) {
+	b, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// lookup returns the response of the same request made within the window,
+// waiting for it when it still runs. Otherwise the request is registered and
+// done must be called with its response, nil when it failed.
+func (d *queryDedup) lookup(ctx context.Context, cfg *config.ContextConfig, key string) (map[string]any, bool, func(map[string]any)) {
+	noop := func(map[string]any) {}
+	if d.window <= 0 {
+		return nil, false, noop
+	}
+
+	d.mu.Lock()
+	now := d.now()
+	for k, q := range d.entries {
+		if q.cfg != cfg || (isDone(q) && (q.response == nil || now.Sub(q.at) >= d.window)) {
+			delete(d.entries, k)
+		}
+	}
+	if q, ok := d.entries[key]; ok {
+		d.mu.Unlock()
+		select {
+		case <-q.done:
+		case <-ctx.Done():
+			return nil, false, noop
+		}
+		if q.response != nil {
+			return q.response, true, noop
+		}
+		return nil, false, noop
+	}
+	q := &dedupQuery{cfg: cfg, done: make(chan struct{})}
+	d.entries[key] = q
+	d.mu.Unlock()
+
+	return nil, false, func(response map[string]any) {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		q.response = response
+		q.at = d.now()
+		close(q.done)
+		if response == nil && d.entries[key] == q {
+			delete(d.entries, key)
+		}
+	}
+}
+
+func isDone(q *dedupQuery) bool {
+	select {
+	case <-q.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// cachedResponse returns response with meta.cached set, leaving the shared
+// response unchanged.
+func cachedResponse(response map[string]any) map[string]any {
+	meta := map[string]any{}
+	if m, ok := response["meta"].(map[string]any); ok {
+		for k, v := range m {
+			meta[k] = v
+		}
+	}
+	meta["cached"] = true
+	copied := make(map[string]any, len(response))
+	for k, v := range response {
+		copied[k] = v
+	}
+	copied["meta"] = meta
+	return copied
+}
@@ -3,12 +3,24 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
 	"github.com/bascanada/logviewer/pkg/ty"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 )
 
 func TestMCP_ListContexts(t *testing.T) {
@@ -57,3 +69,1307 @@ func TestMCP_ListContexts(t *testing.T) {
 		t.Fatalf("expected 'alpha' in context list: %v", list)
 	}
 }
+
+func TestMCP_GetEntry(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	lines := `{"@timestamp":"2024-05-01T10:30:00.123Z","level":"INFO","message":"started"}
+{"@timestamp":"2024-05-01T10:30:01.456Z","level":"ERROR","message":"payment failed","order":"A-1"}
+`
+	if err := os.WriteFile(logFile, []byte(lines), 0600); err != nil {
+		t.Fatalf("write log file: %v", err)
+	}
+
+	search := client.LogSearch{Options: ty.MI{"cmd": "cat " + logFile}}
+	search.FieldExtraction.JSON.S(true)
+	search.FieldExtraction.JSONTimestampKey.S("@timestamp")
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cfg.Contexts["app"] = config.SearchContext{Client: "local", Search: search}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	callResult := func(tool string, args map[string]any) (map[string]any, bool) {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := bundle.ToolHandlers[tool](context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s error: %v", tool, err)
+		}
+		tc, ok := res.Content[0].(mcp.TextContent)
+		if !ok {
+			t.Fatalf("%s: unexpected content %+v", tool, res.Content)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal([]byte(tc.Text), &payload); err != nil {
+			t.Fatalf("%s: invalid JSON %v raw=%s", tool, err, tc.Text)
+		}
+		return payload, res.IsError
+	}
+	call := func(tool string, args map[string]any) map[string]any {
+		t.Helper()
+		payload, isErr := callResult(tool, args)
+		if isErr {
+			t.Fatalf("%s failed: %v", tool, payload)
+		}
+		return payload
+	}
+
+	queried := call("query_logs", map[string]any{"contextID": "app"})
+	entries, _ := queried["entries"].([]any)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %v", queried)
+	}
+	var id string
+	for _, e := range entries {
+		if m := e.(map[string]any); m["message"] == "payment failed" {
+			id, _ = m["id"].(string)
+		}
+	}
+	if id == "" {
+		t.Fatalf("query_logs entries have no id: %v", entries)
+	}
+
+	got := call("get_entry", map[string]any{"contextID": "app", "id": id})
+	entry, _ := got["entry"].(map[string]any)
+	if entry["id"] != id || entry["message"] != "payment failed" {
+		t.Fatalf("unexpected entry: %v", got)
+	}
+	if fields, _ := entry["fields"].(map[string]any); fields["order"] != "A-1" {
+		t.Fatalf("expected all fields, got %v", entry["fields"])
+	}
+
+	missing, isErr := callResult("get_entry", map[string]any{"contextID": "app", "id": strings.Split(id, "-")[0] + "-000000000000"})
+	if !isErr || missing["code"] != "ENTRY_NOT_FOUND" {
+		t.Fatalf("expected ENTRY_NOT_FOUND, got %v", missing)
+	}
+}
+
+func TestMCP_QueryLogsSelect(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	lines := `{"@timestamp":"2024-05-01T10:30:01Z","level":"ERROR","message":"payment failed","order":"A-1","stack":"long trace"}
+`
+	if err := os.WriteFile(logFile, []byte(lines), 0600); err != nil {
+		t.Fatalf("write log file: %v", err)
+	}
+
+	search := client.LogSearch{Options: ty.MI{"cmd": "cat " + logFile}}
+	search.FieldExtraction.JSON.S(true)
+	search.FieldExtraction.JSONTimestampKey.S("@timestamp")
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cfg.Contexts["app"] = config.SearchContext{Client: "local", Search: search}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	query := func(selected []any) map[string]any {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"contextID": "app", "select": selected}
+		res, err := bundle.ToolHandlers["query_logs"](context.Background(), req)
+		if err != nil || res.IsError {
+			t.Fatalf("query_logs failed: %v %+v", err, res)
+		}
+		var payload struct {
+			Entries []map[string]any `json:"entries"`
+		}
+		if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &payload); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(payload.Entries) != 1 {
+			t.Fatalf("expected 1 entry, got %v", payload.Entries)
+		}
+		return payload.Entries[0]
+	}
+
+	entry := query([]any{"message", "order", "missing"})
+	keys := make([]string, 0, len(entry))
+	for k := range entry {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if want := []string{"id", "level", "message", "missing", "order", "timestamp"}; !slices.Equal(keys, want) {
+		t.Fatalf("expected keys %v, got %v", want, keys)
+	}
+	if entry["order"] != "A-1" || entry["missing"] != nil || entry["level"] != "ERROR" {
+		t.Fatalf("unexpected projection: %v", entry)
+	}
+
+	entry = query([]any{"order", "-level", "-id"})
+	if _, ok := entry["level"]; ok {
+		t.Fatalf("level should be excluded: %v", entry)
+	}
+	if _, ok := entry["id"]; ok {
+		t.Fatalf("id should be excluded: %v", entry)
+	}
+	if entry["timestamp"] != "2024-05-01T10:30:01Z" || entry["order"] != "A-1" {
+		t.Fatalf("unexpected projection: %v", entry)
+	}
+}
+
+func TestMCP_QueryLogsSignature(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	lines := `{"@timestamp":"2024-05-01T10:30:01Z","level":"ERROR","message":"payment 42 failed for order A-1"}
+`
+	if err := os.WriteFile(logFile, []byte(lines), 0600); err != nil {
+		t.Fatalf("write log file: %v", err)
+	}
+
+	search := client.LogSearch{Options: ty.MI{"cmd": "cat " + logFile}}
+	search.FieldExtraction.JSON.S(true)
+	search.FieldExtraction.JSONTimestampKey.S("@timestamp")
+	search.FieldExtraction.Signature.S(client.SignatureBasic)
+	search.FieldExtraction.MaxMessageLength.S(10)
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cfg.Contexts["app"] = config.SearchContext{Client: "local", Search: search}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"contextID": "app"}
+	res, err := bundle.ToolHandlers["query_logs"](context.Background(), req)
+	if err != nil || res.IsError {
+		t.Fatalf("query_logs failed: %v %+v", err, res)
+	}
+	var payload struct {
+		Entries []struct {
+			Message string         `json:"message"`
+			Fields  map[string]any `json:"fields"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(payload.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %v", payload.Entries)
+	}
+	entry := payload.Entries[0]
+	if got := entry.Fields[client.SignatureField]; got != "payment <num> failed for order A-<num>" {
+		t.Fatalf("expected the signature of the full message, got %v", got)
+	}
+	if entry.Message != "payment 42…(truncated 21 chars)" {
+		t.Fatalf("expected a truncated message, got %q", entry.Message)
+	}
+}
+
+// notifiedSession is an initialized client session keeping the notifications
+// sent to it.
+type notifiedSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *notifiedSession) Initialize()       {}
+func (s *notifiedSession) Initialized() bool { return true }
+func (s *notifiedSession) SessionID() string { return "test" }
+func (s *notifiedSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+var _ server.ClientSession = (*notifiedSession)(nil)
+
+func TestMCP_QueryLogsProgress(t *testing.T) {
+	defer func(interval time.Duration) { progressInterval = interval }(progressInterval)
+	progressInterval = 0
+
+	// A local command is a stream, whose entries are not paged
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cfg.Contexts["app"] = config.SearchContext{Client: "local", Search: client.LogSearch{Options: ty.MI{"cmd": "seq 1 250"}}}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	session := &notifiedSession{notifications: make(chan mcp.JSONRPCNotification, 100)}
+	ctx := bundle.Server.WithContext(context.Background(), session)
+	msg, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": map[string]any{
+		"name":      "query_logs",
+		"arguments": map[string]any{"contextID": "app", "size": 1000},
+		"_meta":     map[string]any{"progressToken": "tok"},
+	}})
+	if res, ok := bundle.Server.HandleMessage(ctx, msg).(mcp.JSONRPCResponse); !ok {
+		t.Fatalf("query_logs failed: %+v", res)
+	}
+	close(session.notifications)
+
+	var progress []any
+	for n := range session.notifications {
+		if n.Method == "notifications/progress" {
+			progress = append(progress, n.Params.AdditionalFields["progress"])
+		}
+	}
+	// Unthrottled: every batch read is reported, then the final count
+	if want := []any{float64(0), float64(100), float64(200), float64(250), float64(250)}; !slices.Equal(progress, want) {
+		t.Fatalf("expected progress %v, got %v", want, progress)
+	}
+}
+
+func TestMCP_GetFieldValuesWithCounts(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	lines := `{"@timestamp":"2024-05-01T10:30:00Z","level":"INFO","message":"a"}
+{"@timestamp":"2024-05-01T10:30:01Z","level":"ERROR","message":"b"}
+{"@timestamp":"2024-05-01T10:30:02Z","level":"INFO","message":"c"}
+{"@timestamp":"2024-05-01T10:30:03Z","level":"DEBUG","message":"d"}
+`
+	if err := os.WriteFile(logFile, []byte(lines), 0600); err != nil {
+		t.Fatalf("write log file: %v", err)
+	}
+
+	search := client.LogSearch{Options: ty.MI{"cmd": "cat " + logFile}}
+	search.FieldExtraction.JSON.S(true)
+	search.FieldExtraction.JSONTimestampKey.S("@timestamp")
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cfg.Contexts["app"] = config.SearchContext{Client: "local", Search: search}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	call := func(args map[string]any) (string, bool) {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := bundle.ToolHandlers["get_field_values"](context.Background(), req)
+		if err != nil {
+			t.Fatalf("get_field_values error: %v", err)
+		}
+		tc, _ := res.Content[0].(mcp.TextContent)
+		return tc.Text, res.IsError
+	}
+
+	text, isErr := call(map[string]any{"contextID": "app", "fields": []any{"level"}, "withCounts": true})
+	if isErr {
+		t.Fatalf("get_field_values failed: %s", text)
+	}
+	want := `{"level":{"values":[{"value":"INFO","count":2},{"value":"DEBUG","count":1},{"value":"ERROR","count":1}]}}`
+	if text != want {
+		t.Fatalf("unexpected counts:\n got %s\nwant %s", text, want)
+	}
+
+	text, _ = call(map[string]any{"contextID": "app", "fields": []any{"level"}, "withCounts": true, "sort": "alpha"})
+	if !strings.Contains(text, `[{"value":"DEBUG","count":1},{"value":"ERROR","count":1},{"value":"INFO","count":2}]`) {
+		t.Fatalf("expected alphabetical order, got %s", text)
+	}
+
+	if text, isErr = call(map[string]any{"contextID": "app", "fields": []any{"level"}, "sort": "size"}); !isErr {
+		t.Fatalf("expected invalid sort error, got %s", text)
+	}
+}
+
+func TestMCP_SummarizeLogs(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	lines := `{"@timestamp":"2024-05-01T10:30:00Z","level":"INFO","message":"started","service":"api"}
+{"@timestamp":"2024-05-01T10:30:01Z","level":"ERROR","message":"timeout after 3000 ms","service":"api"}
+{"@timestamp":"2024-05-01T10:30:02Z","level":"ERROR","message":"disk full","service":"db"}
+{"@timestamp":"2024-05-01T10:30:03Z","level":"ERROR","message":"timeout after 250 ms","service":"api"}
+`
+	if err := os.WriteFile(logFile, []byte(lines), 0600); err != nil {
+		t.Fatalf("write log file: %v", err)
+	}
+
+	search := client.LogSearch{Options: ty.MI{"cmd": "cat " + logFile}}
+	search.FieldExtraction.JSON.S(true)
+	search.FieldExtraction.JSONTimestampKey.S("@timestamp")
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cfg.Contexts["app"] = config.SearchContext{Client: "local", Search: search}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	call := func(args map[string]any) (string, bool) {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := bundle.ToolHandlers["summarize_logs"](context.Background(), req)
+		if err != nil {
+			t.Fatalf("summarize_logs error: %v", err)
+		}
+		tc, _ := res.Content[0].(mcp.TextContent)
+		return tc.Text, res.IsError
+	}
+
+	text, isErr := call(map[string]any{"contextID": "app", "topN": 1})
+	if isErr {
+		t.Fatalf("summarize_logs failed: %s", text)
+	}
+	var summary struct {
+		Scanned    int  `json:"scanned"`
+		Matched    int  `json:"matched"`
+		Distinct   int  `json:"distinct"`
+		Truncated  bool `json:"truncated"`
+		Signatures []struct {
+			Signature string `json:"signature"`
+			Count     int    `json:"count"`
+			Example   string `json:"example"`
+			FirstSeen string `json:"firstSeen"`
+			LastSeen  string `json:"lastSeen"`
+		} `json:"signatures"`
+	}
+	if err := json.Unmarshal([]byte(text), &summary); err != nil {
+		t.Fatalf("unmarshal summary: %v (%s)", err, text)
+	}
+	if summary.Scanned != 4 || summary.Matched != 3 || summary.Distinct != 2 || !summary.Truncated || len(summary.Signatures) != 1 {
+		t.Fatalf("unexpected summary: %s", text)
+	}
+	top := summary.Signatures[0]
+	if top.Signature != "timeout after <num> ms" || top.Count != 2 || top.Example != "timeout after 3000 ms" {
+		t.Fatalf("unexpected top signature: %+v", top)
+	}
+	if top.FirstSeen != "2024-05-01T10:30:01Z" || top.LastSeen != "2024-05-01T10:30:03Z" {
+		t.Fatalf("unexpected first/last seen: %+v", top)
+	}
+
+	text, isErr = call(map[string]any{"contextID": "app", "groupBy": "service"})
+	if isErr {
+		t.Fatalf("summarize_logs with groupBy failed: %s", text)
+	}
+	var grouped struct {
+		GroupBy string `json:"groupBy"`
+		Groups  []struct {
+			Value      string `json:"value"`
+			Count      int    `json:"count"`
+			Matched    int    `json:"matched"`
+			Signatures []struct {
+				Signature string `json:"signature"`
+				Count     int    `json:"count"`
+			} `json:"signatures"`
+		} `json:"groups"`
+	}
+	if err := json.Unmarshal([]byte(text), &grouped); err != nil {
+		t.Fatalf("unmarshal grouped summary: %v (%s)", err, text)
+	}
+	if grouped.GroupBy != "service" || len(grouped.Groups) != 2 {
+		t.Fatalf("unexpected groups: %s", text)
+	}
+	api := grouped.Groups[0]
+	if api.Value != "api" || api.Count != 3 || api.Matched != 2 || len(api.Signatures) != 1 || api.Signatures[0].Count != 2 {
+		t.Fatalf("unexpected api group: %+v", api)
+	}
+	if db := grouped.Groups[1]; db.Value != "db" || db.Count != 1 || db.Signatures[0].Signature != "disk full" {
+		t.Fatalf("unexpected db group: %+v", db)
+	}
+
+	if text, isErr = call(map[string]any{"contextID": "app", "normalization": "fuzzy"}); !isErr {
+		t.Fatalf("expected invalid normalization error, got %s", text)
+	}
+	if text, _ = call(map[string]any{"contextID": "missing"}); !strings.Contains(text, "CONTEXT_NOT_FOUND") {
+		t.Fatalf("expected context not found, got %s", text)
+	}
+}
+
+func TestMCP_PingContext(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(logFile, []byte("hello\n"), 0600); err != nil {
+		t.Fatalf("write log file: %v", err)
+	}
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cfg.Contexts["app"] = config.SearchContext{Client: "local", Search: client.LogSearch{Options: ty.MI{"cmd": "cat " + logFile}}}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	call := func(args map[string]any) map[string]any {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := bundle.ToolHandlers["ping_context"](context.Background(), req)
+		if err != nil {
+			t.Fatalf("ping_context error: %v", err)
+		}
+		tc, _ := res.Content[0].(mcp.TextContent)
+		var payload map[string]any
+		if err := json.Unmarshal([]byte(tc.Text), &payload); err != nil {
+			t.Fatalf("unexpected ping_context payload %q: %v", tc.Text, err)
+		}
+		return payload
+	}
+
+	payload := call(map[string]any{"contextID": "app"})
+	if payload["status"] != "ok" || payload["backendType"] != "local" {
+		t.Fatalf("expected a healthy local backend, got %v", payload)
+	}
+	if _, ok := payload["latencyMs"]; !ok {
+		t.Fatalf("expected latencyMs in %v", payload)
+	}
+
+	payload = call(map[string]any{"contextID": "ap"})
+	if payload["code"] != "CONTEXT_NOT_FOUND" {
+		t.Fatalf("expected CONTEXT_NOT_FOUND, got %v", payload)
+	}
+}
+
+func TestMCP_QueryLogsRegexValidation(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(logFile, []byte("aaa\n"), 0600); err != nil {
+		t.Fatalf("write log file: %v", err)
+	}
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cfg.Contexts["app"] = config.SearchContext{Client: "local", Search: client.LogSearch{
+		Options:         ty.MI{"cmd": "cat " + logFile},
+		Fields:          ty.MS{"_": "${PATTERN}"},
+		FieldsCondition: ty.MS{"_": operator.Regex},
+	}}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	call := func(pattern string) map[string]any {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"contextID": "app", "variables": map[string]any{"PATTERN": pattern}}
+		res, err := bundle.ToolHandlers["query_logs"](context.Background(), req)
+		if err != nil {
+			t.Fatalf("query_logs error: %v", err)
+		}
+		tc, _ := res.Content[0].(mcp.TextContent)
+		var payload map[string]any
+		if err := json.Unmarshal([]byte(tc.Text), &payload); err != nil {
+			t.Fatalf("unexpected query_logs payload %q: %v", tc.Text, err)
+		}
+		return payload
+	}
+
+	payload := call("(a+")
+	if payload["code"] != "VALIDATION_ERROR" || payload["pattern"] != "(a+" {
+		t.Fatalf("expected a VALIDATION_ERROR for the pattern, got %v", payload)
+	}
+
+	payload = call("(a+)+")
+	meta, _ := payload["meta"].(map[string]any)
+	warnings, _ := meta["warnings"].([]any)
+	if len(warnings) != 1 || meta["resultCount"] != float64(1) {
+		t.Fatalf("expected one entry and a nested quantifier warning, got %v", payload)
+	}
+}
+
+func TestMCP_QueryLogsFilterValidation(t *testing.T) {
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cfg.Contexts["app"] = config.SearchContext{Client: "local", Search: client.LogSearch{
+		Options: ty.MI{"cmd": "echo hello"},
+		Filter: &client.Filter{Logic: client.LogicAnd, Filters: []client.Filter{
+			{Field: "level", Value: "ERROR"},
+			{Field: "status", Op: "above", Value: "500"},
+		}},
+	}}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"contextID": "app"}
+	res, err := bundle.ToolHandlers["query_logs"](context.Background(), req)
+	if err != nil {
+		t.Fatalf("query_logs error: %v", err)
+	}
+	tc, _ := res.Content[0].(mcp.TextContent)
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(tc.Text), &payload); err != nil {
+		t.Fatalf("unexpected query_logs payload %q: %v", tc.Text, err)
+	}
+	if payload["code"] != "VALIDATION_ERROR" || payload["path"] != "filters[1]" {
+		t.Fatalf("expected a VALIDATION_ERROR at filters[1], got %v", payload)
+	}
+}
+
+func TestMCP_InvertedTimeRange(t *testing.T) {
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cfg.Contexts["app"] = config.SearchContext{Client: "local", Search: client.LogSearch{Options: ty.MI{"cmd": "echo hello"}}}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	for _, tool := range []string{"query_logs", "summarize_logs"} {
+		for name, args := range map[string]map[string]any{
+			"inverted":      {"start_time": "2024-01-02T00:00:00Z", "end_time": "2024-01-01T00:00:00Z"},
+			"last and from": {"last": "1h", "start_time": "2024-01-01T00:00:00Z"},
+		} {
+			args["contextID"] = "app"
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = args
+			res, err := bundle.ToolHandlers[tool](context.Background(), req)
+			if err != nil {
+				t.Fatalf("%s error: %v", tool, err)
+			}
+			tc, _ := res.Content[0].(mcp.TextContent)
+			var payload map[string]any
+			if err := json.Unmarshal([]byte(tc.Text), &payload); err != nil {
+				t.Fatalf("%s %s: unexpected payload %q: %v", tool, name, tc.Text, err)
+			}
+			if payload["code"] != "VALIDATION_ERROR" || payload["hint"] == nil {
+				t.Fatalf("%s %s: expected a VALIDATION_ERROR with a hint, got %v", tool, name, payload)
+			}
+		}
+	}
+}
+
+func TestMCP_RestrictTools(t *testing.T) {
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	build := func() *MCPServerBundle {
+		t.Helper()
+		bundle, err := buildMCPServerWithManager(cm)
+		if err != nil {
+			t.Fatalf("build error: %v", err)
+		}
+		return bundle
+	}
+	listed := func(bundle *MCPServerBundle) []string {
+		var names []string
+		for name := range bundle.Server.ListTools() {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	bundle := build()
+	all := listed(bundle)
+	if err := bundle.RestrictTools(nil, []string{"reload_config"}); err != nil {
+		t.Fatalf("restrict error: %v", err)
+	}
+	if got := listed(bundle); len(got) != len(all)-1 || slices.Contains(got, "reload_config") {
+		t.Fatalf("expected every tool but reload_config, got %v", got)
+	}
+	if _, ok := bundle.ToolHandlers["reload_config"]; ok {
+		t.Fatalf("reload_config handler still registered")
+	}
+
+	bundle = build()
+	if err := bundle.RestrictTools([]string{"get_fields"}, nil); err != nil {
+		t.Fatalf("restrict error: %v", err)
+	}
+	if got := strings.Join(listed(bundle), ","); got != "get_fields,list_contexts,query_logs" {
+		t.Fatalf("expected get_fields and the core tools, got %s", got)
+	}
+
+	bundle = build()
+	if err := bundle.RestrictTools([]string{"get_fields"}, []string{"query_logs"}); err != nil {
+		t.Fatalf("restrict error: %v", err)
+	}
+	if got := strings.Join(listed(bundle), ","); got != "get_fields,list_contexts" {
+		t.Fatalf("expected core tools to be disabled explicitly, got %s", got)
+	}
+
+	bundle = build()
+	err = bundle.RestrictTools(nil, []string{"save_search"})
+	if err == nil || !strings.Contains(err.Error(), `unknown MCP tool "save_search"`) {
+		t.Fatalf("expected unknown tool error, got %v", err)
+	}
+	if got := listed(bundle); len(got) != len(all) {
+		t.Fatalf("tools changed after an error: %v", got)
+	}
+}
+
+func TestMCP_ContextResources(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	// Inside the field discovery window
+	now := time.Now().UTC().Format(time.RFC3339)
+	lines := `{"@timestamp":"` + now + `","level":"INFO","message":"a","order":"A-1"}
+{"@timestamp":"` + now + `","level":"ERROR","message":"b","region":"eu"}
+`
+	if err := os.WriteFile(logFile, []byte(lines), 0600); err != nil {
+		t.Fatalf("write log file: %v", err)
+	}
+
+	search := client.LogSearch{Options: ty.MI{"cmd": "cat " + logFile}}
+	search.FieldExtraction.JSON.S(true)
+	search.FieldExtraction.JSONTimestampKey.S("@timestamp")
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["local"] = config.Client{Type: "local", Options: ty.MI{}}
+	cfg.Contexts["app"] = config.SearchContext{Client: "local", Search: search}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	send := func(method string, params any) map[string]any {
+		t.Helper()
+		msg, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+		b, _ := json.Marshal(bundle.Server.HandleMessage(context.Background(), msg))
+		var res map[string]any
+		_ = json.Unmarshal(b, &res)
+		return res
+	}
+	read := func(uri string) (string, string) {
+		t.Helper()
+		res := send("resources/read", map[string]any{"uri": uri})
+		if e, ok := res["error"].(map[string]any); ok {
+			return "", e["message"].(string)
+		}
+		contents := res["result"].(map[string]any)["contents"].([]any)
+		return contents[0].(map[string]any)["text"].(string), ""
+	}
+
+	listed := send("resources/list", map[string]any{})
+	raw, _ := json.Marshal(listed)
+	for _, uri := range []string{"logviewer://contexts", "logviewer://context/app/fields", "logviewer://context/app/schema"} {
+		if !strings.Contains(string(raw), uri) {
+			t.Fatalf("expected %s in the resource list: %s", uri, raw)
+		}
+	}
+
+	text, errMsg := read("logviewer://context/app/fields")
+	if errMsg != "" {
+		t.Fatalf("fields resource failed: %s", errMsg)
+	}
+	var names []string
+	if err := json.Unmarshal([]byte(text), &names); err != nil || !slices.Contains(names, "order") || !slices.Contains(names, "region") {
+		t.Fatalf("unexpected fields: %s", text)
+	}
+
+	text, errMsg = read("logviewer://context/app/schema")
+	if errMsg != "" || !strings.Contains(text, `"cmd":"cat `) {
+		t.Fatalf("unexpected schema: %s %s", text, errMsg)
+	}
+
+	// A context added by a reload is read through the templates
+	reloaded := *cfg
+	reloaded.Contexts = config.Contexts{"app": cfg.Contexts["app"], "added": {Client: "local", Search: search, Description: "after reload"}}
+	next, err := NewConfigManagerForTest(&reloaded)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	cm.mu.Lock()
+	cm.currentCfg, cm.searchFactory = next.Get()
+	cm.mu.Unlock()
+	if text, errMsg = read("logviewer://context/added/schema"); errMsg != "" {
+		t.Fatalf("reloaded context resource failed: %s", errMsg)
+	}
+
+	if _, errMsg = read("logviewer://context/missing/fields"); !strings.Contains(errMsg, `context "missing" not found`) || !strings.Contains(errMsg, "added, app") {
+		t.Fatalf("expected a not found error with the contexts, got %q", errMsg)
+	}
+	if _, errMsg = read("logviewer://context/app/logs"); errMsg == "" {
+		t.Fatalf("expected an error for an unknown resource kind")
+	}
+}
+
+func TestFieldDiscoveryCache(t *testing.T) {
+	calls := 0
+	cfg := &config.ContextConfig{Contexts: config.Contexts{"app": {}}}
+	cm := &ConfigManager{currentCfg: cfg, searchFactory: &MockSearchFactory{
+		OnGetFieldValues: func(_ context.Context, _ string, _ client.LogSearch, _ []string) (map[string][]string, error) {
+			calls++
+			return map[string][]string{"level": {"INFO"}}, nil
+		},
+	}}
+	cache := newFieldDiscoveryCache()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	for range 2 {
+		if _, err := cache.fieldNames(context.Background(), cm, "app"); err != nil {
+			t.Fatalf("fieldNames: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected the second read from the cache, got %d searches", calls)
+	}
+
+	now = now.Add(fieldDiscoveryTTL)
+	_, _ = cache.fieldNames(context.Background(), cm, "app")
+	cm.currentCfg = &config.ContextConfig{Contexts: cfg.Contexts}
+	_, _ = cache.fieldNames(context.Background(), cm, "app")
+	if calls != 3 {
+		t.Fatalf("expected a search after the TTL and after a reload, got %d searches", calls)
+	}
+}
+
+func TestMCP_FacetFields(t *testing.T) {
+	var gotSearch client.LogSearch
+	var entries []client.LogEntry
+	for _, e := range []struct {
+		level, service string
+		n              int
+	}{{"INFO", "db", 3}, {"ERROR", "api", 5}} {
+		for range e.n {
+			entries = append(entries, client.LogEntry{Fields: ty.MI{"level": e.level, "service": e.service}})
+		}
+	}
+	cfg := &config.ContextConfig{Contexts: config.Contexts{"app": {}}}
+	cm := &ConfigManager{currentCfg: cfg, searchFactory: &MockSearchFactory{
+		OnGetSearchResult: func(_ context.Context, _ string, search client.LogSearch) (client.LogSearchResult, error) {
+			gotSearch = search
+			return &MockResult{Entries: entries}, nil
+		},
+	}}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	call := func(args map[string]any) (string, bool) {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := bundle.ToolHandlers["facet_fields"](context.Background(), req)
+		if err != nil {
+			t.Fatalf("facet_fields error: %v", err)
+		}
+		tc, _ := res.Content[0].(mcp.TextContent)
+		return tc.Text, res.IsError
+	}
+
+	text, isErr := call(map[string]any{"contextID": "app", "fields": []any{"level", "service"}, "filters": map[string]any{"env": "prod"}})
+	if isErr {
+		t.Fatalf("facet_fields failed: %s", text)
+	}
+	if gotSearch.Fields["env"] != "prod" || gotSearch.Size.Value != fieldCountsScanSize || gotSearch.Range.Last.Value != "15m" {
+		t.Fatalf("unexpected search: %+v", gotSearch)
+	}
+	var facets map[string]fieldValueCounts
+	if err := json.Unmarshal([]byte(text), &facets); err != nil {
+		t.Fatalf("unmarshal facets: %v (%s)", err, text)
+	}
+	if got := facets["level"].Values; len(got) != 2 || got[0] != (client.FieldCount{Value: "ERROR", Count: 5}) {
+		t.Fatalf("expected the most frequent level first, got %+v", got)
+	}
+	if got := facets["service"].Values; len(got) != 2 || got[0].Value != "api" {
+		t.Fatalf("expected the most frequent service first, got %+v", got)
+	}
+	if facets["level"].Approximate {
+		t.Fatalf("expected exact counts below the scan cap, got %s", text)
+	}
+
+	for len(entries) < fieldCountsScanSize {
+		entries = append(entries, client.LogEntry{Fields: ty.MI{"level": "DEBUG"}})
+	}
+	text, _ = call(map[string]any{"contextID": "app", "fields": []any{"level"}})
+	if err := json.Unmarshal([]byte(text), &facets); err != nil || !facets["level"].Approximate {
+		t.Fatalf("expected approximate counts at the scan cap, got %s", text)
+	}
+
+	if text, isErr = call(map[string]any{"contextID": "app"}); !isErr {
+		t.Fatalf("expected missing fields error, got %s", text)
+	}
+}
+
+func TestMCP_ErrorEnvelope(t *testing.T) {
+	cfg := &config.ContextConfig{Contexts: config.Contexts{"app": {}, "down": {}, "slow": {}, "vars": {}, "broken": {}}}
+	searchErr := func(contextID string) error {
+		switch contextID {
+		case "down":
+			return errors.New("connection refused")
+		case "slow":
+			return fmt.Errorf("search: %w", context.DeadlineExceeded)
+		}
+		return nil
+	}
+	cm := &ConfigManager{currentCfg: cfg, searchFactory: &MockSearchFactory{
+		OnGetSearchContext: func(_ context.Context, contextID string, search client.LogSearch) (*config.SearchContext, error) {
+			switch contextID {
+			case "missing":
+				return nil, fmt.Errorf("%w: %s", config.ErrContextNotFound, contextID)
+			case "broken":
+				return nil, errors.New("search \"base\" not found")
+			case "vars":
+				search.Variables = map[string]client.VariableDefinition{"tenant": {Description: "the tenant", Required: true}}
+			}
+			return &config.SearchContext{Search: search}, nil
+		},
+		OnGetSearchResult: func(_ context.Context, contextID string, search client.LogSearch) (client.LogSearchResult, error) {
+			if err := searchErr(contextID); err != nil {
+				return nil, err
+			}
+			return &MockResult{}, nil
+		},
+		OnGetFieldValues: func(_ context.Context, contextID string, _ client.LogSearch, _ []string) (map[string][]string, error) {
+			return nil, searchErr(contextID)
+		},
+	}}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		tool string
+		args map[string]any
+		code string
+	}{
+		{"query_logs without contextID", "query_logs", map[string]any{}, "VALIDATION_ERROR"},
+		{"query_logs unknown context", "query_logs", map[string]any{"contextID": "missing"}, "CONTEXT_NOT_FOUND"},
+		{"query_logs invalid range", "query_logs", map[string]any{"contextID": "app", "last": "1h", "start_time": "2024-05-01T10:00:00Z"}, "VALIDATION_ERROR"},
+		{"query_logs broken context", "query_logs", map[string]any{"contextID": "broken"}, "VALIDATION_ERROR"},
+		{"query_logs missing variable", "query_logs", map[string]any{"contextID": "vars"}, "VALIDATION_ERROR"},
+		{"query_logs backend failure", "query_logs", map[string]any{"contextID": "down"}, "BACKEND_UNAVAILABLE"},
+		{"query_logs backend timeout", "query_logs", map[string]any{"contextID": "slow"}, "TIMEOUT"},
+		{"get_fields without contextID", "get_fields", map[string]any{}, "VALIDATION_ERROR"},
+		{"get_fields backend failure", "get_fields", map[string]any{"contextID": "down"}, "BACKEND_UNAVAILABLE"},
+		{"get_field_values without fields", "get_field_values", map[string]any{"contextID": "app"}, "VALIDATION_ERROR"},
+		{"get_field_values invalid sort", "get_field_values", map[string]any{"contextID": "app", "fields": []any{"level"}, "sort": "size"}, "VALIDATION_ERROR"},
+		{"get_field_values unknown context", "get_field_values", map[string]any{"contextID": "missing", "fields": []any{"level"}}, "CONTEXT_NOT_FOUND"},
+		{"get_field_values backend failure", "get_field_values", map[string]any{"contextID": "down", "fields": []any{"level"}}, "BACKEND_UNAVAILABLE"},
+		{"get_context_details without contextID", "get_context_details", map[string]any{}, "VALIDATION_ERROR"},
+		{"get_context_details unknown context", "get_context_details", map[string]any{"contextID": "missing"}, "CONTEXT_NOT_FOUND"},
+		{"get_context_details broken context", "get_context_details", map[string]any{"contextID": "broken"}, "VALIDATION_ERROR"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tc.args
+			res, err := bundle.ToolHandlers[tc.tool](context.Background(), req)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.tool, err)
+			}
+			if !res.IsError {
+				t.Fatalf("expected an error result, got %+v", res.Content)
+			}
+			text, _ := res.Content[0].(mcp.TextContent)
+			var payload map[string]any
+			if err := json.Unmarshal([]byte(text.Text), &payload); err != nil {
+				t.Fatalf("expected a JSON envelope, got %q", text.Text)
+			}
+			if payload["code"] != tc.code || payload["error"] == "" {
+				t.Fatalf("expected code %s, got %v", tc.code, payload)
+			}
+		})
+	}
+}
+
+func TestMCP_CorrelateTrace(t *testing.T) {
+	at := func(sec int) time.Time { return time.Date(2024, 5, 1, 10, 30, sec, 0, time.UTC) }
+	byContext := map[string][]client.LogEntry{
+		"api":    {{Timestamp: at(0), Message: "GET /orders", ContextID: "api"}, {Timestamp: at(3), Message: "200 OK", ContextID: "api"}},
+		"worker": {{Timestamp: at(1), Message: "order job started", ContextID: "worker"}},
+	}
+	var mu sync.Mutex
+	var filters []ty.MS
+	cfg := &config.ContextConfig{Contexts: config.Contexts{"api": {}, "worker": {}, "db": {}}}
+	cm := &ConfigManager{currentCfg: cfg, searchFactory: &MockSearchFactory{
+		OnGetSearchResult: func(_ context.Context, contextID string, search client.LogSearch) (client.LogSearchResult, error) {
+			mu.Lock()
+			filters = append(filters, search.Fields)
+			mu.Unlock()
+			if contextID == "db" {
+				return nil, errors.New("connection refused")
+			}
+			return &MockResult{Entries: byContext[contextID]}, nil
+		},
+	}}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	call := func(args map[string]any) (string, bool) {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := bundle.ToolHandlers["correlate_trace"](context.Background(), req)
+		if err != nil {
+			t.Fatalf("correlate_trace error: %v", err)
+		}
+		tc, _ := res.Content[0].(mcp.TextContent)
+		return tc.Text, res.IsError
+	}
+
+	text, isErr := call(map[string]any{"field": "traceId", "value": "abc", "contextIDs": []any{"api", "worker", "db"}})
+	if isErr {
+		t.Fatalf("correlate_trace failed: %s", text)
+	}
+	var got struct {
+		Entries []struct {
+			Message   string `json:"message"`
+			ContextID string `json:"context_id"`
+		} `json:"entries"`
+		Counts map[string]int `json:"counts"`
+		Errors []struct {
+			ContextID string `json:"contextId"`
+			Error     string `json:"error"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(text), &got); err != nil {
+		t.Fatalf("unmarshal: %v (%s)", err, text)
+	}
+	var timeline []string
+	for _, e := range got.Entries {
+		timeline = append(timeline, e.ContextID+":"+e.Message)
+	}
+	if strings.Join(timeline, "|") != "api:GET /orders|worker:order job started|api:200 OK" {
+		t.Fatalf("unexpected timeline: %v", timeline)
+	}
+	if got.Counts["api"] != 2 || got.Counts["worker"] != 1 {
+		t.Fatalf("unexpected counts: %v", got.Counts)
+	}
+	if len(got.Errors) != 1 || got.Errors[0].ContextID != "db" || got.Errors[0].Error != "connection refused" {
+		t.Fatalf("expected the db failure in errors, got %+v", got.Errors)
+	}
+	for _, f := range filters {
+		if f["traceId"] != "abc" {
+			t.Fatalf("expected the trace filter on every context, got %v", filters)
+		}
+	}
+
+	if text, isErr = call(map[string]any{"field": "traceId", "value": "abc", "contextIDs": []any{"db"}}); !isErr || !strings.Contains(text, "BACKEND_UNAVAILABLE") {
+		t.Fatalf("expected an error when every context fails, got %s", text)
+	}
+	if text, _ = call(map[string]any{"field": "traceId", "value": "abc", "contextIDs": []any{"api", "apj"}}); !strings.Contains(text, "CONTEXT_NOT_FOUND") {
+		t.Fatalf("expected context not found, got %s", text)
+	}
+	if _, isErr = call(map[string]any{"field": "traceId", "value": "abc"}); !isErr {
+		t.Fatalf("expected an error without contextIDs")
+	}
+}
+
+func TestMCP_QueryLogsDedup(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	cfg := &config.ContextConfig{Contexts: config.Contexts{"app": {}}}
+	cm := &ConfigManager{currentCfg: cfg, searchFactory: &MockSearchFactory{
+		OnGetSearchContext: func(_ context.Context, _ string, search client.LogSearch) (*config.SearchContext, error) {
+			return &config.SearchContext{Search: search}, nil
+		},
+		OnGetSearchResult: func(_ context.Context, _ string, _ client.LogSearch) (client.LogSearchResult, error) {
+			calls.Add(1)
+			<-release
+			return &MockResult{Entries: []client.LogEntry{{Timestamp: time.Now(), Message: "hello"}}}, nil
+		},
+	}}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	query := func(args map[string]any) map[string]any {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := bundle.ToolHandlers["query_logs"](context.Background(), req)
+		if err != nil || res.IsError {
+			t.Errorf("query_logs failed: %v %+v", err, res)
+			return nil
+		}
+		var payload struct {
+			Meta map[string]any `json:"meta"`
+		}
+		if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &payload); err != nil {
+			t.Errorf("invalid JSON: %v", err)
+		}
+		return payload.Meta
+	}
+
+	// Identical calls in flight together share one backend query
+	args := map[string]any{"contextID": "app", "last": "1h", "fields": map[string]any{"level": "ERROR"}}
+	metas := make([]map[string]any, 4)
+	var wg sync.WaitGroup
+	for i := range metas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			metas[i] = query(map[string]any{"contextID": "app", "fields": map[string]any{"level": "ERROR"}, "last": "1h"})
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Fatalf("expected one backend query for concurrent duplicates, got %d", calls.Load())
+	}
+	cached := 0
+	for _, meta := range metas {
+		if meta["cached"] == true {
+			cached++
+		}
+	}
+	if cached != len(metas)-1 {
+		t.Fatalf("expected all but the first call cached, got %d of %d", cached, len(metas))
+	}
+
+	if meta := query(args); meta["cached"] != true || meta["resultCount"] != float64(1) {
+		t.Fatalf("expected a cached repeat, got %v", meta)
+	}
+	if meta := query(map[string]any{"contextID": "app", "last": "1h", "fields": map[string]any{"level": "ERROR"}, "pageToken": "2"}); meta["cached"] != nil {
+		t.Fatalf("another page must not be cached, got %v", meta)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected a backend query for the other page, got %d", calls.Load())
+	}
+}
+
+func TestMCP_QueryLogsOutput(t *testing.T) {
+	cfg := &config.ContextConfig{Contexts: config.Contexts{"app": {}}}
+	cm := &ConfigManager{currentCfg: cfg, searchFactory: &MockSearchFactory{
+		OnGetSearchContext: func(_ context.Context, _ string, search client.LogSearch) (*config.SearchContext, error) {
+			return &config.SearchContext{Search: search}, nil
+		},
+		OnGetSearchResult: func(_ context.Context, _ string, _ client.LogSearch) (client.LogSearchResult, error) {
+			return &MockResult{Entries: []client.LogEntry{{
+				Timestamp: time.Date(2024, 5, 1, 10, 30, 1, 0, time.UTC),
+				Level:     "ERROR",
+				Message:   "2024-05-01 ERROR payment failed order=A-1",
+				Fields:    ty.MI{"order": "A-1"},
+				ContextID: "app",
+			}}}, nil
+		},
+	}}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	query := func(args map[string]any) (json.RawMessage, string) {
+		t.Helper()
+		args["contextID"] = "app"
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := bundle.ToolHandlers["query_logs"](context.Background(), req)
+		if err != nil {
+			t.Fatalf("query_logs error: %v", err)
+		}
+		text := res.Content[0].(mcp.TextContent).Text
+		if res.IsError {
+			return nil, text
+		}
+		var payload struct {
+			Entries json.RawMessage `json:"entries"`
+		}
+		if err := json.Unmarshal([]byte(text), &payload); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return payload.Entries, ""
+	}
+	keysOf := func(raw json.RawMessage) []string {
+		t.Helper()
+		var entries []map[string]any
+		if err := json.Unmarshal(raw, &entries); err != nil || len(entries) != 1 {
+			t.Fatalf("expected one object entry, got %s (%v)", raw, err)
+		}
+		keys := make([]string, 0, len(entries[0]))
+		for k := range entries[0] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	for _, output := range []string{"", "full"} {
+		raw, errText := query(map[string]any{"output": output})
+		if errText != "" {
+			t.Fatalf("output %q failed: %s", output, errText)
+		}
+		if want := []string{"context_id", "fields", "id", "level", "message", "timestamp"}; !slices.Equal(keysOf(raw), want) {
+			t.Fatalf("output %q: expected keys %v, got %v", output, want, keysOf(raw))
+		}
+	}
+
+	raw, errText := query(map[string]any{"output": "minimal"})
+	if errText != "" {
+		t.Fatalf("minimal failed: %s", errText)
+	}
+	if want := []string{"level", "message", "timestamp"}; !slices.Equal(keysOf(raw), want) {
+		t.Fatalf("minimal: expected keys %v, got %v", want, keysOf(raw))
+	}
+
+	raw, errText = query(map[string]any{"output": "raw"})
+	if errText != "" {
+		t.Fatalf("raw failed: %s", errText)
+	}
+	var lines []string
+	if err := json.Unmarshal(raw, &lines); err != nil || !slices.Equal(lines, []string{"2024-05-01 ERROR payment failed order=A-1"}) {
+		t.Fatalf("raw: expected the log lines, got %s (%v)", raw, err)
+	}
+
+	if _, errText = query(map[string]any{"output": "pretty"}); !strings.Contains(errText, "VALIDATION_ERROR") || !strings.Contains(errText, `invalid output \"pretty\"`) {
+		t.Fatalf("expected a validation error for an unknown output, got %q", errText)
+	}
+	if _, errText = query(map[string]any{"output": "minimal", "select": []any{"order"}}); !strings.Contains(errText, "select only applies to output=full") {
+		t.Fatalf("expected select to be rejected with minimal, got %q", errText)
+	}
+}
+
+func TestMCP_SampleLogs(t *testing.T) {
+	var got client.LogSearch
+	cfg := &config.ContextConfig{Contexts: config.Contexts{"app": {}}}
+	cm := &ConfigManager{currentCfg: cfg, searchFactory: &MockSearchFactory{
+		OnGetSearchContext: func(_ context.Context, contextID string, search client.LogSearch) (*config.SearchContext, error) {
+			if _, ok := cfg.Contexts[contextID]; !ok {
+				return nil, fmt.Errorf("%w: %s", config.ErrContextNotFound, contextID)
+			}
+			return &config.SearchContext{Search: search}, nil
+		},
+		OnGetSearchResult: func(_ context.Context, _ string, search client.LogSearch) (client.LogSearchResult, error) {
+			got = search
+			return &MockResult{Entries: []client.LogEntry{{Timestamp: time.Now(), Message: "hello"}}}, nil
+		},
+	}}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	sample := func(args map[string]any) (map[string]any, string) {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		res, err := bundle.ToolHandlers["sample_logs"](context.Background(), req)
+		if err != nil {
+			t.Fatalf("sample_logs error: %v", err)
+		}
+		text := res.Content[0].(mcp.TextContent).Text
+		if res.IsError {
+			return nil, text
+		}
+		var payload struct {
+			Meta map[string]any `json:"meta"`
+		}
+		if err := json.Unmarshal([]byte(text), &payload); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return payload.Meta, ""
+	}
+
+	meta, errText := sample(map[string]any{"contextID": "app", "fields": map[string]any{"level": "ERROR"}, "pageToken": "x"})
+	if errText != "" {
+		t.Fatalf("sample_logs failed: %s", errText)
+	}
+	if meta["sampled"] != true || meta["resultCount"] != float64(1) {
+		t.Fatalf("expected a sampled result, got %v", meta)
+	}
+	if got.Size.Value != 5 || got.Range.Last.Value != "5m" || got.Fields["level"] != "ERROR" || got.PageToken.Set {
+		t.Fatalf("expected size 5 over the last 5m with the filter, got %+v", got)
+	}
+
+	if _, errText = sample(map[string]any{"contextID": "app", "size": 500, "last": "1h"}); errText != "" {
+		t.Fatalf("sample_logs failed: %s", errText)
+	}
+	if got.Size.Value != sampleMaxSize || got.Range.Last.Value != "1h" {
+		t.Fatalf("expected the size capped at %d over the last 1h, got %+v", sampleMaxSize, got)
+	}
+
+	if _, errText = sample(map[string]any{"contextID": "ap"}); !strings.Contains(errText, "CONTEXT_NOT_FOUND") || !strings.Contains(errText, `"app"`) {
+		t.Fatalf("expected context not found with suggestions, got %q", errText)
+	}
+}
+
+func TestMCP_ExposeContexts(t *testing.T) {
+	cfg := &config.ContextConfig{Clients: config.Clients{}, Searches: config.Searches{}, Contexts: config.Contexts{}}
+	cfg.Clients["dummy"] = config.Client{Type: "local", Options: ty.MI{}}
+	for _, id := range []string{"staging-api", "staging-billing", "prod-api"} {
+		cfg.Contexts[id] = config.SearchContext{Client: "dummy", Search: client.LogSearch{Options: ty.MI{"cmd": "echo hello"}}}
+	}
+	cfg.MCP = config.MCPConfig{ExposeContexts: []string{"staging-*"}, HideContexts: []string{"staging-billing"}}
+
+	cm, err := NewConfigManagerForTest(cfg)
+	if err != nil {
+		t.Fatalf("config manager error: %v", err)
+	}
+	bundle, err := buildMCPServerWithManager(cm)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	res, err := bundle.ToolHandlers["list_contexts"](context.Background(), mcp.CallToolRequest{})
+	if err != nil || res.IsError {
+		t.Fatalf("list_contexts failed: %v %+v", err, res)
+	}
+	var list []string
+	if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &list); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !slices.Equal(list, []string{"staging-api"}) {
+		t.Fatalf("expected only the exposed context, got %v", list)
+	}
+
+	query := func(contextID string) (string, bool) {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"contextID": contextID}
+		res, err := bundle.ToolHandlers["query_logs"](context.Background(), req)
+		if err != nil {
+			t.Fatalf("query_logs error: %v", err)
+		}
+		return res.Content[0].(mcp.TextContent).Text, res.IsError
+	}
+	if text, isErr := query("staging-api"); isErr {
+		t.Fatalf("query on an exposed context failed: %s", text)
+	}
+	for _, hidden := range []string{"prod-api", "staging-billing"} {
+		text, isErr := query(hidden)
+		if !isErr || !strings.Contains(text, "CONTEXT_NOT_FOUND") {
+			t.Fatalf("expected %s to be not found, got %s", hidden, text)
+		}
+		var envelope struct {
+			AvailableContexts []string `json:"availableContexts"`
+		}
+		if err := json.Unmarshal([]byte(text), &envelope); err != nil || !slices.Equal(envelope.AvailableContexts, []string{"staging-api"}) {
+			t.Fatalf("hidden contexts must not be listed as available: %s", text)
+		}
+	}
+}
@@ -1,8 +1,21 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/fsnotify/fsnotify"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestLevenshteinBasic validates distance properties including empty/identical strings.
@@ -60,3 +73,298 @@ func TestSuggestSimilarSkipsIdentical(t *testing.T) {
 		}
 	}
 }
+
+func TestLimitFieldValues(t *testing.T) {
+	fields := ty.UniSet[string]{
+		"level": {"WARN", "ERROR", "INFO"},
+		"app":   {"api"},
+	}
+
+	assert.Equal(t, fields, limitFieldValues(fields, 0), "no limit returns fields unchanged")
+
+	assert.Equal(t, map[string]fieldValuesSample{
+		"level": {Values: []string{"ERROR", "INFO"}, Truncated: true},
+		"app":   {Values: []string{"api"}},
+	}, limitFieldValues(fields, 2))
+}
+
+func TestConfigManagerReloadDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("watcher: %v", err)
+	}
+	defer func() { _ = watcher.Close() }()
+	cm := &ConfigManager{configPath: path, watcher: watcher}
+
+	write(`
+clients:
+  local: {type: local}
+contexts:
+  app: {client: local, search: {size: 10}}
+  old: {client: local}
+`)
+	diff, err := cm.Reload()
+	if err != nil {
+		t.Fatalf("first reload: %v", err)
+	}
+	assert.True(t, diff.FirstLoad)
+	assert.Equal(t, 2, diff.Contexts.Count)
+	assert.Empty(t, diff.Contexts.Added, "first load reports counts only")
+
+	write(`
+clients:
+  local: {type: local}
+  other: {type: local}
+contexts:
+  app: {client: local, search: {size: 20}}
+  new: {client: other}
+searches:
+  errors: {}
+`)
+	diff, err = cm.Reload()
+	if err != nil {
+		t.Fatalf("second reload: %v", err)
+	}
+	assert.False(t, diff.FirstLoad)
+	assert.Equal(t, ConfigSectionDiff{Count: 2, Added: []string{"new"}, Removed: []string{"old"}, Changed: []string{"app"}}, diff.Contexts)
+	assert.Equal(t, []string{"other"}, diff.Clients.Added)
+	assert.Equal(t, []string{"errors"}, diff.Searches.Added)
+
+	write("contexts: [not a map")
+	_, err = cm.Reload()
+	assert.Error(t, err)
+	cfg, _ := cm.Get()
+	assert.Contains(t, cfg.Contexts, "new", "previous config stays live")
+}
+
+func TestProgressReporter(t *testing.T) {
+	assert.Nil(t, newProgressReporter(context.Background(), mcp.CallToolRequest{}), "no progress token")
+
+	var sent []map[string]any
+	clock := time.Unix(0, 0)
+	p := &progressReporter{
+		send:     func(params map[string]any) error { sent = append(sent, params); return nil },
+		token:    "tok",
+		total:    3000,
+		interval: time.Second,
+		now:      func() time.Time { return clock },
+	}
+
+	p.Report(0, "querying")
+	p.Report(1000, "1000 entries fetched") // throttled
+	clock = clock.Add(time.Second)
+	p.Report(2000, "2000 entries fetched")
+	p.Done(2500, "2500 entries")
+
+	if assert.Len(t, sent, 3) {
+		assert.Equal(t, "tok", sent[0]["progressToken"])
+		assert.Equal(t, float64(3000), sent[0]["total"])
+		assert.Equal(t, float64(2000), sent[1]["progress"])
+		assert.Equal(t, float64(2500), sent[2]["total"], "final notification completes the progress")
+	}
+
+	var none *progressReporter
+	none.Report(1, "ignored")
+	none.Done(1, "ignored")
+}
+
+func TestParseFieldArgs(t *testing.T) {
+	fields, filter := parseFieldArgs(map[string]any{
+		"app":    "api",
+		"level":  []any{"ERROR", "FATAL"},
+		"status": []any{500.0, 503.0},
+		"host":   []any{},
+		"zone":   []any{"eu"},
+	})
+
+	assert.Equal(t, ty.MS{"app": "api", "zone": "eu"}, fields)
+	if assert.NotNil(t, filter) {
+		assert.Equal(t, client.LogicAnd, filter.Logic)
+		if assert.Len(t, filter.Filters, 2) {
+			assert.Equal(t, client.LogicOr, filter.Filters[0].Logic)
+			assert.Equal(t, "level", filter.Filters[0].Filters[1].Field)
+			assert.Equal(t, "FATAL", filter.Filters[0].Filters[1].Value)
+			assert.Equal(t, "503", filter.Filters[1].Filters[1].Value)
+		}
+	}
+
+	fields, filter = parseFieldArgs(map[string]any{"level": []any{"ERROR", "WARN"}})
+	assert.Empty(t, fields)
+	if assert.NotNil(t, filter) {
+		assert.Equal(t, client.LogicOr, filter.Logic)
+		assert.Len(t, filter.Filters, 2)
+	}
+}
+
+func TestCountFieldValuesTruncates(t *testing.T) {
+	entries := []client.LogEntry{
+		{Fields: ty.MI{"code": "a"}}, {Fields: ty.MI{"code": "b"}}, {Fields: ty.MI{"code": "b"}}, {Fields: ty.MI{"code": "c"}},
+	}
+	counts := countFieldValues(entries, []string{"code", "missing"}, false, true, 2)
+
+	assert.Equal(t, []client.FieldCount{{Value: "b", Count: 2}, {Value: "a", Count: 1}}, counts["code"].Values)
+	assert.True(t, counts["code"].Truncated)
+	assert.True(t, counts["code"].Approximate)
+	assert.Empty(t, counts["missing"].Values)
+	assert.False(t, counts["missing"].Truncated)
+}
+
+func TestSummarizeEntries(t *testing.T) {
+	at := func(sec int) time.Time { return time.Date(2024, 5, 1, 10, 30, sec, 0, time.UTC) }
+	entries := []client.LogEntry{
+		{Timestamp: at(5), Level: "ERROR", Message: "timeout after 3000 ms"},
+		{Timestamp: at(1), Level: "ERROR", Message: "timeout after 250 ms"},
+		{Timestamp: at(2), Level: "INFO", Message: "request served in 12ms"},
+		{Timestamp: at(3), Level: "fatal", Message: "user u42 not found"},
+		{Timestamp: at(4), Level: "ERROR", Message: "user u7 not found"},
+		{Timestamp: at(0), Level: "ERROR", Message: "disk full"},
+	}
+
+	summary := summarizeEntries(entries, client.SignatureBasic, false, 10)
+	assert.Equal(t, 6, summary.Scanned)
+	assert.Equal(t, 5, summary.Matched)
+	assert.Equal(t, 4, summary.Distinct, "basic keeps words with digits apart")
+	first := summary.Signatures[0]
+	assert.Equal(t, "timeout after <num> ms", first.Signature)
+	assert.Equal(t, 2, first.Count)
+	assert.Equal(t, "timeout after 3000 ms", first.Example)
+	assert.Equal(t, at(1), first.FirstSeen, "entries are not assumed to be sorted")
+	assert.Equal(t, at(5), first.LastSeen)
+	// Ties ordered by first occurrence
+	assert.Equal(t, "disk full", summary.Signatures[1].Signature)
+	assert.Equal(t, []client.FieldCount{{Value: "ERROR", Count: 4}, {Value: "INFO", Count: 1}, {Value: "fatal", Count: 1}}, summary.Levels)
+
+	summary = summarizeEntries(entries, client.SignatureAggressive, false, 1)
+	assert.Equal(t, 3, summary.Distinct)
+	assert.Len(t, summary.Signatures, 1)
+	assert.True(t, summary.Truncated)
+
+	summary = summarizeEntries(entries, client.SignatureBasic, true, 10)
+	assert.Equal(t, 6, summary.Matched)
+	assert.Equal(t, 5, summary.Distinct)
+}
+
+func TestSummarizeGroups(t *testing.T) {
+	entries := []client.LogEntry{
+		{Level: "ERROR", Message: "timeout after 3000 ms", Fields: ty.MI{"service": "api"}},
+		{Level: "INFO", Message: "served", Fields: ty.MI{"service": "api"}},
+		{Level: "ERROR", Message: "disk full", Fields: ty.MI{"service": "db"}},
+		{Level: "ERROR", Message: "no service"},
+	}
+
+	groups, truncated := summarizeGroups(entries, "service", client.SignatureBasic, false, 10)
+	assert.False(t, truncated)
+	require.Len(t, groups, 2, "entries without the field are left out")
+	assert.Equal(t, "api", groups[0].Value)
+	assert.Equal(t, 2, groups[0].Count)
+	assert.Equal(t, 1, groups[0].Matched)
+	require.Len(t, groups[0].Signatures, 1)
+	assert.Equal(t, "timeout after <num> ms", groups[0].Signatures[0].Signature)
+	assert.Equal(t, "db", groups[1].Value)
+
+	groups, truncated = summarizeGroups(entries, "level", client.SignatureBasic, true, 1)
+	assert.True(t, truncated)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "ERROR", groups[0].Value)
+	assert.Equal(t, 3, groups[0].Count)
+	assert.Len(t, groups[0].Signatures, 1)
+	assert.True(t, groups[0].Truncated)
+}
+
+func TestSearchArgs(t *testing.T) {
+	search := client.LogSearch{Fields: ty.MS{"app": "api"}}
+	vars := searchArgs(map[string]any{
+		"filters":   map[string]any{"status": 500},
+		"variables": map[string]any{"env": "prod", "shard": 2},
+	}, &search)
+
+	assert.Equal(t, ty.MS{"app": "api", "status": "500"}, search.Fields)
+	assert.Equal(t, map[string]string{"env": "prod", "shard": "2"}, vars)
+	assert.Empty(t, searchArgs(nil, &search))
+}
+
+func TestTruncateRunes(t *testing.T) {
+	assert.Equal(t, "héllo", truncateRunes("héllo", 5))
+	assert.Equal(t, "hé…", truncateRunes("héllo", 2))
+}
+
+func TestCheckRegexFilters(t *testing.T) {
+	filter := &client.Filter{Logic: client.LogicAnd, Filters: []client.Filter{
+		{Field: "level", Value: "(not a regex"},
+		{Field: "msg", Op: operator.Regex, Value: `timeout after \d+ms`},
+		{Logic: client.LogicOr, Filters: []client.Filter{{Field: "path", Op: operator.Regex, Value: `^/api/(v\d+/)?users`}}},
+	}}
+	warnings, err := checkRegexFilters(filter)
+	assert.NoError(t, err, "plain equals values are not regexes")
+	assert.Empty(t, warnings)
+
+	_, err = checkRegexFilters(&client.Filter{Logic: client.LogicNot, Filters: []client.Filter{{Field: "msg", Op: operator.Regex, Value: "(unclosed"}}})
+	var reErr *regexError
+	if assert.ErrorAs(t, err, &reErr) {
+		assert.Equal(t, "msg", reErr.Field)
+		assert.Equal(t, "(unclosed", reErr.Pattern)
+	}
+
+	for _, pattern := range []string{"(a+)+$", "(x*y)*", `(\w{2,})+`} {
+		warnings, err = checkRegexFilters(&client.Filter{Field: "msg", Op: operator.Regex, Value: pattern})
+		assert.NoError(t, err, pattern)
+		assert.Len(t, warnings, 1, pattern)
+	}
+	for _, pattern := range []string{"a+b+", "(ab){2}", `\d{1,3}(\.\d{1,3}){3}`} {
+		warnings, err = checkRegexFilters(&client.Filter{Field: "msg", Op: operator.Regex, Value: pattern})
+		assert.NoError(t, err, pattern)
+		assert.Empty(t, warnings, pattern)
+	}
+}
+
+func TestHandleBackendUnavailable(t *testing.T) {
+	err := fmt.Errorf("query failed: %w", &client.BackendUnavailableError{Err: &client.ThrottledError{Err: fmt.Errorf("rate exceeded")}, Attempts: 5})
+	res := handleBackendUnavailable("prod", err)
+	tc, _ := res.Content[0].(mcp.TextContent)
+	assert.Contains(t, tc.Text, `"code":"BACKEND_UNAVAILABLE"`)
+	assert.Contains(t, tc.Text, `"contextID":"prod"`)
+	assert.Contains(t, tc.Text, "Narrow the query")
+}
+
+func TestQueryDedup(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	d := newQueryDedup(2 * time.Second)
+	d.now = func() time.Time { return now }
+	cfg := &config.ContextConfig{}
+	response := map[string]any{"entries": []any{}, "meta": map[string]any{"resultCount": 0}}
+
+	_, hit, done := d.lookup(ctx, cfg, "a")
+	require.False(t, hit)
+	done(response)
+
+	now = now.Add(time.Second)
+	got, hit, _ := d.lookup(ctx, cfg, "a")
+	require.True(t, hit)
+	require.Equal(t, true, cachedResponse(got)["meta"].(map[string]any)["cached"])
+	require.Nil(t, response["meta"].(map[string]any)["cached"], "the shared response is not changed")
+
+	_, hit, _ = d.lookup(ctx, &config.ContextConfig{}, "a")
+	require.False(t, hit, "a reloaded config starts over")
+
+	_, hit, done = d.lookup(ctx, cfg, "b")
+	require.False(t, hit)
+	done(nil)
+	_, hit, done = d.lookup(ctx, cfg, "b")
+	require.False(t, hit, "failed requests are not reused")
+	done(response)
+
+	now = now.Add(2 * time.Second)
+	_, hit, _ = d.lookup(ctx, cfg, "b")
+	require.False(t, hit, "expired after the window")
+
+	_, hit, _ = newQueryDedup(0).lookup(ctx, cfg, "a")
+	require.False(t, hit)
+}
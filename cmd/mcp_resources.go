@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	contextResourcePrefix = "logviewer://context/"
+	// fieldDiscoveryWindow is the time range searched for the fields of a
+	// context resource, the get_fields default
+	fieldDiscoveryWindow = "15m"
+	// fieldDiscoveryTTL is how long the fields of a context are served from
+	// the cache before asking the backend again
+	fieldDiscoveryTTL = time.Minute
+)
+
+// fieldDiscoveryCache keeps the field names found for each context. An entry
+// belongs to the configuration it was discovered with, so a reload of the
+// config starts over.
+type fieldDiscoveryCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]fieldDiscovery
+}
+
+type fieldDiscovery struct {
+	cfg   *config.ContextConfig
+	names []string
+	at    time.Time
+}
+
+func newFieldDiscoveryCache() *fieldDiscoveryCache {
+	return &fieldDiscoveryCache{now: time.Now, entries: make(map[string]fieldDiscovery)}
+}
+
+// fieldNames returns the field names of contextID, discovered over the last
+// fieldDiscoveryWindow unless the cache holds them for the current config.
+func (c *fieldDiscoveryCache) fieldNames(ctx context.Context, cm *ConfigManager, contextID string) ([]string, error) {
+	cfg, searchFactory := cm.Get()
+	c.mu.Lock()
+	cached, ok := c.entries[contextID]
+	c.mu.Unlock()
+	if ok && cached.cfg == cfg && c.now().Sub(cached.at) < fieldDiscoveryTTL {
+		return cached.names, nil
+	}
+
+	search := client.LogSearch{}
+	search.Range.Last.S(fieldDiscoveryWindow)
+	values, err := searchFactory.GetFieldValues(ctx, contextID, []string{}, search, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	c.mu.Lock()
+	c.entries[contextID] = fieldDiscovery{cfg: cfg, names: names, at: c.now()}
+	c.mu.Unlock()
+	return names, nil
+}
+
+// parseContextResourceURI splits logviewer://context/<id>/<kind> into the
+// context and the kind of resource, fields or schema.
+func parseContextResourceURI(uri string) (string, string, error) {
+	rest, ok := strings.CutPrefix(uri, contextResourcePrefix)
+	contextID, kind, found := strings.Cut(rest, "/")
+	if !ok || !found || contextID == "" || (kind != "fields" && kind != "schema") {
+		return "", "", fmt.Errorf("invalid context resource URI %q: expected %s<contextID>/fields or %s<contextID>/schema", uri, contextResourcePrefix, contextResourcePrefix)
+	}
+	return contextID, kind, nil
+}
+
+// registerContextResources adds the fields and schema resources of each
+// context. The contexts of the config are listed, and templates resolve the
+// contexts added by a later reload; the content is always read from the
+// current config.
+func registerContextResources(s *server.MCPServer, cm *ConfigManager) {
+	cache := newFieldDiscoveryCache()
+	read := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return readContextResource(ctx, cm, cache, request.Params.URI)
+	}
+
+	cfg, _ := cm.Get()
+	ids := make([]string, 0, len(cfg.Contexts))
+	for id := range cfg.Contexts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		s.AddResource(mcp.NewResource(
+			contextResourcePrefix+id+"/fields",
+			"Fields of "+id,
+			mcp.WithResourceDescription(fmt.Sprintf("Sorted JSON array of the field names found in %s over the last %s.", id, fieldDiscoveryWindow)),
+			mcp.WithMIMEType("application/json"),
+		), read)
+		s.AddResource(mcp.NewResource(
+			contextResourcePrefix+id+"/schema",
+			"Search schema of "+id,
+			mcp.WithResourceDescription(fmt.Sprintf("JSON search configuration of %s: variables, field extraction and filters, as get_context_details returns it.", id)),
+			mcp.WithMIMEType("application/json"),
+		), read)
+	}
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		contextResourcePrefix+"{contextID}/fields",
+		"Context fields",
+		mcp.WithTemplateDescription("Sorted JSON array of the field names found in a context."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), read)
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		contextResourcePrefix+"{contextID}/schema",
+		"Context search schema",
+		mcp.WithTemplateDescription("JSON search configuration of a context."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), read)
+}
+
+// readContextResource returns the content of a context resource. An unknown
+// context fails with the available ones.
+func readContextResource(ctx context.Context, cm *ConfigManager, cache *fieldDiscoveryCache, uri string) ([]mcp.ResourceContents, error) {
+	contextID, kind, err := parseContextResourceURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	cfg, searchFactory := cm.Get()
+	if _, ok := cfg.Contexts[contextID]; !ok {
+		ids := make([]string, 0, len(cfg.Contexts))
+		for id := range cfg.Contexts {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		return nil, fmt.Errorf("context %q not found in %s (available: %s)", contextID, uri, strings.Join(ids, ", "))
+	}
+
+	var payload any
+	switch kind {
+	case "fields":
+		names, err := cache.fieldNames(ctx, cm, contextID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover the fields of %s: %w", contextID, err)
+		}
+		payload = names
+	case "schema":
+		searchContext, err := searchFactory.GetSearchContext(ctx, contextID, []string{}, client.LogSearch{}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the search context of %s: %w", contextID, err)
+		}
+		payload = searchContext.Search
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", uri, err)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(b)}}, nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/printer"
+)
+
+// fetchAllPages emits the entries of result and of every following page,
+// requested with next, until the backend has no more results or maxResults
+// entries (0 for no limit) were emitted. Progress is written to progress after
+// each page. It stops when a page token repeats, so a backend returning a
+// stable token cannot loop forever, and returns the number of entries emitted.
+func fetchAllPages(
+	ctx context.Context,
+	result client.LogSearchResult,
+	next func(pageToken string) (client.LogSearchResult, error),
+	maxResults int,
+	emit func(result client.LogSearchResult, entries []client.LogEntry) error,
+	progress io.Writer,
+) (int, error) {
+	if result.GetSearch().Follow {
+		return 0, errors.New("--page-all cannot be used with --refresh")
+	}
+
+	total := 0
+	seen := make(map[string]bool)
+	for page := 1; ; page++ {
+		entries, _, err := result.GetEntries(ctx)
+		partial := client.IsPartial(err)
+		if err != nil && !partial {
+			return total, err
+		}
+
+		if maxResults > 0 && total+len(entries) > maxResults {
+			entries = entries[:maxResults-total]
+		}
+		if err := emit(result, entries); err != nil {
+			return total, err
+		}
+		total += len(entries)
+		fmt.Fprintf(progress, "page %d: %d entries fetched\n", page, total)
+
+		if partial {
+			fmt.Fprintf(progress, "Warning: %v; stopping after page %d\n", err, page)
+			return total, nil
+		}
+		if maxResults > 0 && total >= maxResults {
+			return total, nil
+		}
+
+		info := result.GetPaginationInfo()
+		if info == nil || !info.HasMore || info.NextPageToken == "" || len(entries) == 0 {
+			return total, nil
+		}
+		if seen[info.NextPageToken] {
+			fmt.Fprintf(progress, "Warning: backend returned page token %q again; stopping\n", info.NextPageToken)
+			return total, nil
+		}
+		seen[info.NextPageToken] = true
+
+		result, err = next(info.NextPageToken)
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// fetchedPage is a LogSearchResult serving the entries already fetched for
+// one page, so the printer renders each page as it arrives.
+type fetchedPage struct {
+	client.LogSearchResult
+	entries []client.LogEntry
+}
+
+func (p fetchedPage) GetEntries(_ context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	return p.entries, nil, nil
+}
+
+func (p fetchedPage) Err() <-chan error {
+	return nil
+}
+
+// runPageAll prints result and all the following pages for --page-all, as
+// NDJSON with --json and through the printer template otherwise.
+func runPageAll(ctx context.Context, result client.LogSearchResult) error {
+	var emit func(result client.LogSearchResult, entries []client.LogEntry) error
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		emit = func(result client.LogSearchResult, entries []client.LogEntry) error {
+			for i := range entries {
+				if nestFields {
+					entries[i].Fields = printer.NestFields(entries[i].Fields)
+				}
+				if err := enc.Encode(entries[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	} else {
+		emit = func(result client.LogSearchResult, entries []client.LogEntry) error {
+			_, err := printer.PrintPrinter{}.Display(ctx, fetchedPage{LogSearchResult: result, entries: entries}, func(error) {})
+			return err
+		}
+	}
+
+	next := func(token string) (client.LogSearchResult, error) {
+		pageToken = token
+		return resolveSearch()
+	}
+	_, err := fetchAllPages(ctx, result, next, maxResults, emit, os.Stderr)
+	return err
+}
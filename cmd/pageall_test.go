@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenPage is a page of pageSize entries starting at offset, with a next
+// token until total is reached; a stable token is returned when set.
+type tokenPage struct {
+	offset, pageSize, total int
+	stableToken             string
+}
+
+func (p *tokenPage) GetSearch() *client.LogSearch { return &client.LogSearch{} }
+func (p *tokenPage) GetEntries(_ context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	var entries []client.LogEntry
+	for i := p.offset; i < p.offset+p.pageSize && i < p.total; i++ {
+		entries = append(entries, client.LogEntry{Message: fmt.Sprintf("entry %d", i)})
+	}
+	return entries, nil, nil
+}
+func (p *tokenPage) GetFields(_ context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
+	return nil, nil, nil
+}
+func (p *tokenPage) GetPaginationInfo() *client.PaginationInfo {
+	if p.stableToken != "" {
+		return &client.PaginationInfo{HasMore: true, NextPageToken: p.stableToken}
+	}
+	if p.offset+p.pageSize >= p.total {
+		return nil
+	}
+	return &client.PaginationInfo{HasMore: true, NextPageToken: strconv.Itoa(p.offset + p.pageSize)}
+}
+func (p *tokenPage) Err() <-chan error { return nil }
+
+func TestFetchAllPages(t *testing.T) {
+	collect := func(first *tokenPage, maxResults int) ([]string, []string, string) {
+		var messages, tokens []string
+		var progress bytes.Buffer
+		next := func(token string) (client.LogSearchResult, error) {
+			tokens = append(tokens, token)
+			offset, _ := strconv.Atoi(token)
+			return &tokenPage{offset: offset, pageSize: first.pageSize, total: first.total, stableToken: first.stableToken}, nil
+		}
+		emit := func(_ client.LogSearchResult, entries []client.LogEntry) error {
+			for _, e := range entries {
+				messages = append(messages, e.Message)
+			}
+			return nil
+		}
+		total, err := fetchAllPages(context.Background(), first, next, maxResults, emit, &progress)
+		require.NoError(t, err)
+		assert.Equal(t, len(messages), total)
+		return messages, tokens, progress.String()
+	}
+
+	t.Run("follows tokens until exhausted", func(t *testing.T) {
+		messages, tokens, progress := collect(&tokenPage{pageSize: 10, total: 25}, 0)
+		assert.Len(t, messages, 25)
+		assert.Equal(t, "entry 24", messages[24])
+		assert.Equal(t, []string{"10", "20"}, tokens)
+		assert.Contains(t, progress, "page 3: 25 entries fetched")
+	})
+
+	t.Run("stops at max results", func(t *testing.T) {
+		messages, tokens, _ := collect(&tokenPage{pageSize: 10, total: 100}, 15)
+		assert.Len(t, messages, 15)
+		assert.Equal(t, []string{"10"}, tokens)
+	})
+
+	t.Run("stops on a repeated token", func(t *testing.T) {
+		messages, tokens, progress := collect(&tokenPage{pageSize: 10, total: 100, stableToken: "same"}, 0)
+		assert.Len(t, messages, 20)
+		assert.Equal(t, []string{"same"}, tokens)
+		assert.Contains(t, progress, `page token "same" again`)
+	})
+}
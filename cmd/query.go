@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/log/client/config"
@@ -76,7 +78,7 @@ func stringArrayEnvVariable(strs []string, maps *ty.MS) error {
 }
 
 // buildSearchRequest creates a LogSearch from CLI flags
-func buildSearchRequest() client.LogSearch {
+func buildSearchRequest() (client.LogSearch, error) {
 	searchRequest := client.LogSearch{
 		Fields:          ty.MS{},
 		FieldsCondition: ty.MS{},
@@ -86,10 +88,12 @@ func buildSearchRequest() client.LogSearch {
 	parseBasicFlags(&searchRequest)
 	parseTimeFlags(&searchRequest)
 	parseFieldExtractionFlags(&searchRequest)
-	parseFieldFlags(&searchRequest)
+	if err := parseFieldFlags(&searchRequest); err != nil {
+		return searchRequest, err
+	}
 	parseClientOptions(&searchRequest)
 
-	return searchRequest
+	return searchRequest, nil
 }
 
 func parseBasicFlags(req *client.LogSearch) {
@@ -105,6 +109,7 @@ func parseBasicFlags(req *client.LogSearch) {
 	if nativeQuery != "" {
 		req.NativeQuery.S(nativeQuery)
 	}
+	req.NativeQueryOnly = nativeQueryOnly
 	req.Follow = refresh
 }
 
@@ -120,6 +125,10 @@ func parseTimeFlags(req *client.LogSearch) {
 	if last != "" {
 		req.Range.Last.S(last)
 	}
+	if err := req.Range.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
 }
 
 func parseFieldExtractionFlags(req *client.LogSearch) {
@@ -129,9 +138,12 @@ func parseFieldExtractionFlags(req *client.LogSearch) {
 	if kvRegex != "" {
 		req.FieldExtraction.KvRegex.S(kvRegex)
 	}
+	if maxMessageLength > 0 {
+		req.FieldExtraction.MaxMessageLength.S(maxMessageLength)
+	}
 }
 
-func parseFieldFlags(req *client.LogSearch) {
+func parseFieldFlags(req *client.LogSearch) error {
 	// Parse fields: auto-detect hl syntax vs legacy syntax
 	if len(fields) > 0 {
 		var hlFields []string
@@ -147,6 +159,7 @@ func parseFieldFlags(req *client.LogSearch) {
 
 		// Process legacy fields (field=value)
 		if len(legacyFields) > 0 {
+			warnDuplicateFields(legacyFields)
 			_ = stringArrayEnvVariable(legacyFields, &req.Fields)
 		}
 
@@ -163,16 +176,94 @@ func parseFieldFlags(req *client.LogSearch) {
 	if len(fieldsOps) > 0 {
 		_ = stringArrayEnvVariable(fieldsOps, &req.FieldsCondition)
 	}
+	if fieldsLogic != "" {
+		if !strings.EqualFold(fieldsLogic, "and") && !strings.EqualFold(fieldsLogic, "or") {
+			return fmt.Errorf("invalid --fields-logic %q: expected and or or", fieldsLogic)
+		}
+		req.FieldsLogic.S(strings.ToLower(fieldsLogic))
+	}
+
+	// Parse --fields-file conditions
+	if fieldsFile != "" {
+		fileFilter, err := parseFieldsFile(fieldsFile, req.FieldsCondition)
+		if err != nil {
+			return err
+		}
+		mergeFilterWithAnd(&req.Filter, fileFilter)
+	}
 
 	// Parse -q/--query expression
 	if queryExpr != "" {
 		queryFilter, err := query.ParseQueryExpression(queryExpr)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to parse query expression: %v\n", err)
+			return fmt.Errorf("invalid query expression: %w", err)
+		}
+		mergeFilterWithAnd(&req.Filter, queryFilter)
+	}
+	return nil
+}
+
+// warnDuplicateFields warns about a field given several times with -f, as
+// only its last value is kept.
+func warnDuplicateFields(legacyFields []string) {
+	seen := make(map[string]int)
+	for _, f := range legacyFields {
+		key, _, found := strings.Cut(f, "=")
+		if !found || key == "" {
+			continue
+		}
+		if seen[key]++; seen[key] == 2 {
+			fmt.Fprintf(os.Stderr, "warning: -f %s is given several times and only its last value is used; use -q '%s=A OR %s=B' to match several values\n", key, key, key)
+		}
+	}
+}
+
+// parseFieldsFile reads filter conditions from a file, one per line, using the
+// same hl or legacy (field=value) syntax as -f. Blank lines and lines starting
+// with # are ignored. Legacy conditions use the operator from conditions
+// (--fields-condition) for their field. All conditions are combined with AND.
+func parseFieldsFile(path string, conditions ty.MS) (*client.Filter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fields file: %w", err)
+	}
+
+	var filters []client.Filter
+	var errs []error
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var f *client.Filter
+		if query.IsHLSyntax(line) {
+			f, err = query.ParseFilterFlag(line)
 		} else {
-			mergeFilterWithAnd(&req.Filter, queryFilter)
+			field, _, _ := strings.Cut(line, "=")
+			f, err = query.ParseLegacyFilter(line, conditions[strings.TrimSpace(field)])
 		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %w", path, i+1, err))
+			continue
+		}
+		filters = append(filters, *f)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	switch len(filters) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &filters[0], nil
 	}
+	return &client.Filter{
+		Logic:   client.LogicAnd,
+		Filters: filters,
+	}, nil
 }
 
 func parseClientOptions(req *client.LogSearch) {
@@ -200,15 +291,56 @@ func parseClientOptions(req *client.LogSearch) {
 	if cmd != "" {
 		req.Options[local.OptionsCmd] = cmd
 	}
+	if localFile != "" {
+		req.Options[local.OptionsFile] = localFile
+	}
 	if sshOptions.DisablePTY {
 		req.Options["disablePTY"] = true
 	}
+	if strictFields != "" {
+		req.Options[factory.StrictFieldsOption] = strictFields
+	}
+	if queryTimeout != "" {
+		req.Options[factory.QueryTimeoutOption] = queryTimeout
+	}
+	if nestFields && !jsonOutput {
+		fmt.Fprintln(os.Stderr, "error: --nest-fields requires --json")
+		os.Exit(1)
+	}
+	if template != "" && templateFile != "" {
+		fmt.Fprintln(os.Stderr, "error: --format and --template-file cannot be used together")
+		os.Exit(1)
+	}
 	if template != "" {
 		req.PrinterOptions.Template.S(template)
 	}
+	if templateFile != "" {
+		text, err := printer.LoadTemplateFile(templateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		req.PrinterOptions.Template.S(text)
+	}
+	if len(highlightTerms) > 0 {
+		req.PrinterOptions.Highlight = highlightTerms
+	}
+	if highlightCase {
+		req.PrinterOptions.HighlightCase.S(true)
+	}
+
+	if timezone != "" {
+		if _, err := printer.LoadDisplayLocation(timezone); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		req.PrinterOptions.Timezone.S(timezone)
+	}
 
-	// Handle color flag
-	if colorOutput != "" {
+	// Handle color flags; --no-color wins over --color
+	if noColor {
+		req.PrinterOptions.Color.S(false)
+	} else if colorOutput != "" {
 		switch colorOutput {
 		case "always":
 			req.PrinterOptions.Color.S(true)
@@ -244,6 +376,45 @@ func parseRuntimeVars() map[string]string {
 	return runtimeVars
 }
 
+// checkRequiredVars fails when a context declares required variables that
+// --var, the environment and their default all leave unset, listing them
+// with their description.
+func checkRequiredVars(cfg *config.ContextConfig, contextIDs, inherits []string, runtimeVars map[string]string) error {
+	for _, id := range contextIDs {
+		sc, err := cfg.GetSearchContext(id, inherits, client.LogSearch{}, runtimeVars)
+		if err != nil {
+			return err
+		}
+		missing := config.MissingVariables(sc.Search, runtimeVars)
+		if len(missing) == 0 {
+			continue
+		}
+		for i, name := range missing {
+			if desc := sc.Search.Variables[name].Description; desc != "" {
+				missing[i] = fmt.Sprintf("%s (%s)", name, desc)
+			}
+		}
+		return fmt.Errorf("%w for context %s: %s; set them with --var name=value or in the environment",
+			config.ErrMissingVariables, id, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// applyDisplayTimezone sets the zone timestamps are shown in: the --timezone
+// flag, else the display.timezone of cfg, which may be nil, else local.
+func applyDisplayTimezone(cfg *config.ContextConfig) error {
+	name := timezone
+	if name == "" && cfg != nil {
+		name = cfg.Display.Timezone.Value
+	}
+	location, err := printer.LoadDisplayLocation(name)
+	if err != nil {
+		return err
+	}
+	printer.SetDisplayLocation(location)
+	return nil
+}
+
 // resolveContextIDsFromConfig resolves context IDs, using current context if none specified
 func resolveContextIDsFromConfig(cfg *config.ContextConfig) []string {
 	if len(contextIDs) > 0 {
@@ -264,35 +435,44 @@ func isAdHocQuery() bool {
 		cloudwatchLogGroup != "" ||
 		(k8sNamespace != "" && len(contextIDs) == 0 && configPath == "") ||
 		(cmd != "" && len(contextIDs) == 0 && configPath == "") ||
+		(localFile != "" && len(contextIDs) == 0 && configPath == "") ||
 		endpointSplunk != "" ||
 		((dockerContainer != "" || dockerService != "") && len(contextIDs) == 0 && configPath == "")
 }
 
-// getAdHocLogClient creates a LogClient from ad-hoc CLI flags
-func getAdHocLogClient(searchRequest *client.LogSearch) (client.LogBackend, error) {
-	var err error
-	var system string
-
+// adHocSystem returns the backend type selected by the ad-hoc CLI flags, or
+// "" when none is. It also names the entries of ad-hoc queries, which have no
+// context id.
+func adHocSystem() string {
 	switch {
 	case endpointOpensearch != "":
-		system = "opensearch"
+		return "opensearch"
 	case endpointKibana != "":
-		system = "kibana"
+		return "kibana"
 	case cloudwatchLogGroup != "":
-		system = "cloudwatch"
+		return "cloudwatch"
 	case k8sNamespace != "":
-		system = "k8s"
+		return "k8s"
 	case cmd != "":
 		if sshOptions.Addr != "" {
-			system = "ssh"
-		} else {
-			system = "local"
+			return "ssh"
 		}
+		return "local"
+	case localFile != "":
+		return "local"
 	case endpointSplunk != "":
-		system = "splunk"
+		return "splunk"
 	case dockerContainer != "" || dockerService != "":
-		system = "docker"
-	default:
+		return "docker"
+	}
+	return ""
+}
+
+// getAdHocLogClient creates a LogClient from ad-hoc CLI flags
+func getAdHocLogClient(searchRequest *client.LogSearch) (client.LogBackend, error) {
+	var err error
+	system := adHocSystem()
+	if system == "" {
 		return nil, errors.New(`
         failed to select a system for logging provide one of the following:
 			* --docker-container or --docker-service
@@ -302,6 +482,7 @@ func getAdHocLogClient(searchRequest *client.LogSearch) (client.LogBackend, erro
             * --k8s-namespace
             * --ssh-addr
             * --cmd
+            * --file
         `)
 	}
 
@@ -371,7 +552,10 @@ func getAdHocLogClient(searchRequest *client.LogSearch) (client.LogBackend, erro
 }
 
 func resolveSearch() (client.LogSearchResult, error) {
-	searchRequest := buildSearchRequest()
+	searchRequest, err := buildSearchRequest()
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if this is a config-based query
 	if configPath != "" || len(contextIDs) > 0 {
@@ -379,6 +563,9 @@ func resolveSearch() (client.LogSearchResult, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := applyDisplayTimezone(cfg); err != nil {
+			return nil, err
+		}
 
 		clientFactory, err := factory.GetLogBackendFactory(cfg.Clients)
 		if err != nil {
@@ -396,10 +583,13 @@ func resolveSearch() (client.LogSearchResult, error) {
 		if len(resolvedContextIDs) == 0 {
 			return nil, errors.New("no contexts specified for query; use -i to select one or more contexts or set a default with 'logviewer context use'")
 		}
+		if err := checkRequiredVars(cfg, resolvedContextIDs, inherits, runtimeVars); err != nil {
+			return nil, err
+		}
 
 		// For single context, execute directly without MultiLogSearchResult wrapper
 		if len(resolvedContextIDs) == 1 {
-			ctx := context.Background()
+			ctx := withFallbackWarnings(context.Background())
 			searchRequest.Options["__context_id__"] = resolvedContextIDs[0]
 			return searchFactory.GetSearchResult(ctx, resolvedContextIDs[0], inherits, searchRequest, runtimeVars)
 		}
@@ -409,37 +599,32 @@ func resolveSearch() (client.LogSearchResult, error) {
 		if err != nil {
 			return nil, err
 		}
-		var wg sync.WaitGroup
-		ctx := context.Background()
-
-		for _, contextID := range resolvedContextIDs {
-			wg.Add(1)
-			go func(cid string) {
-				defer wg.Done()
-				reqCopy := searchRequest
-				reqCopy.Options = ty.MergeM(make(ty.MI, len(searchRequest.Options)+1), searchRequest.Options)
-				reqCopy.Options["__context_id__"] = cid
-				reqCopy.Fields = ty.MergeM(make(ty.MS, len(searchRequest.Fields)), searchRequest.Fields)
-				reqCopy.FieldsCondition = ty.MergeM(make(ty.MS, len(searchRequest.FieldsCondition)), searchRequest.FieldsCondition)
-				if searchRequest.Variables != nil {
-					reqCopy.Variables = make(map[string]client.VariableDefinition, len(searchRequest.Variables))
-					for k, v := range searchRequest.Variables {
-						reqCopy.Variables[k] = v
-					}
+		ctx := withFallbackWarnings(context.Background())
+
+		err = client.FanOut(ctx, resolvedContextIDs, concurrency, func(cid string) {
+			reqCopy := searchRequest
+			reqCopy.Options = ty.MergeM(make(ty.MI, len(searchRequest.Options)+1), searchRequest.Options)
+			reqCopy.Options["__context_id__"] = cid
+			reqCopy.Fields = ty.MergeM(make(ty.MS, len(searchRequest.Fields)), searchRequest.Fields)
+			reqCopy.FieldsCondition = ty.MergeM(make(ty.MS, len(searchRequest.FieldsCondition)), searchRequest.FieldsCondition)
+			if searchRequest.Variables != nil {
+				reqCopy.Variables = make(map[string]client.VariableDefinition, len(searchRequest.Variables))
+				for k, v := range searchRequest.Variables {
+					reqCopy.Variables[k] = v
 				}
-				sr, err := searchFactory.GetSearchResult(ctx, cid, inherits, reqCopy, runtimeVars)
-				multiResult.Add(sr, err)
-			}(contextID)
+			}
+			sr, err := searchFactory.GetSearchResult(ctx, cid, inherits, reqCopy, runtimeVars)
+			multiResult.AddContext(cid, sr, err)
+		})
+		if err != nil {
+			return nil, err
 		}
 
-		wg.Wait()
-
-		if len(multiResult.Errors) > 0 {
-			var errorStrings []string
-			for _, e := range multiResult.Errors {
-				errorStrings = append(errorStrings, e.Error())
-			}
-			fmt.Fprintf(os.Stderr, "errors encountered for some contexts:\n%s\n", strings.Join(errorStrings, "\n"))
+		if multiResult.Failures.AllFailed() {
+			return nil, multiResult.ContextErr()
+		}
+		if multiResult.Failures.Partial() {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", multiResult.ContextErr())
 		}
 		return multiResult, nil
 	}
@@ -452,17 +637,23 @@ func resolveSearch() (client.LogSearchResult, error) {
 		}
 	}
 
+	if err := searchRequest.ValidateFilter(); err != nil {
+		return nil, err
+	}
+
 	logClient, err := getAdHocLogClient(&searchRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	searchResult, err := logClient.Get(context.Background(), &searchRequest)
+	ctx := withFallbackWarnings(context.Background())
+	client.ReportFallbacks(ctx, logClient, &searchRequest)
+	searchResult, err := logClient.Get(ctx, &searchRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	return searchResult, nil
+	return client.WithContextID(searchResult, adHocSystem()), nil
 }
 
 var queryFieldCommand = &cobra.Command{
@@ -495,6 +686,14 @@ var queryLogCommand = &cobra.Command{
 			os.Exit(1)
 		}
 
+		if pageAll {
+			if err := runPageAll(context.Background(), searchResult); err != nil {
+				fmt.Fprintln(os.Stderr, "error:", err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		if paginationInfo := searchResult.GetPaginationInfo(); paginationInfo != nil && paginationInfo.HasMore {
 			fmt.Fprintf(os.Stderr, "More results available. To fetch the next page, run the same command with --page-token \"%s\"\n", paginationInfo.NextPageToken)
 		}
@@ -504,7 +703,9 @@ var queryLogCommand = &cobra.Command{
 			enc := json.NewEncoder(os.Stdout)
 			entries, c, err := searchResult.GetEntries(context.Background())
 
-			if err != nil {
+			if client.IsPartial(err) {
+				fmt.Fprintf(os.Stderr, "Warning: %v; showing the %d entries received\n", err, len(entries))
+			} else if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
@@ -512,8 +713,9 @@ var queryLogCommand = &cobra.Command{
 			// Helper to encode a slice of entries
 			printJSON := func(es []client.LogEntry) error {
 				for i := range es {
-					// Extract JSON fields if enabled
-					client.ExtractJSONFromEntry(&es[i], searchResult.GetSearch())
+					if nestFields {
+						es[i].Fields = printer.NestFields(es[i].Fields)
+					}
 					if err := enc.Encode(es[i]); err != nil {
 						return err
 					}
@@ -540,6 +742,10 @@ var queryLogCommand = &cobra.Command{
 
 		outputter := printer.PrintPrinter{}
 		onError := func(err error) {
+			if client.IsReconnecting(err) {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				return
+			}
 			fmt.Fprintf(os.Stderr, "Error displaying logs: %v\n", err)
 			os.Exit(1)
 		}
@@ -580,6 +786,9 @@ Examples:
   # With filters applied
   logviewer query values -i prod-logs error_code -f level=ERROR --last 1h
 
+  # Percentiles instead of the distinct values of a numeric field
+  logviewer query values -i prod-logs latency_ms --numeric --last 1h
+
   # Ad-hoc query (without config)
   logviewer query values level app --opensearch-endpoint http://localhost:9200 --elk-index app-logs --last 1h`,
 	PreRun: onCommandStart,
@@ -593,7 +802,236 @@ Examples:
 			os.Exit(1)
 		}
 
-		if err := RunQueryValues(os.Stdout, logClient, search, fieldNames, jsonOutput); err != nil {
+		if err := RunQueryValues(os.Stdout, logClient, search, fieldNames, jsonOutput, numericValues); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var queryCountCommand = &cobra.Command{
+	Use:   "count",
+	Short: "Print the number of log entries matching the search",
+	Long: `Print the number of log entries matching the search, without streaming them.
+
+OpenSearch and Splunk count the entries natively. Other backends fetch every
+page of the search and count the entries returned.
+
+Examples:
+  # Errors of the last hour
+  logviewer query count -i prod-logs --last 1h -f level=ERROR
+
+  # As JSON: {"count": N}
+  logviewer query count -i prod-logs --last 1h --json`,
+	PreRun: onCommandStart,
+	Run: func(_ *cobra.Command, _ []string) {
+		logClient, search, err := resolveLogClient()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+
+		if err := RunQueryCount(os.Stdout, logClient, search, jsonOutput); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+const (
+	// histogramDefaultSize is the number of entries bucketed when --size is
+	// not set, the backends returning only a page by default
+	histogramDefaultSize = 10000
+	// histogramMaxBuckets bounds the buckets between the first and the last
+	// entry, so a small --interval over a long range fails early
+	histogramMaxBuckets = 1000
+	// histogramBarWidth is the length of the bar of the fullest bucket
+	histogramBarWidth = 50
+	// histogramNoGroup is the --group-by value of entries without the field
+	histogramNoGroup = "(none)"
+)
+
+// HistogramBucket is the number of entries whose timestamp falls in the
+// interval starting at Start, split by the value of the --group-by field.
+type HistogramBucket struct {
+	Start  time.Time      `json:"start"`
+	Count  int            `json:"count"`
+	Groups map[string]int `json:"groups,omitempty"`
+}
+
+// parseHistogramInterval parses --interval, a duration like the --last ones.
+func parseHistogramInterval(value string) (time.Duration, error) {
+	// NormalizeTimeValue leaves durations as they are and rewrites times
+	if _, isTime := ty.NormalizeTimeValue(value); !isTime {
+		if interval, err := time.ParseDuration(value); err == nil && interval > 0 {
+			return interval, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid --interval %q: expected a positive duration like 10m", value)
+}
+
+// bucketEntries counts the entries per interval, from the bucket of the
+// oldest entry to the one of the newest, empty buckets included. With
+// groupBy, each bucket also counts the entries per value of that field,
+// "level" falling back to the entry level. Entries without a timestamp are
+// skipped.
+func bucketEntries(entries []client.LogEntry, interval time.Duration, groupBy string) ([]HistogramBucket, error) {
+	var first, last time.Time
+	for _, entry := range entries {
+		if entry.Timestamp.IsZero() {
+			continue
+		}
+		start := entry.Timestamp.Truncate(interval)
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+	if first.IsZero() {
+		return []HistogramBucket{}, nil
+	}
+
+	n := int(last.Sub(first)/interval) + 1
+	if n > histogramMaxBuckets {
+		return nil, fmt.Errorf("--interval %s makes %d buckets between %s and %s, more than %d; use a larger interval",
+			interval, n, first.Format(time.RFC3339), last.Format(time.RFC3339), histogramMaxBuckets)
+	}
+	buckets := make([]HistogramBucket, n)
+	for i := range buckets {
+		buckets[i].Start = first.Add(time.Duration(i) * interval).UTC()
+	}
+
+	for _, entry := range entries {
+		if entry.Timestamp.IsZero() {
+			continue
+		}
+		bucket := &buckets[entry.Timestamp.Truncate(interval).Sub(first)/interval]
+		bucket.Count++
+		if groupBy == "" {
+			continue
+		}
+		if bucket.Groups == nil {
+			bucket.Groups = make(map[string]int)
+		}
+		bucket.Groups[histogramGroup(entry, groupBy)]++
+	}
+	return buckets, nil
+}
+
+func histogramGroup(entry client.LogEntry, field string) string {
+	v, ok := entry.Fields[field]
+	if !ok && field == "level" {
+		v, ok = entry.Level, true
+	}
+	if !ok || v == nil || fmt.Sprint(v) == "" {
+		return histogramNoGroup
+	}
+	return fmt.Sprint(v)
+}
+
+// RunQueryHistogram buckets the entries of the search per interval and
+// prints a bar per bucket, with a bar per group under it when groupBy is
+// set, the times in loc. With asJSON the buckets are printed as a JSON array.
+func RunQueryHistogram(out, warnings io.Writer, cli client.LogClient, search client.LogSearch, interval time.Duration, groupBy string, asJSON bool, loc *time.Location) error {
+	if !search.Size.Set {
+		search.Size.S(histogramDefaultSize)
+	}
+	entries, err := cli.Query(context.Background(), search)
+	if err != nil {
+		return err
+	}
+	if len(entries) >= search.Size.Value {
+		fmt.Fprintf(warnings, "Warning: the histogram stops at %d entries; raise --size or narrow the time range to include all of them\n", search.Size.Value)
+	}
+
+	buckets, err := bucketEntries(entries, interval, groupBy)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return json.NewEncoder(out).Encode(buckets)
+	}
+	if len(buckets) == 0 {
+		_, err := fmt.Fprintln(out, "(no entries)")
+		return err
+	}
+
+	maxCount := 0
+	labelWidth := 0
+	for _, b := range buckets {
+		maxCount = max(maxCount, b.Count)
+		for group := range b.Groups {
+			labelWidth = max(labelWidth, len(group))
+		}
+	}
+	bar := func(count int) string {
+		width := count * histogramBarWidth / maxCount
+		if width == 0 && count > 0 {
+			width = 1
+		}
+		return strings.Repeat("█", width)
+	}
+
+	layout := "2006-01-02 15:04"
+	if interval < time.Minute {
+		layout = "2006-01-02 15:04:05"
+	}
+	indent := strings.Repeat(" ", len(layout)+2)
+	for _, b := range buckets {
+		fmt.Fprintf(out, "%s  %s %d\n", b.Start.In(loc).Format(layout), bar(b.Count), b.Count)
+
+		groups := make([]string, 0, len(b.Groups))
+		for group := range b.Groups {
+			groups = append(groups, group)
+		}
+		sort.Slice(groups, func(i, j int) bool {
+			if b.Groups[groups[i]] != b.Groups[groups[j]] {
+				return b.Groups[groups[i]] > b.Groups[groups[j]]
+			}
+			return groups[i] < groups[j]
+		})
+		for _, group := range groups {
+			fmt.Fprintf(out, "%s%-*s  %s %d\n", indent, labelWidth, group, bar(b.Groups[group]), b.Groups[group])
+		}
+	}
+	return nil
+}
+
+var queryHistogramCommand = &cobra.Command{
+	Use:   "histogram",
+	Short: "Chart the number of log entries per time interval",
+	Long: `Bucket the log entries matching the search per time interval and print a bar
+chart, to spot spikes without leaving the terminal.
+
+Up to --size entries are bucketed (10000 by default). With --group-by, each
+bucket is split by the value of a field.
+
+Examples:
+  # Entries per 10 minutes over the last 6 hours
+  logviewer query histogram -i prod-logs --last 6h --interval 10m
+
+  # Split by level
+  logviewer query histogram -i prod-logs --last 1h --interval 5m --group-by level
+
+  # The buckets as JSON
+  logviewer query histogram -i prod-logs --last 1h --json`,
+	PreRun: onCommandStart,
+	Run: func(_ *cobra.Command, _ []string) {
+		interval, err := parseHistogramInterval(histogramInterval)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+
+		logClient, search, err := resolveLogClient()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+
+		if err := RunQueryHistogram(os.Stdout, os.Stderr, logClient, search, interval, histogramGroupBy, jsonOutput, printer.DisplayLocation()); err != nil {
 			fmt.Fprintln(os.Stderr, "error:", err)
 			os.Exit(1)
 		}
@@ -605,7 +1043,14 @@ var queryCommand = &cobra.Command{
 	Short:  "Query a login system for logs and available fields",
 	PreRun: onCommandStart,
 	Run: func(cmd *cobra.Command, _ []string) {
-		cmd.Println("Please use 'logviewer query log' to stream logs, 'logviewer query field' to inspect fields, or 'logviewer query values' to get distinct values.")
+		if decodeToken != "" {
+			if err := RunDecodeToken(os.Stdout, decodeToken); err != nil {
+				fmt.Fprintln(os.Stderr, "error:", err)
+				os.Exit(1)
+			}
+			return
+		}
+		cmd.Println("Please use 'logviewer query log' to stream logs, 'logviewer query field' to inspect fields, 'logviewer query values' to get distinct values, 'logviewer query count' to count entries, or 'logviewer query histogram' to chart them over time.")
 		_ = cmd.Help()
 	},
 }
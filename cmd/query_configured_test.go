@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
@@ -12,8 +13,11 @@ import (
 
 // MockSearchFactory for testing ConfiguredLogClient
 type MockSearchFactory struct {
-	OnGetSearchResult func(ctx context.Context, contextID string, search client.LogSearch) (client.LogSearchResult, error)
-	OnGetFieldValues  func(ctx context.Context, contextID string, search client.LogSearch, fields []string) (map[string][]string, error)
+	OnGetSearchResult  func(ctx context.Context, contextID string, search client.LogSearch) (client.LogSearchResult, error)
+	OnGetFieldValues   func(ctx context.Context, contextID string, search client.LogSearch, fields []string) (map[string][]string, error)
+	OnGetFieldFacets   func(ctx context.Context, contextID string, search client.LogSearch, fields []string) (map[string]map[string]int, error)
+	OnGetCount         func(ctx context.Context, contextID string, search client.LogSearch) (int, error)
+	OnGetSearchContext func(ctx context.Context, contextID string, search client.LogSearch) (*config.SearchContext, error)
 }
 
 func (m *MockSearchFactory) GetSearchResult(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (client.LogSearchResult, error) {
@@ -24,6 +28,9 @@ func (m *MockSearchFactory) GetSearchResult(ctx context.Context, contextID strin
 }
 
 func (m *MockSearchFactory) GetSearchContext(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (*config.SearchContext, error) {
+	if m.OnGetSearchContext != nil {
+		return m.OnGetSearchContext(ctx, contextID, logSearch)
+	}
 	return nil, nil
 }
 
@@ -34,6 +41,37 @@ func (m *MockSearchFactory) GetFieldValues(ctx context.Context, contextID string
 	return nil, nil
 }
 
+func (m *MockSearchFactory) GetFields(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (ty.UniSet[string], error) {
+	result, err := m.GetSearchResult(ctx, contextID, inherits, logSearch, runtimeVars)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	fields, _, err := result.GetFields(ctx)
+	return fields, err
+}
+
+func (m *MockSearchFactory) GetFieldNames(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) ([]string, error) {
+	result, err := m.GetSearchResult(ctx, contextID, inherits, logSearch, runtimeVars)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return client.GetFieldNames(ctx, result)
+}
+
+func (m *MockSearchFactory) GetFieldFacets(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, fields []string, runtimeVars map[string]string) (map[string]map[string]int, error) {
+	if m.OnGetFieldFacets != nil {
+		return m.OnGetFieldFacets(ctx, contextID, logSearch, fields)
+	}
+	return nil, nil
+}
+
+func (m *MockSearchFactory) GetCount(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (int, error) {
+	if m.OnGetCount != nil {
+		return m.OnGetCount(ctx, contextID, logSearch)
+	}
+	return 0, nil
+}
+
 type MockResult struct {
 	Entries []client.LogEntry
 	Fields  ty.UniSet[string]
@@ -109,6 +147,31 @@ func TestConfiguredLogClient_GetValues(t *testing.T) {
 	assert.ElementsMatch(t, []string{"val-from-ctx1", "val-from-ctx2"}, values)
 }
 
+func TestConfiguredLogClient_Count(t *testing.T) {
+	counts := map[string]int{"ctx1": 3, "ctx2": 4}
+	mockFactory := &MockSearchFactory{
+		OnGetCount: func(ctx context.Context, contextID string, search client.LogSearch) (int, error) {
+			if contextID == "broken" {
+				return 0, errors.New("backend down")
+			}
+			return counts[contextID], nil
+		},
+	}
+
+	cli := &ConfiguredLogClient{
+		Factory:    mockFactory,
+		ContextIDs: []string{"ctx1", "ctx2"},
+	}
+
+	count, err := cli.Count(context.Background(), client.LogSearch{})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, count)
+
+	cli.ContextIDs = append(cli.ContextIDs, "broken")
+	_, err = cli.Count(context.Background(), client.LogSearch{})
+	assert.ErrorContains(t, err, "context broken: backend down")
+}
+
 func TestResolveLogClient_AdHoc(t *testing.T) {
 	// Setup global flags for ad-hoc
 	cmd = "tail -f"
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"sort"
+	"strconv"
 	"sync"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
@@ -20,6 +22,8 @@ type ConfiguredLogClient struct {
 	ContextIDs  []string
 	Inherits    []string
 	RuntimeVars map[string]string
+	// Concurrency limits the contexts queried at once (client.DefaultConcurrency when 0)
+	Concurrency int
 }
 
 func (c *ConfiguredLogClient) Query(ctx context.Context, search client.LogSearch) ([]client.LogEntry, error) {
@@ -38,31 +42,31 @@ func (c *ConfiguredLogClient) Query(ctx context.Context, search client.LogSearch
 	if err != nil {
 		return nil, err
 	}
-	var wg sync.WaitGroup
-
-	for _, contextID := range c.ContextIDs {
-		wg.Add(1)
-		go func(cid string) {
-			defer wg.Done()
-			reqCopy := search
-			// Deep copy maps
-			reqCopy.Options = ty.MergeM(make(ty.MI, len(search.Options)+1), search.Options)
-			reqCopy.Options["__context_id__"] = cid
-			reqCopy.Fields = ty.MergeM(make(ty.MS, len(search.Fields)), search.Fields)
-			reqCopy.FieldsCondition = ty.MergeM(make(ty.MS, len(search.FieldsCondition)), search.FieldsCondition)
-		
-			if search.Variables != nil {
-				reqCopy.Variables = make(map[string]client.VariableDefinition, len(search.Variables))
-				for k, v := range search.Variables {
-					reqCopy.Variables[k] = v
-				}
+
+	err = client.FanOut(ctx, c.ContextIDs, c.Concurrency, func(cid string) {
+		reqCopy := search
+		// Deep copy maps
+		reqCopy.Options = ty.MergeM(make(ty.MI, len(search.Options)+1), search.Options)
+		reqCopy.Options["__context_id__"] = cid
+		reqCopy.Fields = ty.MergeM(make(ty.MS, len(search.Fields)), search.Fields)
+		reqCopy.FieldsCondition = ty.MergeM(make(ty.MS, len(search.FieldsCondition)), search.FieldsCondition)
+
+		if search.Variables != nil {
+			reqCopy.Variables = make(map[string]client.VariableDefinition, len(search.Variables))
+			for k, v := range search.Variables {
+				reqCopy.Variables[k] = v
 			}
+		}
 
-			sr, err := c.Factory.GetSearchResult(ctx, cid, c.Inherits, reqCopy, c.RuntimeVars)
-			multiResult.Add(sr, err)
-		}(contextID)
+		sr, err := c.Factory.GetSearchResult(ctx, cid, c.Inherits, reqCopy, c.RuntimeVars)
+		multiResult.AddContext(cid, sr, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if multiResult.Failures.AllFailed() {
+		return nil, multiResult.ContextErr()
 	}
-	wg.Wait()
 
 	return consumeSearchResult(ctx, multiResult)
 }
@@ -84,59 +88,56 @@ func (c *ConfiguredLogClient) GetFields(ctx context.Context, search client.LogSe
 	// Similar fan-out logic for fields
 	allFields := make(ty.UniSet[string])
 	var mu sync.Mutex
-	var wg sync.WaitGroup
 	var hasError bool
 
-	for _, contextID := range c.ContextIDs {
-		wg.Add(1)
-		go func(cid string) {
-			defer wg.Done()
-			// Note: SearchFactory doesn't expose GetFields directly with runtimeVars,
-			// it uses GetSearchResult -> GetFields.
-			reqCopy := search
-			reqCopy.Options = ty.MergeM(make(ty.MI), search.Options)
-			reqCopy.Options["__context_id__"] = cid
-			
-			sr, err := c.Factory.GetSearchResult(ctx, cid, c.Inherits, reqCopy, c.RuntimeVars)
-			if err != nil {
-				mu.Lock()
-				hasError = true
-				mu.Unlock()
-				return
-			}
-			
-			fields, ch, err := sr.GetFields(ctx)
-			if err != nil {
-				mu.Lock()
-				hasError = true
-				mu.Unlock()
-				return
-			}
-			
+	fanErr := client.FanOut(ctx, c.ContextIDs, c.Concurrency, func(cid string) {
+		// Note: SearchFactory doesn't expose GetFields directly with runtimeVars,
+		// it uses GetSearchResult -> GetFields.
+		reqCopy := search
+		reqCopy.Options = ty.MergeM(make(ty.MI), search.Options)
+		reqCopy.Options["__context_id__"] = cid
+
+		sr, err := c.Factory.GetSearchResult(ctx, cid, c.Inherits, reqCopy, c.RuntimeVars)
+		if err != nil {
 			mu.Lock()
-			if fields != nil {
-				for k, v := range fields {
-					for _, val := range v {
-						allFields.Add(k, val)
-					}
+			hasError = true
+			mu.Unlock()
+			return
+		}
+
+		fields, ch, err := sr.GetFields(ctx)
+		if err != nil {
+			mu.Lock()
+			hasError = true
+			mu.Unlock()
+			return
+		}
+
+		mu.Lock()
+		if fields != nil {
+			for k, v := range fields {
+				for _, val := range v {
+					allFields.Add(k, val)
 				}
 			}
-			mu.Unlock()
+		}
+		mu.Unlock()
 
-			if ch != nil {
-				for batch := range ch {
-					mu.Lock()
-					for k, v := range batch {
-						for _, val := range v {
-							allFields.Add(k, val)
-						}
+		if ch != nil {
+			for batch := range ch {
+				mu.Lock()
+				for k, v := range batch {
+					for _, val := range v {
+						allFields.Add(k, val)
 					}
-					mu.Unlock()
 				}
+				mu.Unlock()
 			}
-		}(contextID)
+		}
+	})
+	if fanErr != nil {
+		return nil, fanErr
 	}
-	wg.Wait()
 
 	if hasError && len(allFields) == 0 {
 		return nil, errors.New("failed to get fields from all contexts")
@@ -149,31 +150,28 @@ func (c *ConfiguredLogClient) GetValues(ctx context.Context, search client.LogSe
 	// Fan-out for values
 	allValues := make(map[string]struct{})
 	var mu sync.Mutex
-	var wg sync.WaitGroup
 	var hasError bool
 
-	for _, contextID := range c.ContextIDs {
-		wg.Add(1)
-		go func(cid string) {
-			defer wg.Done()
-			valsMap, err := c.Factory.GetFieldValues(ctx, cid, c.Inherits, search, []string{field}, c.RuntimeVars)
-			if err != nil {
-				mu.Lock()
-				hasError = true
-				mu.Unlock()
-				return
-			}
-			
-			if vals, ok := valsMap[field]; ok {
-				mu.Lock()
-				for _, v := range vals {
-					allValues[v] = struct{}{}
-				}
-				mu.Unlock()
+	fanErr := client.FanOut(ctx, c.ContextIDs, c.Concurrency, func(cid string) {
+		valsMap, err := c.Factory.GetFieldValues(ctx, cid, c.Inherits, search, []string{field}, c.RuntimeVars)
+		if err != nil {
+			mu.Lock()
+			hasError = true
+			mu.Unlock()
+			return
+		}
+
+		if vals, ok := valsMap[field]; ok {
+			mu.Lock()
+			for _, v := range vals {
+				allValues[v] = struct{}{}
 			}
-		}(contextID)
+			mu.Unlock()
+		}
+	})
+	if fanErr != nil {
+		return nil, fanErr
 	}
-	wg.Wait()
 
 	if hasError && len(allValues) == 0 {
 		return nil, errors.New("failed to get field values")
@@ -187,12 +185,44 @@ func (c *ConfiguredLogClient) GetValues(ctx context.Context, search client.LogSe
 	return result, nil
 }
 
+// Count sums the entries matching the search in each context. A context
+// failing fails the count, since a partial sum would look like a real one.
+func (c *ConfiguredLogClient) Count(ctx context.Context, search client.LogSearch) (int, error) {
+	total := 0
+	var errs []error
+	var mu sync.Mutex
+
+	fanErr := client.FanOut(ctx, c.ContextIDs, c.Concurrency, func(cid string) {
+		count, err := c.Factory.GetCount(ctx, cid, c.Inherits, search, c.RuntimeVars)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("context %s: %w", cid, err))
+			return
+		}
+		total += count
+	})
+	if fanErr != nil {
+		return 0, fanErr
+	}
+	if len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+	return total, nil
+}
+
 // resolveLogClient determines the appropriate LogClient based on flags/config.
 func resolveLogClient() (client.LogClient, client.LogSearch, error) {
-	searchRequest := buildSearchRequest()
+	searchRequest, err := buildSearchRequest()
+	if err != nil {
+		return nil, searchRequest, err
+	}
 
 	// 1. Ad-Hoc
 	if isAdHocQuery() {
+		if err := applyDisplayTimezone(nil); err != nil {
+			return nil, searchRequest, err
+		}
 		backend, err := getAdHocLogClient(&searchRequest)
 		if err != nil {
 			return nil, searchRequest, err
@@ -209,6 +239,9 @@ func resolveLogClient() (client.LogClient, client.LogSearch, error) {
 	if err != nil {
 		return nil, searchRequest, err
 	}
+	if err := applyDisplayTimezone(cfg); err != nil {
+		return nil, searchRequest, err
+	}
 
 	backendFactory, err := factory.GetLogBackendFactory(cfg.Clients)
 	if err != nil {
@@ -226,26 +259,51 @@ func resolveLogClient() (client.LogClient, client.LogSearch, error) {
 	if len(resolvedContextIDs) == 0 {
 		return nil, searchRequest, errors.New("no context specified; use -i to select a context")
 	}
+	if err := checkRequiredVars(cfg, resolvedContextIDs, inherits, runtimeVars); err != nil {
+		return nil, searchRequest, err
+	}
 
 	return &ConfiguredLogClient{
 		Factory:     searchFactory,
 		ContextIDs:  resolvedContextIDs,
 		Inherits:    inherits,
 		RuntimeVars: runtimeVars,
+		Concurrency: concurrency,
 	}, searchRequest, nil
 }
 
-// RunQueryValues executes the 'query values' logic using a LogClient.
-func RunQueryValues(out io.Writer, cli client.LogClient, search client.LogSearch, fields []string, asJSON bool) error {
+// RunQueryValues executes the 'query values' logic using a LogClient. With
+// numeric, the fields whose values are mostly numbers are summarized from the
+// entries of the search instead of listing their distinct values.
+func RunQueryValues(out io.Writer, cli client.LogClient, search client.LogSearch, fields []string, asJSON, numeric bool) error {
 	ctx := context.Background()
-	results := make(map[string][]string)
+	results := make(map[string]any)
+	values := make(map[string][]string)
+	stats := make(map[string]client.NumericStats)
+
+	var entries []client.LogEntry
+	if numeric {
+		var err error
+		entries, err = cli.Query(ctx, search)
+		if err != nil {
+			return fmt.Errorf("error querying entries for numeric fields: %w", err)
+		}
+	}
 
 	for _, field := range fields {
-		values, err := cli.GetValues(ctx, search, field)
+		if numeric {
+			if summary, ok := client.SummarizeNumeric(client.FieldOccurrences(entries, field)); ok {
+				stats[field] = summary
+				results[field] = summary
+				continue
+			}
+		}
+		fieldValues, err := cli.GetValues(ctx, search, field)
 		if err != nil {
 			return fmt.Errorf("error getting values for field %s: %w", field, err)
 		}
-		results[field] = values
+		values[field] = fieldValues
+		results[field] = fieldValues
 	}
 
 	if asJSON {
@@ -256,19 +314,59 @@ func RunQueryValues(out io.Writer, cli client.LogClient, search client.LogSearch
 
 	// Text output
 	for _, field := range fields {
-		values := results[field]
-		if len(values) == 0 {
+		if summary, ok := stats[field]; ok {
+			fmt.Fprintf(out, "%s (numeric, %d values)\n", field, summary.Count)
+			fmt.Fprintf(out, "    min %s  max %s  avg %s\n", formatStat(summary.Min), formatStat(summary.Max), formatStat(summary.Avg))
+			fmt.Fprintf(out, "    p50 %s  p95 %s  p99 %s\n", formatStat(summary.P50), formatStat(summary.P95), formatStat(summary.P99))
+			if summary.NonNumeric > 0 {
+				fmt.Fprintf(out, "    (%d non-numeric values ignored)\n", summary.NonNumeric)
+			}
+			continue
+		}
+		fieldValues := values[field]
+		if len(fieldValues) == 0 {
 			fmt.Fprintf(out, "%s \n    (no values found)\n", field)
 			continue
 		}
 		fmt.Fprintf(out, "%s \n", field)
-		for _, v := range values {
+		for _, v := range fieldValues {
 			fmt.Fprintf(out, "    %s\n", v)
 		}
 	}
 	return nil
 }
 
+// RunQueryCount prints the number of entries matching the search, as
+// {"count": N} with asJSON.
+func RunQueryCount(out io.Writer, cli client.LogClient, search client.LogSearch, asJSON bool) error {
+	count, err := cli.Count(context.Background(), search)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return json.NewEncoder(out).Encode(map[string]int{"count": count})
+	}
+	_, err = fmt.Fprintln(out, count)
+	return err
+}
+
+// formatStat prints a stat with at most two decimals.
+func formatStat(n float64) string {
+	return strconv.FormatFloat(math.Round(n*100)/100, 'f', -1, 64)
+}
+
+// RunDecodeToken prints the content of a page token as JSON. Tokens of older
+// builds print as a version 0 token with only their cursor.
+func RunDecodeToken(out io.Writer, token string) error {
+	t, err := client.DecodePageToken(token)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
 // RunQueryField executes the 'query field' logic using a LogClient.
 func RunQueryField(out io.Writer, cli client.LogClient, search client.LogSearch, asJSON bool) error {
 	ctx := context.Background()
@@ -299,4 +397,4 @@ func RunQueryField(out io.Writer, cli client.LogClient, search client.LogSearch,
 		}
 	}
 	return nil
-}
\ No newline at end of file
+}
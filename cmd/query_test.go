@@ -2,14 +2,21 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
+	"github.com/bascanada/logviewer/pkg/log/printer"
 	"github.com/bascanada/logviewer/pkg/ty"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRunQueryValues(t *testing.T) {
@@ -31,7 +38,7 @@ func TestRunQueryValues(t *testing.T) {
 
 	t.Run("text output", func(t *testing.T) {
 		var buf bytes.Buffer
-		err := RunQueryValues(&buf, mockClient, search, []string{"level"}, false)
+		err := RunQueryValues(&buf, mockClient, search, []string{"level"}, false, false)
 		assert.NoError(t, err)
 
 		output := buf.String()
@@ -42,7 +49,7 @@ func TestRunQueryValues(t *testing.T) {
 
 	t.Run("json output", func(t *testing.T) {
 		var buf bytes.Buffer
-		err := RunQueryValues(&buf, mockClient, search, []string{"app"}, true)
+		err := RunQueryValues(&buf, mockClient, search, []string{"app"}, true, false)
 		assert.NoError(t, err)
 
 		var result map[string][]string
@@ -55,7 +62,7 @@ func TestRunQueryValues(t *testing.T) {
 
 	t.Run("multiple fields", func(t *testing.T) {
 		var buf bytes.Buffer
-		err := RunQueryValues(&buf, mockClient, search, []string{"level", "app"}, false)
+		err := RunQueryValues(&buf, mockClient, search, []string{"level", "app"}, false, false)
 		assert.NoError(t, err)
 
 		output := buf.String()
@@ -64,6 +71,67 @@ func TestRunQueryValues(t *testing.T) {
 	})
 }
 
+func TestRunQueryValues_Numeric(t *testing.T) {
+	mockClient := &client.MockLogClient{
+		OnQuery: func(_ client.LogSearch) ([]client.LogEntry, error) {
+			return []client.LogEntry{
+				{Fields: ty.MI{"latency_ms": 10, "app": "api"}},
+				{Fields: ty.MI{"latency_ms": "20", "app": "api"}},
+				{Fields: ty.MI{"latency_ms": 40.5, "app": "web"}},
+				{Fields: ty.MI{"latency_ms": "timeout"}},
+			}, nil
+		},
+		OnValues: func(_ client.LogSearch, field string) ([]string, error) {
+			if field == "app" {
+				return []string{"api", "web"}, nil
+			}
+			return []string{}, nil
+		},
+	}
+
+	t.Run("text output", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := RunQueryValues(&buf, mockClient, client.LogSearch{}, []string{"latency_ms", "app", "missing"}, false, true)
+		assert.NoError(t, err)
+
+		output := buf.String()
+		assert.Contains(t, output, "latency_ms (numeric, 3 values)")
+		assert.Contains(t, output, "min 10  max 40.5  avg 23.5")
+		assert.Contains(t, output, "p50 20  p95 40.5  p99 40.5")
+		assert.Contains(t, output, "(1 non-numeric values ignored)")
+		assert.Contains(t, output, "app \n    api\n    web\n", "other fields keep their values")
+		assert.Contains(t, output, "missing \n    (no values found)")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := RunQueryValues(&buf, mockClient, client.LogSearch{}, []string{"latency_ms", "app"}, true, true)
+		assert.NoError(t, err)
+
+		var result struct {
+			Latency client.NumericStats `json:"latency_ms"`
+			App     []string            `json:"app"`
+		}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+		assert.Equal(t, 3, result.Latency.Count)
+		assert.Equal(t, 1, result.Latency.NonNumeric)
+		assert.Equal(t, 40.5, result.Latency.P99)
+		assert.Equal(t, []string{"api", "web"}, result.App)
+	})
+}
+
+func TestRunDecodeToken(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, RunDecodeToken(&buf, client.EncodePageToken("opensearch", "50")))
+	var token client.PageToken
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &token))
+	assert.Equal(t, client.PageToken{V: 1, Backend: "opensearch", Cursor: "50"}, token)
+
+	buf.Reset()
+	assert.NoError(t, RunDecodeToken(&buf, "50"))
+	assert.Contains(t, buf.String(), `"v": 0`, "raw cursors of older builds")
+}
+
 func TestMergeFilterWithAnd(t *testing.T) {
 	var existing *client.Filter
 	added := &client.Filter{Field: "f1", Value: "v1"}
@@ -107,17 +175,92 @@ func TestParseFlags(t *testing.T) {
 	// parseTimeFlags
 	from = "2023-01-01"
 	to = "2023-01-02"
-	last = "1h"
 	parseTimeFlags(req)
 	assert.True(t, req.Range.Gte.Set)
 	assert.True(t, req.Range.Lte.Set)
-	assert.Equal(t, "1h", req.Range.Last.Value)
+
+	// --last can't be combined with --from, only with --to
+	timeReq := &client.LogSearch{}
+	from = ""
+	last = "1h"
+	defer func() { to, last = "", "" }()
+	parseTimeFlags(timeReq)
+	assert.Equal(t, "1h", timeReq.Range.Last.Value)
+	assert.True(t, timeReq.Range.Lte.Set)
 
 	// parseFieldFlags
 	fields = []string{"level=ERROR", "msg~=err.*"}
-	parseFieldFlags(req)
+	fieldsLogic = "OR"
+	defer func() { fieldsLogic = "" }()
+	assert.NoError(t, parseFieldFlags(req))
 	assert.Equal(t, "ERROR", req.Fields["level"])
 	assert.NotNil(t, req.Filter)
+	assert.Equal(t, "or", req.FieldsLogic.Value)
+}
+
+func TestParseFieldsFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("mixed syntax with comments and blanks", func(t *testing.T) {
+		path := write("filters.txt", "# shared filters\n\nlevel=ERROR\nlatency_ms>=500\n  # indented comment\nmsg=time*\n")
+
+		filter, err := parseFieldsFile(path, ty.MS{"msg": "wildcard"})
+		assert.NoError(t, err)
+		assert.Equal(t, client.LogicAnd, filter.Logic)
+		assert.Len(t, filter.Filters, 3)
+		assert.Equal(t, client.Filter{Field: "level", Op: operator.Equals, Value: "ERROR"}, filter.Filters[0])
+		assert.Equal(t, operator.Gte, filter.Filters[1].Op)
+		assert.Equal(t, operator.Wildcard, filter.Filters[2].Op)
+	})
+
+	t.Run("parse errors report line numbers", func(t *testing.T) {
+		path := write("bad.txt", "level=ERROR\nnot a condition\n# ok\n!=oops\n")
+
+		_, err := parseFieldsFile(path, ty.MS{})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "bad.txt:2:")
+		assert.Contains(t, err.Error(), "bad.txt:4:")
+	})
+
+	t.Run("empty file yields no filter", func(t *testing.T) {
+		path := write("empty.txt", "# nothing here\n\n")
+
+		filter, err := parseFieldsFile(path, ty.MS{})
+		assert.NoError(t, err)
+		assert.Nil(t, filter)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := parseFieldsFile(filepath.Join(dir, "missing.txt"), ty.MS{})
+		assert.Error(t, err)
+	})
+
+	t.Run("combined with -f flags using AND", func(t *testing.T) {
+		path := write("combined.txt", "level=WARN\n")
+		fields = []string{"level=ERROR"}
+		fieldsFile = path
+		defer func() { fields = nil; fieldsFile = "" }()
+
+		req := &client.LogSearch{Fields: ty.MS{}, FieldsCondition: ty.MS{}}
+		assert.NoError(t, parseFieldFlags(req))
+
+		assert.Equal(t, "ERROR", req.Fields["level"])
+		assert.Equal(t, "WARN", req.Filter.Value)
+		assert.Equal(t, client.LogicAnd, req.GetEffectiveFilter().Logic)
+	})
+
+	t.Run("errors are returned to the command", func(t *testing.T) {
+		fieldsFile = write("invalid.txt", "!=oops\n")
+		defer func() { fieldsFile = "" }()
+
+		_, err := buildSearchRequest()
+		assert.ErrorContains(t, err, "invalid.txt:1:")
+	})
 }
 
 func TestParseRuntimeVars(t *testing.T) {
@@ -129,6 +272,33 @@ func TestParseRuntimeVars(t *testing.T) {
 	assert.Equal(t, "v2", res["k2"])
 }
 
+func TestCheckRequiredVars(t *testing.T) {
+	cfg := &config.ContextConfig{
+		Searches: config.Searches{
+			"traced": client.LogSearch{Variables: map[string]client.VariableDefinition{
+				"traceId": {Required: true, Description: "Trace to follow"},
+			}},
+		},
+		Contexts: config.Contexts{
+			"api": config.SearchContext{Search: client.LogSearch{Variables: map[string]client.VariableDefinition{
+				"LV_TEST_TENANT": {Required: true},
+				"env":            {Required: true, Default: "prod"},
+			}}},
+		},
+	}
+
+	err := checkRequiredVars(cfg, []string{"api"}, []string{"traced"}, nil)
+	require.ErrorIs(t, err, config.ErrMissingVariables)
+	assert.Contains(t, err.Error(), "for context api: LV_TEST_TENANT, traceId (Trace to follow)")
+
+	t.Setenv("LV_TEST_TENANT", "acme")
+	err = checkRequiredVars(cfg, []string{"api"}, []string{"traced"}, map[string]string{"traceId": "abc"})
+	assert.NoError(t, err, "set by --var and the environment, env by its default")
+
+	err = checkRequiredVars(cfg, []string{"missing"}, nil, nil)
+	assert.ErrorIs(t, err, config.ErrContextNotFound)
+}
+
 func TestResolveContextIDsFromConfig(t *testing.T) {
 	cfg := &config.ContextConfig{
 		CurrentContext: "ctx1",
@@ -190,3 +360,144 @@ func TestRunQueryField(t *testing.T) {
 		assert.Equal(t, []string{"foo bar"}, fields["message"])
 	})
 }
+
+func TestRunQueryCount(t *testing.T) {
+	mockClient := &client.MockLogClient{
+		OnCount: func(search client.LogSearch) (int, error) {
+			return 42, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, RunQueryCount(&buf, mockClient, client.LogSearch{}, false))
+	assert.Equal(t, "42\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, RunQueryCount(&buf, mockClient, client.LogSearch{}, true))
+	assert.JSONEq(t, `{"count": 42}`, buf.String())
+}
+
+func TestParseHistogramInterval(t *testing.T) {
+	interval, err := parseHistogramInterval("10m")
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, interval)
+
+	for _, bad := range []string{"", "0s", "-5m", "10:00", "often"} {
+		_, err := parseHistogramInterval(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestBucketEntries(t *testing.T) {
+	at := func(clock string) time.Time {
+		ts, _ := time.Parse(time.RFC3339, "2024-01-02T"+clock+"Z")
+		return ts
+	}
+	entries := []client.LogEntry{
+		{Timestamp: at("10:31:00"), Level: "INFO"},
+		{Timestamp: at("10:02:00"), Level: "ERROR"},
+		{Timestamp: at("10:09:59"), Fields: ty.MI{"level": "WARN"}},
+		{Timestamp: at("10:00:00")},
+		{Message: "no timestamp"},
+	}
+
+	buckets, err := bucketEntries(entries, 10*time.Minute, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []HistogramBucket{
+		{Start: at("10:00:00"), Count: 3},
+		{Start: at("10:10:00"), Count: 0},
+		{Start: at("10:20:00"), Count: 0},
+		{Start: at("10:30:00"), Count: 1},
+	}, buckets, "empty buckets fill the gaps")
+
+	buckets, err = bucketEntries(entries, 10*time.Minute, "level")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"ERROR": 1, "WARN": 1, histogramNoGroup: 1}, buckets[0].Groups)
+	assert.Nil(t, buckets[1].Groups)
+
+	_, err = bucketEntries(entries, time.Millisecond, "")
+	assert.ErrorContains(t, err, "use a larger interval")
+
+	buckets, err = bucketEntries(nil, time.Minute, "")
+	assert.NoError(t, err)
+	assert.Empty(t, buckets)
+}
+
+func TestRunQueryHistogram(t *testing.T) {
+	ts, _ := time.Parse(time.RFC3339, "2024-01-02T10:00:00Z")
+	mockClient := &client.MockLogClient{
+		OnQuery: func(search client.LogSearch) ([]client.LogEntry, error) {
+			return []client.LogEntry{
+				{Timestamp: ts, Level: "ERROR"},
+				{Timestamp: ts.Add(time.Minute), Level: "INFO"},
+				{Timestamp: ts.Add(2 * time.Minute), Level: "INFO"},
+				{Timestamp: ts.Add(10 * time.Minute), Level: "INFO"},
+			}, nil
+		},
+	}
+
+	t.Run("prints a bar per bucket and group", func(t *testing.T) {
+		var out, warnings bytes.Buffer
+		err := RunQueryHistogram(&out, &warnings, mockClient, client.LogSearch{}, 10*time.Minute, "level", false, time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, histogramDefaultSize, mockClient.LastSearch.Size.Value)
+		assert.Empty(t, warnings.String())
+
+		lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+		assert.Len(t, lines, 5)
+		assert.Equal(t, "2024-01-02 10:00  "+strings.Repeat("█", histogramBarWidth)+" 3", lines[0])
+		assert.Contains(t, lines[1], "INFO ")
+		assert.True(t, strings.HasSuffix(lines[1], " 2"), lines[1])
+		assert.Contains(t, lines[2], "ERROR")
+		assert.True(t, strings.HasPrefix(lines[3], "2024-01-02 10:10  "), lines[3])
+	})
+
+	t.Run("outputs JSON buckets", func(t *testing.T) {
+		var out bytes.Buffer
+		err := RunQueryHistogram(&out, &out, mockClient, client.LogSearch{}, 10*time.Minute, "", true, time.UTC)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"start":"2024-01-02T10:00:00Z","count":3},{"start":"2024-01-02T10:10:00Z","count":1}]`, out.String())
+	})
+
+	t.Run("warns when the size cuts the entries", func(t *testing.T) {
+		var out, warnings bytes.Buffer
+		search := client.LogSearch{}
+		search.Size.S(4)
+		err := RunQueryHistogram(&out, &warnings, mockClient, search, time.Hour, "", false, time.UTC)
+		assert.NoError(t, err)
+		assert.Contains(t, warnings.String(), "stops at 4 entries")
+	})
+}
+
+func TestApplyDisplayTimezone(t *testing.T) {
+	defer printer.SetDisplayLocation(nil)
+	defer func() { timezone = "" }()
+	cfg := &config.ContextConfig{Display: config.DisplayConfig{Timezone: ty.OptWrap("America/Toronto")}}
+
+	assert.NoError(t, applyDisplayTimezone(cfg))
+	assert.Equal(t, "America/Toronto", printer.DisplayLocation().String())
+
+	timezone = "utc"
+	assert.NoError(t, applyDisplayTimezone(cfg))
+	assert.Equal(t, time.UTC, printer.DisplayLocation(), "the flag wins over the config")
+
+	timezone = "Mars/Olympus"
+	assert.ErrorContains(t, applyDisplayTimezone(nil), "invalid timezone")
+}
+
+func TestResolveSearch_AdHocContextID(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(logFile, []byte("hello\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cmd = "cat " + logFile
+	defer func() { cmd = "" }()
+
+	result, err := resolveSearch()
+	assert.NoError(t, err)
+	entries, _, err := result.GetEntries(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "local", entries[0].ContextID)
+	}
+}
@@ -5,6 +5,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -41,7 +42,14 @@ var rootCmd = &cobra.Command{
 
 // Execute runs the root command.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	shutdownTracing, err := setupTracing(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: tracing disabled: %v\n", err)
+		shutdownTracing = func() {}
+	}
+	err = rootCmd.Execute()
+	shutdownTracing()
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -53,6 +61,8 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&logger.Path, "logging-path", "", "file to output logs of the application")
 	rootCmd.PersistentFlags().StringVar(&logger.Level, "logging-level", "", "logging level to output INFO WARN ERROR DEBUG TRACE")
 	rootCmd.PersistentFlags().BoolVar(&logger.Stdout, "logging-stdout", false, "output appplication log in the stdout")
+	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "Do not warn about filters a backend applies client-side")
+	rootCmd.PersistentFlags().StringVar(&timezone, "timezone", "", "Zone timestamps are shown in: an IANA name (e.g. America/Toronto), UTC or local (default: display.timezone of the config, else local)")
 
 	// Register completion for --logging-level flag
 	_ = rootCmd.RegisterFlagCompletionFunc("logging-level", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracingConfigured(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_SDK_DISABLED", "")
+	assert.False(t, tracingConfigured())
+
+	shutdown, err := setupTracing(context.Background())
+	assert.NoError(t, err)
+	shutdown() // no-op without an endpoint
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://localhost:4318/v1/traces")
+	assert.True(t, tracingConfigured())
+
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	assert.False(t, tracingConfigured())
+}
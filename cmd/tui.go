@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
 	"github.com/bascanada/logviewer/pkg/log/factory"
 	"github.com/bascanada/logviewer/pkg/tui"
 	tea "github.com/charmbracelet/bubbletea"
@@ -40,12 +41,15 @@ Examples:
   logviewer tui -i prod-logs -f level=ERROR --last 1h
 
   # Launch TUI with query
-  logviewer tui -i prod-logs -q "level=ERROR AND service=api"`,
+  logviewer tui -i prod-logs -q "level=ERROR AND service=api"
+
+  # Open tabs are saved on quit and restored when no -i is given
+  logviewer tui --no-restore`,
 	PreRun: onCommandStart,
 	Run:    runTUI,
 }
 
-func runTUI(_ *cobra.Command, _ []string) {
+func runTUI(cmd *cobra.Command, _ []string) {
 	// Load configuration
 	cfg, _, err := loadConfig(configPath)
 	if err != nil {
@@ -67,15 +71,34 @@ func runTUI(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	if err := applyDisplayTimezone(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Build search request from flags
-	searchRequest := buildSearchRequest()
+	searchRequest, err := buildSearchRequest()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Get runtime variables
 	runtimeVars := parseRuntimeVars()
 
-	// Resolve context IDs
+	// Restore the previous session when asked, or by default when no context was given
+	sessionPath, err := tui.DefaultSessionPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: session persistence disabled: %v\n", err)
+	}
+	session := loadTUISession(sessionPath, cfg)
+
+	// Resolve context IDs; restored tabs replace the current context default
 	resolvedContextIDs := resolveContextIDsFromConfig(cfg)
-	if len(resolvedContextIDs) == 0 {
+	if session != nil && len(contextIDs) == 0 {
+		resolvedContextIDs = nil
+	}
+	if len(resolvedContextIDs) == 0 && session == nil {
 		// If no context specified, try to show available contexts
 		if len(cfg.Contexts) > 0 {
 			fmt.Fprintln(os.Stderr, "No context specified. Available contexts:")
@@ -91,11 +114,31 @@ func runTUI(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	if err := checkRequiredVars(cfg, resolvedContextIDs, inherits, runtimeVars); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create TUI model
 	model := tui.New(cfg, clientFactory, searchFactory)
 	model.RuntimeVars = runtimeVars
 	model.InitialContexts = resolvedContextIDs
 	model.InitialInherits = inherits
+	model.SessionPath = sessionPath
+	model.InitialSession = session
+	if historyPath, err := tui.DefaultHistoryPath(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: search history disabled: %v\n", err)
+	} else if err := model.SearchBar.LoadHistory(historyPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not load search history: %v\n", err)
+	}
+	if slotsPath, err := tui.DefaultSlotsPath(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: quick-slots disabled: %v\n", err)
+	} else if err := model.SearchBar.LoadSlots(slotsPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not load quick-slots: %v\n", err)
+	}
+	model.FieldCache.TTL = fieldCacheTTL
+	model.ErrorLevel = errorLevel
+	model.ApplyTUIConfig(tuiFlagOverrides(cmd))
 	searchCopy := deepCopyLogSearch(searchRequest)
 	model.InitialSearch = &searchCopy
 
@@ -119,6 +162,28 @@ func runTUI(_ *cobra.Command, _ []string) {
 	}
 }
 
+// loadTUISession returns the saved session to restore, or nil when restoring
+// is disabled or nothing usable was saved. Tabs of deleted contexts are
+// skipped with a warning.
+func loadTUISession(path string, cfg *config.ContextConfig) *tui.Session {
+	if path == "" || noRestore || (!restoreSession && len(contextIDs) > 0) {
+		return nil
+	}
+
+	session, skipped, err := tui.LoadSession(path, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not restore session: %v\n", err)
+		return nil
+	}
+	for _, id := range skipped {
+		fmt.Fprintf(os.Stderr, "Warning: skipping saved tab for unknown context '%s'\n", id)
+	}
+	if len(session.Tabs) == 0 {
+		return nil
+	}
+	return session
+}
+
 // deepCopyLogSearch creates a deep copy of a LogSearch to avoid shared references.
 // Based on config.deepCopyLogSearch but adapted to avoid circular dependencies.
 func deepCopyLogSearch(src client.LogSearch) client.LogSearch {
@@ -179,3 +244,19 @@ func deepCopyFilter(src client.Filter) client.Filter {
 
 	return dst
 }
+
+// tuiFlagOverrides returns the sidebar settings given on the command line,
+// which take precedence over the tui section of the config.
+func tuiFlagOverrides(cmd *cobra.Command) config.TUIConfig {
+	var overrides config.TUIConfig
+	if cmd.Flags().Changed("sidebar") {
+		overrides.DetailsVisible.S(sidebarOpen)
+	}
+	if cmd.Flags().Changed("sidebar-mode") {
+		overrides.SidebarMode.S(sidebarMode)
+	}
+	if cmd.Flags().Changed("split-ratio") {
+		overrides.SplitRatio.S(splitRatio)
+	}
+	return overrides
+}
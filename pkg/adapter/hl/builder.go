@@ -175,9 +175,9 @@ func buildLeafExpression(filter *client.Filter) (string, error) {
 		return "", err
 	}
 
-	// Handle exists operator specially
-	if op == operator.Exists {
-		if negate {
+	// Handle exists operators specially; not_exists is a negated exists
+	if op == operator.Exists || op == operator.NotExists {
+		if negate != (op == operator.NotExists) {
 			return fmt.Sprintf("not exists(.%s)", field), nil
 		}
 		return fmt.Sprintf("exists(.%s)", field), nil
@@ -229,7 +229,7 @@ func mapOperator(op string, negate bool) (string, error) {
 	case operator.Lte:
 		return "<=", nil
 
-	case operator.Exists:
+	case operator.Exists, operator.NotExists:
 		// Handled specially in buildLeafExpression
 		return "", nil
 
@@ -150,6 +150,26 @@ func TestBuildArgs_ExistsOperator(t *testing.T) {
 	t.Fatal("-q argument not found")
 }
 
+func TestBuildArgs_NotExistsOperator(t *testing.T) {
+	search := &client.LogSearch{
+		Filter: &client.Filter{
+			Field: "trace_id",
+			Op:    operator.NotExists,
+		},
+	}
+
+	args, err := BuildArgs(search, []string{"/var/log/app.log"})
+	require.NoError(t, err)
+
+	for i, arg := range args {
+		if arg == "-q" && i+1 < len(args) {
+			assert.Equal(t, "not exists(.trace_id)", args[i+1])
+			return
+		}
+	}
+	t.Fatal("-q argument not found")
+}
+
 func TestBuildArgs_WildcardFilter(t *testing.T) {
 	search := &client.LogSearch{
 		Filter: &client.Filter{
@@ -55,6 +55,17 @@ func (h HeaderAuth) Login(req *http.Request) error {
 	return nil
 }
 
+// StatusError is returned for a response with an error status, so callers
+// can check the status with errors.As.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request failed with status code %d: %s", e.StatusCode, e.Body)
+}
+
 // Client is a wrapper around http.Client with convenience methods for JSON/Data requests.
 type Client struct {
 	client http.Client
@@ -119,7 +130,7 @@ func (c Client) post(path string, headers ty.MS, buf *bytes.Buffer, responseData
 
 	if res.StatusCode >= 400 {
 		log.Printf("error %d  %s"+ty.LB, res.StatusCode, string(resBody))
-		return fmt.Errorf("request failed with status code %d: %s", res.StatusCode, string(resBody))
+		return &StatusError{StatusCode: res.StatusCode, Body: string(resBody)}
 	}
 
 	return json.Unmarshal(resBody, &responseData)
@@ -222,7 +233,7 @@ func (c Client) Get(path string, queryParams ty.MS, headers ty.MS, body interfac
 
 	if res.StatusCode >= 400 {
 		log.Printf("error %d  %s"+ty.LB, res.StatusCode, string(resBody))
-		return fmt.Errorf("request failed with status code %d: %s", res.StatusCode, string(resBody))
+		return &StatusError{StatusCode: res.StatusCode, Body: string(resBody)}
 	}
 
 	// Log a truncated GET response body for debugging (avoid huge output)
@@ -287,7 +298,7 @@ func (c Client) Delete(path string, headers ty.MS, auth Auth) error {
 
 	if res.StatusCode >= 400 {
 		log.Printf("error %d  %s"+ty.LB, res.StatusCode, string(resBody))
-		return fmt.Errorf("request failed with status code %d: %s", res.StatusCode, string(resBody))
+		return &StatusError{StatusCode: res.StatusCode, Body: string(resBody)}
 	}
 
 	return nil
@@ -2,6 +2,7 @@
 package http
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/bascanada/logviewer/pkg/ty"
@@ -36,3 +37,19 @@ func TestHttpClient_Get_SendsHeaders(t *testing.T) {
 	assert.Equal(t, "ok", response["status"])
 	assert.True(t, gock.IsDone())
 }
+
+func TestHttpClient_Get_StatusError(t *testing.T) {
+	defer gock.Off()
+	gock.DisableNetworking()
+
+	url := "http://example.com"
+	gock.New(url).Get("/missing").Reply(404).BodyString("not found")
+
+	var response map[string]string
+	err := GetClient(url, nil).Get("/missing", nil, nil, nil, &response, nil)
+
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, 404, statusErr.StatusCode)
+	assert.Equal(t, "request failed with status code 404: not found", err.Error())
+}
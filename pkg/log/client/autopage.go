@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// MaxPageSizeOption is the search (or client) option overriding the number of
+// entries a backend returns per request.
+const MaxPageSizeOption = "maxPageSize"
+
+// PageSizeLimiter is implemented by backends that cap the number of entries a
+// single request can return. A cap of 0 means search is not limited.
+type PageSizeLimiter interface {
+	MaxPageSize(search *LogSearch) int
+}
+
+// maxPageSize returns the page cap for search on backend, 0 when there is none.
+func maxPageSize(backend LogBackend, search *LogSearch) int {
+	switch v := search.Options[MaxPageSizeOption].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	if limiter, ok := backend.(PageSizeLimiter); ok {
+		return limiter.MaxPageSize(search)
+	}
+	return 0
+}
+
+// GetAutoPaged runs search on backend like backend.Get, but when the backend
+// caps a request below search.Size the result fetches the following pages
+// itself, so callers get up to Size entries. Follow mode is never paged.
+func GetAutoPaged(ctx context.Context, backend LogBackend, search *LogSearch) (LogSearchResult, error) {
+	pageSize := maxPageSize(backend, search)
+	if search.Follow || !search.Size.Set || pageSize <= 0 || search.Size.Value <= pageSize {
+		return backend.Get(ctx, search)
+	}
+
+	first := search.Clone()
+	first.Size = ty.OptWrap(pageSize)
+	// The entries are counted per page, in GetEntries
+	result, err := backend.Get(withoutEntryProgress(ctx), first)
+	if err != nil {
+		return nil, err
+	}
+	return &autoPagedResult{
+		LogSearchResult: result,
+		backend:         backend,
+		search:          search,
+		pageSize:        pageSize,
+		last:            result,
+	}, nil
+}
+
+// autoPagedResult is a LogSearchResult whose entries span as many backend
+// pages as needed to reach the requested size.
+type autoPagedResult struct {
+	LogSearchResult // first page
+
+	backend  LogBackend
+	search   *LogSearch
+	pageSize int
+	last     LogSearchResult // page the pagination info is read from
+}
+
+// GetSearch returns the search of the first page with the requested size.
+func (r *autoPagedResult) GetSearch() *LogSearch {
+	search := *r.LogSearchResult.GetSearch()
+	search.Size = r.search.Size
+	return &search
+}
+
+// GetEntries returns the entries of the first page followed by the next pages
+// until the requested size is reached, the backend has no more results or ctx
+// is cancelled.
+func (r *autoPagedResult) GetEntries(ctx context.Context) ([]LogEntry, chan []LogEntry, error) {
+	// The entries are counted per page rather than by each backend request
+	pageCtx := withoutEntryProgress(ctx)
+	entries, ch, err := r.LogSearchResult.GetEntries(pageCtx)
+	if err != nil || ch != nil {
+		// Streaming results are not paged
+		return entries, ch, err
+	}
+
+	total := r.search.Size.Value
+	current := r.LogSearchResult
+	AddEntryProgress(ctx, min(len(entries), total))
+	for len(entries) < total {
+		info := current.GetPaginationInfo()
+		if info == nil || !info.HasMore || info.NextPageToken == "" {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return r.stopped(entries, current, err)
+		}
+
+		next := r.search.Clone()
+		next.Size = ty.OptWrap(min(r.pageSize, total-len(entries)))
+		next.PageToken = ty.OptWrap(info.NextPageToken)
+
+		page, err := r.backend.Get(pageCtx, next)
+		if err != nil {
+			return r.stopped(entries, current, err)
+		}
+		pageEntries, _, err := page.GetEntries(pageCtx)
+		if err != nil {
+			return r.stopped(entries, current, err)
+		}
+		if len(pageEntries) == 0 {
+			break
+		}
+		AddEntryProgress(ctx, min(len(pageEntries), total-len(entries)))
+		entries = append(entries, pageEntries...)
+		current = page
+	}
+	r.last = current
+
+	if len(entries) > total {
+		entries = entries[:total]
+	}
+	return entries, nil, nil
+}
+
+// stopped ends paging on err. A timeout after some pages were fetched returns
+// them with a PartialResultError, and the pagination info of the last page
+// received lets a follow-up request resume where the result was cut short.
+func (r *autoPagedResult) stopped(entries []LogEntry, last LogSearchResult, err error) ([]LogEntry, chan []LogEntry, error) {
+	r.last = last
+	return entries, nil, PartialOnTimeout(entries, err)
+}
+
+// GetPaginationInfo returns the pagination info of the last page fetched, so
+// a follow-up request continues after the entries already returned.
+func (r *autoPagedResult) GetPaginationInfo() *PaginationInfo {
+	return r.last.GetPaginationInfo()
+}
+
+// GetFieldNames forwards to the lister of the first page.
+func (r *autoPagedResult) GetFieldNames(ctx context.Context) ([]string, error) {
+	return ForwardFieldNames(ctx, r, r.LogSearchResult)
+}
+
+// Close closes the first page, the one streaming when the backend streams.
+func (r *autoPagedResult) Close() error {
+	return ForwardClose(r.LogSearchResult)
+}
@@ -0,0 +1,141 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedBackend serves total entries with offset page tokens, capping each
+// request at pageCap like a real backend would.
+type pagedBackend struct {
+	total    int
+	pageCap  int
+	requests []client.LogSearch
+	failAt   int   // request number failing with err, 0 for none
+	err      error // error of request failAt
+}
+
+func (b *pagedBackend) MaxPageSize(_ *client.LogSearch) int { return b.pageCap }
+
+func (b *pagedBackend) Get(_ context.Context, search *client.LogSearch) (client.LogSearchResult, error) {
+	b.requests = append(b.requests, *search)
+	if b.failAt > 0 && len(b.requests) == b.failAt {
+		return nil, b.err
+	}
+	offset := 0
+	if search.PageToken.Set {
+		offset, _ = strconv.Atoi(search.PageToken.Value)
+	}
+	size := min(search.Size.Value, b.pageCap)
+	var entries []client.LogEntry
+	for i := offset; i < offset+size && i < b.total; i++ {
+		entries = append(entries, client.LogEntry{Message: fmt.Sprintf("entry %d", i)})
+	}
+	return &pagedResult{search: search, entries: entries, offset: offset, hasMore: offset+len(entries) < b.total}, nil
+}
+
+func (b *pagedBackend) GetFieldValues(_ context.Context, _ *client.LogSearch, _ []string) (map[string][]string, error) {
+	return nil, nil
+}
+
+type pagedResult struct {
+	search  *client.LogSearch
+	entries []client.LogEntry
+	offset  int
+	hasMore bool
+}
+
+func (r *pagedResult) GetSearch() *client.LogSearch { return r.search }
+func (r *pagedResult) GetEntries(_ context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	return r.entries, nil, nil
+}
+func (r *pagedResult) GetFields(_ context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
+	return nil, nil, nil
+}
+func (r *pagedResult) GetPaginationInfo() *client.PaginationInfo {
+	if !r.hasMore {
+		return nil
+	}
+	return &client.PaginationInfo{HasMore: true, NextPageToken: strconv.Itoa(r.offset + len(r.entries))}
+}
+func (r *pagedResult) Err() <-chan error { return nil }
+
+func TestGetAutoPaged(t *testing.T) {
+	t.Run("pages until size is reached", func(t *testing.T) {
+		backend := &pagedBackend{total: 10000, pageCap: 1000}
+		search := &client.LogSearch{Size: ty.OptWrap(2500)}
+
+		result, err := client.GetAutoPaged(context.Background(), backend, search)
+		require.NoError(t, err)
+		entries, _, err := result.GetEntries(context.Background())
+		require.NoError(t, err)
+
+		assert.Len(t, entries, 2500)
+		assert.Equal(t, "entry 2499", entries[2499].Message)
+		require.Len(t, backend.requests, 3)
+		assert.Equal(t, 500, backend.requests[2].Size.Value, "last page only asks for the rest")
+		assert.Equal(t, 2500, result.GetSearch().Size.Value)
+		assert.Equal(t, "2500", result.GetPaginationInfo().NextPageToken)
+	})
+
+	t.Run("reports progress after each page", func(t *testing.T) {
+		backend := &pagedBackend{total: 10000, pageCap: 1000}
+		result, err := client.GetAutoPaged(context.Background(), backend, &client.LogSearch{Size: ty.OptWrap(2500)})
+		require.NoError(t, err)
+		var fetched []int
+		ctx := client.WithEntryProgress(context.Background(), func(n int) { fetched = append(fetched, n) })
+		_, _, err = result.GetEntries(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1000, 2000, 2500}, fetched)
+	})
+
+	t.Run("stops when the backend has no more", func(t *testing.T) {
+		backend := &pagedBackend{total: 1200, pageCap: 1000}
+		result, err := client.GetAutoPaged(context.Background(), backend, &client.LogSearch{Size: ty.OptWrap(5000)})
+		require.NoError(t, err)
+		entries, _, err := result.GetEntries(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, entries, 1200)
+		assert.Len(t, backend.requests, 2)
+		assert.Nil(t, result.GetPaginationInfo())
+	})
+
+	t.Run("respects cancellation", func(t *testing.T) {
+		backend := &pagedBackend{total: 10000, pageCap: 1000}
+		result, err := client.GetAutoPaged(context.Background(), backend, &client.LogSearch{Size: ty.OptWrap(5000)})
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		entries, _, err := result.GetEntries(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Len(t, entries, 1000, "first page is kept")
+	})
+
+	t.Run("no paging in follow mode or under the cap", func(t *testing.T) {
+		backend := &pagedBackend{total: 10000, pageCap: 1000}
+		_, err := client.GetAutoPaged(context.Background(), backend, &client.LogSearch{Size: ty.OptWrap(5000), Follow: true})
+		require.NoError(t, err)
+		_, err = client.GetAutoPaged(context.Background(), backend, &client.LogSearch{Size: ty.OptWrap(500)})
+		require.NoError(t, err)
+		require.Len(t, backend.requests, 2)
+		assert.Equal(t, 5000, backend.requests[0].Size.Value, "follow search passed through")
+	})
+
+	t.Run("option overrides the backend cap", func(t *testing.T) {
+		backend := &pagedBackend{total: 10000, pageCap: 1000}
+		search := &client.LogSearch{Size: ty.OptWrap(900), Options: ty.MI{client.MaxPageSizeOption: 300}}
+		result, err := client.GetAutoPaged(context.Background(), backend, search)
+		require.NoError(t, err)
+		entries, _, err := result.GetEntries(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, entries, 900)
+		assert.Len(t, backend.requests, 3)
+	})
+}
@@ -105,3 +105,9 @@ func (a *BackendAdapter) GetValues(ctx context.Context, search LogSearch, field
 
 	return []string{}, nil
 }
+
+// Count returns the number of entries matching the search, natively when the
+// backend supports it.
+func (a *BackendAdapter) Count(ctx context.Context, search LogSearch) (int, error) {
+	return CountEntries(ctx, a.Backend, &search)
+}
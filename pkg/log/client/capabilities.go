@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
+)
+
+// Capabilities describes which parts of a filter a backend applies natively.
+// The parts it cannot apply are filtered client-side on the entries it
+// returns, so a search may get fewer than Size entries.
+type Capabilities struct {
+	// Operators are the operators applied natively; nil means all of them.
+	Operators []string
+	// FreeText is set when free-text ("_") conditions are applied natively.
+	FreeText bool
+	// Negation is set when negated conditions are applied natively.
+	Negation bool
+	// Groups is set when OR and NOT groups, and groups nested in the top-level
+	// AND, are applied natively.
+	Groups bool
+	// Fields reports whether the conditions on a field are applied natively;
+	// nil means they all are.
+	Fields func(field string) bool
+}
+
+// CapabilityReporter is implemented by backends that cannot apply every
+// filter natively for some searches. Backends not implementing it apply
+// everything themselves.
+type CapabilityReporter interface {
+	Capabilities(search *LogSearch) Capabilities
+}
+
+// Fallback is a feature of a search that a backend applies client-side.
+type Fallback struct {
+	Capability string // e.g. "regex", "negation"
+	Message    string
+}
+
+// Fallbacks returns the features of search that backend cannot apply
+// natively, once each. It is empty for native-query-only searches, whose
+// filters are not used.
+func Fallbacks(backend LogBackend, search *LogSearch) []Fallback {
+	reporter, ok := backend.(CapabilityReporter)
+	if !ok || search.IsNativeQueryOnly() {
+		return nil
+	}
+	caps := reporter.Capabilities(search)
+
+	var names []string
+	add := func(name string) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	var walk func(f *Filter, top bool)
+	walk = func(f *Filter, top bool) {
+		if f == nil {
+			return
+		}
+		if f.Logic != "" {
+			if !caps.Groups && !(top && f.Logic == LogicAnd) {
+				add(string(f.Logic) + " groups")
+			}
+			for i := range f.Filters {
+				walk(&f.Filters[i], false)
+			}
+			return
+		}
+		if f.Field == "" {
+			return
+		}
+		op := f.Op
+		if op == "" {
+			op = operator.Equals
+		}
+		if caps.Operators != nil && !slices.Contains(caps.Operators, op) {
+			add(op)
+		}
+		if f.Field == "_" && !caps.FreeText {
+			add("free-text")
+		}
+		if f.Negate && !caps.Negation {
+			add("negation")
+		}
+		if caps.Fields != nil && !caps.Fields(f.Field) {
+			add(fmt.Sprintf("%q field", f.Field))
+		}
+	}
+	walk(search.GetEffectiveFilter(), true)
+
+	fallbacks := make([]Fallback, 0, len(names))
+	for _, name := range names {
+		fallbacks = append(fallbacks, Fallback{
+			Capability: name,
+			Message: fmt.Sprintf("the backend cannot apply %s filters natively; they are applied client-side "+
+				"on the entries it returns, so fewer entries than requested may be shown", name),
+		})
+	}
+	return fallbacks
+}
+
+type fallbackWarningKey struct{}
+
+// WithFallbackWarnings returns a context whose searches call warn for each
+// feature their backend applies client-side.
+func WithFallbackWarnings(ctx context.Context, warn func(Fallback)) context.Context {
+	return context.WithValue(ctx, fallbackWarningKey{}, warn)
+}
+
+// ReportFallbacks calls the WithFallbackWarnings callback of ctx, if any, with
+// the Fallbacks of search on backend.
+func ReportFallbacks(ctx context.Context, backend LogBackend, search *LogSearch) {
+	warn, ok := ctx.Value(fallbackWarningKey{}).(func(Fallback))
+	if !ok || warn == nil {
+		return
+	}
+	for _, fallback := range Fallbacks(backend, search) {
+		warn(fallback)
+	}
+}
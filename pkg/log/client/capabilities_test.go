@@ -0,0 +1,101 @@
+package client_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+)
+
+// equalsOnlyBackend applies only plain equality conditions natively.
+type equalsOnlyBackend struct {
+	pagedBackend
+}
+
+func (b *equalsOnlyBackend) Capabilities(_ *client.LogSearch) client.Capabilities {
+	return client.Capabilities{Operators: []string{operator.Equals}}
+}
+
+// fieldsBackend applies every filter natively, except on fields with a dash.
+type fieldsBackend struct {
+	pagedBackend
+}
+
+func (b *fieldsBackend) Capabilities(_ *client.LogSearch) client.Capabilities {
+	return client.Capabilities{Groups: true, Negation: true, FreeText: true, Fields: func(field string) bool {
+		return !strings.Contains(field, "-")
+	}}
+}
+
+func fallbackNames(fallbacks []client.Fallback) []string {
+	names := make([]string, len(fallbacks))
+	for i, f := range fallbacks {
+		names[i] = f.Capability
+	}
+	return names
+}
+
+func TestFallbacks(t *testing.T) {
+	backend := &equalsOnlyBackend{}
+
+	t.Run("plain equality needs no fallback", func(t *testing.T) {
+		search := &client.LogSearch{Fields: ty.MS{"level": "error", "app": "api"}}
+		assert.Empty(t, client.Fallbacks(backend, search))
+	})
+
+	t.Run("each capability is reported once", func(t *testing.T) {
+		search := &client.LogSearch{Filter: &client.Filter{Logic: client.LogicAnd, Filters: []client.Filter{
+			{Field: "msg", Op: operator.Regex, Value: "a.*"},
+			{Field: "path", Op: operator.Regex, Value: "^/api"},
+			{Field: "_", Value: "timeout"},
+			{Field: "env", Value: "dev", Negate: true},
+			{Logic: client.LogicOr, Filters: []client.Filter{{Field: "a", Value: "1"}, {Field: "b", Value: "2"}}},
+		}}}
+		assert.Equal(t, []string{"regex", "free-text", "negation", "OR groups"}, fallbackNames(client.Fallbacks(backend, search)))
+	})
+
+	t.Run("fields the backend cannot name", func(t *testing.T) {
+		backend := &fieldsBackend{}
+		search := &client.LogSearch{Filter: &client.Filter{Logic: client.LogicOr, Filters: []client.Filter{
+			{Field: "level", Value: "error"},
+			{Field: "user-id", Value: "42"},
+		}}}
+		assert.Equal(t, []string{`"user-id" field`}, fallbackNames(client.Fallbacks(backend, search)))
+	})
+
+	t.Run("native query only searches do not use the filter", func(t *testing.T) {
+		search := &client.LogSearch{
+			Fields:          ty.MS{"msg": "a.*"},
+			FieldsCondition: ty.MS{"msg": operator.Regex},
+			NativeQueryOnly: true,
+		}
+		search.NativeQuery.S("fields @message")
+		assert.Empty(t, client.Fallbacks(backend, search))
+	})
+
+	t.Run("backends without capabilities apply everything", func(t *testing.T) {
+		search := &client.LogSearch{Fields: ty.MS{"msg": "a.*"}, FieldsCondition: ty.MS{"msg": operator.Regex}}
+		assert.Empty(t, client.Fallbacks(&pagedBackend{}, search))
+	})
+}
+
+func TestReportFallbacks(t *testing.T) {
+	search := &client.LogSearch{Fields: ty.MS{"msg": "a.*"}, FieldsCondition: ty.MS{"msg": operator.Regex}}
+
+	// No callback set: nothing happens
+	client.ReportFallbacks(context.Background(), &equalsOnlyBackend{}, search)
+
+	var got []client.Fallback
+	ctx := client.WithFallbackWarnings(context.Background(), func(f client.Fallback) {
+		got = append(got, f)
+	})
+	client.ReportFallbacks(ctx, &equalsOnlyBackend{}, search)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, "regex", got[0].Capability)
+		assert.Contains(t, got[0].Message, "applied client-side")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -18,6 +19,10 @@ import (
 // ErrContextNotFound is a sentinel error allowing callers to detect missing contexts via errors.Is.
 var ErrContextNotFound = errors.New("context not found")
 
+// ErrMissingVariables is returned when required variables of a context are
+// not set, see MissingVariables.
+var ErrMissingVariables = errors.New("missing required variables")
+
 // Sentinel errors returned by LoadContextConfig so callers can detect exact
 // failure modes using errors.Is().
 var (
@@ -124,6 +129,9 @@ func LoadContextConfig(explicitPath string) (*ContextConfig, error) {
 		for k, v := range partial.Contexts {
 			mergedCfg.Contexts[k] = v
 		}
+		mergedCfg.TUI.Merge(&partial.TUI)
+		mergedCfg.MCP.Merge(&partial.MCP)
+		mergedCfg.Display.Merge(&partial.Display)
 		filesLoaded++
 	}
 
@@ -270,7 +278,10 @@ type ContextConfig struct {
 	Clients        `json:"clients" yaml:"clients"`
 	Searches       `json:"searches" yaml:"searches"`
 	Contexts       `json:"contexts" yaml:"contexts"`
-	CurrentContext string `json:"-" yaml:"-"`
+	TUI            TUIConfig     `json:"tui,omitempty" yaml:"tui,omitempty"`
+	MCP            MCPConfig     `json:"mcp,omitempty" yaml:"mcp,omitempty"`
+	Display        DisplayConfig `json:"display,omitempty" yaml:"display,omitempty"`
+	CurrentContext string        `json:"-" yaml:"-"`
 }
 
 // GetSearchContext resolves a search context by ID, merging with defaults and overrides.
@@ -307,17 +318,42 @@ func (cc ContextConfig) GetSearchContext(contextID string, inherits []string, lo
 	if err := searchContext.Search.MergeInto(&logSearch); err != nil {
 		return SearchContext{}, fmt.Errorf("failed to merge provided search: %w", err)
 	}
+	// The context may set last while the caller sets from, explicit bounds
+	// win there, but they must still be in order once merged
+	if err := searchContext.Search.Range.ValidateOrder(); err != nil {
+		return SearchContext{}, err
+	}
 
 	// Build complete variable map: defaults from variable definitions + runtime vars (runtime takes precedence)
 	completeVars := make(map[string]string)
-	// First, add defaults from variable definitions
+	// First, add defaults from variable definitions, unless the environment
+	// sets the variable: it is resolved from there like ${name:-default},
+	// so its value is checked like a runtime one
 	for varName, varDef := range searchContext.Search.Variables {
 		if varDef.Default != nil {
-			completeVars[varName] = fmt.Sprintf("%v", varDef.Default)
+			def := fmt.Sprintf("%v", varDef.Default)
+			if err := ty.ValidateVar(varName, varDef.Type, varDef.AllowedValues, def); err != nil {
+				return SearchContext{}, fmt.Errorf("invalid default: %w", err)
+			}
+			completeVars[varName] = def
+		}
+		if _, ok := runtimeVars[varName]; ok {
+			continue
+		}
+		if env, ok := os.LookupEnv(varName); ok {
+			if err := ty.ValidateVar(varName, varDef.Type, varDef.AllowedValues, env); err != nil {
+				return SearchContext{}, fmt.Errorf("invalid environment variable: %w", err)
+			}
+			delete(completeVars, varName)
 		}
 	}
 	// Then, override with runtime variables
 	for k, v := range runtimeVars {
+		if varDef, ok := searchContext.Search.Variables[k]; ok {
+			if err := ty.ValidateVar(k, varDef.Type, varDef.AllowedValues, v); err != nil {
+				return SearchContext{}, err
+			}
+		}
 		completeVars[k] = v
 	}
 
@@ -351,6 +387,27 @@ func (cc ContextConfig) GetSearchContext(contextID string, inherits []string, lo
 	return searchContext, nil
 }
 
+// MissingVariables returns, sorted, the required variables of search
+// without a default that are set neither in runtimeVars nor in the
+// environment.
+func MissingVariables(search client.LogSearch, runtimeVars map[string]string) []string {
+	var missing []string
+	for name, def := range search.Variables {
+		if !def.Required || def.Default != nil {
+			continue
+		}
+		if _, ok := runtimeVars[name]; ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	return missing
+}
+
 // deepCopyLogSearch creates a deep copy of a LogSearch to avoid mutating the original config.
 // This is critical because maps are reference types - without deep copy, merging operations
 // would permanently modify the original config's maps.
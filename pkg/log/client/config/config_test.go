@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
 )
 
 func writeTemp(t *testing.T, dir, name, content string) string {
@@ -280,6 +283,8 @@ clients:
   c1: { type: local, options: {} }
 contexts:
   mainCtx: { client: c1, search: {} }
+tui: { detailsVisible: true, splitRatio: 0.6, levelColors: { error: "#FF0000", debug: "244" } }
+display: { timezone: UTC }
 `
 	if err := os.WriteFile(filepath.Join(configDir, DefaultConfigFile), []byte(mainContent), 0600); err != nil {
 		t.Fatalf("failed to write main config: %v", err)
@@ -290,6 +295,7 @@ contexts:
 contexts:
   dropInCtx: { client: c1, search: {} }
   mainCtx: { client: c1, description: "overridden", search: {} } # Should override mainCtx
+tui: { sidebarMode: json, splitRatio: 0.5, levelColors: { error: "196" } }
 `
 	if err := os.WriteFile(filepath.Join(dropInDir, "extra.yaml"), []byte(dropInContent), 0600); err != nil {
 		t.Fatalf("failed to write drop-in config: %v", err)
@@ -311,6 +317,18 @@ contexts:
 	if _, ok := cfg.Contexts["dropInCtx"]; !ok {
 		t.Errorf("expected dropInCtx to be present")
 	}
+
+	// TUI settings merge one by one, the drop-in winning
+	if !cfg.TUI.DetailsVisible.Value || cfg.TUI.SidebarMode.Value != "json" || cfg.TUI.SplitRatio.Value != 0.5 {
+		t.Errorf("expected merged tui settings, got %+v", cfg.TUI)
+	}
+	if cfg.TUI.LevelColors["error"] != "196" || cfg.TUI.LevelColors["debug"] != "244" {
+		t.Errorf("expected level colors merged by level, got %v", cfg.TUI.LevelColors)
+	}
+	// The drop-in has no display settings, the main ones are kept
+	if cfg.Display.Timezone.Value != "UTC" {
+		t.Errorf("expected display timezone UTC, got %q", cfg.Display.Timezone.Value)
+	}
 }
 
 func TestLoadContextConfig_EnvVarMultiFile(t *testing.T) {
@@ -336,3 +354,205 @@ func TestLoadContextConfig_EnvVarMultiFile(t *testing.T) {
 		t.Errorf("expected ctx2 from file2")
 	}
 }
+
+func TestGetSearchContext_VariableValidation(t *testing.T) {
+	configContent := `
+clients:
+  c1:
+    type: local
+contexts:
+  test-ctx:
+    client: c1
+    search:
+      fields:
+        env: "${env}"
+      size: 10
+      variables:
+        env:
+          type: enum
+          default: prod
+          allowedValues: [dev, prod]
+        limit:
+          type: int
+          default: 100
+`
+	path := writeTemp(t, "", "varvalidation.yaml", configContent)
+	cfg, err := LoadContextConfig(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	ctx, err := cfg.GetSearchContext("test-ctx", nil, client.LogSearch{}, map[string]string{"env": "dev", "limit": "5"})
+	if err != nil {
+		t.Fatalf("valid variables rejected: %v", err)
+	}
+	if ctx.Search.Fields["env"] != "dev" {
+		t.Errorf("expected env=dev, got %s", ctx.Search.Fields["env"])
+	}
+
+	_, err = cfg.GetSearchContext("test-ctx", nil, client.LogSearch{}, map[string]string{"env": "qa"})
+	if err == nil || !strings.Contains(err.Error(), "not one of dev, prod") {
+		t.Errorf("expected enum error listing allowed values, got %v", err)
+	}
+
+	_, err = cfg.GetSearchContext("test-ctx", nil, client.LogSearch{}, map[string]string{"limit": "ten"})
+	if err == nil {
+		t.Error("expected int validation error")
+	}
+
+	def := cfg.Contexts["test-ctx"].Search.Variables["env"]
+	def.Default = "qa"
+	cfg.Contexts["test-ctx"].Search.Variables["env"] = def
+	_, err = cfg.GetSearchContext("test-ctx", nil, client.LogSearch{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid default") {
+		t.Errorf("expected invalid default error, got %v", err)
+	}
+}
+
+func TestGetSearchContext_VariablePrecedence(t *testing.T) {
+	configContent := `
+clients:
+  c1:
+    type: local
+contexts:
+  test-ctx:
+    client: c1
+    search:
+      fields:
+        env: "${LV_TEST_ENV}"
+      variables:
+        LV_TEST_ENV:
+          default: prod
+`
+	path := writeTemp(t, "", "varprecedence.yaml", configContent)
+	cfg, err := LoadContextConfig(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		env     string
+		runtime map[string]string
+		want    string
+	}{
+		{name: "default", want: "prod"},
+		{name: "environment over default", env: "staging", want: "staging"},
+		{name: "runtime over environment", env: "staging", runtime: map[string]string{"LV_TEST_ENV": "dev"}, want: "dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("LV_TEST_ENV", tt.env)
+			}
+			ctx, err := cfg.GetSearchContext("test-ctx", nil, client.LogSearch{}, tt.runtime)
+			if err != nil {
+				t.Fatalf("failed to get search context: %v", err)
+			}
+			if ctx.Search.Fields["env"] != tt.want {
+				t.Errorf("expected env=%s, got %s", tt.want, ctx.Search.Fields["env"])
+			}
+		})
+	}
+}
+
+func TestGetSearchContext_EnvVariableValidation(t *testing.T) {
+	configContent := `
+clients:
+  c1:
+    type: local
+contexts:
+  test-ctx:
+    client: c1
+    search:
+      fields:
+        env: "${LV_TEST_ENV}"
+      variables:
+        LV_TEST_ENV:
+          type: enum
+          default: prod
+          allowedValues: [dev, prod]
+`
+	path := writeTemp(t, "", "envvalidation.yaml", configContent)
+	cfg, err := LoadContextConfig(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	t.Setenv("LV_TEST_ENV", "qa")
+	_, err = cfg.GetSearchContext("test-ctx", nil, client.LogSearch{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "not one of dev, prod") {
+		t.Errorf("expected enum error for the environment value, got %v", err)
+	}
+
+	ctx, err := cfg.GetSearchContext("test-ctx", nil, client.LogSearch{}, map[string]string{"LV_TEST_ENV": "dev"})
+	if err != nil {
+		t.Fatalf("runtime value should win over the invalid environment: %v", err)
+	}
+	if ctx.Search.Fields["env"] != "dev" {
+		t.Errorf("expected env=dev, got %s", ctx.Search.Fields["env"])
+	}
+}
+
+func TestMissingVariables(t *testing.T) {
+	search := client.LogSearch{Variables: map[string]client.VariableDefinition{
+		"session":    {Required: true},
+		"LV_TEST_ID": {Required: true},
+		"tenant":     {Required: true, Default: "main"},
+		"level":      {},
+		"trace":      {Required: true},
+	}}
+
+	missing := MissingVariables(search, nil)
+	if want := []string{"LV_TEST_ID", "session", "trace"}; !slices.Equal(missing, want) {
+		t.Errorf("expected %v, got %v", want, missing)
+	}
+
+	t.Setenv("LV_TEST_ID", "42")
+	missing = MissingVariables(search, map[string]string{"session": "abc"})
+	if want := []string{"trace"}; !slices.Equal(missing, want) {
+		t.Errorf("expected %v, got %v", want, missing)
+	}
+}
+
+func TestGetSearchContext_InvertedRange(t *testing.T) {
+	cfg := &ContextConfig{Contexts: Contexts{"ctx": {Search: client.LogSearch{
+		Range: client.SearchRange{Last: ty.OptWrap("15m"), Lte: ty.OptWrap("2024-01-01T00:00:00Z")},
+	}}}}
+
+	// The caller's from wins over the context's last
+	_, err := cfg.GetSearchContext("ctx", nil, client.LogSearch{Range: client.SearchRange{Gte: ty.OptWrap("2023-12-31T00:00:00Z")}}, nil)
+	if err != nil {
+		t.Fatalf("valid range rejected: %v", err)
+	}
+
+	_, err = cfg.GetSearchContext("ctx", nil, client.LogSearch{Range: client.SearchRange{Gte: ty.OptWrap("2024-01-02T00:00:00Z")}}, nil)
+	var rangeErr *client.RangeError
+	if !errors.As(err, &rangeErr) {
+		t.Fatalf("expected a RangeError, got %v", err)
+	}
+}
+
+func TestMCPConfigExposes(t *testing.T) {
+	all := MCPConfig{}
+	if !all.Exposes("prod") {
+		t.Errorf("expected every context exposed without lists")
+	}
+
+	c := MCPConfig{ExposeContexts: []string{"staging-*", "local"}, HideContexts: []string{"staging-billing"}}
+	for id, want := range map[string]bool{"staging-api": true, "local": true, "staging-billing": false, "prod": false} {
+		if got := c.Exposes(id); got != want {
+			t.Errorf("Exposes(%q) = %v, want %v", id, got, want)
+		}
+	}
+
+	hidden := MCPConfig{HideContexts: []string{"prod*"}}
+	if hidden.Exposes("prod-db") || !hidden.Exposes("dev") {
+		t.Errorf("expected only prod contexts hidden")
+	}
+
+	c.Merge(&MCPConfig{HideContexts: []string{"local"}})
+	if c.Exposes("local") || !c.Exposes("staging-billing") || !c.Exposes("staging-api") {
+		t.Errorf("expected the merged hide list to replace the previous one, got %+v", c)
+	}
+}
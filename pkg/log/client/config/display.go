@@ -0,0 +1,16 @@
+package config
+
+import "github.com/bascanada/logviewer/pkg/ty"
+
+// DisplayConfig holds how entries are shown by the printer and the TUI.
+type DisplayConfig struct {
+	// Timezone is the zone timestamps are shown in: an IANA name like
+	// America/Toronto, UTC or local (the default). The --timezone flag and
+	// the printerOptions.timezone of a search win over it.
+	Timezone ty.Opt[string] `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+// Merge sets the settings of other over those of c.
+func (c *DisplayConfig) Merge(other *DisplayConfig) {
+	c.Timezone.Merge(&other.Timezone)
+}
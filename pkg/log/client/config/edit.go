@@ -0,0 +1,238 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrContextExists is returned when renaming a context to an ID already in use.
+var ErrContextExists = errors.New("context already exists")
+
+// FindContextFile returns the file among files whose definition of contextID
+// is the one in effect (the last one, since later files win on collision),
+// and the other files that also define it.
+func FindContextFile(files []string, contextID string) (string, []string, error) {
+	var defining []string
+	for _, path := range files {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		cfg, err := loadSingleFile(path)
+		if err != nil {
+			return "", nil, fmt.Errorf("error loading %s: %w", path, err)
+		}
+		if _, ok := cfg.Contexts[contextID]; ok {
+			defining = append(defining, path)
+		}
+	}
+	if len(defining) == 0 {
+		return "", nil, fmt.Errorf("%w: %s", ErrContextNotFound, contextID)
+	}
+	last := len(defining) - 1
+	return defining[last], defining[:last], nil
+}
+
+// RenameContext renames the context oldID to newID in the config file at path.
+func RenameContext(path, oldID, newID string) error {
+	if newID == "" {
+		return errors.New("new context ID is empty")
+	}
+	return editContextKey(path, oldID, newID)
+}
+
+// DeleteContext removes the context contextID from the config file at path.
+func DeleteContext(path, contextID string) error {
+	return editContextKey(path, contextID, "")
+}
+
+// editContextKey renames the contexts entry contextID to newID, or removes it
+// when newID is empty. The file is edited in place rather than re-encoded
+// from ContextConfig, so other settings, their order, and YAML comments, are
+// kept as is.
+func editContextKey(path, contextID, newID string) error {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		data, err = editJSONContextKey(data, contextID, newID)
+	} else {
+		data, err = editYAMLContextKey(data, contextID, newID)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// jsonMember is the position in the document of a member of a JSON object.
+type jsonMember struct {
+	name       string
+	start, end int // the key, quotes included
+	valueEnd   int
+	prevEnd    int // end of the previous member, or of the opening brace
+}
+
+// editJSONContextKey edits the bytes of the contexts key only, so the order
+// and the formatting of the rest of the file are kept.
+func editJSONContextKey(data []byte, contextID, newID string) ([]byte, error) {
+	members, err := jsonContextMembers(data)
+	if err != nil {
+		return nil, errors.Join(ErrConfigParse, err)
+	}
+	index := -1
+	for i, m := range members {
+		switch m.name {
+		case contextID:
+			index = i
+		case newID:
+			return nil, fmt.Errorf("%w: %s", ErrContextExists, newID)
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrContextNotFound, contextID)
+	}
+
+	m := members[index]
+	var from, to int
+	var replacement []byte
+	switch {
+	case newID != "":
+		from, to = m.start, m.end
+		if replacement, err = json.Marshal(newID); err != nil {
+			return nil, err
+		}
+	case index > 0:
+		// With the comma after the previous member
+		from, to = members[index-1].valueEnd, m.valueEnd
+	case len(members) > 1:
+		// With the comma before the next member
+		from, to = m.start, members[1].start
+	default:
+		from, to = m.prevEnd, m.valueEnd
+	}
+
+	out := make([]byte, 0, len(data)-(to-from)+len(replacement))
+	out = append(out, data[:from]...)
+	out = append(out, replacement...)
+	return append(out, data[to:]...), nil
+}
+
+// jsonContextMembers returns the members of the contexts object of a JSON
+// config, none when it has no contexts.
+func jsonContextMembers(data []byte) ([]jsonMember, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, errors.New("config is not a JSON object")
+	}
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if key != "contexts" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return nil, err
+		}
+
+		var members []jsonMember
+		prevEnd := int(dec.InputOffset())
+		for dec.More() {
+			searchFrom := prevEnd
+			if len(members) > 0 {
+				searchFrom = members[len(members)-1].valueEnd
+			}
+			name, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			m := jsonMember{name: name.(string), end: int(dec.InputOffset()), prevEnd: prevEnd}
+			m.start = searchFrom + bytes.IndexByte(data[searchFrom:], '"')
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return nil, err
+			}
+			m.valueEnd = int(dec.InputOffset())
+			prevEnd = m.valueEnd
+			members = append(members, m)
+		}
+		return members, nil
+	}
+	return nil, nil
+}
+
+func editYAMLContextKey(data []byte, contextID, newID string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Join(ErrConfigParse, err)
+	}
+	var contexts *yaml.Node
+	if len(doc.Content) == 1 {
+		contexts = mappingValue(doc.Content[0], "contexts")
+	}
+	if contexts == nil || contexts.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%w: %s", ErrContextNotFound, contextID)
+	}
+
+	index := -1
+	for i := 0; i+1 < len(contexts.Content); i += 2 {
+		switch contexts.Content[i].Value {
+		case contextID:
+			index = i
+		case newID:
+			return nil, fmt.Errorf("%w: %s", ErrContextExists, newID)
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrContextNotFound, contextID)
+	}
+	if newID != "" {
+		contexts.Content[index].Value = newID
+	} else {
+		contexts.Content = append(contexts.Content[:index], contexts.Content[index+2:]...)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingValue returns the value node of key in the YAML mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRenameContextKeepsComments(t *testing.T) {
+	path := writeTemp(t, "", "edit.yaml", `# team config
+clients:
+  local: {type: local}
+contexts:
+  # the main app
+  app:
+    client: local
+  worker:
+    client: local
+`)
+
+	if err := RenameContext(path, "app", "api"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if err := RenameContext(path, "api", "worker"); !errors.Is(err, ErrContextExists) {
+		t.Errorf("expected ErrContextExists, got %v", err)
+	}
+	if err := DeleteContext(path, "worker"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{"# team config", "# the main app", "api:"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "worker") {
+		t.Errorf("worker not deleted:\n%s", content)
+	}
+
+	cfg, err := loadSingleFile(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if cfg.Contexts["api"].Client != "local" {
+		t.Errorf("renamed context lost its settings: %+v", cfg.Contexts["api"])
+	}
+}
+
+func TestEditJSONContextKeyKeepsOrder(t *testing.T) {
+	const config = `{
+  "contexts": {
+    "b": {"client": "local"},
+    "a": {"client": "local"},
+    "c": {"client": "local"}
+  },
+  "clients": {"local": {"type": "local"}},
+  "searches": {}
+}
+`
+	tests := []struct {
+		name        string
+		id, newID   string
+		wantContext string
+	}{
+		{"rename", "a", "z", `{
+    "b": {"client": "local"},
+    "z": {"client": "local"},
+    "c": {"client": "local"}
+  }`},
+		{"delete the first", "b", "", `{
+    "a": {"client": "local"},
+    "c": {"client": "local"}
+  }`},
+		{"delete in the middle", "a", "", `{
+    "b": {"client": "local"},
+    "c": {"client": "local"}
+  }`},
+		{"delete the last", "c", "", `{
+    "b": {"client": "local"},
+    "a": {"client": "local"}
+  }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := editJSONContextKey([]byte(config), tt.id, tt.newID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := strings.Replace(config, `{
+    "b": {"client": "local"},
+    "a": {"client": "local"},
+    "c": {"client": "local"}
+  }`, tt.wantContext, 1)
+			if string(out) != want {
+				t.Errorf("got:\n%s\nwant:\n%s", out, want)
+			}
+		})
+	}
+
+	out, err := editJSONContextKey([]byte(`{"contexts": {"only": {}}, "clients": {}}`), "only", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"contexts": {}, "clients": {}}` {
+		t.Errorf("unexpected output after deleting the only context: %s", out)
+	}
+
+	if _, err := editJSONContextKey([]byte(config), "a", "c"); !errors.Is(err, ErrContextExists) {
+		t.Errorf("expected ErrContextExists, got %v", err)
+	}
+	if _, err := editJSONContextKey([]byte(`{"clients": {}}`), "a", ""); !errors.Is(err, ErrContextNotFound) {
+		t.Errorf("expected ErrContextNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// KeychainService is the service name under which keychain secrets are
+// stored, the account being the name after KeychainPrefix.
+const KeychainService = "logviewer"
+
+// keychainLookup reads a secret from the keychain of the OS, replaced in tests.
+var keychainLookup = func(name string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", KeychainService, "-a", name, "-w")
+	case "linux", "freebsd", "openbsd":
+		cmd = exec.Command("secret-tool", "lookup", "service", KeychainService, "account", name)
+	default:
+		return "", fmt.Errorf("keychain is not supported on %s", runtime.GOOS)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	secret := strings.TrimRight(string(out), "\r\n")
+	if secret == "" {
+		return "", errors.New("no such entry")
+	}
+	return secret, nil
+}
+
+// ResolveKeychain returns a copy of options with "keychain:<name>" references
+// replaced by the secrets they point to, keeping an auth scheme written in
+// front of the reference, e.g. "Splunk keychain:prod-splunk".
+func ResolveKeychain(options ty.MI) (ty.MI, error) {
+	if options == nil {
+		return nil, nil
+	}
+	resolved, err := resolveKeychainMap(options)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(ty.MI), nil
+}
+
+// resolveKeychainMap walks an option map like redactMap and returns a copy
+// with keychain references resolved.
+func resolveKeychainMap(m interface{}) (interface{}, error) {
+	resolveValue := func(key string, v interface{}) (interface{}, error) {
+		switch vv := v.(type) {
+		case string:
+			return resolveKeychainValue(key, vv)
+		case ty.MI, map[string]interface{}, ty.MS, map[string]string:
+			return resolveKeychainMap(vv)
+		}
+		return v, nil
+	}
+
+	var err error
+	switch mm := m.(type) {
+	case ty.MI:
+		out := make(ty.MI, len(mm))
+		for k, v := range mm {
+			if out[k], err = resolveValue(k, v); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(mm))
+		for k, v := range mm {
+			if out[k], err = resolveValue(k, v); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case ty.MS:
+		out := make(ty.MS, len(mm))
+		for k, v := range mm {
+			if out[k], err = resolveKeychainValue(k, v); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case map[string]string:
+		out := make(map[string]string, len(mm))
+		for k, v := range mm {
+			if out[k], err = resolveKeychainValue(k, v); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	return m, nil
+}
+
+func resolveKeychainValue(key, v string) (string, error) {
+	scheme := ""
+	for _, s := range authSchemes {
+		if strings.HasPrefix(v, s+KeychainPrefix) {
+			scheme = s
+			break
+		}
+	}
+	name, ok := strings.CutPrefix(strings.TrimPrefix(v, scheme), KeychainPrefix)
+	if !ok {
+		return v, nil
+	}
+	secret, err := keychainLookup(name)
+	if err != nil {
+		return "", fmt.Errorf("option %s: reading %q from the keychain: %w", key, name, err)
+	}
+	return scheme + secret, nil
+}
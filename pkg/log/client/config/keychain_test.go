@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveKeychain(t *testing.T) {
+	lookup := keychainLookup
+	defer func() { keychainLookup = lookup }()
+	keychainLookup = func(name string) (string, error) {
+		if name == "prod-splunk" {
+			return "abc123", nil
+		}
+		return "", errors.New("no such entry")
+	}
+
+	options := ty.MI{
+		"url":     "https://splunk.example.com",
+		"token":   "keychain:prod-splunk",
+		"headers": ty.MS{"Authorization": "Splunk keychain:prod-splunk", "X-Trace": "keep"},
+		"auth":    map[string]interface{}{"password": "keychain:prod-splunk"},
+		"retries": 3,
+	}
+
+	resolved, err := ResolveKeychain(options)
+	require.NoError(t, err)
+	assert.Equal(t, ty.MI{
+		"url":     "https://splunk.example.com",
+		"token":   "abc123",
+		"headers": ty.MS{"Authorization": "Splunk abc123", "X-Trace": "keep"},
+		"auth":    map[string]interface{}{"password": "abc123"},
+		"retries": 3,
+	}, resolved)
+	assert.Equal(t, "keychain:prod-splunk", options["token"], "options are left unchanged")
+
+	_, err = ResolveKeychain(ty.MI{"apiKey": "keychain:unknown"})
+	assert.EqualError(t, err, `option apiKey: reading "unknown" from the keychain: no such entry`)
+}
@@ -0,0 +1,41 @@
+package config
+
+import "path"
+
+// MCPConfig holds the settings of the MCP server.
+type MCPConfig struct {
+	// ExposeContexts lists the contexts agents may use, as names or globs
+	// like "staging-*". Every context is exposed when it is empty.
+	ExposeContexts []string `json:"exposeContexts,omitempty" yaml:"exposeContexts,omitempty"`
+	// HideContexts lists the contexts agents never see, as names or globs,
+	// even when ExposeContexts matches them.
+	HideContexts []string `json:"hideContexts,omitempty" yaml:"hideContexts,omitempty"`
+}
+
+// Merge sets the lists of other over those of c.
+func (c *MCPConfig) Merge(other *MCPConfig) {
+	if len(other.ExposeContexts) > 0 {
+		c.ExposeContexts = append([]string(nil), other.ExposeContexts...)
+	}
+	if len(other.HideContexts) > 0 {
+		c.HideContexts = append([]string(nil), other.HideContexts...)
+	}
+}
+
+// Exposes reports whether contextID may be used over MCP.
+func (c MCPConfig) Exposes(contextID string) bool {
+	if matchesAny(c.HideContexts, contextID) {
+		return false
+	}
+	return len(c.ExposeContexts) == 0 || matchesAny(c.ExposeContexts, contextID)
+}
+
+func matchesAny(patterns []string, contextID string) bool {
+	for _, p := range patterns {
+		// A malformed glob only matches its exact text
+		if ok, err := path.Match(p, contextID); ok || (err != nil && p == contextID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// KeychainPrefix marks a secret stored outside the config file, e.g.
+// "keychain:prod-splunk". Values with this prefix are never redacted.
+const KeychainPrefix = "keychain:"
+
+// secretOptionKeys lists the client option keys (case-insensitive) whose values
+// are treated as secrets when redacting a config.
+var secretOptionKeys = map[string]bool{
+	"token":         true,
+	"password":      true,
+	"apikey":        true,
+	"authorization": true,
+}
+
+// authSchemes are kept in clear in front of a redacted Authorization value so
+// the header is still well formed once the variable is resolved.
+var authSchemes = []string{"Splunk ", "Bearer ", "Basic "}
+
+var envNameSanitizer = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// RedactedSecret describes a plaintext secret replaced by an environment
+// variable reference.
+type RedactedSecret struct {
+	Client string // Client name
+	Path   string // Dotted option path, e.g. headers.Authorization
+	EnvVar string // Environment variable now referenced by the config
+	Value  string // The plaintext secret that was removed
+}
+
+// IsSecretReference reports whether v already points to a secret stored
+// elsewhere, either as an environment variable or a keychain entry.
+func IsSecretReference(v string) bool {
+	return strings.Contains(v, "${") || strings.HasPrefix(v, "$") || strings.HasPrefix(v, KeychainPrefix)
+}
+
+// RedactSecrets replaces plaintext secrets in client options with ${ENV}
+// references so the config can be committed safely. Values that are already
+// references are left untouched. The removed secrets are returned, sorted by
+// environment variable name, so the caller can store them.
+func RedactSecrets(cfg *ContextConfig) []RedactedSecret {
+	if cfg == nil {
+		return nil
+	}
+
+	var secrets []RedactedSecret
+	for name, c := range cfg.Clients {
+		options, found := redactMap(c.Options, name, "")
+		if len(found) == 0 {
+			continue
+		}
+		c.Options = options.(ty.MI)
+		cfg.Clients[name] = c
+		secrets = append(secrets, found...)
+	}
+
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].EnvVar < secrets[j].EnvVar })
+	return secrets
+}
+
+// redactMap walks an option map and returns a copy with secrets replaced. The
+// copy keeps the concrete map type of the input.
+func redactMap(m interface{}, clientName, prefix string) (interface{}, []RedactedSecret) {
+	var secrets []RedactedSecret
+
+	redactValue := func(key string, v interface{}) interface{} {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		switch vv := v.(type) {
+		case string:
+			if !secretOptionKeys[strings.ToLower(key)] || vv == "" || IsSecretReference(vv) {
+				return v
+			}
+			secret := RedactedSecret{Client: clientName, Path: path, EnvVar: secretEnvName(clientName, path), Value: vv}
+			scheme := ""
+			for _, s := range authSchemes {
+				if strings.HasPrefix(vv, s) {
+					scheme = s
+					secret.Value = strings.TrimPrefix(vv, s)
+					break
+				}
+			}
+			if scheme != "" && IsSecretReference(secret.Value) {
+				return v
+			}
+			secrets = append(secrets, secret)
+			return scheme + "${" + secret.EnvVar + "}"
+		case ty.MI, map[string]interface{}, ty.MS, map[string]string:
+			redacted, found := redactMap(vv, clientName, path)
+			secrets = append(secrets, found...)
+			return redacted
+		}
+		return v
+	}
+
+	switch mm := m.(type) {
+	case ty.MI:
+		out := make(ty.MI, len(mm))
+		for k, v := range mm {
+			out[k] = redactValue(k, v)
+		}
+		return out, secrets
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(mm))
+		for k, v := range mm {
+			out[k] = redactValue(k, v)
+		}
+		return out, secrets
+	case ty.MS:
+		out := make(ty.MS, len(mm))
+		for k, v := range mm {
+			out[k] = redactValue(k, v).(string)
+		}
+		return out, secrets
+	case map[string]string:
+		out := make(map[string]string, len(mm))
+		for k, v := range mm {
+			out[k] = redactValue(k, v).(string)
+		}
+		return out, secrets
+	}
+	return m, nil
+}
+
+// MaskedSecret replaces secret values in MaskSecrets output.
+const MaskedSecret = "********"
+
+// MaskSecrets returns a copy of options with plaintext secrets replaced by
+// MaskedSecret, for display. References to secrets are kept as they are.
+func MaskSecrets(options ty.MI) map[string]any {
+	if options == nil {
+		return nil
+	}
+	out := make(map[string]any, len(options))
+	for k, v := range options {
+		out[k] = maskValue(k, v)
+	}
+	return out
+}
+
+func maskValue(key string, v interface{}) interface{} {
+	switch vv := v.(type) {
+	case string:
+		if !secretOptionKeys[strings.ToLower(key)] || vv == "" || IsSecretReference(vv) {
+			return v
+		}
+		for _, s := range authSchemes {
+			if strings.HasPrefix(vv, s) {
+				return s + MaskedSecret
+			}
+		}
+		return MaskedSecret
+	case ty.MI:
+		return MaskSecrets(vv)
+	case map[string]interface{}:
+		return MaskSecrets(vv)
+	case ty.MS:
+		return MaskSecrets(msToMI(vv))
+	case map[string]string:
+		return MaskSecrets(msToMI(vv))
+	}
+	return v
+}
+
+// SecretValues returns the plaintext secrets of options, with ${ENV}
+// references resolved and auth schemes removed, so they can be masked out of
+// messages. Keychain references are skipped.
+func SecretValues(options ty.MI) []string {
+	var values []string
+	var walk func(key string, v interface{})
+	walk = func(key string, v interface{}) {
+		switch vv := v.(type) {
+		case string:
+			if !secretOptionKeys[strings.ToLower(key)] || strings.HasPrefix(vv, KeychainPrefix) {
+				return
+			}
+			vv = ty.ResolveVars(vv, nil)
+			for _, s := range authSchemes {
+				if strings.HasPrefix(vv, s) {
+					vv = strings.TrimPrefix(vv, s)
+					break
+				}
+			}
+			if vv != "" {
+				values = append(values, vv)
+			}
+		case ty.MI:
+			for k, x := range vv {
+				walk(k, x)
+			}
+		case map[string]interface{}:
+			for k, x := range vv {
+				walk(k, x)
+			}
+		case ty.MS:
+			for k, x := range vv {
+				walk(k, x)
+			}
+		case map[string]string:
+			for k, x := range vv {
+				walk(k, x)
+			}
+		}
+	}
+	for k, v := range options {
+		walk(k, v)
+	}
+	sort.Strings(values)
+	return values
+}
+
+func msToMI(m map[string]string) ty.MI {
+	out := make(ty.MI, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// secretEnvName builds the variable name for a secret, e.g.
+// LOGVIEWER_PROD_SPLUNK_HEADERS_AUTHORIZATION.
+func secretEnvName(clientName, path string) string {
+	name := strings.ToUpper(clientName + "_" + path)
+	return "LOGVIEWER_" + strings.Trim(envNameSanitizer.ReplaceAllString(name, "_"), "_")
+}
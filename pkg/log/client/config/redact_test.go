@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	cfg := &ContextConfig{
+		Clients: Clients{
+			"prod-splunk": {
+				Type: "splunk",
+				Options: ty.MI{
+					"url": "https://splunk.example.com",
+					"headers": ty.MS{
+						"Authorization": "Splunk abc123",
+						"X-Trace":       "keep",
+					},
+				},
+			},
+			"search": {
+				Type: "opensearch",
+				Options: ty.MI{
+					"endpoint": "http://localhost:9200",
+					"auth": map[string]interface{}{
+						"username": "admin",
+						"password": "hunter2",
+					},
+					"apiKey": "${MY_API_KEY}",
+					"token":  "keychain:search-token",
+				},
+			},
+		},
+	}
+
+	secrets := RedactSecrets(cfg)
+
+	assert.Equal(t, []RedactedSecret{
+		{Client: "prod-splunk", Path: "headers.Authorization", EnvVar: "LOGVIEWER_PROD_SPLUNK_HEADERS_AUTHORIZATION", Value: "abc123"},
+		{Client: "search", Path: "auth.password", EnvVar: "LOGVIEWER_SEARCH_AUTH_PASSWORD", Value: "hunter2"},
+	}, secrets)
+
+	splunkOpts := cfg.Clients["prod-splunk"].Options
+	assert.Equal(t, ty.MS{
+		"Authorization": "Splunk ${LOGVIEWER_PROD_SPLUNK_HEADERS_AUTHORIZATION}",
+		"X-Trace":       "keep",
+	}, splunkOpts["headers"])
+	assert.Equal(t, "https://splunk.example.com", splunkOpts["url"])
+
+	searchOpts := cfg.Clients["search"].Options
+	assert.Equal(t, "${LOGVIEWER_SEARCH_AUTH_PASSWORD}", searchOpts["auth"].(map[string]interface{})["password"])
+	assert.Equal(t, "admin", searchOpts["auth"].(map[string]interface{})["username"])
+
+	// Already-referenced secrets are untouched
+	assert.Equal(t, "${MY_API_KEY}", searchOpts["apiKey"])
+	assert.Equal(t, "keychain:search-token", searchOpts["token"])
+
+	// A second pass finds nothing left to redact
+	assert.Empty(t, RedactSecrets(cfg))
+}
+
+func TestSecretValues(t *testing.T) {
+	t.Setenv("PING_TEST_TOKEN", "s3cr3t-token")
+	options := ty.MI{
+		"url":     "https://splunk.example.com",
+		"token":   "${PING_TEST_TOKEN}",
+		"headers": ty.MS{"Authorization": "Bearer abc123", "X-Trace": "keep"},
+		"auth":    map[string]interface{}{"password": "hunter2", "username": "admin"},
+		"apiKey":  "keychain:search-token",
+	}
+
+	assert.Equal(t, []string{"abc123", "hunter2", "s3cr3t-token"}, SecretValues(options))
+}
@@ -0,0 +1,29 @@
+package config
+
+import "github.com/bascanada/logviewer/pkg/ty"
+
+// TUIConfig holds the defaults of the interactive TUI, applied when it starts.
+type TUIConfig struct {
+	// DetailsVisible opens the sidebar at launch
+	DetailsVisible ty.Opt[bool] `json:"detailsVisible,omitempty" yaml:"detailsVisible,omitempty"`
+	// SidebarMode is the sidebar content at launch: entry, fields or json
+	SidebarMode ty.Opt[string] `json:"sidebarMode,omitempty" yaml:"sidebarMode,omitempty"`
+	// SplitRatio is the share of the width given to the log list, e.g. 0.6
+	SplitRatio ty.Opt[float64] `json:"splitRatio,omitempty" yaml:"splitRatio,omitempty"`
+	// LevelColors maps a level to the color of its rows, a hex color like
+	// #EF4444 or an ANSI number; "default" keeps the normal row color
+	LevelColors map[string]string `json:"levelColors,omitempty" yaml:"levelColors,omitempty"`
+}
+
+// Merge sets the settings of other over those of c.
+func (c *TUIConfig) Merge(other *TUIConfig) {
+	c.DetailsVisible.Merge(&other.DetailsVisible)
+	c.SidebarMode.Merge(&other.SidebarMode)
+	c.SplitRatio.Merge(&other.SplitRatio)
+	for level, color := range other.LevelColors {
+		if c.LevelColors == nil {
+			c.LevelColors = make(map[string]string, len(other.LevelColors))
+		}
+		c.LevelColors[level] = color
+	}
+}
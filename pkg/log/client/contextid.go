@@ -0,0 +1,69 @@
+package client
+
+import "context"
+
+// WithContextID returns result with the ContextID of its entries set to
+// contextID when the backend left it empty, and their timestamps in UTC.
+// Backends labelling entries themselves, like Docker with container ids, keep
+// their labels.
+func WithContextID(result LogSearchResult, contextID string) LogSearchResult {
+	if result == nil || contextID == "" {
+		return result
+	}
+	return &contextIDResult{LogSearchResult: result, contextID: contextID}
+}
+
+type contextIDResult struct {
+	LogSearchResult
+	contextID string
+}
+
+func (r *contextIDResult) GetEntries(ctx context.Context) ([]LogEntry, chan []LogEntry, error) {
+	entries, ch, err := r.LogSearchResult.GetEntries(ctx)
+	r.stamp(entries)
+	if ch == nil {
+		return entries, nil, err
+	}
+
+	stamped := make(chan []LogEntry)
+	go func() {
+		defer close(stamped)
+		for batch := range ch {
+			r.stamp(batch)
+			select {
+			case stamped <- batch:
+			case <-ctx.Done():
+				// Let the backend finish sending
+				for range ch {
+				}
+				return
+			}
+		}
+	}()
+	return entries, stamped, err
+}
+
+func (r *contextIDResult) stamp(entries []LogEntry) {
+	for i := range entries {
+		if entries[i].ContextID == "" {
+			entries[i].ContextID = r.contextID
+		}
+		NormalizeTimestamp(&entries[i])
+	}
+}
+
+// GetPaginationInfo binds the next page token to the context, so it is
+// rejected by the others.
+func (r *contextIDResult) GetPaginationInfo() *PaginationInfo {
+	return withPageTokenContext(r.LogSearchResult.GetPaginationInfo(), r.contextID)
+}
+
+// GetFieldNames forwards to the lister of the wrapped result.
+func (r *contextIDResult) GetFieldNames(ctx context.Context) ([]string, error) {
+	return ForwardFieldNames(ctx, r, r.LogSearchResult)
+}
+
+// Close closes the wrapped result.
+func (r *contextIDResult) Close() error {
+	return ForwardClose(r.LogSearchResult)
+}
@@ -0,0 +1,44 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+)
+
+// streamResult returns one entry and streams the batches of ch.
+type streamResult struct {
+	ch chan []client.LogEntry
+}
+
+func (r *streamResult) GetSearch() *client.LogSearch { return &client.LogSearch{} }
+func (r *streamResult) GetEntries(_ context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	return []client.LogEntry{{Message: "initial"}}, r.ch, nil
+}
+func (r *streamResult) GetFields(_ context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
+	return nil, nil, nil
+}
+func (r *streamResult) GetPaginationInfo() *client.PaginationInfo { return nil }
+func (r *streamResult) Err() <-chan error                         { return nil }
+
+func TestWithContextID(t *testing.T) {
+	assert.Nil(t, client.WithContextID(nil, "prod"))
+
+	ch := make(chan []client.LogEntry, 1)
+	ch <- []client.LogEntry{{Message: "streamed"}, {Message: "labelled", ContextID: "abc123"}}
+	close(ch)
+
+	result := client.WithContextID(&streamResult{ch: ch}, "prod")
+	entries, stream, err := result.GetEntries(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", entries[0].ContextID)
+
+	batch := <-stream
+	assert.Equal(t, "prod", batch[0].ContextID)
+	assert.Equal(t, "abc123", batch[1].ContextID)
+	_, open := <-stream
+	assert.False(t, open)
+}
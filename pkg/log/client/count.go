@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// countPageSize is the page size used to count the entries of a backend
+// without a native count, unless the backend caps its pages lower.
+const countPageSize = 500
+
+// Counter is implemented by backends that count the entries matching a
+// search natively, without fetching them.
+type Counter interface {
+	Count(ctx context.Context, search *LogSearch) (int, error)
+}
+
+// CountEntries returns the number of entries matching search on backend,
+// regardless of its size. Backends implementing Counter count them natively,
+// unless some filters are applied client-side; otherwise the entries of every
+// page are fetched and counted.
+func CountEntries(ctx context.Context, backend LogBackend, search *LogSearch) (int, error) {
+	if search.Follow {
+		return 0, errors.New("entries cannot be counted with --refresh")
+	}
+	if counter, ok := backend.(Counter); ok && len(Fallbacks(backend, search)) == 0 {
+		return counter.Count(ctx, search)
+	}
+
+	pageSize := countPageSize
+	if limit := maxPageSize(backend, search); limit > 0 && limit < pageSize {
+		pageSize = limit
+	}
+
+	page := search.Clone()
+	page.Size = ty.OptWrap(pageSize)
+	page.PageToken = ty.Opt[string]{}
+
+	total := 0
+	seen := make(map[string]bool)
+	for {
+		result, err := backend.Get(ctx, page)
+		if err != nil {
+			return 0, err
+		}
+		entries, ch, err := result.GetEntries(ctx)
+		if err != nil {
+			return 0, err
+		}
+		total += len(entries)
+		if ch != nil {
+			for batch := range ch {
+				total += len(batch)
+			}
+		}
+
+		// A token seen before would count the same entries again
+		info := result.GetPaginationInfo()
+		if info == nil || !info.HasMore || info.NextPageToken == "" || len(entries) == 0 || seen[info.NextPageToken] {
+			return total, nil
+		}
+		seen[info.NextPageToken] = true
+		page.PageToken = ty.OptWrap(info.NextPageToken)
+	}
+}
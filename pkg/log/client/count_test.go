@@ -0,0 +1,63 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingBackend counts natively and applies only equality conditions.
+type countingBackend struct {
+	equalsOnlyBackend
+	counted int
+}
+
+func (b *countingBackend) Count(_ context.Context, _ *client.LogSearch) (int, error) {
+	b.counted++
+	return b.total, nil
+}
+
+func TestCountEntries(t *testing.T) {
+	t.Run("uses the native count", func(t *testing.T) {
+		backend := &countingBackend{equalsOnlyBackend: equalsOnlyBackend{pagedBackend{total: 4200, pageCap: 1000}}}
+		search := &client.LogSearch{Fields: ty.MS{"level": "ERROR"}, Size: ty.OptWrap(10)}
+
+		count, err := client.CountEntries(context.Background(), backend, search)
+		require.NoError(t, err)
+		assert.Equal(t, 4200, count, "the size does not bound the count")
+		assert.Equal(t, 1, backend.counted)
+		assert.Empty(t, backend.requests)
+	})
+
+	t.Run("counts the pages when filters are applied client-side", func(t *testing.T) {
+		backend := &countingBackend{equalsOnlyBackend: equalsOnlyBackend{pagedBackend{total: 1200, pageCap: 1000}}}
+		search := &client.LogSearch{Filter: &client.Filter{Field: "msg", Op: operator.Regex, Value: "a.*"}}
+
+		count, err := client.CountEntries(context.Background(), backend, search)
+		require.NoError(t, err)
+		assert.Equal(t, 1200, count)
+		assert.Zero(t, backend.counted)
+	})
+
+	t.Run("counts every page without a native count", func(t *testing.T) {
+		backend := &pagedBackend{total: 1234, pageCap: 1000}
+		search := &client.LogSearch{Size: ty.OptWrap(10), PageToken: ty.OptWrap("600")}
+
+		count, err := client.CountEntries(context.Background(), backend, search)
+		require.NoError(t, err)
+		assert.Equal(t, 1234, count, "from the first page, whatever the token")
+		require.Len(t, backend.requests, 3)
+		assert.Equal(t, 500, backend.requests[0].Size.Value)
+		assert.Equal(t, 10, search.Size.Value, "the search is not modified")
+	})
+
+	t.Run("refuses follow mode", func(t *testing.T) {
+		_, err := client.CountEntries(context.Background(), &pagedBackend{}, &client.LogSearch{Follow: true})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultConcurrency is the number of contexts queried at once by a
+// multi-context search when no limit is configured.
+const DefaultConcurrency = 8
+
+// FanOut calls fn for each context id, running at most limit calls at once
+// (DefaultConcurrency when limit <= 0), and waits for them to return. Ids
+// still queued when ctx is done are skipped and ctx.Err() is returned.
+func FanOut(ctx context.Context, contextIDs []string, limit int, fn func(contextID string)) error {
+	if limit <= 0 {
+		limit = DefaultConcurrency
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	var err error
+
+	for _, cid := range contextIDs {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if err != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(cid string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(cid)
+		}(cid)
+	}
+
+	wg.Wait()
+	return err
+}
@@ -0,0 +1,53 @@
+package client_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFanOut_Limit(t *testing.T) {
+	var running, peak int32
+	var mu sync.Mutex
+	var seen []string
+
+	ids := []string{"a", "b", "c", "d", "e", "f"}
+	err := client.FanOut(context.Background(), ids, 2, func(cid string) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		mu.Lock()
+		seen = append(seen, cid)
+		mu.Unlock()
+	})
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, ids, seen)
+	assert.LessOrEqual(t, peak, int32(2))
+}
+
+func TestFanOut_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+
+	err := client.FanOut(ctx, []string{"a", "b", "c"}, 1, func(string) {
+		atomic.AddInt32(&calls, 1)
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), calls)
+}
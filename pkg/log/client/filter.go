@@ -27,7 +27,7 @@ type Filter struct {
 	// --- Leaf Node (Condition) ---
 	// If Field is set, this is a condition
 	Field  string `json:"field,omitempty" yaml:"field,omitempty"`
-	Op     string `json:"op,omitempty" yaml:"op,omitempty"` // e.g., "equals", "regex", "wildcard", "exists", "match", "gt", "gte", "lt", "lte"
+	Op     string `json:"op,omitempty" yaml:"op,omitempty"` // e.g., "equals", "regex", "wildcard", "exists", "not_exists", "match", "gt", "gte", "lt", "lte"
 	Value  string `json:"value,omitempty" yaml:"value,omitempty"`
 	Negate bool   `json:"negate,omitempty" yaml:"negate,omitempty"` // For != and !~= operators
 
@@ -61,22 +61,53 @@ func (f *Filter) Clone() *Filter {
 	return &clone
 }
 
-// Validate checks if the filter is structurally valid.
+// FilterError describes a malformed filter node. Path locates the node from
+// the root, e.g. "filters[1].filters[0]", and is empty for the root itself.
+type FilterError struct {
+	Path   string
+	Reason string
+}
+
+func (e *FilterError) Error() string {
+	if e.Path == "" {
+		return "invalid filter: " + e.Reason
+	}
+	return fmt.Sprintf("invalid filter at %s: %s", e.Path, e.Reason)
+}
+
+// Validate checks that the filter is structurally valid: leaves have a field
+// and a known operator, branches a known logic and no leaf properties. An
+// empty filter is valid and matches everything. The error is a *FilterError
+// locating the first malformed node.
 func (f *Filter) Validate() error {
+	return f.validate("")
+}
+
+func (f *Filter) validate(path string) error {
 	if f == nil {
 		return nil
 	}
+	invalid := func(format string, args ...any) error {
+		return &FilterError{Path: path, Reason: fmt.Sprintf(format, args...)}
+	}
 
 	isLeaf := f.Field != ""
 	isBranch := f.Logic != ""
 
 	// A filter must be either a leaf or a branch, not both
 	if isLeaf && isBranch {
-		return fmt.Errorf("filter cannot have both 'field' and 'logic' set")
+		return invalid("filter cannot have both 'field' and 'logic' set")
 	}
 
-	// Empty filter (neither leaf nor branch) is valid and means "match all"
+	// Empty filter (neither leaf nor branch) is valid and means "match all",
+	// but an operator or value without a field is a mistake
 	if !isLeaf && !isBranch {
+		if f.Op != "" || f.Value != "" || f.Negate {
+			return invalid("condition has no field (op=%q, value=%q)", f.Op, f.Value)
+		}
+		if len(f.Filters) > 0 {
+			return invalid("nested filters require a logic (AND, OR or NOT)")
+		}
 		return nil
 	}
 
@@ -84,21 +115,21 @@ func (f *Filter) Validate() error {
 	if isLeaf {
 		// Validate operator
 		switch f.Op {
-		case "", operator.Equals, operator.Match, operator.Wildcard, operator.Exists, operator.Regex,
+		case "", operator.Equals, operator.Match, operator.Wildcard, operator.Exists, operator.NotExists, operator.Regex,
 			operator.Gt, operator.Gte, operator.Lt, operator.Lte:
 			// valid
 		default:
-			return fmt.Errorf("invalid operator: %s", f.Op)
+			return invalid("unknown operator %q for field '%s'", f.Op, f.Field)
 		}
 
-		// 'exists' operator doesn't need a value, others do
-		if f.Op != operator.Exists && f.Value == "" {
-			return fmt.Errorf("filter with field '%s' requires a value (unless op is 'exists')", f.Field)
+		// 'exists' and 'not_exists' operators don't need a value, others do
+		if f.Op != operator.Exists && f.Op != operator.NotExists && f.Value == "" {
+			return invalid("filter with field '%s' requires a value (unless op is 'exists' or 'not_exists')", f.Field)
 		}
 
 		// Leaf nodes shouldn't have children
 		if len(f.Filters) > 0 {
-			return fmt.Errorf("leaf filter (field='%s') cannot have nested filters", f.Field)
+			return invalid("leaf filter (field='%s') cannot have nested filters", f.Field)
 		}
 	}
 
@@ -109,23 +140,27 @@ func (f *Filter) Validate() error {
 		case LogicAnd, LogicOr, LogicNot:
 			// valid
 		default:
-			return fmt.Errorf("invalid logic operator: %s", f.Logic)
+			return invalid("invalid logic operator: %s", f.Logic)
 		}
 
 		// NOT should ideally have at least one child
 		if f.Logic == LogicNot && len(f.Filters) == 0 {
-			return fmt.Errorf("NOT filter must have at least one child filter")
+			return invalid("NOT filter must have at least one child filter")
 		}
 
 		// Branch nodes shouldn't have leaf properties
-		if f.Value != "" {
-			return fmt.Errorf("branch filter (logic='%s') should not have a value", f.Logic)
+		if f.Value != "" || f.Op != "" {
+			return invalid("branch filter (logic='%s') should not have an op or value", f.Logic)
 		}
 
 		// Recursively validate children
-		for i, child := range f.Filters {
-			if err := child.Validate(); err != nil {
-				return fmt.Errorf("filter[%d]: %w", i, err)
+		for i := range f.Filters {
+			childPath := fmt.Sprintf("filters[%d]", i)
+			if path != "" {
+				childPath = path + "." + childPath
+			}
+			if err := f.Filters[i].validate(childPath); err != nil {
+				return err
 			}
 		}
 	}
@@ -197,9 +232,16 @@ func (f *Filter) matchLeaf(entry LogEntry) bool {
 	// Use LogEntry.Field() for consistent field access (handles case-insensitivity and struct fields)
 	fieldValRaw := entry.Field(f.Field)
 
-	// Handle "exists" operator
-	if f.Op == operator.Exists {
-		return fieldValRaw != "" && fieldValRaw != nil
+	// Handle "exists" and "not_exists" operators; an empty string counts as missing
+	if f.Op == operator.Exists || f.Op == operator.NotExists {
+		exists := fieldValRaw != "" && fieldValRaw != nil
+		if f.Op == operator.NotExists {
+			exists = !exists
+		}
+		if f.Negate {
+			return !exists
+		}
+		return exists
 	}
 
 	// Convert to string for comparison
@@ -239,6 +239,26 @@ func TestFilterMatch(t *testing.T) {
 		assert.False(t, f.Match(entry))
 	})
 
+	t.Run("exists - negated", func(t *testing.T) {
+		f := &client.Filter{Field: "trace_id", Op: operator.Exists, Negate: true}
+		assert.False(t, f.Match(entry))
+	})
+
+	t.Run("not_exists - field missing", func(t *testing.T) {
+		f := &client.Filter{Field: "nonexistent", Op: operator.NotExists}
+		assert.True(t, f.Match(entry))
+	})
+
+	t.Run("not_exists - field present", func(t *testing.T) {
+		f := &client.Filter{Field: "trace_id", Op: operator.NotExists}
+		assert.False(t, f.Match(entry))
+	})
+
+	t.Run("not_exists - empty string counts as missing", func(t *testing.T) {
+		f := &client.Filter{Field: "trace_id", Op: operator.NotExists}
+		assert.True(t, f.Match(client.LogEntry{Fields: ty.MI{"trace_id": ""}}))
+	})
+
 	t.Run("_ sentinel - searches message", func(t *testing.T) {
 		f := &client.Filter{Field: "_", Op: operator.Match, Value: "error"}
 		assert.True(t, f.Match(entry))
@@ -456,6 +476,48 @@ func TestGetEffectiveFilter(t *testing.T) {
 		assert.Equal(t, "level", f.Field)
 		assert.Equal(t, "ERROR", f.Value)
 	})
+
+	t.Run("legacy fields are sorted by name", func(t *testing.T) {
+		s := &client.LogSearch{
+			Fields: ty.MS{"zone": "a", "app": "b", "level": "c"},
+		}
+		for i := 0; i < 10; i++ {
+			f := s.GetEffectiveFilter()
+			assert.Equal(t, []string{"app", "level", "zone"}, []string{f.Filters[0].Field, f.Filters[1].Field, f.Filters[2].Field})
+		}
+	})
+
+	t.Run("exists condition without a field value is folded in", func(t *testing.T) {
+		s := &client.LogSearch{
+			Fields:          ty.MS{"level": "ERROR"},
+			FieldsCondition: ty.MS{"trace_id": operator.Exists, "user": operator.Regex},
+		}
+		f := s.GetEffectiveFilter()
+		assert.Equal(t, client.LogicAnd, f.Logic)
+		assert.Len(t, f.Filters, 2, "valueless regex condition is ignored")
+		assert.Equal(t, client.Filter{Field: "trace_id", Op: operator.Exists}, f.Filters[1])
+	})
+
+	t.Run("fields logic or groups the legacy fields", func(t *testing.T) {
+		s := &client.LogSearch{
+			Fields:      ty.MS{"level": "ERROR", "status": "500"},
+			FieldsLogic: ty.OptWrap("OR"),
+			Filter:      &client.Filter{Field: "app", Value: "api"},
+		}
+		f := s.GetEffectiveFilter()
+		assert.Equal(t, client.LogicAnd, f.Logic, "the Filter is still ANDed")
+		assert.Len(t, f.Filters, 2)
+		assert.Equal(t, client.LogicOr, f.Filters[0].Logic)
+		assert.Equal(t, []string{"level", "status"}, []string{f.Filters[0].Filters[0].Field, f.Filters[0].Filters[1].Field})
+		assert.Equal(t, "app", f.Filters[1].Field)
+
+		assert.True(t, s.GetEffectiveFilter().Filters[0].Match(client.LogEntry{Fields: ty.MI{"status": "500"}}))
+	})
+
+	t.Run("fields logic or with a single field is a leaf", func(t *testing.T) {
+		s := &client.LogSearch{Fields: ty.MS{"level": "ERROR"}, FieldsLogic: ty.OptWrap("or")}
+		assert.Equal(t, "level", s.GetEffectiveFilter().Field)
+	})
 }
 
 func TestMergeIntoWithFilter(t *testing.T) {
@@ -649,3 +711,38 @@ func TestFilterClone(t *testing.T) {
 		assert.Equal(t, "DEBUG", original.Filters[0].Value)
 	})
 }
+
+func TestFilterValidatePath(t *testing.T) {
+	f := &client.Filter{
+		Logic: client.LogicAnd,
+		Filters: []client.Filter{
+			{Field: "app", Value: "myapp"},
+			{Logic: client.LogicOr, Filters: []client.Filter{
+				{Field: "level", Value: "ERROR"},
+				{Op: operator.Regex, Value: "time.*out"}, // no field
+			}},
+		},
+	}
+	err := f.Validate()
+	var filterErr *client.FilterError
+	if assert.ErrorAs(t, err, &filterErr) {
+		assert.Equal(t, "filters[1].filters[1]", filterErr.Path)
+	}
+	assert.EqualError(t, err, `invalid filter at filters[1].filters[1]: condition has no field (op="regex", value="time.*out")`)
+
+	err = (&client.Filter{Field: "level", Op: "like", Value: "E%"}).Validate()
+	assert.EqualError(t, err, `invalid filter: unknown operator "like" for field 'level'`)
+
+	assert.Error(t, (&client.Filter{Filters: []client.Filter{{Field: "a", Value: "b"}}}).Validate(), "children without logic")
+	assert.NoError(t, (&client.Filter{Logic: client.LogicAnd}).Validate(), "empty groups match everything")
+	assert.NoError(t, (*client.Filter)(nil).Validate())
+}
+
+func TestLogSearchValidateFilter(t *testing.T) {
+	search := client.LogSearch{Filter: &client.Filter{Op: operator.Equals, Value: "x"}}
+	assert.Error(t, search.ValidateFilter())
+
+	search.NativeQuery.S("index=main")
+	search.NativeQueryOnly = true
+	assert.NoError(t, search.ValidateFilter(), "native-only searches ignore the filter")
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FieldCount is the number of entries sharing one value of a field.
+type FieldCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// GroupByField counts the distinct values of field across entries. Entries
+// without the field are skipped; "level" falls back to the entry level for
+// backends that don't keep it in Fields. Results are sorted by count descending, then
+// by value so the order is stable.
+func GroupByField(entries []LogEntry, field string) []FieldCount {
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		v, ok := entry.Fields[field]
+		if !ok && field == "level" {
+			v, ok = entry.Level, true
+		}
+		if !ok || v == nil {
+			continue
+		}
+		value := fmt.Sprint(v)
+		if value == "" {
+			continue
+		}
+		counts[value]++
+	}
+
+	result := make([]FieldCount, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, FieldCount{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
@@ -0,0 +1,74 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupByField(t *testing.T) {
+	entries := []client.LogEntry{
+		{Fields: ty.MI{"level": "INFO", "status": 200}},
+		{Fields: ty.MI{"level": "ERROR", "status": 500}},
+		{Fields: ty.MI{"level": "INFO", "status": 200}},
+		{Fields: ty.MI{"level": "WARN"}},
+		{Fields: ty.MI{"level": ""}},
+		{Fields: ty.MI{}},
+	}
+
+	t.Run("sorted by count then value", func(t *testing.T) {
+		counts := client.GroupByField(entries, "level")
+		assert.Equal(t, []client.FieldCount{
+			{Value: "INFO", Count: 2},
+			{Value: "ERROR", Count: 1},
+			{Value: "WARN", Count: 1},
+		}, counts)
+	})
+
+	t.Run("non-string values are stringified", func(t *testing.T) {
+		counts := client.GroupByField(entries, "status")
+		assert.Equal(t, []client.FieldCount{
+			{Value: "200", Count: 2},
+			{Value: "500", Count: 1},
+		}, counts)
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		assert.Empty(t, client.GroupByField(entries, "unknown"))
+	})
+}
+
+func TestGroupByField_EntryLevel(t *testing.T) {
+	entries := []client.LogEntry{
+		{Level: "ERROR"},
+		{Level: "ERROR", Fields: ty.MI{}},
+		{Level: "INFO", Fields: ty.MI{"level": "WARN"}},
+	}
+	assert.Equal(t, []client.FieldCount{
+		{Value: "ERROR", Count: 2},
+		{Value: "WARN", Count: 1},
+	}, client.GroupByField(entries, "level"))
+}
+
+func TestGetFieldFacetsFromResult(t *testing.T) {
+	search := &client.LogSearch{}
+	search.FieldExtraction.JSON.S(true)
+	result := &MockLogSearchResult{
+		Search: search,
+		Entries: []client.LogEntry{
+			{Level: "ERROR", Message: `{"service":"api"}`},
+			{Level: "INFO", Message: `{"service":"api"}`},
+			{Level: "INFO", Message: `{"service":"db"}`},
+			{Level: "INFO", Message: "plain"},
+		},
+	}
+
+	facets, err := client.GetFieldFacetsFromResult(context.Background(), result, []string{"service", "level", "missing"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"api": 2, "db": 1}, facets["service"], "entries without the field are not counted")
+	assert.Equal(t, map[string]int{"INFO": 3, "ERROR": 1}, facets["level"])
+	assert.Empty(t, facets["missing"])
+}
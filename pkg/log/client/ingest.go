@@ -0,0 +1,70 @@
+package client
+
+import "context"
+
+// WithIngest returns result with its entries prepared the same way for every
+// consumer, following the FieldExtraction of its search: JSON fields
+// extracted, the signature attached and the message truncated, in that order.
+func WithIngest(result LogSearchResult) LogSearchResult {
+	if result == nil {
+		return result
+	}
+	return &ingestResult{LogSearchResult: result}
+}
+
+// ingestEntry prepares entry like WithIngest does. Each step is idempotent,
+// so an entry already prepared by its backend is left unchanged.
+func ingestEntry(entry *LogEntry, search *LogSearch) {
+	if search == nil {
+		return
+	}
+	ExtractJSONFromEntry(entry, search)
+	AttachSignature(entry, search)
+	TruncateMessage(entry, search)
+}
+
+type ingestResult struct {
+	LogSearchResult
+}
+
+func (r *ingestResult) GetEntries(ctx context.Context) ([]LogEntry, chan []LogEntry, error) {
+	entries, ch, err := r.LogSearchResult.GetEntries(ctx)
+	r.ingest(entries)
+	if ch == nil {
+		return entries, nil, err
+	}
+
+	ingested := make(chan []LogEntry)
+	go func() {
+		defer close(ingested)
+		for batch := range ch {
+			r.ingest(batch)
+			select {
+			case ingested <- batch:
+			case <-ctx.Done():
+				// Let the backend finish sending
+				for range ch {
+				}
+				return
+			}
+		}
+	}()
+	return entries, ingested, err
+}
+
+func (r *ingestResult) ingest(entries []LogEntry) {
+	search := r.GetSearch()
+	for i := range entries {
+		ingestEntry(&entries[i], search)
+	}
+}
+
+// GetFieldNames forwards to the lister of the wrapped result.
+func (r *ingestResult) GetFieldNames(ctx context.Context) ([]string, error) {
+	return ForwardFieldNames(ctx, r, r.LogSearchResult)
+}
+
+// Close closes the wrapped result.
+func (r *ingestResult) Close() error {
+	return ForwardClose(r.LogSearchResult)
+}
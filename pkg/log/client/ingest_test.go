@@ -0,0 +1,46 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchStreamResult is a streamResult of search.
+type searchStreamResult struct {
+	streamResult
+	search *client.LogSearch
+}
+
+func (r *searchStreamResult) GetSearch() *client.LogSearch { return r.search }
+
+func TestWithIngest(t *testing.T) {
+	assert.Nil(t, client.WithIngest(nil))
+
+	search := &client.LogSearch{FieldExtraction: client.FieldExtraction{
+		JSON:             ty.OptWrap(true),
+		Signature:        ty.OptWrap(client.SignatureBasic),
+		MaxMessageLength: ty.OptWrap(8),
+	}}
+	ch := make(chan []client.LogEntry, 1)
+	ch <- []client.LogEntry{{Message: `{"message":"user 42 logged in","level":"INFO"}`}}
+	close(ch)
+
+	result := client.WithIngest(&searchStreamResult{streamResult: streamResult{ch: ch}, search: search})
+	entries, stream, err := result.GetEntries(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "initial", entries[0].Message)
+	assert.Equal(t, "initial", entries[0].Fields[client.SignatureField])
+
+	batch := <-stream
+	require.Len(t, batch, 1)
+	assert.Equal(t, "INFO", batch[0].Level, "JSON fields are extracted")
+	assert.Equal(t, "user <num> logged in", batch[0].Fields[client.SignatureField], "the signature is of the full message")
+	assert.Equal(t, "user 42 …(truncated 9 chars)", batch[0].Message)
+	_, open := <-stream
+	assert.False(t, open)
+}
@@ -9,4 +9,5 @@ type LogClient interface {
 	Query(ctx context.Context, search LogSearch) ([]LogEntry, error)
 	GetFields(ctx context.Context, search LogSearch) (map[string][]string, error)
 	GetValues(ctx context.Context, search LogSearch, field string) ([]string, error)
+	Count(ctx context.Context, search LogSearch) (int, error)
 }
@@ -1,17 +1,25 @@
 package client
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/bascanada/logviewer/pkg/log/client/operator"
 	"github.com/bascanada/logviewer/pkg/ty"
 )
 
 // VariableDefinition describes a dynamic parameter for a search context.
 // This provides metadata to UIs and LLMs about what inputs are expected.
+// Type (int, bool, enum or regex) and AllowedValues are checked against the
+// default and the provided value before the variable is substituted.
 type VariableDefinition struct {
-	Description string      `json:"description,omitempty"`
-	Type        string      `json:"type,omitempty"`
-	Default     interface{} `json:"default,omitempty"`
-	Required    bool        `json:"required,omitempty"`
+	Description   string      `json:"description,omitempty"`
+	Type          string      `json:"type,omitempty"`
+	Default       interface{} `json:"default,omitempty"`
+	Required      bool        `json:"required,omitempty"`
+	AllowedValues []string    `json:"allowedValues,omitempty" yaml:"allowedValues,omitempty"`
 }
 
 // SearchRange defines the time range for a search.
@@ -21,6 +29,45 @@ type SearchRange struct {
 	Last ty.Opt[string] `json:"last" yaml:"last"`
 }
 
+// RangeError is returned by SearchRange.Validate for inconsistent bounds.
+type RangeError struct {
+	Reason string
+}
+
+func (e *RangeError) Error() string {
+	return "invalid time range: " + e.Reason
+}
+
+// Validate checks the bounds given for one search: Last can't be combined
+// with Gte, as backends disagree on which one wins, and the bounds must be in
+// order (see ValidateOrder). Last with only Lte is the window ending at Lte.
+func (r SearchRange) Validate() error {
+	if r.Last.Value != "" && r.Gte.Value != "" {
+		return &RangeError{Reason: "last (" + r.Last.Value + ") conflicts with from (" + r.Gte.Value + "), use one or the other"}
+	}
+	return r.ValidateOrder()
+}
+
+// ValidateOrder checks Gte is not after Lte. Both are compared as instants,
+// so bounds in different time zones compare correctly, and only when both
+// are absolute timestamps; one-sided and relative ranges are valid.
+func (r SearchRange) ValidateOrder() error {
+	gte, okGte := parseRangeTime(r.Gte.Value)
+	lte, okLte := parseRangeTime(r.Lte.Value)
+	if okGte && okLte && gte.After(lte) {
+		return &RangeError{Reason: "from (" + r.Gte.Value + ") is after to (" + r.Lte.Value + ")"}
+	}
+	return nil
+}
+
+func parseRangeTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	return t, err == nil
+}
+
 // RefreshOptions defines options for auto-refreshing search results.
 type RefreshOptions struct {
 	Duration ty.Opt[string] `json:"duration,omitempty" yaml:"duration,omitempty"`
@@ -36,6 +83,22 @@ type FieldExtraction struct {
 	JSONMessageKey   ty.Opt[string] `json:"jsonMessageKey,omitempty" yaml:"jsonMessageKey,omitempty"`
 	JSONLevelKey     ty.Opt[string] `json:"jsonLevelKey,omitempty" yaml:"jsonLevelKey,omitempty"`
 	JSONTimestampKey ty.Opt[string] `json:"jsonTimestampKey,omitempty" yaml:"jsonTimestampKey,omitempty"`
+
+	// JSONAutoDetect enables JSON extraction when the first lines read are
+	// JSON objects, inferring the keys not set. Used when JSON is not set.
+	JSONAutoDetect ty.Opt[bool] `json:"jsonAutoDetect,omitempty" yaml:"jsonAutoDetect,omitempty"`
+
+	// TimestampZone is the IANA zone, e.g. Europe/Paris, of the parsed
+	// timestamps without an offset. Defaults to the local zone.
+	TimestampZone ty.Opt[string] `json:"timestampZone,omitempty" yaml:"timestampZone,omitempty"`
+
+	// Signature attaches a normalized message signature as the _signature
+	// field. Value is the normalization level: "basic" or "aggressive".
+	Signature ty.Opt[string] `json:"signature,omitempty" yaml:"signature,omitempty"`
+
+	// MaxMessageLength truncates messages to this many characters, storing
+	// the original length in the _message_length field. 0 means unlimited.
+	MaxMessageLength ty.Opt[int] `json:"maxMessageLength,omitempty" yaml:"maxMessageLength,omitempty"`
 }
 
 // PrinterOptions defines options for printing log entries (template, color, etc.).
@@ -43,6 +106,42 @@ type PrinterOptions struct {
 	Template     ty.Opt[string] `json:"template,omitempty" yaml:"template,omitempty"`
 	MessageRegex ty.Opt[string] `json:"messageRegex,omitempty" yaml:"messageRegex,omitempty"`
 	Color        ty.Opt[bool]   `json:"color,omitempty" yaml:"color,omitempty"`
+	// Highlight lists terms colored in printed messages, each in its own color.
+	Highlight []string `json:"highlight,omitempty" yaml:"highlight,omitempty"`
+	// HighlightCase makes Highlight matching case-sensitive.
+	HighlightCase ty.Opt[bool] `json:"highlightCase,omitempty" yaml:"highlightCase,omitempty"`
+	// Timezone is the zone timestamps are printed in: an IANA name, UTC or
+	// Local (the default).
+	Timezone ty.Opt[string] `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	// Rules pick the TUI row template of an entry: the first matching rule
+	// wins, and entries matching none use Template.
+	Rules []TemplateRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// TemplateRule formats the entries matching its condition with its own
+// template. Level and Field/Value are compared case-insensitively and must
+// all match when set; a Field without Value only needs the field present.
+type TemplateRule struct {
+	Level    string `json:"level,omitempty" yaml:"level,omitempty"`
+	Field    string `json:"field,omitempty" yaml:"field,omitempty"`
+	Value    string `json:"value,omitempty" yaml:"value,omitempty"`
+	Template string `json:"template" yaml:"template"`
+}
+
+// Matches reports whether entry meets the condition of the rule. A rule
+// without a condition matches every entry.
+func (r TemplateRule) Matches(entry LogEntry) bool {
+	if r.Level != "" && !strings.EqualFold(r.Level, entry.Level) {
+		return false
+	}
+	if r.Field == "" {
+		return true
+	}
+	value := entry.Field(r.Field)
+	if value == nil || value == "" {
+		return false
+	}
+	return r.Value == "" || strings.EqualFold(r.Value, fmt.Sprint(value))
 }
 
 // LogSearch defines the criteria for a log search operation.
@@ -51,10 +150,19 @@ type LogSearch struct {
 	// (e.g., Splunk SPL, OpenSearch DSL). Filters are appended to refine results.
 	NativeQuery ty.Opt[string] `json:"nativeQuery,omitempty" yaml:"nativeQuery,omitempty"`
 
+	// NativeQueryOnly sends NativeQuery exactly as written, without appending
+	// Fields/Filter conditions. The time range is still applied unless the native
+	// query already specifies one. Has no effect when NativeQuery is empty.
+	NativeQueryOnly bool `json:"nativeQueryOnly,omitempty" yaml:"nativeQueryOnly,omitempty"`
+
 	// Current filterring fields (legacy - use Filter for complex queries)
 	Fields ty.MS `json:"fields,omitempty" yaml:"fields,omitempty"`
 	// Extra rules for filtering fields (legacy - use Filter for complex queries)
 	FieldsCondition ty.MS `json:"fieldsCondition,omitempty" yaml:"fieldsCondition,omitempty"`
+	// FieldsLogic combines the Fields entries with "and" (default) or "or".
+	// A map holds one value per field: use Filter to match several values of
+	// the same field.
+	FieldsLogic ty.Opt[string] `json:"fieldsLogic,omitempty" yaml:"fieldsLogic,omitempty"`
 
 	// Filter is the new AST-based filter supporting nested logic (AND/OR/NOT)
 	Filter *Filter `json:"filter,omitempty" yaml:"filter,omitempty"`
@@ -79,6 +187,10 @@ type LogSearch struct {
 
 	PrinterOptions PrinterOptions `json:"printerOptions,omitempty" yaml:"printerOptions,omitempty"`
 
+	// Redact masks sensitive values in the returned entries. Merged searches
+	// add to it, so a context keeps the redaction it inherits.
+	Redact Redaction `json:"redact,omitempty" yaml:"redact,omitempty"`
+
 	// Variables defines the dynamic inputs for this search context.
 	// The map key is the variable name (e.g., "sessionId").
 	Variables map[string]VariableDefinition `json:"variables,omitempty"`
@@ -114,6 +226,19 @@ func (s *LogSearch) Clone() *LogSearch {
 		}
 	}
 
+	if s.PrinterOptions.Highlight != nil {
+		clone.PrinterOptions.Highlight = append([]string(nil), s.PrinterOptions.Highlight...)
+	}
+	if s.PrinterOptions.Rules != nil {
+		clone.PrinterOptions.Rules = append([]TemplateRule(nil), s.PrinterOptions.Rules...)
+	}
+	if s.Redact.Patterns != nil {
+		clone.Redact.Patterns = append([]string(nil), s.Redact.Patterns...)
+	}
+	if s.Redact.Fields != nil {
+		clone.Redact.Fields = append([]string(nil), s.Redact.Fields...)
+	}
+
 	// Deep copy Filter if it exists
 	if s.Filter != nil {
 		clone.Filter = s.Filter.Clone()
@@ -122,13 +247,47 @@ func (s *LogSearch) Clone() *LogSearch {
 	return &clone
 }
 
+// IsNativeQueryOnly reports whether backends should send only the native query,
+// ignoring Fields/FieldsCondition and Filter.
+func (s *LogSearch) IsNativeQueryOnly() bool {
+	return s.NativeQueryOnly && s.NativeQuery.Set && s.NativeQuery.Value != ""
+}
+
+// FieldsLogicOr reports whether the Fields entries are combined with OR.
+func (s *LogSearch) FieldsLogicOr() bool {
+	return s.FieldsLogic.Set && strings.EqualFold(s.FieldsLogic.Value, "or")
+}
+
 // GetEffectiveFilter returns a unified filter tree that combines legacy Fields/FieldsCondition
 // with the new Filter field. This allows backward compatibility while supporting new AST filters.
+//
+// It is the single source of truth for filtering: backends must build their
+// query from it rather than reading Fields or Filter directly. Precedence:
+//   - each Fields entry becomes a leaf, using the FieldsCondition operator for
+//     that field when set (equals otherwise);
+//   - a FieldsCondition of exists/not_exists applies even without a Fields value;
+//   - the legacy leaves, sorted by field name, are ORed together when
+//     FieldsLogic is "or" and ANDed otherwise;
+//   - the legacy leaves, or their OR group, and Filter are ANDed together.
+//
+// An empty search returns nil, which matches everything. NativeQuery is not
+// part of the result; backends combine it themselves (see IsNativeQueryOnly).
 func (s *LogSearch) GetEffectiveFilter() *Filter {
 	var allFilters []Filter
 
-	// 1. Convert Legacy Fields to Filter Nodes
-	for field, value := range s.Fields {
+	// 1. Convert Legacy Fields to Filter Nodes, in a stable order
+	fields := make([]string, 0, len(s.Fields)+len(s.FieldsCondition))
+	for field := range s.Fields {
+		fields = append(fields, field)
+	}
+	for field, condition := range s.FieldsCondition {
+		if _, ok := s.Fields[field]; !ok && (condition == operator.Exists || condition == operator.NotExists) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
 		op := operator.Equals
 		if condition, ok := s.FieldsCondition[field]; ok && condition != "" {
 			op = condition
@@ -137,9 +296,12 @@ func (s *LogSearch) GetEffectiveFilter() *Filter {
 		allFilters = append(allFilters, Filter{
 			Field: field,
 			Op:    op,
-			Value: value,
+			Value: s.Fields[field],
 		})
 	}
+	if len(allFilters) > 1 && s.FieldsLogicOr() {
+		allFilters = []Filter{{Logic: LogicOr, Filters: allFilters}}
+	}
 
 	// 2. Add the Explicit New Filter (if it exists)
 	if s.Filter != nil {
@@ -162,6 +324,15 @@ func (s *LogSearch) GetEffectiveFilter() *Filter {
 	}
 }
 
+// ValidateFilter checks the effective filter of the search with
+// Filter.Validate. Native-only searches don't use it and always pass.
+func (s *LogSearch) ValidateFilter() error {
+	if s.IsNativeQueryOnly() {
+		return nil
+	}
+	return s.GetEffectiveFilter().Validate()
+}
+
 // MergeInto merges another LogSearch into this one.
 func (s *LogSearch) MergeInto(logSeach *LogSearch) error {
 
@@ -184,6 +355,7 @@ func (s *LogSearch) MergeInto(logSeach *LogSearch) error {
 
 	s.Fields = ty.MergeM(s.Fields, logSeach.Fields)
 	s.FieldsCondition = ty.MergeM(s.FieldsCondition, logSeach.FieldsCondition)
+	s.FieldsLogic.Merge(&logSeach.FieldsLogic)
 	s.Options = ty.MergeM(s.Options, logSeach.Options)
 
 	// Merge Filter: AND them together if both exist
@@ -207,9 +379,22 @@ func (s *LogSearch) MergeInto(logSeach *LogSearch) error {
 	s.FieldExtraction.JSONMessageKey.Merge(&logSeach.FieldExtraction.JSONMessageKey)
 	s.FieldExtraction.JSONLevelKey.Merge(&logSeach.FieldExtraction.JSONLevelKey)
 	s.FieldExtraction.JSONTimestampKey.Merge(&logSeach.FieldExtraction.JSONTimestampKey)
+	s.FieldExtraction.JSONAutoDetect.Merge(&logSeach.FieldExtraction.JSONAutoDetect)
+	s.FieldExtraction.TimestampZone.Merge(&logSeach.FieldExtraction.TimestampZone)
+	s.FieldExtraction.Signature.Merge(&logSeach.FieldExtraction.Signature)
+	s.FieldExtraction.MaxMessageLength.Merge(&logSeach.FieldExtraction.MaxMessageLength)
 	s.PrinterOptions.Template.Merge(&logSeach.PrinterOptions.Template)
 	s.PrinterOptions.MessageRegex.Merge(&logSeach.PrinterOptions.MessageRegex)
 	s.PrinterOptions.Color.Merge(&logSeach.PrinterOptions.Color)
+	s.PrinterOptions.HighlightCase.Merge(&logSeach.PrinterOptions.HighlightCase)
+	s.PrinterOptions.Timezone.Merge(&logSeach.PrinterOptions.Timezone)
+	if len(logSeach.PrinterOptions.Highlight) > 0 {
+		s.PrinterOptions.Highlight = append([]string(nil), logSeach.PrinterOptions.Highlight...)
+	}
+	if len(logSeach.PrinterOptions.Rules) > 0 {
+		s.PrinterOptions.Rules = append([]TemplateRule(nil), logSeach.PrinterOptions.Rules...)
+	}
+	s.Redact.merge(logSeach.Redact)
 	s.Range.Gte.Merge(&logSeach.Range.Gte)
 
 	s.Range.Lte.Merge(&logSeach.Range.Lte)
@@ -220,6 +405,9 @@ func (s *LogSearch) MergeInto(logSeach *LogSearch) error {
 	if logSeach.Follow {
 		s.Follow = true
 	}
+	if logSeach.NativeQueryOnly {
+		s.NativeQueryOnly = true
+	}
 
 	return nil
 }
@@ -49,6 +49,22 @@ func TestMergingFollow(t *testing.T) {
 
 }
 
+func TestMergingNativeQueryOnly(t *testing.T) {
+	searchParent := client.LogSearch{}
+	searchChild := client.LogSearch{
+		NativeQuery:     ty.OptWrap("index=main"),
+		NativeQueryOnly: true,
+	}
+
+	_ = searchParent.MergeInto(&searchChild)
+
+	assert.True(t, searchParent.NativeQueryOnly, "NativeQueryOnly should be true after merge")
+	assert.True(t, searchParent.IsNativeQueryOnly())
+
+	// Without a native query the flag has no effect
+	assert.False(t, (&client.LogSearch{NativeQueryOnly: true}).IsNativeQueryOnly())
+}
+
 func TestMergingPrinterOptions(t *testing.T) {
 
 	searchParent := client.LogSearch{
@@ -75,6 +91,19 @@ func TestMergingPrinterOptions(t *testing.T) {
 
 }
 
+func TestTemplateRuleMatches(t *testing.T) {
+	entry := client.LogEntry{Level: "ERROR", Fields: ty.MI{"app": "API", "code": 500}}
+
+	assert.True(t, client.TemplateRule{}.Matches(entry), "no condition")
+	assert.True(t, client.TemplateRule{Level: "error"}.Matches(entry))
+	assert.False(t, client.TemplateRule{Level: "INFO"}.Matches(entry))
+	assert.True(t, client.TemplateRule{Field: "app", Value: "api"}.Matches(entry))
+	assert.True(t, client.TemplateRule{Field: "code", Value: "500"}.Matches(entry))
+	assert.True(t, client.TemplateRule{Field: "code"}.Matches(entry), "field present")
+	assert.False(t, client.TemplateRule{Field: "stack"}.Matches(entry), "field missing")
+	assert.False(t, client.TemplateRule{Level: "ERROR", Field: "app", Value: "web"}.Matches(entry), "all conditions must match")
+}
+
 func TestClone(t *testing.T) {
 	t.Run("Clone creates deep copy of LogSearch", func(t *testing.T) {
 		original := &client.LogSearch{
@@ -224,3 +253,52 @@ func TestClone(t *testing.T) {
 		assert.Equal(t, "app", original.Filter.Filters[1].Filters[0].Field)
 	})
 }
+
+func TestSearchRangeValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		r       client.SearchRange
+		wantErr string
+	}{
+		{name: "empty", r: client.SearchRange{}},
+		{name: "from only", r: client.SearchRange{Gte: ty.OptWrap("2024-01-02T00:00:00Z")}},
+		{name: "to only", r: client.SearchRange{Lte: ty.OptWrap("2024-01-02T00:00:00Z")}},
+		{name: "last with to", r: client.SearchRange{Last: ty.OptWrap("1h"), Lte: ty.OptWrap("2024-01-02T00:00:00Z")}},
+		{name: "in order", r: client.SearchRange{Gte: ty.OptWrap("2024-01-01T00:00:00Z"), Lte: ty.OptWrap("2024-01-02T00:00:00Z")}},
+		{name: "equal", r: client.SearchRange{Gte: ty.OptWrap("2024-01-01T00:00:00Z"), Lte: ty.OptWrap("2024-01-01T00:00:00Z")}},
+		// 01:00+02:00 is 23:00Z the day before
+		{name: "in order across zones", r: client.SearchRange{Gte: ty.OptWrap("2024-01-02T01:00:00+02:00"), Lte: ty.OptWrap("2024-01-02T00:00:00Z")}},
+		{name: "relative bounds", r: client.SearchRange{Gte: ty.OptWrap("2h"), Lte: ty.OptWrap("2024-01-01T00:00:00Z")}},
+		{
+			name:    "inverted",
+			r:       client.SearchRange{Gte: ty.OptWrap("2024-01-02T00:00:00.5Z"), Lte: ty.OptWrap("2024-01-02T00:00:00Z")},
+			wantErr: "invalid time range: from (2024-01-02T00:00:00.5Z) is after to (2024-01-02T00:00:00Z)",
+		},
+		{
+			name:    "inverted across zones",
+			r:       client.SearchRange{Gte: ty.OptWrap("2024-01-02T00:00:00Z"), Lte: ty.OptWrap("2024-01-02T01:00:00+02:00")},
+			wantErr: "is after to",
+		},
+		{
+			name:    "last with from",
+			r:       client.SearchRange{Last: ty.OptWrap("1h"), Gte: ty.OptWrap("2024-01-01T00:00:00Z")},
+			wantErr: "last (1h) conflicts with from",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.r.Validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var rangeErr *client.RangeError
+			assert.ErrorAs(t, err, &rangeErr)
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+
+	// Merged searches may hold a context's last next to the caller's from
+	merged := client.SearchRange{Last: ty.OptWrap("1h"), Gte: ty.OptWrap("2024-01-01T00:00:00Z")}
+	assert.NoError(t, merged.ValidateOrder())
+}
@@ -2,8 +2,12 @@ package client
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,6 +15,10 @@ import (
 )
 
 // LogEntry represents a single log record.
+//
+// JSON output of an entry is deterministic: encoding/json writes map keys,
+// including those of nested objects in Fields, in sorted order, so NDJSON
+// and MCP responses can be diffed and used as golden files.
 type LogEntry struct {
 	Timestamp time.Time `json:"timestamp"`
 	Message   string    `json:"message"`
@@ -45,6 +53,39 @@ func (e LogEntry) Field(key string) interface{} {
 	return ""
 }
 
+// EntryID returns a stable identifier for e, built from its timestamp and a
+// hash of its timestamp and message since backends have no common entry id.
+// The timestamp prefix lets the entry be found again with a narrow time range;
+// see ParseEntryID.
+func EntryID(e LogEntry) string {
+	nanos := strconv.FormatInt(e.Timestamp.UnixNano(), 10)
+	if e.Timestamp.IsZero() {
+		nanos = "0"
+	}
+	sum := sha256.Sum256([]byte(nanos + "\n" + e.Message))
+	return nanos + "-" + hex.EncodeToString(sum[:6])
+}
+
+// ParseEntryID returns the timestamp encoded in an EntryID. The zero time is
+// returned for entries without a timestamp.
+func ParseEntryID(id string) (time.Time, error) {
+	nanos, hash, ok := strings.Cut(id, "-")
+	if !ok || len(hash) != 12 {
+		return time.Time{}, fmt.Errorf("invalid entry id %q: expected <timestamp>-<hash>", id)
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return time.Time{}, fmt.Errorf("invalid entry id %q: %w", id, err)
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid entry id %q: %w", id, err)
+	}
+	if n == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(0, n).UTC(), nil
+}
+
 // LogSearchResult is the result of a search operation.
 // It provides methods to retrieve entries, fields, and pagination info.
 type LogSearchResult interface {
@@ -55,6 +96,65 @@ type LogSearchResult interface {
 	Err() <-chan error
 }
 
+// FieldNamesLister is implemented by results that can list field names
+// without collecting their distinct values.
+type FieldNamesLister interface {
+	GetFieldNames(context context.Context) ([]string, error)
+}
+
+// GetFieldNames returns the sorted field names of a result, using
+// FieldNamesLister when available and falling back to GetFields.
+func GetFieldNames(ctx context.Context, result LogSearchResult) ([]string, error) {
+	var names []string
+	if lister, ok := result.(FieldNamesLister); ok {
+		var err error
+		if names, err = lister.GetFieldNames(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if names, err = fieldNamesFromFields(ctx, result); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ForwardFieldNames lists the field names of outer, a result wrapping inner,
+// with the FieldNamesLister of inner when it has one, else from the
+// GetFields of outer. Wrappers forward GetFieldNames with it so they do not
+// hide the lister of the result they wrap.
+func ForwardFieldNames(ctx context.Context, outer, inner LogSearchResult) ([]string, error) {
+	if lister, ok := inner.(FieldNamesLister); ok {
+		return lister.GetFieldNames(ctx)
+	}
+	return fieldNamesFromFields(ctx, outer)
+}
+
+// ForwardClose closes inner when it has a Close method, like the Splunk
+// results cancelling their search job, and does nothing otherwise. Wrappers
+// implement Close with it so they do not hide the one of the result they
+// wrap.
+func ForwardClose(inner LogSearchResult) error {
+	if closer, ok := inner.(interface{ Close() error }); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func fieldNamesFromFields(ctx context.Context, result LogSearchResult) ([]string, error) {
+	fields, _, err := result.GetFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		names = append(names, k)
+	}
+	return names, nil
+}
+
 // PaginationInfo contains information about available pages of results.
 type PaginationInfo struct {
 	HasMore       bool
@@ -140,7 +240,7 @@ func ExtractJSONFromEntry(entry *LogEntry, search *LogSearch) {
 
 	// Extract timestamp
 	if v, ok := jsonMap[tsKey]; ok {
-		if parsed, err := parseTimestamp(v); err == nil && !parsed.IsZero() {
+		if parsed, err := parseTimestamp(v, TimestampLocationOrLocal(search)); err == nil && !parsed.IsZero() {
 			entry.Timestamp = parsed
 		}
 	}
@@ -197,15 +297,41 @@ func GetFieldValuesFromResult(ctx context.Context, result LogSearchResult, field
 	return result2, nil
 }
 
-// parseTimestamp attempts to parse various timestamp formats
-func parseTimestamp(value interface{}) (time.Time, error) {
+// GetFieldFacetsFromResult counts, for each of fields, the entries of result
+// per distinct value. Like GetFieldValuesFromResult it iterates through the
+// entries, and entries without a field are not counted for it.
+func GetFieldFacetsFromResult(ctx context.Context, result LogSearchResult, fields []string) (map[string]map[string]int, error) {
+	entries, _, err := result.GetEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := make(map[string]map[string]int, len(fields))
+	for _, field := range fields {
+		facets[field] = make(map[string]int)
+	}
+	for _, entry := range entries {
+		ExtractJSONFromEntry(&entry, result.GetSearch())
+		for _, field := range fields {
+			val := entry.Field(field)
+			if val != nil && val != "" {
+				facets[field][fmt.Sprintf("%v", val)]++
+			}
+		}
+	}
+	return facets, nil
+}
+
+// parseTimestamp attempts to parse various timestamp formats, in UTC. A
+// timestamp without an offset is in loc.
+func parseTimestamp(value interface{}, loc *time.Location) (time.Time, error) {
 	var timeStr string
 	switch v := value.(type) {
 	case string:
 		timeStr = v
 	case float64:
 		// Unix timestamp
-		return time.Unix(int64(v), 0), nil
+		return time.Unix(int64(v), 0).UTC(), nil
 	default:
 		return time.Time{}, fmt.Errorf("unsupported timestamp type: %T", value)
 	}
@@ -220,8 +346,8 @@ func parseTimestamp(value interface{}) (time.Time, error) {
 	}
 
 	for _, format := range formats {
-		if t, err := time.Parse(format, timeStr); err == nil {
-			return t, nil
+		if t, err := time.ParseInLocation(format, timeStr, loc); err == nil {
+			return t.UTC(), nil
 		}
 	}
 
@@ -0,0 +1,64 @@
+package client_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryID(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 10, 30, 0, 123456789, time.UTC)
+	entry := client.LogEntry{Timestamp: ts, Message: "payment failed", Level: "ERROR"}
+
+	id := client.EntryID(entry)
+	assert.Equal(t, id, client.EntryID(entry), "stable")
+	assert.Equal(t, id, client.EntryID(client.LogEntry{Timestamp: ts.In(time.FixedZone("EST", -5*3600)), Message: "payment failed"}),
+		"only timestamp and message count")
+	assert.NotEqual(t, id, client.EntryID(client.LogEntry{Timestamp: ts, Message: "payment ok"}))
+	assert.NotEqual(t, id, client.EntryID(client.LogEntry{Timestamp: ts.Add(time.Nanosecond), Message: "payment failed"}))
+
+	parsed, err := client.ParseEntryID(id)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(ts))
+}
+
+func TestEntryID_NoTimestamp(t *testing.T) {
+	id := client.EntryID(client.LogEntry{Message: "boot"})
+	assert.Regexp(t, `^0-[0-9a-f]{12}$`, id)
+
+	parsed, err := client.ParseEntryID(id)
+	require.NoError(t, err)
+	assert.True(t, parsed.IsZero())
+}
+
+func TestParseEntryID_Invalid(t *testing.T) {
+	for _, id := range []string{"", "abc", "123-xyz", "123-0123456789ab-1", "x-0123456789ab"} {
+		_, err := client.ParseEntryID(id)
+		assert.Error(t, err, id)
+	}
+}
+
+func TestLogEntry_JSONFieldOrder(t *testing.T) {
+	entry := client.LogEntry{
+		Timestamp: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC),
+		Message:   "done",
+		Fields: ty.MI{
+			"zone": "b",
+			"app":  "api",
+			"req":  map[string]interface{}{"path": "/", "id": 1, "headers": ty.MI{"x-b": "2", "x-a": "1"}},
+		},
+	}
+
+	want := `{"timestamp":"2024-05-01T10:30:00Z","message":"done","level":"",` +
+		`"fields":{"app":"api","req":{"headers":{"x-a":"1","x-b":"2"},"id":1,"path":"/"},"zone":"b"},"context_id":""}`
+	for i := 0; i < 20; i++ {
+		b, err := json.Marshal(entry)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(b))
+	}
+}
@@ -17,6 +17,8 @@ type MultiLogSearchResult struct {
 	Results []LogSearchResult
 	// a slice of errors encountered during the concurrent query execution.
 	Errors []error
+	// the per-context failures recorded by AddContext.
+	Failures MultiError
 	// the original LogSearch request that initiated the multi-context query.
 	Search *LogSearch
 	// mutex to protect concurrent access to Results and Errors slices.
@@ -54,6 +56,25 @@ func (m *MultiLogSearchResult) Add(result LogSearchResult, err error) {
 	}
 }
 
+// AddContext is Add for the result of contextID, recording a failure in
+// Failures so callers can tell which contexts failed and whether all did.
+// This method is safe for concurrent use.
+func (m *MultiLogSearchResult) AddContext(contextID string, result LogSearchResult, err error) {
+	m.Add(result, err)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.Failures.Total++
+	m.Failures.Add(contextID, err)
+}
+
+// ContextErr returns the per-context failures as a *MultiError, or nil when every
+// context added with AddContext succeeded.
+func (m *MultiLogSearchResult) ContextErr() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.Failures.ErrOrNil()
+}
+
 // GetSearch returns the original LogSearch request.
 func (m *MultiLogSearchResult) GetSearch() *LogSearch {
 	return m.Search
@@ -67,17 +88,25 @@ func (m *MultiLogSearchResult) GetEntries(ctx context.Context) ([]LogEntry, chan
 	var wg sync.WaitGroup
 	var subChannels []chan []LogEntry
 	var subChannelsResults []LogSearchResult
+	var partialErrs []error
 
 	for _, result := range m.Results {
 		wg.Add(1)
 		go func(r LogSearchResult) {
 			defer wg.Done()
 			entries, ch, err := r.GetEntries(ctx)
-			if err != nil {
-				// In a real-world scenario, you might want to handle this error more gracefully.
-				// For now, we'll just skip the results from this source.
+			if err != nil && !IsPartial(err) {
+				// Skip the results from this source.
 				return
 			}
+			if err != nil {
+				// Keep the entries received before the timeout
+				var partial *PartialResultError
+				errors.As(err, &partial)
+				mutex.Lock()
+				partialErrs = append(partialErrs, partial.Err)
+				mutex.Unlock()
+			}
 
 			// Get the search config for this individual result
 			resultSearch := r.GetSearch()
@@ -91,6 +120,7 @@ func (m *MultiLogSearchResult) GetEntries(ctx context.Context) ([]LogEntry, chan
 				entries[i].ContextID = contextID
 				// Apply JSON extraction based on this result's search config
 				ExtractJSONFromEntry(&entries[i], resultSearch)
+				NormalizeTimestamp(&entries[i])
 			}
 
 			mutex.Lock()
@@ -143,6 +173,7 @@ func (m *MultiLogSearchResult) GetEntries(ctx context.Context) ([]LogEntry, chan
 						for k := range entries {
 							entries[k].ContextID = contextID
 							ExtractJSONFromEntry(&entries[k], resultSearch)
+							NormalizeTimestamp(&entries[k])
 						}
 						mergedChannel <- entries
 					}
@@ -153,6 +184,9 @@ func (m *MultiLogSearchResult) GetEntries(ctx context.Context) ([]LogEntry, chan
 		}()
 	}
 
+	if len(partialErrs) > 0 {
+		return allEntries, mergedChannel, &PartialResultError{Err: errors.Join(partialErrs...)}
+	}
 	return allEntries, mergedChannel, nil
 }
 
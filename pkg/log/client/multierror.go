@@ -0,0 +1,92 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ContextError is the failure of one context of a multi-context operation.
+type ContextError struct {
+	ContextID string
+	Err       error
+}
+
+func (e *ContextError) Error() string {
+	return e.ContextID + ": " + e.Err.Error()
+}
+
+func (e *ContextError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON writes the error as {"contextId": ..., "error": ...}.
+func (e *ContextError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ContextID string `json:"contextId"`
+		Error     string `json:"error"`
+	}{e.ContextID, e.Err.Error()})
+}
+
+// MultiError gathers the failures of an operation run on several contexts.
+// Total is the number of contexts it ran on, so callers can tell a partial
+// result, worth showing with a warning, from one where every context failed.
+type MultiError struct {
+	Errors []*ContextError
+	Total  int
+}
+
+// Add records the failure of contextID; a nil err is ignored.
+func (e *MultiError) Add(contextID string, err error) {
+	if err != nil {
+		e.Errors = append(e.Errors, &ContextError{ContextID: contextID, Err: err})
+	}
+}
+
+// AllFailed reports whether every context failed.
+func (e *MultiError) AllFailed() bool {
+	return len(e.Errors) > 0 && len(e.Errors) >= e.Total
+}
+
+// Partial reports whether some contexts failed but not all of them.
+func (e *MultiError) Partial() bool {
+	return len(e.Errors) > 0 && len(e.Errors) < e.Total
+}
+
+// ErrOrNil returns e when a context failed, and nil otherwise.
+func (e *MultiError) ErrOrNil() error {
+	if e == nil || len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *MultiError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		parts[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of %d contexts failed: %s", len(e.Errors), e.Total, strings.Join(parts, "; "))
+}
+
+// Unwrap returns the first failure, so errors.Is and errors.As look into it.
+func (e *MultiError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors[0]
+}
+
+// MarshalJSON writes the failures for MCP and HTTP responses.
+func (e *MultiError) MarshalJSON() ([]byte, error) {
+	errs := e.Errors
+	if errs == nil {
+		errs = []*ContextError{}
+	}
+	return json.Marshal(struct {
+		Errors    []*ContextError `json:"errors"`
+		Failed    int             `json:"failed"`
+		Total     int             `json:"total"`
+		AllFailed bool            `json:"allFailed"`
+	}{errs, len(e.Errors), e.Total, e.AllFailed()})
+}
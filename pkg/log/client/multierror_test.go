@@ -0,0 +1,72 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiError(t *testing.T) {
+	t.Run("no failure is nil", func(t *testing.T) {
+		me := &client.MultiError{Total: 2}
+		me.Add("a", nil)
+		assert.NoError(t, me.ErrOrNil())
+		assert.False(t, me.AllFailed())
+		assert.False(t, me.Partial())
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		me := &client.MultiError{Total: 2}
+		me.Add("a", context.DeadlineExceeded)
+		require.Error(t, me.ErrOrNil())
+		assert.True(t, me.Partial())
+		assert.False(t, me.AllFailed())
+		assert.Equal(t, "1 of 2 contexts failed: a: context deadline exceeded", me.Error())
+	})
+
+	t.Run("all failed", func(t *testing.T) {
+		me := &client.MultiError{Total: 2}
+		me.Add("a", errors.New("boom"))
+		me.Add("b", errors.New("bang"))
+		assert.True(t, me.AllFailed())
+		assert.False(t, me.Partial())
+	})
+
+	t.Run("unwraps to the first failure", func(t *testing.T) {
+		me := &client.MultiError{Total: 2}
+		me.Add("a", context.DeadlineExceeded)
+		me.Add("b", errors.New("bang"))
+		assert.ErrorIs(t, me, context.DeadlineExceeded)
+
+		var ce *client.ContextError
+		require.ErrorAs(t, me, &ce)
+		assert.Equal(t, "a", ce.ContextID)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		me := &client.MultiError{Total: 3}
+		me.Add("a", errors.New("boom"))
+		b, err := json.Marshal(me)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"errors":[{"contextId":"a","error":"boom"}],"failed":1,"total":3,"allFailed":false}`, string(b))
+	})
+}
+
+func TestMultiLogSearchResult_AddContext(t *testing.T) {
+	multiRes, _ := client.NewMultiLogSearchResult(&client.LogSearch{})
+
+	multiRes.AddContext("a", &MockLogSearchResult{}, nil)
+	assert.NoError(t, multiRes.ContextErr())
+
+	multiRes.AddContext("b", nil, errors.New("boom"))
+	var me *client.MultiError
+	require.ErrorAs(t, multiRes.ContextErr(), &me)
+	assert.True(t, me.Partial())
+	assert.Equal(t, 2, me.Total)
+	assert.Len(t, multiRes.Errors, 1)
+}
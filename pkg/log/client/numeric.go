@@ -0,0 +1,82 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NumericStats summarizes the numeric values of a field: the distinct values
+// of a field like latency_ms say little, their distribution does.
+type NumericStats struct {
+	Count      int     `json:"count"`
+	NonNumeric int     `json:"non_numeric,omitempty"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	Avg        float64 `json:"avg"`
+	P50        float64 `json:"p50"`
+	P95        float64 `json:"p95"`
+	P99        float64 `json:"p99"`
+}
+
+// FieldOccurrences returns the value of field in each of entries that has
+// it, one per entry so each value weighs by how often it was logged.
+// Like GroupByField, "level" falls back to the entry level.
+func FieldOccurrences(entries []LogEntry, field string) []string {
+	values := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		v, ok := entry.Fields[field]
+		if !ok && field == "level" {
+			v, ok = entry.Level, true
+		}
+		if !ok || v == nil {
+			continue
+		}
+		if value := fmt.Sprint(v); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// SummarizeNumeric computes the stats of the values that parse as numbers,
+// counting the others in NonNumeric. It returns false when most values are
+// not numbers, as for a field that isn't numeric, and for no values.
+// Percentiles use the nearest rank.
+func SummarizeNumeric(values []string) (NumericStats, bool) {
+	numbers := make([]float64, 0, len(values))
+	stats := NumericStats{}
+	for _, value := range values {
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			stats.NonNumeric++
+			continue
+		}
+		numbers = append(numbers, n)
+	}
+	if len(numbers) == 0 || len(numbers) <= stats.NonNumeric {
+		return stats, false
+	}
+
+	sort.Float64s(numbers)
+	sum := 0.0
+	for _, n := range numbers {
+		sum += n
+	}
+	stats.Count = len(numbers)
+	stats.Min = numbers[0]
+	stats.Max = numbers[len(numbers)-1]
+	stats.Avg = sum / float64(len(numbers))
+	stats.P50 = nearestRank(numbers, 50)
+	stats.P95 = nearestRank(numbers, 95)
+	stats.P99 = nearestRank(numbers, 99)
+	return stats, true
+}
+
+// nearestRank returns the p-th percentile of the sorted numbers.
+func nearestRank(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,43 @@
+package client_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeNumeric(t *testing.T) {
+	values := make([]string, 0, 100)
+	for i := 1; i <= 100; i++ {
+		values = append(values, strconv.Itoa(i))
+	}
+	stats, ok := client.SummarizeNumeric(values)
+	assert.True(t, ok)
+	assert.Equal(t, client.NumericStats{Count: 100, Min: 1, Max: 100, Avg: 50.5, P50: 50, P95: 95, P99: 99}, stats)
+
+	// Mixed values: the others are counted and left out
+	stats, ok = client.SummarizeNumeric([]string{"12.5", " 3 ", "n/a", "7"})
+	assert.True(t, ok)
+	assert.Equal(t, client.NumericStats{Count: 3, NonNumeric: 1, Min: 3, Max: 12.5, Avg: 7.5, P50: 7, P95: 12.5, P99: 12.5}, stats)
+
+	_, ok = client.SummarizeNumeric([]string{"api", "web", "1"})
+	assert.False(t, ok, "mostly not numbers")
+	_, ok = client.SummarizeNumeric(nil)
+	assert.False(t, ok, "no values")
+	_, ok = client.SummarizeNumeric([]string{"NaN", "Inf"})
+	assert.False(t, ok)
+}
+
+func TestFieldOccurrences(t *testing.T) {
+	entries := []client.LogEntry{
+		{Level: "INFO", Fields: ty.MI{"latency_ms": 12}},
+		{Level: "INFO", Fields: ty.MI{"latency_ms": 12}},
+		{Level: "ERROR", Fields: ty.MI{"latency_ms": nil}},
+		{Level: "WARN"},
+	}
+	assert.Equal(t, []string{"12", "12"}, client.FieldOccurrences(entries, "latency_ms"))
+	assert.Equal(t, []string{"INFO", "INFO", "ERROR", "WARN"}, client.FieldOccurrences(entries, "level"))
+}
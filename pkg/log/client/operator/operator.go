@@ -8,8 +8,13 @@ const (
 	Match = "match"
 	// Wildcard performs a wildcard query.
 	Wildcard = "wildcard"
-	// Exists checks if a field exists.
+	// Exists checks if a field exists. A field holding an empty string is
+	// treated as missing by the in-memory evaluator and Splunk; OpenSearch and
+	// Kibana follow the backend's exists query, which counts indexed empty
+	// strings as present.
 	Exists = "exists"
+	// NotExists checks that a field is missing, using the same rule as Exists.
+	NotExists = "not_exists"
 	// Regex performs a regular expression match.
 	Regex = "regex"
 
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// PageTokenVersion is the version of the page tokens this build produces.
+// Tokens of an older version are still read, newer ones are rejected.
+const PageTokenVersion = 1
+
+// PageToken is the content of a NextPageToken: the cursor of a backend, an
+// offset, a timestamp or a search_after value, with the backend and the
+// context it belongs to so it can't be replayed against another one.
+type PageToken struct {
+	V       int    `json:"v"`
+	Backend string `json:"backend"`
+	Context string `json:"context,omitempty"`
+	Cursor  string `json:"cursor"`
+}
+
+// EncodePageToken returns the cursor of backend as an opaque page token.
+func EncodePageToken(backend, cursor string) string {
+	return PageToken{V: PageTokenVersion, Backend: backend, Cursor: cursor}.String()
+}
+
+// String returns the token as base64-encoded JSON.
+func (t PageToken) String() string {
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodePageToken parses a page token. A token that isn't one, like the raw
+// offsets and timestamps of older builds, is returned as the Cursor of a
+// version 0 token, which any backend accepts.
+func DecodePageToken(token string) (PageToken, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return PageToken{Cursor: token}, nil
+	}
+	var t PageToken
+	if err := json.Unmarshal(b, &t); err != nil || t.V == 0 {
+		return PageToken{Cursor: token}, nil
+	}
+	if t.V > PageTokenVersion {
+		return PageToken{}, fmt.Errorf("page token version %d is newer than the supported version %d, upgrade logviewer", t.V, PageTokenVersion)
+	}
+	return t, nil
+}
+
+// PageCursor returns the cursor of the page token of search for backend, or
+// "" when there is none. A token of another backend fails.
+func PageCursor(search *LogSearch, backend string) (string, error) {
+	if !search.PageToken.Set || search.PageToken.Value == "" {
+		return "", nil
+	}
+	t, err := DecodePageToken(search.PageToken.Value)
+	if err != nil {
+		return "", err
+	}
+	if t.V > 0 && t.Backend != backend {
+		return "", fmt.Errorf("invalid page token: it was returned by the %s backend, not %s", t.Backend, backend)
+	}
+	return t.Cursor, nil
+}
+
+// CheckPageTokenContext fails when the page token of search was returned by
+// another context than contextID.
+func CheckPageTokenContext(search *LogSearch, contextID string) error {
+	if !search.PageToken.Set || search.PageToken.Value == "" {
+		return nil
+	}
+	t, err := DecodePageToken(search.PageToken.Value)
+	if err != nil {
+		return err
+	}
+	if t.Context != "" && t.Context != contextID {
+		return fmt.Errorf("invalid page token: it was returned by context %q, not %q", t.Context, contextID)
+	}
+	return nil
+}
+
+// withPageTokenContext binds the page token of info to contextID.
+func withPageTokenContext(info *PaginationInfo, contextID string) *PaginationInfo {
+	if info == nil || info.NextPageToken == "" {
+		return info
+	}
+	t, err := DecodePageToken(info.NextPageToken)
+	if err != nil || t.V == 0 {
+		return info
+	}
+	t.Context = contextID
+	return &PaginationInfo{HasMore: info.HasMore, NextPageToken: t.String()}
+}
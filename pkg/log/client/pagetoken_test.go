@@ -0,0 +1,76 @@
+package client_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenResult returns no entries and the given next page token.
+type tokenResult struct {
+	streamResult
+	token string
+}
+
+func (r *tokenResult) GetPaginationInfo() *client.PaginationInfo {
+	return &client.PaginationInfo{HasMore: true, NextPageToken: r.token}
+}
+
+func TestPageToken(t *testing.T) {
+	token := client.EncodePageToken("splunk", "100")
+	decoded, err := client.DecodePageToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, client.PageToken{V: client.PageTokenVersion, Backend: "splunk", Cursor: "100"}, decoded)
+
+	search := &client.LogSearch{PageToken: ty.OptWrap(token)}
+	cursor, err := client.PageCursor(search, "splunk")
+	require.NoError(t, err)
+	assert.Equal(t, "100", cursor)
+	_, err = client.PageCursor(search, "opensearch")
+	assert.ErrorContains(t, err, "returned by the splunk backend, not opensearch")
+
+	cursor, err = client.PageCursor(&client.LogSearch{}, "splunk")
+	require.NoError(t, err)
+	assert.Empty(t, cursor, "no token")
+
+	// Raw cursors of older builds are still accepted by any backend
+	for _, legacy := range []string{"100", "2024-01-02T03:04:05.123Z", "not base64!"} {
+		cursor, err := client.PageCursor(&client.LogSearch{PageToken: ty.OptWrap(legacy)}, "cloudwatch")
+		require.NoError(t, err)
+		assert.Equal(t, legacy, cursor)
+	}
+
+	// Tokens of a newer build are rejected
+	newer := base64.RawURLEncoding.EncodeToString([]byte(`{"v":2,"backend":"splunk","cursor":"100"}`))
+	_, err = client.DecodePageToken(newer)
+	assert.ErrorContains(t, err, "newer than the supported version 1")
+}
+
+func TestPageToken_Context(t *testing.T) {
+	result := client.WithContextID(&tokenResult{token: client.EncodePageToken("splunk", "100")}, "prod")
+	info := result.GetPaginationInfo()
+	require.NotNil(t, info)
+	assert.True(t, info.HasMore)
+	decoded, err := client.DecodePageToken(info.NextPageToken)
+	require.NoError(t, err)
+	assert.Equal(t, "prod", decoded.Context)
+	assert.Equal(t, "100", decoded.Cursor)
+
+	search := &client.LogSearch{PageToken: ty.OptWrap(info.NextPageToken)}
+	assert.NoError(t, client.CheckPageTokenContext(search, "prod"))
+	assert.ErrorContains(t, client.CheckPageTokenContext(search, "staging"), `returned by context "prod", not "staging"`)
+
+	// The context doesn't stop the backend from reading its cursor
+	cursor, err := client.PageCursor(search, "splunk")
+	require.NoError(t, err)
+	assert.Equal(t, "100", cursor)
+
+	// Legacy tokens are left alone
+	legacy := client.WithContextID(&tokenResult{token: "100"}, "prod").GetPaginationInfo()
+	assert.Equal(t, "100", legacy.NextPageToken)
+	assert.NoError(t, client.CheckPageTokenContext(&client.LogSearch{PageToken: ty.OptWrap("100")}, "staging"))
+}
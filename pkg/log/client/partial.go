@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+)
+
+// PartialResultError is returned alongside entries when a backend timed out
+// after some of them were received. The entries are valid but incomplete;
+// Err is the timeout that cut the result short.
+type PartialResultError struct {
+	Err error
+}
+
+func (e *PartialResultError) Error() string {
+	return "partial results: " + e.Err.Error()
+}
+
+func (e *PartialResultError) Unwrap() error {
+	return e.Err
+}
+
+// IsPartial reports whether err marks a result as partial, i.e. the entries
+// returned with it should be shown rather than discarded.
+func IsPartial(err error) bool {
+	var partial *PartialResultError
+	return errors.As(err, &partial)
+}
+
+// IsTimeout reports whether err is a deadline or network timeout. Other
+// errors, like authentication failures, are fatal and never give partial
+// results.
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// PartialOnTimeout wraps err in a PartialResultError when it is a timeout and
+// entries were already received, and returns it unchanged otherwise. Backends
+// return it with the entries gathered when a fetch is cut short.
+func PartialOnTimeout(entries []LogEntry, err error) error {
+	if len(entries) == 0 || !IsTimeout(err) || IsPartial(err) {
+		return err
+	}
+	return &PartialResultError{Err: err}
+}
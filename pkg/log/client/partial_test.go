@@ -0,0 +1,73 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsTimeout(t *testing.T) {
+	assert.True(t, client.IsTimeout(context.DeadlineExceeded))
+	assert.True(t, client.IsTimeout(fmt.Errorf("search: %w", context.DeadlineExceeded)))
+	assert.True(t, client.IsTimeout(timeoutError{}))
+	assert.False(t, client.IsTimeout(errors.New("401 unauthorized")))
+	assert.False(t, client.IsTimeout(context.Canceled))
+	assert.False(t, client.IsTimeout(nil))
+}
+
+func TestGetAutoPaged_Partial(t *testing.T) {
+	t.Run("timeout keeps the pages received", func(t *testing.T) {
+		backend := &pagedBackend{total: 10000, pageCap: 1000, failAt: 3, err: fmt.Errorf("search: %w", context.DeadlineExceeded)}
+		result, err := client.GetAutoPaged(context.Background(), backend, &client.LogSearch{Size: ty.OptWrap(5000)})
+		require.NoError(t, err)
+
+		entries, _, err := result.GetEntries(context.Background())
+		require.Error(t, err)
+		assert.True(t, client.IsPartial(err))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Len(t, entries, 2000)
+		assert.Equal(t, "2000", result.GetPaginationInfo().NextPageToken, "a next page resumes after the timeout")
+	})
+
+	t.Run("fatal errors are not partial", func(t *testing.T) {
+		backend := &pagedBackend{total: 10000, pageCap: 1000, failAt: 2, err: errors.New("401 unauthorized")}
+		result, err := client.GetAutoPaged(context.Background(), backend, &client.LogSearch{Size: ty.OptWrap(5000)})
+		require.NoError(t, err)
+
+		_, _, err = result.GetEntries(context.Background())
+		require.Error(t, err)
+		assert.False(t, client.IsPartial(err))
+	})
+}
+
+func TestMultiLogSearchResult_Partial(t *testing.T) {
+	search := &client.LogSearch{Size: ty.OptWrap(5000)}
+	multi, err := client.NewMultiLogSearchResult(search)
+	require.NoError(t, err)
+
+	slow := &pagedBackend{total: 10000, pageCap: 1000, failAt: 2, err: context.DeadlineExceeded}
+	slowResult, err := client.GetAutoPaged(context.Background(), slow, search)
+	require.NoError(t, err)
+	multi.Add(slowResult, nil)
+
+	fast := &pagedBackend{total: 10, pageCap: 1000}
+	fastResult, err := client.GetAutoPaged(context.Background(), fast, search)
+	require.NoError(t, err)
+	multi.Add(fastResult, nil)
+
+	entries, _, err := multi.GetEntries(context.Background())
+	assert.True(t, client.IsPartial(err))
+	assert.Len(t, entries, 1010)
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type entryProgressKey struct{}
+
+// entryProgress counts the entries received for a search, added to by every
+// request and stream of its backend, concurrently.
+type entryProgress struct {
+	fetched atomic.Int64
+	report  func(fetched int)
+}
+
+// WithEntryProgress returns a context whose searches call report with the
+// number of entries received so far as their backend accumulates them: after
+// each page, or every few entries read from a stream. report may be called
+// often and from several goroutines, callers throttle what they send.
+func WithEntryProgress(ctx context.Context, report func(fetched int)) context.Context {
+	return context.WithValue(ctx, entryProgressKey{}, &entryProgress{report: report})
+}
+
+// withoutEntryProgress returns ctx without the progress of its search, for
+// the requests whose entries the caller counts itself.
+func withoutEntryProgress(ctx context.Context) context.Context {
+	if ctx.Value(entryProgressKey{}) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, entryProgressKey{}, (*entryProgress)(nil))
+}
+
+// AddEntryProgress adds n entries received to the progress of ctx, if any.
+func AddEntryProgress(ctx context.Context, n int) {
+	p, ok := ctx.Value(entryProgressKey{}).(*entryProgress)
+	if !ok || p == nil || p.report == nil || n <= 0 {
+		return
+	}
+	p.report(int(p.fetched.Add(int64(n))))
+}
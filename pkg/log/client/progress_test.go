@@ -0,0 +1,39 @@
+package client_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddEntryProgress(t *testing.T) {
+	client.AddEntryProgress(context.Background(), 10) // no progress, ignored
+
+	var last atomic.Int64
+	ctx := client.WithEntryProgress(context.Background(), func(fetched int) {
+		for {
+			prev := last.Load()
+			if int64(fetched) <= prev || last.CompareAndSwap(prev, int64(fetched)) {
+				return
+			}
+		}
+	})
+
+	// Concurrent streams add to one count
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				client.AddEntryProgress(ctx, 10)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(1000), last.Load())
+}
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// RedactionMask replaces the redacted parts of the entries.
+const RedactionMask = "***"
+
+// Redaction masks sensitive values, like PII or secrets, in the entries
+// returned for a search.
+type Redaction struct {
+	// Patterns are regular expressions whose matches in the message and in
+	// the string field values are replaced with RedactionMask.
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
+	// Fields are the fields, at any depth, whose whole value is replaced
+	// with RedactionMask, e.g. "email" masks both email and user.email.
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// IsZero reports whether the redaction masks nothing.
+func (r Redaction) IsZero() bool {
+	return len(r.Patterns) == 0 && len(r.Fields) == 0
+}
+
+// merge adds the patterns and fields of other not in r yet, so a search
+// keeps the redaction of the contexts it inherits.
+func (r *Redaction) merge(other Redaction) {
+	r.Patterns = appendMissing(r.Patterns, other.Patterns)
+	r.Fields = appendMissing(r.Fields, other.Fields)
+}
+
+func appendMissing(to, from []string) []string {
+	// Never append into an array shared with the search merged from
+	to = slices.Clip(to)
+	for _, v := range from {
+		if !slices.Contains(to, v) {
+			to = append(to, v)
+		}
+	}
+	return to
+}
+
+// Redactor applies a Redaction, its patterns compiled once.
+type Redactor struct {
+	patterns []*regexp.Regexp
+	fields   map[string]bool
+}
+
+// NewRedactor compiles r. It returns nil, which redacts nothing, when r is
+// empty.
+func NewRedactor(r Redaction) (*Redactor, error) {
+	if r.IsZero() {
+		return nil, nil
+	}
+	redactor := &Redactor{fields: make(map[string]bool, len(r.Fields))}
+	for _, p := range r.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		redactor.patterns = append(redactor.patterns, re)
+	}
+	for _, f := range r.Fields {
+		redactor.fields[f] = true
+	}
+	return redactor, nil
+}
+
+// Entry masks the message and the fields of entry in place.
+func (rd *Redactor) Entry(entry *LogEntry) {
+	if rd == nil {
+		return
+	}
+	entry.Message = rd.text(entry.Message)
+	for k, v := range entry.Fields {
+		entry.Fields[k] = rd.value(k, v)
+	}
+}
+
+// Values masks the values found for field, e.g. by GetFields.
+func (rd *Redactor) Values(field string, values []string) []string {
+	if rd == nil {
+		return values
+	}
+	masked := make([]string, 0, len(values))
+	for _, v := range values {
+		if rd.isField(field) {
+			v = RedactionMask
+		} else {
+			v = rd.text(v)
+		}
+		if !slices.Contains(masked, v) {
+			masked = append(masked, v)
+		}
+	}
+	return masked
+}
+
+func (rd *Redactor) value(key string, v any) any {
+	if rd.fields[key] {
+		return RedactionMask
+	}
+	switch v := v.(type) {
+	case string:
+		return rd.text(v)
+	case map[string]any:
+		for k, nested := range v {
+			v[k] = rd.value(k, nested)
+		}
+	case ty.MI:
+		for k, nested := range v {
+			v[k] = rd.value(k, nested)
+		}
+	case []any:
+		for i, nested := range v {
+			v[i] = rd.value("", nested)
+		}
+	}
+	return v
+}
+
+// isField reports whether the field, whose nested name is dotted in the
+// field sets, is masked whole.
+func (rd *Redactor) isField(field string) bool {
+	if rd.fields[field] {
+		return true
+	}
+	if i := strings.LastIndexByte(field, '.'); i >= 0 {
+		return rd.fields[field[i+1:]]
+	}
+	return false
+}
+
+func (rd *Redactor) text(s string) string {
+	for _, re := range rd.patterns {
+		s = re.ReplaceAllLiteralString(s, RedactionMask)
+	}
+	return s
+}
+
+// WithRedaction returns result with its entries and field values masked by
+// redactor. A nil redactor returns result unchanged.
+func WithRedaction(result LogSearchResult, redactor *Redactor) LogSearchResult {
+	if result == nil || redactor == nil {
+		return result
+	}
+	return &redactedResult{LogSearchResult: result, redactor: redactor}
+}
+
+type redactedResult struct {
+	LogSearchResult
+	redactor *Redactor
+}
+
+func (r *redactedResult) GetEntries(ctx context.Context) ([]LogEntry, chan []LogEntry, error) {
+	entries, ch, err := r.LogSearchResult.GetEntries(ctx)
+	r.redact(entries)
+	if ch == nil {
+		return entries, nil, err
+	}
+
+	redacted := make(chan []LogEntry)
+	go func() {
+		defer close(redacted)
+		for batch := range ch {
+			r.redact(batch)
+			select {
+			case redacted <- batch:
+			case <-ctx.Done():
+				// Let the backend finish sending
+				for range ch {
+				}
+				return
+			}
+		}
+	}()
+	return entries, redacted, err
+}
+
+func (r *redactedResult) redact(entries []LogEntry) {
+	for i := range entries {
+		r.redactor.Entry(&entries[i])
+	}
+}
+
+func (r *redactedResult) GetFields(ctx context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
+	fields, ch, err := r.LogSearchResult.GetFields(ctx)
+	fields = r.redactFields(fields)
+	if ch == nil {
+		return fields, nil, err
+	}
+
+	redacted := make(chan ty.UniSet[string])
+	go func() {
+		defer close(redacted)
+		for update := range ch {
+			select {
+			case redacted <- r.redactFields(update):
+			case <-ctx.Done():
+				for range ch {
+				}
+				return
+			}
+		}
+	}()
+	return fields, redacted, err
+}
+
+func (r *redactedResult) redactFields(fields ty.UniSet[string]) ty.UniSet[string] {
+	if fields == nil {
+		return nil
+	}
+	masked := make(ty.UniSet[string], len(fields))
+	for k, values := range fields {
+		masked[k] = r.redactor.Values(k, values)
+	}
+	return masked
+}
+
+// GetFieldNames forwards to the lister of the wrapped result, unless some
+// fields are masked: their names then come from the redacted GetFields.
+func (r *redactedResult) GetFieldNames(ctx context.Context) ([]string, error) {
+	if len(r.redactor.fields) > 0 {
+		return fieldNamesFromFields(ctx, r)
+	}
+	return ForwardFieldNames(ctx, r, r.LogSearchResult)
+}
+
+// Close closes the wrapped result.
+func (r *redactedResult) Close() error {
+	return ForwardClose(r.LogSearchResult)
+}
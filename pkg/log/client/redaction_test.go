@@ -0,0 +1,81 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor(t *testing.T) {
+	redactor, err := client.NewRedactor(client.Redaction{
+		Patterns: []string{`[\w.]+@[\w.]+`, `\b\d{4}-\d{4}-\d{4}-\d{4}\b`},
+		Fields:   []string{"password", "token"},
+	})
+	require.NoError(t, err)
+
+	t.Run("masks the matches in the message", func(t *testing.T) {
+		entry := client.LogEntry{Message: "alice@example.com paid with 4111-1111-1111-1111"}
+		redactor.Entry(&entry)
+		assert.Equal(t, "*** paid with ***", entry.Message)
+	})
+
+	t.Run("masks fields whole and the matches in their values", func(t *testing.T) {
+		entry := client.LogEntry{Fields: ty.MI{
+			"password": "hunter2",
+			"user":     map[string]any{"email": "bob@example.com", "token": 42},
+			"tags":     []any{"carol@example.com", "ok"},
+			"status":   200,
+		}}
+		redactor.Entry(&entry)
+		assert.Equal(t, "***", entry.Fields["password"])
+		assert.Equal(t, map[string]any{"email": "***", "token": "***"}, entry.Fields["user"])
+		assert.Equal(t, []any{"***", "ok"}, entry.Fields["tags"])
+		assert.Equal(t, 200, entry.Fields["status"])
+	})
+
+	t.Run("masks field values", func(t *testing.T) {
+		assert.Equal(t, []string{"***"}, redactor.Values("user.token", []string{"a", "b"}))
+		assert.Equal(t, []string{"***", "ok"}, redactor.Values("user", []string{"a@b.c", "d@e.f", "ok"}))
+	})
+
+	t.Run("nil redacts nothing", func(t *testing.T) {
+		none, err := client.NewRedactor(client.Redaction{})
+		require.NoError(t, err)
+		assert.Nil(t, none)
+		entry := client.LogEntry{Message: "alice@example.com"}
+		none.Entry(&entry)
+		assert.Equal(t, "alice@example.com", entry.Message)
+	})
+
+	_, err = client.NewRedactor(client.Redaction{Patterns: []string{"("}})
+	assert.ErrorContains(t, err, `invalid redaction pattern "("`)
+}
+
+func TestWithRedaction(t *testing.T) {
+	redactor, err := client.NewRedactor(client.Redaction{Patterns: []string{`secret-\w+`}})
+	require.NoError(t, err)
+
+	ch := make(chan []client.LogEntry, 1)
+	ch <- []client.LogEntry{{Message: "token secret-abc streamed"}}
+	close(ch)
+
+	result := client.WithRedaction(&streamResult{ch: ch}, redactor)
+	_, stream, err := result.GetEntries(context.Background())
+	require.NoError(t, err)
+	batch := <-stream
+	assert.Equal(t, "token *** streamed", batch[0].Message)
+
+	plain := &streamResult{}
+	assert.Same(t, plain, client.WithRedaction(plain, nil))
+}
+
+func TestLogSearchMergeRedaction(t *testing.T) {
+	base := client.LogSearch{Redact: client.Redaction{Fields: []string{"password"}}}
+	require.NoError(t, base.MergeInto(&client.LogSearch{Redact: client.Redaction{Fields: []string{"password", "token"}, Patterns: []string{`\d+`}}}))
+	assert.Equal(t, []string{"password", "token"}, base.Redact.Fields)
+	assert.Equal(t, []string{`\d+`}, base.Redact.Patterns)
+}
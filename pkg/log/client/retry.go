@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy bounds the retries of calls a backend throttled.
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls made, the first one included.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry, doubled on each retry
+	// up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// MaxElapsed bounds the total time spent waiting between attempts; a
+	// retry that would exceed it is not made.
+	MaxElapsed time.Duration
+}
+
+// DefaultRetryPolicy is the policy of the cloud backends.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	MaxElapsed:  30 * time.Second,
+}
+
+// ThrottledError marks an error as the backend refusing a call because of a
+// rate limit or quota, so the call may succeed later.
+type ThrottledError struct {
+	Err error
+	// RetryAfter is the wait the backend asked for, zero when it did not say.
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return "throttled: " + e.Err.Error()
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return e.Err
+}
+
+// BackendUnavailableError is returned once the retries of a throttled call
+// are exhausted.
+type BackendUnavailableError struct {
+	Err      error
+	Attempts int
+}
+
+func (e *BackendUnavailableError) Error() string {
+	return fmt.Sprintf("backend unavailable, still throttled after %d attempts: %v; "+
+		"narrow the query (shorter time range, more filters, smaller size) or retry later", e.Attempts, e.Err)
+}
+
+func (e *BackendUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// IsBackendUnavailable reports whether err is a BackendUnavailableError.
+func IsBackendUnavailable(err error) bool {
+	var unavailable *BackendUnavailableError
+	return errors.As(err, &unavailable)
+}
+
+// RetryThrottled calls op until it returns nil or an error that is not a
+// ThrottledError, waiting between throttled attempts as the policy says, or
+// as long as the backend asked if longer. It returns a
+// BackendUnavailableError when the policy runs out, and the context error
+// as soon as ctx is done. onRetry, when not nil, is called before each wait.
+func RetryThrottled(ctx context.Context, policy RetryPolicy, op func() error, onRetry func(err error, wait time.Duration)) error {
+	var waited time.Duration
+	delay := policy.BaseDelay
+	for attempt := 1; ; attempt++ {
+		err := op()
+		var throttled *ThrottledError
+		if err == nil || !errors.As(err, &throttled) {
+			return err
+		}
+
+		wait := max(delay, throttled.RetryAfter)
+		if attempt >= policy.MaxAttempts || waited+wait > policy.MaxElapsed {
+			return &BackendUnavailableError{Err: err, Attempts: attempt}
+		}
+		if onRetry != nil {
+			onRetry(err, wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		waited += wait
+		delay = min(delay*2, policy.MaxDelay)
+	}
+}
+
+// ReconnectingError is sent on the error channel of a followed result while
+// its backend reconnects after the connection dropped. The stream goes on
+// once reconnected, so it is a notice to show rather than a failure.
+type ReconnectingError struct {
+	Err         error
+	Attempt     int
+	MaxAttempts int
+	// Wait is the backoff before this attempt.
+	Wait time.Duration
+}
+
+func (e *ReconnectingError) Error() string {
+	return fmt.Sprintf("connection lost: %v; reconnecting in %s (attempt %d/%d)", e.Err, e.Wait, e.Attempt, e.MaxAttempts)
+}
+
+func (e *ReconnectingError) Unwrap() error {
+	return e.Err
+}
+
+// IsReconnecting reports whether err is a ReconnectingError, i.e. a stream
+// error that should be shown without stopping the stream.
+func IsReconnecting(err error) bool {
+	var reconnecting *ReconnectingError
+	return errors.As(err, &reconnecting)
+}
+
+// ParseRetryAfter returns the wait of a Retry-After header value, given in
+// seconds or as an HTTP date, and false when value is empty or invalid.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(at.Sub(now), 0), true
+}
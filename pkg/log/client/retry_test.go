@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond, MaxElapsed: time.Second}
+
+func TestRetryThrottled(t *testing.T) {
+	throttled := &ThrottledError{Err: errors.New("rate exceeded")}
+
+	calls := 0
+	var waits []time.Duration
+	err := RetryThrottled(context.Background(), testRetryPolicy, func() error {
+		calls++
+		if calls < 3 {
+			return throttled
+		}
+		return nil
+	}, func(_ error, wait time.Duration) { waits = append(waits, wait) })
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, waits)
+
+	calls = 0
+	err = RetryThrottled(context.Background(), testRetryPolicy, func() error { calls++; return throttled }, nil)
+	var unavailable *BackendUnavailableError
+	if assert.ErrorAs(t, err, &unavailable) {
+		assert.Equal(t, 3, unavailable.Attempts)
+	}
+	assert.True(t, IsBackendUnavailable(err))
+	assert.Contains(t, err.Error(), "narrow the query")
+	assert.Equal(t, 3, calls)
+
+	fatal := errors.New("access denied")
+	calls = 0
+	err = RetryThrottled(context.Background(), testRetryPolicy, func() error { calls++; return fatal }, nil)
+	assert.Equal(t, fatal, err, "other errors are not retried")
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryThrottledBounds(t *testing.T) {
+	// A Retry-After longer than the total budget gives up without waiting
+	calls := 0
+	start := time.Now()
+	err := RetryThrottled(context.Background(), testRetryPolicy, func() error {
+		calls++
+		return &ThrottledError{Err: errors.New("slow down"), RetryAfter: time.Hour}
+	}, nil)
+	assert.True(t, IsBackendUnavailable(err))
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(start), time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := testRetryPolicy
+	policy.BaseDelay = time.Minute
+	policy.MaxElapsed = time.Hour
+	err = RetryThrottled(ctx, policy, func() error { return &ThrottledError{Err: errors.New("slow down")} },
+		func(error, time.Duration) { cancel() })
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	d, ok := ParseRetryAfter("3", now)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+
+	d, ok = ParseRetryAfter("Wed, 01 May 2024 10:30:10 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, d)
+
+	d, ok = ParseRetryAfter("Wed, 01 May 2024 10:29:00 GMT", now)
+	assert.True(t, ok)
+	assert.Zero(t, d)
+
+	for _, value := range []string{"", "-1", "soon"} {
+		_, ok = ParseRetryAfter(value, now)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestIsReconnecting(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := fmt.Errorf("stream: %w", &ReconnectingError{Err: cause, Attempt: 1, MaxAttempts: 3, Wait: time.Second})
+
+	assert.True(t, IsReconnecting(err))
+	assert.ErrorIs(t, err, cause)
+	assert.Contains(t, err.Error(), "reconnecting in 1s (attempt 1/3)")
+	assert.False(t, IsReconnecting(cause))
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// SignatureField is the entry field holding the signature when
+// FieldExtraction.Signature is enabled.
+const SignatureField = "_signature"
+
+// Signature normalization levels, set through FieldExtraction.Signature.
+const (
+	// SignatureBasic replaces quoted literals, UUIDs, hex values and standalone numbers.
+	SignatureBasic = "basic"
+	// SignatureAggressive also replaces any word containing a digit, emails and URLs.
+	SignatureAggressive = "aggressive"
+)
+
+// maxSignatureInput bounds the part of the message that is normalized so
+// signatures stay cheap on very long messages.
+const maxSignatureInput = 512
+
+type signatureRule struct {
+	re          *regexp.Regexp
+	replacement string
+	// keep, when set, leaves matches it returns true for unchanged
+	keep func(match string) bool
+}
+
+func (r signatureRule) apply(s string) string {
+	if r.keep == nil {
+		return r.re.ReplaceAllString(s, r.replacement)
+	}
+	return r.re.ReplaceAllStringFunc(s, func(match string) string {
+		if r.keep(match) {
+			return match
+		}
+		return r.replacement
+	})
+}
+
+// Go's \b only knows ASCII word characters, so the hex and number rules match
+// whole unicode words and use keep to leave the ones that don't qualify.
+const signatureWord = `[\p{L}\p{Nd}_]+`
+
+// notMixedHex keeps words that are not hex made of both letters and digits,
+// so plain words like "defaced" and plain numbers are left alone.
+func notMixedHex(match string) bool {
+	if len(match) < 6 {
+		return true
+	}
+	hasDigit, hasLetter := false, false
+	for _, r := range match {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F'):
+			hasLetter = true
+		default:
+			return true
+		}
+	}
+	return !hasDigit || !hasLetter
+}
+
+// notNumber keeps words containing anything other than digits and the
+// separators of decimals and times.
+func notNumber(match string) bool {
+	for _, r := range match {
+		if !unicode.IsDigit(r) && r != '.' && r != ',' && r != ':' {
+			return true
+		}
+	}
+	return false
+}
+
+var basicSignatureRules = []signatureRule{
+	{regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`), "<str>", nil},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>", nil},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`), "<hex>", nil},
+	{regexp.MustCompile(signatureWord), "<hex>", notMixedHex},
+	{regexp.MustCompile(signatureWord + `(?:[.,:]` + signatureWord + `)*`), "<num>", notNumber},
+}
+
+var aggressiveSignatureRules = []signatureRule{
+	{regexp.MustCompile(`\b[a-z][a-z0-9+.-]*://\S+`), "<url>", nil},
+	{regexp.MustCompile(`\b[\w.+-]+@[\w-]+(?:\.[\w-]+)+\b`), "<email>", nil},
+	{regexp.MustCompile(`[\p{L}_./:-]*\p{Nd}[\p{L}\p{Nd}_./:-]*`), "<*>", nil},
+}
+
+var signatureSpaces = regexp.MustCompile(`\s+`)
+
+// Signature returns a stable signature for entry by normalizing away the
+// variable parts of its message (numbers, UUIDs, hex values, quoted literals),
+// so entries produced by the same log statement share one signature.
+func Signature(entry LogEntry) string {
+	return SignatureWithLevel(entry, SignatureBasic)
+}
+
+// SignatureWithLevel is Signature with an explicit normalization level.
+// Unknown levels fall back to SignatureBasic.
+func SignatureWithLevel(entry LogEntry, level string) string {
+	message := entry.Message
+	if len(message) > maxSignatureInput {
+		cut := maxSignatureInput
+		for cut > 0 && !utf8.RuneStart(message[cut]) {
+			cut--
+		}
+		message = message[:cut]
+	}
+
+	if level == SignatureAggressive {
+		// URLs and emails first, before their parts get rewritten
+		for _, rule := range aggressiveSignatureRules[:2] {
+			message = rule.apply(message)
+		}
+	}
+	for _, rule := range basicSignatureRules {
+		message = rule.apply(message)
+	}
+	if level == SignatureAggressive {
+		message = aggressiveSignatureRules[2].apply(message)
+	}
+
+	return strings.TrimSpace(signatureSpaces.ReplaceAllString(message, " "))
+}
+
+// AttachSignature stores the entry signature in Fields[SignatureField] when
+// the search enables FieldExtraction.Signature.
+func AttachSignature(entry *LogEntry, search *LogSearch) {
+	if search == nil || !search.FieldExtraction.Signature.Set || search.FieldExtraction.Signature.Value == "" {
+		return
+	}
+	if entry.Fields == nil {
+		entry.Fields = make(ty.MI)
+	}
+	entry.Fields[SignatureField] = SignatureWithLevel(*entry, search.FieldExtraction.Signature.Value)
+}
@@ -0,0 +1,76 @@
+package client_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignature(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"numbers", "request took 152ms for 3 items", "request took 152ms for <num> items"},
+		{"decimal and time", "latency 12.5 at 10:42:01", "latency <num> at <num>"},
+		{"uuid", "order 3f2b1c9e-8a7d-4e6f-9b0a-1c2d3e4f5a6b not found", "order <uuid> not found"},
+		{"hex", "pointer 0xdeadbeef, commit 9fceb02a", "pointer <hex>, commit <hex>"},
+		{"hex-looking words kept", "defaced cafe", "defaced cafe"},
+		{"quoted", `user "alice" opened 'report.pdf'`, "user <str> opened <str>"},
+		{"whitespace collapsed", "  a   b\t\tc  ", "a b c"},
+		{"unicode digits", "délai dépassé après ٣٠ secondes", "délai dépassé après <num> secondes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, client.Signature(client.LogEntry{Message: tt.message}))
+		})
+	}
+}
+
+func TestSignature_SameStatementSameSignature(t *testing.T) {
+	a := client.Signature(client.LogEntry{Message: `user 42 failed login from "10.0.0.1"`})
+	b := client.Signature(client.LogEntry{Message: `user 7 failed login from "192.168.1.20"`})
+	assert.Equal(t, a, b)
+}
+
+func TestSignatureWithLevel_Aggressive(t *testing.T) {
+	entry := client.LogEntry{Message: "GET https://api.example.com/v1/users?id=9 by bob@example.com took 152ms on pod-7f9c"}
+	got := client.SignatureWithLevel(entry, client.SignatureAggressive)
+	assert.Equal(t, "GET <url> by <email> took <*> on <*>", got)
+
+	// Basic leaves those parts alone
+	basic := client.SignatureWithLevel(entry, client.SignatureBasic)
+	assert.Contains(t, basic, "bob@example.com")
+
+	// Unknown level behaves like basic
+	assert.Equal(t, basic, client.SignatureWithLevel(entry, "unknown"))
+}
+
+func TestSignature_LongMessageTruncated(t *testing.T) {
+	long := "error " + strings.Repeat("é", 2000)
+	got := client.Signature(client.LogEntry{Message: long})
+	assert.LessOrEqual(t, len(got), 512)
+	assert.True(t, strings.HasPrefix(got, "error é"))
+	assert.False(t, strings.ContainsRune(got, '�'))
+}
+
+func TestAttachSignature(t *testing.T) {
+	search := &client.LogSearch{}
+	entry := client.LogEntry{Message: "job 12 done"}
+
+	client.AttachSignature(&entry, search)
+	assert.Nil(t, entry.Fields, "disabled by default")
+
+	search.FieldExtraction.Signature = ty.OptWrap(client.SignatureBasic)
+	client.AttachSignature(&entry, search)
+	assert.Equal(t, "job <num> done", entry.Fields[client.SignatureField])
+
+	entry = client.LogEntry{Message: "job 12 done", Fields: ty.MI{"app": "worker"}}
+	client.AttachSignature(&entry, search)
+	assert.Equal(t, "worker", entry.Fields["app"])
+	assert.Equal(t, "job <num> done", entry.Fields[client.SignatureField])
+}
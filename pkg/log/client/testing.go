@@ -10,6 +10,7 @@ type MockLogClient struct {
 	OnQuery    func(search LogSearch) ([]LogEntry, error)
 	OnFields   func(search LogSearch) (map[string][]string, error)
 	OnValues   func(search LogSearch, field string) ([]string, error)
+	OnCount    func(search LogSearch) (int, error)
 }
 
 func (m *MockLogClient) Query(ctx context.Context, s LogSearch) ([]LogEntry, error) {
@@ -33,3 +34,11 @@ func (m *MockLogClient) GetValues(ctx context.Context, s LogSearch, field string
 	}
 	return []string{}, nil
 }
+
+func (m *MockLogClient) Count(ctx context.Context, s LogSearch) (int, error) {
+	m.LastSearch = s
+	if m.OnCount != nil {
+		return m.OnCount(s)
+	}
+	return 0, nil
+}
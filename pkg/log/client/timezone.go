@@ -0,0 +1,49 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// locations caches the zones loaded by name, since time.LoadLocation reads
+// the zone database each time.
+var locations sync.Map
+
+// TimestampLocation returns the zone assumed for the timestamps without an
+// offset parsed for search: FieldExtraction.TimestampZone, an IANA name like
+// Europe/Paris, or the local zone when it is unset.
+func TimestampLocation(search *LogSearch) (*time.Location, error) {
+	if search == nil || !search.FieldExtraction.TimestampZone.Set || search.FieldExtraction.TimestampZone.Value == "" {
+		return time.Local, nil
+	}
+	name := search.FieldExtraction.TimestampZone.Value
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fieldExtraction.timestampZone %q: %w", name, err)
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// TimestampLocationOrLocal is TimestampLocation falling back to the local
+// zone for an invalid zone, which the factory rejects before searching.
+func TimestampLocationOrLocal(search *LogSearch) *time.Location {
+	loc, err := TimestampLocation(search)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// NormalizeTimestamp stores the timestamp of entry in UTC, so entries of
+// backends reporting different zones sort and merge on the same clock. The
+// printers convert it to the display zone.
+func NormalizeTimestamp(entry *LogEntry) {
+	if !entry.Timestamp.IsZero() {
+		entry.Timestamp = entry.Timestamp.UTC()
+	}
+}
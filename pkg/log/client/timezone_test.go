@@ -0,0 +1,95 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampLocation(t *testing.T) {
+	loc, err := client.TimestampLocation(&client.LogSearch{})
+	require.NoError(t, err)
+	assert.Equal(t, time.Local, loc)
+
+	search := &client.LogSearch{}
+	search.FieldExtraction.TimestampZone.S("America/New_York")
+	loc, err = client.TimestampLocation(search)
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York", loc.String())
+
+	search.FieldExtraction.TimestampZone.S("Mars/Olympus")
+	_, err = client.TimestampLocation(search)
+	assert.ErrorContains(t, err, "timestampZone")
+	assert.Equal(t, time.Local, client.TimestampLocationOrLocal(search))
+}
+
+func TestExtractJSONFromEntry_TimestampZone(t *testing.T) {
+	search := &client.LogSearch{}
+	search.FieldExtraction.JSON.S(true)
+	search.FieldExtraction.TimestampZone.S("America/New_York")
+	extract := func(ts string) time.Time {
+		entry := client.LogEntry{Message: `{"timestamp":"` + ts + `","message":"m"}`}
+		client.ExtractJSONFromEntry(&entry, search)
+		assert.Equal(t, time.UTC, entry.Timestamp.Location())
+		return entry.Timestamp
+	}
+
+	// Before and after the March 10 2024 DST change
+	assert.Equal(t, time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC), extract("2024-03-09 10:00:00"))
+	assert.Equal(t, time.Date(2024, 3, 11, 14, 0, 0, 0, time.UTC), extract("2024-03-11 10:00:00"))
+	assert.Equal(t, time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC), extract("2024-03-11T10:00:00+02:00"),
+		"an offset wins over the zone")
+}
+
+func TestWithContextID_NormalizesToUTC(t *testing.T) {
+	paris := time.FixedZone("CEST", 2*60*60)
+	ch := make(chan []client.LogEntry, 1)
+	ch <- []client.LogEntry{{Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, paris)}}
+	close(ch)
+
+	entries, stream, err := client.WithContextID(&streamResult{ch: ch}, "prod").GetEntries(context.Background())
+	require.NoError(t, err)
+	assert.True(t, entries[0].Timestamp.IsZero(), "zero timestamps stay zero")
+	batch := <-stream
+	assert.Equal(t, time.UTC, batch[0].Timestamp.Location())
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), batch[0].Timestamp)
+}
+
+// zonedResult returns entries from a backend reporting them in its own zone.
+type zonedResult struct {
+	streamResult
+	search  *client.LogSearch
+	entries []client.LogEntry
+}
+
+func (r *zonedResult) GetSearch() *client.LogSearch { return r.search }
+func (r *zonedResult) GetEntries(_ context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	return r.entries, nil, nil
+}
+
+func TestMultiLogSearchResult_MergesZonesInUTC(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	newYork := time.FixedZone("EST", -5*60*60)
+	multi := &client.MultiLogSearchResult{Results: []client.LogSearchResult{
+		&zonedResult{search: &client.LogSearch{Options: ty.MI{"__context_id__": "tokyo"}}, entries: []client.LogEntry{
+			{Message: "tokyo", Timestamp: time.Date(2024, 1, 2, 0, 30, 0, 0, tokyo)}, // 15:30 UTC the day before
+		}},
+		&zonedResult{search: &client.LogSearch{Options: ty.MI{"__context_id__": "ny"}}, entries: []client.LogEntry{
+			{Message: "ny", Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, newYork)}, // 15:00 UTC
+		}},
+	}, Search: &client.LogSearch{}}
+
+	entries, _, err := multi.GetEntries(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "ny", entries[0].Message)
+	assert.Equal(t, "tokyo", entries[1].Message)
+	for _, entry := range entries {
+		assert.Equal(t, time.UTC, entry.Timestamp.Location())
+	}
+}
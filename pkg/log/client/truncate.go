@@ -0,0 +1,45 @@
+package client
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// MessageLengthField is the entry field holding the original message length,
+// in characters, when the message was truncated by FieldExtraction.MaxMessageLength.
+const MessageLengthField = "_message_length"
+
+// TruncateMessage shortens entry.Message to FieldExtraction.MaxMessageLength
+// characters and appends a "…(truncated N chars)" marker. It runs after
+// ExtractJSONFromEntry so JSON is parsed from the full message, never splits
+// a rune, and is idempotent: an entry already truncated is left unchanged.
+func TruncateMessage(entry *LogEntry, search *LogSearch) {
+	if search == nil || !search.FieldExtraction.MaxMessageLength.Set || search.FieldExtraction.MaxMessageLength.Value <= 0 {
+		return
+	}
+	if _, done := entry.Fields[MessageLengthField]; done {
+		return
+	}
+	maxLength := search.FieldExtraction.MaxMessageLength.Value
+	if len(entry.Message) <= maxLength {
+		// Fewer bytes than the limit is always fewer characters
+		return
+	}
+	length := utf8.RuneCountInString(entry.Message)
+	if length <= maxLength {
+		return
+	}
+
+	cut := 0
+	for i := 0; i < maxLength; i++ {
+		_, size := utf8.DecodeRuneInString(entry.Message[cut:])
+		cut += size
+	}
+	entry.Message = fmt.Sprintf("%s…(truncated %d chars)", entry.Message[:cut], length-maxLength)
+	if entry.Fields == nil {
+		entry.Fields = make(ty.MI)
+	}
+	entry.Fields[MessageLengthField] = length
+}
@@ -0,0 +1,53 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateMessage(t *testing.T) {
+	search := &client.LogSearch{}
+	search.FieldExtraction.MaxMessageLength.S(5)
+
+	t.Run("truncates with a marker", func(t *testing.T) {
+		entry := client.LogEntry{Message: "0123456789"}
+		client.TruncateMessage(&entry, search)
+		assert.Equal(t, "01234…(truncated 5 chars)", entry.Message)
+		assert.Equal(t, 10, entry.Fields[client.MessageLengthField])
+
+		client.TruncateMessage(&entry, search)
+		assert.Equal(t, "01234…(truncated 5 chars)", entry.Message, "idempotent")
+	})
+
+	t.Run("does not split runes", func(t *testing.T) {
+		entry := client.LogEntry{Message: "héllo wörld"}
+		client.TruncateMessage(&entry, search)
+		assert.Equal(t, "héllo…(truncated 6 chars)", entry.Message)
+	})
+
+	t.Run("short messages and unlimited", func(t *testing.T) {
+		entry := client.LogEntry{Message: "éééé"} // 8 bytes, 4 chars
+		client.TruncateMessage(&entry, search)
+		assert.Equal(t, "éééé", entry.Message)
+		assert.Nil(t, entry.Fields)
+
+		unlimited := &client.LogSearch{}
+		unlimited.FieldExtraction.MaxMessageLength.S(0)
+		entry = client.LogEntry{Message: "0123456789"}
+		client.TruncateMessage(&entry, unlimited)
+		assert.Equal(t, "0123456789", entry.Message)
+	})
+
+	t.Run("json is extracted from the full message", func(t *testing.T) {
+		jsonSearch := search.Clone()
+		jsonSearch.FieldExtraction.JSON.S(true)
+		entry := client.LogEntry{Message: `{"message":"a long message","user":"bob"}`}
+		client.ExtractJSONFromEntry(&entry, jsonSearch)
+		client.TruncateMessage(&entry, jsonSearch)
+		assert.Equal(t, "a lon…(truncated 9 chars)", entry.Message)
+		assert.Equal(t, ty.MI{"user": "bob", client.MessageLengthField: 14}, entry.Fields)
+	})
+}
@@ -5,6 +5,7 @@ package factory
 
 import (
 	"errors"
+	"fmt"
 	"runtime"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
@@ -52,10 +53,9 @@ func GetLogBackendFactory(clients config.Clients) (LogBackendFactory, error) {
 		v.Options = v.Options.ResolveVariables()
 		switch v.Type {
 		case "opensearch":
-			options := v.Options
 			logBackendFactory.clients[k] = ty.GetLazy(func() (*client.LogBackend, error) {
 				vv, err := opensearch.GetClient(opensearch.Target{
-					Endpoint: options.GetString("endpoint"),
+					Endpoint: v.Options.GetString("endpoint"),
 				})
 				if err != nil {
 					return nil, err
@@ -64,9 +64,8 @@ func GetLogBackendFactory(clients config.Clients) (LogBackendFactory, error) {
 				return &vv, nil
 			})
 		case "kibana":
-			options := v.Options
 			logBackendFactory.clients[k] = ty.GetLazy(func() (*client.LogBackend, error) {
-				vv, err := kibana.GetClient(kibana.Target{Endpoint: options.GetString("endpoint")})
+				vv, err := kibana.GetClient(kibana.Target{Endpoint: v.Options.GetString("endpoint")})
 				if err != nil {
 					return nil, err
 				}
@@ -99,10 +98,19 @@ func GetLogBackendFactory(clients config.Clients) (LogBackendFactory, error) {
 				user := v.Options.GetString("user")
 				addr := v.Options.GetString("addr")
 				pk := v.Options.GetString("privateKey")
+				attempts := 0
+				switch n := v.Options["reconnectMaxAttempts"].(type) {
+				case int:
+					attempts = n
+				case float64:
+					attempts = int(n)
+				}
 				vv, err := ssh.GetLogClient(ssh.LogClientOptions{
-					User:       user,
-					Addr:       addr,
-					PrivateKey: pk,
+					User:                 user,
+					Addr:                 addr,
+					PrivateKey:           pk,
+					ReconnectMaxAttempts: attempts,
+					ReconnectMaxDelay:    v.Options.GetString("reconnectMaxDelay"),
 				})
 				if err != nil {
 					return nil, err
@@ -130,15 +138,7 @@ func GetLogBackendFactory(clients config.Clients) (LogBackendFactory, error) {
 			})
 		case "docker":
 			logBackendFactory.clients[k] = ty.GetLazy(func() (*client.LogBackend, error) {
-				host := v.Options.GetString("host")
-				if host == "" {
-					if runtime.GOOS == "windows" {
-						host = defaultDockerHostWindows
-					} else {
-						host = defaultDockerHostUnix
-					}
-				}
-				vv, err := docker.GetLogClient(host)
+				vv, err := docker.GetLogClient(dockerHost(v.Options))
 				return &vv, err
 			})
 		case "cloudwatch":
@@ -153,10 +153,34 @@ func GetLogBackendFactory(clients config.Clients) (LogBackendFactory, error) {
 		default:
 			return nil, errors.New("invalid type for client : " + v.Type)
 		}
+
+		// Secrets in the keychain are only read once the client is used, so a
+		// missing entry fails that client alone.
+		name, build := k, logBackendFactory.clients[k]
+		logBackendFactory.clients[k] = ty.GetLazy(func() (*client.LogBackend, error) {
+			options, err := config.ResolveKeychain(v.Options)
+			if err != nil {
+				return nil, fmt.Errorf("client %s: %w", name, err)
+			}
+			v.Options = options
+			return build()
+		})
 	}
 
 	return logBackendFactory, nil
 }
 
+// dockerHost returns the host option of a docker client, defaulting to the
+// local daemon socket.
+func dockerHost(options ty.MI) string {
+	if host := options.GetString("host"); host != "" {
+		return host
+	}
+	if runtime.GOOS == "windows" {
+		return defaultDockerHostWindows
+	}
+	return defaultDockerHostUnix
+}
+
 // GetLogBackendFactory builds a LogBackendFactory from the provided
 // configuration, lazily constructing clients on demand.
@@ -1,12 +1,19 @@
 package factory_test
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
+	"github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/log/client/config"
 	"github.com/bascanada/logviewer/pkg/log/factory"
 	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/h2non/gock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetLogBackendFactory(t *testing.T) {
@@ -238,3 +245,54 @@ func TestGetLogBackendFactory(t *testing.T) {
 		assert.NotNil(t, f)
 	})
 }
+
+func TestGetLogBackendFactory_Keychain(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("keychain is read with security or secret-tool")
+	}
+	// Stand-in for both keychain tools, the account is their fifth argument
+	bin := t.TempDir()
+	script := "#!/bin/sh\n[ \"$5\" = prod-splunk ] && echo s3cret\n"
+	for _, tool := range []string{"security", "secret-tool"} {
+		require.NoError(t, os.WriteFile(filepath.Join(bin, tool), []byte(script), 0o755))
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	redacted := &config.ContextConfig{Clients: config.Clients{
+		"splunk": {
+			Type: "splunk",
+			Options: ty.MI{
+				"url":     "http://splunk.com:8080",
+				"headers": ty.MS{"Authorization": "Splunk keychain:prod-splunk"},
+			},
+		},
+		"missing": {
+			Type:    "opensearch",
+			Options: ty.MI{"endpoint": "http://os:9200", "password": "keychain:other"},
+		},
+	}}
+	assert.Empty(t, config.RedactSecrets(redacted), "keychain references are kept")
+
+	f, err := factory.GetLogBackendFactory(redacted.Clients)
+	require.NoError(t, err)
+
+	t.Run("resolves references when the client is built", func(t *testing.T) {
+		defer gock.Off()
+		gock.New("http://splunk.com:8080").
+			Post("/search/jobs").
+			MatchHeader("Authorization", "^Splunk s3cret$").
+			Reply(500)
+
+		backend, err := f.Get("splunk")
+		require.NoError(t, err)
+		_, _ = (*backend).Get(context.Background(), &client.LogSearch{})
+		assert.True(t, gock.IsDone(), "the job is created with the secret")
+		assert.Equal(t, "Splunk keychain:prod-splunk", redacted.Clients["splunk"].Options.GetMS("headers")["Authorization"], "the config keeps the reference")
+	})
+
+	t.Run("missing entry fails its client", func(t *testing.T) {
+		_, err := f.Get("missing")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `client missing: option password: reading "other" from the keychain`)
+	})
+}
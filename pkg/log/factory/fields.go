@@ -0,0 +1,224 @@
+package factory
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// FieldsCacheTTLOption is the client (or search) option setting how long
+// GetFields reuses the fields discovered for a search, e.g. "30s". Numbers
+// are seconds and 0 disables the cache.
+const FieldsCacheTTLOption = "fieldsCacheTTL"
+
+const (
+	// defaultFieldsCacheTTL is used when FieldsCacheTTLOption is not set.
+	defaultFieldsCacheTTL = time.Minute
+	// fieldsCacheSize bounds the field sets cached, the least recently used
+	// being dropped first.
+	fieldsCacheSize = 128
+)
+
+// fieldsKey and fieldNamesKey prefix the keys of the fields of GetFields and
+// of the names of GetFieldNames in the fields cache.
+const (
+	fieldsKey     = "fields\x00"
+	fieldNamesKey = "names\x00"
+)
+
+type fieldsRefreshKey struct{}
+
+// WithFieldsRefresh returns a context making GetFields and GetFieldNames
+// discover the fields again instead of reading them from the cache. The
+// fields found replace the cached ones.
+func WithFieldsRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fieldsRefreshKey{}, true)
+}
+
+func isFieldsRefresh(ctx context.Context) bool {
+	refresh, _ := ctx.Value(fieldsRefreshKey{}).(bool)
+	return refresh
+}
+
+// fieldsCacheTTL returns the fieldsCacheTTL of search, defaultFieldsCacheTTL
+// when it is not set.
+func fieldsCacheTTL(search *client.LogSearch) (time.Duration, error) {
+	var ttl time.Duration
+	switch v := search.Options[FieldsCacheTTLOption].(type) {
+	case nil:
+		return defaultFieldsCacheTTL, nil
+	case string:
+		if v == "" {
+			return defaultFieldsCacheTTL, nil
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", FieldsCacheTTLOption, v, err)
+		}
+		ttl = d
+	case int:
+		ttl = time.Duration(v) * time.Second
+	case float64:
+		ttl = time.Duration(v * float64(time.Second))
+	default:
+		return 0, fmt.Errorf("invalid %s %v: expected a duration like 60s", FieldsCacheTTLOption, v)
+	}
+	if ttl < 0 {
+		return 0, fmt.Errorf("invalid %s %s: must not be negative", FieldsCacheTTLOption, ttl)
+	}
+	return ttl, nil
+}
+
+// cachedFields is the field set discovered for one search.
+type cachedFields struct {
+	key     string
+	fields  ty.UniSet[string]
+	fetched time.Time
+}
+
+// fieldsCache is an LRU cache of field sets, holding at most size of them.
+// It serves GetFields and GetFieldNames, keyed by context and resolved
+// search, and the strict check, keyed by backend options.
+type fieldsCache struct {
+	mu      sync.Mutex
+	size    int
+	now     func() time.Time
+	order   *list.List // of *cachedFields, most recently used first
+	entries map[string]*list.Element
+}
+
+func newFieldsCache(size int) *fieldsCache {
+	return &fieldsCache{size: size, now: time.Now, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns the fields cached for key when they were fetched less than ttl
+// ago. Expired fields are dropped.
+func (c *fieldsCache) get(key string, ttl time.Duration) (ty.UniSet[string], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	cached := el.Value.(*cachedFields)
+	if c.now().Sub(cached.fetched) >= ttl {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return cached.fields, true
+}
+
+func (c *fieldsCache) put(key string, fields ty.UniSet[string]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value = &cachedFields{key: key, fields: fields, fetched: c.now()}
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cachedFields{key: key, fields: fields, fetched: c.now()})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedFields).key)
+	}
+}
+
+// GetFields returns the fields of the search with their values, reusing the
+// ones discovered for the same context and resolved search, time window
+// included, for fieldsCacheTTL. The returned set is shared with the cache and
+// must not be modified.
+func (sf *logSearchFactory) GetFields(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (ty.UniSet[string], error) {
+	key, ttl, err := sf.fieldsCacheKey(contextID, inherits, logSearch, runtimeVars)
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl > 0 && !isFieldsRefresh(ctx) {
+		if fields, ok := sf.fieldsCache.get(fieldsKey+key, ttl); ok {
+			return fields, nil
+		}
+	}
+
+	sr, err := sf.GetSearchResult(ctx, contextID, inherits, logSearch, runtimeVars)
+	if err != nil {
+		return nil, err
+	}
+	fields, _, err := sr.GetFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ttl > 0 {
+		sf.fieldsCache.put(fieldsKey+key, fields)
+	}
+	return fields, nil
+}
+
+// GetFieldNames returns the sorted field names of the search, listed by the
+// backend without their values when it can. They are cached like GetFields,
+// whose cached fields also answer.
+func (sf *logSearchFactory) GetFieldNames(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) ([]string, error) {
+	key, ttl, err := sf.fieldsCacheKey(contextID, inherits, logSearch, runtimeVars)
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl > 0 && !isFieldsRefresh(ctx) {
+		for _, prefix := range []string{fieldsKey, fieldNamesKey} {
+			if fields, ok := sf.fieldsCache.get(prefix+key, ttl); ok {
+				names := make([]string, 0, len(fields))
+				for name := range fields {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				return names, nil
+			}
+		}
+	}
+
+	sr, err := sf.GetSearchResult(ctx, contextID, inherits, logSearch, runtimeVars)
+	if err != nil {
+		return nil, err
+	}
+	names, err := client.GetFieldNames(ctx, sr)
+	if err != nil {
+		return nil, err
+	}
+	if ttl > 0 {
+		fields := make(ty.UniSet[string], len(names))
+		for _, name := range names {
+			fields[name] = nil
+		}
+		sf.fieldsCache.put(fieldNamesKey+key, fields)
+	}
+	return names, nil
+}
+
+// fieldsCacheKey returns the key of the search in the fields cache, the
+// context and its resolved search, and its fieldsCacheTTL.
+func (sf *logSearchFactory) fieldsCacheKey(contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (string, time.Duration, error) {
+	searchContext, err := sf.config.GetSearchContext(contextID, inherits, logSearch, runtimeVars)
+	if err != nil {
+		return "", 0, err
+	}
+	sf.mergeClientOptions(&searchContext.Search, searchContext.Client)
+
+	ttl, err := fieldsCacheTTL(&searchContext.Search)
+	if err != nil {
+		return "", 0, err
+	}
+	resolved, err := json.Marshal(searchContext.Search)
+	if err != nil {
+		return "", 0, err
+	}
+	return contextID + "\x00" + string(resolved), ttl, nil
+}
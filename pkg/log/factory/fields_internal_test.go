@@ -0,0 +1,53 @@
+package factory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldsCache(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	c := newFieldsCache(2)
+	c.now = func() time.Time { return now }
+	fields := ty.UniSet[string]{"level": {"INFO"}}
+
+	c.put("a", fields)
+	c.put("b", fields)
+	_, ok := c.get("a", time.Minute)
+	assert.True(t, ok)
+
+	// b is the least recently used
+	c.put("c", fields)
+	_, ok = c.get("b", time.Minute)
+	assert.False(t, ok, "evicted over the size")
+	_, ok = c.get("a", time.Minute)
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = c.get("a", time.Minute)
+	assert.False(t, ok, "expired")
+	assert.Equal(t, 1, c.order.Len(), "expired entries are dropped")
+}
+
+// fieldValuesBackend reports the same fields for every search.
+type fieldValuesBackend struct{ client.LogBackend }
+
+func (fieldValuesBackend) GetFieldValues(_ context.Context, _ *client.LogSearch, _ []string) (map[string][]string, error) {
+	return map[string][]string{"app": {"api"}}, nil
+}
+
+func TestStrictFieldSetIsBounded(t *testing.T) {
+	sf := &logSearchFactory{fieldsCache: newFieldsCache(2)}
+	for _, index := range []string{"logs-2024.05.01", "logs-2024.05.02", "logs-2024.05.03"} {
+		search := &client.LogSearch{Options: ty.MI{"index": index}}
+		names, err := sf.fieldSet(context.Background(), fieldValuesBackend{}, search)
+		assert.NoError(t, err)
+		assert.Equal(t, "app", names["app"])
+	}
+	assert.Equal(t, 2, sf.fieldsCache.order.Len(), "the oldest field set is evicted")
+}
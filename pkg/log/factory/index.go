@@ -0,0 +1,126 @@
+package factory
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+)
+
+// templatedOptions lists the search options naming an index or log group that
+// may be written as a Go template, e.g. `logs-{{ .To.Format "2006.01.02" }}`.
+var templatedOptions = []string{"index", "logGroupName"}
+
+// maxTemplateDays bounds the number of indices produced by the days function.
+const maxTemplateDays = 366
+
+// indexTemplateData is the data available to index templates. From and To
+// are the bounds of the search time range; Now is when the search started.
+type indexTemplateData struct {
+	Now  time.Time
+	From time.Time
+	To   time.Time
+}
+
+// resolveIndexTemplates expands templated index/log-group options in place.
+// Values without "{{" are left untouched so plain names and wildcards such as
+// `logs-*` are passed to the backend as written.
+func resolveIndexTemplates(search *client.LogSearch, now time.Time) error {
+	var data *indexTemplateData
+
+	for _, key := range templatedOptions {
+		value, ok := search.Options.GetStringOk(key)
+		if !ok || !strings.Contains(value, "{{") {
+			continue
+		}
+
+		if data == nil {
+			from, to, err := searchTimeRange(search, now)
+			if err != nil {
+				return err
+			}
+			data = &indexTemplateData{Now: now, From: from, To: to}
+		}
+
+		tmpl, err := template.New(key).Funcs(template.FuncMap{
+			"days":  func() ([]time.Time, error) { return daysBetween(data.From, data.To) },
+			"daily": func(prefix, layout string) (string, error) { return dailyIndices(data.From, data.To, prefix, layout) },
+		}).Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s template: %w", key, err)
+		}
+
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return fmt.Errorf("failed to render %s template: %w", key, err)
+		}
+		search.Options[key] = sb.String()
+	}
+
+	return nil
+}
+
+// searchTimeRange returns the time range a search covers, falling back to now
+// for an open end and to the end itself when there is no start.
+func searchTimeRange(search *client.LogSearch, now time.Time) (time.Time, time.Time, error) {
+	to := now
+	if search.Range.Lte.Value != "" {
+		t, err := time.Parse(time.RFC3339, search.Range.Lte.Value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("can't parse lte date %q: %w", search.Range.Lte.Value, err)
+		}
+		to = t
+	}
+
+	from := to
+	if search.Range.Gte.Value != "" {
+		t, err := time.Parse(time.RFC3339, search.Range.Gte.Value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("can't parse gte date %q: %w", search.Range.Gte.Value, err)
+		}
+		from = t
+	} else if search.Range.Last.Value != "" {
+		d, err := time.ParseDuration(search.Range.Last.Value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("can't parse duration for last %q: %w", search.Range.Last.Value, err)
+		}
+		from = to.Add(-d)
+	}
+
+	if from.After(to) {
+		from, to = to, from
+	}
+	return from.UTC(), to.UTC(), nil
+}
+
+// daysBetween returns midnight UTC of every day touched by [from, to]. An
+// end at exactly midnight is exclusive, that day holding nothing of the
+// range, unless the range is that single instant.
+func daysBetween(from, to time.Time) ([]time.Time, error) {
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	var days []time.Time
+	for day.Before(to) || (day.Equal(to) && len(days) == 0) {
+		if len(days) == maxTemplateDays {
+			return nil, fmt.Errorf("time range spans more than %d days", maxTemplateDays)
+		}
+		days = append(days, day)
+		day = day.AddDate(0, 0, 1)
+	}
+	return days, nil
+}
+
+// dailyIndices joins prefix+day for each day in the range with commas, the
+// multi-index syntax accepted by OpenSearch and Kibana.
+func dailyIndices(from, to time.Time, prefix, layout string) (string, error) {
+	days, err := daysBetween(from, to)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, len(days))
+	for i, d := range days {
+		names[i] = prefix + d.Format(layout)
+	}
+	return strings.Join(names, ","), nil
+}
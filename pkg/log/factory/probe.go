@@ -0,0 +1,114 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// probeLast is the range of the search probing a context, kept short so
+// the backend has little to scan.
+const probeLast = "5m"
+
+// ProbeResult is the answer of the backend of a context to Probe.
+type ProbeResult struct {
+	// Latency is how long the backend took to answer.
+	Latency time.Duration
+	// Entries is the number of entries received.
+	Entries int
+}
+
+// Probe checks that the backend of contextID answers, running the context
+// search for a single entry of the last minutes. An empty or partial result
+// is a success. The secrets of the client and search options are masked out
+// of the returned error, which still wraps the backend one.
+func Probe(ctx context.Context, cfg *config.ContextConfig, sf SearchFactory, contextID string, runtimeVars map[string]string) (ProbeResult, error) {
+	search := client.LogSearch{Size: ty.OptWrap(1)}
+	search.Range.Last.S(probeLast)
+
+	searchContext, err := cfg.GetSearchContext(contextID, nil, search, runtimeVars)
+	if err != nil {
+		return ProbeResult{}, err
+	}
+	secrets := append(config.SecretValues(cfg.Clients[searchContext.Client].Options), config.SecretValues(searchContext.Search.Options)...)
+
+	var res ProbeResult
+	start := time.Now()
+	res.Entries, err = probeEntries(ctx, sf, contextID, search, runtimeVars)
+	res.Latency = time.Since(start)
+	if err != nil {
+		return res, &maskedError{err: err, msg: maskSecrets(err.Error(), secrets)}
+	}
+	return res, nil
+}
+
+func probeEntries(ctx context.Context, sf SearchFactory, contextID string, search client.LogSearch, runtimeVars map[string]string) (int, error) {
+	result, err := sf.GetSearchResult(ctx, contextID, nil, search, runtimeVars)
+	if err != nil {
+		return 0, err
+	}
+	entries, ch, err := result.GetEntries(ctx)
+	n := len(entries)
+	if ch != nil {
+		for batch := range ch {
+			n += len(batch)
+		}
+	}
+	if err != nil && !client.IsPartial(err) {
+		return 0, err
+	}
+	return n, nil
+}
+
+// maskedError is an error whose message had its secrets masked.
+type maskedError struct {
+	err error
+	msg string
+}
+
+func (e *maskedError) Error() string { return e.msg }
+func (e *maskedError) Unwrap() error { return e.err }
+
+// maskSecrets replaces the secrets found in text with config.MaskedSecret.
+// Secrets shorter than 4 characters are left alone to keep text readable.
+func maskSecrets(text string, secrets []string) string {
+	for _, secret := range secrets {
+		if len(secret) >= 4 {
+			text = strings.ReplaceAll(text, secret, config.MaskedSecret)
+		}
+	}
+	return text
+}
+
+// ClientEndpoint returns where a client connects to, as resolved from its
+// options, or "" for the clients reading from the local machine without
+// one.
+func ClientEndpoint(c config.Client) string {
+	options := c.Options.ResolveVariables()
+	switch c.Type {
+	case "opensearch", "kibana":
+		return options.GetString("endpoint")
+	case "splunk":
+		return options.GetString("url")
+	case "ssh":
+		if user := options.GetString("user"); user != "" {
+			return fmt.Sprintf("%s@%s", user, options.GetString("addr"))
+		}
+		return options.GetString("addr")
+	case "docker":
+		return dockerHost(options)
+	case "k8s":
+		return options.GetString("kubeConfig")
+	case "cloudwatch":
+		if endpoint := options.GetString("endpoint"); endpoint != "" {
+			return endpoint
+		}
+		return options.GetString("region")
+	}
+	return ""
+}
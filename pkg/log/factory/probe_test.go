@@ -0,0 +1,81 @@
+package factory_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/log/factory"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partialResult gives its entries with a partial result error.
+type partialResult struct{ entriesResult }
+
+func (r *partialResult) GetEntries(_ context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	return r.entries, nil, &client.PartialResultError{Err: context.DeadlineExceeded}
+}
+
+func TestProbe(t *testing.T) {
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			return &entriesResult{search: search}, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{"test-client": mockBackend},
+	}
+	cfg := config.ContextConfig{
+		Clients: config.Clients{"test-client": config.Client{Type: "local", Options: ty.MI{"token": "s3cr3t-token"}}},
+		Contexts: config.Contexts{
+			"test-ctx": config.SearchContext{Client: "test-client", Search: client.LogSearch{Size: ty.OptWrap(100)}},
+		},
+	}
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+
+	res, err := factory.Probe(context.Background(), &cfg, f, "test-ctx", nil)
+	require.NoError(t, err, "no entry is still an answer")
+	assert.Zero(t, res.Entries)
+	assert.Equal(t, 1, mockBackend.LastSearch.Size.Value)
+	assert.Equal(t, "5m", mockBackend.LastSearch.Range.Last.Value)
+
+	mockBackend.OnGet = func(search *client.LogSearch) (client.LogSearchResult, error) {
+		return &partialResult{entriesResult{search: search, entries: []client.LogEntry{{Message: "a"}}}}, nil
+	}
+	res, err = factory.Probe(context.Background(), &cfg, f, "test-ctx", nil)
+	require.NoError(t, err, "a partial result is an answer")
+	assert.Equal(t, 1, res.Entries)
+
+	mockBackend.OnGet = func(*client.LogSearch) (client.LogSearchResult, error) {
+		return nil, fmt.Errorf("401 for token s3cr3t-token: %w", context.DeadlineExceeded)
+	}
+	_, err = factory.Probe(context.Background(), &cfg, f, "test-ctx", nil)
+	assert.EqualError(t, err, "401 for token ********: context deadline exceeded")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "the backend error is still wrapped")
+
+	_, err = factory.Probe(context.Background(), &cfg, f, "missing", nil)
+	assert.ErrorIs(t, err, config.ErrContextNotFound)
+}
+
+func TestClientEndpoint(t *testing.T) {
+	t.Setenv("SPLUNK_URL", "https://splunk:8089")
+
+	tests := []struct {
+		client config.Client
+		want   string
+	}{
+		{config.Client{Type: "opensearch", Options: ty.MI{"endpoint": "http://os:9200"}}, "http://os:9200"},
+		{config.Client{Type: "splunk", Options: ty.MI{"url": "${SPLUNK_URL}"}}, "https://splunk:8089"},
+		{config.Client{Type: "ssh", Options: ty.MI{"user": "ops", "addr": "host:22"}}, "ops@host:22"},
+		{config.Client{Type: "docker", Options: ty.MI{"host": "tcp://docker:2375"}}, "tcp://docker:2375"},
+		{config.Client{Type: "cloudwatch", Options: ty.MI{"region": "us-east-1"}}, "us-east-1"},
+		{config.Client{Type: "local"}, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, factory.ClientEndpoint(tt.client), tt.client.Type)
+	}
+}
@@ -4,9 +4,12 @@ package factory
 
 import (
 	"context"
+	"time"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // SearchFactory exposes methods to construct or retrieve search contexts
@@ -17,6 +20,18 @@ type SearchFactory interface {
 	// GetFieldValues returns distinct values for the specified fields.
 	// If fields is empty, returns values for all fields found in the logs.
 	GetFieldValues(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, fields []string, runtimeVars map[string]string) (map[string][]string, error)
+	// GetFields returns the fields of the search with their values, cached
+	// for a while per context and resolved search; see WithFieldsRefresh.
+	GetFields(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (ty.UniSet[string], error)
+	// GetFieldNames returns the sorted field names of the search, without
+	// their values when the backend can list them, cached like GetFields.
+	GetFieldNames(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) ([]string, error)
+	// GetFieldFacets counts the entries of the search per distinct value of
+	// each of fields. Only the entries returned for the search Size are counted.
+	GetFieldFacets(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, fields []string, runtimeVars map[string]string) (map[string]map[string]int, error)
+	// GetCount returns the number of entries matching the search, whatever
+	// its Size, natively when the backend can count them.
+	GetCount(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (int, error)
 }
 
 type logSearchFactory struct {
@@ -24,6 +39,10 @@ type logSearchFactory struct {
 	searchesContext config.Contexts
 
 	config config.ContextConfig
+
+	// fieldsCache holds the fields returned by GetFields and the field sets
+	// used by the strictFields check
+	fieldsCache *fieldsCache
 }
 
 func (sf *logSearchFactory) GetSearchContext(_ context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (*config.SearchContext, error) {
@@ -34,12 +53,16 @@ func (sf *logSearchFactory) GetSearchContext(_ context.Context, contextID string
 	return &searchContext, nil
 }
 
-func (sf *logSearchFactory) GetSearchResult(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (client.LogSearchResult, error) {
+func (sf *logSearchFactory) GetSearchResult(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (_ client.LogSearchResult, err error) {
+	ctx, span := startSpan(ctx, "logviewer.GetSearchResult", attribute.String("logviewer.context_id", contextID))
+	var attrs []attribute.KeyValue
+	defer func() { endSpan(span, err, attrs...) }()
 
 	searchContext, err := sf.config.GetSearchContext(contextID, inherits, logSearch, runtimeVars)
 	if err != nil {
 		return nil, err
 	}
+	attrs = sf.searchAttributes(contextID, searchContext.Client)
 
 	logClient, err := sf.clientsFactory.Get(searchContext.Client)
 	if err != nil {
@@ -50,16 +73,58 @@ func (sf *logSearchFactory) GetSearchResult(ctx context.Context, contextID strin
 	// configuration (e.g., paths, preferNativeDriver for local/ssh clients)
 	sf.mergeClientOptions(&searchContext.Search, searchContext.Client)
 
-	sr, err := (*logClient).Get(ctx, &searchContext.Search)
+	if err := resolveIndexTemplates(&searchContext.Search, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := searchContext.Search.ValidateFilter(); err != nil {
+		return nil, err
+	}
+	if err := client.CheckPageTokenContext(&searchContext.Search, contextID); err != nil {
+		return nil, err
+	}
+	if _, err := client.TimestampLocation(&searchContext.Search); err != nil {
+		return nil, err
+	}
+
+	redactor, err := client.NewRedactor(searchContext.Search.Redact)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sf.checkStrictFields(ctx, *logClient, &searchContext.Search); err != nil {
+		return nil, err
+	}
+
+	// Tell the caller about filters the backend leaves to client-side matching
+	client.ReportFallbacks(ctx, *logClient, &searchContext.Search)
+
+	// Backends capping a request below Size are paged until Size is reached,
+	// and bounded by the queryTimeout option
+	sr, err := getWithTimeout(ctx, *logClient, &searchContext.Search)
+	// Entries are labelled with the context even when the caller did not set
+	// the __context_id__ option
+	sr = client.WithContextID(sr, contextID)
+	sr = client.WithIngest(sr)
+	// Sensitive values are masked before any caller sees the entries
+	sr = client.WithRedaction(sr, redactor)
+	if err == nil && tracer != nil {
+		sr = &tracedResult{LogSearchResult: sr, attrs: attrs}
+	}
 
 	return sr, err
 }
 
-func (sf *logSearchFactory) GetFieldValues(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, fields []string, runtimeVars map[string]string) (map[string][]string, error) {
+func (sf *logSearchFactory) GetFieldValues(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, fields []string, runtimeVars map[string]string) (values map[string][]string, err error) {
+	ctx, span := startSpan(ctx, "logviewer.GetFieldValues", attribute.String("logviewer.context_id", contextID))
+	var attrs []attribute.KeyValue
+	defer func() { endSpan(span, err, append(attrs, attribute.Int("logviewer.field_count", len(values)))...) }()
+
 	searchContext, err := sf.config.GetSearchContext(contextID, inherits, logSearch, runtimeVars)
 	if err != nil {
 		return nil, err
 	}
+	attrs = sf.searchAttributes(contextID, searchContext.Client)
 
 	logClient, err := sf.clientsFactory.Get(searchContext.Client)
 	if err != nil {
@@ -69,7 +134,85 @@ func (sf *logSearchFactory) GetFieldValues(ctx context.Context, contextID string
 	// Merge client options into search options
 	sf.mergeClientOptions(&searchContext.Search, searchContext.Client)
 
-	return (*logClient).GetFieldValues(ctx, &searchContext.Search, fields)
+	if err := resolveIndexTemplates(&searchContext.Search, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := searchContext.Search.ValidateFilter(); err != nil {
+		return nil, err
+	}
+	redactor, err := client.NewRedactor(searchContext.Search.Redact)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := queryTimeout(&searchContext.Search)
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	values, err = (*logClient).GetFieldValues(ctx, &searchContext.Search, fields)
+	for field, v := range values {
+		values[field] = redactor.Values(field, v)
+	}
+	return values, labelTimeout(ctx, timeout, err)
+}
+
+func (sf *logSearchFactory) GetFieldFacets(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, fields []string, runtimeVars map[string]string) (map[string]map[string]int, error) {
+	// Backends have no common way to count values, so the entries are counted
+	sr, err := sf.GetSearchResult(ctx, contextID, inherits, logSearch, runtimeVars)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetFieldFacetsFromResult(ctx, sr, fields)
+}
+
+func (sf *logSearchFactory) GetCount(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (count int, err error) {
+	ctx, span := startSpan(ctx, "logviewer.GetCount", attribute.String("logviewer.context_id", contextID))
+	var attrs []attribute.KeyValue
+	defer func() { endSpan(span, err, append(attrs, attribute.Int("logviewer.count", count))...) }()
+
+	searchContext, err := sf.config.GetSearchContext(contextID, inherits, logSearch, runtimeVars)
+	if err != nil {
+		return 0, err
+	}
+	attrs = sf.searchAttributes(contextID, searchContext.Client)
+
+	logClient, err := sf.clientsFactory.Get(searchContext.Client)
+	if err != nil {
+		return 0, err
+	}
+
+	sf.mergeClientOptions(&searchContext.Search, searchContext.Client)
+
+	if err := resolveIndexTemplates(&searchContext.Search, time.Now()); err != nil {
+		return 0, err
+	}
+
+	if err := searchContext.Search.ValidateFilter(); err != nil {
+		return 0, err
+	}
+	if err := sf.checkStrictFields(ctx, *logClient, &searchContext.Search); err != nil {
+		return 0, err
+	}
+
+	timeout, err := queryTimeout(&searchContext.Search)
+	if err != nil {
+		return 0, err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	count, err = client.CountEntries(ctx, *logClient, &searchContext.Search)
+	return count, labelTimeout(ctx, timeout, err)
 }
 
 // mergeClientOptions merges client-level options (e.g., paths, preferNativeDriver)
@@ -104,6 +247,7 @@ func GetLogSearchFactory(
 	factory.searchesContext = make(config.Contexts)
 	factory.clientsFactory = f
 	factory.config = c
+	factory.fieldsCache = newFieldsCache(fieldsCacheSize)
 
 	return factory, nil
 }
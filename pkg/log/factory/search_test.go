@@ -2,13 +2,19 @@ package factory_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/log/client/config"
 	"github.com/bascanada/logviewer/pkg/log/factory"
 	"github.com/bascanada/logviewer/pkg/ty"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // MockLogBackend implements client.LogBackend for testing
@@ -128,6 +134,63 @@ func TestSearchFactory_GetFieldValues(t *testing.T) {
 	assert.Equal(t, []string{"INFO"}, results["level"])
 }
 
+func TestSearchFactory_GetFieldFacets(t *testing.T) {
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			return &entriesResult{search: search, entries: []client.LogEntry{
+				{Level: "INFO"}, {Level: "ERROR"}, {Level: "INFO"},
+			}}, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{"test-client": mockBackend},
+	}
+	cfg := config.ContextConfig{
+		Clients: config.Clients{
+			"test-client": config.Client{Type: "local", Options: ty.MI{"client-opt": "v"}},
+		},
+		Contexts: config.Contexts{
+			"test-ctx": config.SearchContext{Client: "test-client"},
+		},
+	}
+
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+
+	facets, err := f.GetFieldFacets(context.Background(), "test-ctx", nil, client.LogSearch{}, []string{"level"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"INFO": 2, "ERROR": 1}, facets["level"])
+	assert.Equal(t, "v", mockBackend.LastSearch.Options["client-opt"], "searched like GetSearchResult")
+}
+
+func TestSearchFactory_GetCount(t *testing.T) {
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			return &entriesResult{search: search, entries: []client.LogEntry{
+				{Level: "INFO"}, {Level: "ERROR"}, {Level: "INFO"},
+			}}, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{"test-client": mockBackend},
+	}
+	cfg := config.ContextConfig{
+		Clients: config.Clients{
+			"test-client": config.Client{Type: "local", Options: ty.MI{"client-opt": "v"}},
+		},
+		Contexts: config.Contexts{
+			"test-ctx": config.SearchContext{Client: "test-client"},
+		},
+	}
+
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+
+	// The backend has no native count, its entries are counted
+	count, err := f.GetCount(context.Background(), "test-ctx", nil, client.LogSearch{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, "v", mockBackend.LastSearch.Options["client-opt"], "searched like GetSearchResult")
+}
+
 func TestSearchFactory_GetSearchContext(t *testing.T) {
 	mockClientFactory := &MockLogBackendFactory{}
 	cfg := config.ContextConfig{
@@ -146,3 +209,647 @@ func TestSearchFactory_GetSearchContext(t *testing.T) {
 	assert.Equal(t, "test-client", ctx.Client)
 	assert.Equal(t, "test desc", ctx.Description)
 }
+
+func TestSearchFactory_IndexTemplate(t *testing.T) {
+	mockBackend := &MockLogBackend{}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{
+			"test-client": mockBackend,
+		},
+	}
+
+	cfg := config.ContextConfig{
+		Clients: config.Clients{
+			"test-client": config.Client{Type: "opensearch"},
+		},
+		Contexts: config.Contexts{
+			"test-ctx": config.SearchContext{Client: "test-client"},
+		},
+	}
+
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+
+	run := func(index string, rng client.SearchRange) (string, error) {
+		search := client.LogSearch{Options: ty.MI{"index": index}, Range: rng}
+		_, err := f.GetSearchResult(context.Background(), "test-ctx", nil, search, nil)
+		if err != nil {
+			return "", err
+		}
+		return mockBackend.LastSearch.Options.GetString("index"), nil
+	}
+
+	absolute := client.SearchRange{
+		Gte: ty.OptWrap("2024-01-02T22:00:00Z"),
+		Lte: ty.OptWrap("2024-01-04T01:00:00Z"),
+	}
+
+	t.Run("formats the end of the search range", func(t *testing.T) {
+		index, err := run(`logs-{{ .To.Format "2006.01.02" }}`, absolute)
+		assert.NoError(t, err)
+		assert.Equal(t, "logs-2024.01.04", index)
+	})
+
+	t.Run("relative range is computed from lte", func(t *testing.T) {
+		index, err := run(`logs-{{ .From.Format "2006.01.02" }}`, client.SearchRange{
+			Lte:  ty.OptWrap("2024-01-04T01:00:00Z"),
+			Last: ty.OptWrap("48h"),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "logs-2024.01.02", index)
+	})
+
+	t.Run("daily expands every day of the range", func(t *testing.T) {
+		index, err := run(`{{ daily "logs-" "2006.01.02" }}`, absolute)
+		assert.NoError(t, err)
+		assert.Equal(t, "logs-2024.01.02,logs-2024.01.03,logs-2024.01.04", index)
+	})
+
+	t.Run("daily excludes the day an end at midnight starts", func(t *testing.T) {
+		index, err := run(`{{ daily "logs-" "2006.01.02" }}`, client.SearchRange{
+			Gte: ty.OptWrap("2024-01-02T22:00:00Z"),
+			Lte: ty.OptWrap("2024-01-04T00:00:00Z"),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "logs-2024.01.02,logs-2024.01.03", index)
+
+		index, err = run(`{{ daily "logs-" "2006.01.02" }}`, client.SearchRange{
+			Gte: ty.OptWrap("2024-01-04T00:00:00Z"),
+			Lte: ty.OptWrap("2024-01-04T00:00:00Z"),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "logs-2024.01.04", index, "a single instant keeps its day")
+	})
+
+	t.Run("wildcards are passed through", func(t *testing.T) {
+		index, err := run("logs-*", absolute)
+		assert.NoError(t, err)
+		assert.Equal(t, "logs-*", index)
+	})
+
+	t.Run("invalid template is an error", func(t *testing.T) {
+		_, err := run("logs-{{ .Nope", absolute)
+		assert.Error(t, err)
+	})
+}
+
+func TestSearchFactory_StrictFields(t *testing.T) {
+	discoveries := 0
+	mockBackend := &MockLogBackend{
+		OnValues: func(search *client.LogSearch, fields []string) (map[string][]string, error) {
+			discoveries++
+			return map[string][]string{"App": {"api"}, "trace_id": {"abc"}}, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{
+			"test-client": mockBackend,
+		},
+	}
+
+	cfg := config.ContextConfig{
+		Clients: config.Clients{
+			"test-client": config.Client{Type: "local"},
+		},
+		Contexts: config.Contexts{
+			"test-ctx": config.SearchContext{
+				Client: "test-client",
+				Search: client.LogSearch{Options: ty.MI{factory.StrictFieldsOption: true}},
+			},
+		},
+	}
+
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+	ctx := context.Background()
+
+	t.Run("known fields match case-insensitively", func(t *testing.T) {
+		search := client.LogSearch{Fields: ty.MS{"app": "api", "level": "ERROR"}}
+		_, err := f.GetSearchResult(ctx, "test-ctx", nil, search, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown field lists the valid ones", func(t *testing.T) {
+		search := client.LogSearch{Filter: &client.Filter{Field: "trace", Op: "equals", Value: "abc"}}
+		_, err := f.GetSearchResult(ctx, "test-ctx", nil, search, nil)
+		assert.ErrorIs(t, err, factory.ErrUnknownField)
+		assert.Contains(t, err.Error(), "trace (valid fields: App, level, message, timestamp, trace_id)")
+	})
+
+	t.Run("field set is cached", func(t *testing.T) {
+		assert.Equal(t, 1, discoveries)
+	})
+
+	t.Run("can be disabled per query", func(t *testing.T) {
+		search := client.LogSearch{
+			Fields:  ty.MS{"trace": "abc"},
+			Options: ty.MI{factory.StrictFieldsOption: false},
+		}
+		_, err := f.GetSearchResult(ctx, "test-ctx", nil, search, nil)
+		assert.NoError(t, err)
+	})
+}
+
+// blockingResult is a result whose GetEntries waits for its context to end,
+// returning the entries received until then.
+type blockingResult struct {
+	search  *client.LogSearch
+	entries []client.LogEntry
+}
+
+func (r *blockingResult) GetSearch() *client.LogSearch { return r.search }
+func (r *blockingResult) GetEntries(ctx context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	<-ctx.Done()
+	return r.entries, nil, client.PartialOnTimeout(r.entries, ctx.Err())
+}
+func (r *blockingResult) GetFields(_ context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
+	return nil, nil, nil
+}
+func (r *blockingResult) GetPaginationInfo() *client.PaginationInfo { return nil }
+func (r *blockingResult) Err() <-chan error                         { return nil }
+
+func TestSearchFactory_QueryTimeout(t *testing.T) {
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			return &blockingResult{search: search}, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{"test-client": mockBackend},
+	}
+	cfg := config.ContextConfig{
+		Clients: config.Clients{
+			"test-client": config.Client{Type: "local", Options: ty.MI{factory.QueryTimeoutOption: "20ms"}},
+		},
+		Contexts: config.Contexts{
+			"test-ctx": config.SearchContext{Client: "test-client"},
+		},
+	}
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+	ctx := context.Background()
+
+	t.Run("client option bounds GetEntries", func(t *testing.T) {
+		result, err := f.GetSearchResult(ctx, "test-ctx", nil, client.LogSearch{}, nil)
+		assert.NoError(t, err)
+		_, _, err = result.GetEntries(ctx)
+		assert.ErrorIs(t, err, factory.ErrQueryTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "query timed out after 20ms (queryTimeout)")
+	})
+
+	t.Run("entries received before the deadline are partial", func(t *testing.T) {
+		mockBackend.OnGet = func(search *client.LogSearch) (client.LogSearchResult, error) {
+			return &blockingResult{search: search, entries: []client.LogEntry{{Message: "a"}}}, nil
+		}
+		defer func() {
+			mockBackend.OnGet = func(search *client.LogSearch) (client.LogSearchResult, error) {
+				return &blockingResult{search: search}, nil
+			}
+		}()
+		result, err := f.GetSearchResult(ctx, "test-ctx", nil, client.LogSearch{}, nil)
+		assert.NoError(t, err)
+		entries, _, err := result.GetEntries(ctx)
+		assert.Len(t, entries, 1)
+		assert.True(t, client.IsPartial(err))
+		assert.ErrorIs(t, err, factory.ErrQueryTimeout)
+	})
+
+	t.Run("search option overrides the client one", func(t *testing.T) {
+		search := client.LogSearch{Options: ty.MI{factory.QueryTimeoutOption: "nope"}}
+		_, err := f.GetSearchResult(ctx, "test-ctx", nil, search, nil)
+		assert.ErrorContains(t, err, `invalid queryTimeout "nope"`)
+	})
+
+	t.Run("follow queries are not bounded", func(t *testing.T) {
+		result, err := f.GetSearchResult(ctx, "test-ctx", nil, client.LogSearch{Follow: true}, nil)
+		assert.NoError(t, err)
+		entriesCtx, cancel := context.WithTimeout(ctx, 60*time.Millisecond)
+		defer cancel()
+		_, _, err = result.GetEntries(entriesCtx)
+		assert.NotErrorIs(t, err, factory.ErrQueryTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "only the caller's deadline applies")
+	})
+}
+
+// entriesResult returns fixed entries.
+type entriesResult struct {
+	search  *client.LogSearch
+	entries []client.LogEntry
+}
+
+func (r *entriesResult) GetSearch() *client.LogSearch { return r.search }
+func (r *entriesResult) GetEntries(_ context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	return r.entries, nil, nil
+}
+func (r *entriesResult) GetFields(_ context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
+	return ty.UniSet[string]{"level": {"ERROR"}}, nil, nil
+}
+func (r *entriesResult) GetPaginationInfo() *client.PaginationInfo { return nil }
+func (r *entriesResult) Err() <-chan error                         { return nil }
+
+func TestSearchFactory_Tracing(t *testing.T) {
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			return &entriesResult{search: search, entries: []client.LogEntry{{Message: "a"}, {Message: "b"}}}, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{"test-client": mockBackend},
+	}
+	cfg := config.ContextConfig{
+		Clients: config.Clients{
+			"test-client": config.Client{Type: "splunk", Options: ty.MI{"token": "s3cr3t-token"}},
+		},
+		Contexts: config.Contexts{
+			"test-ctx": config.SearchContext{Client: "test-client"},
+		},
+	}
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+	ctx := context.Background()
+
+	t.Run("no tracer provider leaves results unwrapped", func(t *testing.T) {
+		result, err := f.GetSearchResult(ctx, "test-ctx", nil, client.LogSearch{}, nil)
+		assert.NoError(t, err)
+		assert.NotEqual(t, "*factory.tracedResult", fmt.Sprintf("%T", result))
+	})
+
+	t.Run("spans for the query and its entries", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		factory.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		t.Cleanup(func() { factory.SetTracerProvider(nil) })
+
+		parentCtx, parent := sdktrace.NewTracerProvider().Tracer("test").Start(ctx, "parent")
+		result, err := f.GetSearchResult(parentCtx, "test-ctx", nil, client.LogSearch{}, nil)
+		assert.NoError(t, err)
+		_, _, err = result.GetEntries(parentCtx)
+		assert.NoError(t, err)
+		_, err = f.GetSearchResult(ctx, "missing-ctx", nil, client.LogSearch{}, nil)
+		assert.Error(t, err)
+		parent.End()
+
+		spans := recorder.Ended()
+		if !assert.Len(t, spans, 3) {
+			return
+		}
+		attrs := func(s sdktrace.ReadOnlySpan) map[string]string {
+			m := map[string]string{}
+			for _, kv := range s.Attributes() {
+				m[string(kv.Key)] = kv.Value.Emit()
+			}
+			return m
+		}
+
+		assert.Equal(t, "logviewer.GetSearchResult", spans[0].Name())
+		assert.Equal(t, parent.SpanContext().TraceID(), spans[0].SpanContext().TraceID(), "the incoming span is the parent")
+		assert.Equal(t, map[string]string{
+			"logviewer.context_id": "test-ctx",
+			"logviewer.client":     "test-client",
+			"logviewer.backend":    "splunk",
+		}, attrs(spans[0]))
+
+		assert.Equal(t, "logviewer.GetEntries", spans[1].Name())
+		assert.Equal(t, "2", attrs(spans[1])["logviewer.result_count"])
+
+		assert.Equal(t, codes.Error, spans[2].Status().Code)
+		for _, s := range spans {
+			for _, v := range attrs(s) {
+				assert.NotContains(t, v, "s3cr3t")
+			}
+		}
+	})
+}
+
+func TestSearchFactory_StampsContextID(t *testing.T) {
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			return &entriesResult{search: search, entries: []client.LogEntry{{Message: "a"}, {Message: "b", ContextID: "container-1"}}}, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{"test-client": mockBackend},
+	}
+	cfg := config.ContextConfig{
+		Clients:  config.Clients{"test-client": config.Client{Type: "local"}},
+		Contexts: config.Contexts{"test-ctx": config.SearchContext{Client: "test-client"}},
+	}
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+
+	// No __context_id__ option, as the TUI searches
+	result, err := f.GetSearchResult(context.Background(), "test-ctx", nil, client.LogSearch{}, nil)
+	assert.NoError(t, err)
+	entries, _, err := result.GetEntries(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "test-ctx", entries[0].ContextID)
+		assert.Equal(t, "container-1", entries[1].ContextID, "labels set by the backend are kept")
+	}
+}
+
+func TestSearchFactory_RejectsInvalidFilter(t *testing.T) {
+	called := false
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			called = true
+			return &entriesResult{search: search}, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{"test-client": mockBackend},
+	}
+	cfg := config.ContextConfig{
+		Clients:  config.Clients{"test-client": config.Client{Type: "local"}},
+		Contexts: config.Contexts{"test-ctx": config.SearchContext{Client: "test-client"}},
+	}
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+
+	search := client.LogSearch{Filter: &client.Filter{Logic: client.LogicOr, Filters: []client.Filter{
+		{Field: "level", Value: "ERROR"},
+		{Field: "level", Op: "between", Value: "1,2"},
+	}}}
+	_, err := f.GetSearchResult(context.Background(), "test-ctx", nil, search, nil)
+	var filterErr *client.FilterError
+	if assert.ErrorAs(t, err, &filterErr) {
+		assert.Equal(t, "filters[1]", filterErr.Path)
+	}
+	assert.False(t, called, "the backend is not queried")
+}
+
+func TestSearchFactory_GetFields_Cache(t *testing.T) {
+	calls := 0
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			calls++
+			return &entriesResult{search: search}, nil
+		},
+	}
+	newFactory := func(options ty.MI) factory.SearchFactory {
+		calls = 0
+		cfg := config.ContextConfig{
+			Clients:  config.Clients{"test-client": config.Client{Type: "local", Options: options}},
+			Contexts: config.Contexts{"test-ctx": config.SearchContext{Client: "test-client"}},
+		}
+		f, _ := factory.GetLogSearchFactory(&MockLogBackendFactory{Backends: map[string]client.LogBackend{"test-client": mockBackend}}, cfg)
+		return f
+	}
+	last := func(window string) client.LogSearch {
+		search := client.LogSearch{}
+		search.Range.Last.S(window)
+		return search
+	}
+	ctx := context.Background()
+
+	t.Run("second call within the TTL is cached", func(t *testing.T) {
+		f := newFactory(nil)
+		for range 2 {
+			fields, err := f.GetFields(ctx, "test-ctx", nil, last("15m"), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"ERROR"}, fields["level"])
+		}
+		assert.Equal(t, 1, calls)
+
+		_, err := f.GetFields(ctx, "test-ctx", nil, last("1h"), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls, "another window is discovered again")
+	})
+
+	t.Run("refresh bypasses the cache", func(t *testing.T) {
+		f := newFactory(nil)
+		_, _ = f.GetFields(ctx, "test-ctx", nil, last("15m"), nil)
+		_, _ = f.GetFields(factory.WithFieldsRefresh(ctx), "test-ctx", nil, last("15m"), nil)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("field names share the cache", func(t *testing.T) {
+		f := newFactory(nil)
+		for range 2 {
+			names, err := f.GetFieldNames(ctx, "test-ctx", nil, last("15m"), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"level"}, names)
+		}
+		assert.Equal(t, 1, calls)
+
+		_, _ = f.GetFields(ctx, "test-ctx", nil, last("1h"), nil)
+		names, err := f.GetFieldNames(ctx, "test-ctx", nil, last("1h"), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"level"}, names)
+		assert.Equal(t, 2, calls, "the cached fields answer")
+
+		_, _ = f.GetFieldNames(factory.WithFieldsRefresh(ctx), "test-ctx", nil, last("15m"), nil)
+		assert.Equal(t, 3, calls, "refresh bypasses the cache")
+	})
+
+	t.Run("expired after the TTL", func(t *testing.T) {
+		f := newFactory(ty.MI{factory.FieldsCacheTTLOption: "10ms"})
+		_, _ = f.GetFields(ctx, "test-ctx", nil, last("15m"), nil)
+		time.Sleep(20 * time.Millisecond)
+		_, _ = f.GetFields(ctx, "test-ctx", nil, last("15m"), nil)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("disabled with a zero TTL", func(t *testing.T) {
+		f := newFactory(ty.MI{factory.FieldsCacheTTLOption: 0})
+		_, _ = f.GetFields(ctx, "test-ctx", nil, last("15m"), nil)
+		_, _ = f.GetFields(ctx, "test-ctx", nil, last("15m"), nil)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("invalid TTL", func(t *testing.T) {
+		f := newFactory(ty.MI{factory.FieldsCacheTTLOption: "soon"})
+		_, err := f.GetFields(ctx, "test-ctx", nil, last("15m"), nil)
+		assert.ErrorContains(t, err, "invalid fieldsCacheTTL")
+	})
+}
+
+func TestSearchFactory_Redaction(t *testing.T) {
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			return &entriesResult{search: search, entries: []client.LogEntry{
+				{Message: "login from alice@example.com", Fields: ty.MI{"password": "hunter2"}},
+			}}, nil
+		},
+		OnValues: func(_ *client.LogSearch, _ []string) (map[string][]string, error) {
+			return map[string][]string{"password": {"hunter2", "letmein"}, "user": {"alice@example.com"}}, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{"test-client": mockBackend},
+	}
+	redact := client.Redaction{Patterns: []string{`\S+@\S+`}, Fields: []string{"password"}}
+	cfg := config.ContextConfig{
+		Clients: config.Clients{"test-client": config.Client{Type: "local"}},
+		Contexts: config.Contexts{
+			"test-ctx": config.SearchContext{Client: "test-client", Search: client.LogSearch{Redact: redact}},
+			"invalid":  config.SearchContext{Client: "test-client", Search: client.LogSearch{Redact: client.Redaction{Patterns: []string{"["}}}},
+		},
+	}
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+	ctx := context.Background()
+
+	t.Run("entries are masked", func(t *testing.T) {
+		result, err := f.GetSearchResult(ctx, "test-ctx", nil, client.LogSearch{}, nil)
+		assert.NoError(t, err)
+		entries, _, err := result.GetEntries(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "login from ***", entries[0].Message)
+		assert.Equal(t, "***", entries[0].Fields["password"])
+	})
+
+	t.Run("field values are masked", func(t *testing.T) {
+		values, err := f.GetFieldValues(ctx, "test-ctx", nil, client.LogSearch{}, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{"password": {"***"}, "user": {"***"}}, values)
+	})
+
+	t.Run("invalid patterns fail the search", func(t *testing.T) {
+		_, err := f.GetSearchResult(ctx, "invalid", nil, client.LogSearch{}, nil)
+		assert.ErrorContains(t, err, "invalid redaction pattern")
+	})
+}
+
+func TestSearchFactory_Ingest(t *testing.T) {
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			return &entriesResult{search: search, entries: []client.LogEntry{
+				{Message: `{"message":"login 42 from alice@example.com","password":"hunter2"}`},
+			}}, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{"test-client": mockBackend},
+	}
+	cfg := config.ContextConfig{
+		Clients: config.Clients{"test-client": config.Client{Type: "local"}},
+		Contexts: config.Contexts{
+			"test-ctx": config.SearchContext{Client: "test-client", Search: client.LogSearch{
+				Redact: client.Redaction{Patterns: []string{`\S+@\S+`}, Fields: []string{"password"}},
+				FieldExtraction: client.FieldExtraction{
+					JSON:             ty.OptWrap(true),
+					Signature:        ty.OptWrap(client.SignatureBasic),
+					MaxMessageLength: ty.OptWrap(10),
+				},
+			}},
+		},
+	}
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+	ctx := context.Background()
+
+	result, err := f.GetSearchResult(ctx, "test-ctx", nil, client.LogSearch{}, nil)
+	require.NoError(t, err)
+	entries, _, err := result.GetEntries(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	// Extracted before the masking, so the JSON fields are masked too
+	assert.Equal(t, "***", entries[0].Fields["password"])
+	assert.Equal(t, "login <num> from ***", entries[0].Fields[client.SignatureField])
+	assert.Equal(t, "login 42 f…(truncated 21 chars)", entries[0].Message)
+}
+
+// namesResult lists its field names natively, counting the calls.
+type namesResult struct {
+	entriesResult
+	listed, scanned int
+}
+
+func (r *namesResult) GetFieldNames(_ context.Context) ([]string, error) {
+	r.listed++
+	return []string{"level", "service"}, nil
+}
+
+func (r *namesResult) GetFields(ctx context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
+	r.scanned++
+	return r.entriesResult.GetFields(ctx)
+}
+
+func TestSearchFactory_FieldNamesLister(t *testing.T) {
+	result := &namesResult{}
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			result.search = search
+			return result, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{"test-client": mockBackend},
+	}
+	cfg := config.ContextConfig{
+		Clients: config.Clients{
+			"test-client": config.Client{Type: "local", Options: ty.MI{"queryTimeout": "30s"}},
+		},
+		Contexts: config.Contexts{
+			"test-ctx": config.SearchContext{Client: "test-client", Search: client.LogSearch{
+				Redact: client.Redaction{Patterns: []string{`\d{16}`}},
+			}},
+			"masked-ctx": config.SearchContext{Client: "test-client", Search: client.LogSearch{
+				Redact: client.Redaction{Fields: []string{"email"}},
+			}},
+		},
+	}
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+
+	recorder := tracetest.NewSpanRecorder()
+	factory.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { factory.SetTracerProvider(nil) })
+
+	// Timeout, context id, ingest, redaction and tracing wrappers all forward it
+	sr, err := f.GetSearchResult(context.Background(), "test-ctx", nil, client.LogSearch{}, nil)
+	assert.NoError(t, err)
+	names, err := client.GetFieldNames(context.Background(), sr)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"level", "service"}, names)
+	assert.Equal(t, 1, result.listed)
+	assert.Zero(t, result.scanned, "the fields are not scanned")
+
+	// Masked fields are listed from the redacted fields
+	sr, err = f.GetSearchResult(context.Background(), "masked-ctx", nil, client.LogSearch{}, nil)
+	assert.NoError(t, err)
+	names, err = client.GetFieldNames(context.Background(), sr)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"level"}, names)
+	assert.Equal(t, 1, result.listed)
+	assert.Equal(t, 1, result.scanned)
+}
+
+// closingResult records that it was closed, like a Splunk job cancelled.
+type closingResult struct {
+	entriesResult
+	closed int
+}
+
+func (r *closingResult) Close() error {
+	r.closed++
+	return nil
+}
+
+func TestSearchFactory_CloseReachesResult(t *testing.T) {
+	result := &closingResult{}
+	mockBackend := &MockLogBackend{
+		OnGet: func(search *client.LogSearch) (client.LogSearchResult, error) {
+			result.search = search
+			return result, nil
+		},
+	}
+	mockClientFactory := &MockLogBackendFactory{
+		Backends: map[string]client.LogBackend{"test-client": mockBackend},
+	}
+	cfg := config.ContextConfig{
+		Clients: config.Clients{
+			"test-client": config.Client{Type: "local", Options: ty.MI{"queryTimeout": "30s", client.MaxPageSizeOption: 1}},
+		},
+		Contexts: config.Contexts{
+			"test-ctx": config.SearchContext{Client: "test-client", Search: client.LogSearch{
+				Size:   ty.OptWrap(5),
+				Redact: client.Redaction{Fields: []string{"password"}},
+			}},
+		},
+	}
+	f, _ := factory.GetLogSearchFactory(mockClientFactory, cfg)
+
+	factory.SetTracerProvider(sdktrace.NewTracerProvider())
+	t.Cleanup(func() { factory.SetTracerProvider(nil) })
+
+	// Auto-paging, timeout, context id, redaction and tracing wrappers
+	sr, err := f.GetSearchResult(context.Background(), "test-ctx", nil, client.LogSearch{}, nil)
+	require.NoError(t, err)
+	closer, ok := sr.(interface{ Close() error })
+	require.True(t, ok, "the wrapped result can be closed")
+	assert.NoError(t, closer.Close())
+	assert.Equal(t, 1, result.closed)
+}
@@ -0,0 +1,133 @@
+package factory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+)
+
+// StrictFieldsOption is the search option that makes a query fail when its
+// filter references a field the backend does not report, rather than quietly
+// matching nothing. Set it to false on a query to disable it for that query.
+const StrictFieldsOption = "strictFields"
+
+// strictFieldsTTL is how long the field set of a search is reused for the
+// strict check before being discovered again.
+const strictFieldsTTL = 5 * time.Minute
+
+// strictFieldsKey prefixes the keys of the field sets of the strict check
+// in the fields cache.
+const strictFieldsKey = "strict\x00"
+
+// ErrUnknownField is returned in strict mode for a filter on a field the
+// backend does not know.
+var ErrUnknownField = errors.New("unknown field")
+
+// coreFields are entry fields every backend provides, never reported unknown.
+var coreFields = []string{"level", "message", "timestamp"}
+
+// checkStrictFields returns ErrUnknownField, listing the valid fields, when
+// strict mode is enabled and the filter of search references a field missing
+// from the backend's field set. Fields are compared case-insensitively and an
+// empty field set, e.g. no logs in range, disables the check.
+func (sf *logSearchFactory) checkStrictFields(ctx context.Context, backend client.LogBackend, search *client.LogSearch) error {
+	if strict, ok := search.Options.GetBoolOk(StrictFieldsOption); !ok || !strict {
+		return nil
+	}
+
+	referenced := filterFields(search.GetEffectiveFilter())
+	if len(referenced) == 0 {
+		return nil
+	}
+
+	known, err := sf.fieldSet(ctx, backend, search)
+	if err != nil {
+		return fmt.Errorf("failed to get fields for strict check: %w", err)
+	}
+	if len(known) == 0 {
+		return nil
+	}
+
+	var unknown []string
+	for _, field := range referenced {
+		if _, ok := known[strings.ToLower(field)]; !ok {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	valid := make([]string, 0, len(known))
+	for _, name := range known {
+		valid = append(valid, name)
+	}
+	sort.Strings(valid)
+	return fmt.Errorf("%w: %s (valid fields: %s)", ErrUnknownField, strings.Join(unknown, ", "), strings.Join(valid, ", "))
+}
+
+// fieldSet returns the fields of search, keyed by lower-cased name. They
+// are discovered without its filter and kept in the fields cache per backend
+// options for strictFieldsTTL.
+func (sf *logSearchFactory) fieldSet(ctx context.Context, backend client.LogBackend, search *client.LogSearch) (map[string]string, error) {
+	options, err := json.Marshal(search.Options)
+	if err != nil {
+		return nil, err
+	}
+	key := strictFieldsKey + string(options)
+
+	values, ok := sf.fieldsCache.get(key, strictFieldsTTL)
+	if !ok {
+		discovery := search.Clone()
+		discovery.Fields = nil
+		discovery.FieldsCondition = nil
+		discovery.Filter = nil
+		values, err = backend.GetFieldValues(ctx, discovery, nil)
+		if err != nil {
+			return nil, err
+		}
+		sf.fieldsCache.put(key, values)
+	}
+
+	names := make(map[string]string, len(values)+len(coreFields))
+	if len(values) > 0 {
+		for field := range values {
+			names[strings.ToLower(field)] = field
+		}
+		for _, field := range coreFields {
+			if _, ok := names[field]; !ok {
+				names[field] = field
+			}
+		}
+	}
+	return names, nil
+}
+
+// filterFields returns the distinct fields referenced by the leaves of f,
+// except the "_" full-text sentinel.
+func filterFields(f *client.Filter) []string {
+	if f == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var fields []string
+	var walk func(f *client.Filter)
+	walk = func(f *client.Filter) {
+		if f.Field != "" && f.Field != "_" && !seen[f.Field] {
+			seen[f.Field] = true
+			fields = append(fields, f.Field)
+		}
+		for i := range f.Filters {
+			walk(&f.Filters[i])
+		}
+	}
+	walk(f)
+	sort.Strings(fields)
+	return fields
+}
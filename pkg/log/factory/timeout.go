@@ -0,0 +1,113 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+)
+
+// QueryTimeoutOption is the client (or search) option bounding how long a
+// query may take, from the backend request until its entries are received,
+// e.g. "30s". Numbers are seconds. Follow queries are not bounded.
+const QueryTimeoutOption = "queryTimeout"
+
+// ErrQueryTimeout labels the errors of queries cut short by queryTimeout.
+var ErrQueryTimeout = errors.New("query timed out")
+
+// queryTimeout returns the queryTimeout of search, 0 when it is not set.
+func queryTimeout(search *client.LogSearch) (time.Duration, error) {
+	var timeout time.Duration
+	switch v := search.Options[QueryTimeoutOption].(type) {
+	case nil:
+		return 0, nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", QueryTimeoutOption, v, err)
+		}
+		timeout = d
+	case int:
+		timeout = time.Duration(v) * time.Second
+	case float64:
+		timeout = time.Duration(v * float64(time.Second))
+	default:
+		return 0, fmt.Errorf("invalid %s %v: expected a duration like 30s", QueryTimeoutOption, v)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("invalid %s %s: must not be negative", QueryTimeoutOption, timeout)
+	}
+	return timeout, nil
+}
+
+// labelTimeout wraps err in ErrQueryTimeout when it was caused by the
+// deadline of ctx, keeping the original error to unwrap.
+func labelTimeout(ctx context.Context, timeout time.Duration, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w after %s (%s): %w", ErrQueryTimeout, timeout, QueryTimeoutOption, err)
+}
+
+// getWithTimeout runs the search like client.GetAutoPaged, bounding the
+// request and the GetEntries call of its result by the queryTimeout of
+// search together.
+func getWithTimeout(ctx context.Context, backend client.LogBackend, search *client.LogSearch) (client.LogSearchResult, error) {
+	timeout, err := queryTimeout(search)
+	if err != nil {
+		return nil, err
+	}
+	if timeout == 0 || search.Follow {
+		return client.GetAutoPaged(ctx, backend, search)
+	}
+
+	deadline := time.Now().Add(timeout)
+	// Not cancelled when Get returns: backends may keep reading with it
+	getCtx, cancel := context.WithDeadline(ctx, deadline)
+	result, err := client.GetAutoPaged(getCtx, backend, search)
+	if err != nil {
+		defer cancel()
+		return nil, labelTimeout(getCtx, timeout, err)
+	}
+	return &timeoutResult{LogSearchResult: result, deadline: deadline, timeout: timeout, cancel: cancel}, nil
+}
+
+// timeoutResult bounds GetEntries by the deadline of the query. The entries a
+// backend received before the deadline are returned with its partial result
+// error, labelled like the other timeouts.
+type timeoutResult struct {
+	client.LogSearchResult
+
+	deadline time.Time
+	timeout  time.Duration
+	cancel   context.CancelFunc // releases the context of the backend request
+}
+
+func (r *timeoutResult) GetEntries(ctx context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	entriesCtx, cancel := context.WithDeadline(ctx, r.deadline)
+	entries, ch, err := r.LogSearchResult.GetEntries(entriesCtx)
+	defer func() {
+		// A stream keeps reading until the deadline
+		if ch == nil {
+			cancel()
+			r.cancel()
+		}
+	}()
+	return entries, ch, labelTimeout(entriesCtx, r.timeout, err)
+}
+
+// GetFieldNames forwards to the lister of the wrapped result.
+func (r *timeoutResult) GetFieldNames(ctx context.Context) ([]string, error) {
+	return client.ForwardFieldNames(ctx, r, r.LogSearchResult)
+}
+
+// Close closes the wrapped result and releases the context of its request.
+func (r *timeoutResult) Close() error {
+	defer r.cancel()
+	return client.ForwardClose(r.LogSearchResult)
+}
@@ -0,0 +1,96 @@
+package factory
+
+import (
+	"context"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans of the search factory.
+const tracerName = "github.com/bascanada/logviewer/pkg/log/factory"
+
+// tracer records query spans, nil until SetTracerProvider is called so
+// queries are not wrapped at all without tracing.
+var tracer trace.Tracer
+
+// SetTracerProvider makes the search factories record a span for each
+// GetSearchResult, GetEntries, GetFields and GetFieldValues call with tp; nil
+// turns tracing off. Spans only carry the context id, client, backend type and
+// result counts, never search options that may hold secrets.
+func SetTracerProvider(tp trace.TracerProvider) {
+	if tp == nil {
+		tracer = nil
+		return
+	}
+	tracer = tp.Tracer(tracerName)
+}
+
+// startSpan starts a span under the span of ctx, if any. It returns a nil
+// span when tracing is off.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if tracer == nil {
+		return ctx, nil
+	}
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan sets attrs and err on span and ends it; it does nothing for a nil
+// span.
+func endSpan(span trace.Span, err error, attrs ...attribute.KeyValue) {
+	if span == nil {
+		return
+	}
+	span.SetAttributes(attrs...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// searchAttributes returns the span attributes identifying a query.
+func (sf *logSearchFactory) searchAttributes(contextID, clientID string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("logviewer.context_id", contextID),
+		attribute.String("logviewer.client", clientID),
+		attribute.String("logviewer.backend", sf.config.Clients[clientID].Type),
+	}
+}
+
+// tracedResult records a span for each GetEntries, GetFields and
+// GetFieldNames call of the result it wraps.
+type tracedResult struct {
+	client.LogSearchResult
+
+	attrs []attribute.KeyValue
+}
+
+func (r *tracedResult) GetEntries(ctx context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	ctx, span := startSpan(ctx, "logviewer.GetEntries", r.attrs...)
+	entries, ch, err := r.LogSearchResult.GetEntries(ctx)
+	// A stream only counts the entries returned before it starts
+	endSpan(span, err, attribute.Int("logviewer.result_count", len(entries)), attribute.Bool("logviewer.streaming", ch != nil))
+	return entries, ch, err
+}
+
+func (r *tracedResult) GetFields(ctx context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
+	ctx, span := startSpan(ctx, "logviewer.GetFields", r.attrs...)
+	fields, ch, err := r.LogSearchResult.GetFields(ctx)
+	endSpan(span, err, attribute.Int("logviewer.field_count", len(fields)))
+	return fields, ch, err
+}
+
+func (r *tracedResult) GetFieldNames(ctx context.Context) ([]string, error) {
+	ctx, span := startSpan(ctx, "logviewer.GetFieldNames", r.attrs...)
+	names, err := client.ForwardFieldNames(ctx, r, r.LogSearchResult)
+	endSpan(span, err, attribute.Int("logviewer.field_count", len(names)))
+	return names, err
+}
+
+func (r *tracedResult) Close() error {
+	return client.ForwardClose(r.LogSearchResult)
+}
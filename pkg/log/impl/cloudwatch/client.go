@@ -6,7 +6,6 @@ package cloudwatch
 import (
 	"context"
 	"errors"
-	"fmt"
 	"log/slog"
 	"strings"
 	"time"
@@ -16,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
 	"github.com/bascanada/logviewer/pkg/ty"
 )
 
@@ -31,6 +31,9 @@ type CWClient interface {
 type LogClient struct {
 	client CWClient
 	logger *slog.Logger
+	// retry bounds the retries of throttled calls; zero means
+	// client.DefaultRetryPolicy
+	retry client.RetryPolicy
 }
 
 // sanitizeQueryValue escapes single quotes in user provided values to safely embed them
@@ -56,6 +59,33 @@ func isSafeFieldName(name string) bool {
 	return true
 }
 
+// maxInsightsLimit is the largest limit a Logs Insights query accepts.
+const maxInsightsLimit = 10000
+
+// backendName tags the page tokens of Insights queries, whose cursor is the
+// timestamp of the oldest entry of the previous page.
+const backendName = "cloudwatch"
+
+// MaxPageSize implements client.PageSizeLimiter: sizes above the Insights
+// limit are fetched in several queries. The FilterLogEvents fallback does not
+// support page tokens, so it is not paged.
+func (c *LogClient) MaxPageSize(search *client.LogSearch) int {
+	if useInsights, ok := search.Options.GetBoolOk("useInsights"); ok && !useInsights {
+		return 0
+	}
+	return maxInsightsLimit
+}
+
+// Capabilities implements client.CapabilityReporter: Logs Insights applies
+// every filter, the FilterLogEvents fallback only plain top-level equality
+// conditions. Neither applies the conditions on fields it cannot name.
+func (c *LogClient) Capabilities(search *client.LogSearch) client.Capabilities {
+	if useInsights, ok := search.Options.GetBoolOk("useInsights"); ok && !useInsights {
+		return client.Capabilities{Operators: []string{operator.Equals}, Fields: queryableField}
+	}
+	return client.Capabilities{FreeText: true, Negation: true, Groups: true, Fields: queryableField}
+}
+
 // Get executes a CloudWatch Logs query and returns the results.
 //
 //nolint:gocyclo // Complex search parameter handling and API orchestration
@@ -72,40 +102,17 @@ func (c *LogClient) Get(ctx context.Context, search *client.LogSearch) (client.L
 	}
 
 	// 1. Build the query string
-	var queryParts []string
-	// Always fetch the raw message and timestamp
-	queryParts = append(queryParts, "fields @timestamp, @message")
-
-	// Add filters from LogSearch.Fields with sanitization to avoid query injection.
-	for key, value := range search.Fields {
-		if !isSafeFieldName(key) {
-			// Skip unsafe field names to avoid injection via the key itself.
-			continue
-		}
-		sanitizedValue := sanitizeQueryValue(value)
-		queryParts = append(queryParts, fmt.Sprintf(" | filter %s = '%s'", key, sanitizedValue))
-	}
-
-	// Add sorting and limits
-	queryParts = append(queryParts, " | sort @timestamp desc")
-
-	if search.PageToken.Set && search.PageToken.Value != "" {
-		// The page token is the timestamp of the last event from the previous page.
-		// We need to fetch events *before* this timestamp.
-		// We also need to validate the token is a valid timestamp.
-		if _, err := time.Parse(time.RFC3339Nano, search.PageToken.Value); err != nil {
-			return nil, fmt.Errorf("invalid page token: expected a timestamp in RFC3339Nano format, got %s", search.PageToken.Value)
-		}
-		sanitizedToken := sanitizeQueryValue(search.PageToken.Value)
-		queryParts = append(queryParts, fmt.Sprintf(" | filter @timestamp < timestamp('%s')", sanitizedToken))
+	queryString, err := buildInsightsQuery(search)
+	if err != nil {
+		return nil, err
 	}
 
-	if search.Size.Set {
-		queryParts = append(queryParts, " | limit "+fmt.Sprintf("%d", search.Size.Value))
+	// In native-only mode the Insights query is sent verbatim. The time range is
+	// still applied because Insights only accepts it as StartQuery parameters.
+	if search.IsNativeQueryOnly() {
+		queryString = search.NativeQuery.Value
 	}
 
-	queryString := strings.Join(queryParts, "")
-
 	// 2. Determine time range using search.Range (Last takes precedence over Gte/Lte)
 	endTime := time.Now()
 	startTime := endTime.Add(-1 * time.Hour) // default fallback
@@ -152,11 +159,15 @@ func (c *LogClient) Get(ctx context.Context, search *client.LogSearch) (client.L
 
 	// 3. Execute either Insights query or FilterLogEvents fallback
 	if useInsights {
-		startQueryOutput, err := c.client.StartQuery(ctx, &cloudwatchlogs.StartQueryInput{
-			LogGroupName: aws.String(logGroupName),
-			QueryString:  aws.String(queryString),
-			StartTime:    aws.Int64(startTime.UnixMilli()),
-			EndTime:      aws.Int64(endTime.UnixMilli()),
+		var startQueryOutput *cloudwatchlogs.StartQueryOutput
+		err := callWithRetry(ctx, c.retry, c.logger, "StartQuery", func() (err error) {
+			startQueryOutput, err = c.client.StartQuery(ctx, &cloudwatchlogs.StartQueryInput{
+				LogGroupName: aws.String(logGroupName),
+				QueryString:  aws.String(queryString),
+				StartTime:    aws.Int64(startTime.UnixMilli()),
+				EndTime:      aws.Int64(endTime.UnixMilli()),
+			})
+			return err
 		})
 		if err != nil {
 			return nil, err
@@ -164,7 +175,12 @@ func (c *LogClient) Get(ctx context.Context, search *client.LogSearch) (client.L
 		if startQueryOutput.QueryId == nil {
 			return nil, errors.New("StartQuery did not return a QueryId")
 		}
-		return &LogSearchResult{client: c.client, queryID: *startQueryOutput.QueryId, search: search, logger: c.logger}, nil
+		result := &LogSearchResult{client: c.client, queryID: *startQueryOutput.QueryId, search: search, logger: c.logger, retry: c.retry}
+		if !search.IsNativeQueryOnly() {
+			// The conditions left out of the query are matched on its results
+			_, result.rest = insightsConditions(search.GetEffectiveFilter())
+		}
+		return result, nil
 	}
 
 	// FilterLogEvents fallback
@@ -174,18 +190,12 @@ func (c *LogClient) Get(ctx context.Context, search *client.LogSearch) (client.L
 		EndTime:      aws.Int64(endTime.UnixMilli()),
 	}
 	// Add filter pattern if simple equality filters are present (combine as AND)
-	if len(search.Fields) > 0 {
-		var parts []string
-		for k, v := range search.Fields {
-			if isSafeFieldName(k) {
-				parts = append(parts, fmt.Sprintf("%s=\"%s\"", k, sanitizeQueryValue(v)))
-			}
-		}
-		if len(parts) > 0 {
-			p := strings.Join(parts, " ")
-			input.FilterPattern = aws.String(p)
-		}
+	effectiveFilter := search.GetEffectiveFilter()
+	if p := buildFilterPattern(effectiveFilter); p != "" {
+		input.FilterPattern = aws.String(p)
 	}
+	// The other conditions are matched on the events received
+	rest := clientSideFilter(effectiveFilter)
 	// Page through results until size reached or no more
 	entries := []client.LogEntry{}
 	nextToken := aws.String("")
@@ -193,21 +203,38 @@ func (c *LogClient) Get(ctx context.Context, search *client.LogSearch) (client.L
 		if nextToken != nil && *nextToken != "" {
 			input.NextToken = nextToken
 		}
-		out, err := c.client.FilterLogEvents(ctx, input)
+		var out *cloudwatchlogs.FilterLogEventsOutput
+		err := callWithRetry(ctx, c.retry, c.logger, "FilterLogEvents", func() (err error) {
+			out, err = c.client.FilterLogEvents(ctx, input)
+			return err
+		})
 		if err != nil {
+			// The pages received before a timeout are kept
+			if err = client.PartialOnTimeout(entries, err); client.IsPartial(err) {
+				return &staticCloudWatchResult{entries: entries, search: search, err: err}, nil
+			}
 			return nil, err
 		}
+		received := len(entries)
 		for _, e := range out.Events {
 			msg := ""
 			if e.Message != nil {
 				msg = *e.Message
 			}
 			ts := time.Unix(0, *e.Timestamp*int64(time.Millisecond))
-			entries = append(entries, client.LogEntry{Timestamp: ts, Message: msg, Fields: ty.MI{}})
+			entry := client.LogEntry{Timestamp: ts, Message: msg, Fields: ty.MI{}}
+			if rest != nil {
+				client.ExtractJSONFromEntry(&entry, search)
+				if !rest.Match(entry) {
+					continue
+				}
+			}
+			entries = append(entries, entry)
 			if search.Size.Set && len(entries) >= search.Size.Value {
 				break
 			}
 		}
+		client.AddEntryProgress(ctx, len(entries)-received)
 		if search.Size.Set && len(entries) >= search.Size.Value {
 			break
 		}
@@ -227,11 +254,12 @@ func (c *LogClient) Get(ctx context.Context, search *client.LogSearch) (client.L
 type staticCloudWatchResult struct {
 	entries []client.LogEntry
 	search  *client.LogSearch
+	err     error // partial result error of the pages cut short
 }
 
 func (r *staticCloudWatchResult) GetSearch() *client.LogSearch { return r.search }
 func (r *staticCloudWatchResult) GetEntries(_ context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
-	return r.entries, nil, nil
+	return r.entries, nil, r.err
 }
 func (r *staticCloudWatchResult) GetFields(_ context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
 	return ty.UniSet[string]{}, nil, nil
@@ -281,5 +309,8 @@ func GetLogClient(options ty.MI) (client.LogBackend, error) {
 		return nil, err
 	}
 
-	return &LogClient{client: cloudwatchlogs.NewFromConfig(cfg), logger: slog.Default()}, nil
+	cw := cloudwatchlogs.NewFromConfig(cfg, func(o *cloudwatchlogs.Options) {
+		o.Retryer = sdkRetryer{Retryer: o.Retryer}
+	})
+	return &LogClient{client: cw, logger: slog.Default()}, nil
 }
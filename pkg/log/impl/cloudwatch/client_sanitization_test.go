@@ -7,8 +7,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
 	"github.com/bascanada/logviewer/pkg/ty"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // mock client for sanitization tests
@@ -50,3 +52,90 @@ func TestQueryBuildingEscapesValues(t *testing.T) {
 	qs := *mockClient.startCaptured.QueryString
 	assert.Contains(t, qs, "filter level = 'error\\'critical'")
 }
+
+func TestQueryBuildingUsesFilterAST(t *testing.T) {
+	mockClient := &mockCWClientSanitize{}
+	c := &LogClient{client: mockClient}
+	s := &client.LogSearch{
+		Fields: ty.MS{"app": "api"},
+		Filter: &client.Filter{
+			Logic: client.LogicOr,
+			Filters: []client.Filter{
+				{Field: "level", Value: "ERROR"},
+				{Field: "status", Op: operator.Gte, Value: "500"},
+			},
+		},
+		Options: ty.MI{"logGroupName": "lg"},
+	}
+	_, err := c.Get(context.Background(), s)
+	assert.NoError(t, err)
+	qs := *mockClient.startCaptured.QueryString
+	assert.Contains(t, qs, "| filter app = 'api' | filter (level = 'ERROR' or status >= 500) |")
+}
+
+func TestBuildInsightsFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter client.Filter
+		want   string
+	}{
+		{"negated equals", client.Filter{Field: "level", Value: "INFO", Negate: true}, "not (level = 'INFO')"},
+		{"regex", client.Filter{Field: "path", Op: operator.Regex, Value: "^/api/v[0-9]"}, `path like /^\/api\/v[0-9]/`},
+		{"wildcard", client.Filter{Field: "host", Op: operator.Wildcard, Value: "web-*"}, `host like /^web-.*$/`},
+		{"match", client.Filter{Field: "msg", Op: operator.Match, Value: "time.out"}, `msg like /(?i)time\.out/`},
+		{"exists", client.Filter{Field: "trace", Op: operator.Exists}, "ispresent(trace)"},
+		{"not exists", client.Filter{Field: "trace", Op: operator.NotExists}, "not ispresent(trace)"},
+		{"negated not exists", client.Filter{Field: "trace", Op: operator.NotExists, Negate: true}, "ispresent(trace)"},
+		{"non numeric comparison", client.Filter{Field: "ver", Op: operator.Lt, Value: "b"}, "ver < 'b'"},
+		{"free text", client.Filter{Field: "_", Value: "timeout"}, "@message like 'timeout'"},
+		{"unsafe field dropped", client.Filter{Field: "a;b", Value: "x"}, ""},
+		{"not group", client.Filter{Logic: client.LogicNot, Filters: []client.Filter{{Field: "a", Value: "1"}, {Field: "b", Value: "2"}}}, "not (a = '1' and b = '2')"},
+		{"equals", client.Filter{Field: "level", Value: "ERROR"}, "level = 'ERROR'"},
+		{"nested groups", client.Filter{Logic: client.LogicOr, Filters: []client.Filter{
+			{Logic: client.LogicAnd, Filters: []client.Filter{{Field: "app", Value: "api"}, {Field: "status", Op: operator.Gte, Value: "500"}}},
+			{Logic: client.LogicNot, Filters: []client.Filter{{Field: "trace", Op: operator.Exists}}},
+		}}, "((app = 'api' and status >= 500) or not (ispresent(trace)))"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, buildInsightsFilter(&tt.filter))
+		})
+	}
+}
+
+func TestBuildInsightsQuery(t *testing.T) {
+	search := &client.LogSearch{
+		Fields: ty.MS{"app": "api"},
+		Filter: &client.Filter{Logic: client.LogicAnd, Filters: []client.Filter{
+			{Field: "path", Op: operator.Regex, Value: "^/v1"},
+			{Logic: client.LogicOr, Filters: []client.Filter{{Field: "level", Value: "ERROR"}, {Field: "panic", Op: operator.Exists}}},
+		}},
+	}
+	search.Size.S(50)
+	query, err := buildInsightsQuery(search)
+	require.NoError(t, err)
+	assert.Equal(t, "fields @timestamp, @message | filter app = 'api' | filter (path like /^\\/v1/ and (level = 'ERROR' or ispresent(panic))) | sort @timestamp desc | limit 50", query)
+
+	// No filter and no size: every entry, newest first
+	query, err = buildInsightsQuery(&client.LogSearch{})
+	require.NoError(t, err)
+	assert.Equal(t, "fields @timestamp, @message | sort @timestamp desc", query)
+
+	// The page token bounds the timestamp
+	paged := &client.LogSearch{PageToken: ty.OptWrap(client.EncodePageToken(backendName, "2024-01-02T03:04:05Z"))}
+	query, err = buildInsightsQuery(paged)
+	require.NoError(t, err)
+	assert.Contains(t, query, "| sort @timestamp desc | filter @timestamp < timestamp('2024-01-02T03:04:05Z')")
+	paged.PageToken = ty.OptWrap("yesterday")
+	_, err = buildInsightsQuery(paged)
+	assert.ErrorContains(t, err, "invalid page token")
+}
+
+func TestBuildFilterPattern(t *testing.T) {
+	s := &client.LogSearch{
+		Fields:          ty.MS{"level": "error", "app": "api"},
+		FieldsCondition: ty.MS{"app": operator.Regex},
+	}
+	assert.Equal(t, `level="error"`, buildFilterPattern(s.GetEffectiveFilter()))
+	assert.Equal(t, "", buildFilterPattern(nil))
+}
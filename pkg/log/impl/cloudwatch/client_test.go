@@ -2,16 +2,26 @@ package cloudwatch
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
 	"github.com/bascanada/logviewer/pkg/ty"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // mockCWClient is a mock implementation of the CWClient interface.
@@ -136,6 +146,28 @@ func TestCloudWatch_TimeRange_Last(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestLogClient_Get_NativeQueryOnly(t *testing.T) {
+	native := "fields @timestamp, @message | filter @message like /timeout/ | stats count() by bin(5m)"
+	mockClient := &mockCWClient{
+		StartQueryFunc: func(_ context.Context, params *cloudwatchlogs.StartQueryInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error) {
+			assert.Equal(t, native, *params.QueryString)
+			windowMs := *params.EndTime - *params.StartTime
+			assert.InDelta(t, 10*60*1000, windowMs, 5*1000)
+			return &cloudwatchlogs.StartQueryOutput{QueryId: aws.String("qid-native")}, nil
+		},
+	}
+	c := &LogClient{client: mockClient}
+	s := &client.LogSearch{
+		Options:         ty.MI{"logGroupName": "lg"},
+		Fields:          ty.MS{"level": "ERROR"},
+		NativeQueryOnly: true,
+	}
+	s.NativeQuery.S(native)
+	s.Range.Last.S("10m")
+	_, err := c.Get(context.Background(), s)
+	assert.NoError(t, err)
+}
+
 func TestCloudWatch_TimeRange_GteLte(t *testing.T) {
 	mockClient := &mockCWClient{
 		StartQueryFunc: func(_ context.Context, params *cloudwatchlogs.StartQueryInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error) {
@@ -250,7 +282,7 @@ func TestLogSearchResult_GetPaginationInfo(t *testing.T) {
 		info := result.GetPaginationInfo()
 		assert.NotNil(t, info)
 		assert.True(t, info.HasMore)
-		assert.Equal(t, lastTimestamp.Format(time.RFC3339Nano), info.NextPageToken)
+		assert.Equal(t, client.EncodePageToken("cloudwatch", lastTimestamp.Format(time.RFC3339Nano)), info.NextPageToken)
 	})
 }
 
@@ -373,6 +405,37 @@ func TestLogSearchResult_PollingMechanism(t *testing.T) {
 		assert.True(t, pollCount >= 1, "Should have polled at least once")
 	})
 
+	t.Run("Timeout keeps the results found so far", func(t *testing.T) {
+		mockClient := &mockCWClient{
+			GetQueryResultsFunc: func(_ context.Context, _ *cloudwatchlogs.GetQueryResultsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error) {
+				// A running query returns the rows found so far
+				return &cloudwatchlogs.GetQueryResultsOutput{
+					Status: types.QueryStatusRunning,
+					Results: [][]types.ResultField{
+						{{Field: aws.String("@message"), Value: aws.String("found early")}},
+					},
+				}, nil
+			},
+		}
+
+		result := &LogSearchResult{
+			client:  mockClient,
+			queryID: "test-query-id",
+			search:  &client.LogSearch{Options: ty.MI{"cloudwatchPollInterval": "1ms"}},
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		entries, _, err := result.GetEntries(ctx)
+		assert.True(t, client.IsPartial(err))
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, "found early", entries[0].Message)
+		}
+		_, _, err = result.GetEntries(ctx)
+		assert.True(t, client.IsPartial(err), "still partial once cached")
+	})
+
 	t.Run("Handles Failed status", func(t *testing.T) {
 		mockClient := &mockCWClient{
 			GetQueryResultsFunc: func(_ context.Context, _ *cloudwatchlogs.GetQueryResultsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error) {
@@ -454,3 +517,187 @@ func TestParseCloudWatchTimestamp(t *testing.T) {
 		assert.False(t, ok)
 	})
 }
+
+func TestLogClient_Get_FilterLogEventsClientSideFilter(t *testing.T) {
+	mockClient := &mockCWClient{
+		FilterLogEventsFunc: func(_ context.Context, params *cloudwatchlogs.FilterLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+			assert.Equal(t, `level="error"`, aws.ToString(params.FilterPattern))
+			return &cloudwatchlogs.FilterLogEventsOutput{Events: []types.FilteredLogEvent{
+				{Timestamp: aws.Int64(1), Message: aws.String("connection timeout")},
+				{Timestamp: aws.Int64(2), Message: aws.String("disk full")},
+			}}, nil
+		},
+	}
+	c := &LogClient{client: mockClient}
+	s := &client.LogSearch{
+		Options:         ty.MI{"logGroupName": "lg", "useInsights": false},
+		Fields:          ty.MS{"level": "error", "_": "time.ut"},
+		FieldsCondition: ty.MS{"_": operator.Regex},
+	}
+
+	caps := c.Capabilities(s)
+	assert.Equal(t, []string{operator.Equals}, caps.Operators)
+	assert.False(t, caps.FreeText)
+
+	result, err := c.Get(context.Background(), s)
+	assert.NoError(t, err)
+	entries, _, err := result.GetEntries(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "connection timeout", entries[0].Message)
+	}
+}
+
+func TestLogClient_Get_InsightsClientSideFilter(t *testing.T) {
+	mockClient := &mockCWClient{
+		StartQueryFunc: func(_ context.Context, params *cloudwatchlogs.StartQueryInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error) {
+			// The OR group naming user-id is left out whole, not narrowed
+			assert.Equal(t, "fields @timestamp, @message | filter app = 'api' | sort @timestamp desc", aws.ToString(params.QueryString))
+			return &cloudwatchlogs.StartQueryOutput{QueryId: aws.String("q")}, nil
+		},
+		GetQueryResultsFunc: func(_ context.Context, _ *cloudwatchlogs.GetQueryResultsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error) {
+			row := func(message string) []types.ResultField {
+				return []types.ResultField{{Field: aws.String("@message"), Value: aws.String(message)}}
+			}
+			return &cloudwatchlogs.GetQueryResultsOutput{Status: types.QueryStatusComplete, Results: [][]types.ResultField{
+				row(`{"user-id":"42","level":"INFO"}`),
+				row(`{"user-id":"7","level":"ERROR"}`),
+				row(`{"user-id":"7","level":"INFO"}`),
+			}}, nil
+		},
+	}
+	c := &LogClient{client: mockClient}
+	s := &client.LogSearch{
+		Options: ty.MI{"logGroupName": "lg"},
+		Fields:  ty.MS{"app": "api"},
+		Filter: &client.Filter{Logic: client.LogicOr, Filters: []client.Filter{
+			{Field: "user-id", Value: "42"},
+			{Field: "level", Value: "ERROR"},
+		}},
+	}
+	s.FieldExtraction.JSON.S(true)
+
+	fallbacks := client.Fallbacks(c, s)
+	if assert.Len(t, fallbacks, 1) {
+		assert.Equal(t, `"user-id" field`, fallbacks[0].Capability)
+	}
+
+	result, err := c.Get(context.Background(), s)
+	require.NoError(t, err)
+	entries, _, err := result.GetEntries(context.Background())
+	require.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "42", entries[0].Fields["user-id"])
+		assert.Equal(t, "ERROR", entries[1].Level)
+	}
+}
+
+func TestLogClient_Get_FilterLogEventsTimeout(t *testing.T) {
+	calls := 0
+	mockClient := &mockCWClient{
+		FilterLogEventsFunc: func(_ context.Context, _ *cloudwatchlogs.FilterLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+			calls++
+			switch calls {
+			case 1:
+				return &cloudwatchlogs.FilterLogEventsOutput{
+					Events:    []types.FilteredLogEvent{{Timestamp: aws.Int64(1), Message: aws.String("page 1")}},
+					NextToken: aws.String("next"),
+				}, nil
+			case 2:
+				return nil, context.DeadlineExceeded
+			}
+			return nil, errors.New("AccessDeniedException")
+		},
+	}
+	c := &LogClient{client: mockClient}
+	s := &client.LogSearch{Options: ty.MI{"logGroupName": "lg", "useInsights": false}}
+
+	result, err := c.Get(context.Background(), s)
+	assert.NoError(t, err)
+	entries, _, err := result.GetEntries(context.Background())
+	assert.True(t, client.IsPartial(err))
+	assert.Len(t, entries, 1, "the pages received before the timeout are kept")
+
+	// Other errors still fail the query
+	_, err = c.Get(context.Background(), s)
+	assert.ErrorContains(t, err, "AccessDeniedException")
+	assert.False(t, client.IsPartial(err))
+}
+
+func TestLogClient_RetriesThrottledCalls(t *testing.T) {
+	throttle := &smithy.GenericAPIError{Code: "LimitExceededException", Message: "too many concurrent queries"}
+	policy := client.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxElapsed: time.Second}
+
+	starts := 0
+	mockClient := &mockCWClient{
+		StartQueryFunc: func(_ context.Context, _ *cloudwatchlogs.StartQueryInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error) {
+			starts++
+			if starts == 1 {
+				return nil, throttle
+			}
+			return &cloudwatchlogs.StartQueryOutput{QueryId: aws.String("qid")}, nil
+		},
+	}
+	c := &LogClient{client: mockClient, retry: policy}
+	search := &client.LogSearch{Options: ty.MI{"logGroupName": "g"}}
+	_, err := c.Get(context.Background(), search)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, starts)
+
+	mockClient.StartQueryFunc = func(_ context.Context, _ *cloudwatchlogs.StartQueryInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error) {
+		return nil, throttle
+	}
+	_, err = c.Get(context.Background(), search)
+	assert.True(t, client.IsBackendUnavailable(err))
+
+	denied := &smithy.GenericAPIError{Code: "AccessDeniedException"}
+	calls := 0
+	mockClient.FilterLogEventsFunc = func(_ context.Context, _ *cloudwatchlogs.FilterLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+		calls++
+		return nil, denied
+	}
+	_, err = c.Get(context.Background(), &client.LogSearch{Options: ty.MI{"logGroupName": "g", "useInsights": false}})
+	assert.ErrorIs(t, err, denied)
+	assert.Equal(t, 1, calls, "errors other than throttling are not retried")
+}
+
+func TestGetLogClient_ThrottlesRetriedOnce(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"__type":"ThrottlingException","message":"Rate exceeded"}`))
+	}))
+	defer server.Close()
+
+	backend, err := GetLogClient(ty.MI{"region": "us-east-1", "endpoint": server.URL})
+	require.NoError(t, err)
+	c := backend.(*LogClient)
+	c.retry = client.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxElapsed: time.Second}
+
+	_, err = c.Get(context.Background(), &client.LogSearch{Options: ty.MI{"logGroupName": "g"}})
+	assert.True(t, client.IsBackendUnavailable(err))
+	assert.Equal(t, int32(2), requests.Load(), "the SDK leaves throttled calls to the retry policy")
+}
+
+func TestSDKRetryer(t *testing.T) {
+	r := sdkRetryer{Retryer: retry.NewStandard()}
+	assert.False(t, r.IsErrorRetryable(&smithy.GenericAPIError{Code: "ThrottlingException"}))
+	assert.True(t, r.IsErrorRetryable(&smithy.GenericAPIError{Code: "RequestTimeoutException"}), "other transient errors are retried")
+	_, ok := interface{}(r).(aws.RetryerV2)
+	assert.True(t, ok)
+}
+
+func TestAsThrottledRetryAfter(t *testing.T) {
+	resp := &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"2"}}}}
+	err := &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{Response: resp, Err: errors.New("rate exceeded")}}
+
+	var throttled *client.ThrottledError
+	if assert.ErrorAs(t, asThrottled(err), &throttled) {
+		assert.Equal(t, 2*time.Second, throttled.RetryAfter)
+	}
+	assert.Nil(t, asThrottled(nil))
+}
@@ -0,0 +1,283 @@
+package cloudwatch
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
+)
+
+// insightsRegex escapes a regex for a /.../ literal in an Insights query.
+func insightsRegex(pattern string) string {
+	return "/" + strings.ReplaceAll(pattern, "/", `\/`) + "/"
+}
+
+// insightsValue quotes v unless it is a number, for comparison operators.
+func insightsValue(v string) string {
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return v
+	}
+	return "'" + sanitizeQueryValue(v) + "'"
+}
+
+// buildInsightsCondition converts a leaf filter to a Logs Insights boolean
+// expression. Unsafe field names yield an empty condition.
+func buildInsightsCondition(f *client.Filter) string {
+	field := f.Field
+	if field == "" {
+		return ""
+	}
+	// "_" is the free-text sentinel, searched in the raw message
+	if field == "_" {
+		field = "@message"
+	}
+	if !isSafeFieldName(field) {
+		return ""
+	}
+
+	op := f.Op
+	if op == "" {
+		op = operator.Equals
+	}
+
+	var cond string
+	switch op {
+	case operator.Exists:
+		cond = fmt.Sprintf("ispresent(%s)", field)
+	case operator.NotExists:
+		if f.Negate {
+			return fmt.Sprintf("ispresent(%s)", field)
+		}
+		return fmt.Sprintf("not ispresent(%s)", field)
+	case operator.Regex:
+		cond = fmt.Sprintf("%s like %s", field, insightsRegex(f.Value))
+	case operator.Wildcard:
+		pattern := regexp.QuoteMeta(f.Value)
+		pattern = strings.ReplaceAll(pattern, `\*`, `.*`)
+		pattern = strings.ReplaceAll(pattern, `\?`, `.`)
+		cond = fmt.Sprintf("%s like %s", field, insightsRegex("^"+pattern+"$"))
+	case operator.Match:
+		cond = fmt.Sprintf("%s like %s", field, insightsRegex("(?i)"+regexp.QuoteMeta(f.Value)))
+	case operator.Gt:
+		cond = fmt.Sprintf("%s > %s", field, insightsValue(f.Value))
+	case operator.Gte:
+		cond = fmt.Sprintf("%s >= %s", field, insightsValue(f.Value))
+	case operator.Lt:
+		cond = fmt.Sprintf("%s < %s", field, insightsValue(f.Value))
+	case operator.Lte:
+		cond = fmt.Sprintf("%s <= %s", field, insightsValue(f.Value))
+	default: // equals
+		if f.Field == "_" {
+			// Free text is a substring search, like the other backends
+			cond = fmt.Sprintf("%s like '%s'", field, sanitizeQueryValue(f.Value))
+		} else {
+			cond = fmt.Sprintf("%s = '%s'", field, sanitizeQueryValue(f.Value))
+		}
+	}
+
+	if f.Negate {
+		return fmt.Sprintf("not (%s)", cond)
+	}
+	return cond
+}
+
+// buildInsightsFilter converts a filter tree to a Logs Insights boolean
+// expression. It returns an empty string when nothing can be expressed.
+func buildInsightsFilter(f *client.Filter) string {
+	if f == nil {
+		return ""
+	}
+
+	// Handle Leaf (Condition)
+	if f.Field != "" {
+		return buildInsightsCondition(f)
+	}
+
+	// Handle Branch (Group)
+	if f.Logic == "" || len(f.Filters) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for i := range f.Filters {
+		if part := buildInsightsFilter(&f.Filters[i]); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	switch f.Logic {
+	case client.LogicOr:
+		if len(parts) == 1 {
+			return parts[0]
+		}
+		return "(" + strings.Join(parts, " or ") + ")"
+	case client.LogicNot:
+		// NOT applies to all children (ANDed together, then inverted)
+		return "not (" + strings.Join(parts, " and ") + ")"
+	default:
+		if len(parts) == 1 {
+			return parts[0]
+		}
+		return "(" + strings.Join(parts, " and ") + ")"
+	}
+}
+
+// queryableField reports whether conditions on field can be sent to
+// CloudWatch, whose queries only name fields of safe runes.
+func queryableField(field string) bool {
+	return field == "_" || isSafeFieldName(field)
+}
+
+// inInsightsQuery reports whether every field f has a condition on can be
+// named in an Insights query.
+func inInsightsQuery(f *client.Filter) bool {
+	if f.Field != "" {
+		return queryableField(f.Field)
+	}
+	for i := range f.Filters {
+		if !inInsightsQuery(&f.Filters[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// insightsConditions splits the conditions ANDed at the top of f between the
+// ones sent in the Insights query and the rest, matched client-side on the
+// entries returned, nil when there is none. A condition naming a field the
+// query cannot hold is matched client-side as a whole, so an OR group keeps
+// all of its alternatives.
+func insightsConditions(f *client.Filter) ([]*client.Filter, *client.Filter) {
+	if f == nil {
+		return nil, nil
+	}
+	conditions := []*client.Filter{f}
+	if f.Logic == client.LogicAnd {
+		conditions = conditions[:0]
+		for i := range f.Filters {
+			conditions = append(conditions, &f.Filters[i])
+		}
+	}
+
+	var native []*client.Filter
+	var rest []client.Filter
+	for _, c := range conditions {
+		if inInsightsQuery(c) {
+			native = append(native, c)
+		} else {
+			rest = append(rest, *c)
+		}
+	}
+	if len(rest) == 0 {
+		return native, nil
+	}
+	return native, &client.Filter{Logic: client.LogicAnd, Filters: rest}
+}
+
+// buildInsightsQuery translates search to a Logs Insights query: the
+// timestamp and message fields, a filter command per condition ANDed at the
+// top of the effective filter (legacy Fields + Filter AST), the newest
+// entries first, the page token as an upper bound on the timestamp and Size
+// as the limit. The conditions insightsConditions leaves out are not sent.
+func buildInsightsQuery(search *client.LogSearch) (string, error) {
+	var queryParts []string
+	// Always fetch the raw message and timestamp
+	queryParts = append(queryParts, "fields @timestamp, @message")
+
+	// Values are sanitized to avoid query injection
+	conditions, _ := insightsConditions(search.GetEffectiveFilter())
+	for _, f := range conditions {
+		if cond := buildInsightsFilter(f); cond != "" {
+			queryParts = append(queryParts, " | filter "+cond)
+		}
+	}
+
+	// Add sorting and limits
+	queryParts = append(queryParts, " | sort @timestamp desc")
+
+	cursor, err := client.PageCursor(search, backendName)
+	if err != nil {
+		return "", err
+	}
+	if cursor != "" {
+		// The page token is the timestamp of the last event from the previous page.
+		// We need to fetch events *before* this timestamp.
+		// We also need to validate the token is a valid timestamp.
+		if _, err := time.Parse(time.RFC3339Nano, cursor); err != nil {
+			return "", fmt.Errorf("invalid page token: expected a timestamp in RFC3339Nano format, got %s", cursor)
+		}
+		sanitizedToken := sanitizeQueryValue(cursor)
+		queryParts = append(queryParts, fmt.Sprintf(" | filter @timestamp < timestamp('%s')", sanitizedToken))
+	}
+
+	if search.Size.Set {
+		queryParts = append(queryParts, " | limit "+fmt.Sprintf("%d", search.Size.Value))
+	}
+
+	return strings.Join(queryParts, ""), nil
+}
+
+// buildFilterPattern builds a FilterLogEvents pattern from the plain equality
+// conditions at the top level of the filter. The pattern syntax cannot express
+// the other conditions, which clientSideFilter returns instead.
+func buildFilterPattern(f *client.Filter) string {
+	var parts []string
+	for _, leaf := range topLevelLeaves(f) {
+		if inFilterPattern(&leaf) {
+			parts = append(parts, fmt.Sprintf("%s=\"%s\"", leaf.Field, sanitizeQueryValue(leaf.Value)))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// clientSideFilter returns the part of the filter buildFilterPattern leaves
+// out, to be matched on the FilterLogEvents entries, or nil when there is
+// none.
+func clientSideFilter(f *client.Filter) *client.Filter {
+	if f == nil {
+		return nil
+	}
+	if f.Logic != "" && f.Logic != client.LogicAnd {
+		return f
+	}
+
+	var rest []client.Filter
+	for _, leaf := range topLevelLeaves(f) {
+		if !inFilterPattern(&leaf) {
+			rest = append(rest, leaf)
+		}
+	}
+	if len(rest) == 0 {
+		return nil
+	}
+	return &client.Filter{Logic: client.LogicAnd, Filters: rest}
+}
+
+// topLevelLeaves returns the conditions ANDed at the top of f, none when f is
+// an OR or NOT group.
+func topLevelLeaves(f *client.Filter) []client.Filter {
+	if f == nil {
+		return nil
+	}
+	if f.Logic == client.LogicAnd {
+		return f.Filters
+	}
+	if f.Logic != "" {
+		return nil
+	}
+	return []client.Filter{*f}
+}
+
+// inFilterPattern reports whether a top-level condition can be expressed in
+// a FilterLogEvents pattern.
+func inFilterPattern(leaf *client.Filter) bool {
+	return leaf.Field != "" && leaf.Field != "_" && !leaf.Negate &&
+		(leaf.Op == "" || leaf.Op == operator.Equals) && isSafeFieldName(leaf.Field)
+}
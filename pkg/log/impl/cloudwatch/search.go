@@ -19,10 +19,17 @@ type LogSearchResult struct {
 	queryID string
 	search  *client.LogSearch
 	logger  *slog.Logger
+	retry   client.RetryPolicy
 
 	// cached results
 	entries []client.LogEntry
 	fields  ty.UniSet[string]
+	// partialErr is the timeout that stopped the polling, the entries being
+	// the ones the query had found until then
+	partialErr error
+	// rest is the part of the filter left out of the query, matched on the
+	// entries it returns
+	rest *client.Filter
 }
 
 // GetSearch returns the search configuration.
@@ -33,7 +40,7 @@ func (r *LogSearchResult) GetSearch() *client.LogSearch {
 // GetEntries polls for the query results and converts them.
 func (r *LogSearchResult) fetchEntries(ctx context.Context) error {
 	if len(r.entries) > 0 { // already fetched
-		return nil
+		return r.partialErr
 	}
 	var results *cloudwatchlogs.GetQueryResultsOutput
 	// Determine base polling interval from options; default 1s. Allow override via option: cloudwatchPollInterval (duration string)
@@ -59,11 +66,15 @@ func (r *LogSearchResult) fetchEntries(ctx context.Context) error {
 	}
 	interval := baseInterval
 	for attempt := 0; ; attempt++ {
-		var err error
-		results, err = r.client.GetQueryResults(ctx, &cloudwatchlogs.GetQueryResultsInput{QueryId: &r.queryID})
-		if err != nil {
+		var polled *cloudwatchlogs.GetQueryResultsOutput
+		err := callWithRetry(ctx, r.retry, r.logger, "GetQueryResults", func() (err error) {
+			polled, err = r.client.GetQueryResults(ctx, &cloudwatchlogs.GetQueryResultsInput{QueryId: &r.queryID})
 			return err
+		})
+		if err != nil {
+			return r.stopped(results, err)
 		}
+		results = polled
 		if results.Status == types.QueryStatusComplete || results.Status == types.QueryStatusFailed || results.Status == types.QueryStatusCancelled {
 			break
 		}
@@ -72,9 +83,32 @@ func (r *LogSearchResult) fetchEntries(ctx context.Context) error {
 			// Increase interval with backoff (exponential) until max
 			interval = time.Duration(math.Min(float64(maxInterval), float64(interval)*backoffFactor))
 		case <-ctx.Done():
-			return ctx.Err()
+			return r.stopped(results, ctx.Err())
 		}
 	}
+	r.setEntries(results)
+	return nil
+}
+
+// stopped ends the polling on err. A running query already returns the
+// results it found, so a timeout keeps the last ones polled as partial
+// results.
+func (r *LogSearchResult) stopped(results *cloudwatchlogs.GetQueryResultsOutput, err error) error {
+	if results == nil {
+		return err
+	}
+	r.setEntries(results)
+	err = client.PartialOnTimeout(r.entries, err)
+	if client.IsPartial(err) {
+		r.partialErr = err
+	} else {
+		r.entries = nil
+	}
+	return err
+}
+
+// setEntries converts the rows of results to the entries of the result.
+func (r *LogSearchResult) setEntries(results *cloudwatchlogs.GetQueryResultsOutput) {
 	for _, resultFields := range results.Results {
 		entry := client.LogEntry{Fields: make(ty.MI)}
 		for _, field := range resultFields {
@@ -96,9 +130,14 @@ func (r *LogSearchResult) fetchEntries(ctx context.Context) error {
 				entry.Fields[fName] = fVal
 			}
 		}
+		if r.rest != nil {
+			client.ExtractJSONFromEntry(&entry, r.search)
+			if !r.rest.Match(entry) {
+				continue
+			}
+		}
 		r.entries = append(r.entries, entry)
 	}
-	return nil
 }
 
 // Err returns an error channel (unused for CloudWatch).
@@ -108,10 +147,10 @@ func (r *LogSearchResult) Err() <-chan error {
 
 // GetEntries returns log entries and a channel for streaming updates.
 func (r *LogSearchResult) GetEntries(ctx context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
-	if err := r.fetchEntries(ctx); err != nil {
+	if err := r.fetchEntries(ctx); err != nil && !client.IsPartial(err) {
 		return nil, nil, err
 	}
-	return r.entries, nil, nil
+	return r.entries, nil, r.partialErr
 }
 
 // GetFields retrieves distinct values for the specified fields.
@@ -122,7 +161,7 @@ func (r *LogSearchResult) GetFields(ctx context.Context) (ty.UniSet[string], cha
 	}
 	// Ensure entries are loaded with passed context for proper cancellation.
 	if len(r.entries) == 0 {
-		if err := r.fetchEntries(ctx); err != nil {
+		if err := r.fetchEntries(ctx); err != nil && !client.IsPartial(err) {
 			return nil, nil, err
 		}
 	}
@@ -183,6 +222,6 @@ func (r *LogSearchResult) GetPaginationInfo() *client.PaginationInfo {
 
 	return &client.PaginationInfo{
 		HasMore:       true,
-		NextPageToken: nextPageToken,
+		NextPageToken: client.EncodePageToken(backendName, nextPageToken),
 	}
 }
@@ -0,0 +1,89 @@
+package cloudwatch
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	"github.com/bascanada/logviewer/pkg/log/client"
+)
+
+// throttleCodes are the CloudWatch Logs error codes of rate limits and
+// quotas, e.g. too many concurrent Insights queries.
+var throttleCodes = map[string]bool{
+	"ThrottlingException":      true,
+	"Throttling":               true,
+	"TooManyRequestsException": true,
+	"RequestLimitExceeded":     true,
+	"LimitExceededException":   true,
+}
+
+// isThrottled reports whether CloudWatch refused a call because of a quota,
+// with the HTTP response of the error if any.
+func isThrottled(err error) (bool, *awshttp.ResponseError) {
+	var apiErr smithy.APIError
+	throttled := errors.As(err, &apiErr) && throttleCodes[apiErr.ErrorCode()]
+	var respErr *awshttp.ResponseError
+	if !errors.As(err, &respErr) || respErr.Response == nil {
+		return throttled, nil
+	}
+	return throttled || respErr.HTTPStatusCode() == http.StatusTooManyRequests, respErr
+}
+
+// asThrottled wraps err in a client.ThrottledError when CloudWatch refused
+// the call because of a quota, with the Retry-After of the response if any.
+func asThrottled(err error) error {
+	if err == nil {
+		return nil
+	}
+	throttled, respErr := isThrottled(err)
+	if !throttled {
+		return err
+	}
+
+	result := &client.ThrottledError{Err: err}
+	if respErr != nil {
+		result.RetryAfter, _ = client.ParseRetryAfter(respErr.Response.Header.Get("Retry-After"), time.Now())
+	}
+	return result
+}
+
+// sdkRetryer is the retryer of the AWS SDK without its retries of throttled
+// calls, which callWithRetry makes instead following the Retry-After of
+// CloudWatch. Other transient errors are still retried by the SDK.
+type sdkRetryer struct {
+	aws.Retryer
+}
+
+func (r sdkRetryer) IsErrorRetryable(err error) bool {
+	if throttled, _ := isThrottled(err); throttled {
+		return false
+	}
+	return r.Retryer.IsErrorRetryable(err)
+}
+
+// GetAttemptToken implements aws.RetryerV2 for the retryers that do.
+func (r sdkRetryer) GetAttemptToken(ctx context.Context) (func(error) error, error) {
+	if v2, ok := r.Retryer.(aws.RetryerV2); ok {
+		return v2.GetAttemptToken(ctx)
+	}
+	return r.GetInitialToken(), nil
+}
+
+// callWithRetry runs a CloudWatch call, retrying it while it is throttled.
+// A zero policy means client.DefaultRetryPolicy.
+func callWithRetry(ctx context.Context, policy client.RetryPolicy, logger *slog.Logger, name string, call func() error) error {
+	if policy.MaxAttempts == 0 {
+		policy = client.DefaultRetryPolicy
+	}
+	return client.RetryThrottled(ctx, policy, func() error { return asThrottled(call()) }, func(err error, wait time.Duration) {
+		if logger != nil {
+			logger.Warn("cloudwatch: throttled, retrying", "call", name, "wait", wait, "error", err)
+		}
+	})
+}
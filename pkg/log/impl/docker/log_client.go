@@ -13,12 +13,9 @@ import (
 	"github.com/docker/cli/cli/connhelper"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
 
-	"sync"
-
 	logclient "github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/log/reader"
 )
@@ -48,62 +45,9 @@ func (lc LogClient) Get(ctx context.Context, search *logclient.LogSearch) (logcl
 	// Specify the container ID or name
 	containerID := search.Options.GetString("container")
 
-	// Check if service is provided for service discovery
+	// A compose service merges the logs of all the containers backing it
 	if service := search.Options.GetString("service"); service != "" {
-		// Use service discovery
-		filterArgs := filters.NewArgs()
-		filterArgs.Add("label", fmt.Sprintf("com.docker.compose.service=%s", service))
-
-		// Optional project filter
-		if project := search.Options.GetString("project"); project != "" {
-			filterArgs.Add("label", fmt.Sprintf("com.docker.compose.project=%s", project))
-		}
-
-		containers, err := lc.apiClient.ContainerList(ctx, container.ListOptions{
-			Filters: filterArgs,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to list containers for service %s: %w", service, err)
-		}
-
-		if len(containers) == 0 {
-			return nil, fmt.Errorf("no running containers found for service %s", service)
-		}
-
-		// Use MultiLogSearchResult to merge logs from all containers when multiple replicas exist
-		if len(containers) > 1 {
-			multiResult, err := logclient.NewMultiLogSearchResult(search)
-			if err != nil {
-				return nil, err
-			}
-
-			var wg sync.WaitGroup
-			for _, container := range containers {
-				wg.Add(1)
-				go func(cID string) {
-					defer wg.Done()
-					// Clone the search object to prevent race conditions
-					containerSearch := search.Clone()
-
-					// Configure for specific container
-					containerSearch.Options["container"] = cID
-					delete(containerSearch.Options, "service")
-					containerSearch.Options["__context_id__"] = cID[:12]
-
-					// Fetch logs
-					result, err := lc.Get(ctx, containerSearch)
-					if err != nil {
-						fmt.Fprintf(os.Stderr, "Error fetching logs for container %s: %v\n", cID[:12], err)
-					}
-					multiResult.Add(result, err)
-				}(container.ID)
-			}
-			wg.Wait()
-			return multiResult, nil
-		}
-
-		// Use the first matching container
-		containerID = containers[0].ID
+		return lc.getService(ctx, search, service)
 	}
 
 	var since, until string
@@ -0,0 +1,357 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+
+	logclient "github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// servicePollInterval is how often a followed service lists its containers
+// again, to stream the replicas started after the search.
+var servicePollInterval = 5 * time.Second
+
+// serviceResult merges the logs of every container backing a compose
+// service into one stream sorted by timestamp, tagging each entry with its
+// container in Fields["container"].
+type serviceResult struct {
+	lc         LogClient
+	search     *logclient.LogSearch
+	service    string
+	filterArgs filters.Args
+
+	mu        sync.Mutex
+	streams   []containerStream
+	streaming map[string]bool
+}
+
+// containerStream is the result of one container of the service.
+type containerStream struct {
+	container types.Container
+	result    logclient.LogSearchResult
+}
+
+var _ logclient.LogSearchResult = (*serviceResult)(nil)
+
+// getService resolves service to its running containers and returns their
+// merged result.
+func (lc LogClient) getService(ctx context.Context, search *logclient.LogSearch, service string) (logclient.LogSearchResult, error) {
+	filterArgs := filters.NewArgs()
+	filterArgs.Add("label", fmt.Sprintf("com.docker.compose.service=%s", service))
+
+	// Optional project filter
+	if project := search.Options.GetString("project"); project != "" {
+		filterArgs.Add("label", fmt.Sprintf("com.docker.compose.project=%s", project))
+	}
+
+	containers, err := lc.apiClient.ContainerList(ctx, container.ListOptions{
+		Filters: filterArgs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers for service %s: %w", service, err)
+	}
+
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no running containers found for service %s", service)
+	}
+
+	r := &serviceResult{
+		lc:         lc,
+		search:     search,
+		service:    service,
+		filterArgs: filterArgs,
+		streaming:  map[string]bool{},
+	}
+
+	// Open every container at once; the service fails only when all of them do
+	failures := logclient.MultiError{Total: len(containers)}
+	var wg sync.WaitGroup
+	for _, c := range containers {
+		wg.Add(1)
+		go func(c types.Container) {
+			defer wg.Done()
+			result, err := lc.Get(ctx, r.containerSearch(c))
+
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error fetching logs for container %s: %v\n", shortID(c.ID), err)
+				failures.Add(shortID(c.ID), err)
+				return
+			}
+			r.streams = append(r.streams, containerStream{container: c, result: result})
+		}(c)
+	}
+	wg.Wait()
+
+	if failures.AllFailed() {
+		return nil, &failures
+	}
+	return r, nil
+}
+
+// containerSearch returns the search for one container of the service.
+func (r *serviceResult) containerSearch(c types.Container) *logclient.LogSearch {
+	search := r.search.Clone()
+	search.Options["container"] = c.ID
+	delete(search.Options, "service")
+	search.Options["__context_id__"] = shortID(c.ID)
+	return search
+}
+
+// read returns the entries of s so far, tagged with its container, and the
+// channel of the following ones. A partial result error is returned with the
+// entries.
+func (r *serviceResult) read(ctx context.Context, s containerStream) ([]logclient.LogEntry, chan []logclient.LogEntry, error) {
+	entries, ch, err := s.result.GetEntries(ctx)
+	if err != nil && !logclient.IsPartial(err) {
+		return nil, nil, err
+	}
+	if ch != nil {
+		r.mu.Lock()
+		r.streaming[s.container.ID] = true
+		r.mu.Unlock()
+	}
+	tagEntries(entries, s.container)
+	return entries, ch, err
+}
+
+// GetEntries reads every container at once and merges their entries by
+// timestamp. In follow mode the containers keep streaming, and the service is
+// listed again every servicePollInterval to pick up new replicas; a container
+// that stops just ends its stream.
+func (r *serviceResult) GetEntries(ctx context.Context) ([]logclient.LogEntry, chan []logclient.LogEntry, error) {
+	r.mu.Lock()
+	streams := append([]containerStream(nil), r.streams...)
+	r.mu.Unlock()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		entries  []logclient.LogEntry
+		channels = map[string]chan []logclient.LogEntry{}
+		failures = logclient.MultiError{Total: len(streams)}
+		partial  error
+	)
+
+	for _, st := range streams {
+		wg.Add(1)
+		go func(st containerStream) {
+			defer wg.Done()
+			es, ch, err := r.read(ctx, st)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && !logclient.IsPartial(err) {
+				fmt.Fprintf(os.Stderr, "Error reading logs for container %s: %v\n", shortID(st.container.ID), err)
+				failures.Add(shortID(st.container.ID), err)
+				return
+			}
+			if err != nil {
+				partial = err
+			}
+			entries = append(entries, es...)
+			if ch != nil {
+				channels[st.container.ID] = ch
+			}
+		}(st)
+	}
+	wg.Wait()
+
+	if failures.AllFailed() {
+		return nil, nil, &failures
+	}
+
+	sortByTimestamp(entries)
+	if r.search.Size.Set && r.search.Size.Value > 0 && len(entries) > r.search.Size.Value {
+		entries = entries[len(entries)-r.search.Size.Value:]
+	}
+
+	if !r.search.Follow {
+		return entries, nil, partial
+	}
+
+	out := make(chan []logclient.LogEntry)
+	var forwarders sync.WaitGroup
+
+	forward := func(c types.Container, ch chan []logclient.LogEntry) {
+		defer forwarders.Done()
+		defer func() {
+			r.mu.Lock()
+			delete(r.streaming, c.ID)
+			r.mu.Unlock()
+		}()
+		for batch := range ch {
+			tagEntries(batch, c)
+			select {
+			case out <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	for _, st := range streams {
+		if ch, ok := channels[st.container.ID]; ok {
+			forwarders.Add(1)
+			go forward(st.container, ch)
+		}
+	}
+
+	forwarders.Add(1)
+	go func() {
+		defer forwarders.Done()
+		ticker := time.NewTicker(servicePollInterval)
+		defer ticker.Stop()
+		lastPoll := time.Now()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			containers, err := r.lc.apiClient.ContainerList(ctx, container.ListOptions{Filters: r.filterArgs})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error listing containers for service %s: %v\n", r.service, err)
+				continue
+			}
+			since := lastPoll
+			lastPoll = time.Now()
+
+			for _, c := range containers {
+				r.mu.Lock()
+				known := r.streaming[c.ID]
+				r.mu.Unlock()
+				if known {
+					continue
+				}
+
+				// Only read what the container logged since the last poll, so
+				// a restarted container does not replay its history.
+				search := r.containerSearch(c)
+				search.Range = logclient.SearchRange{}
+				search.Range.Gte.S(since.UTC().Format(time.RFC3339Nano))
+				search.Size = ty.Opt[int]{}
+
+				result, err := r.lc.Get(ctx, search)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error fetching logs for container %s: %v\n", shortID(c.ID), err)
+					continue
+				}
+				st := containerStream{container: c, result: result}
+				r.mu.Lock()
+				r.streams = append(r.streams, st)
+				r.mu.Unlock()
+
+				es, ch, err := r.read(ctx, st)
+				if err != nil && !logclient.IsPartial(err) {
+					fmt.Fprintf(os.Stderr, "Error fetching logs for container %s: %v\n", shortID(c.ID), err)
+					continue
+				}
+				if len(es) > 0 {
+					select {
+					case out <- es:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if ch != nil {
+					forwarders.Add(1)
+					go forward(c, ch)
+				}
+			}
+		}
+	}()
+
+	go func() {
+		forwarders.Wait()
+		close(out)
+	}()
+
+	return entries, out, nil
+}
+
+// GetFields merges the fields of the containers read so far.
+func (r *serviceResult) GetFields(ctx context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
+	r.mu.Lock()
+	streams := append([]containerStream(nil), r.streams...)
+	r.mu.Unlock()
+
+	fields := make(ty.UniSet[string])
+	for _, st := range streams {
+		fields.Add("container", containerName(st.container))
+		fs, _, err := st.result.GetFields(ctx)
+		if err != nil {
+			continue
+		}
+		for k, values := range fs {
+			for _, v := range values {
+				fields.Add(k, v)
+			}
+		}
+	}
+	return fields, nil, nil
+}
+
+// GetSearch returns the search of the service.
+func (r *serviceResult) GetSearch() *logclient.LogSearch {
+	return r.search
+}
+
+// GetPaginationInfo returns nil, docker logs are not paginated.
+func (r *serviceResult) GetPaginationInfo() *logclient.PaginationInfo {
+	return nil
+}
+
+// Err returns nil, stream errors are printed as they happen.
+func (r *serviceResult) Err() <-chan error {
+	return nil
+}
+
+// tagEntries sets the container of each entry, and its context id to the
+// short container id.
+func tagEntries(entries []logclient.LogEntry, c types.Container) {
+	name := containerName(c)
+	for i := range entries {
+		if entries[i].Fields == nil {
+			entries[i].Fields = ty.MI{}
+		}
+		entries[i].Fields["container"] = name
+		entries[i].ContextID = shortID(c.ID)
+	}
+}
+
+func sortByTimestamp(entries []logclient.LogEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+}
+
+// containerName returns the name of c without the leading slash, or its
+// short id when it has none.
+func containerName(c types.Container) string {
+	if len(c.Names) > 0 {
+		return strings.TrimPrefix(c.Names[0], "/")
+	}
+	return shortID(c.ID)
+}
+
+// shortID returns the 12 character form of a container id.
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
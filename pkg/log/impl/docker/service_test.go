@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"io"
 	"testing"
+	"time"
 
 	logclient "github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/ty"
@@ -256,3 +257,138 @@ func TestServiceLogs_PartialFailure(t *testing.T) {
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestServiceLogs_TagsContainer(t *testing.T) {
+	mockClient := new(MockDockerClient)
+	lc := LogClient{apiClient: mockClient, host: "local"}
+
+	ctx := context.Background()
+	search := &logclient.LogSearch{Options: ty.MI{"service": "web-app"}}
+
+	mockClient.On("ContainerList", ctx, mock.Anything).Return([]types.Container{
+		{ID: "container_id_1_long", Names: []string{"/web-app-1"}},
+		{ID: "container_id_2_long", Names: []string{"/web-app-2"}},
+	}, nil)
+	mockClient.On("ContainerLogs", ctx, "container_id_1_long", mock.Anything).
+		Return(io.NopCloser(bytes.NewReader(makeLogFrame("2024-01-01T00:00:03.000000000Z late\n"))), nil)
+	mockClient.On("ContainerLogs", ctx, "container_id_2_long", mock.Anything).
+		Return(io.NopCloser(bytes.NewReader(makeLogFrame("2024-01-01T00:00:01.000000000Z early\n"))), nil)
+
+	result, err := lc.Get(ctx, search)
+	assert.NoError(t, err)
+
+	entries, _, err := result.GetEntries(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, " early", entries[0].Message)
+		assert.Equal(t, "web-app-2", entries[0].Fields["container"])
+		assert.Equal(t, " late", entries[1].Message)
+		assert.Equal(t, "web-app-1", entries[1].Fields["container"])
+	}
+
+	fields, _, err := result.GetFields(ctx)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"web-app-1", "web-app-2"}, fields["container"])
+}
+
+func TestServiceLogs_AllContainersFail(t *testing.T) {
+	mockClient := new(MockDockerClient)
+	lc := LogClient{apiClient: mockClient, host: "local"}
+
+	ctx := context.Background()
+	search := &logclient.LogSearch{Options: ty.MI{"service": "web-app"}}
+
+	mockClient.On("ContainerList", ctx, mock.Anything).Return([]types.Container{
+		{ID: "c1", Names: []string{"/web-app-1"}},
+		{ID: "c2", Names: []string{"/web-app-2"}},
+	}, nil)
+	mockClient.On("ContainerLogs", ctx, mock.Anything, mock.Anything).Return(io.NopCloser(bytes.NewReader(nil)), assert.AnError)
+
+	_, err := lc.Get(ctx, search)
+	var me *logclient.MultiError
+	if assert.ErrorAs(t, err, &me) {
+		assert.True(t, me.AllFailed())
+	}
+}
+
+func TestServiceLogs_FollowPicksUpNewContainer(t *testing.T) {
+	defer func(d time.Duration) { servicePollInterval = d }(servicePollInterval)
+	servicePollInterval = 10 * time.Millisecond
+
+	mockClient := new(MockDockerClient)
+	lc := LogClient{apiClient: mockClient, host: "local"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	search := &logclient.LogSearch{Options: ty.MI{"service": "web-app"}, Follow: true}
+
+	c1 := types.Container{ID: "c1", Names: []string{"/web-app-1"}}
+	c2 := types.Container{ID: "c2", Names: []string{"/web-app-2"}}
+	mockClient.On("ContainerList", ctx, mock.Anything).Return([]types.Container{c1}, nil).Once()
+	mockClient.On("ContainerList", ctx, mock.Anything).Return([]types.Container{c1, c2}, nil)
+	mockClient.On("ContainerLogs", ctx, "c1", mock.Anything).
+		Return(io.NopCloser(bytes.NewReader(makeLogFrame("2024-01-01T00:00:01.000000000Z from c1\n"))), nil)
+	mockClient.On("ContainerLogs", ctx, "c2", mock.MatchedBy(func(opts container.LogsOptions) bool {
+		return opts.Since != ""
+	})).Return(io.NopCloser(bytes.NewReader(makeLogFrame("2024-01-01T00:00:02.000000000Z from c2\n"))), nil)
+
+	result, err := lc.Get(ctx, search)
+	assert.NoError(t, err)
+
+	entries, ch, err := result.GetEntries(ctx)
+	assert.NoError(t, err)
+	assert.NotNil(t, ch)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "web-app-1", entries[0].Fields["container"])
+	}
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case batch := <-ch:
+			for _, e := range batch {
+				if e.Fields["container"] == "web-app-2" {
+					assert.Equal(t, " from c2", e.Message)
+					return
+				}
+			}
+		case <-timeout:
+			t.Fatal("no entries from the new container")
+		}
+	}
+}
+
+func TestServiceLogs_TimeoutKeepsEntries(t *testing.T) {
+	mockClient := new(MockDockerClient)
+	lc := LogClient{
+		apiClient: mockClient,
+		host:      "local",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	search := &logclient.LogSearch{
+		Options: ty.MI{
+			"service": "web-app",
+		},
+	}
+
+	mockClient.On("ContainerList", ctx, mock.Anything).Return([]types.Container{
+		{ID: "container_id_1", Names: []string{"/web-app-1"}},
+	}, nil)
+
+	// The stream sends one line and then stalls past the deadline.
+	pr, pw := io.Pipe()
+	t.Cleanup(func() { _ = pw.Close() })
+	go func() {
+		_, _ = pw.Write(makeLogFrame("2024-01-01T00:00:01.000000000Z log from c1\n"))
+	}()
+	mockClient.On("ContainerLogs", ctx, "container_id_1", mock.Anything).Return(io.ReadCloser(pr), nil)
+
+	result, err := lc.Get(ctx, search)
+	assert.NoError(t, err)
+
+	entries, _, err := result.GetEntries(ctx)
+	assert.True(t, logclient.IsPartial(err), "got %v", err)
+	assert.Len(t, entries, 1)
+}
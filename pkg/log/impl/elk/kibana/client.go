@@ -4,7 +4,11 @@ package kibana
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/bascanada/logviewer/pkg/http"
 	"github.com/bascanada/logviewer/pkg/log/client"
@@ -28,13 +32,14 @@ type kibanaClient struct {
 	client HTTPClient
 }
 
-func (kc kibanaClient) Get(_ context.Context, search *client.LogSearch) (client.LogSearchResult, error) {
+func (kc kibanaClient) Get(ctx context.Context, search *client.LogSearch) (client.LogSearchResult, error) {
 	var searchResponse SearchResponse
 
 	request, err := getSearchRequest(search)
 	if err != nil {
 		return nil, err
 	}
+	request.Params.Body.Timeout = elk.SearchTimeout(ctx)
 
 	err = kc.client.PostJSON("/internal/search/es", ty.MS{
 		"kbn-version": search.Options.GetOr("version", "7.10.2").(string),
@@ -43,7 +48,13 @@ func (kc kibanaClient) Get(_ context.Context, search *client.LogSearch) (client.
 		return nil, err
 	}
 
-	return elk.NewSearchResult(&kc, search, searchResponse.RawResponse.Hits), nil
+	res := elk.NewSearchResult(&kc, search, searchResponse.RawResponse.Hits)
+	res.Backend = backendName
+	res.SearchAfter = true
+	res.TimedOut = searchResponse.RawResponse.TimedOut
+	// Offset tokens of older builds keep paging by offset
+	res.CurrentOffset = request.Params.Body.From
+	return res, nil
 }
 
 // buildKibanaCondition builds a single Kibana query condition from a filter leaf.
@@ -78,7 +89,7 @@ func buildKibanaCondition(f *client.Filter) ty.MI {
 				field: f.Value,
 			},
 		}
-	case operator.Exists:
+	case operator.Exists, operator.NotExists:
 		condition = ty.MI{
 			"exists": ty.MI{
 				"field": field,
@@ -130,8 +141,8 @@ func buildKibanaCondition(f *client.Filter) ty.MI {
 		}
 	}
 
-	// Handle negation
-	if f.Negate {
+	// Handle negation; not_exists is a negated exists
+	if f.Negate != (op == operator.NotExists) {
 		return ty.MI{
 			"bool": ty.MI{
 				"must_not": []ty.MI{condition},
@@ -209,13 +220,26 @@ func getSearchRequest(search *client.LogSearch) (SearchRequest, error) {
 		return request, errors.New("index is not provided for kibana log client")
 	}
 
-	gte, lte, err := elk.GetDateRange(search)
-	if err != nil {
-		return SearchRequest{}, err
+	nativeOnly := search.IsNativeQueryOnly()
+	skipRange := nativeOnly && elk.NativeQueryHasTimeRange(search.NativeQuery.Value)
+
+	var gte, lte string
+	if skipRange {
+		if search.Size.Value == 0 {
+			search.Size.S(100)
+		}
+	} else {
+		var err error
+		gte, lte, err = elk.GetDateRange(search)
+		if err != nil {
+			return SearchRequest{}, err
+		}
 	}
 
 	request.Params.Index = index
 	request.Params.Body.Size = search.Size.Value
+	// _id breaks the ties between entries of the same timestamp, so a page
+	// starting after the last sort values neither skips nor repeats entries
 	request.Params.Body.Sort = []ty.MI{
 		{
 			"@timestamp": ty.MI{
@@ -223,6 +247,14 @@ func getSearchRequest(search *client.LogSearch) (SearchRequest, error) {
 				"unmapped_type": "boolean",
 			},
 		},
+		{
+			"_id": ty.MI{
+				"order": "desc",
+			},
+		},
+	}
+	if err := setPageCursor(search, &request.Params.Body); err != nil {
+		return SearchRequest{}, err
 	}
 	request.Params.Body.StoredFields = []string{"*"}
 	request.Params.Body.DocValueFields = []ty.MI{
@@ -241,16 +273,29 @@ func getSearchRequest(search *client.LogSearch) (SearchRequest, error) {
 		{"match_all": ty.MI{}},
 	}
 
-	effectiveFilter := search.GetEffectiveFilter()
-	if effectiveFilter != nil {
-		filterQuery := buildKibanaQuery(effectiveFilter)
-		if filterQuery != nil {
-			conditions = append(conditions, filterQuery)
+	// Native query is passed as raw Lucene syntax
+	if search.NativeQuery.Set && search.NativeQuery.Value != "" {
+		conditions = append(conditions, ty.MI{
+			"query_string": ty.MI{
+				"query": search.NativeQuery.Value,
+			},
+		})
+	}
+
+	if !nativeOnly {
+		effectiveFilter := search.GetEffectiveFilter()
+		if effectiveFilter != nil {
+			filterQuery := buildKibanaQuery(effectiveFilter)
+			if filterQuery != nil {
+				conditions = append(conditions, filterQuery)
+			}
 		}
 	}
 
 	// Add timestamp range
-	conditions = append(conditions, elk.GetDateRangeConditon(gte, lte))
+	if !skipRange {
+		conditions = append(conditions, elk.GetDateRangeConditon(gte, lte))
+	}
 
 	request.Params.Body.Query = ty.MI{
 		"bool": ty.MI{
@@ -261,6 +306,31 @@ func getSearchRequest(search *client.LogSearch) (SearchRequest, error) {
 	return request, nil
 }
 
+// backendName is set on the results of the proxied searches, so their page
+// tokens are refused by the other elk clients.
+const backendName = "kibana"
+
+// setPageCursor starts body after the page token of search: the sort values
+// of the last hit of the previous page, or the offset of older builds.
+func setPageCursor(search *client.LogSearch, body *Body) error {
+	cursor, err := client.PageCursor(search, backendName)
+	if err != nil || cursor == "" {
+		return err
+	}
+	if strings.HasPrefix(cursor, "[") {
+		if err := json.Unmarshal([]byte(cursor), &body.SearchAfter); err != nil {
+			return fmt.Errorf("invalid page token: %w", err)
+		}
+		return nil
+	}
+	offset, err := strconv.Atoi(cursor)
+	if err != nil {
+		return fmt.Errorf("invalid page token: %w", err)
+	}
+	body.From = offset
+	return nil
+}
+
 func (kc kibanaClient) GetFieldValues(ctx context.Context, search *client.LogSearch, fields []string) (map[string][]string, error) {
 	// For kibana, we need to run a search and extract field values from the results
 	result, err := kc.Get(ctx, search)
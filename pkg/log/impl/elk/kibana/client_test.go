@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/bascanada/logviewer/pkg/http"
 	"github.com/bascanada/logviewer/pkg/log/client"
@@ -142,3 +143,138 @@ func TestBuildKibanaQuery(t *testing.T) {
 	assert.Contains(t, string(b), "level")
 	assert.Contains(t, string(b), "ERROR")
 }
+
+func TestGetSearchRequest_NativeQueryOnly(t *testing.T) {
+	search := &client.LogSearch{
+		NativeQuery:     ty.OptWrap(`status:500 AND host:web*`),
+		NativeQueryOnly: true,
+		Fields:          ty.MS{"level": "ERROR"},
+		Options:         ty.MI{"index": "log-index"},
+		Range:           client.SearchRange{Last: ty.OptWrap("15m")},
+	}
+
+	request, err := getSearchRequest(search)
+	assert.NoError(t, err)
+
+	b, _ := json.Marshal(request.Params.Body.Query)
+	assert.Contains(t, string(b), "query_string")
+	assert.Contains(t, string(b), "status:500 AND host:web*")
+	assert.Contains(t, string(b), "@timestamp")
+	assert.NotContains(t, string(b), "ERROR")
+}
+
+func TestBuildKibanaCondition_NotExists(t *testing.T) {
+	q := buildKibanaCondition(&client.Filter{Field: "trace_id", Op: operator.NotExists})
+	assert.Equal(t, ty.MI{"bool": ty.MI{"must_not": []ty.MI{{"exists": ty.MI{"field": "trace_id"}}}}}, q)
+
+	// Negating not_exists yields a plain exists
+	q = buildKibanaCondition(&client.Filter{Field: "trace_id", Op: operator.NotExists, Negate: true})
+	assert.Equal(t, ty.MI{"exists": ty.MI{"field": "trace_id"}}, q)
+}
+
+func TestKibanaClient_SearchAfterPagination(t *testing.T) {
+	// Documents as Elasticsearch sorts them, newest first; b and c share a
+	// timestamp and are ordered by _id
+	docs := []elk.Hit{
+		{ID: "e", Source: ty.MI{"message": "e", "@timestamp": "2024-05-01T10:00:04Z"}, Sort: []json.RawMessage{json.RawMessage("1714557604000"), json.RawMessage(`"e"`)}},
+		{ID: "d", Source: ty.MI{"message": "d", "@timestamp": "2024-05-01T10:00:03Z"}, Sort: []json.RawMessage{json.RawMessage("1714557603000"), json.RawMessage(`"d"`)}},
+		{ID: "c", Source: ty.MI{"message": "c", "@timestamp": "2024-05-01T10:00:02Z"}, Sort: []json.RawMessage{json.RawMessage("1714557602000"), json.RawMessage(`"c"`)}},
+		{ID: "b", Source: ty.MI{"message": "b", "@timestamp": "2024-05-01T10:00:02Z"}, Sort: []json.RawMessage{json.RawMessage("1714557602000"), json.RawMessage(`"b"`)}},
+		{ID: "a", Source: ty.MI{"message": "a", "@timestamp": "2024-05-01T10:00:01Z"}, Sort: []json.RawMessage{json.RawMessage("1714557601000"), json.RawMessage(`"a"`)}},
+	}
+	var requests []SearchRequest
+	mockHTTP := &MockHTTPClient{
+		OnPostJSON: func(_ string, _ ty.MS, body interface{}, responseData interface{}, _ http.Auth) error {
+			request := *body.(*SearchRequest)
+			requests = append(requests, request)
+			start := 0
+			if after := request.Params.Body.SearchAfter; after != nil {
+				// The documents after the one with the cursor sort values
+				for i, d := range docs {
+					if string(d.Sort[0]) == string(after[0]) && string(d.Sort[1]) == string(after[1]) {
+						start = i + 1
+					}
+				}
+			}
+			end := min(start+request.Params.Body.Size, len(docs))
+			responseData.(*SearchResponse).RawResponse.Hits = elk.Hits{Hits: docs[start:end]}
+			return nil
+		},
+	}
+	kc := kibanaClient{client: mockHTTP}
+
+	page := func(token string) ([]string, string) {
+		t.Helper()
+		search := &client.LogSearch{Options: ty.MI{"index": "logs"}, Size: ty.OptWrap(3)}
+		search.Range.Gte.S("2024-05-01T00:00:00Z")
+		search.Range.Lte.S("2024-05-02T00:00:00Z")
+		if token != "" {
+			search.PageToken.S(token)
+		}
+		result, err := kc.Get(context.Background(), search)
+		assert.NoError(t, err)
+		entries, _, err := result.GetEntries(context.Background())
+		assert.NoError(t, err)
+		var messages []string
+		for _, e := range entries {
+			messages = append(messages, e.Message)
+		}
+		next := ""
+		if info := result.GetPaginationInfo(); info != nil {
+			next = info.NextPageToken
+		}
+		return messages, next
+	}
+
+	first, token := page("")
+	assert.Equal(t, []string{"c", "d", "e"}, first, "entries are returned oldest first")
+	assert.NotEmpty(t, token)
+	assert.Equal(t, []ty.MI{
+		{"@timestamp": ty.MI{"order": "desc", "unmapped_type": "boolean"}},
+		{"_id": ty.MI{"order": "desc"}},
+	}, requests[0].Params.Body.Sort)
+
+	second, token := page(token)
+	assert.Equal(t, []string{"a", "b"}, second, "the page starts after c, sharing its timestamp with b")
+	assert.Empty(t, token, "the last page has no token")
+	assert.Equal(t, []json.RawMessage{json.RawMessage("1714557602000"), json.RawMessage(`"c"`)}, requests[1].Params.Body.SearchAfter)
+	assert.Zero(t, requests[1].Params.Body.From)
+
+	// Offsets of older builds still page by offset
+	_, _ = page("3")
+	assert.Equal(t, 3, requests[2].Params.Body.From)
+	assert.Nil(t, requests[2].Params.Body.SearchAfter)
+
+	search := &client.LogSearch{Options: ty.MI{"index": "logs"}, PageToken: ty.OptWrap(client.EncodePageToken(backendName, "[oops"))}
+	search.Range.Last.S("15m")
+	_, err := kc.Get(context.Background(), search)
+	assert.ErrorContains(t, err, "invalid page token")
+}
+
+func TestKibanaClient_Get_TimedOut(t *testing.T) {
+	var timeout string
+	mockHTTP := &MockHTTPClient{
+		OnPostJSON: func(_ string, _ ty.MS, body interface{}, responseData interface{}, _ http.Auth) error {
+			timeout = body.(*SearchRequest).Params.Body.Timeout
+			resp := responseData.(*SearchResponse)
+			resp.RawResponse.TimedOut = true
+			resp.RawResponse.Hits = elk.Hits{Hits: []elk.Hit{
+				{Source: ty.MI{"message": "test log", "@timestamp": "2023-01-01T12:00:00Z"}},
+			}}
+			return nil
+		},
+	}
+	kc := kibanaClient{target: Target{Endpoint: "http://kibana:5601"}, client: mockHTTP}
+	search := &client.LogSearch{Options: ty.MI{"index": "log-index"}, Range: client.SearchRange{Last: ty.OptWrap("15m")}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	result, err := kc.Get(ctx, search)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, timeout)
+
+	entries, _, err := result.GetEntries(ctx)
+	assert.Len(t, entries, 1)
+	assert.True(t, client.IsPartial(err))
+	assert.ErrorIs(t, err, elk.ErrSearchTimedOut)
+}
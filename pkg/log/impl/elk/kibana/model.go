@@ -1,6 +1,8 @@
 package kibana
 
 import (
+	"encoding/json"
+
 	"github.com/bascanada/logviewer/pkg/log/impl/elk"
 	"github.com/bascanada/logviewer/pkg/ty"
 )
@@ -14,6 +16,14 @@ type Body struct {
 	DocValueFields []ty.MI  `json:"docvalue_fields,omitempty"`
 	Source         ty.MI    `json:"_source,omitempty"`
 	Query          ty.MI    `json:"query"`
+
+	// SearchAfter holds the sort values of the last hit of the previous page,
+	// From the offset of the page for the tokens of older builds
+	SearchAfter []json.RawMessage `json:"search_after,omitempty"`
+	From        int               `json:"from,omitempty"`
+
+	// Timeout makes the cluster return the hits found so far once it elapses
+	Timeout string `json:"timeout,omitempty"`
 }
 
 // Params represents the parameters of a Kibana/Elasticsearch search request.
@@ -29,7 +39,8 @@ type SearchRequest struct {
 
 // Response represents the response from a Kibana/Elasticsearch search request.
 type Response struct {
-	Hits elk.Hits `json:"hits"`
+	TimedOut bool     `json:"timed_out"`
+	Hits     elk.Hits `json:"hits"`
 }
 
 // SearchResponse represents the full Kibana/Elasticsearch search response.
@@ -14,6 +14,10 @@ import (
 	"github.com/bascanada/logviewer/pkg/ty"
 )
 
+// valuesSizeOption caps the distinct values GetFieldValues returns per
+// field, overriding the search size.
+const valuesSizeOption = "valuesSize"
+
 // Target describes the connection target for an OpenSearch-backed client.
 type Target struct {
 	Endpoint string `json:"endpoint"`
@@ -24,7 +28,7 @@ type openSearchClient struct {
 	client http.Client
 }
 
-func (kc openSearchClient) Get(_ context.Context, search *client.LogSearch) (client.LogSearchResult, error) {
+func (kc openSearchClient) Get(ctx context.Context, search *client.LogSearch) (client.LogSearchResult, error) {
 	var searchResult SearchResult
 
 	index := search.Options.GetString("index")
@@ -37,24 +41,22 @@ func (kc openSearchClient) Get(_ context.Context, search *client.LogSearch) (cli
 	if err != nil {
 		return nil, err
 	}
+	request.Timeout = elk.SearchTimeout(ctx)
 
 	err = kc.client.Get(fmt.Sprintf("/%s/_search", index), ty.MS{}, ty.MS{}, &request, &searchResult, nil)
 	if err != nil {
-		return nil, err
+		return nil, wrapRuntimeMappingError(err, request.RuntimeMappings != nil)
 	}
 
 	res := elk.NewSearchResult(&kc, search, searchResult.Hits)
+	res.Backend = backendName
+	res.TimedOut = searchResult.TimedOut
 
-	// If a page token was provided we already validated and parsed it in
-	// GetSearchRequest; reuse that value for pagination calculation.
-	if search.PageToken.Set && search.PageToken.Value != "" {
-		if parsedOffset, err := strconv.Atoi(search.PageToken.Value); err == nil {
-			res.CurrentOffset = parsedOffset
-		} else {
-			// Shouldn't happen because GetSearchRequest already validated the token,
-			// but guard defensively.
-			return nil, fmt.Errorf("invalid page token: %w", err)
-		}
+	// The page token was already validated by GetSearchRequest; parse it
+	// again for the pagination of the result
+	res.CurrentOffset, err = pageOffset(search)
+	if err != nil {
+		return nil, err
 	}
 
 	return res, nil
@@ -72,71 +74,162 @@ func (kc openSearchClient) GetFieldValues(ctx context.Context, search *client.Lo
 		return kc.getFieldValuesFromSearch(ctx, search)
 	}
 
-	// Build base query
-	gte, lte, err := elk.GetDateRange(search)
+	// Build base query (native query, filters and time range)
+	filterConditions, err := buildQueryConditions(search)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build conditions from the effective filter
-	var filterConditions []ty.MI
+	query := ty.MI{
+		"bool": ty.MI{
+			"must": filterConditions,
+		},
+	}
+
+	maxValues := fieldValuesSize(search)
 
-	// Add Native Query if provided
-	if search.NativeQuery.Set && search.NativeQuery.Value != "" {
-		filterConditions = append(filterConditions, ty.MI{
-			"query_string": ty.MI{
-				"query": search.NativeQuery.Value,
-			},
-		})
+	runtimeMappings, _, err := getRuntimeMappings(search)
+	if err != nil {
+		return nil, err
 	}
 
-	// Add effective filter conditions
-	effectiveFilter := search.GetEffectiveFilter()
-	if effectiveFilter != nil {
-		filterQuery := buildOpenSearchQuery(effectiveFilter)
-		if filterQuery != nil {
-			filterConditions = append(filterConditions, ty.MI(filterQuery))
+	// Use .keyword suffix for text fields to enable aggregation
+	// This is required in OpenSearch/Elasticsearch for analyzed text fields.
+	// Runtime fields are not analyzed and have no .keyword sub-field.
+	aggFields := make(map[string]string, len(fields))
+	for _, field := range fields {
+		aggFields[field] = field
+		if _, isRuntime := runtimeMappings[field]; !isRuntime && !strings.HasSuffix(field, ".keyword") {
+			aggFields[field] = field + ".keyword"
 		}
 	}
+	result, err := kc.termsAggregation(index, query, runtimeMappings, aggFields, maxValues)
+	if err != nil {
+		return nil, wrapRuntimeMappingError(err, runtimeMappings != nil)
+	}
 
-	// Add timestamp range condition
-	timestampCondition := ty.MI{
-		"range": ty.MI{
-			"@timestamp": ty.MI{
-				"format": "strict_date_optional_time",
-				"gte":    gte,
-				"lte":    lte,
-			},
-		},
+	// Keyword and numeric fields have no .keyword sub-field: aggregate them
+	// as they are, one at a time since a text field fails the whole request
+	for _, field := range fields {
+		if len(result[field]) > 0 || aggFields[field] == field {
+			continue
+		}
+		values, err := kc.termsAggregation(index, query, runtimeMappings, map[string]string{field: field}, maxValues)
+		if err != nil {
+			if isNotAggregatable(err) {
+				continue
+			}
+			return nil, wrapRuntimeMappingError(err, runtimeMappings != nil)
+		}
+		result[field] = values[field]
 	}
-	filterConditions = append(filterConditions, timestampCondition)
 
-	query := ty.MI{
-		"bool": ty.MI{
-			"must": filterConditions,
-		},
+	// Fields that still have no buckets, like text fields without a keyword
+	// sub-field, are read from the documents of a regular search
+	var scan []string
+	for _, field := range fields {
+		if len(result[field]) == 0 {
+			scan = append(scan, field)
+		}
+	}
+	if len(scan) > 0 {
+		searchResult, err := kc.Get(ctx, search)
+		if err != nil {
+			return nil, err
+		}
+		values, err := client.GetFieldValuesFromResult(ctx, searchResult, scan)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range scan {
+			result[field] = values[field]
+			if result[field] == nil {
+				result[field] = []string{}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Count returns the number of documents matching search with the _count API.
+// Runtime fields are not supported by _count, so searches filtering on them
+// read the total of a _search without hits instead.
+func (kc openSearchClient) Count(_ context.Context, search *client.LogSearch) (int, error) {
+	index := search.Options.GetString("index")
+
+	if index == "" {
+		return 0, errors.New("index is not provided for opensearch log client")
+	}
+
+	request, err := GetSearchRequest(search)
+	if err != nil {
+		return 0, err
 	}
 
-	// Determine the max number of distinct values to return per field
-	// Use search.Size if specified, otherwise default to 100
-	maxValues := 100
+	if request.RuntimeMappings == nil {
+		var response struct {
+			Count int `json:"count"`
+		}
+		body := ty.MI{"query": request.Query}
+		if err := kc.client.Get(fmt.Sprintf("/%s/_count", index), ty.MS{}, ty.MS{}, &body, &response, nil); err != nil {
+			return 0, err
+		}
+		return response.Count, nil
+	}
+
+	var response struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+		} `json:"hits"`
+	}
+	body := ty.MI{
+		"query":            request.Query,
+		"size":             0,
+		"track_total_hits": true,
+		"runtime_mappings": request.RuntimeMappings,
+	}
+	if err := kc.client.Get(fmt.Sprintf("/%s/_search", index), ty.MS{}, ty.MS{}, &body, &response, nil); err != nil {
+		return 0, wrapRuntimeMappingError(err, true)
+	}
+	return response.Hits.Total.Value, nil
+}
+
+// fieldValuesSize returns the number of distinct values to return per field:
+// the valuesSize option, else search.Size, else 100.
+func fieldValuesSize(search *client.LogSearch) int {
+	switch v := search.Options[valuesSizeOption].(type) {
+	case int:
+		if v > 0 {
+			return v
+		}
+	case float64:
+		if v > 0 {
+			return int(v)
+		}
+	case string:
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
 	if search.Size.Set && search.Size.Value > 0 {
-		maxValues = search.Size.Value
+		return search.Size.Value
 	}
+	return 100
+}
 
-	// Build aggregations for each field
+// termsAggregation returns the distinct values of each field of aggFields,
+// mapped to the field it is aggregated on, with a terms aggregation of at
+// most size buckets over the documents query matches.
+func (kc openSearchClient) termsAggregation(index string, query ty.MI, runtimeMappings Map, aggFields map[string]string, size int) (map[string][]string, error) {
 	aggs := ty.MI{}
-	for _, field := range fields {
-		// Use .keyword suffix for text fields to enable aggregation
-		// This is required in OpenSearch/Elasticsearch for analyzed text fields
-		fieldName := field
-		if !strings.HasSuffix(field, ".keyword") {
-			fieldName = field + ".keyword"
-		}
+	for field, aggField := range aggFields {
 		aggs[field+"_values"] = ty.MI{
 			"terms": ty.MI{
-				"field": fieldName,
-				"size":  maxValues,
+				"field": aggField,
+				"size":  size,
 			},
 		}
 	}
@@ -147,6 +240,9 @@ func (kc openSearchClient) GetFieldValues(ctx context.Context, search *client.Lo
 		"size":  0,
 		"aggs":  aggs,
 	}
+	if runtimeMappings != nil {
+		request["runtime_mappings"] = runtimeMappings
+	}
 
 	var response struct {
 		Aggregations map[string]struct {
@@ -157,29 +253,28 @@ func (kc openSearchClient) GetFieldValues(ctx context.Context, search *client.Lo
 		} `json:"aggregations"`
 	}
 
-	err = kc.client.Get(fmt.Sprintf("/%s/_search", index), ty.MS{}, ty.MS{}, &request, &response, nil)
-	if err != nil {
+	if err := kc.client.Get(fmt.Sprintf("/%s/_search", index), ty.MS{}, ty.MS{}, &request, &response, nil); err != nil {
 		return nil, err
 	}
 
 	// Extract values from aggregations
-	result := make(map[string][]string)
-	for _, field := range fields {
-		aggKey := field + "_values"
-		if agg, ok := response.Aggregations[aggKey]; ok {
-			var values []string
-			for _, bucket := range agg.Buckets {
-				values = append(values, fmt.Sprintf("%v", bucket.Key))
-			}
-			result[field] = values
-		} else {
-			result[field] = []string{}
+	result := make(map[string][]string, len(aggFields))
+	for field := range aggFields {
+		values := []string{}
+		for _, bucket := range response.Aggregations[field+"_values"].Buckets {
+			values = append(values, fmt.Sprintf("%v", bucket.Key))
 		}
+		result[field] = values
 	}
-
 	return result, nil
 }
 
+// isNotAggregatable reports whether the cluster refused a terms aggregation
+// because a field is analyzed text without fielddata.
+func isNotAggregatable(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "fielddata")
+}
+
 // getFieldValuesFromSearch falls back to getting field values from a regular search
 func (kc openSearchClient) getFieldValuesFromSearch(ctx context.Context, search *client.LogSearch) (map[string][]string, error) {
 	searchResult, err := kc.Get(ctx, search)
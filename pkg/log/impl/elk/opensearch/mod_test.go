@@ -0,0 +1,181 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	httpPkg "github.com/bascanada/logviewer/pkg/http"
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/impl/elk"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCluster answers terms aggregations like a cluster where level is a text
+// field with a keyword sub-field, status is numeric and message is text only.
+type fakeCluster struct {
+	mu       sync.Mutex
+	requests []ty.MI
+}
+
+func (f *fakeCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body ty.MI
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	f.mu.Lock()
+	f.requests = append(f.requests, body)
+	f.mu.Unlock()
+
+	aggs, ok := body["aggs"].(map[string]interface{})
+	if !ok {
+		_, _ = w.Write([]byte(`{"hits":{"hits":[{"_source":{"message":"boom","@timestamp":"2024-01-02T03:04:05Z"}},{"_source":{"message":"ok","@timestamp":"2024-01-02T03:04:04Z"}}]}}`))
+		return
+	}
+	buckets := map[string][]interface{}{
+		"level.keyword": {"ERROR", "INFO"},
+		"status":        {200, 500},
+	}
+	result := ty.MI{}
+	for name, agg := range aggs {
+		field := agg.(map[string]interface{})["terms"].(map[string]interface{})["field"].(string)
+		if field == "message" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"type":"illegal_argument_exception","reason":"Text fields are not optimised for operations that require per-document field data like aggregations and sorting, so these operations are disabled by default. Please use a keyword field instead. Alternatively, set fielddata=true on [message]"}}`))
+			return
+		}
+		var list []ty.MI
+		for _, key := range buckets[field] {
+			list = append(list, ty.MI{"key": key, "doc_count": 1})
+		}
+		result[name] = ty.MI{"buckets": list}
+	}
+	_ = json.NewEncoder(w).Encode(ty.MI{"aggregations": result})
+}
+
+func TestGetFieldValues_Aggregation(t *testing.T) {
+	cluster := &fakeCluster{}
+	server := httptest.NewServer(cluster)
+	defer server.Close()
+	kc := openSearchClient{client: httpPkg.GetClient(server.URL, nil)}
+
+	search := &client.LogSearch{Options: ty.MI{"index": "logs", valuesSizeOption: 5}}
+	search.Range.Last.S("15m")
+	search.Fields = ty.MS{"app": "api"}
+
+	values, err := kc.GetFieldValues(context.Background(), search, []string{"level", "status", "message"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ERROR", "INFO"}, values["level"])
+	assert.Equal(t, []string{"200", "500"}, values["status"], "numeric fields are aggregated without .keyword")
+	assert.ElementsMatch(t, []string{"boom", "ok"}, values["message"], "text fields fall back to the documents")
+
+	// The aggregations use the time range, the filters and the bucket cap
+	first, err := json.Marshal(cluster.requests[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(first), `"range"`)
+	assert.Contains(t, string(first), `"app"`)
+	assert.Contains(t, string(first), `"size":5`)
+	assert.EqualValues(t, 0, cluster.requests[0]["size"], "no hits for aggregations")
+	assert.Len(t, cluster.requests, 4, "keyword aggregation, status and message retries, then the scan")
+}
+
+func TestFieldValuesSize(t *testing.T) {
+	search := &client.LogSearch{}
+	assert.Equal(t, 100, fieldValuesSize(search))
+	search.Size.S(20)
+	assert.Equal(t, 20, fieldValuesSize(search))
+	for _, v := range []interface{}{50, 50.0, "50"} {
+		search.Options = ty.MI{valuesSizeOption: v}
+		assert.Equal(t, 50, fieldValuesSize(search), "%T option wins over the size", v)
+	}
+	search.Options = ty.MI{valuesSizeOption: "many"}
+	assert.Equal(t, 20, fieldValuesSize(search))
+}
+
+func TestIsNotAggregatable(t *testing.T) {
+	assert.True(t, isNotAggregatable(errors.New("request failed with status code 400: ... set fielddata=true on [message]")))
+	assert.False(t, isNotAggregatable(errors.New("request failed with status code 500")))
+}
+
+func TestCount(t *testing.T) {
+	var paths []string
+	var bodies []ty.MI
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body ty.MI
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, body)
+		if r.URL.Path == "/logs/_count" {
+			_, _ = w.Write([]byte(`{"count":4200}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"hits":{"total":{"value":17},"hits":[]}}`))
+	}))
+	defer server.Close()
+	kc := openSearchClient{client: httpPkg.GetClient(server.URL, nil)}
+
+	search := &client.LogSearch{Options: ty.MI{"index": "logs"}}
+	search.Range.Last.S("1h")
+	search.Fields = ty.MS{"level": "ERROR"}
+
+	count, err := kc.Count(context.Background(), search)
+	require.NoError(t, err)
+	assert.Equal(t, 4200, count)
+	assert.Equal(t, []string{"/logs/_count"}, paths)
+	assert.Contains(t, bodies[0], "query")
+	assert.NotContains(t, bodies[0], "size", "_count takes only the query")
+
+	t.Run("reads the total of a search with runtime fields", func(t *testing.T) {
+		paths, bodies = nil, nil
+		runtime := &client.LogSearch{Options: ty.MI{
+			"index":               "logs",
+			runtimeMappingsOption: ty.MI{"user": ty.MI{"type": "keyword", "script": "emit(params._source.u)"}},
+		}}
+		runtime.Range.Last.S("1h")
+
+		count, err := kc.Count(context.Background(), runtime)
+		require.NoError(t, err)
+		assert.Equal(t, 17, count)
+		assert.Equal(t, []string{"/logs/_search"}, paths)
+		assert.EqualValues(t, 0, bodies[0]["size"])
+		assert.Equal(t, true, bodies[0]["track_total_hits"])
+	})
+}
+
+func TestGet_TimedOut(t *testing.T) {
+	var body ty.MI
+	hits := `[{"_source":{"message":"boom","@timestamp":"2024-01-02T03:04:05Z"}}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"timed_out":true,"hits":{"hits":` + hits + `}}`))
+	}))
+	defer server.Close()
+	kc := openSearchClient{client: httpPkg.GetClient(server.URL, nil)}
+	get := func(ctx context.Context) ([]client.LogEntry, error) {
+		search := &client.LogSearch{Options: ty.MI{"index": "logs"}}
+		search.Range.Last.S("1h")
+		result, err := kc.Get(ctx, search)
+		require.NoError(t, err)
+		entries, _, err := result.GetEntries(ctx)
+		return entries, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	entries, err := get(ctx)
+	assert.Len(t, entries, 1, "the hits found before the timeout are kept")
+	assert.True(t, client.IsPartial(err))
+	assert.Regexp(t, `^\d+ms$`, body["timeout"], "the cluster answers before the deadline")
+
+	hits = `[]`
+	_, err = get(context.Background())
+	assert.ErrorIs(t, err, elk.ErrSearchTimedOut)
+	assert.False(t, client.IsPartial(err), "no hit is a failure")
+	assert.NotContains(t, body, "timeout", "no deadline, no timeout")
+}
@@ -11,8 +11,8 @@ import (
 
 // SearchResult represents the result of an OpenSearch query.
 type SearchResult struct {
-	Took int `json:"took"`
-	// timeout_out
+	Took     int  `json:"took"`
+	TimedOut bool `json:"timed_out"`
 	// _shards
 	Hits elk.Hits `json:"hits"`
 }
@@ -29,6 +29,14 @@ type SearchRequest struct {
 	Size  int        `json:"size"`
 	From  int        `json:"from,omitempty"`
 	Sort  []SortItem `json:"sort"`
+
+	// RuntimeMappings defines fields computed at query time. Their values are
+	// not part of _source, so they are requested explicitly through Fields.
+	RuntimeMappings Map      `json:"runtime_mappings,omitempty"`
+	Fields          []string `json:"fields,omitempty"`
+
+	// Timeout makes the cluster return the hits found so far once it elapses
+	Timeout string `json:"timeout,omitempty"`
 }
 
 // buildOpenSearchCondition builds a single OpenSearch query condition from a filter leaf.
@@ -63,7 +71,7 @@ func buildOpenSearchCondition(f *client.Filter) Map {
 				field: f.Value,
 			},
 		}
-	case operator.Exists:
+	case operator.Exists, operator.NotExists:
 		condition = Map{
 			"exists": Map{
 				"field": field,
@@ -117,8 +125,8 @@ func buildOpenSearchCondition(f *client.Filter) Map {
 		}
 	}
 
-	// Handle negation
-	if f.Negate {
+	// Handle negation; not_exists is a negated exists
+	if f.Negate != (op == operator.NotExists) {
 		return Map{
 			"bool": Map{
 				"must_not": []Map{condition},
@@ -187,11 +195,25 @@ func buildOpenSearchQuery(f *client.Filter) Map {
 	return nil
 }
 
-// GetSearchRequest builds an OpenSearch query request from the given LogSearch parameters.
-func GetSearchRequest(logSearch *client.LogSearch) (SearchRequest, error) {
-	gte, lte, err := elk.GetDateRange(logSearch)
-	if err != nil {
-		return SearchRequest{}, err
+// buildQueryConditions returns the bool.must clauses for a search: the native
+// query, the effective filter and the @timestamp range. In native-only mode the
+// filter is skipped, and so is the range when the native query already
+// constrains @timestamp.
+func buildQueryConditions(logSearch *client.LogSearch) ([]Map, error) {
+	nativeOnly := logSearch.IsNativeQueryOnly()
+	skipRange := nativeOnly && elk.NativeQueryHasTimeRange(logSearch.NativeQuery.Value)
+
+	var gte, lte string
+	if skipRange {
+		if logSearch.Size.Value == 0 {
+			logSearch.Size.S(100)
+		}
+	} else {
+		var err error
+		gte, lte, err = elk.GetDateRange(logSearch)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Build conditions from the effective filter
@@ -207,25 +229,47 @@ func GetSearchRequest(logSearch *client.LogSearch) (SearchRequest, error) {
 	}
 
 	// 2. Add effective filter conditions
-	effectiveFilter := logSearch.GetEffectiveFilter()
-	if effectiveFilter != nil {
-		filterQuery := buildOpenSearchQuery(effectiveFilter)
-		if filterQuery != nil {
-			filterConditions = append(filterConditions, filterQuery)
+	if !nativeOnly {
+		effectiveFilter := logSearch.GetEffectiveFilter()
+		if effectiveFilter != nil {
+			filterQuery := buildOpenSearchQuery(effectiveFilter)
+			if filterQuery != nil {
+				filterConditions = append(filterConditions, filterQuery)
+			}
 		}
 	}
 
-	// Add timestamp range condition
-	timestampCondition := Map{
-		"range": Map{
-			"@timestamp": Map{
-				"format": "strict_date_optional_time",
-				"gte":    gte,
-				"lte":    lte,
-			},
-		},
+	// 3. Add timestamp range condition
+	if !skipRange {
+		filterConditions = append(filterConditions, Map(elk.GetDateRangeConditon(gte, lte)))
+	}
+
+	return filterConditions, nil
+}
+
+// backendName tags the page tokens of opensearch results.
+const backendName = "opensearch"
+
+// pageOffset returns the offset the page token of logSearch points to, 0
+// without a token.
+func pageOffset(logSearch *client.LogSearch) (int, error) {
+	cursor, err := client.PageCursor(logSearch, backendName)
+	if err != nil || cursor == "" {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid page token: %w", err)
+	}
+	return offset, nil
+}
+
+// GetSearchRequest builds an OpenSearch query request from the given LogSearch parameters.
+func GetSearchRequest(logSearch *client.LogSearch) (SearchRequest, error) {
+	filterConditions, err := buildQueryConditions(logSearch)
+	if err != nil {
+		return SearchRequest{}, err
 	}
-	filterConditions = append(filterConditions, timestampCondition)
 
 	query := Map{
 		"bool": Map{
@@ -240,19 +284,22 @@ func GetSearchRequest(logSearch *client.LogSearch) (SearchRequest, error) {
 		},
 	}
 
-	from := 0
-	if logSearch.PageToken.Set && logSearch.PageToken.Value != "" {
-		parsedOffset, err := strconv.Atoi(logSearch.PageToken.Value)
-		if err != nil {
-			return SearchRequest{}, fmt.Errorf("invalid page token: %w", err)
-		}
-		from = parsedOffset
+	from, err := pageOffset(logSearch)
+	if err != nil {
+		return SearchRequest{}, err
+	}
+
+	runtimeMappings, runtimeFields, err := getRuntimeMappings(logSearch)
+	if err != nil {
+		return SearchRequest{}, err
 	}
 
 	return SearchRequest{
-		Query: query,
-		Sort:  []SortItem{sortItem},
-		Size:  logSearch.Size.Value,
-		From:  from,
+		Query:           query,
+		Sort:            []SortItem{sortItem},
+		Size:            logSearch.Size.Value,
+		From:            from,
+		RuntimeMappings: runtimeMappings,
+		Fields:          runtimeFields,
 	}, nil
 }
@@ -3,10 +3,12 @@ package opensearch
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
 	"github.com/bascanada/logviewer/pkg/ty"
 )
 
@@ -496,6 +498,19 @@ func TestGetSearchRequest_HLCompatibleOperators(t *testing.T) {
 	})
 }
 
+func TestBuildOpenSearchCondition_NotExists(t *testing.T) {
+	q := buildOpenSearchCondition(&client.Filter{Field: "trace_id", Op: operator.NotExists})
+	if !reflect.DeepEqual(q, Map{"bool": Map{"must_not": []Map{{"exists": Map{"field": "trace_id"}}}}}) {
+		t.Errorf("expected must_not exists, got: %v", q)
+	}
+
+	// Negating not_exists yields a plain exists
+	q = buildOpenSearchCondition(&client.Filter{Field: "trace_id", Op: operator.NotExists, Negate: true})
+	if !reflect.DeepEqual(q, Map{"exists": Map{"field": "trace_id"}}) {
+		t.Errorf("expected exists, got: %v", q)
+	}
+}
+
 func TestGetSearchRequest_NativeQuery(t *testing.T) {
 	t.Run("native query standalone", func(t *testing.T) {
 		logSearch := &client.LogSearch{
@@ -580,3 +595,129 @@ func TestGetSearchRequest_NativeQuery(t *testing.T) {
 		}
 	})
 }
+
+func TestGetSearchRequest_NativeQueryOnly(t *testing.T) {
+	t.Run("filters are ignored but time range applied", func(t *testing.T) {
+		logSearch := &client.LogSearch{
+			NativeQuery:     ty.OptWrap(`message:timeout`),
+			NativeQueryOnly: true,
+			Fields:          ty.MS{"level": "ERROR"},
+			Range:           client.SearchRange{Last: ty.OptWrap("30m")},
+		}
+
+		request, err := GetSearchRequest(logSearch)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		must := request.Query["bool"].(Map)["must"].([]Map)
+		if len(must) != 2 {
+			t.Fatalf("expected native query and range only, got %d clauses: %v", len(must), must)
+		}
+		if _, ok := must[0]["query_string"]; !ok {
+			t.Errorf("expected first clause to be query_string, got: %v", must[0])
+		}
+		if _, ok := must[1]["range"]; !ok {
+			t.Errorf("expected second clause to be the time range, got: %v", must[1])
+		}
+	})
+
+	t.Run("native time range takes precedence", func(t *testing.T) {
+		logSearch := &client.LogSearch{
+			NativeQuery:     ty.OptWrap(`@timestamp:[now-7d TO now] AND level:ERROR`),
+			NativeQueryOnly: true,
+		}
+
+		request, err := GetSearchRequest(logSearch)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		must := request.Query["bool"].(Map)["must"].([]Map)
+		if len(must) != 1 {
+			t.Fatalf("expected only the native query, got %d clauses: %v", len(must), must)
+		}
+		if request.Size != 100 {
+			t.Errorf("expected default size 100, got %d", request.Size)
+		}
+	})
+}
+
+func TestGetSearchRequest_RuntimeMappings(t *testing.T) {
+	t.Run("runtime mappings and fields are added", func(t *testing.T) {
+		logSearch := &client.LogSearch{
+			Range: client.SearchRange{Last: ty.OptWrap("30m")},
+			Options: ty.MI{
+				"runtimeMappings": map[string]interface{}{
+					"day_of_week": map[string]interface{}{
+						"type":   "keyword",
+						"script": "emit(doc['@timestamp'].value.dayOfWeekEnum.toString())",
+					},
+					"duration_s": ty.MI{
+						"type":   "double",
+						"script": ty.MI{"source": "emit(doc['duration_ms'].value / 1000.0)"},
+					},
+				},
+			},
+			Filter: &client.Filter{Field: "day_of_week", Value: "MONDAY"},
+		}
+
+		request, err := GetSearchRequest(logSearch)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(request.RuntimeMappings) != 2 {
+			t.Errorf("expected 2 runtime mappings, got %v", request.RuntimeMappings)
+		}
+		if strings.Join(request.Fields, ",") != "day_of_week,duration_s" {
+			t.Errorf("expected runtime fields to be requested, got %v", request.Fields)
+		}
+
+		b, _ := json.Marshal(&request)
+		if !strings.Contains(string(b), `"runtime_mappings"`) {
+			t.Errorf("expected runtime_mappings in request body, got: %s", string(b))
+		}
+	})
+
+	t.Run("no runtime mappings omits the block", func(t *testing.T) {
+		request, err := GetSearchRequest(&client.LogSearch{Range: client.SearchRange{Last: ty.OptWrap("30m")}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b, _ := json.Marshal(&request)
+		if strings.Contains(string(b), "runtime_mappings") || strings.Contains(string(b), `"fields"`) {
+			t.Errorf("expected no runtime_mappings or fields, got: %s", string(b))
+		}
+	})
+
+	t.Run("invalid definitions are rejected", func(t *testing.T) {
+		cases := map[string]interface{}{
+			"not a map":        "day_of_week",
+			"missing type":     ty.MI{"day_of_week": ty.MI{"script": "emit('x')"}},
+			"unsupported type": ty.MI{"day_of_week": ty.MI{"type": "text"}},
+		}
+		for name, mappings := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, err := GetSearchRequest(&client.LogSearch{
+					Range:   client.SearchRange{Last: ty.OptWrap("30m")},
+					Options: ty.MI{"runtimeMappings": mappings},
+				})
+				if err == nil {
+					t.Errorf("expected an error for %s", name)
+				}
+			})
+		}
+	})
+}
+
+func TestWrapRuntimeMappingError(t *testing.T) {
+	scriptErr := fmt.Errorf("request failed with status code 400: cannot execute [inline] scripts")
+
+	if err := wrapRuntimeMappingError(scriptErr, true); !strings.Contains(err.Error(), "scripting, which is disabled") {
+		t.Errorf("expected scripting disabled hint, got: %v", err)
+	}
+	if err := wrapRuntimeMappingError(scriptErr, false); err != scriptErr {
+		t.Errorf("expected error to be unchanged without runtime mappings, got: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package opensearch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// runtimeMappingsOption is the search option holding runtime field definitions,
+// keyed by field name:
+//
+//	options:
+//	  runtimeMappings:
+//	    day_of_week:
+//	      type: keyword
+//	      script: "emit(doc['@timestamp'].value.dayOfWeekEnum.toString())"
+const runtimeMappingsOption = "runtimeMappings"
+
+// runtimeFieldTypes lists the types accepted for a runtime field.
+var runtimeFieldTypes = map[string]bool{
+	"boolean":   true,
+	"composite": true,
+	"date":      true,
+	"double":    true,
+	"geo_point": true,
+	"ip":        true,
+	"keyword":   true,
+	"long":      true,
+	"lookup":    true,
+}
+
+// getRuntimeMappings reads and validates the runtimeMappings option. It returns
+// the runtime_mappings block and the sorted list of runtime field names, or nil
+// when the option is not set.
+func getRuntimeMappings(logSearch *client.LogSearch) (Map, []string, error) {
+	raw, ok := logSearch.Options[runtimeMappingsOption]
+	if !ok || raw == nil {
+		return nil, nil, nil
+	}
+
+	definitions, ok := asMap(raw)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s option must be a map of field name to definition", runtimeMappingsOption)
+	}
+
+	mappings := Map{}
+	names := make([]string, 0, len(definitions))
+	for name, def := range definitions {
+		definition, ok := asMap(def)
+		if !ok {
+			return nil, nil, fmt.Errorf("runtime field %q: definition must be a map with a type", name)
+		}
+		fieldType, _ := definition["type"].(string)
+		if fieldType == "" {
+			return nil, nil, fmt.Errorf("runtime field %q: type is required", name)
+		}
+		if !runtimeFieldTypes[fieldType] {
+			return nil, nil, fmt.Errorf("runtime field %q: unsupported type %q", name, fieldType)
+		}
+		mappings[name] = definition
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return mappings, names, nil
+}
+
+// asMap accepts Map, ty.MI and the plain map type produced by YAML/JSON decoding.
+func asMap(v interface{}) (Map, bool) {
+	switch m := v.(type) {
+	case Map:
+		return m, true
+	case ty.MI:
+		return Map(m), true
+	case map[string]interface{}:
+		return Map(m), true
+	}
+	return nil, false
+}
+
+// wrapRuntimeMappingError gives a clearer message when a cluster with scripting
+// disabled rejects a request carrying runtime fields.
+func wrapRuntimeMappingError(err error, hasRuntimeMappings bool) error {
+	if err == nil || !hasRuntimeMappings {
+		return err
+	}
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "script") && (strings.Contains(msg, "disabled") || strings.Contains(msg, "cannot execute") || strings.Contains(msg, "not allowed")) {
+		return fmt.Errorf("runtime fields require scripting, which is disabled on this cluster: %w", err)
+	}
+	return err
+}
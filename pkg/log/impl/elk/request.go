@@ -4,6 +4,7 @@ package elk
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
@@ -64,3 +65,10 @@ func GetDateRangeConditon(gte, lte string) ty.MI {
 		},
 	}
 }
+
+// NativeQueryHasTimeRange reports whether a query_string native query already
+// constrains @timestamp, in which case native-only searches don't add their own
+// range condition.
+func NativeQueryHasTimeRange(query string) bool {
+	return strings.Contains(query, "@timestamp:")
+}
@@ -4,6 +4,7 @@ package elk
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strconv"
@@ -13,6 +14,23 @@ import (
 	"github.com/bascanada/logviewer/pkg/ty"
 )
 
+// ErrSearchTimedOut is the error of a search the cluster cut short at the
+// timeout of the request.
+var ErrSearchTimedOut = fmt.Errorf("search timed out on the cluster: %w", context.DeadlineExceeded)
+
+// SearchTimeout returns the timeout to send with a search so the cluster
+// answers with the hits found so far before the deadline of ctx, or "" when
+// ctx has none.
+func SearchTimeout(ctx context.Context) string {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return ""
+	}
+	// Leave a tenth of the time for the response to come back
+	timeout := time.Until(deadline) * 9 / 10
+	return fmt.Sprintf("%dms", max(timeout.Milliseconds(), 1))
+}
+
 // Hit represents a single hit returned by Elasticsearch for a document.
 type Hit struct {
 	Index  string `json:"_index"`
@@ -20,6 +38,12 @@ type Hit struct {
 	ID     string `json:"_id"`
 	Score  int32  `json:"_score"`
 	Source ty.MI  `json:"_source"`
+	// Fields holds values requested through the "fields" parameter, such as
+	// runtime fields, which are not part of _source. Values are always arrays.
+	Fields ty.MI `json:"fields,omitempty"`
+	// Sort holds the sort values of the hit, kept as received so a
+	// search_after cursor round-trips without losing precision.
+	Sort []json.RawMessage `json:"sort,omitempty"`
 }
 
 // Hits is a wrapper for the hit list returned by an Elasticsearch query.
@@ -42,7 +66,17 @@ type SearchResult struct {
 	// store extracted fields
 	// parsed offset from the incoming page token (set by client.Get)
 	CurrentOffset int
-	ErrChan       chan error
+	// Backend is the client type the next page tokens are issued for
+	Backend string
+	ErrChan chan error
+
+	// TimedOut is set when the cluster cut the search short at its timeout,
+	// the hits being the ones found until then
+	TimedOut bool
+
+	// SearchAfter makes the next page token the sort values of the last hit,
+	// for backends paging with search_after rather than an offset
+	SearchAfter bool
 }
 
 // NewSearchResult constructs a SearchResult from a client, search
@@ -69,6 +103,9 @@ func (sr SearchResult) GetEntries(context context.Context) ([]client.LogEntry, c
 	entries := sr.parseResults()
 
 	c, err := sr.onChange(context)
+	if err == nil && sr.TimedOut {
+		err = client.PartialOnTimeout(entries, ErrSearchTimedOut)
+	}
 
 	return entries, c, err
 }
@@ -91,6 +128,25 @@ func (sr SearchResult) GetFields(_ context.Context) (ty.UniSet[string], chan ty.
 	return fields, nil, nil
 }
 
+// GetFieldNames lists the field names present in the search results without
+// collecting their values.
+func (sr SearchResult) GetFieldNames(_ context.Context) ([]string, error) {
+	seen := map[string]struct{}{}
+	for _, h := range sr.result.Hits {
+		for k, v := range h.Source {
+			if k == "message" || k == "@timestamp" {
+				continue
+			}
+			ty.AddFieldName(k, v, seen)
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for k := range seen {
+		names = append(names, k)
+	}
+	return names, nil
+}
+
 func (sr SearchResult) parseResults() []client.LogEntry {
 	size := len(sr.result.Hits)
 
@@ -124,7 +180,7 @@ func (sr SearchResult) parseResults() []client.LogEntry {
 			entries[size-i-1] = client.LogEntry{
 				Message:   message,
 				Timestamp: date,
-				Level:     level, Fields: h.Source}
+				Level:     level, Fields: mergeHitFields(h)}
 		} else {
 			fmt.Printf("timestamp is not string : %+v \n", h.Source["@timestamp"])
 		}
@@ -133,6 +189,30 @@ func (sr SearchResult) parseResults() []client.LogEntry {
 	return entries
 }
 
+// mergeHitFields returns the hit _source with any extra "fields" values (e.g.
+// runtime fields) added. Single-valued arrays are unwrapped and keys already
+// present in _source are left untouched.
+func mergeHitFields(h Hit) ty.MI {
+	if len(h.Fields) == 0 {
+		return h.Source
+	}
+	fields := make(ty.MI, len(h.Source)+len(h.Fields))
+	for k, v := range h.Source {
+		fields[k] = v
+	}
+	for k, v := range h.Fields {
+		if _, exists := fields[k]; exists {
+			continue
+		}
+		if values, ok := v.([]interface{}); ok && len(values) == 1 {
+			fields[k] = values[0]
+		} else {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
 // GetPaginationInfo returns pagination details (has more / next page
 // token) when the search explicitly requested a size and more results
 // are available.
@@ -152,9 +232,19 @@ func (sr SearchResult) GetPaginationInfo() *client.PaginationInfo {
 		return nil
 	}
 
+	// The oldest hit is last, the next page starts after its sort values
+	if sr.SearchAfter && numResults > 0 && len(sr.result.Hits[numResults-1].Sort) > 0 {
+		if cursor, err := json.Marshal(sr.result.Hits[numResults-1].Sort); err == nil {
+			return &client.PaginationInfo{
+				HasMore:       true,
+				NextPageToken: client.EncodePageToken(sr.Backend, string(cursor)),
+			}
+		}
+	}
+
 	return &client.PaginationInfo{
 		HasMore:       true,
-		NextPageToken: strconv.Itoa(currentOffset + numResults),
+		NextPageToken: client.EncodePageToken(sr.Backend, strconv.Itoa(currentOffset+numResults)),
 	}
 }
 
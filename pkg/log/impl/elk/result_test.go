@@ -36,7 +36,7 @@ func TestSearchResult_GetPaginationInfo(t *testing.T) {
 		paginationInfo := result.GetPaginationInfo()
 		assert.NotNil(t, paginationInfo)
 		assert.True(t, paginationInfo.HasMore)
-		assert.Equal(t, "10", paginationInfo.NextPageToken)
+		assert.Equal(t, client.EncodePageToken(result.Backend, "10"), paginationInfo.NextPageToken)
 	})
 
 	t.Run("with existing page token", func(t *testing.T) {
@@ -52,7 +52,7 @@ func TestSearchResult_GetPaginationInfo(t *testing.T) {
 		paginationInfo := result.GetPaginationInfo()
 		assert.NotNil(t, paginationInfo)
 		assert.True(t, paginationInfo.HasMore)
-		assert.Equal(t, "20", paginationInfo.NextPageToken)
+		assert.Equal(t, client.EncodePageToken(result.Backend, "20"), paginationInfo.NextPageToken)
 	})
 
 	t.Run("invalid page token", func(t *testing.T) {
@@ -67,7 +67,7 @@ func TestSearchResult_GetPaginationInfo(t *testing.T) {
 		paginationInfo := result.GetPaginationInfo()
 		assert.NotNil(t, paginationInfo)
 		assert.True(t, paginationInfo.HasMore)
-		assert.Equal(t, "10", paginationInfo.NextPageToken)
+		assert.Equal(t, client.EncodePageToken(result.Backend, "10"), paginationInfo.NextPageToken)
 	})
 }
 
@@ -403,6 +403,34 @@ func TestSearchResult_parseResults(t *testing.T) {
 		assert.Len(t, entries, 1)
 		assert.Equal(t, "WARN", entries[0].Level)
 	})
+
+	t.Run("Surfaces runtime fields", func(t *testing.T) {
+		timestamp := time.Now().Format(time.RFC3339Nano)
+		result := SearchResult{
+			search: &client.LogSearch{Options: ty.MI{"index": "test"}},
+			result: Hits{
+				Hits: []Hit{
+					{
+						Source: ty.MI{
+							"message":    "log message",
+							"@timestamp": timestamp,
+						},
+						Fields: ty.MI{
+							"day_of_week": []interface{}{"MONDAY"},
+							"tags":        []interface{}{"a", "b"},
+							"@timestamp":  []interface{}{"ignored"},
+						},
+					},
+				},
+			},
+		}
+
+		entries := result.parseResults()
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "MONDAY", entries[0].Fields["day_of_week"])
+		assert.Equal(t, []interface{}{"a", "b"}, entries[0].Fields["tags"])
+		assert.Equal(t, timestamp, entries[0].Fields["@timestamp"])
+	})
 }
 
 func TestSearchResult_GetSearch(t *testing.T) {
@@ -418,3 +446,24 @@ func TestSearchResult_Err(t *testing.T) {
 
 	assert.Equal(t, (<-chan error)(errChan), result.Err())
 }
+
+func TestSearchResult_GetFieldNames(t *testing.T) {
+	result := SearchResult{
+		search: &client.LogSearch{},
+		result: Hits{
+			Hits: []Hit{
+				{Source: ty.MI{"message": "m", "@timestamp": "t", "level": "INFO", "kubernetes": map[string]interface{}{"pod": "api-1"}}},
+				{Source: ty.MI{"message": "m", "level": "ERROR", "count": 3.0}},
+			},
+		},
+	}
+
+	names, err := client.GetFieldNames(context.Background(), result)
+	require.NoError(t, err)
+
+	fields, _, err := result.GetFields(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"kubernetes.pod", "level"}, names)
+	assert.Len(t, fields, len(names), "names-only listing should match GetFields keys")
+}
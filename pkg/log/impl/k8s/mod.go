@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -56,7 +57,7 @@ type LogClientOptions struct {
  */
 
 type k8sLogClient struct {
-	clientset *kubernetes.Clientset
+	clientset kubernetes.Interface
 }
 
 func (lc k8sLogClient) Get(ctx context.Context, search *client.LogSearch) (client.LogSearchResult, error) {
@@ -79,7 +80,7 @@ func (lc k8sLogClient) Get(ctx context.Context, search *client.LogSearch) (clien
 
 	// If labelSelector is provided, query multiple pods
 	if labelSelector != "" {
-		return lc.getLogsFromMultiplePods(ctx, search, namespace, labelSelector, previous, timestamp, follow, tailLines)
+		return lc.getLogsFromMultiplePods(ctx, search, namespace, labelSelector)
 	}
 
 	// Single pod query (original behavior)
@@ -125,10 +126,24 @@ func (lc k8sLogClient) Get(ctx context.Context, search *client.LogSearch) (clien
 	return reader.GetLogResult(search, scanner, podLogs)
 }
 
-// podNameInjector wraps a LogSearchResult and injects the pod name into each log entry's Fields
+// podNameInjector wraps a LogSearchResult and injects the pod name, and the
+// container when known, into each log entry's Fields
 type podNameInjector struct {
-	inner   client.LogSearchResult
-	podName string
+	inner     client.LogSearchResult
+	podName   string
+	container string
+}
+
+func (p *podNameInjector) inject(entries []client.LogEntry) {
+	for i := range entries {
+		if entries[i].Fields == nil {
+			entries[i].Fields = make(ty.MI)
+		}
+		entries[i].Fields[FieldPod] = p.podName
+		if p.container != "" {
+			entries[i].Fields[FieldContainer] = p.container
+		}
+	}
 }
 
 func (p *podNameInjector) GetSearch() *client.LogSearch {
@@ -137,17 +152,12 @@ func (p *podNameInjector) GetSearch() *client.LogSearch {
 
 func (p *podNameInjector) GetEntries(ctx context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
 	entries, ch, err := p.inner.GetEntries(ctx)
-	if err != nil {
+	if err != nil && !client.IsPartial(err) {
 		return nil, nil, err
 	}
 
 	// Inject pod name into all initial entries
-	for i := range entries {
-		if entries[i].Fields == nil {
-			entries[i].Fields = make(ty.MI)
-		}
-		entries[i].Fields[FieldPod] = p.podName
-	}
+	p.inject(entries)
 
 	// If there's a channel for streaming entries, wrap it
 	if ch != nil {
@@ -155,19 +165,18 @@ func (p *podNameInjector) GetEntries(ctx context.Context) ([]client.LogEntry, ch
 		go func() {
 			defer close(wrappedCh)
 			for batch := range ch {
-				for i := range batch {
-					if batch[i].Fields == nil {
-						batch[i].Fields = make(ty.MI)
-					}
-					batch[i].Fields[FieldPod] = p.podName
+				p.inject(batch)
+				select {
+				case wrappedCh <- batch:
+				case <-ctx.Done():
+					return
 				}
-				wrappedCh <- batch
 			}
 		}()
-		return entries, wrappedCh, nil
+		return entries, wrappedCh, err
 	}
 
-	return entries, nil, nil
+	return entries, nil, err
 }
 
 func (p *podNameInjector) GetFields(ctx context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
@@ -182,17 +191,13 @@ func (p *podNameInjector) Err() <-chan error {
 	return p.inner.Err()
 }
 
-// getLogsFromMultiplePods fetches logs from all pods matching the label selector
-// and aggregates them using MultiLogSearchResult
+// getLogsFromMultiplePods fetches logs from all containers of the pods
+// matching the label selector and merges them in a podsResult
 func (lc k8sLogClient) getLogsFromMultiplePods(
 	ctx context.Context,
 	search *client.LogSearch,
 	namespace string,
 	labelSelector string,
-	_ bool,
-	_ bool,
-	_ bool,
-	_ *int64,
 ) (client.LogSearchResult, error) {
 
 	// List pods matching the label selector
@@ -207,75 +212,44 @@ func (lc k8sLogClient) getLogsFromMultiplePods(
 		return nil, errors.New("no pods found matching labelSelector: " + labelSelector)
 	}
 
-	// If only one pod, optimize by returning a single result
-	if len(podList.Items) == 1 {
-		podName := podList.Items[0].Name
-		singlePodSearch := *search
-		singlePodSearch.Options[FieldPod] = podName
-		delete(singlePodSearch.Options, FieldLabelSelector)
-
-		result, err := lc.Get(ctx, &singlePodSearch)
-		if err != nil {
-			return nil, err
-		}
-
-		// Wrap the result to inject pod name, ensuring consistent output with the multi-pod case
-		if result != nil {
-			return &podNameInjector{
-				inner:   result,
-				podName: podName,
-			}, nil
-		}
-		return result, nil
-	}
-
-	// Create multi-result aggregator
-	multiResult, err := client.NewMultiLogSearchResult(search)
-	if err != nil {
-		return nil, err
+	r := &podsResult{
+		lc:              lc,
+		search:          search,
+		namespace:       namespace,
+		labelSelector:   labelSelector,
+		resourceVersion: podList.ResourceVersion,
+		streaming:       map[string]bool{},
+		ended:           map[string]time.Time{},
 	}
 
-	// Query logs from each pod concurrently
+	// Open the logs of every container concurrently
+	container := search.Options.GetString(FieldContainer)
+	var mu sync.Mutex
+	var failures client.MultiError
 	var wg sync.WaitGroup
 	for _, pod := range podList.Items {
-		wg.Add(1)
-		go func(podName string) {
-			defer wg.Done()
-
-			// Create a copy of the search for this specific pod
-			// Deep copy all map fields to prevent race conditions
-			podSearch := *search
-			podSearch.Options = ty.MergeM(make(ty.MI, len(search.Options)+1), search.Options)
-			podSearch.Options[FieldPod] = podName
-			podSearch.Fields = ty.MergeM(make(ty.MS, len(search.Fields)), search.Fields)
-			podSearch.FieldsCondition = ty.MergeM(make(ty.MS, len(search.FieldsCondition)), search.FieldsCondition)
-			if search.Variables != nil {
-				podSearch.Variables = make(map[string]client.VariableDefinition, len(search.Variables))
-				for k, v := range search.Variables {
-					podSearch.Variables[k] = v
+		for _, c := range podContainers(pod, container) {
+			failures.Total++
+			wg.Add(1)
+			go func(podName, c string) {
+				defer wg.Done()
+				if _, err := r.open(ctx, r.podSearch(podName, c), podName, c); err != nil {
+					mu.Lock()
+					failures.Add(podName+"/"+c, err)
+					mu.Unlock()
 				}
-			}
-
-			// Remove labelSelector from options to avoid infinite recursion
-			delete(podSearch.Options, FieldLabelSelector)
-
-			// Get logs for this pod
-			result, err := lc.Get(ctx, &podSearch)
-
-			// Wrap the result to inject pod name into each log entry
-			if result != nil {
-				result = &podNameInjector{
-					inner:   result,
-					podName: podName,
-				}
-			}
-
-			multiResult.Add(result, err)
-		}(pod.Name)
+			}(pod.Name, c)
+		}
 	}
-
 	wg.Wait()
-	return multiResult, nil
+
+	if failures.AllFailed() {
+		return nil, &failures
+	}
+	if failures.Partial() {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", &failures)
+	}
+	return r, nil
 }
 
 func (lc k8sLogClient) GetFieldValues(ctx context.Context, search *client.LogSearch, fields []string) (map[string][]string, error) {
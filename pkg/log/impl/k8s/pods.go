@@ -0,0 +1,324 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// podsResult merges the logs of every pod matching a label selector into one
+// stream sorted by timestamp. Each container of a pod is its own stream, and
+// its entries are tagged with Fields["pod"] and Fields["container"].
+type podsResult struct {
+	lc              k8sLogClient
+	search          *client.LogSearch
+	namespace       string
+	labelSelector   string
+	resourceVersion string
+
+	mu        sync.Mutex
+	streams   []podStream
+	streaming map[string]bool
+	ended     map[string]time.Time
+}
+
+// podStream is the result of one container of a pod.
+type podStream struct {
+	pod       string
+	container string
+	result    client.LogSearchResult
+}
+
+func (s podStream) key() string {
+	return s.pod + "/" + s.container
+}
+
+var _ client.LogSearchResult = (*podsResult)(nil)
+
+// podContainers returns the containers of pod to read: the one of the search
+// when set, every container of the pod otherwise.
+func podContainers(pod v1.Pod, container string) []string {
+	if container != "" || len(pod.Spec.Containers) == 0 {
+		return []string{container}
+	}
+	names := make([]string, len(pod.Spec.Containers))
+	for i, c := range pod.Spec.Containers {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// podSearch returns the search reading one container of pod.
+func (r *podsResult) podSearch(pod, container string) *client.LogSearch {
+	search := r.search.Clone()
+	search.Options[FieldPod] = pod
+	if container != "" {
+		search.Options[FieldContainer] = container
+	}
+	// Remove labelSelector from options to avoid infinite recursion
+	delete(search.Options, FieldLabelSelector)
+	return search
+}
+
+// open starts the log stream of one container of pod.
+func (r *podsResult) open(ctx context.Context, search *client.LogSearch, pod, container string) (podStream, error) {
+	result, err := r.lc.Get(ctx, search)
+	if err != nil {
+		return podStream{}, err
+	}
+	s := podStream{
+		pod:       pod,
+		container: container,
+		result:    &podNameInjector{inner: result, podName: pod, container: container},
+	}
+	r.mu.Lock()
+	r.streams = append(r.streams, s)
+	r.mu.Unlock()
+	return s, nil
+}
+
+// read returns the entries of s so far and the channel of the following ones,
+// marking s as streaming while the channel is open, and as ended otherwise.
+func (r *podsResult) read(ctx context.Context, s podStream) ([]client.LogEntry, chan []client.LogEntry, error) {
+	entries, ch, err := s.result.GetEntries(ctx)
+	if err != nil && !client.IsPartial(err) {
+		return nil, nil, err
+	}
+	r.mu.Lock()
+	if ch != nil {
+		r.streaming[s.key()] = true
+	} else {
+		r.ended[s.key()] = time.Now()
+	}
+	r.mu.Unlock()
+	return entries, ch, err
+}
+
+// GetEntries reads every pod at once and merges their entries by timestamp.
+// In follow mode the pods keep streaming, and a watch on the label selector
+// starts streaming the pods that become ready after the search. The streams
+// stop when ctx is done.
+func (r *podsResult) GetEntries(ctx context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	r.mu.Lock()
+	streams := append([]podStream(nil), r.streams...)
+	r.mu.Unlock()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		entries  []client.LogEntry
+		channels = map[string]chan []client.LogEntry{}
+		failures = client.MultiError{Total: len(streams)}
+		partial  error
+	)
+
+	for _, s := range streams {
+		wg.Add(1)
+		go func(s podStream) {
+			defer wg.Done()
+			es, ch, err := r.read(ctx, s)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && !client.IsPartial(err) {
+				failures.Add(s.key(), err)
+				return
+			}
+			if err != nil {
+				partial = err
+			}
+			entries = append(entries, es...)
+			if ch != nil {
+				channels[s.key()] = ch
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	if failures.AllFailed() {
+		return nil, nil, &failures
+	}
+	if failures.Partial() {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", &failures)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	if r.search.Size.Set && r.search.Size.Value > 0 && len(entries) > r.search.Size.Value {
+		entries = entries[len(entries)-r.search.Size.Value:]
+	}
+
+	if !r.search.Follow {
+		return entries, nil, partial
+	}
+
+	out := make(chan []client.LogEntry)
+	var forwarders sync.WaitGroup
+
+	forward := func(s podStream, ch chan []client.LogEntry) {
+		defer forwarders.Done()
+		defer func() {
+			r.mu.Lock()
+			delete(r.streaming, s.key())
+			r.ended[s.key()] = time.Now()
+			r.mu.Unlock()
+		}()
+		for batch := range ch {
+			select {
+			case out <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	for _, s := range streams {
+		if ch, ok := channels[s.key()]; ok {
+			forwarders.Add(1)
+			go forward(s, ch)
+		}
+	}
+
+	forwarders.Add(1)
+	go func() {
+		defer forwarders.Done()
+		r.watch(ctx, func(s podStream, es []client.LogEntry, ch chan []client.LogEntry) bool {
+			if len(es) > 0 {
+				select {
+				case out <- es:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			if ch != nil {
+				forwarders.Add(1)
+				go forward(s, ch)
+			}
+			return true
+		})
+	}()
+
+	go func() {
+		forwarders.Wait()
+		close(out)
+	}()
+
+	return entries, out, nil
+}
+
+// watch opens the containers of the running pods matching the label selector
+// that are not streaming yet, until ctx is done. A container that streamed
+// before is only read from the time its stream ended, so a restart does not
+// replay its history. onStream returns false to stop watching.
+func (r *podsResult) watch(ctx context.Context, onStream func(podStream, []client.LogEntry, chan []client.LogEntry) bool) {
+	w, err := r.lc.clientset.CoreV1().Pods(r.namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector:   r.labelSelector,
+		ResourceVersion: r.resourceVersion,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error watching pods for labelSelector %s: %v\n", r.labelSelector, err)
+		return
+	}
+	defer w.Stop()
+
+	for {
+		var event watch.Event
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			event = ev
+		}
+
+		pod, ok := event.Object.(*v1.Pod)
+		if !ok || event.Type == watch.Deleted || pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+
+		for _, container := range podContainers(*pod, r.search.Options.GetString(FieldContainer)) {
+			key := podStream{pod: pod.Name, container: container}.key()
+			r.mu.Lock()
+			streaming := r.streaming[key]
+			ended, restarted := r.ended[key]
+			r.mu.Unlock()
+			if streaming {
+				continue
+			}
+
+			search := r.podSearch(pod.Name, container)
+			search.Range = client.SearchRange{}
+			search.Size = ty.Opt[int]{}
+			if restarted {
+				search.Range.Gte.S(ended.UTC().Format(time.RFC3339))
+			}
+
+			s, err := r.open(ctx, search, pod.Name, container)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error fetching logs for pod %s: %v\n", key, err)
+				continue
+			}
+			es, ch, err := r.read(ctx, s)
+			if err != nil && !client.IsPartial(err) {
+				fmt.Fprintf(os.Stderr, "Error reading logs for pod %s: %v\n", key, err)
+				continue
+			}
+			if !onStream(s, es, ch) {
+				return
+			}
+		}
+	}
+}
+
+// GetFields merges the fields of the containers read so far.
+func (r *podsResult) GetFields(ctx context.Context) (ty.UniSet[string], chan ty.UniSet[string], error) {
+	r.mu.Lock()
+	streams := append([]podStream(nil), r.streams...)
+	r.mu.Unlock()
+
+	fields := make(ty.UniSet[string])
+	for _, s := range streams {
+		fields.Add(FieldPod, s.pod)
+		if s.container != "" {
+			fields.Add(FieldContainer, s.container)
+		}
+		fs, _, err := s.result.GetFields(ctx)
+		if err != nil {
+			continue
+		}
+		for k, values := range fs {
+			for _, v := range values {
+				fields.Add(k, v)
+			}
+		}
+	}
+	return fields, nil, nil
+}
+
+// GetSearch returns the search of the label selector.
+func (r *podsResult) GetSearch() *client.LogSearch {
+	return r.search
+}
+
+// GetPaginationInfo returns nil, pod logs are not paginated.
+func (r *podsResult) GetPaginationInfo() *client.PaginationInfo {
+	return nil
+}
+
+// Err returns nil, stream errors are printed as they happen.
+func (r *podsResult) Err() <-chan error {
+	return nil
+}
@@ -0,0 +1,155 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/reader"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testPod(name string, containers ...string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "web"}},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	for _, c := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: c})
+	}
+	return pod
+}
+
+func TestGetLogsFromMultiplePods(t *testing.T) {
+	t.Run("Reads every container of every pod", func(t *testing.T) {
+		lc := k8sLogClient{clientset: fake.NewSimpleClientset(
+			testPod("web-1", "app", "sidecar"),
+			testPod("web-2", "app"),
+		)}
+		search := &client.LogSearch{Options: ty.MI{FieldNamespace: "default", FieldLabelSelector: "app=web"}}
+
+		result, err := lc.Get(context.Background(), search)
+		require.NoError(t, err)
+
+		entries, ch, err := result.GetEntries(context.Background())
+		require.NoError(t, err)
+		assert.Nil(t, ch)
+
+		var sources []string
+		for _, e := range entries {
+			sources = append(sources, e.Fields.GetString(FieldPod)+"/"+e.Fields.GetString(FieldContainer))
+		}
+		assert.ElementsMatch(t, []string{"web-1/app", "web-1/sidecar", "web-2/app"}, sources)
+	})
+
+	t.Run("Reads only the container of the search", func(t *testing.T) {
+		lc := k8sLogClient{clientset: fake.NewSimpleClientset(testPod("web-1", "app", "sidecar"))}
+		search := &client.LogSearch{Options: ty.MI{
+			FieldNamespace:     "default",
+			FieldLabelSelector: "app=web",
+			FieldContainer:     "app",
+		}}
+
+		result, err := lc.Get(context.Background(), search)
+		require.NoError(t, err)
+
+		entries, _, err := result.GetEntries(context.Background())
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "app", entries[0].Fields[FieldContainer])
+	})
+
+	t.Run("No matching pod", func(t *testing.T) {
+		lc := k8sLogClient{clientset: fake.NewSimpleClientset()}
+		search := &client.LogSearch{Options: ty.MI{FieldNamespace: "default", FieldLabelSelector: "app=web"}}
+
+		_, err := lc.Get(context.Background(), search)
+		assert.ErrorContains(t, err, "no pods found")
+	})
+
+	t.Run("Follow picks up new pods from the watch", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(testPod("web-1", "app"))
+		lc := k8sLogClient{clientset: clientset}
+		search := &client.LogSearch{
+			Follow:  true,
+			Options: ty.MI{FieldNamespace: "default", FieldLabelSelector: "app=web"},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result, err := lc.Get(ctx, search)
+		require.NoError(t, err)
+
+		entries, ch, err := result.GetEntries(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, ch)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "web-1", entries[0].Fields[FieldPod])
+
+		// Wait for the watch to be registered before creating the pod
+		time.Sleep(50 * time.Millisecond)
+		_, err = clientset.CoreV1().Pods("default").Create(ctx, testPod("web-2", "app"), metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		select {
+		case batch := <-ch:
+			require.NotEmpty(t, batch)
+			assert.Equal(t, "web-2", batch[0].Fields[FieldPod])
+			assert.Equal(t, "app", batch[0].Fields[FieldContainer])
+		case <-time.After(2 * time.Second):
+			t.Fatal("no entries from the new pod")
+		}
+
+		// Cancelling the context closes the stream
+		cancel()
+		select {
+		case _, ok := <-ch:
+			for ok {
+				_, ok = <-ch
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("stream not closed on cancel")
+		}
+	})
+}
+
+func TestPodsResult_TimeoutKeepsEntries(t *testing.T) {
+	// The stream sends one line and then stalls past the deadline.
+	pr, pw := io.Pipe()
+	t.Cleanup(func() { _ = pw.Close() })
+	go func() {
+		_, _ = io.WriteString(pw, "log from web-1\n")
+	}()
+
+	search := &client.LogSearch{Options: ty.MI{FieldNamespace: "default"}}
+	stream, err := reader.GetLogResult(search, bufio.NewScanner(pr), pr)
+	require.NoError(t, err)
+
+	result := &podsResult{
+		search:    search,
+		streaming: map[string]bool{},
+		ended:     map[string]time.Time{},
+		streams: []podStream{{
+			pod:       "web-1",
+			container: "app",
+			result:    &podNameInjector{inner: stream, podName: "web-1", container: "app"},
+		}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	entries, ch, err := result.GetEntries(ctx)
+	assert.True(t, client.IsPartial(err), "got %v", err)
+	assert.Nil(t, ch)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "web-1", entries[0].Fields[FieldPod])
+}
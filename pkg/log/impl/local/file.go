@@ -0,0 +1,257 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	mylog "github.com/bascanada/logviewer/pkg/log"
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/reader"
+)
+
+// getFile reads the file at path directly, without a shell: its last Size
+// lines, or all of them, then in follow mode the lines appended to it. A
+// truncated file is read again from its start, and a rotated one, renamed or
+// removed then created again, is reopened.
+func (lc localLogClient) getFile(ctx context.Context, search *client.LogSearch, path string) (client.LogSearchResult, error) {
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	var watcher *fsnotify.Watcher
+	if search.Follow {
+		// Watch the directory, the file may be replaced by a rotation
+		if watcher, err = fsnotify.NewWatcher(); err == nil {
+			err = watcher.Add(filepath.Dir(path))
+		}
+		if err != nil {
+			_ = f.Close()
+			if watcher != nil {
+				_ = watcher.Close()
+			}
+			return nil, fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	t := &fileTail{path: filepath.Clean(path), file: f, watcher: watcher, pr: pr, pw: pw, done: make(chan struct{})}
+
+	go t.run(ctx, search)
+
+	search, out, closer := detectJSON(search, pr, t)
+	result, err := reader.GetLogResult(search, bufio.NewScanner(out), closer)
+	if err != nil {
+		_ = closer.Close()
+		return nil, err
+	}
+	return result, nil
+}
+
+// fileTail writes the lines of a file to a pipe read by the reader.
+type fileTail struct {
+	path    string
+	file    *os.File
+	watcher *fsnotify.Watcher
+
+	pr   *io.PipeReader
+	pw   *io.PipeWriter
+	done chan struct{}
+	once sync.Once
+	// lastByte is the last byte written, to end a partial line before
+	// switching to a new file
+	lastByte byte
+}
+
+// Close stops the tail.
+func (t *fileTail) Close() error {
+	t.once.Do(func() {
+		close(t.done)
+		_ = t.pr.Close()
+	})
+	return nil
+}
+
+func (t *fileTail) run(ctx context.Context, search *client.LogSearch) {
+	defer func() {
+		if t.file != nil {
+			_ = t.file.Close()
+		}
+		if t.watcher != nil {
+			_ = t.watcher.Close()
+		}
+	}()
+
+	size := 0
+	if search.Size.Set && search.Size.Value > 0 {
+		size = search.Size.Value
+	}
+	if err := t.writeHistory(size); err != nil || t.watcher == nil {
+		_ = t.pw.CloseWithError(err)
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = t.pw.Close()
+			return
+		case <-t.done:
+			return
+		case err, ok := <-t.watcher.Errors:
+			if !ok {
+				_ = t.pw.Close()
+				return
+			}
+			mylog.Warn("watching %s: %v", t.path, err)
+		case event, ok := <-t.watcher.Events:
+			if !ok {
+				_ = t.pw.Close()
+				return
+			}
+			if filepath.Clean(event.Name) != t.path {
+				continue
+			}
+			if err := t.handle(event); err != nil {
+				_ = t.pw.CloseWithError(err)
+				return
+			}
+		}
+	}
+}
+
+// writeHistory writes the last size lines of the file, all of them when
+// size is 0, leaving the file at its end.
+func (t *fileTail) writeHistory(size int) error {
+	if size == 0 {
+		return t.copy()
+	}
+
+	lines := make([]string, 0, size)
+	scanner := bufio.NewScanner(t.file)
+	for scanner.Scan() {
+		if len(lines) == size {
+			lines = append(lines[:0], lines[1:]...)
+		}
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	for _, l := range lines {
+		if err := t.write([]byte(l + "\n")); err != nil {
+			return err
+		}
+	}
+	// The scanner may stop before the end on a partial last line
+	_, err := t.file.Seek(0, io.SeekEnd)
+	return err
+}
+
+// handle reacts to an event on the followed file.
+func (t *fileTail) handle(event fsnotify.Event) error {
+	switch {
+	case event.Has(fsnotify.Create):
+		// Rotated: read what is left of the old file, then switch
+		if err := t.copy(); err != nil {
+			return err
+		}
+		return t.reopen()
+	case event.Has(fsnotify.Rename), event.Has(fsnotify.Remove):
+		// Keep the old file open until the new one is created
+		return t.copy()
+	case event.Has(fsnotify.Write):
+		if t.file == nil {
+			return t.reopen()
+		}
+		if err := t.rewindIfTruncated(); err != nil {
+			return err
+		}
+		return t.copy()
+	}
+	return nil
+}
+
+// rewindIfTruncated reads the file from its start again when it is now
+// shorter than what was read.
+func (t *fileTail) rewindIfTruncated() error {
+	offset, err := t.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	info, err := t.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < offset {
+		if err := t.endLine(); err != nil {
+			return err
+		}
+		_, err = t.file.Seek(0, io.SeekStart)
+	}
+	return err
+}
+
+// reopen switches to the file now at path.
+func (t *fileTail) reopen() error {
+	if t.file != nil {
+		_ = t.file.Close()
+		t.file = nil
+	}
+	if err := t.endLine(); err != nil {
+		return err
+	}
+	f, err := os.Open(t.path) //nolint:gosec
+	if err != nil {
+		// Not created yet, the next event tries again
+		return nil
+	}
+	t.file = f
+	return t.copy()
+}
+
+// copy writes the unread content of the file.
+func (t *fileTail) copy() error {
+	if t.file == nil {
+		return nil
+	}
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := t.file.Read(buf)
+		if n > 0 {
+			if werr := t.write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// endLine ends the partial line written last, so it is not merged with the
+// first line of the next file.
+func (t *fileTail) endLine() error {
+	if t.lastByte == 0 || t.lastByte == '\n' {
+		return nil
+	}
+	return t.write([]byte("\n"))
+}
+
+func (t *fileTail) write(b []byte) error {
+	if _, err := t.pw.Write(b); err != nil {
+		return err
+	}
+	t.lastByte = b[len(b)-1]
+	return nil
+}
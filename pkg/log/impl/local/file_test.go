@@ -0,0 +1,103 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	logclient "github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func messages(entries []logclient.LogEntry) []string {
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.Message
+	}
+	return out
+}
+
+// nextMessage waits for the next streamed entry.
+func nextMessage(t *testing.T, ch chan []logclient.LogEntry) string {
+	t.Helper()
+	select {
+	case batch := <-ch:
+		require.NotEmpty(t, batch)
+		return batch[0].Message
+	case <-time.After(3 * time.Second):
+		t.Fatal("no entry streamed")
+		return ""
+	}
+}
+
+func appendTo(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func TestLocalClient_File(t *testing.T) {
+	lc, err := GetLogClient()
+	require.NoError(t, err)
+
+	t.Run("reads the last Size lines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0o600))
+
+		result, err := lc.Get(context.Background(), &logclient.LogSearch{
+			Size:    ty.Opt[int]{Set: true, Value: 2},
+			Options: ty.MI{OptionsFile: path},
+		})
+		require.NoError(t, err)
+
+		entries, ch, err := result.GetEntries(context.Background())
+		require.NoError(t, err)
+		assert.Nil(t, ch)
+		assert.Equal(t, []string{"two", "three"}, messages(entries))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := lc.Get(context.Background(), &logclient.LogSearch{
+			Options: ty.MI{OptionsFile: filepath.Join(t.TempDir(), "missing.log")},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("follows appends, truncation and rotation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o600))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		result, err := lc.Get(ctx, &logclient.LogSearch{
+			Follow:  true,
+			Options: ty.MI{OptionsFile: path},
+		})
+		require.NoError(t, err)
+
+		entries, ch, err := result.GetEntries(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, ch)
+		assert.Equal(t, []string{"old"}, messages(entries))
+
+		appendTo(t, path, "appended\n")
+		assert.Equal(t, "appended", nextMessage(t, ch))
+
+		require.NoError(t, os.Truncate(path, 0))
+		// Let the truncation be seen before the file grows past the offset
+		time.Sleep(100 * time.Millisecond)
+		appendTo(t, path, "after truncate\n")
+		assert.Equal(t, "after truncate", nextMessage(t, ch))
+
+		require.NoError(t, os.Rename(path, path+".1"))
+		require.NoError(t, os.WriteFile(path, []byte("rotated\n"), 0o600))
+		assert.Equal(t, "rotated", nextMessage(t, ch))
+	})
+}
@@ -0,0 +1,165 @@
+package local
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	mylog "github.com/bascanada/logviewer/pkg/log"
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+const (
+	// sniffLines is the number of lines looked at to detect JSON.
+	sniffLines = 5
+	// sniffTimeout bounds the wait for the lines to sniff, a followed source
+	// may print them slowly.
+	sniffTimeout = 500 * time.Millisecond
+)
+
+var (
+	jsonTimestampKeys = []string{"@timestamp", "timestamp", "time"}
+	jsonLevelKeys     = []string{"level", "severity"}
+	jsonMessageKeys   = []string{"message", "msg"}
+)
+
+// detectJSON returns search with JSON extraction enabled when
+// FieldExtraction.JSONAutoDetect is set and the first lines of r are JSON
+// objects, with the keys not set inferred from the first object. The returned
+// reader and closer replace r and closer, as the sniffed lines are read.
+func detectJSON(search *client.LogSearch, r io.Reader, closer io.Closer) (*client.LogSearch, io.Reader, io.Closer) {
+	fe := search.FieldExtraction
+	if !fe.JSONAutoDetect.Value || fe.JSON.Set {
+		return search, r, closer
+	}
+
+	sniffed := newSniffer(r, closer)
+	object, ok := jsonObjects(sniffed.lines())
+	if !ok {
+		return search, sniffed.pr, sniffed
+	}
+
+	detected := search.Clone()
+	detected.FieldExtraction.JSON.S(true)
+	inferKey(&detected.FieldExtraction.JSONTimestampKey, object, jsonTimestampKeys)
+	inferKey(&detected.FieldExtraction.JSONLevelKey, object, jsonLevelKeys)
+	inferKey(&detected.FieldExtraction.JSONMessageKey, object, jsonMessageKeys)
+
+	mylog.Debug("detected JSON logs, timestamp=%s level=%s message=%s",
+		detected.FieldExtraction.JSONTimestampKey.Value,
+		detected.FieldExtraction.JSONLevelKey.Value,
+		detected.FieldExtraction.JSONMessageKey.Value)
+	return detected, sniffed.pr, sniffed
+}
+
+// inferKey sets the key to the first of candidates in object, unless set.
+func inferKey(key *ty.Opt[string], object map[string]any, candidates []string) {
+	if key.Set {
+		return
+	}
+	for _, c := range candidates {
+		if _, ok := object[c]; ok {
+			key.S(c)
+			return
+		}
+	}
+}
+
+// jsonObjects returns the first of lines when every non-empty one is a JSON
+// object, and at least one is.
+func jsonObjects(lines []string) (map[string]any, bool) {
+	var first map[string]any
+	for _, l := range lines {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		var object map[string]any
+		if !strings.HasPrefix(l, "{") || json.Unmarshal([]byte(l), &object) != nil {
+			return nil, false
+		}
+		if first == nil {
+			first = object
+		}
+	}
+	return first, first != nil
+}
+
+// sniffer copies the lines of a source to a pipe, keeping the first ones
+// aside to be looked at before the reader starts.
+type sniffer struct {
+	source io.Closer
+	pr     *io.PipeReader
+	pw     *io.PipeWriter
+	first  chan string
+	once   sync.Once
+}
+
+func newSniffer(r io.Reader, source io.Closer) *sniffer {
+	pr, pw := io.Pipe()
+	s := &sniffer{source: source, pr: pr, pw: pw, first: make(chan string, sniffLines)}
+	go s.run(r)
+	return s
+}
+
+func (s *sniffer) run(r io.Reader) {
+	br := bufio.NewReader(r)
+	n := 0
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			if n < sniffLines {
+				s.first <- line
+				n++
+				if n == sniffLines {
+					close(s.first)
+				}
+			}
+			if _, werr := io.WriteString(s.pw, line); werr != nil {
+				break
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				_ = s.pw.CloseWithError(err)
+			}
+			break
+		}
+	}
+	if n < sniffLines {
+		close(s.first)
+	}
+	_ = s.pw.Close()
+}
+
+// lines returns the first lines of the source, waiting at most sniffTimeout.
+func (s *sniffer) lines() []string {
+	var lines []string
+	timer := time.NewTimer(sniffTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case l, ok := <-s.first:
+			if !ok {
+				return lines
+			}
+			lines = append(lines, l)
+		case <-timer.C:
+			return lines
+		}
+	}
+}
+
+// Close stops the copy and closes the source.
+func (s *sniffer) Close() error {
+	var err error
+	s.once.Do(func() {
+		_ = s.pr.Close()
+		err = s.source.Close()
+	})
+	return err
+}
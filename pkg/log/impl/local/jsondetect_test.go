@@ -0,0 +1,101 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logclient "github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONObjects(t *testing.T) {
+	object, ok := jsonObjects([]string{`{"msg":"a"}` + "\n", "\n", `{"msg":"b"}`})
+	assert.True(t, ok)
+	assert.Equal(t, "a", object["msg"])
+
+	_, ok = jsonObjects([]string{`{"msg":"a"}`, "plain text"})
+	assert.False(t, ok)
+
+	_, ok = jsonObjects([]string{`["not", "an", "object"]`})
+	assert.False(t, ok)
+
+	_, ok = jsonObjects(nil)
+	assert.False(t, ok)
+}
+
+func TestLocalClient_JSONAutoDetect(t *testing.T) {
+	lc, err := GetLogClient()
+	require.NoError(t, err)
+
+	get := func(t *testing.T, content string, fe logclient.FieldExtraction) logclient.LogSearchResult {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		result, err := lc.Get(context.Background(), &logclient.LogSearch{
+			FieldExtraction: fe,
+			Options:         ty.MI{OptionsFile: path},
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	ndjson := `{"@timestamp":"2024-01-02T03:04:05Z","severity":"ERROR","msg":"boom","user":"alice"}` + "\n" +
+		`{"@timestamp":"2024-01-02T03:04:06Z","severity":"INFO","msg":"ok","user":"bob"}` + "\n"
+
+	t.Run("infers the keys of JSON lines", func(t *testing.T) {
+		result := get(t, ndjson, logclient.FieldExtraction{JSONAutoDetect: ty.OptWrap(true)})
+
+		fe := result.GetSearch().FieldExtraction
+		assert.True(t, fe.JSON.Value)
+		assert.Equal(t, "@timestamp", fe.JSONTimestampKey.Value)
+		assert.Equal(t, "severity", fe.JSONLevelKey.Value)
+		assert.Equal(t, "msg", fe.JSONMessageKey.Value)
+
+		entries, _, err := result.GetEntries(context.Background())
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "boom", entries[0].Message)
+		assert.Equal(t, "ERROR", entries[0].Level)
+		assert.Equal(t, "alice", entries[0].Fields["user"])
+		assert.Equal(t, 2024, entries[0].Timestamp.Year())
+	})
+
+	t.Run("keeps the keys set", func(t *testing.T) {
+		result := get(t, ndjson, logclient.FieldExtraction{
+			JSONAutoDetect: ty.OptWrap(true),
+			JSONMessageKey: ty.OptWrap("user"),
+		})
+
+		fe := result.GetSearch().FieldExtraction
+		assert.Equal(t, "user", fe.JSONMessageKey.Value)
+		assert.Equal(t, "severity", fe.JSONLevelKey.Value)
+
+		entries, _, err := result.GetEntries(context.Background())
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "alice", entries[0].Message)
+	})
+
+	t.Run("leaves plain text alone", func(t *testing.T) {
+		result := get(t, "one\ntwo\n", logclient.FieldExtraction{JSONAutoDetect: ty.OptWrap(true)})
+
+		assert.False(t, result.GetSearch().FieldExtraction.JSON.Set)
+		entries, _, err := result.GetEntries(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "two"}, messages(entries))
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		result := get(t, ndjson, logclient.FieldExtraction{})
+
+		assert.False(t, result.GetSearch().FieldExtraction.JSON.Set)
+		entries, _, err := result.GetEntries(context.Background())
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Contains(t, entries[0].Message, `"msg":"boom"`)
+	})
+}
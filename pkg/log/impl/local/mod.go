@@ -26,6 +26,9 @@ const (
 	OptionsPaths = "paths"
 	// OptionsPreferNativeDriver when set to true, disables hl usage and forces the native Go engine.
 	OptionsPreferNativeDriver = "preferNativeDriver"
+	// OptionsFile is a file to read, and tail in follow mode, directly
+	// instead of running cmd.
+	OptionsFile = "file"
 
 	defaultShellWindows    = "powershell"
 	defaultShellArgWindows = "-Command"
@@ -55,6 +58,10 @@ func getCommand(search *client.LogSearch) (string, error) {
 }
 
 func (lc localLogClient) Get(ctx context.Context, search *client.LogSearch) (client.LogSearchResult, error) {
+	if file := search.Options.GetString(OptionsFile); file != "" {
+		return lc.getFile(ctx, search, file)
+	}
+
 	// Check if we should use hl (high-performance log viewer)
 	paths, hasPaths := search.Options.GetListOfStringsOk(OptionsPaths)
 	preferNative := search.Options.GetBool(OptionsPreferNativeDriver)
@@ -226,9 +233,8 @@ func (lc localLogClient) getWithNativeCmd(ctx context.Context, search *client.Lo
 		return nil, err
 	}
 
-	scanner := bufio.NewScanner(stdout)
-
-	return reader.GetLogResult(search, scanner, stdout)
+	search, out, closer := detectJSON(search, stdout, stdout)
+	return reader.GetLogResult(search, bufio.NewScanner(out), closer)
 }
 
 func (lc localLogClient) GetFieldValues(ctx context.Context, search *client.LogSearch, fields []string) (map[string][]string, error) {
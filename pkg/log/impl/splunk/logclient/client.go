@@ -20,6 +20,10 @@ import (
 // to dispatch on a fresh dev instance.
 const maxRetryDoneJob = 30
 
+// backendName tags the page tokens of job results, whose cursor is the offset
+// of the next event.
+const backendName = "splunk"
+
 // SplunkAuthOptions defines authentication headers.
 type SplunkAuthOptions struct {
 	Header ty.MS `json:"header" yaml:"header"`
@@ -57,6 +61,11 @@ func (s SplunkLogSearchClient) Get(ctx context.Context, search *client.LogSearch
 		s.options.SearchBody = ty.MS{}
 	}
 
+	search, err := s.resolveSavedSearch(search)
+	if err != nil {
+		return nil, err
+	}
+
 	searchRequest, err := getSearchRequest(search)
 	if err != nil {
 		return nil, err
@@ -113,7 +122,7 @@ func (s SplunkLogSearchClient) Get(ctx context.Context, search *client.LogSearch
 		case <-ctx.Done():
 			// When the job is done, we should cancel it
 			defer func() { _ = s.client.CancelSearchJob(searchJobResponse.Sid) }()
-			return nil, ctx.Err()
+			return s.partialResult(ctx, search, searchJobResponse.Sid, useResultsEndpoint)
 		case <-time.After(pollInterval):
 		}
 		log.Printf("waiting for splunk job %s to complete (try %d/%d)", searchJobResponse.Sid, tryCount+1, maxRetries)
@@ -140,19 +149,28 @@ func (s SplunkLogSearchClient) Get(ctx context.Context, search *client.LogSearch
 		tryCount++
 	}
 
+	return s.jobResult(search, searchJobResponse.Sid, useResultsEndpoint)
+}
+
+// jobResult fetches the events of the job sid from the offset of the page
+// token of search.
+func (s SplunkLogSearchClient) jobResult(search *client.LogSearch, sid string, useResultsEndpoint bool) (SplunkLogSearchResult, error) {
 	offset := 0
-	if search.PageToken.Value != "" {
-		var err error
-		offset, err = strconv.Atoi(search.PageToken.Value)
+	cursor, err := client.PageCursor(search, backendName)
+	if err != nil {
+		return SplunkLogSearchResult{}, err
+	}
+	if cursor != "" {
+		offset, err = strconv.Atoi(cursor)
 		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %w", err)
+			return SplunkLogSearchResult{}, fmt.Errorf("invalid page token: %w", err)
 		}
 	}
 
-	firstResult, err := s.client.GetSearchResult(searchJobResponse.Sid, offset, search.Size.Value, useResultsEndpoint)
+	firstResult, err := s.client.GetSearchResult(sid, offset, search.Size.Value, useResultsEndpoint)
 
 	if err != nil {
-		return nil, err
+		return SplunkLogSearchResult{}, err
 	}
 
 	// Determine size limit for enforcing in GetEntries
@@ -164,7 +182,7 @@ func (s SplunkLogSearchClient) Get(ctx context.Context, search *client.LogSearch
 	return SplunkLogSearchResult{
 		logClient:          &s,
 		search:             search,
-		sid:                searchJobResponse.Sid,
+		sid:                sid,
 		results:            []restapi.SearchResultsResponse{firstResult},
 		CurrentOffset:      offset,
 		useResultsEndpoint: useResultsEndpoint,
@@ -172,6 +190,37 @@ func (s SplunkLogSearchClient) Get(ctx context.Context, search *client.LogSearch
 	}, nil
 }
 
+// partialResult returns the events the job sid found before ctx timed out,
+// with a partial result error, or the error of ctx when there is none.
+func (s SplunkLogSearchClient) partialResult(ctx context.Context, search *client.LogSearch, sid string, useResultsEndpoint bool) (client.LogSearchResult, error) {
+	if !client.IsTimeout(ctx.Err()) {
+		return nil, ctx.Err()
+	}
+	// A running job already serves the events it found
+	result, err := s.jobResult(search, sid, useResultsEndpoint)
+	if err != nil || len(result.results[0].Results) == 0 {
+		return nil, ctx.Err()
+	}
+	result.partialErr = ctx.Err()
+	return result, nil
+}
+
+// resolveSavedSearch replaces the `savedsearch` option with the saved search SPL.
+// The search is returned unchanged when the option is not set.
+func (s SplunkLogSearchClient) resolveSavedSearch(search *client.LogSearch) (*client.LogSearch, error) {
+	name := getSavedSearchName(search)
+	if name == "" {
+		return search, nil
+	}
+
+	saved, err := s.client.GetSavedSearch(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return applySavedSearch(search, saved)
+}
+
 // GetFieldValues retrieves distinct values for the specified fields.
 func (s SplunkLogSearchClient) GetFieldValues(ctx context.Context, search *client.LogSearch, fields []string) (map[string][]string, error) {
 	if s.options.Headers == nil {
@@ -181,6 +230,11 @@ func (s SplunkLogSearchClient) GetFieldValues(ctx context.Context, search *clien
 		s.options.SearchBody = ty.MS{}
 	}
 
+	search, err := s.resolveSavedSearch(search)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build the base search request
 	searchRequest, err := getSearchRequest(search)
 	if err != nil {
@@ -211,9 +265,83 @@ func (s SplunkLogSearchClient) GetFieldValues(ctx context.Context, search *clien
 	}
 	query := baseQuery + fmt.Sprintf(" | stats limit=%d ", maxValues) + strings.Join(valuesClauses, ", ")
 
+	results, err := s.runStatsSearch(ctx, query, searchRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract distinct values from the single result row
+	// The stats values() command returns a multivalue field (array) for each field
+	result := make(map[string][]string)
+	for _, field := range fields {
+		result[field] = []string{} // Initialize with empty slice
+	}
+
+	if len(results.Results) > 0 {
+		row := results.Results[0]
+		for _, field := range fields {
+			if v, ok := row[field]; ok {
+				// Handle multivalue field - can be a single value or an array
+				switch val := v.(type) {
+				case []interface{}:
+					for _, item := range val {
+						result[field] = append(result[field], fmt.Sprintf("%v", item))
+					}
+				case string:
+					if val != "" {
+						result[field] = []string{val}
+					}
+				default:
+					if val != nil {
+						result[field] = []string{fmt.Sprintf("%v", val)}
+					}
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Count returns the number of events matching search with `| stats count`.
+func (s SplunkLogSearchClient) Count(ctx context.Context, search *client.LogSearch) (int, error) {
+	if s.options.Headers == nil {
+		s.options.Headers = ty.MS{}
+	}
+	if s.options.SearchBody == nil {
+		s.options.SearchBody = ty.MS{}
+	}
+
+	search, err := s.resolveSavedSearch(search)
+	if err != nil {
+		return 0, err
+	}
+
+	searchRequest, err := getSearchRequest(search)
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := s.runStatsSearch(ctx, searchRequest["search"]+" | stats count", searchRequest)
+	if err != nil {
+		return 0, err
+	}
+	if len(results.Results) == 0 {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(fmt.Sprintf("%v", results.Results[0]["count"]))
+	if err != nil {
+		return 0, fmt.Errorf("invalid splunk count %v: %w", results.Results[0]["count"], err)
+	}
+	return count, nil
+}
+
+// runStatsSearch runs query, ending with a transforming command, over the
+// time range of searchRequest and returns its first result row.
+func (s SplunkLogSearchClient) runStatsSearch(ctx context.Context, query string, searchRequest ty.MS) (restapi.SearchResultsResponse, error) {
 	searchJobResponse, err := s.client.CreateSearchJob(query, searchRequest["earliest_time"], searchRequest["latest_time"], false, s.options.Headers, s.options.SearchBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create search job: %w", err)
+		return restapi.SearchResultsResponse{}, fmt.Errorf("failed to create search job: %w", err)
 	}
 
 	// Wait for job to complete
@@ -231,14 +359,14 @@ func (s SplunkLogSearchClient) GetFieldValues(ctx context.Context, search *clien
 		select {
 		case <-ctx.Done():
 			_ = s.client.CancelSearchJob(searchJobResponse.Sid)
-			return nil, ctx.Err()
+			return restapi.SearchResultsResponse{}, ctx.Err()
 		case <-time.After(pollInterval):
 		}
 
 		status, err := s.client.GetSearchStatus(searchJobResponse.Sid)
 		if err != nil {
 			_ = s.client.CancelSearchJob(searchJobResponse.Sid)
-			return nil, err
+			return restapi.SearchResultsResponse{}, err
 		}
 
 		if len(status.Entry) > 0 {
@@ -251,47 +379,16 @@ func (s SplunkLogSearchClient) GetFieldValues(ctx context.Context, search *clien
 
 	if !isDone {
 		_ = s.client.CancelSearchJob(searchJobResponse.Sid)
-		return nil, fmt.Errorf("timeout waiting for splunk job")
+		return restapi.SearchResultsResponse{}, fmt.Errorf("timeout waiting for splunk job")
 	}
 
 	// Get results from /results endpoint since we're using stats
 	results, err := s.client.GetSearchResult(searchJobResponse.Sid, 0, 1, true)
 	_ = s.client.CancelSearchJob(searchJobResponse.Sid)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get results: %w", err)
+		return restapi.SearchResultsResponse{}, fmt.Errorf("failed to get results: %w", err)
 	}
-
-	// Extract distinct values from the single result row
-	// The stats values() command returns a multivalue field (array) for each field
-	result := make(map[string][]string)
-	for _, field := range fields {
-		result[field] = []string{} // Initialize with empty slice
-	}
-
-	if len(results.Results) > 0 {
-		row := results.Results[0]
-		for _, field := range fields {
-			if v, ok := row[field]; ok {
-				// Handle multivalue field - can be a single value or an array
-				switch val := v.(type) {
-				case []interface{}:
-					for _, item := range val {
-						result[field] = append(result[field], fmt.Sprintf("%v", item))
-					}
-				case string:
-					if val != "" {
-						result[field] = []string{val}
-					}
-				default:
-					if val != nil {
-						result[field] = []string{fmt.Sprintf("%v", val)}
-					}
-				}
-			}
-		}
-	}
-
-	return result, nil
+	return results, nil
 }
 
 // getFieldValuesFromSearch falls back to getting field values from a regular search
@@ -128,7 +128,7 @@ func TestSplunkLogSearchResult_GetPaginationInfo(t *testing.T) {
 		paginationInfo := result.GetPaginationInfo()
 		assert.NotNil(t, paginationInfo)
 		assert.True(t, paginationInfo.HasMore)
-		assert.Equal(t, "10", paginationInfo.NextPageToken)
+		assert.Equal(t, client.EncodePageToken("splunk", "10"), paginationInfo.NextPageToken)
 	})
 
 	t.Run("with existing page token", func(t *testing.T) {
@@ -146,7 +146,7 @@ func TestSplunkLogSearchResult_GetPaginationInfo(t *testing.T) {
 		paginationInfo := result.GetPaginationInfo()
 		assert.NotNil(t, paginationInfo)
 		assert.True(t, paginationInfo.HasMore)
-		assert.Equal(t, "20", paginationInfo.NextPageToken)
+		assert.Equal(t, client.EncodePageToken("splunk", "20"), paginationInfo.NextPageToken)
 	})
 
 	t.Run("invalid page token", func(t *testing.T) {
@@ -163,7 +163,7 @@ func TestSplunkLogSearchResult_GetPaginationInfo(t *testing.T) {
 		paginationInfo := result.GetPaginationInfo()
 		assert.NotNil(t, paginationInfo)
 		assert.True(t, paginationInfo.HasMore)
-		assert.Equal(t, "10", paginationInfo.NextPageToken)
+		assert.Equal(t, client.EncodePageToken("splunk", "10"), paginationInfo.NextPageToken)
 	})
 }
 
@@ -197,3 +197,97 @@ func TestSplunkLogSearchClient_Get_Follow(t *testing.T) {
 
 	assert.True(t, gock.IsDone())
 }
+
+func TestSplunkLogSearchClient_Get_TimeoutKeepsEvents(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://splunk.com:8080").
+		Post("/search/jobs").
+		Reply(200).
+		JSON(ty.MI{"sid": "slow-sid"})
+	gock.New("http://splunk.com:8080").
+		Get("/search/jobs/slow-sid/events").
+		Reply(200).
+		JSON(ty.MI{"results": []ty.MS{{"_raw": "found early", "_time": "2024-06-21T08:56:05.681-07:00"}}})
+	gock.New("http://splunk.com:8080").
+		Delete("/search/jobs/slow-sid").
+		Reply(200)
+
+	logClient, err := GetClient(SplunkLogSearchClientOptions{URL: "http://splunk.com:8080"})
+	assert.NoError(t, err)
+
+	// The job is still running when the deadline is reached
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	logSearch := client.LogSearch{}
+	logSearch.Range.Last.S("15m")
+	result, err := logClient.Get(ctx, &logSearch)
+	assert.NoError(t, err)
+
+	entries, _, err := result.GetEntries(ctx)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "found early", entries[0].Message)
+	assert.True(t, client.IsPartial(err))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, gock.IsDone(), "the job is cancelled")
+}
+
+func TestSplunkLogSearchClient_Get_SavedSearchNotFound(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://splunk.com:8080").
+		Get("/saved/searches/Missing").
+		Reply(404).
+		JSON(ty.MI{"messages": []ty.MI{{"type": "ERROR", "text": "Could not find object id=Missing Search"}}})
+
+	logClient, err := GetClient(SplunkLogSearchClientOptions{
+		URL: "http://splunk.com:8080",
+	})
+	assert.NoError(t, err)
+
+	logSearch := client.LogSearch{
+		Options: ty.MI{"savedsearch": `"Missing Search"`},
+	}
+
+	_, err = logClient.Get(context.Background(), &logSearch)
+	assert.ErrorIs(t, err, restapi.ErrSavedSearchNotFound)
+	assert.Contains(t, err.Error(), "Missing Search")
+	assert.True(t, gock.IsDone())
+}
+
+func TestSplunkLogSearchClient_Count(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://splunk.com:8080").
+		Post("/search/jobs").
+		BodyString(`stats\+count`).
+		Reply(200).
+		JSON(ty.MI{"Sid": "countsid"})
+
+	gock.New("http://splunk.com:8080").
+		Get("/search/jobs/countsid").
+		Reply(200).
+		JSON(ty.MI{"entry": []ty.MI{{"content": ty.MI{"isDone": true}}}})
+
+	gock.New("http://splunk.com:8080").
+		Get("/search/jobs/countsid/results").
+		Reply(200).
+		JSON(ty.MI{"results": []ty.MS{{"count": "42"}}})
+
+	gock.New("http://splunk.com:8080").
+		Delete("/search/jobs/countsid").
+		Reply(200)
+
+	logClient, err := GetClient(SplunkLogSearchClientOptions{
+		URL: "http://splunk.com:8080",
+	})
+	assert.NoError(t, err)
+
+	logSearch := client.LogSearch{Fields: ty.MS{"level": "ERROR"}, Options: ty.MI{"index": "main"}}
+	logSearch.Range.Last.S("1h")
+
+	count, err := logClient.(client.Counter).Count(context.Background(), &logSearch)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+	assert.True(t, gock.IsDone())
+}
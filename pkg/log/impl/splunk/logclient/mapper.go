@@ -3,10 +3,13 @@ package logclient
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/log/client/operator"
+	"github.com/bascanada/logviewer/pkg/log/impl/splunk/restapi"
 	"github.com/bascanada/logviewer/pkg/ty"
 )
 
@@ -32,6 +35,12 @@ var transformingCommandPattern *regexp.Regexp
 // "| fields" or "| fields -" IS transforming (changes event structure)
 var fieldsCommandPattern *regexp.Regexp
 
+// savedSearchTokenRegex matches $token$ placeholders in parameterized saved searches.
+var savedSearchTokenRegex = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_.]*)\$`)
+
+// splunkTimeModifierRegex matches inline earliest=/latest= time modifiers in SPL.
+var splunkTimeModifierRegex = regexp.MustCompile(`(?i)(?:^|[\s(])(?:earliest|latest)\s*=`)
+
 func init() {
 	// Build pattern: | followed by optional whitespace, then one of the commands as a word
 	pattern := `\|\s*(` + strings.Join(transformingCommands, "|") + `)(?:\s|$)`
@@ -59,6 +68,16 @@ func escapeSplunkValue(value string) string {
 	return strings.ReplaceAll(value, "\"", "\\\"")
 }
 
+// wildcardPattern returns the Splunk pattern of a wildcard value: a value
+// with its own * is used verbatim, e.g. web-*-prod, and a plain one matches
+// as a prefix. The pattern is quoted, so literal parts may hold spaces.
+func wildcardPattern(value string) string {
+	if strings.Contains(value, "*") {
+		return value
+	}
+	return value + "*"
+}
+
 // buildSplunkCondition builds a single condition for Splunk search.
 // Returns the condition string and a boolean indicating if it's a regex (needs pipe).
 func buildSplunkCondition(f *client.Filter) (condition string, isRegex bool) {
@@ -91,9 +110,15 @@ func buildSplunkCondition(f *client.Filter) (condition string, isRegex bool) {
 			cond = fmt.Sprintf(`regex %s="%s"`, f.Field, escapeSplunkValue(f.Value))
 			isRegexCond = true
 		case operator.Wildcard:
-			cond = fmt.Sprintf(`%s="%s*"`, f.Field, escapeSplunkValue(f.Value))
+			cond = fmt.Sprintf(`%s="%s"`, f.Field, escapeSplunkValue(wildcardPattern(f.Value)))
 		case operator.Exists:
 			cond = fmt.Sprintf(`%s=*`, f.Field)
+		case operator.NotExists:
+			if f.Negate {
+				cond = fmt.Sprintf(`%s=*`, f.Field)
+			} else {
+				cond = fmt.Sprintf(`NOT %s=*`, f.Field)
+			}
 		case operator.Gt:
 			cond = fmt.Sprintf(`%s>%s`, f.Field, f.Value)
 		case operator.Gte:
@@ -107,8 +132,8 @@ func buildSplunkCondition(f *client.Filter) (condition string, isRegex bool) {
 		}
 	}
 
-	// Handle negation
-	if f.Negate {
+	// Handle negation (already folded into not_exists above)
+	if f.Negate && op != operator.NotExists {
 		if isRegexCond {
 			// The `regex` command doesn't support inline negation.
 			// Use `where NOT match(...)` for negated regex, which is valid SPL.
@@ -119,7 +144,8 @@ func buildSplunkCondition(f *client.Filter) (condition string, isRegex bool) {
 			cond = fmt.Sprintf(`where NOT match(%s, "%s")`, field, escapeSplunkValue(f.Value))
 			isRegexCond = false // where command is not a regex pipe command
 		} else {
-			cond = fmt.Sprintf("NOT (%s)", cond)
+			// A leaf is a single term, NOT needs no parentheses
+			cond = "NOT " + cond
 		}
 	}
 
@@ -195,19 +221,116 @@ func trimTrailingPipe(query string) string {
 	return query
 }
 
+// getSavedSearchName returns the saved search name from the `savedsearch`
+// option, with surrounding quotes removed (e.g. savedsearch:"My Search").
+func getSavedSearchName(logSearch *client.LogSearch) string {
+	name := strings.TrimSpace(logSearch.Options.GetString("savedsearch"))
+	return strings.Trim(name, `"'`)
+}
+
+// applySavedSearch returns a copy of logSearch that uses the saved search SPL as
+// its native query. $token$ placeholders are replaced from the `savedsearchArgs`
+// option. The saved search dispatch time range is used only when the search has
+// no time range of its own. Fields and Filter are appended as usual by
+// getSearchRequest.
+func applySavedSearch(logSearch *client.LogSearch, saved restapi.SavedSearchContent) (*client.LogSearch, error) {
+	name := getSavedSearchName(logSearch)
+	if logSearch.NativeQuery.Set && logSearch.NativeQuery.Value != "" {
+		return nil, fmt.Errorf("savedsearch %q cannot be combined with a native query", name)
+	}
+
+	args := logSearch.Options.GetMS("savedsearchArgs")
+
+	var missing []string
+	spl := savedSearchTokenRegex.ReplaceAllStringFunc(saved.Search, func(token string) string {
+		key := strings.Trim(token, "$")
+		if v, ok := args[key]; ok {
+			return v
+		}
+		missing = append(missing, key)
+		return token
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("savedsearch %q requires arguments: %s (set them in savedsearchArgs)", name, strings.Join(missing, ", "))
+	}
+	spl = strings.TrimSpace(spl)
+	// Saved searches are stored with an implicit leading "search" command;
+	// CreateSearchJob adds it back.
+	spl = strings.TrimPrefix(spl, "search ")
+
+	resolved := logSearch.Clone()
+	resolved.NativeQuery.S(spl)
+	// The saved search is resolved; drop the option so it isn't resolved twice.
+	delete(resolved.Options, "savedsearch")
+
+	hasRange := logSearch.Range.Last.Value != "" || logSearch.Range.Gte.Value != "" || logSearch.Range.Lte.Value != ""
+	if !hasRange {
+		if saved.EarliestTime != "" {
+			resolved.Range.Gte.S(saved.EarliestTime)
+		}
+		if saved.LatestTime != "" {
+			resolved.Range.Lte.S(saved.LatestTime)
+		}
+	}
+
+	return resolved, nil
+}
+
+// nativeQueryHasTimeRange reports whether the SPL already uses earliest= or
+// latest= time modifiers.
+func nativeQueryHasTimeRange(query string) bool {
+	return splunkTimeModifierRegex.MatchString(query)
+}
+
+// splunkTime converts an absolute bound to the epoch seconds Splunk
+// dispatches on. Values that aren't RFC3339, like the relative "-24h@h" or
+// "now", are Splunk time modifiers and pass through.
+func splunkTime(value string) string {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return value
+	}
+	return splunkEpoch(t)
+}
+
+func splunkEpoch(t time.Time) string {
+	ms := t.UnixMilli()
+	if ms%1000 == 0 {
+		return strconv.FormatInt(ms/1000, 10)
+	}
+	return fmt.Sprintf("%d.%03d", ms/1000, ms%1000)
+}
+
 func getSearchRequest(logSearch *client.LogSearch) (ty.MS, error) {
 	ms := ty.MS{
-		"earliest_time": logSearch.Range.Gte.Value,
-		"latest_time":   logSearch.Range.Lte.Value,
+		"earliest_time": splunkTime(logSearch.Range.Gte.Value),
+		"latest_time":   splunkTime(logSearch.Range.Lte.Value),
 	}
 
-	// If the caller provided a `last` duration (e.g. "1min"), prefer that
-	// over explicit gte/lte. We translate it to an earliest_time of
+	// A `last` duration (e.g. "1min") is translated to an earliest_time of
 	// "-<last>" and latest_time of "now" which Splunk understands as a
-	// relative time window.
-	if logSearch.Range.Last.Value != "" {
+	// relative time window. An explicit gte wins over it, and with an
+	// absolute lte the window ends at lte.
+	if logSearch.Range.Last.Value != "" && logSearch.Range.Gte.Value == "" {
 		ms["earliest_time"] = "-" + logSearch.Range.Last.Value
-		ms["latest_time"] = "now"
+		if logSearch.Range.Lte.Value == "" {
+			ms["latest_time"] = "now"
+		} else if lte, err := time.Parse(time.RFC3339Nano, logSearch.Range.Lte.Value); err == nil {
+			if d, err := time.ParseDuration(logSearch.Range.Last.Value); err == nil {
+				ms["earliest_time"] = splunkEpoch(lte.Add(-d))
+			}
+		}
+	}
+
+	// In native-only mode the SPL is sent untouched. If it carries its own
+	// earliest=/latest= modifiers, those win over the logviewer time range.
+	if logSearch.IsNativeQueryOnly() {
+		if nativeQueryHasTimeRange(logSearch.NativeQuery.Value) {
+			delete(ms, "earliest_time")
+			delete(ms, "latest_time")
+		}
+		ms["search"] = trimTrailingPipe(logSearch.NativeQuery.Value)
+		return ms, nil
 	}
 
 	var query strings.Builder
@@ -5,6 +5,7 @@ import (
 
 	"github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/log/client/operator"
+	"github.com/bascanada/logviewer/pkg/log/impl/splunk/restapi"
 	"github.com/bascanada/logviewer/pkg/ty"
 	"github.com/stretchr/testify/assert"
 )
@@ -182,6 +183,39 @@ func TestSearchRequest(t *testing.T) {
 		assert.Equal(t, "now", requestBodyFields["latest_time"])
 	})
 
+	t.Run("time range", func(t *testing.T) {
+		cases := []struct {
+			name                     string
+			last, gte, lte           string
+			wantEarliest, wantLatest string
+		}{
+			{name: "relative only", last: "1h", wantEarliest: "-1h", wantLatest: "now"},
+			{name: "absolute only", gte: "2024-01-02T03:04:05Z", lte: "2024-01-02T04:04:05.250+01:00", wantEarliest: "1704164645", wantLatest: "1704164645.250"},
+			{name: "splunk modifiers pass through", gte: "-24h@h", lte: "now", wantEarliest: "-24h@h", wantLatest: "now"},
+			{name: "gte wins over last", last: "1h", gte: "2024-01-02T03:04:05Z", wantEarliest: "1704164645", wantLatest: ""},
+			{name: "last ends at lte", last: "1h", lte: "2024-01-02T03:04:05Z", wantEarliest: "1704161045", wantLatest: "1704164645"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				logSearch := &client.LogSearch{Options: ty.MI{"index": "nonprod"}}
+				if tc.last != "" {
+					logSearch.Range.Last.S(tc.last)
+				}
+				if tc.gte != "" {
+					logSearch.Range.Gte.S(tc.gte)
+				}
+				if tc.lte != "" {
+					logSearch.Range.Lte.S(tc.lte)
+				}
+
+				requestBodyFields, err := getSearchRequest(logSearch)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.wantEarliest, requestBodyFields["earliest_time"])
+				assert.Equal(t, tc.wantLatest, requestBodyFields["latest_time"])
+			})
+		}
+	})
+
 	// Tests for new recursive Filter AST
 	t.Run("recursive filter - simple AND", func(t *testing.T) {
 		logSearch := &client.LogSearch{
@@ -340,6 +374,28 @@ func TestSearchRequest(t *testing.T) {
 		assert.Contains(t, requestBodyFields["search"], `trace_id=*`)
 	})
 
+	t.Run("recursive filter - not_exists operator", func(t *testing.T) {
+		logSearch := &client.LogSearch{
+			Filter: &client.Filter{
+				Field: "trace_id",
+				Op:    operator.NotExists,
+			},
+			Options: ty.MI{"index": "nonprod"},
+		}
+		logSearch.Range.Gte.S("24h@h")
+		logSearch.Range.Lte.S("now")
+
+		requestBodyFields, err := getSearchRequest(logSearch)
+		assert.NoError(t, err)
+		assert.Contains(t, requestBodyFields["search"], `NOT trace_id=*`)
+
+		logSearch.Filter.Negate = true
+		requestBodyFields, err = getSearchRequest(logSearch)
+		assert.NoError(t, err)
+		assert.Contains(t, requestBodyFields["search"], `trace_id=*`)
+		assert.NotContains(t, requestBodyFields["search"], `NOT`)
+	})
+
 	// Tests for NativeQuery support
 	t.Run("native query - standalone", func(t *testing.T) {
 		logSearch := &client.LogSearch{
@@ -653,6 +709,48 @@ func TestSearchRequest_HLCompatibleOperators(t *testing.T) {
 		assert.Contains(t, requestBodyFields["search"], `latency_ms>=500`)
 		assert.Contains(t, requestBodyFields["search"], `latency_ms<2000`)
 	})
+
+	t.Run("native only ignores fields and filter", func(t *testing.T) {
+		logSearch := &client.LogSearch{
+			NativeQueryOnly: true,
+			Fields:          ty.MS{"level": "ERROR"},
+			Filter:          &client.Filter{Field: "app", Op: operator.Equals, Value: "api"},
+			Options:         ty.MI{"index": "main", "fields": []string{"host"}},
+		}
+		logSearch.NativeQuery.S("index=prod sourcetype=access | stats count by status |")
+		logSearch.Range.Last.S("1h")
+
+		requestBodyFields, err := getSearchRequest(logSearch)
+		assert.NoError(t, err)
+		assert.Equal(t, "index=prod sourcetype=access | stats count by status", requestBodyFields["search"])
+		assert.Equal(t, "-1h", requestBodyFields["earliest_time"])
+		assert.Equal(t, "now", requestBodyFields["latest_time"])
+	})
+
+	t.Run("native only keeps inline time modifiers", func(t *testing.T) {
+		logSearch := &client.LogSearch{NativeQueryOnly: true}
+		logSearch.NativeQuery.S("index=prod earliest=-7d@d latest=now error")
+		logSearch.Range.Last.S("15m")
+
+		requestBodyFields, err := getSearchRequest(logSearch)
+		assert.NoError(t, err)
+		assert.Equal(t, "index=prod earliest=-7d@d latest=now error", requestBodyFields["search"])
+		assert.NotContains(t, requestBodyFields, "earliest_time")
+		assert.NotContains(t, requestBodyFields, "latest_time")
+	})
+
+	t.Run("native only without native query keeps filters", func(t *testing.T) {
+		logSearch := &client.LogSearch{
+			NativeQueryOnly: true,
+			Fields:          ty.MS{"level": "ERROR"},
+			Options:         ty.MI{"index": "main"},
+		}
+		logSearch.Range.Last.S("1h")
+
+		requestBodyFields, err := getSearchRequest(logSearch)
+		assert.NoError(t, err)
+		assert.Equal(t, `index=main level="ERROR"`, requestBodyFields["search"])
+	})
 }
 
 func TestContainsTransformingCommand(t *testing.T) {
@@ -707,3 +805,119 @@ func TestContainsTransformingCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestApplySavedSearch(t *testing.T) {
+	t.Run("saved search becomes the base query with filters appended", func(t *testing.T) {
+		logSearch := &client.LogSearch{
+			Fields:  ty.MS{"level": "ERROR"},
+			Options: ty.MI{"savedsearch": `"Payment Errors"`, "index": "ignored"},
+		}
+		logSearch.Range.Last.S("1h")
+
+		resolved, err := applySavedSearch(logSearch, restapi.SavedSearchContent{Search: "search index=payments sourcetype=json", EarliestTime: "-7d", LatestTime: "now"})
+		assert.NoError(t, err)
+
+		requestBodyFields, err := getSearchRequest(resolved)
+		assert.NoError(t, err)
+		assert.Equal(t, `index=payments sourcetype=json | search level="ERROR"`, requestBodyFields["search"])
+		// logviewer's own time range wins
+		assert.Equal(t, "-1h", requestBodyFields["earliest_time"])
+		assert.Equal(t, "now", requestBodyFields["latest_time"])
+		// the original search is untouched
+		assert.False(t, logSearch.NativeQuery.Set)
+		assert.Equal(t, `"Payment Errors"`, logSearch.Options["savedsearch"])
+	})
+
+	t.Run("saved search time range used when none specified", func(t *testing.T) {
+		logSearch := &client.LogSearch{Options: ty.MI{"savedsearch": "Nightly"}}
+
+		resolved, err := applySavedSearch(logSearch, restapi.SavedSearchContent{Search: "index=batch", EarliestTime: "-24h@h", LatestTime: "@h"})
+		assert.NoError(t, err)
+		assert.Equal(t, "-24h@h", resolved.Range.Gte.Value)
+		assert.Equal(t, "@h", resolved.Range.Lte.Value)
+	})
+
+	t.Run("parameterized saved search", func(t *testing.T) {
+		logSearch := &client.LogSearch{Options: ty.MI{
+			"savedsearch":     "By Host",
+			"savedsearchArgs": ty.MI{"host": "web-01"},
+		}}
+		logSearch.Range.Last.S("15m")
+
+		resolved, err := applySavedSearch(logSearch, restapi.SavedSearchContent{Search: "index=main host=$host$"})
+		assert.NoError(t, err)
+		assert.Equal(t, "index=main host=web-01", resolved.NativeQuery.Value)
+	})
+
+	t.Run("missing saved search arguments", func(t *testing.T) {
+		logSearch := &client.LogSearch{Options: ty.MI{"savedsearch": "By Host"}}
+
+		_, err := applySavedSearch(logSearch, restapi.SavedSearchContent{Search: "index=main host=$host$ app=$app$"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "host, app")
+	})
+
+	t.Run("cannot combine with native query", func(t *testing.T) {
+		logSearch := &client.LogSearch{Options: ty.MI{"savedsearch": "By Host"}}
+		logSearch.NativeQuery.S("index=main")
+
+		_, err := applySavedSearch(logSearch, restapi.SavedSearchContent{Search: "index=main"})
+		assert.Error(t, err)
+	})
+}
+
+func TestSearchRequest_NegationAndWildcards(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *client.Filter
+		want   string
+	}{
+		{
+			name:   "negated equals",
+			filter: &client.Filter{Field: "level", Value: "DEBUG", Negate: true},
+			want:   `index=main NOT level="DEBUG"`,
+		},
+		{
+			name:   "mid-string wildcard",
+			filter: &client.Filter{Field: "host", Op: operator.Wildcard, Value: "web-*-prod"},
+			want:   `index=main host="web-*-prod"`,
+		},
+		{
+			name:   "wildcard with spaces around the literal parts",
+			filter: &client.Filter{Field: "app", Op: operator.Wildcard, Value: "pet store * v2"},
+			want:   `index=main app="pet store * v2"`,
+		},
+		{
+			name:   "trailing wildcard is not doubled",
+			filter: &client.Filter{Field: "host", Op: operator.Wildcard, Value: "web-*"},
+			want:   `index=main host="web-*"`,
+		},
+		{
+			name:   "negated wildcard",
+			filter: &client.Filter{Field: "host", Op: operator.Wildcard, Value: "*-canary-*", Negate: true},
+			want:   `index=main NOT host="*-canary-*"`,
+		},
+		{
+			name: "combined conditions",
+			filter: &client.Filter{Logic: client.LogicAnd, Filters: []client.Filter{
+				{Field: "host", Op: operator.Wildcard, Value: "web-*-prod"},
+				{Field: "status", Value: "200", Negate: true},
+				{Logic: client.LogicOr, Filters: []client.Filter{
+					{Field: "level", Value: "ERROR"},
+					{Field: "message", Value: "time out", Negate: true},
+				}},
+			}},
+			want: `index=main (host="web-*-prod" NOT status="200" (level="ERROR" OR NOT message="time out"))`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logSearch := &client.LogSearch{Filter: tc.filter, Options: ty.MI{"index": "main"}}
+			logSearch.Range.Last.S("1h")
+
+			requestBodyFields, err := getSearchRequest(logSearch)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, requestBodyFields["search"])
+		})
+	}
+}
@@ -30,6 +30,9 @@ type SplunkLogSearchResult struct {
 	useResultsEndpoint bool
 	// sizeLimit enforces max number of entries to return (0 = no limit)
 	sizeLimit int
+	// partialErr is the timeout that cut the job short, its events being
+	// the ones found until then
+	partialErr error
 }
 
 // GetSearch returns the search configuration.
@@ -54,7 +57,7 @@ func (s SplunkLogSearchResult) GetEntries(ctx context.Context) ([]client.LogEntr
 		if s.sizeLimit > 0 && len(entries) > s.sizeLimit {
 			entries = entries[:s.sizeLimit]
 		}
-		return entries, nil, nil
+		return entries, nil, client.PartialOnTimeout(entries, s.partialErr)
 	}
 
 	entryChan := make(chan []client.LogEntry)
@@ -121,6 +124,27 @@ func (s SplunkLogSearchResult) GetFields(_ context.Context) (ty.UniSet[string],
 	return fields, nil, nil
 }
 
+// GetFieldNames lists the unique field names from the search results without
+// collecting their values.
+func (s SplunkLogSearchResult) GetFieldNames(_ context.Context) ([]string, error) {
+	seen := map[string]struct{}{}
+	for _, resultEntry := range s.results {
+		for _, result := range resultEntry.Results {
+			for k, v := range result {
+				if k[0] == '_' {
+					continue
+				}
+				ty.AddFieldName(k, v, seen)
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for k := range seen {
+		names = append(names, k)
+	}
+	return names, nil
+}
+
 // GetPaginationInfo returns information for fetching the next page.
 func (s SplunkLogSearchResult) GetPaginationInfo() *client.PaginationInfo {
 	if s.isFollow || !s.search.Size.Set {
@@ -141,7 +165,7 @@ func (s SplunkLogSearchResult) GetPaginationInfo() *client.PaginationInfo {
 
 	return &client.PaginationInfo{
 		HasMore:       true,
-		NextPageToken: strconv.Itoa(currentOffset + numResults),
+		NextPageToken: client.EncodePageToken(backendName, strconv.Itoa(currentOffset+numResults)),
 	}
 }
 
@@ -2,8 +2,11 @@
 package restapi
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	nethttp "net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -30,6 +33,25 @@ type SearchResultsResponse struct {
 	Results []ty.MI `json:"results"`
 }
 
+// SavedSearchContent holds the parts of a saved search definition used to run it.
+type SavedSearchContent struct {
+	Search       string `json:"search"`
+	EarliestTime string `json:"dispatch.earliest_time"`
+	LatestTime   string `json:"dispatch.latest_time"`
+}
+
+// SavedSearchResponse holds the response for a saved search lookup.
+type SavedSearchResponse struct {
+	Entry []struct {
+		Name    string             `json:"name"`
+		Content SavedSearchContent `json:"content"`
+	} `json:"entry"`
+}
+
+// ErrSavedSearchNotFound is returned when a saved search does not exist or is
+// not visible to the authenticated user.
+var ErrSavedSearchNotFound = errors.New("splunk saved search not found")
+
 // SplunkTarget describes the connection target for a Splunk client.
 type SplunkTarget struct {
 	Endpoint string `json:"endpoint"`
@@ -148,6 +170,30 @@ func (src SplunkRestClient) GetSearchStatus(
 	return response, err
 }
 
+// GetSavedSearch retrieves the definition of a saved search by name.
+func (src SplunkRestClient) GetSavedSearch(name string) (SavedSearchContent, error) {
+	var response SavedSearchResponse
+
+	searchPath := fmt.Sprintf("/saved/searches/%s", url.PathEscape(name))
+
+	queryParams := ty.MS{
+		"output_mode": "json",
+	}
+
+	err := src.client.Get(searchPath, queryParams, src.target.Headers, nil, &response, src.target.Auth)
+	if err != nil {
+		var statusErr *http.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == nethttp.StatusNotFound {
+			return SavedSearchContent{}, fmt.Errorf("%w: %q", ErrSavedSearchNotFound, name)
+		}
+		return SavedSearchContent{}, fmt.Errorf("failed to get splunk saved search %q: %w", name, err)
+	}
+	if len(response.Entry) == 0 || response.Entry[0].Content.Search == "" {
+		return SavedSearchContent{}, fmt.Errorf("%w: %q", ErrSavedSearchNotFound, name)
+	}
+	return response.Entry[0].Content, nil
+}
+
 // GetSearchResult retrieves the results of a search job in Splunk.
 func (src SplunkRestClient) GetSearchResult(
 	sid string,
@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
@@ -40,11 +41,19 @@ type LogClientOptions struct {
 
 	PrivateKey string `json:"privateKey"`
 	DisablePTY bool   `json:"disablePTY"`
+
+	// ReconnectMaxAttempts is the number of reconnections tried when the
+	// connection of a followed command drops, DefaultReconnectPolicy's when 0;
+	// a negative value disables reconnecting.
+	ReconnectMaxAttempts int `json:"reconnectMaxAttempts"`
+	// ReconnectMaxDelay caps the backoff between reconnections, like "30s".
+	ReconnectMaxDelay string `json:"reconnectMaxDelay"`
 }
 
 type sshLogClient struct {
-	conn    *sshc.Client
-	options LogClientOptions
+	conn      *connection
+	options   LogClientOptions
+	reconnect client.RetryPolicy
 }
 
 func getCommand(search *client.LogSearch) (string, error) {
@@ -103,7 +112,69 @@ func (lc sshLogClient) Get(_ context.Context, search *client.LogSearch) (client.
 		mylog.Debug("using native command for SSH: %s", cmd)
 	}
 
-	session, err := lc.conn.NewSession()
+	// For hybrid mode, mark it for debugging/metrics purposes.
+	// Note: We do NOT skip client-side filtering based on this flag because
+	// we can't know if hl actually ran on the remote until after all output is read.
+	// The reader will always apply filtering for SSH hybrid mode to ensure correctness.
+	searchToUse := search
+	if useHybridHL {
+		preFilteredSearch := *search
+		if preFilteredSearch.Options == nil {
+			preFilteredSearch.Options = make(map[string]interface{})
+		}
+		// Mark as hybrid mode for debugging (not used to skip filtering)
+		preFilteredSearch.Options["__hybridHL__"] = true
+		searchToUse = &preFilteredSearch
+	}
+
+	rc, err := lc.start(lc.conn.get(), cmd, search)
+	if err != nil {
+		return nil, err
+	}
+
+	// A followed command is restarted on a new connection when the current
+	// one drops
+	if search.Follow && lc.reconnect.MaxAttempts > 0 {
+		stream := newFollowStream(lc, cmd, search, rc)
+		result, err := reader.GetLogResult(searchToUse, bufio.NewScanner(stream.pr), stream)
+		if err != nil {
+			_ = stream.Close()
+			return nil, err
+		}
+		result.ErrChan = stream.errChan
+		go stream.run()
+		return result, nil
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		defer close(errChan)
+		if err := <-rc.done; err != nil {
+			errChan <- err
+		}
+	}()
+
+	result, err := reader.GetLogResult(searchToUse, bufio.NewScanner(rc.stdout), rc.session)
+	if err != nil {
+		return nil, err
+	}
+	result.ErrChan = errChan
+
+	return result, nil
+}
+
+// remoteCommand is a command started in a session on the remote host.
+type remoteCommand struct {
+	session *sshc.Session
+	stdout  io.Reader
+	// done receives the error of the command, nil when it succeeded, once it
+	// exited or its connection dropped
+	done chan error
+}
+
+// start runs cmd in a new session of conn.
+func (lc sshLogClient) start(conn *sshc.Client, cmd string, search *client.LogSearch) (*remoteCommand, error) {
+	session, err := conn.NewSession()
 	if err != nil {
 		return nil, err
 	}
@@ -147,20 +218,17 @@ func (lc sshLogClient) Get(_ context.Context, search *client.LogSearch) (client.
 		return nil, fmt.Errorf("failed to start ssh command: %w", err)
 	}
 
-	// Track which engine was used (for hybrid mode)
-	var engineUsed string
-	errChan := make(chan error, 1)
+	done := make(chan error, 1)
 	go func() {
-		defer close(errChan)
+		defer close(done)
 		// Read stderr to detect engine marker and capture errors
 		stderrScanner := bufio.NewScanner(errOut)
 		var stderrOutput bytes.Buffer
 		for stderrScanner.Scan() {
 			line := stderrScanner.Text()
-			// Check for engine marker
+			// Check for engine marker, kept for debugging
 			if strings.HasPrefix(line, "HL_ENGINE=") {
-				engineUsed = strings.TrimPrefix(line, "HL_ENGINE=")
-				mylog.Debug("remote engine detected: %s", engineUsed)
+				mylog.Debug("remote engine detected: %s", strings.TrimPrefix(line, "HL_ENGINE="))
 				continue
 			}
 			stderrOutput.WriteString(line)
@@ -168,40 +236,14 @@ func (lc sshLogClient) Get(_ context.Context, search *client.LogSearch) (client.
 		}
 		if err := session.Wait(); err != nil {
 			if stderrOutput.Len() > 0 {
-				errChan <- fmt.Errorf("ssh command failed: %w (remote output: %s)", err, stderrOutput.String())
+				done <- fmt.Errorf("ssh command failed: %w (remote output: %s)", err, stderrOutput.String())
 			} else {
-				errChan <- fmt.Errorf("ssh command failed: %w", err)
+				done <- fmt.Errorf("ssh command failed: %w", err)
 			}
 		}
 	}()
 
-	scanner := bufio.NewScanner(out)
-
-	// For hybrid mode, mark it for debugging/metrics purposes.
-	// Note: We do NOT skip client-side filtering based on this flag because
-	// we can't know if hl actually ran on the remote until after all output is read.
-	// The reader will always apply filtering for SSH hybrid mode to ensure correctness.
-	searchToUse := search
-	if useHybridHL {
-		preFilteredSearch := *search
-		if preFilteredSearch.Options == nil {
-			preFilteredSearch.Options = make(map[string]interface{})
-		}
-		// Mark as hybrid mode for debugging (not used to skip filtering)
-		preFilteredSearch.Options["__hybridHL__"] = true
-		searchToUse = &preFilteredSearch
-	}
-
-	result, err := reader.GetLogResult(searchToUse, scanner, session)
-	if err != nil {
-		return nil, err
-	}
-	result.ErrChan = errChan
-
-	// Store engine info for debugging/metrics
-	_ = engineUsed // Available for future use (metrics, logging)
-
-	return result, nil
+	return &remoteCommand{session: session, stdout: out, done: done}, nil
 }
 
 // buildHybridHLCommand creates a shell command that:
@@ -296,10 +338,17 @@ func GetLogClient(options LogClientOptions) (client.LogBackend, error) {
 			}),
 	}
 
-	conn, err := sshc.Dial("tcp", options.Addr, sshConfig)
+	reconnect, err := reconnectPolicy(options)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dial(func() (*sshc.Client, error) {
+		return sshc.Dial("tcp", options.Addr, sshConfig)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return sshLogClient{conn: conn, options: options}, nil
+	return sshLogClient{conn: conn, options: options, reconnect: reconnect}, nil
 }
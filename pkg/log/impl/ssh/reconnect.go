@@ -0,0 +1,357 @@
+package ssh
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	sshc "golang.org/x/crypto/ssh"
+)
+
+// DefaultReconnectPolicy bounds the reconnections of a followed command.
+// MaxElapsed is not used.
+var DefaultReconnectPolicy = client.RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+}
+
+const (
+	// replayHistory is the number of delivered lines remembered to drop the
+	// ones a restarted command prints again.
+	replayHistory = 1000
+	// replayWindow bounds how long after a reconnection lines are held back
+	// while looking for the last delivered one.
+	replayWindow = 2 * time.Second
+)
+
+// reconnectPolicy returns the reconnect policy of options.
+func reconnectPolicy(options LogClientOptions) (client.RetryPolicy, error) {
+	policy := DefaultReconnectPolicy
+	switch {
+	case options.ReconnectMaxAttempts < 0:
+		policy.MaxAttempts = 0
+	case options.ReconnectMaxAttempts > 0:
+		policy.MaxAttempts = options.ReconnectMaxAttempts
+	}
+	if options.ReconnectMaxDelay != "" {
+		d, err := time.ParseDuration(options.ReconnectMaxDelay)
+		if err != nil || d <= 0 {
+			return policy, fmt.Errorf("invalid reconnectMaxDelay %q: expected a duration like 30s", options.ReconnectMaxDelay)
+		}
+		policy.MaxDelay = d
+		policy.BaseDelay = min(policy.BaseDelay, d)
+	}
+	return policy, nil
+}
+
+// connection is an SSH connection that can be dialed again once it dropped.
+type connection struct {
+	mu     sync.Mutex
+	client *sshc.Client
+	dial   func() (*sshc.Client, error)
+}
+
+func dial(fn func() (*sshc.Client, error)) (*connection, error) {
+	c, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	return &connection{client: c, dial: fn}, nil
+}
+
+func (c *connection) get() *sshc.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.client
+}
+
+// redial replaces the connection with a new one, unless broken is no longer
+// the current one because another stream already redialed.
+func (c *connection) redial(broken *sshc.Client) (*sshc.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client != broken {
+		return c.client, nil
+	}
+	client, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	_ = broken.Close()
+	c.client = client
+	return client, nil
+}
+
+// isDropped reports whether err, the result of a remote command, means the
+// connection dropped rather than the command failing.
+func isDropped(err error) bool {
+	var exitErr *sshc.ExitError
+	return err != nil && !errors.As(err, &exitErr)
+}
+
+// followStream feeds the output of a followed command to the reader,
+// restarting the command on a new connection when the current one drops.
+// Reconnection attempts are sent on errChan as client.ReconnectingError.
+type followStream struct {
+	lc     sshLogClient
+	cmd    string
+	search *client.LogSearch
+
+	pr      *io.PipeReader
+	pw      *io.PipeWriter
+	errChan chan error
+	closed  chan struct{}
+
+	mu        sync.Mutex
+	current   *remoteCommand
+	closeOnce sync.Once
+
+	replay replayFilter
+}
+
+func newFollowStream(lc sshLogClient, cmd string, search *client.LogSearch, rc *remoteCommand) *followStream {
+	pr, pw := io.Pipe()
+	return &followStream{
+		lc:      lc,
+		cmd:     cmd,
+		search:  search,
+		pr:      pr,
+		pw:      pw,
+		errChan: make(chan error, lc.reconnect.MaxAttempts+1),
+		closed:  make(chan struct{}),
+		current: rc,
+		replay:  replayFilter{size: replayHistory},
+	}
+}
+
+// Close stops the stream and the running command.
+func (s *followStream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		s.mu.Lock()
+		if s.current != nil {
+			_ = s.current.session.Close()
+		}
+		s.mu.Unlock()
+		_ = s.pr.Close()
+	})
+	return nil
+}
+
+// notify sends err on errChan, dropping it when the caller does not read
+// the errors, so the stream never blocks on them.
+func (s *followStream) notify(err error) {
+	select {
+	case s.errChan <- err:
+	default:
+	}
+}
+
+func (s *followStream) isClosed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// run copies the output of the commands to the pipe until one exits on its
+// own, the reconnections run out or the stream is closed.
+func (s *followStream) run() {
+	defer close(s.errChan)
+
+	rc := s.current
+	for {
+		err := s.copy(rc)
+		if s.isClosed() {
+			_ = s.pw.Close()
+			return
+		}
+		if !isDropped(err) {
+			if err != nil {
+				s.notify(err)
+			}
+			_ = s.pw.Close()
+			return
+		}
+
+		rc, err = s.reconnect(err)
+		if err != nil {
+			if !s.isClosed() {
+				s.notify(err)
+			}
+			_ = s.pw.Close()
+			return
+		}
+		s.replay.resync()
+	}
+}
+
+// copy writes the lines of rc to the pipe, dropping the ones already
+// delivered before a reconnection, and returns the result of the command.
+func (s *followStream) copy(rc *remoteCommand) error {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(rc.stdout)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-s.closed:
+				return
+			}
+		}
+	}()
+
+	write := func(ls []string) bool {
+		for _, l := range ls {
+			if _, err := io.WriteString(s.pw, l+"\n"); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+
+	timer := time.NewTimer(replayWindow)
+	defer timer.Stop()
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				if !write(s.replay.flush()) {
+					_ = s.Close()
+				}
+				return <-rc.done
+			}
+			if !write(s.replay.push(line)) {
+				_ = s.Close()
+			}
+		case <-timer.C:
+			// The replayed lines come at once, anything held longer is new
+			if !write(s.replay.flush()) {
+				_ = s.Close()
+			}
+		case <-s.closed:
+			return nil
+		}
+	}
+}
+
+// reconnect dials a new connection and starts the command again, waiting
+// between attempts with an exponential backoff.
+func (s *followStream) reconnect(cause error) (*remoteCommand, error) {
+	policy := s.lc.reconnect
+	delay := policy.BaseDelay
+	broken := s.lc.conn.get()
+
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		s.notify(&client.ReconnectingError{Err: cause, Attempt: attempt, MaxAttempts: policy.MaxAttempts, Wait: delay})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-s.closed:
+			timer.Stop()
+			return nil, cause
+		case <-timer.C:
+		}
+		delay = min(delay*2, policy.MaxDelay)
+
+		conn, err := s.lc.conn.redial(broken)
+		if err != nil {
+			cause = err
+			continue
+		}
+		rc, err := s.lc.start(conn, s.cmd, s.search)
+		if err != nil {
+			cause = err
+			broken = conn
+			continue
+		}
+
+		s.mu.Lock()
+		stopped := s.isClosed()
+		if !stopped {
+			s.current = rc
+		}
+		s.mu.Unlock()
+		if stopped {
+			_ = rc.session.Close()
+			return nil, cause
+		}
+		return rc, nil
+	}
+	return nil, fmt.Errorf("ssh connection lost, gave up after %d reconnection attempts: %w", policy.MaxAttempts, cause)
+}
+
+// replayFilter remembers the last delivered lines so the lines a restarted
+// command prints again, like the tail of a followed file, are dropped.
+// After resync, lines are held back until the last delivered line is seen
+// again with the lines before it matching the history; they are all dropped
+// then. Lines not matching are delivered by flush.
+type replayFilter struct {
+	size    int
+	history []string
+	pending []string
+	syncing bool
+}
+
+// resync starts holding lines back until the replay of the history ends.
+func (f *replayFilter) resync() {
+	f.syncing = len(f.history) > 0
+	f.pending = nil
+}
+
+// push returns the lines to deliver once line is received.
+func (f *replayFilter) push(line string) []string {
+	if !f.syncing {
+		f.remember(line)
+		return []string{line}
+	}
+
+	f.pending = append(f.pending, line)
+	if f.replayed() {
+		f.syncing = false
+		f.pending = nil
+		return nil
+	}
+	if len(f.pending) > len(f.history) {
+		return f.flush()
+	}
+	return nil
+}
+
+// flush stops holding lines back and returns the ones held.
+func (f *replayFilter) flush() []string {
+	f.syncing = false
+	pending := f.pending
+	f.pending = nil
+	for _, l := range pending {
+		f.remember(l)
+	}
+	return pending
+}
+
+// replayed reports whether the pending lines end like the history.
+func (f *replayFilter) replayed() bool {
+	n := min(len(f.pending), len(f.history))
+	for i := 1; i <= n; i++ {
+		if f.pending[len(f.pending)-i] != f.history[len(f.history)-i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *replayFilter) remember(line string) {
+	f.history = append(f.history, line)
+	if len(f.history) > f.size {
+		f.history = f.history[len(f.history)-f.size:]
+	}
+}
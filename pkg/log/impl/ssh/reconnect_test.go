@@ -0,0 +1,92 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sshc "golang.org/x/crypto/ssh"
+)
+
+func pushAll(f *replayFilter, lines ...string) []string {
+	var out []string
+	for _, l := range lines {
+		out = append(out, f.push(l)...)
+	}
+	return out
+}
+
+func TestReplayFilter(t *testing.T) {
+	t.Run("Delivers lines before any reconnection", func(t *testing.T) {
+		f := replayFilter{size: 10}
+		assert.Equal(t, []string{"a", "b"}, pushAll(&f, "a", "b"))
+	})
+
+	t.Run("Drops the replayed tail", func(t *testing.T) {
+		f := replayFilter{size: 10}
+		pushAll(&f, "a", "b", "c")
+		f.resync()
+		assert.Empty(t, pushAll(&f, "b", "c"))
+		assert.Equal(t, []string{"d"}, pushAll(&f, "d"))
+	})
+
+	t.Run("Drops a replay longer than the history", func(t *testing.T) {
+		f := replayFilter{size: 2}
+		pushAll(&f, "a", "b", "c")
+		f.resync()
+		assert.Empty(t, pushAll(&f, "x", "b", "c"))
+		assert.Equal(t, []string{"d"}, pushAll(&f, "d"))
+	})
+
+	t.Run("Delivers lines not in the history", func(t *testing.T) {
+		f := replayFilter{size: 2}
+		pushAll(&f, "a", "b")
+		f.resync()
+		assert.Empty(t, pushAll(&f, "x", "y"))
+		assert.Equal(t, []string{"x", "y", "z"}, pushAll(&f, "z"))
+	})
+
+	t.Run("Flush delivers the held lines", func(t *testing.T) {
+		f := replayFilter{size: 10}
+		pushAll(&f, "a", "b")
+		f.resync()
+		assert.Empty(t, pushAll(&f, "x"))
+		assert.Equal(t, []string{"x"}, f.flush())
+		assert.Equal(t, []string{"y"}, pushAll(&f, "y"))
+	})
+
+	t.Run("Nothing to resync without history", func(t *testing.T) {
+		f := replayFilter{size: 10}
+		f.resync()
+		assert.Equal(t, []string{"a"}, pushAll(&f, "a"))
+	})
+}
+
+func TestReconnectPolicy(t *testing.T) {
+	policy, err := reconnectPolicy(LogClientOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultReconnectPolicy, policy)
+
+	policy, err = reconnectPolicy(LogClientOptions{ReconnectMaxAttempts: 3, ReconnectMaxDelay: "500ms"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, policy.MaxAttempts)
+	assert.Equal(t, 500*time.Millisecond, policy.MaxDelay)
+	assert.Equal(t, 500*time.Millisecond, policy.BaseDelay)
+
+	policy, err = reconnectPolicy(LogClientOptions{ReconnectMaxAttempts: -1})
+	require.NoError(t, err)
+	assert.Zero(t, policy.MaxAttempts)
+
+	_, err = reconnectPolicy(LogClientOptions{ReconnectMaxDelay: "soon"})
+	assert.ErrorContains(t, err, "invalid reconnectMaxDelay")
+}
+
+func TestIsDropped(t *testing.T) {
+	assert.False(t, isDropped(nil))
+	assert.False(t, isDropped(fmt.Errorf("ssh command failed: %w", &sshc.ExitError{})))
+	assert.True(t, isDropped(fmt.Errorf("ssh command failed: %w", &sshc.ExitMissingError{})))
+	assert.True(t, isDropped(errors.New("connection reset by peer")))
+}
@@ -36,9 +36,9 @@ func InitColorState(explicitSetting *bool, writer io.Writer) {
 		return
 	}
 
-	// Priority 3: Auto-detect TTY
+	// Priority 3: Auto-detect TTY (Cygwin/MSYS terminals on Windows are pipes)
 	if f, ok := writer.(*os.File); ok {
-		globalColorState.enabled = isatty.IsTerminal(f.Fd())
+		globalColorState.enabled = isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
 		color.NoColor = !globalColorState.enabled
 		return
 	}
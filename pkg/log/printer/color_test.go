@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/TylerBrock/colorjson"
 	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
 )
@@ -185,6 +186,44 @@ func TestColorString(t *testing.T) {
 	})
 }
 
+func TestColorKV(t *testing.T) {
+	defer func() {
+		globalColorState.enabled = false
+		color.NoColor = true
+	}()
+	globalColorState.enabled = true
+	color.NoColor = false
+	palette := colorjson.NewFormatter()
+	key := func(k string) string { return palette.KeyColor.Sprint(k) }
+
+	t.Run("colors values by type", func(t *testing.T) {
+		result := ColorKV("user=alice status=500 ok=true parent=null")
+		assert.Equal(t, key("user")+"="+palette.StringColor.Sprint("alice")+" "+
+			key("status")+"="+palette.NumberColor.Sprint("500")+" "+
+			key("ok")+"="+palette.BoolColor.Sprint("true")+" "+
+			key("parent")+"="+palette.NullColor.Sprint("null"), result)
+	})
+
+	t.Run("keeps quoted values with spaces whole", func(t *testing.T) {
+		result := ColorKV(`msg="request failed: timeout" path='/api v2'`)
+		assert.Equal(t, key("msg")+"="+palette.StringColor.Sprint(`"request failed: timeout"`)+" "+
+			key("path")+"="+palette.StringColor.Sprint(`'/api v2'`), result)
+	})
+
+	t.Run("leaves the text around the tokens untouched", func(t *testing.T) {
+		result := ColorKV("GET /health done in a=b, x == y url=http://h/?q=1 empty=")
+		assert.Equal(t, "GET /health done in "+key("a")+"="+palette.StringColor.Sprint("b,")+
+			" x == y "+key("url")+"="+palette.StringColor.Sprint("http://h/?q=1")+" "+key("empty")+"=", result)
+		assert.Equal(t, "no pairs here", ColorKV("no pairs here"))
+	})
+
+	t.Run("returns the text as is without color", func(t *testing.T) {
+		globalColorState.enabled = false
+		color.NoColor = true
+		assert.Equal(t, `msg="a b" n=1`, ColorKV(`msg="a b" n=1`))
+	})
+}
+
 func TestBold(t *testing.T) {
 	text := "important"
 
@@ -3,10 +3,13 @@ package printer_test
 import (
 	"strings"
 	"testing"
+	"text/template"
 	"time"
 
 	"github.com/bascanada/logviewer/pkg/log/printer"
+	"github.com/bascanada/logviewer/pkg/ty"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExpandJSON(t *testing.T) {
@@ -256,4 +259,60 @@ func TestFormatTimestamp(t *testing.T) {
 		assert.Equal(t, "10:30", printer.FormatTimestamp(ts, "15:04"))
 		assert.Equal(t, "Dec 17 10:30:45", printer.FormatTimestamp(ts, "Jan 02 15:04:05"))
 	})
+
+	t.Run("converts to the display zone", func(t *testing.T) {
+		defer printer.SetDisplayLocation(nil)
+		loc, err := printer.LoadDisplayLocation("America/Toronto")
+		require.NoError(t, err)
+		printer.SetDisplayLocation(loc)
+
+		// Stored in UTC, shown on either side of the November 3 2024 DST change
+		assert.Equal(t, "2024-11-02 12:00 EDT", printer.FormatTimestamp(time.Date(2024, 11, 2, 16, 0, 0, 0, time.UTC), "2006-01-02 15:04 MST"))
+		assert.Equal(t, "2024-11-04 11:00 EST", printer.FormatTimestamp(time.Date(2024, 11, 4, 16, 0, 0, 0, time.UTC), "2006-01-02 15:04 MST"))
+		assert.Equal(t, "11:00", printer.FormatDate("15:04", time.Date(2024, 11, 4, 16, 0, 0, 0, time.UTC)))
+
+		_, err = printer.LoadDisplayLocation("Mars/Olympus")
+		assert.ErrorContains(t, err, "invalid timezone")
+	})
+
+	t.Run("accepts local and utc in any case", func(t *testing.T) {
+		for name, want := range map[string]*time.Location{"": time.Local, "local": time.Local, "Local": time.Local, "utc": time.UTC, "UTC": time.UTC} {
+			loc, err := printer.LoadDisplayLocation(name)
+			require.NoError(t, err, name)
+			assert.Equal(t, want, loc, name)
+		}
+		assert.Equal(t, time.Local, printer.DisplayLocation(), "local until set")
+	})
+}
+
+func TestHumanDuration(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"ms integer", 1200, "1.2s"},
+		{"small ms integer", int64(250), "250ms"},
+		{"JSON number", 90500.0, "1m31s"},
+		{"float string", "1234.5", "1.235s"},
+		{"fraction of a ms", "0.25", "250µs"},
+		{"duration string", "1h30m", "1h30m"},
+		{"whole hours", 7200000, "2h"},
+		{"whole minutes", "120000", "2m"},
+		{"nil", nil, ""},
+		{"invalid string", "soon", "soon"},
+		{"invalid type", true, "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, printer.HumanDuration(tt.value))
+		})
+	}
+
+	t.Run("missing field in a template", func(t *testing.T) {
+		tmpl := template.Must(template.New("").Funcs(printer.GetTemplateFunctionsMap()).Parse(`[{{HumanDuration .Fields.latency_ms}}]`))
+		var out strings.Builder
+		require.NoError(t, tmpl.Execute(&out, struct{ Fields ty.MI }{Fields: ty.MI{}}))
+		assert.Equal(t, "[]", out.String())
+	})
 }
@@ -0,0 +1,99 @@
+package printer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// highlightColors are assigned to highlight terms in the order they are given,
+// cycling when there are more terms than colors.
+var highlightColors = []*color.Color{
+	color.New(color.FgBlack, color.BgYellow),
+	color.New(color.FgBlack, color.BgCyan),
+	color.New(color.FgBlack, color.BgMagenta),
+	color.New(color.FgBlack, color.BgGreen),
+	color.New(color.FgWhite, color.BgRed),
+	color.New(color.FgWhite, color.BgBlue),
+}
+
+// Highlighter colors occurrences of a set of terms in a string, like grep --color.
+type Highlighter struct {
+	re *regexp.Regexp
+	// groupColor maps a regex capture group to the color of its term
+	groupColor map[int]*color.Color
+}
+
+// NewHighlighter builds a Highlighter for terms. Matching is case-insensitive
+// unless caseSensitive is set. It returns nil when there is nothing to highlight.
+func NewHighlighter(terms []string, caseSensitive bool) *Highlighter {
+	type term struct {
+		text  string
+		color *color.Color
+	}
+	var list []term
+	seen := map[string]bool{}
+	for _, t := range terms {
+		t = strings.TrimSpace(t)
+		key := t
+		if !caseSensitive {
+			key = strings.ToLower(t)
+		}
+		if t == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		list = append(list, term{text: t, color: highlightColors[len(list)%len(highlightColors)]})
+	}
+	if len(list) == 0 {
+		return nil
+	}
+
+	// Longer terms first so "timeout" wins over "time" at the same position
+	sort.SliceStable(list, func(i, j int) bool { return len(list[i].text) > len(list[j].text) })
+
+	groups := make([]string, len(list))
+	groupColor := make(map[int]*color.Color, len(list))
+	for i, t := range list {
+		groups[i] = "(" + regexp.QuoteMeta(t.text) + ")"
+		groupColor[i+1] = t.color
+	}
+	pattern := strings.Join(groups, "|")
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+
+	return &Highlighter{re: regexp.MustCompile(pattern), groupColor: groupColor}
+}
+
+// Highlight returns s with every term occurrence wrapped in its color. It is a
+// no-op when color output is disabled.
+func (h *Highlighter) Highlight(s string) string {
+	if h == nil || s == "" || !IsColorEnabled() {
+		return s
+	}
+
+	matches := h.re.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(s[last:m[0]])
+		c := highlightColors[0]
+		for group := 1; group*2 < len(m); group++ {
+			if m[group*2] >= 0 {
+				c = h.groupColor[group]
+				break
+			}
+		}
+		b.WriteString(c.Sprint(s[m[0]:m[1]]))
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package printer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlighter(t *testing.T) {
+	enabled := true
+	InitColorState(&enabled, os.Stdout)
+	defer func() {
+		disabled := false
+		InitColorState(&disabled, os.Stdout)
+	}()
+
+	yellow := highlightColors[0].Sprint
+	cyan := highlightColors[1].Sprint
+
+	t.Run("case-insensitive by default", func(t *testing.T) {
+		h := NewHighlighter([]string{"timeout"}, false)
+		assert.Equal(t, "read "+yellow("TIMEOUT")+" after "+yellow("timeout"), h.Highlight("read TIMEOUT after timeout"))
+	})
+
+	t.Run("case-sensitive", func(t *testing.T) {
+		h := NewHighlighter([]string{"timeout"}, true)
+		assert.Equal(t, "TIMEOUT "+yellow("timeout"), h.Highlight("TIMEOUT timeout"))
+	})
+
+	t.Run("each term gets its own color", func(t *testing.T) {
+		h := NewHighlighter([]string{"timeout", "refused"}, false)
+		assert.Equal(t, yellow("timeout")+" then "+cyan("refused"), h.Highlight("timeout then refused"))
+	})
+
+	t.Run("longer term wins on overlap", func(t *testing.T) {
+		h := NewHighlighter([]string{"time", "timeout"}, false)
+		assert.Equal(t, cyan("timeout"), h.Highlight("timeout"))
+	})
+
+	t.Run("no terms", func(t *testing.T) {
+		assert.Nil(t, NewHighlighter([]string{"", " "}, false))
+		var h *Highlighter
+		assert.Equal(t, "timeout", h.Highlight("timeout"))
+	})
+
+	t.Run("disabled color leaves message untouched", func(t *testing.T) {
+		disabled := false
+		InitColorState(&disabled, os.Stdout)
+		defer InitColorState(&enabled, os.Stdout)
+
+		h := NewHighlighter([]string{"timeout"}, false)
+		assert.Equal(t, "timeout", h.Highlight("timeout"))
+	})
+}
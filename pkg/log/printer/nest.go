@@ -0,0 +1,82 @@
+package printer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/ty"
+)
+
+// NestFields returns fields with dotted names un-flattened into nested maps,
+// e.g. "http.method" and "http.status" become {"http": {"method", "status"}}.
+// A name is kept flat when nesting it would conflict with another field, such
+// as "http" holding a scalar or a map of its own next to "http.method", or
+// when it has an empty segment like "a..b". Flattening the result back gives
+// the original names, as long as the values were not already maps.
+func NestFields(fields ty.MI) ty.MI {
+	if len(fields) == 0 {
+		return fields
+	}
+
+	nested := make(ty.MI, len(fields))
+	var dotted []string
+	for key, value := range fields {
+		if strings.Contains(key, ".") {
+			dotted = append(dotted, key)
+			continue
+		}
+		nested[key] = value
+	}
+	// Sorted so the same conflicts are resolved the same way on every entry
+	sort.Strings(dotted)
+
+	// created holds the paths of the maps made here, the only ones other names
+	// may be nested into
+	created := map[string]bool{}
+	for _, key := range dotted {
+		if !nestField(nested, created, key, fields[key]) {
+			nested[key] = fields[key]
+		}
+	}
+	return nested
+}
+
+// nestField stores value at the dotted path key of root, creating the
+// intermediate maps. It returns false, leaving root unchanged, when the path
+// conflicts with a value already there.
+func nestField(root ty.MI, created map[string]bool, key string, value any) bool {
+	parts := strings.Split(key, ".")
+	for _, part := range parts {
+		if part == "" {
+			return false
+		}
+	}
+
+	// Check the whole path first so a conflict doesn't leave empty maps behind
+	node := root
+	depth := 0
+	for ; depth < len(parts)-1; depth++ {
+		existing, ok := node[parts[depth]]
+		if !ok {
+			break
+		}
+		if !created[strings.Join(parts[:depth+1], ".")] {
+			return false
+		}
+		node = existing.(ty.MI)
+	}
+	if depth == len(parts)-1 {
+		if _, ok := node[parts[depth]]; ok {
+			return false
+		}
+	}
+
+	for ; depth < len(parts)-1; depth++ {
+		child := ty.MI{}
+		node[parts[depth]] = child
+		created[strings.Join(parts[:depth+1], ".")] = true
+		node = child
+	}
+	node[parts[len(parts)-1]] = value
+	return true
+}
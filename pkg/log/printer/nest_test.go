@@ -0,0 +1,67 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNestFields(t *testing.T) {
+	fields := ty.MI{
+		"level":                   "INFO",
+		"attributes.http.method":  "GET",
+		"attributes.http.status":  200,
+		"attributes.service.name": "api",
+	}
+	assert.Equal(t, ty.MI{
+		"level": "INFO",
+		"attributes": ty.MI{
+			"http":    ty.MI{"method": "GET", "status": 200},
+			"service": ty.MI{"name": "api"},
+		},
+	}, NestFields(fields))
+}
+
+func TestNestFieldsConflicts(t *testing.T) {
+	fields := ty.MI{
+		"http":        "legacy",
+		"http.method": "GET",
+		"a.b":         1,
+		"a.b.c":       2,
+		"meta":        ty.MI{"x": 1},
+		"meta.y":      2,
+		"trailing.":   3,
+		"..":          4,
+	}
+	assert.Equal(t, ty.MI{
+		"http":        "legacy",
+		"http.method": "GET",
+		"a":           ty.MI{"b": 1},
+		"a.b.c":       2,
+		"meta":        ty.MI{"x": 1},
+		"meta.y":      2,
+		"trailing.":   3,
+		"..":          4,
+	}, NestFields(fields))
+}
+
+func TestNestFieldsRoundTrip(t *testing.T) {
+	fields := ty.MI{"a.b.c": 1, "a.b.d": 2, "a.e": 3, "a.b": 4, "f": 5}
+	nested := NestFields(fields)
+
+	flat := ty.MI{}
+	var flatten func(prefix string, m ty.MI)
+	flatten = func(prefix string, m ty.MI) {
+		for k, v := range m {
+			if child, ok := v.(ty.MI); ok {
+				flatten(prefix+k+".", child)
+				continue
+			}
+			flat[prefix+k] = v
+		}
+	}
+	flatten("", nested)
+	assert.Equal(t, fields, flat)
+	assert.Nil(t, NestFields(nil))
+}
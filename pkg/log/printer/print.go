@@ -9,12 +9,18 @@ import (
 	"github.com/bascanada/logviewer/pkg/log/client"
 )
 
-// PrintPrinter prints results to standard output.
-type PrintPrinter struct{}
+// PrintPrinter prints results to standard output, or hands them to Sink when
+// it is set.
+type PrintPrinter struct {
+	Sink OutputSink
+}
 
-// Display writes `result` to stdout and returns whether the result continues
-// streaming (follow mode) along with any immediate error encountered.
+// Display writes `result` to the sink, stdout by default, and returns whether
+// the result continues streaming (follow mode) along with any immediate error
+// encountered.
 func (pp PrintPrinter) Display(ctx context.Context, result client.LogSearchResult, onError func(error)) (bool, error) {
-
+	if pp.Sink != nil {
+		return DriveSink(ctx, result, pp.Sink, func() {}, onError)
+	}
 	return WrapIoWritter(ctx, result, os.Stdout, func() {}, onError)
 }
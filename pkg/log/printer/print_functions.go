@@ -3,7 +3,10 @@ package printer
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"text/template"
 	"time"
 
@@ -102,12 +105,52 @@ func ExtractJSON(runes []rune, start int) string {
 	return ""
 }
 
-// FormatDate formats a time.Time object according to the layout.
+// displayLocation is the zone FormatDate and FormatTimestamp print in, the
+// local zone when nil. Entries are stored in UTC.
+var displayLocation atomic.Pointer[time.Location]
+
+// SetDisplayLocation sets the zone timestamps are printed in, the local zone
+// when loc is nil.
+func SetDisplayLocation(loc *time.Location) {
+	displayLocation.Store(loc)
+}
+
+// DisplayLocation returns the zone timestamps are printed in.
+func DisplayLocation() *time.Location {
+	if loc := displayLocation.Load(); loc != nil {
+		return loc
+	}
+	return time.Local
+}
+
+// LoadDisplayLocation returns the zone named by the timezone printer option:
+// an IANA name like America/Toronto, UTC, or local, in any case. Empty is the
+// local zone.
+func LoadDisplayLocation(name string) (*time.Location, error) {
+	switch {
+	case name == "" || strings.EqualFold(name, "local"):
+		return time.Local, nil
+	case strings.EqualFold(name, "utc"):
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// inDisplayLocation returns t in the display zone.
+func inDisplayLocation(t time.Time) time.Time {
+	return t.In(DisplayLocation())
+}
+
+// FormatDate formats a time.Time object in the display zone according to the layout.
 func FormatDate(layout string, t time.Time) string {
-	return t.Format(layout)
+	return inDisplayLocation(t).Format(layout)
 }
 
-// FormatTimestamp formats a timestamp in local time, returning "N/A" for zero-value timestamps.
+// FormatTimestamp formats a timestamp in the display zone, local time by default, returning "N/A" for zero-value timestamps.
 // This is useful for aggregated results (stats, timechart) where timestamps may be unknown.
 // Converting to local time ensures the displayed time matches what users can type in --from/--to.
 // Usage in template: {{FormatTimestamp .Timestamp "15:04:05"}}
@@ -115,7 +158,7 @@ func FormatTimestamp(t time.Time, layout string) string {
 	if t.IsZero() {
 		return "N/A"
 	}
-	return t.Local().Format(layout)
+	return inDisplayLocation(t).Format(layout)
 }
 
 // MultilineFields formats map fields into a multiline string prefixed with " * ".
@@ -143,6 +186,50 @@ func KV(values ty.MI) string {
 	return strings.Join(items, " ")
 }
 
+// kvTokenRegex matches a key=value token starting the text or following a
+// space. The value may be double or single quoted, spaces included.
+var kvTokenRegex = regexp.MustCompile(`(?:^|\s)([A-Za-z_][\w.\-]*)=("(?:[^"\\]|\\.)*"|'[^']*'|\S*)`)
+
+// ColorKV colors the keys and values of the key=value tokens of a plain text
+// message with the palette of the JSON formatter, the values by their type.
+// Text that is not a key=value token is kept as it is.
+// Usage in template: {{ColorKV .Message}}
+func ColorKV(value string) string {
+	if !IsColorEnabled() {
+		return value
+	}
+
+	palette := colorjson.NewFormatter()
+	var result strings.Builder
+	last := 0
+	for _, m := range kvTokenRegex.FindAllStringSubmatchIndex(value, -1) {
+		keyStart, keyEnd, valueStart, valueEnd := m[2], m[3], m[4], m[5]
+		result.WriteString(value[last:keyStart])
+		result.WriteString(palette.KeyColor.Sprint(value[keyStart:keyEnd]))
+		result.WriteString("=")
+		if valueEnd > valueStart {
+			result.WriteString(kvValueColor(palette, value[valueStart:valueEnd]).Sprint(value[valueStart:valueEnd]))
+		}
+		last = valueEnd
+	}
+	result.WriteString(value[last:])
+	return result.String()
+}
+
+// kvValueColor returns the color the JSON formatter gives to the type of v.
+func kvValueColor(palette *colorjson.Formatter, v string) *color.Color {
+	switch {
+	case v == "true" || v == "false":
+		return palette.BoolColor
+	case v == "null" || v == "nil":
+		return palette.NullColor
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return palette.NumberColor
+	}
+	return palette.StringColor
+}
+
 // ExpandJSON detects and formats all JSON objects and arrays in the message.
 // Outputs formatted, indented (and colored if enabled) JSON on new lines.
 // Usage in template: {{.Message}}{{ExpandJson .Message}}
@@ -331,6 +418,61 @@ func Trim(s string) string {
 	return strings.TrimSpace(s)
 }
 
+// HumanDuration formats a duration compactly, e.g. 1.2s or 1h30m. Numbers,
+// and strings holding one, are milliseconds; other strings are parsed as Go
+// durations like 1500ms. Nil gives an empty string and a value that is not a
+// duration is returned as it is.
+// Usage in template: {{HumanDuration .Fields.latency_ms}}
+func HumanDuration(value interface{}) string {
+	var d time.Duration
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Duration:
+		d = v
+	case int:
+		d = time.Duration(v) * time.Millisecond
+	case int64:
+		d = time.Duration(v) * time.Millisecond
+	case float64:
+		d = time.Duration(v * float64(time.Millisecond))
+	case json.Number:
+		ms, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+		d = time.Duration(ms * float64(time.Millisecond))
+	case string:
+		if ms, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			d = time.Duration(ms * float64(time.Millisecond))
+		} else if parsed, err := time.ParseDuration(strings.TrimSpace(v)); err == nil {
+			d = parsed
+		} else {
+			return v
+		}
+	default:
+		return fmt.Sprint(v)
+	}
+
+	switch abs := d.Abs(); {
+	case abs >= time.Minute:
+		d = d.Round(time.Second)
+	case abs >= time.Second:
+		d = d.Round(time.Millisecond)
+	default:
+		d = d.Round(time.Microsecond)
+	}
+	// 1h0m0s reads 1h and 2m0s reads 2m
+	str := d.String()
+	if strings.HasSuffix(str, "m0s") {
+		str = strings.TrimSuffix(str, "0s")
+	}
+	if strings.HasSuffix(str, "h0m") {
+		str = strings.TrimSuffix(str, "0m")
+	}
+	return str
+}
+
 // ColorLevel applies color based on log level.
 // Usage in template: {{ColorLevel .Level}}
 // Color mapping: ERROR/FATAL/CRITICAL=red, WARN/WARNING=yellow, INFO=cyan, DEBUG=blue, TRACE=dim
@@ -429,11 +571,13 @@ func GetTemplateFunctionsMap() template.FuncMap {
 		"Field":                GetField,
 		"KV":                   KV,
 		"Trim":                 Trim,
+		"HumanDuration":        HumanDuration,
 		// Color functions
 		"ColorLevel":     ColorLevel,
 		"ColorTimestamp": ColorTimestamp,
 		"ColorContext":   ColorContext,
 		"ColorString":    ColorString,
+		"ColorKV":        ColorKV,
 		"Bold":           Bold,
 	}
 }
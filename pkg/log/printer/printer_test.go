@@ -86,3 +86,27 @@ func TestMessageRegex(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultTemplateColorsLevel(t *testing.T) {
+	entries := []client.LogEntry{{Level: "ERROR", Message: "boom", ContextID: "api"}}
+
+	t.Run("plain when color is off", func(t *testing.T) {
+		search := &client.LogSearch{PrinterOptions: client.PrinterOptions{Color: ty.OptWrap(false)}}
+		var buf bytes.Buffer
+		_, err := WrapIoWritter(context.Background(), &MockLogSearchResult{search: search, entries: entries}, &buf, func() {}, func(_ error) {})
+
+		assert.NoError(t, err)
+		assert.NotContains(t, buf.String(), "\x1b[")
+		assert.Contains(t, buf.String(), "[api] ERROR boom")
+	})
+
+	t.Run("colored when forced, even into a pipe", func(t *testing.T) {
+		defer func() { InitColorState(nil, &bytes.Buffer{}) }()
+		search := &client.LogSearch{PrinterOptions: client.PrinterOptions{Color: ty.OptWrap(true)}}
+		var buf bytes.Buffer
+		_, err := WrapIoWritter(context.Background(), &MockLogSearchResult{search: search, entries: entries}, &buf, func() {}, func(_ error) {})
+
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "\x1b[31mERROR\x1b[0m boom")
+	})
+}
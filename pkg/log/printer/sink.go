@@ -0,0 +1,194 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"text/template"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/mattn/go-colorable"
+)
+
+// OutputSink receives the entries of a search, for embedders routing them
+// somewhere other than a terminal (a web UI, a database). Entries arrive with
+// their JSON fields extracted, their signature attached and their message
+// truncated, like the printed ones.
+type OutputSink interface {
+	// Write receives the next batch of entries. An error stops the search:
+	// no more batches are written.
+	Write(entries []client.LogEntry) error
+	// Close is called once, after the last batch or a failed Write.
+	Close() error
+}
+
+// WriterSink is the OutputSink of the CLI: it renders each entry with the
+// printer template of the search to an io.Writer.
+type WriterSink struct {
+	writer       io.Writer
+	tmpl         *template.Template
+	messageRegex *regexp.Regexp
+	highlighter  *Highlighter
+}
+
+// NewWriterSink returns a sink printing to writer with the printer options of
+// search. Colors are set up for writer, and for the console behind it when it
+// is a file, and timestamps are printed in the timezone option when set.
+func NewWriterSink(writer io.Writer, search *client.LogSearch) (*WriterSink, error) {
+	printerOptions := search.PrinterOptions
+
+	// Without the option, the zone set for the display is kept
+	if printerOptions.Timezone.Value != "" {
+		location, err := LoadDisplayLocation(printerOptions.Timezone.Value)
+		if err != nil {
+			return nil, err
+		}
+		SetDisplayLocation(location)
+	}
+
+	// Initialize color state based on configuration and TTY detection
+	var colorEnabled *bool
+	if printerOptions.Color.Set {
+		colorEnabled = &printerOptions.Color.Value
+	}
+	InitColorState(colorEnabled, writer)
+	if f, ok := writer.(*os.File); ok && IsColorEnabled() {
+		// Translates ANSI sequences on Windows consoles without VT support;
+		// returns f unchanged elsewhere
+		writer = colorable.NewColorable(f)
+	}
+
+	templateConfig := printerOptions.Template
+
+	if templateConfig.Value == "" {
+		// ColorLevel is a no-op when color is off, so pipes get plain text
+		templateConfig.S("[{{FormatTimestamp .Timestamp \"15:04:05\"}}] [{{.ContextID}}] {{ColorLevel .Level}} {{.Message}}")
+	}
+
+	tmpl, err := template.New("print_printer").Funcs(GetTemplateFunctionsMap()).Parse(templateConfig.Value + "\n")
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare messageRegex if present
+	var messageRegex *regexp.Regexp
+	if printerOptions.MessageRegex.Set && printerOptions.MessageRegex.Value != "" {
+		messageRegex, err = regexp.Compile(printerOptions.MessageRegex.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &WriterSink{
+		writer:       writer,
+		tmpl:         tmpl,
+		messageRegex: messageRegex,
+		highlighter:  NewHighlighter(printerOptions.Highlight, printerOptions.HighlightCase.Value),
+	}, nil
+}
+
+// Write prints entries, keeping the first group of the message regex as the
+// message and highlighting the printed copy only.
+func (s *WriterSink) Write(entries []client.LogEntry) error {
+	for i, entry := range entries {
+		if s.messageRegex != nil {
+			matches := s.messageRegex.FindStringSubmatch(entry.Message)
+			if len(matches) > 1 {
+				entries[i].Message = matches[1]
+			}
+		}
+		// Highlight a copy so the entry keeps its plain message
+		printed := entries[i]
+		printed.Message = s.highlighter.Highlight(printed.Message)
+		if err := s.tmpl.Execute(s.writer, printed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close does nothing: the writer belongs to the caller.
+func (s *WriterSink) Close() error {
+	return nil
+}
+
+// DriveSink writes the entries of result to sink: the first batch before it
+// returns, then, when the result streams (follow), each new batch from a
+// goroutine. It returns whether the result streams and the errors of the
+// first batch.
+//
+// A failed Write on the stream is passed to onError and aborts it: the result
+// is closed when it supports it and the remaining batches are dropped. The
+// sink is closed once the entries are all written or writing fails. update is
+// called after each batch and the errors of the result go to onError.
+func DriveSink(ctx context.Context, result client.LogSearchResult, sink OutputSink, update func(), onError func(error)) (bool, error) {
+	entries, newEntriesChannel, err := result.GetEntries(ctx)
+	if client.IsPartial(err) {
+		fmt.Fprintf(os.Stderr, "warning: %v; showing the %d entries received\n", err, len(entries))
+	} else if err != nil {
+		_ = sink.Close()
+		return false, err
+	}
+
+	if err := sink.Write(entries); err != nil {
+		if newEntriesChannel != nil {
+			abortStream(result, newEntriesChannel)
+		}
+		_ = sink.Close()
+		return false, err
+	}
+
+	update()
+
+	if newEntriesChannel == nil {
+		if err := sink.Close(); err != nil {
+			return false, err
+		}
+	} else {
+		go func() {
+			update()
+			for entries := range newEntriesChannel {
+				if len(entries) == 0 {
+					continue
+				}
+				if err := sink.Write(entries); err != nil {
+					abortStream(result, newEntriesChannel)
+					_ = sink.Close()
+					onError(fmt.Errorf("writing log entries: %w", err))
+					return
+				}
+				update()
+			}
+			if err := sink.Close(); err != nil {
+				onError(err)
+			}
+		}()
+	}
+
+	// new goroutine to listen for errors
+	if errChan := result.Err(); errChan != nil {
+		go func() {
+			for err := range errChan {
+				onError(err)
+			}
+		}()
+	}
+
+	return newEntriesChannel != nil, nil
+}
+
+// abortStream closes result when it supports it, so the backend stops
+// following, and drops the batches still sent on stream.
+func abortStream(result client.LogSearchResult, stream <-chan []client.LogEntry) {
+	if closer, ok := result.(interface{ Close() error }); ok {
+		if err := closer.Close(); err != nil {
+			fmt.Fprintln(os.Stderr, "error closing search:", err)
+		}
+	}
+	go func() {
+		for range stream {
+		}
+	}()
+}
@@ -0,0 +1,145 @@
+package printer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink keeps the batches it receives and fails the Write of failAt,
+// counting from 1.
+type recordingSink struct {
+	mu      sync.Mutex
+	batches [][]client.LogEntry
+	failAt  int
+	closed  chan struct{}
+}
+
+func newRecordingSink(failAt int) *recordingSink {
+	return &recordingSink{failAt: failAt, closed: make(chan struct{})}
+}
+
+func (s *recordingSink) Write(entries []client.LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.batches)+1 == s.failAt {
+		return errors.New("sink full")
+	}
+	s.batches = append(s.batches, entries)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func (s *recordingSink) messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var messages []string
+	for _, batch := range s.batches {
+		for _, entry := range batch {
+			messages = append(messages, entry.Message)
+		}
+	}
+	return messages
+}
+
+// streamingResult is a followed search result sending the batches pushed on
+// stream.
+type streamingResult struct {
+	MockLogSearchResult
+	stream chan []client.LogEntry
+	closed bool
+}
+
+func (r *streamingResult) GetEntries(_ context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
+	return r.entries, r.stream, nil
+}
+
+func (r *streamingResult) Close() error {
+	r.closed = true
+	return nil
+}
+
+func waitClosed(t *testing.T, sink *recordingSink) {
+	t.Helper()
+	select {
+	case <-sink.closed:
+	case <-time.After(time.Second):
+		t.Fatal("sink not closed")
+	}
+}
+
+func TestDriveSink(t *testing.T) {
+	sink := newRecordingSink(0)
+	result := &MockLogSearchResult{search: &client.LogSearch{}, entries: []client.LogEntry{{Message: "first entry"}}}
+
+	continuous, err := DriveSink(context.Background(), result, sink, func() {}, func(error) {})
+	require.NoError(t, err)
+	assert.False(t, continuous)
+	assert.Equal(t, []string{"first entry"}, sink.messages())
+	waitClosed(t, sink)
+}
+
+func TestDriveSink_Stream(t *testing.T) {
+	sink := newRecordingSink(0)
+	result := &streamingResult{
+		MockLogSearchResult: MockLogSearchResult{search: &client.LogSearch{}, entries: []client.LogEntry{{Message: "a"}}},
+		stream:              make(chan []client.LogEntry),
+	}
+
+	continuous, err := DriveSink(context.Background(), result, sink, func() {}, func(error) {})
+	require.NoError(t, err)
+	assert.True(t, continuous)
+
+	result.stream <- []client.LogEntry{{Message: "b"}}
+	result.stream <- []client.LogEntry{{Message: "c"}}
+	close(result.stream)
+	waitClosed(t, sink)
+	assert.Equal(t, []string{"a", "b", "c"}, sink.messages())
+}
+
+func TestDriveSink_WriteErrorAbortsStream(t *testing.T) {
+	sink := newRecordingSink(2)
+	result := &streamingResult{
+		MockLogSearchResult: MockLogSearchResult{search: &client.LogSearch{}, entries: []client.LogEntry{{Message: "a"}}},
+		stream:              make(chan []client.LogEntry),
+	}
+	errs := make(chan error, 1)
+
+	_, err := DriveSink(context.Background(), result, sink, func() {}, func(err error) { errs <- err })
+	require.NoError(t, err)
+
+	result.stream <- []client.LogEntry{{Message: "b"}}
+	waitClosed(t, sink)
+	assert.ErrorContains(t, <-errs, "sink full")
+
+	// The remaining batches are dropped
+	result.stream <- []client.LogEntry{{Message: "c"}}
+	close(result.stream)
+	assert.Equal(t, []string{"a"}, sink.messages())
+	assert.True(t, result.closed)
+
+	// A failed first batch is returned
+	sink = newRecordingSink(1)
+	_, err = DriveSink(context.Background(), &MockLogSearchResult{search: &client.LogSearch{}}, sink, func() {}, func(error) {})
+	assert.ErrorContains(t, err, "sink full")
+	waitClosed(t, sink)
+}
+
+func TestPrintPrinter_Sink(t *testing.T) {
+	sink := newRecordingSink(0)
+	result := &MockLogSearchResult{search: &client.LogSearch{}, entries: []client.LogEntry{{Message: "a"}}}
+
+	_, err := PrintPrinter{Sink: sink}.Display(context.Background(), result, func(error) {})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, sink.messages())
+}
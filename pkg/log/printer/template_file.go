@@ -0,0 +1,32 @@
+package printer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// LoadTemplateFile reads the Go template of an entry from path and checks it
+// parses with the printer functions, so mistakes are reported with the file
+// and line before any query runs. The final newline of the file is dropped
+// since the printer ends each entry with one.
+func LoadTemplateFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("template file %s not found", path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading template file %s: %w", path, err)
+	}
+
+	text := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	// Named after the file so parse errors read "template: name.tmpl:3: ..."
+	if _, err := template.New(filepath.Base(path)).Funcs(GetTemplateFunctionsMap()).Parse(text); err != nil {
+		return "", fmt.Errorf("invalid template file %s: %w", path, err)
+	}
+	return text, nil
+}
@@ -0,0 +1,49 @@
+package printer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTemplateFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("valid file drops the final newline", func(t *testing.T) {
+		path := filepath.Join(dir, "entry.tmpl")
+		require.NoError(t, os.WriteFile(path, []byte("{{ColorLevel .Level}} {{.Message}}\n"), 0o600))
+
+		text, err := LoadTemplateFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "{{ColorLevel .Level}} {{.Message}}", text)
+	})
+
+	t.Run("multi-line template keeps inner newlines", func(t *testing.T) {
+		path := filepath.Join(dir, "multi.tmpl")
+		require.NoError(t, os.WriteFile(path, []byte("{{.Level}}\n  {{.Message}}\r\n"), 0o600))
+
+		text, err := LoadTemplateFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "{{.Level}}\n  {{.Message}}", text)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadTemplateFile(filepath.Join(dir, "missing.tmpl"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing.tmpl not found")
+	})
+
+	t.Run("parse error names the file and line", func(t *testing.T) {
+		path := filepath.Join(dir, "broken.tmpl")
+		require.NoError(t, os.WriteFile(path, []byte("{{.Level}}\n{{NoSuchFunc .Message}}\n"), 0o600))
+
+		_, err := LoadTemplateFile(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid template file "+path)
+		assert.Contains(t, err.Error(), "broken.tmpl:2")
+		assert.Contains(t, err.Error(), "NoSuchFunc")
+	})
+}
@@ -28,6 +28,7 @@ type LogResult struct {
 	kvRegexExtraction         *regexp.Regexp
 	namedGroupRegexExtraction *regexp.Regexp
 	regexDate                 *regexp.Regexp
+	location                  *time.Location // Zone of the timestamps without an offset
 
 	ErrChan chan error
 }
@@ -66,7 +67,7 @@ func (lr *LogResult) parseBlock(block string) (*client.LogEntry, bool) {
 	if lr.regexDate != nil {
 		if loc := lr.regexDate.FindStringIndex(firstLine); loc != nil {
 			matched := firstLine[loc[0]:loc[1]]
-			if parsed, err := parseTimestamp(matched); err == nil {
+			if parsed, err := parseTimestamp(matched, lr.location); err == nil {
 				entry.Timestamp = parsed
 			}
 			// Preserve any prefix bytes that appear before the timestamp
@@ -186,18 +187,25 @@ func (lr *LogResult) flushBlock(pendingBlock *strings.Builder, onEntry func(clie
 	}
 }
 
-func (lr *LogResult) loadEntries() bool {
+// progressBatch is the number of entries read between two progress reports.
+const progressBatch = 100
+
+func (lr *LogResult) loadEntries(ctx context.Context) bool {
 	lr.entries = make([]client.LogEntry, 0)
 	var pendingBlock strings.Builder
 
 	onEntry := func(entry client.LogEntry) {
 		lr.entries = append(lr.entries, entry)
+		if len(lr.entries)%progressBatch == 0 {
+			client.AddEntryProgress(ctx, progressBatch)
+		}
 	}
 
 	for lr.scanner.Scan() {
 		lr.processLine(lr.scanner.Text(), &pendingBlock, onEntry)
 	}
 	lr.flushBlock(&pendingBlock, onEntry)
+	client.AddEntryProgress(ctx, len(lr.entries)%progressBatch)
 
 	return len(lr.entries) > 0
 }
@@ -206,9 +214,21 @@ func (lr *LogResult) loadEntries() bool {
 func (lr *LogResult) GetEntries(ctx context.Context) ([]client.LogEntry, chan []client.LogEntry, error) {
 
 	if !lr.search.Follow {
-		lr.loadEntries()
+		// Closing the source unblocks a read still waiting when ctx is done
+		stop := context.AfterFunc(ctx, func() { _ = lr.closer.Close() })
+		lr.loadEntries(ctx)
+		cut := !stop()
 		_ = lr.closer.Close()
-		return lr.entries, nil, nil
+
+		err := lr.scanner.Err()
+		if cut {
+			err = ctx.Err()
+		}
+		// A timeout keeps the entries read until then; other errors fail
+		if err = client.PartialOnTimeout(lr.entries, err); err != nil && !client.IsPartial(err) {
+			return nil, nil, err
+		}
+		return lr.entries, nil, err
 	}
 
 	// Channel to receive lines from the scanner
@@ -381,6 +401,11 @@ func GetLogResult(
 		}
 	}
 
+	location, err := client.TimestampLocation(search)
+	if err != nil {
+		return nil, err
+	}
+
 	result := &LogResult{
 		search:                    search,
 		scanner:                   scanner,
@@ -388,24 +413,30 @@ func GetLogResult(
 		namedGroupRegexExtraction: namedGroupRegexExtraction,
 		kvRegexExtraction:         kvRegexExtraction,
 		regexDate:                 regexDateExtraction,
+		location:                  location,
 		fields:                    make(ty.UniSet[string]),
 	}
 
 	return result, nil
 }
 
-func parseTimestamp(v interface{}) (time.Time, error) {
+// parseTimestamp parses a timestamp of the timestamp regex, in UTC. A
+// timestamp without an offset is in loc, the local zone when nil.
+func parseTimestamp(v interface{}, loc *time.Location) (time.Time, error) {
 	var parsed time.Time
 	var err error
+	if loc == nil {
+		loc = time.Local
+	}
 
 	switch t := v.(type) {
 	case string:
 		parsed, err = time.Parse(ty.Format, t)
 		if err != nil {
-			parsed, err = time.ParseInLocation("2006-01-02 15:04:05.000", t, time.Local)
+			parsed, err = time.ParseInLocation("2006-01-02 15:04:05.000", t, loc)
 		}
 		if err != nil {
-			parsed, err = time.ParseInLocation("2006-01-02 15:04:05", t, time.Local)
+			parsed, err = time.ParseInLocation("2006-01-02 15:04:05", t, loc)
 		}
 	case float64:
 		sec := int64(t)
@@ -415,5 +446,5 @@ func parseTimestamp(v interface{}) (time.Time, error) {
 		return time.Time{}, fmt.Errorf("unsupported timestamp format: %T", v)
 	}
 
-	return parsed, err
+	return parsed.UTC(), err
 }
@@ -185,6 +185,26 @@ func TestLogResult_GetEntries_NonFollow(t *testing.T) {
 		assert.Len(t, entries, 0)
 		assert.Nil(t, ch)
 	})
+
+	t.Run("Keeps the entries read before a timeout", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		go func() {
+			_, _ = io.WriteString(pw, "line 1\nline 2\n")
+		}()
+
+		result, err := GetLogResult(&client.LogSearch{}, bufio.NewScanner(pr), pr)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		entries, ch, err := result.GetEntries(ctx)
+		require.Error(t, err)
+		assert.True(t, client.IsPartial(err))
+		assert.True(t, client.IsTimeout(err))
+		assert.Len(t, entries, 2)
+		assert.Nil(t, ch)
+	})
 }
 
 func TestLogResult_GetEntries_Follow(t *testing.T) {
@@ -450,7 +470,7 @@ func TestLogResult_loadEntries(t *testing.T) {
 			entries: []client.LogEntry{},
 		}
 
-		result := lr.loadEntries()
+		result := lr.loadEntries(context.Background())
 		assert.True(t, result)
 		assert.Len(t, lr.entries, 2)
 	})
@@ -467,7 +487,7 @@ func TestLogResult_loadEntries(t *testing.T) {
 			entries: []client.LogEntry{},
 		}
 
-		result := lr.loadEntries()
+		result := lr.loadEntries(context.Background())
 		assert.False(t, result)
 		assert.Len(t, lr.entries, 0)
 	})
@@ -475,7 +495,7 @@ func TestLogResult_loadEntries(t *testing.T) {
 
 func TestParseTimestamp(t *testing.T) {
 	t.Run("Parses RFC3339 format", func(t *testing.T) {
-		ts, err := parseTimestamp("2024-01-15T10:30:45Z")
+		ts, err := parseTimestamp("2024-01-15T10:30:45Z", nil)
 		require.NoError(t, err)
 		assert.Equal(t, 2024, ts.Year())
 		assert.Equal(t, time.January, ts.Month())
@@ -483,22 +503,35 @@ func TestParseTimestamp(t *testing.T) {
 	})
 
 	t.Run("Parses local time format", func(t *testing.T) {
-		ts, err := parseTimestamp("2024-01-15 10:30:45.123")
+		ts, err := parseTimestamp("2024-01-15 10:30:45.123", nil)
 		require.NoError(t, err)
 		assert.Equal(t, 2024, ts.Year())
 	})
 
 	t.Run("Parses float64 Unix timestamp", func(t *testing.T) {
 		unixTime := float64(1705315845.123456)
-		ts, err := parseTimestamp(unixTime)
+		ts, err := parseTimestamp(unixTime, nil)
 		require.NoError(t, err)
 		assert.False(t, ts.IsZero())
 	})
 
 	t.Run("Returns error for unsupported type", func(t *testing.T) {
-		_, err := parseTimestamp(12345)
+		_, err := parseTimestamp(12345, nil)
 		assert.Error(t, err)
 	})
+
+	t.Run("Reads timestamps without offset in the zone, in UTC", func(t *testing.T) {
+		paris, err := time.LoadLocation("Europe/Paris")
+		require.NoError(t, err)
+		ts, err := parseTimestamp("2024-07-15 10:30:45", paris)
+		require.NoError(t, err)
+		assert.Equal(t, time.UTC, ts.Location())
+		assert.Equal(t, time.Date(2024, 7, 15, 8, 30, 45, 0, time.UTC), ts, "summer time")
+
+		ts, err = parseTimestamp("2024-01-15T10:30:45+05:00", paris)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 15, 5, 30, 45, 0, time.UTC), ts, "the offset wins")
+	})
 }
 
 func TestLogResult_PreFiltered(t *testing.T) {
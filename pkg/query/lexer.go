@@ -215,6 +215,13 @@ func (l *Lexer) readCondition() error {
 		l.pos++
 	}
 
+	// field!exists is a postfix operator and takes no value
+	if l.atNotExists() {
+		l.tokens = append(l.tokens, Token{Type: TokenOperator, Value: notExistsSymbol, Pos: l.pos})
+		l.pos += len(notExistsSymbol)
+		return nil
+	}
+
 	// Read operator
 	opStart := l.pos
 	op := l.readOperator()
@@ -233,6 +240,20 @@ func (l *Lexer) readCondition() error {
 	return nil
 }
 
+// atNotExists reports whether the input at the current position is the
+// !exists operator, followed by the end of input, whitespace or a parenthesis.
+func (l *Lexer) atNotExists() bool {
+	rest := l.input[l.pos:]
+	if len(rest) < len(notExistsSymbol) || !strings.EqualFold(rest[:len(notExistsSymbol)], notExistsSymbol) {
+		return false
+	}
+	if len(rest) == len(notExistsSymbol) {
+		return true
+	}
+	next := rune(rest[len(notExistsSymbol)])
+	return unicode.IsSpace(next) || next == '(' || next == ')'
+}
+
 // readOperator reads an operator (symbol or keyword like CONTAINS)
 func (l *Lexer) readOperator() string {
 	// Skip whitespace before operator
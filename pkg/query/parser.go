@@ -27,8 +27,14 @@ var operatorMappings = []operatorMapping{
 	{"=", operator.Equals, false}, // equals (must be last among = variants)
 }
 
+// notExistsSymbol is the postfix operator for a missing field: field!exists
+const notExistsSymbol = "!exists"
+
 // IsHLSyntax detects if an expression uses hl syntax (has special operators)
 func IsHLSyntax(expr string) bool {
+	if strings.HasSuffix(strings.TrimSpace(expr), notExistsSymbol) {
+		return true
+	}
 	// Check for hl-style operators (longer ones first to avoid false positives)
 	hlOperators := []string{"!~=", "~=", "!=", ">=", "<=", ">", "<"}
 	for _, op := range hlOperators {
@@ -40,13 +46,26 @@ func IsHLSyntax(expr string) bool {
 }
 
 // ParseFilterFlag parses a single filter expression in hl syntax.
-// Supports: key=value, key!=value, key~=value, key!~=value, key>value, key>=value, key<value, key<=value
+// Supports: key=value, key!=value, key~=value, key!~=value, key>value, key>=value, key<value, key<=value, key!exists
 func ParseFilterFlag(expr string) (*client.Filter, error) {
 	expr = strings.TrimSpace(expr)
 	if expr == "" {
 		return nil, fmt.Errorf("empty filter expression")
 	}
 
+	if strings.HasSuffix(expr, notExistsSymbol) {
+		field := strings.TrimSpace(strings.TrimSuffix(expr, notExistsSymbol))
+		if field == "" {
+			return nil, fmt.Errorf("missing field name in filter expression: %s", expr)
+		}
+		if !strings.ContainsAny(field, "=<>~!") {
+			return &client.Filter{
+				Field: field,
+				Op:    operator.NotExists,
+			}, nil
+		}
+	}
+
 	for _, mapping := range operatorMappings {
 		idx := strings.Index(expr, mapping.symbol)
 		if idx != -1 {
@@ -122,7 +141,7 @@ func ParseLegacyFilter(fieldExpr string, opExpr string) (*client.Filter, error)
 	op := operator.Equals
 	if opExpr != "" {
 		switch opExpr {
-		case operator.Match, operator.Wildcard, operator.Exists, operator.Regex:
+		case operator.Match, operator.Wildcard, operator.Exists, operator.NotExists, operator.Regex:
 			op = opExpr
 		default:
 			return nil, fmt.Errorf("invalid operator: %s", opExpr)
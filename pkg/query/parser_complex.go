@@ -31,7 +31,7 @@ func NewParser(tokens []Token) *Parser {
 //	not_expr  = "NOT"? primary
 //	primary   = "(" query ")" | exists_func | condition
 //	exists_func = "exists" "(" field ")"
-//	condition = field operator value
+//	condition = field operator value | field "!exists"
 func (p *Parser) ParseQuery() (*client.Filter, error) {
 	filter, err := p.parseOrExpr()
 	if err != nil {
@@ -202,7 +202,7 @@ func (p *Parser) parseExistsFunc() (*client.Filter, error) {
 	}, nil
 }
 
-// parseCondition parses: field operator value
+// parseCondition parses: field operator value | field "!exists"
 func (p *Parser) parseCondition() (*client.Filter, error) {
 	if p.current().Type != TokenField {
 		return nil, fmt.Errorf("expected field at position %d, got %v", p.current().Pos, p.current())
@@ -216,6 +216,13 @@ func (p *Parser) parseCondition() (*client.Filter, error) {
 	opSymbol := p.current().Value
 	p.advance()
 
+	if opSymbol == notExistsSymbol {
+		return &client.Filter{
+			Field: field,
+			Op:    operator.NotExists,
+		}, nil
+	}
+
 	if p.current().Type != TokenValue {
 		return nil, fmt.Errorf("expected value after operator at position %d", p.current().Pos)
 	}
@@ -275,6 +282,9 @@ func ParseQueryExpression(expr string) (*client.Filter, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parser error: %w", err)
 	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
 
 	return filter, nil
 }
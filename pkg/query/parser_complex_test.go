@@ -203,6 +203,18 @@ func TestParseQueryExpression(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "not exists",
+			input: "trace_id!exists AND level=error",
+			validate: func(t *testing.T, f *client.Filter) {
+				if f.Logic != client.LogicAnd || len(f.Filters) != 2 {
+					t.Fatalf("expected AND with 2 filters, got %+v", f)
+				}
+				if f.Filters[0].Field != "trace_id" || f.Filters[0].Op != operator.NotExists {
+					t.Errorf("first filter = %+v, want trace_id not_exists", f.Filters[0])
+				}
+			},
+		},
 		{
 			name:  "greater than",
 			input: "status>400",
@@ -417,6 +429,10 @@ func TestParseQueryExpression_Errors(t *testing.T) {
 			name:  "unterminated quote",
 			input: `service="my api`,
 		},
+		{
+			name:  "empty quoted value",
+			input: `level=ERROR OR service=""`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -22,6 +22,7 @@ func TestIsHLSyntax(t *testing.T) {
 		{"hl greater or equal", "status>=400", true},
 		{"hl less than", "duration<1.5", true},
 		{"hl less or equal", "duration<=1.5", true},
+		{"hl not exists", "trace_id!exists", true},
 		{"no operator", "level", false},
 		{"empty", "", false},
 	}
@@ -63,6 +64,14 @@ func TestParseFilterFlag(t *testing.T) {
 				Negate: true,
 			},
 		},
+		{
+			name: "not exists",
+			expr: "trace_id!exists",
+			expected: &client.Filter{
+				Field: "trace_id",
+				Op:    operator.NotExists,
+			},
+		},
 		{
 			name: "regex",
 			expr: "message~=error.*timeout",
@@ -49,6 +49,41 @@ func (m *mockSearchFactory) GetFieldValues(_ context.Context, contextID string,
 	return result, nil
 }
 
+func (m *mockSearchFactory) GetFields(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (ty.UniSet[string], error) {
+	result, err := m.GetSearchResult(ctx, contextID, inherits, logSearch, runtimeVars)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	fields, _, err := result.GetFields(ctx)
+	return fields, err
+}
+
+func (m *mockSearchFactory) GetFieldNames(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) ([]string, error) {
+	result, err := m.GetSearchResult(ctx, contextID, inherits, logSearch, runtimeVars)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return client.GetFieldNames(ctx, result)
+}
+
+func (m *mockSearchFactory) GetFieldFacets(_ context.Context, contextID string, _ []string, _ client.LogSearch, fields []string, _ map[string]string) (map[string]map[string]int, error) {
+	if contextID == "error" {
+		return nil, errors.New("backend error")
+	}
+	result := make(map[string]map[string]int)
+	for _, f := range fields {
+		result[f] = map[string]int{"value1": 2, "value2": 1}
+	}
+	return result, nil
+}
+
+func (m *mockSearchFactory) GetCount(_ context.Context, contextID string, _ []string, _ client.LogSearch, _ map[string]string) (int, error) {
+	if contextID == "error" {
+		return 0, errors.New("backend error")
+	}
+	return 3, nil
+}
+
 // mockLogSearchResult is a mock implementation of client.LogSearchResult
 type mockLogSearchResult struct {
 	client.LogSearchResult
@@ -78,6 +78,40 @@ func (m *MockSearchFactory) GetFieldValues(_ context.Context, contextID string,
 	return map[string][]string(uniSet), nil
 }
 
+func (m *MockSearchFactory) GetFields(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) (ty.UniSet[string], error) {
+	result, err := m.GetSearchResult(ctx, contextID, inherits, logSearch, runtimeVars)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	fields, _, err := result.GetFields(ctx)
+	return fields, err
+}
+
+func (m *MockSearchFactory) GetFieldNames(ctx context.Context, contextID string, inherits []string, logSearch client.LogSearch, runtimeVars map[string]string) ([]string, error) {
+	result, err := m.GetSearchResult(ctx, contextID, inherits, logSearch, runtimeVars)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return client.GetFieldNames(ctx, result)
+}
+
+func (m *MockSearchFactory) GetFieldFacets(_ context.Context, contextID string, _ []string, _ client.LogSearch, fields []string, _ map[string]string) (map[string]map[string]int, error) {
+	facets := make(map[string]map[string]int, len(fields))
+	for _, field := range fields {
+		facets[field] = make(map[string]int)
+		for _, entry := range m.Store.Entries[contextID] {
+			if val, ok := entry.Fields[field].(string); ok {
+				facets[field][val]++
+			}
+		}
+	}
+	return facets, nil
+}
+
+func (m *MockSearchFactory) GetCount(_ context.Context, contextID string, _ []string, _ client.LogSearch, _ map[string]string) (int, error) {
+	return len(m.Store.Entries[contextID]), nil
+}
+
 type InMemoryLogResult struct {
 	AllEntries []client.LogEntry
 	Search     *client.LogSearch
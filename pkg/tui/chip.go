@@ -31,6 +31,10 @@ const (
 	ChipTypeOption
 )
 
+// nativeOnlyChipField marks a ChipTypeNativeQuery chip created with the
+// "native:" prefix, which sends the query without appending the other filters.
+const nativeOnlyChipField = "only"
+
 // Chip represents a single search component in the chip-based search bar
 type Chip struct {
 	Type     ChipType // Type of chip
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultErrorLevel is the lowest level the ]e and [e keys jump to.
+const DefaultErrorLevel = "ERROR"
+
+// pendingKeyTimeout is how long an e after ] or [ still makes the ]e or [e
+// chord, instead of the sidebar resize the key did on its own.
+const pendingKeyTimeout = 500 * time.Millisecond
+
+// levelRanks orders the levels by severity, aliases included.
+var levelRanks = map[string]int{
+	"TRACE":    0,
+	"DEBUG":    1,
+	"INFO":     2,
+	"WARN":     3,
+	"WARNING":  3,
+	"ERROR":    4,
+	"ERR":      4,
+	"FATAL":    5,
+	"CRITICAL": 5,
+	"PANIC":    5,
+}
+
+// PendingKeyTimeoutMsg ends the wait for the second key of a ]e or [e chord.
+type PendingKeyTimeoutMsg struct {
+	Seq int
+}
+
+// atLeastLevel reports whether level is as severe as floor. Unknown levels
+// never match.
+func atLeastLevel(level, floor string) bool {
+	rank, ok := levelRanks[strings.ToUpper(strings.TrimSpace(level))]
+	if !ok {
+		return false
+	}
+	floorRank, ok := levelRanks[strings.ToUpper(floor)]
+	if !ok {
+		floorRank = levelRanks[DefaultErrorLevel]
+	}
+	return rank >= floorRank
+}
+
+// isErrorEntry reports whether entry is at or above the error level.
+func (m *Model) isErrorEntry(entry client.LogEntry) bool {
+	floor := m.ErrorLevel
+	if floor == "" {
+		floor = DefaultErrorLevel
+	}
+	return atLeastLevel(entry.Level, floor)
+}
+
+// startPendingKey waits for the second key of a chord started with key.
+func (m *Model) startPendingKey(key string) tea.Cmd {
+	m.PendingKey = key
+	m.PendingKeySeq++
+	seq := m.PendingKeySeq
+	return tea.Tick(pendingKeyTimeout, func(time.Time) tea.Msg {
+		return PendingKeyTimeoutMsg{Seq: seq}
+	})
+}
+
+// undoPendingResize restores the sidebar the ] or [ of a chord resized, and
+// clears the pending key.
+func (m *Model) undoPendingResize() {
+	if m.SplitRatio != m.PendingRatio {
+		m.SplitRatio = m.PendingRatio
+		m.updateViewportSizes()
+		m.updateSidebarContent()
+	}
+	m.PendingKey = ""
+}
+
+// jumpToError moves the cursor to the next (dir 1) or previous (dir -1)
+// error entry, wrapping around with a status message. Jumping up past the
+// oldest loaded entry loads the previous page first when there is one.
+func (m Model) jumpToError(dir int) (Model, tea.Cmd) {
+	tab := m.CurrentTab()
+	if tab == nil || len(tab.Entries) == 0 {
+		return m, m.showStatusMessage("No errors")
+	}
+
+	n := len(tab.Entries)
+	for i := tab.Cursor + dir; i >= 0 && i < n; i += dir {
+		if m.isErrorEntry(tab.Entries[i]) {
+			return m.moveCursor(i - tab.Cursor)
+		}
+	}
+
+	if dir < 0 && tab.PaginationInfo != nil && tab.PaginationInfo.HasMore && !tab.LoadingMore {
+		tab.LoadingMore = true
+		m.StatusBar.UpdateFromTab(tab)
+		return m, tea.Batch(m.loadMoreLogsCmd(tab), m.showStatusMessage("Loading older entries, press [e again to keep looking"))
+	}
+
+	// Wrap around from the other end, up to the cursor
+	start, message := 0, "Wrapped to the first error"
+	if dir < 0 {
+		start, message = n-1, "Wrapped to the last error"
+	}
+	for i := start; i != tab.Cursor; i += dir {
+		if m.isErrorEntry(tab.Entries[i]) {
+			m, cmd := m.moveCursor(i - tab.Cursor)
+			return m, tea.Batch(cmd, m.showStatusMessage(message))
+		}
+	}
+
+	if m.isErrorEntry(tab.Entries[tab.Cursor]) {
+		return m, m.showStatusMessage("No other errors")
+	}
+	return m, m.showStatusMessage("No errors")
+}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func errorNavEntries(levels ...string) []client.LogEntry {
+	entries := make([]client.LogEntry, len(levels))
+	for i, level := range levels {
+		entries[i] = client.LogEntry{Message: level, Level: level}
+	}
+	return entries
+}
+
+func TestAtLeastLevel(t *testing.T) {
+	assert.True(t, atLeastLevel("ERROR", "ERROR"))
+	assert.True(t, atLeastLevel("fatal", "ERROR"))
+	assert.True(t, atLeastLevel("WARNING", "WARN"))
+	assert.False(t, atLeastLevel("WARN", "ERROR"))
+	assert.False(t, atLeastLevel("", "ERROR"))
+	assert.False(t, atLeastLevel("NOTICE", "TRACE"))
+	assert.True(t, atLeastLevel("ERR", "bogus"), "unknown floors fall back to ERROR")
+}
+
+func TestJumpToError(t *testing.T) {
+	m := newFacetTestModel(errorNavEntries("INFO", "ERROR", "INFO", "WARN", "FATAL", "INFO"))
+
+	m = pressFacetKey(m, "]")
+	assert.Equal(t, "]", m.PendingKey)
+	m = pressFacetKey(m, "e")
+	assert.Empty(t, m.PendingKey)
+	assert.Equal(t, 1, m.Tabs[0].Cursor)
+
+	m = pressFacetKey(pressFacetKey(m, "]"), "e")
+	assert.Equal(t, 4, m.Tabs[0].Cursor)
+
+	m = pressFacetKey(pressFacetKey(m, "]"), "e")
+	assert.Equal(t, 1, m.Tabs[0].Cursor)
+	assert.Equal(t, "Wrapped to the first error", m.StatusBar.Message)
+
+	m = pressFacetKey(pressFacetKey(m, "["), "e")
+	assert.Equal(t, 4, m.Tabs[0].Cursor)
+	assert.Equal(t, "Wrapped to the last error", m.StatusBar.Message)
+
+	m.ErrorLevel = "WARN"
+	m = pressFacetKey(pressFacetKey(m, "["), "e")
+	assert.Equal(t, 3, m.Tabs[0].Cursor)
+}
+
+func TestJumpToError_NoErrors(t *testing.T) {
+	m := newFacetTestModel(errorNavEntries("INFO", "DEBUG"))
+
+	m = pressFacetKey(pressFacetKey(m, "]"), "e")
+	assert.Equal(t, 0, m.Tabs[0].Cursor)
+	assert.Equal(t, "No errors", m.StatusBar.Message)
+}
+
+func TestJumpToError_LoadsOlderPage(t *testing.T) {
+	m := newFacetTestModel(errorNavEntries("INFO", "ERROR"))
+	m.Tabs[0].Cursor = 1
+	m.Tabs[0].PaginationInfo = &client.PaginationInfo{HasMore: true, NextPageToken: "next"}
+
+	m = pressFacetKey(pressFacetKey(m, "["), "e")
+	assert.True(t, m.Tabs[0].LoadingMore)
+	assert.Equal(t, 1, m.Tabs[0].Cursor)
+}
+
+func TestPendingKey_ResizesSidebar(t *testing.T) {
+	m := newFacetTestModel(errorNavEntries("INFO", "ERROR"))
+	m.DetailsVisible = true
+	ratio := m.SplitRatio
+
+	// ] resizes right away, and the next key is handled as usual
+	m = pressFacetKey(m, "]")
+	assert.InDelta(t, ratio-0.05, m.SplitRatio, 1e-9)
+	m = pressFacetKey(m, "w")
+	assert.Empty(t, m.PendingKey)
+	assert.InDelta(t, ratio-0.05, m.SplitRatio, 1e-9)
+	assert.True(t, m.LineWrapping)
+
+	// The timeout only ends the latest pending key
+	m = pressFacetKey(m, "[")
+	assert.InDelta(t, ratio, m.SplitRatio, 1e-9)
+	updated, _ := m.Update(PendingKeyTimeoutMsg{Seq: m.PendingKeySeq - 1})
+	m = updated.(Model)
+	assert.Equal(t, "[", m.PendingKey)
+	updated, _ = m.Update(PendingKeyTimeoutMsg{Seq: m.PendingKeySeq})
+	m = updated.(Model)
+	assert.Empty(t, m.PendingKey)
+	assert.InDelta(t, ratio, m.SplitRatio, 1e-9)
+
+	// An e makes the chord and undoes the resize
+	m = pressFacetKey(pressFacetKey(m, "]"), "e")
+	assert.InDelta(t, ratio, m.SplitRatio, 1e-9)
+	assert.Equal(t, 1, m.Tabs[0].Cursor)
+}
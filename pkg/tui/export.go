@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/printer"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ExportFormat is the file format of the E key export.
+type ExportFormat int
+
+const (
+	// ExportNDJSON writes one JSON entry per line, like query --json.
+	ExportNDJSON ExportFormat = iota
+	// ExportText writes the entries with the tab's printer template, without
+	// colors.
+	ExportText
+)
+
+// String returns the name shown in the export dialog.
+func (f ExportFormat) String() string {
+	if f == ExportText {
+		return "text"
+	}
+	return "ndjson"
+}
+
+// extension returns the file extension of the format, dot included.
+func (f ExportFormat) extension() string {
+	if f == ExportText {
+		return ".log"
+	}
+	return ".ndjson"
+}
+
+// unsafeFileChars matches the characters of a tab name not kept in the
+// default export file name.
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// openExport opens the export dialog for the filtered entries of the current
+// tab, with a file name in the working directory as the default path.
+func (m *Model) openExport() tea.Cmd {
+	tab := m.CurrentTab()
+	if tab == nil || len(m.filteredEntries(tab)) == 0 {
+		return m.showStatusMessage("No entries to export")
+	}
+
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(tab.Name, "-"), "-")
+	if name == "" {
+		name = "logs"
+	}
+	ti := textinput.New()
+	ti.CharLimit = 512
+	ti.Prompt = "Path: "
+	ti.SetValue(fmt.Sprintf("logviewer-%s-%s%s", name, time.Now().Format("20060102-150405"), m.ExportFormat.extension()))
+	ti.Focus()
+	m.ExportInput = ti
+	m.Focus = FocusExport
+	return textinput.Blink
+}
+
+// handleExport handles input when the export dialog has focus: Tab switches
+// the format, Enter writes the file.
+func (m Model) handleExport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.Focus = FocusList
+		return m, nil
+
+	case tea.KeyTab, tea.KeyShiftTab:
+		old := m.ExportFormat
+		m.ExportFormat = (m.ExportFormat + 1) % 2
+		// Follow the format in the file name unless it was changed
+		if path := m.ExportInput.Value(); strings.HasSuffix(path, old.extension()) {
+			m.ExportInput.SetValue(strings.TrimSuffix(path, old.extension()) + m.ExportFormat.extension())
+			m.ExportInput.CursorEnd()
+		}
+		return m, nil
+
+	case tea.KeyEnter:
+		path := strings.TrimSpace(m.ExportInput.Value())
+		if path == "" {
+			return m, nil
+		}
+		m.Focus = FocusList
+		tab := m.CurrentTab()
+		if tab == nil {
+			return m, nil
+		}
+		path = expandHome(path)
+		entries := m.filteredEntries(tab)
+		if err := exportEntriesToFile(path, m.ExportFormat, entries, tab.Template); err != nil {
+			return m, m.showStatusMessage(fmt.Sprintf("Export failed: %v", err))
+		}
+		return m, m.showStatusMessage(fmt.Sprintf("Exported %d entries to %s", len(entries), path))
+	}
+
+	var cmd tea.Cmd
+	m.ExportInput, cmd = m.ExportInput.Update(msg)
+	return m, cmd
+}
+
+// expandHome replaces a leading ~/ of path with the home directory.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// exportEntriesToFile writes entries to path in format, replacing the file.
+func exportEntriesToFile(path string, format ExportFormat, entries []client.LogEntry, tmpl *template.Template) error {
+	f, err := os.Create(path) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := writeExport(w, format, entries, tmpl); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeExport writes entries to w in format. The text format uses tmpl, or the
+// list's default line when it is nil, and has its colors removed.
+func writeExport(w io.Writer, format ExportFormat, entries []client.LogEntry, tmpl *template.Template) error {
+	if format == ExportNDJSON {
+		enc := json.NewEncoder(w)
+		for _, entry := range entries {
+			if err := enc.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, entry := range entries {
+		var line string
+		if tmpl != nil {
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, entry); err != nil {
+				return fmt.Errorf("template: %w", err)
+			}
+			line = buf.String()
+		} else {
+			line = fmt.Sprintf("[%s] [%s] %s %s", printer.FormatTimestamp(entry.Timestamp, "15:04:05"), entry.ContextID, entry.Level, entry.Message)
+		}
+		if _, err := io.WriteString(w, strings.TrimRight(ansi.Strip(line), "\n")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderExportOverlay renders the export dialog.
+func (m Model) renderExportOverlay() string {
+	title := m.Styles.SidebarTitle.Render("Export")
+
+	count := 0
+	if tab := m.CurrentTab(); tab != nil {
+		count = len(m.filteredEntries(tab))
+	}
+	formats := make([]string, 0, 2)
+	for _, f := range []ExportFormat{ExportNDJSON, ExportText} {
+		if f == m.ExportFormat {
+			formats = append(formats, m.Styles.LogSelected.Render(" "+f.String()+" "))
+		} else {
+			formats = append(formats, lipgloss.NewStyle().Foreground(ColorMuted).Render(" "+f.String()+" "))
+		}
+	}
+
+	help := m.Styles.HelpBar.Render("Tab format • Enter write • Esc cancel")
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		"",
+		fmt.Sprintf("%d entries", count),
+		"",
+		"Format: "+strings.Join(formats, " "),
+		m.ExportInput.View(),
+		"",
+		help,
+	)
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorPrimary).
+		Padding(1, 2).
+		Width(m.Width * 2 / 3).
+		Align(lipgloss.Left)
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height,
+		lipgloss.Center,
+		lipgloss.Center,
+		modalStyle.Render(content),
+	)
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/printer"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportFilteredEntries(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := newFacetTestModel([]client.LogEntry{
+		{Timestamp: ts, Level: "ERROR", Message: "payment failed"},
+		{Timestamp: ts, Level: "INFO", Message: "payment ok"},
+		{Timestamp: ts, Level: "ERROR", Message: "db timeout"},
+	})
+	m.Tabs[0].Name = "prod/api"
+	m.Tabs[0].Template = template.Must(template.New("t").Funcs(printer.GetTemplateFunctionsMap()).
+		Parse(`{{ColorLevel .Level}} {{.Message}}`))
+	m.SearchBar.State.Chips = []Chip{{Type: ChipTypeFreeText, Text: "payment"}}
+	dir := t.TempDir()
+
+	m = pressFacetKey(m, "E")
+	require.Equal(t, FocusExport, m.Focus)
+	assert.True(t, strings.HasPrefix(m.ExportInput.Value(), "logviewer-prod-api-"))
+	assert.True(t, strings.HasSuffix(m.ExportInput.Value(), ".ndjson"))
+	assert.Contains(t, m.View(), "2 entries")
+
+	ndjson := filepath.Join(dir, "out.ndjson")
+	m.ExportInput.SetValue(ndjson)
+	m = pressFacetKey(m, "enter")
+	assert.Equal(t, FocusList, m.Focus)
+	assert.Equal(t, "Exported 2 entries to "+ndjson, m.Messages[len(m.Messages)-1].Text)
+
+	data, err := os.ReadFile(ndjson)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	var entry client.LogEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, "payment ok", entry.Message)
+
+	// Tab switches to the template text, following in the default file name
+	m = pressFacetKey(m, "E")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	assert.Equal(t, ExportText, m.ExportFormat)
+	assert.True(t, strings.HasSuffix(m.ExportInput.Value(), ".log"))
+
+	text := filepath.Join(dir, "out.log")
+	m.ExportInput.SetValue(text)
+	m = pressFacetKey(m, "enter")
+	data, err = os.ReadFile(text)
+	require.NoError(t, err)
+	assert.Equal(t, "ERROR payment failed\nINFO payment ok\n", string(data), "colors are removed")
+
+	// Write errors are reported
+	m = pressFacetKey(m, "E")
+	m.ExportInput.SetValue(filepath.Join(dir, "missing", "out.log"))
+	m = pressFacetKey(m, "enter")
+	assert.Contains(t, m.Messages[len(m.Messages)-1].Text, "Export failed")
+}
+
+func TestExportWithoutEntries(t *testing.T) {
+	m := newFacetTestModel(nil)
+	m = pressFacetKey(m, "E")
+	assert.Equal(t, FocusList, m.Focus)
+	assert.Equal(t, "No entries to export", m.Messages[len(m.Messages)-1].Text)
+}
@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// facetTopN is the number of values listed in the facet overlay; the rest are
+// summarized as "+N more".
+const facetTopN = 10
+
+// facetCounts returns the value counts for the current facet field, computed
+// from the entries currently loaded in the active tab.
+func (m Model) facetCounts() []client.FieldCount {
+	tab := m.CurrentTab()
+	if tab == nil || m.FacetField == "" {
+		return nil
+	}
+	return client.GroupByField(tab.Entries, m.FacetField)
+}
+
+// facetVisibleCount returns how many rows the facet overlay shows.
+func (m Model) facetVisibleCount() int {
+	n := len(m.facetCounts())
+	if n > facetTopN {
+		return facetTopN
+	}
+	return n
+}
+
+// openFacet shows the facet overlay, keeping the previous field if it is
+// still available in the current tab.
+func (m *Model) openFacet() {
+	tab := m.CurrentTab()
+	if tab == nil || len(tab.AvailableFields) == 0 {
+		return
+	}
+	if indexOf(tab.AvailableFields, m.FacetField) < 0 {
+		m.FacetField = tab.AvailableFields[0]
+	}
+	m.FacetCursor = 0
+	m.Focus = FocusFacet
+}
+
+// switchFacetField moves to the previous or next available field.
+func (m *Model) switchFacetField(delta int) {
+	tab := m.CurrentTab()
+	if tab == nil || len(tab.AvailableFields) == 0 {
+		return
+	}
+	fields := tab.AvailableFields
+	idx := indexOf(fields, m.FacetField)
+	if idx < 0 {
+		idx = 0
+	} else {
+		idx = (idx + delta + len(fields)) % len(fields)
+	}
+	m.FacetField = fields[idx]
+	m.FacetCursor = 0
+}
+
+// handleFacet handles input when the facet overlay has focus
+func (m Model) handleFacet(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "a", "q":
+		m.Focus = FocusList
+		return m, nil
+
+	case "left", "h", "shift+tab":
+		m.switchFacetField(-1)
+
+	case "right", "l", "tab":
+		m.switchFacetField(1)
+
+	case "up", "k":
+		if m.FacetCursor > 0 {
+			m.FacetCursor--
+		}
+
+	case "down", "j":
+		if m.FacetCursor < m.facetVisibleCount()-1 {
+			m.FacetCursor++
+		}
+
+	case "enter":
+		counts := m.facetCounts()
+		if len(counts) == 0 {
+			return m, nil
+		}
+		if visible := m.facetVisibleCount(); m.FacetCursor >= visible {
+			m.FacetCursor = visible - 1
+		}
+		value := counts[m.FacetCursor].Value
+		m.SearchBar.State.AddChip(Chip{
+			Type:     ChipTypeField,
+			Field:    m.FacetField,
+			Operator: "=",
+			Value:    value,
+			Display:  m.FacetField + "=" + value,
+			Editable: true,
+		})
+		m.Focus = FocusList
+		if tab := m.CurrentTab(); tab != nil {
+			m.saveSearchBarToTab(tab)
+		}
+		cmd := m.refreshCurrentTab()
+		m.StatusBar.UpdateFromTab(m.CurrentTab())
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// renderFacetOverlay renders the top values of the facet field as a bar chart
+func (m Model) renderFacetOverlay() string {
+	title := m.Styles.SidebarTitle.Render("Facet: " + m.FacetField)
+
+	counts := m.facetCounts()
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+	}
+	subtitle := lipgloss.NewStyle().Foreground(ColorMuted).
+		Render(fmt.Sprintf("%d distinct values across %d entries", len(counts), total))
+
+	// Cursor may point past the end after the field or entries changed
+	cursor := m.FacetCursor
+	if visible := m.facetVisibleCount(); cursor >= visible {
+		cursor = visible - 1
+	}
+
+	shown := counts
+	if len(shown) > facetTopN {
+		shown = shown[:facetTopN]
+	}
+
+	valueWidth := 0
+	for _, c := range shown {
+		if w := len(c.Value); w > valueWidth {
+			valueWidth = w
+		}
+	}
+	if maxValue := m.Width / 4; maxValue > 8 && valueWidth > maxValue {
+		valueWidth = maxValue
+	}
+	barWidth := m.Width/2 - valueWidth - 16
+	if barWidth < 5 {
+		barWidth = 5
+	}
+
+	items := make([]string, 0, len(shown)+1)
+	for i, c := range shown {
+		style := m.Styles.LogEntry
+		if i == cursor {
+			style = m.Styles.LogSelected
+		}
+		value := truncateForDisplay(c.Value, valueWidth)
+		length := 1
+		if counts[0].Count > 0 {
+			length = c.Count * barWidth / counts[0].Count
+		}
+		if length < 1 {
+			length = 1
+		}
+		bar := strings.Repeat("█", length)
+		items = append(items, style.Render(fmt.Sprintf("  %-*s %s %d", valueWidth, value, bar, c.Count)))
+	}
+	if len(counts) == 0 {
+		items = append(items, lipgloss.NewStyle().Foreground(ColorMuted).Render("  (no values in loaded entries)"))
+	}
+	if more := len(counts) - len(shown); more > 0 {
+		items = append(items, m.Styles.SidebarValue.Foreground(ColorMuted).Render(fmt.Sprintf("  ... +%d more", more)))
+	}
+
+	help := m.Styles.HelpBar.Render("←→/hl field • ↑↓/jk navigate • Enter filter • Esc close")
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		subtitle,
+		"",
+		strings.Join(items, "\n"),
+		"",
+		help,
+	)
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorPrimary).
+		Padding(1, 2).
+		Width(m.Width / 2).
+		Align(lipgloss.Left)
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height,
+		lipgloss.Center,
+		lipgloss.Center,
+		modalStyle.Render(content),
+	)
+}
+
+// indexOf returns the position of s in list, or -1.
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
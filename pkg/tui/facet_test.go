@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFacetTestModel(entries []client.LogEntry, fields ...string) Model {
+	m := New(nil, nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	m.Width, m.Height = 120, 40
+	m.Tabs = append(m.Tabs, &Tab{
+		ID:              "tab-1",
+		ContextID:       "ctx",
+		Entries:         entries,
+		AvailableFields: fields,
+	})
+	return m
+}
+
+func pressFacetKey(m Model, key string) Model {
+	var msg tea.KeyMsg
+	switch key {
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEscape}
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	updated, _ := m.Update(msg)
+	return updated.(Model)
+}
+
+func TestFacetOverlay(t *testing.T) {
+	entries := []client.LogEntry{
+		{Fields: ty.MI{"app": "api", "level": "INFO"}},
+		{Fields: ty.MI{"app": "api", "level": "ERROR"}},
+		{Fields: ty.MI{"app": "web", "level": "INFO"}},
+	}
+
+	t.Run("switches field and filters on enter", func(t *testing.T) {
+		m := newFacetTestModel(entries, "app", "level")
+
+		m = pressFacetKey(m, "a")
+		assert.Equal(t, FocusFacet, m.Focus)
+		assert.Equal(t, "app", m.FacetField)
+		assert.Contains(t, m.View(), "Facet: app")
+
+		m = pressFacetKey(m, "l")
+		assert.Equal(t, "level", m.FacetField)
+
+		m = pressFacetKey(m, "enter")
+		assert.Equal(t, FocusList, m.Focus)
+		assert.Len(t, m.SearchBar.State.Chips, 1)
+		assert.Equal(t, "level=INFO", m.SearchBar.State.Chips[0].Display)
+	})
+
+	t.Run("counts follow newly loaded entries", func(t *testing.T) {
+		m := newFacetTestModel(entries[:1], "app")
+		m = pressFacetKey(m, "a")
+		assert.Len(t, m.facetCounts(), 1)
+
+		m.Tabs[0].Entries = append(m.Tabs[0].Entries, entries[1:]...)
+		assert.Equal(t, []client.FieldCount{{Value: "api", Count: 2}, {Value: "web", Count: 1}}, m.facetCounts())
+	})
+
+	t.Run("high cardinality shows top values", func(t *testing.T) {
+		many := make([]client.LogEntry, 0, facetTopN+5)
+		for i := 0; i < facetTopN+5; i++ {
+			many = append(many, client.LogEntry{Fields: ty.MI{"id": fmt.Sprintf("id-%02d", i)}})
+		}
+		m := newFacetTestModel(many, "id")
+		m = pressFacetKey(m, "a")
+
+		assert.Contains(t, m.View(), "+5 more")
+		for i := 0; i < facetTopN+5; i++ {
+			m = pressFacetKey(m, "j")
+		}
+		assert.Equal(t, facetTopN-1, m.FacetCursor)
+	})
+
+	t.Run("esc closes", func(t *testing.T) {
+		m := newFacetTestModel(entries, "app")
+		m = pressFacetKey(m, "a")
+		m = pressFacetKey(m, "esc")
+		assert.Equal(t, FocusList, m.Focus)
+	})
+}
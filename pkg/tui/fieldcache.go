@@ -0,0 +1,348 @@
+package tui
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/factory"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// DefaultFieldValueCacheTTL is how long fetched field values are reused.
+	DefaultFieldValueCacheTTL = 5 * time.Minute
+
+	// maxCachedFieldValues bounds the values kept per field so high
+	// cardinality fields (request ids, trace ids) don't grow without limit.
+	maxCachedFieldValues = 500
+
+	// maxCachedFieldKeys bounds the number of context/field/window keys; the
+	// oldest entry is evicted when it is reached.
+	maxCachedFieldKeys = 1000
+)
+
+// fieldCacheKey identifies the values of one field for a context over a
+// time window, so tabs on the same context and range share them.
+type fieldCacheKey struct {
+	contextID string
+	field     string
+	window    string
+}
+
+type fieldCacheEntry struct {
+	values  []string
+	fetched time.Time
+}
+
+// FieldValueCache is a TTL-bounded cache of field values shared across tabs.
+// A TTL of zero or less disables it.
+type FieldValueCache struct {
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[fieldCacheKey]fieldCacheEntry
+	pending map[fieldCacheKey]bool
+	now     func() time.Time
+}
+
+// NewFieldValueCache creates a cache keeping values for ttl.
+func NewFieldValueCache(ttl time.Duration) *FieldValueCache {
+	return &FieldValueCache{
+		TTL:     ttl,
+		entries: make(map[fieldCacheKey]fieldCacheEntry),
+		pending: make(map[fieldCacheKey]bool),
+		now:     time.Now,
+	}
+}
+
+func (c *FieldValueCache) enabled() bool {
+	return c != nil && c.TTL > 0
+}
+
+// Get returns the cached values of field, if present and not expired.
+func (c *FieldValueCache) Get(contextID, window, field string) ([]string, bool) {
+	if !c.enabled() {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := fieldCacheKey{contextID: contextID, field: field, window: window}
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.now().Sub(entry.fetched) > c.TTL {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.values, true
+}
+
+// Put stores the values of each field. Fields with no values are stored too,
+// so a field known to be empty is not fetched again until it expires.
+func (c *FieldValueCache) Put(contextID, window string, values map[string][]string) {
+	if !c.enabled() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for field, vals := range values {
+		if len(vals) > maxCachedFieldValues {
+			vals = vals[:maxCachedFieldValues]
+		}
+		key := fieldCacheKey{contextID: contextID, field: field, window: window}
+		if _, exists := c.entries[key]; !exists && len(c.entries) >= maxCachedFieldKeys {
+			c.evictOldest()
+		}
+		c.entries[key] = fieldCacheEntry{values: vals, fetched: now}
+		delete(c.pending, key)
+	}
+}
+
+// Values returns all unexpired cached fields for a context and window.
+func (c *FieldValueCache) Values(contextID, window string) map[string][]string {
+	if !c.enabled() {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	values := make(map[string][]string)
+	now := c.now()
+	for key, entry := range c.entries {
+		if key.contextID == contextID && key.window == window && now.Sub(entry.fetched) <= c.TTL {
+			values[key.field] = entry.values
+		}
+	}
+	return values
+}
+
+// MarkPending records a fetch in flight for field and reports whether one
+// was already running, so typing doesn't start the same request twice.
+func (c *FieldValueCache) MarkPending(contextID, window, field string) bool {
+	if !c.enabled() {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := fieldCacheKey{contextID: contextID, field: field, window: window}
+	if c.pending[key] {
+		return true
+	}
+	c.pending[key] = true
+	return false
+}
+
+// clearPending forgets an in-flight fetch that failed.
+func (c *FieldValueCache) clearPending(contextID, window, field string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, fieldCacheKey{contextID: contextID, field: field, window: window})
+}
+
+// InvalidateContext drops every cached field of contextID.
+func (c *FieldValueCache) InvalidateContext(contextID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if key.contextID == contextID {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Clear drops every cached field.
+func (c *FieldValueCache) Clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[fieldCacheKey]fieldCacheEntry)
+	c.pending = make(map[fieldCacheKey]bool)
+}
+
+// Len returns the number of cached fields, expired ones included.
+func (c *FieldValueCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// evictOldest removes the least recently fetched entry. Callers hold mu.
+func (c *FieldValueCache) evictOldest() {
+	var oldestKey fieldCacheKey
+	var oldest time.Time
+	first := true
+	for key, entry := range c.entries {
+		if first || entry.fetched.Before(oldest) {
+			oldestKey, oldest, first = key, entry.fetched, false
+		}
+	}
+	if !first {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// tabWindow returns the cache window of a tab from its search range.
+func tabWindow(tab *Tab) string {
+	if tab == nil || tab.Search == nil {
+		return ""
+	}
+	r := tab.Search.Range
+	if r.Last.Set && r.Last.Value != "" {
+		return "last:" + r.Last.Value
+	}
+	return r.Gte.Value + ".." + r.Lte.Value
+}
+
+// FieldValuesMsg delivers values fetched for the value autocomplete.
+type FieldValuesMsg struct {
+	ContextID string
+	Window    string
+	Field     string
+	Values    map[string][]string
+	Err       error
+}
+
+// fetchFieldValuesCmd fetches the values of field for the tab's context
+// through the search factory.
+func fetchFieldValuesCmd(searchFactory factory.SearchFactory, tab *Tab, field string, runtimeVars map[string]string) tea.Cmd {
+	contextID := tab.ContextID
+	window := tabWindow(tab)
+	inherits := tab.Inherits
+	search := client.LogSearch{}
+	if tab.Search != nil {
+		search = *tab.Search
+	}
+
+	return func() tea.Msg {
+		log.Printf("[DEBUG] TUI fetchFieldValuesCmd: contextID=%s, field=%s, window=%s", contextID, field, window)
+		values, err := searchFactory.GetFieldValues(context.Background(), contextID, inherits, search, []string{field}, runtimeVars)
+		return FieldValuesMsg{ContextID: contextID, Window: window, Field: field, Values: values, Err: err}
+	}
+}
+
+// fillFieldValuesFromCache adds cached values for fields the tab lacks.
+func (m *Model) fillFieldValuesFromCache(tab *Tab) {
+	cached := m.FieldCache.Values(tab.ContextID, tabWindow(tab))
+	if len(cached) == 0 {
+		return
+	}
+	if tab.FieldValues == nil {
+		tab.FieldValues = make(map[string][]string)
+	}
+	for field, values := range cached {
+		if _, ok := tab.FieldValues[field]; !ok {
+			tab.FieldValues[field] = values
+		}
+	}
+}
+
+// valueFieldUnderInput returns the field of a "field<op>" input being typed,
+// or "" when the input is not a field comparison.
+func valueFieldUnderInput(input string) string {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(input, "$") {
+		return ""
+	}
+	// Same detection as generateSuggestions
+	if idx := strings.IndexAny(input, "=!~<>"); idx != -1 {
+		return strings.TrimSpace(input[:idx])
+	}
+	return ""
+}
+
+// ensureFieldValues returns a command fetching the values of the field being
+// typed in the search bar, when neither the tab nor the cache has them.
+func (m *Model) ensureFieldValues() tea.Cmd {
+	tab := m.CurrentTab()
+	if tab == nil || m.SearchFactory == nil {
+		return nil
+	}
+	field := valueFieldUnderInput(m.SearchBar.State.CurrentInput)
+	if field == "" {
+		return nil
+	}
+	if _, ok := m.SearchBar.FieldValues[field]; ok {
+		return nil
+	}
+
+	window := tabWindow(tab)
+	if values, ok := m.FieldCache.Get(tab.ContextID, window, field); ok {
+		m.setSearchBarFieldValues(field, values)
+		return nil
+	}
+	if !m.FieldCache.enabled() || m.FieldCache.MarkPending(tab.ContextID, window, field) {
+		return nil
+	}
+	return fetchFieldValuesCmd(m.SearchFactory, tab, field, m.RuntimeVars)
+}
+
+// setSearchBarFieldValues sets the values of field in the search bar and
+// refreshes the open suggestions.
+func (m *Model) setSearchBarFieldValues(field string, values []string) {
+	if m.SearchBar.FieldValues == nil {
+		m.SearchBar.FieldValues = make(map[string][]string)
+	}
+	m.SearchBar.FieldValues[field] = values
+	if tab := m.CurrentTab(); tab != nil {
+		tab.FieldValues = m.SearchBar.FieldValues
+	}
+	if m.SearchBar.State.AutocompleteOpen {
+		m.SearchBar.State.AutocompleteSuggestions = m.SearchBar.generateSuggestions()
+		if m.SearchBar.State.AutocompleteIndex >= len(m.SearchBar.State.AutocompleteSuggestions) {
+			m.SearchBar.State.AutocompleteIndex = 0
+		}
+	}
+}
+
+// handleFieldValues stores fetched values in the cache and in every tab on
+// the same context and window.
+func (m *Model) handleFieldValues(msg FieldValuesMsg) {
+	if msg.Err != nil {
+		log.Printf("[WARN] TUI handleFieldValues: fetch failed, contextID=%s, field=%s, error=%v", msg.ContextID, msg.Field, msg.Err)
+		m.FieldCache.clearPending(msg.ContextID, msg.Window, msg.Field)
+		return
+	}
+	values := msg.Values
+	if _, ok := values[msg.Field]; !ok {
+		values = map[string][]string{msg.Field: nil}
+		for field, vals := range msg.Values {
+			values[field] = vals
+		}
+	}
+	m.FieldCache.Put(msg.ContextID, msg.Window, values)
+
+	current := m.CurrentTab()
+	for _, tab := range m.Tabs {
+		if tab.ContextID != msg.ContextID || tabWindow(tab) != msg.Window {
+			continue
+		}
+		if tab == current {
+			for field, vals := range values {
+				m.setSearchBarFieldValues(field, vals)
+			}
+			continue
+		}
+		m.fillFieldValuesFromCache(tab)
+	}
+}
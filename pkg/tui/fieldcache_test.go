@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldValueCache_TTL(t *testing.T) {
+	now := time.Now()
+	c := NewFieldValueCache(time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Put("prod", "last:1h", map[string][]string{"level": {"INFO", "ERROR"}, "empty": nil})
+
+	values, ok := c.Get("prod", "last:1h", "level")
+	require.True(t, ok)
+	assert.Equal(t, []string{"INFO", "ERROR"}, values)
+
+	_, ok = c.Get("prod", "last:1h", "empty")
+	assert.True(t, ok, "empty results are cached")
+
+	_, ok = c.Get("prod", "last:15m", "level")
+	assert.False(t, ok, "other windows miss")
+
+	now = now.Add(2 * time.Minute)
+	_, ok = c.Get("prod", "last:1h", "level")
+	assert.False(t, ok, "expired")
+}
+
+func TestFieldValueCache_Disabled(t *testing.T) {
+	c := NewFieldValueCache(0)
+	c.Put("prod", "", map[string][]string{"level": {"INFO"}})
+	_, ok := c.Get("prod", "", "level")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestFieldValueCache_Bounds(t *testing.T) {
+	c := NewFieldValueCache(time.Minute)
+
+	many := make([]string, maxCachedFieldValues+10)
+	c.Put("prod", "", map[string][]string{"request_id": many})
+	values, _ := c.Get("prod", "", "request_id")
+	assert.Len(t, values, maxCachedFieldValues)
+
+	for i := 0; i < maxCachedFieldKeys+5; i++ {
+		c.Put("prod", "", map[string][]string{fmt.Sprintf("f%d", i): {"x"}})
+	}
+	assert.Equal(t, maxCachedFieldKeys, c.Len())
+}
+
+func TestFieldValueCache_InvalidateAndClear(t *testing.T) {
+	c := NewFieldValueCache(time.Minute)
+	c.Put("prod", "", map[string][]string{"level": {"INFO"}})
+	c.Put("staging", "", map[string][]string{"level": {"WARN"}})
+
+	c.InvalidateContext("prod")
+	_, ok := c.Get("prod", "", "level")
+	assert.False(t, ok)
+	_, ok = c.Get("staging", "", "level")
+	assert.True(t, ok)
+
+	c.Clear()
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestFieldCache_SharedAcrossTabs(t *testing.T) {
+	m := New(sessionTestConfig("prod"), nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	search := &client.LogSearch{}
+	search.Range.Last.S("1h")
+	m.Tabs = []*Tab{
+		{ID: "t1", ContextID: "prod", Search: search, FieldValues: map[string][]string{}},
+		{ID: "t2", ContextID: "prod", Search: search.Clone(), FieldValues: map[string][]string{}},
+	}
+
+	updated, _ := m.Update(LogEntryMsg{TabID: "t1", Fields: ty.UniSet[string]{"level": {"INFO"}}})
+	m = updated.(Model)
+
+	m.switchToTab(1)
+	assert.Equal(t, []string{"INFO"}, m.SearchBar.FieldValues["level"])
+}
+
+func TestFieldCache_FetchesMissingValues(t *testing.T) {
+	store := NewInMemoryLogStore()
+	store.AddEntries("prod", []client.LogEntry{
+		{Message: "a", Fields: ty.MI{"app": "api"}},
+		{Message: "b", Fields: ty.MI{"app": "worker"}},
+	})
+	m := New(sessionTestConfig("prod"), nil, &MockSearchFactory{Store: store})
+	m.Tabs = []*Tab{{ID: "t1", ContextID: "prod", Search: &client.LogSearch{}, FieldValues: map[string][]string{}}}
+	m.switchToTab(0)
+	m.Focus = FocusSearch
+	m.SearchBar.Focus()
+	m.SearchBar.State.CurrentInput = "app"
+	m.SearchBar.TextInput.SetValue("app")
+	m.SearchBar.TextInput.CursorEnd()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("=")})
+	m = updated.(Model)
+	require.NotNil(t, cmd)
+
+	msg := findFieldValuesMsg(cmd())
+	require.NotNil(t, msg, "a FieldValuesMsg is returned")
+	updated, _ = m.Update(*msg)
+	m = updated.(Model)
+
+	assert.ElementsMatch(t, []string{"api", "worker"}, m.SearchBar.FieldValues["app"])
+	values, ok := m.FieldCache.Get("prod", tabWindow(m.Tabs[0]), "app")
+	assert.True(t, ok)
+	assert.Len(t, values, 2)
+
+	// Refreshing the context drops its cached values
+	m.refreshCurrentTab()
+	_, ok = m.FieldCache.Get("prod", tabWindow(m.Tabs[0]), "app")
+	assert.False(t, ok)
+}
+
+func TestFieldCache_ClearKey(t *testing.T) {
+	m := New(sessionTestConfig("prod"), nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	m.FieldCache.Put("prod", "", map[string][]string{"level": {"INFO"}})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	m = updated.(Model)
+	assert.Equal(t, 0, m.FieldCache.Len())
+}
+
+// findFieldValuesMsg returns the FieldValuesMsg produced by msg, running the
+// commands of a batch.
+func findFieldValuesMsg(msg tea.Msg) *FieldValuesMsg {
+	switch msg := msg.(type) {
+	case FieldValuesMsg:
+		return &msg
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			if cmd == nil {
+				continue
+			}
+			if found := findFieldValuesMsg(cmd()); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
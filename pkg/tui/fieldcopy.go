@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/atotto/clipboard"
+	"github.com/bascanada/logviewer/pkg/log/client"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// writeClipboard is the clipboard writer, replaced in tests.
+var writeClipboard = clipboard.WriteAll
+
+// entryFieldKeys returns the field names of entry in display order.
+func entryFieldKeys(entry client.LogEntry) []string {
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldValueString returns the full value of a field, with objects and
+// arrays as indented JSON.
+func fieldValueString(val interface{}) string {
+	switch val.(type) {
+	case map[string]interface{}, []interface{}:
+		if b, err := json.MarshalIndent(val, "", "  "); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+// selectedEntry returns the entry under the cursor of the current tab.
+func (m *Model) selectedEntry() (client.LogEntry, bool) {
+	tab := m.CurrentTab()
+	if tab == nil || tab.Cursor < 0 || tab.Cursor >= len(tab.Entries) {
+		return client.LogEntry{}, false
+	}
+	return tab.Entries[tab.Cursor], true
+}
+
+// highlightedField returns the field highlighted in the entry details. The
+// highlight is kept by name so it stays on the same field across entries,
+// falling back to the first field when the entry does not have it.
+func (m *Model) highlightedField(entry client.LogEntry) string {
+	keys := entryFieldKeys(entry)
+	if len(keys) == 0 {
+		return ""
+	}
+	if _, ok := entry.Fields[m.DetailField]; ok {
+		return m.DetailField
+	}
+	return keys[0]
+}
+
+// moveFieldCursor moves the entry details highlight by delta fields.
+func (m *Model) moveFieldCursor(delta int) {
+	entry, ok := m.selectedEntry()
+	if !ok {
+		return
+	}
+	keys := entryFieldKeys(entry)
+	if len(keys) == 0 {
+		return
+	}
+
+	current := sort.SearchStrings(keys, m.highlightedField(entry))
+	next := current + delta
+	if next < 0 {
+		next = 0
+	}
+	if next >= len(keys) {
+		next = len(keys) - 1
+	}
+	m.DetailField = keys[next]
+	m.updateSidebarContent()
+}
+
+// copyFieldToClipboard copies the untruncated value of the highlighted field.
+func (m *Model) copyFieldToClipboard() tea.Cmd {
+	entry, ok := m.selectedEntry()
+	if !ok {
+		return m.showStatusMessage("No entry selected")
+	}
+	field := m.highlightedField(entry)
+	if field == "" {
+		return m.showStatusMessage("No fields in this entry")
+	}
+
+	if err := writeClipboard(fieldValueString(entry.Fields[field])); err != nil {
+		return m.showStatusMessage(fmt.Sprintf("Clipboard error: %v", err))
+	}
+	return m.showStatusMessage(fmt.Sprintf("Copied %s to clipboard", field))
+}
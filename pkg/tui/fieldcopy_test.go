@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyFieldValue(t *testing.T) {
+	var copied string
+	orig := writeClipboard
+	defer func() { writeClipboard = orig }()
+	writeClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+
+	long := strings.Repeat("x", 500)
+	entries := []client.LogEntry{
+		{Fields: ty.MI{"app": "api", "long": long, "req": map[string]interface{}{"id": 1.0}, "trace_id": "abc"}},
+		{Fields: ty.MI{"trace_id": "def"}},
+	}
+	m := newFacetTestModel(entries)
+	m.DetailsVisible = true
+
+	// Starts on the first field
+	m = pressFacetKey(m, "Y")
+	assert.Equal(t, "api", copied)
+	assert.Equal(t, "Copied app to clipboard", m.StatusBar.Message)
+
+	m = pressFacetKey(m, "J")
+	m = pressFacetKey(m, "Y")
+	assert.Equal(t, long, copied)
+
+	m = pressFacetKey(m, "J")
+	m = pressFacetKey(m, "Y")
+	assert.Equal(t, "{\n  \"id\": 1\n}", copied)
+
+	// Stays on the field by name when moving to another entry
+	m = pressFacetKey(m, "J")
+	m = pressFacetKey(m, "J")
+	assert.Equal(t, "trace_id", m.DetailField)
+	m = pressFacetKey(m, "j")
+	m = pressFacetKey(m, "Y")
+	assert.Equal(t, "def", copied)
+
+	m = pressFacetKey(m, "K")
+	assert.Equal(t, "trace_id", m.DetailField)
+}
@@ -0,0 +1,99 @@
+package tui
+
+import "unicode"
+
+// Scores of a fuzzy match: each matched character is worth fuzzyMatch, more
+// at the start of a word of the candidate or right after the previous match,
+// and each character skipped between two matches costs fuzzyGap.
+const (
+	fuzzyMatch       = 1
+	fuzzyWordStart   = 5
+	fuzzyConsecutive = 3
+	fuzzyGap         = 1
+)
+
+// fuzzyScore matches the characters of pattern in order in candidate, case
+// insensitively, so "trid" matches "trace_id". It returns the score of the
+// best alignment, higher for matches at word starts and in runs, and false
+// when candidate doesn't contain pattern as a subsequence.
+func fuzzyScore(pattern, candidate string) (int, bool) {
+	p := lowerRunes(pattern)
+	c := []rune(candidate)
+	if len(p) == 0 {
+		return 0, true
+	}
+	if len(p) > len(c) {
+		return 0, false
+	}
+	lower := lowerRunes(candidate)
+
+	// best[j] is the best score of the pattern so far with its last matched
+	// character at candidate index j, or noMatch
+	const noMatch = -1 << 30
+	best := make([]int, len(c))
+	next := make([]int, len(c))
+	for j := range c {
+		best[j] = noMatch
+		if lower[j] == p[0] {
+			best[j] = fuzzyMatch + wordStartBonus(c, j) // Leading skips are free
+		}
+	}
+	for i := 1; i < len(p); i++ {
+		for j := range c {
+			next[j] = noMatch
+			if lower[j] != p[i] {
+				continue
+			}
+			for k := 0; k < j; k++ {
+				if best[k] == noMatch {
+					continue
+				}
+				score := best[k] + fuzzyMatch + wordStartBonus(c, j)
+				if k == j-1 {
+					score += fuzzyConsecutive
+				} else {
+					score -= (j - k - 1) * fuzzyGap
+				}
+				if score > next[j] {
+					next[j] = score
+				}
+			}
+		}
+		best, next = next, best
+	}
+
+	top, found := noMatch, false
+	for _, score := range best {
+		if score > top {
+			top, found = score, true
+		}
+	}
+	return top, found
+}
+
+// wordStartBonus returns fuzzyWordStart when c[j] starts a word: the first
+// character, one after a separator, or an upper case letter after a lower
+// case one as in traceId.
+func wordStartBonus(c []rune, j int) int {
+	if j == 0 {
+		return fuzzyWordStart
+	}
+	prev := c[j-1]
+	if prev == '_' || prev == '.' || prev == '-' || prev == ' ' || prev == '/' {
+		return fuzzyWordStart
+	}
+	if unicode.IsUpper(c[j]) && unicode.IsLower(prev) {
+		return fuzzyWordStart
+	}
+	return 0
+}
+
+// lowerRunes returns the runes of s in lower case, one for one unlike
+// strings.ToLower for a few special cases.
+func lowerRunes(s string) []rune {
+	out := []rune(s)
+	for i, r := range out {
+		out[i] = unicode.ToLower(r)
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	_, ok := fuzzyScore("trid", "trace_id")
+	assert.True(t, ok, "abbreviation")
+	_, ok = fuzzyScore("TRID", "Trace_Id")
+	assert.True(t, ok, "case-insensitive")
+	_, ok = fuzzyScore("dirt", "trace_id")
+	assert.False(t, ok, "out of order")
+	_, ok = fuzzyScore("trace_idx", "trace_id")
+	assert.False(t, ok, "longer than the candidate")
+
+	// Word starts and runs score higher than scattered characters
+	wordStarts, _ := fuzzyScore("trid", "trace_id")
+	scattered, _ := fuzzyScore("trid", "tarpaulin_rigid")
+	assert.Greater(t, wordStarts, scattered)
+	camel, _ := fuzzyScore("tid", "traceId")
+	flat, _ := fuzzyScore("tid", "traceid")
+	assert.Greater(t, camel, flat)
+
+	// Gaps cost
+	short, _ := fuzzyScore("ab", "a_b")
+	long, _ := fuzzyScore("ab", "axxxxb")
+	assert.Greater(t, short, long)
+
+	// The best alignment is kept, not the first one
+	best, _ := fuzzyScore("ti", "tx_trace_id")
+	assert.Equal(t, 7, best, "t of trace then i of id, not the first t and its longer gap")
+}
+
+func TestSuggestFields_Ranking(t *testing.T) {
+	sb := NewSearchBar()
+	sb.AvailableFields = []string{"user_id", "tarpaulin_rigid", "trace_id", "trid_source", "xtrid", "trace_identifier", "level"}
+
+	texts := func(prefix string) []string {
+		var out []string
+		for _, s := range sb.suggestFields(prefix) {
+			out = append(out, s.Text)
+		}
+		return out
+	}
+
+	// Prefix matches first, then fuzzy by score with same-score ties going to
+	// the shorter field
+	assert.Equal(t, []string{"trid_source", "trace_id", "trace_identifier", "xtrid", "tarpaulin_rigid"}, texts("trid"))
+
+	// A match spread over long gaps is not suggested
+	assert.Equal(t, []string{"level"}, texts("lvl"))
+	assert.NotContains(t, texts("ud"), "tarpaulin_rigid")
+
+	// Everything, alphabetically, with no input
+	assert.Len(t, texts(""), len(sb.AvailableFields))
+	assert.Equal(t, "level", texts("")[0])
+}
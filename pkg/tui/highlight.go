@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HighlightPattern returns the pattern of the text to highlight in the log
+// list: the free-text chips, case-insensitive, and the values of the regex
+// chips, OR groups included. Negated chips and regexes that don't compile are
+// left out. It returns nil when there is nothing to highlight.
+func (s *SearchBar) HighlightPattern() *regexp.Regexp {
+	var literals, patterns []string
+	for _, chip := range s.State.Chips {
+		switch chip.Type {
+		case ChipTypeFreeText:
+			if text := strings.TrimSpace(chip.Text); text != "" {
+				literals = append(literals, text)
+			}
+		case ChipTypeField:
+			if chip.Operator == "~=" {
+				patterns = append(patterns, chip.Value)
+			}
+		case ChipTypeFilterGroup:
+			patterns = appendRegexValues(patterns, chip.GroupFilter)
+		}
+	}
+
+	// Longer terms first so "timeout" wins over "time" at the same position
+	sort.SliceStable(literals, func(i, j int) bool { return len(literals[i]) > len(literals[j]) })
+	parts := make([]string, 0, len(literals)+len(patterns))
+	for _, text := range literals {
+		parts = append(parts, "(?i:"+regexp.QuoteMeta(text)+")")
+	}
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		if _, err := regexp.Compile(pattern); err == nil {
+			parts = append(parts, "(?:"+pattern+")")
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(strings.Join(parts, "|"))
+}
+
+// appendRegexValues appends the values of the non-negated regex conditions
+// of filter.
+func appendRegexValues(values []string, filter *client.Filter) []string {
+	if filter == nil {
+		return values
+	}
+	if filter.Field != "" {
+		if filter.Op == operator.Regex && !filter.Negate {
+			values = append(values, filter.Value)
+		}
+		return values
+	}
+	// A NOT group matches the entries without its conditions
+	if filter.Logic == client.LogicNot {
+		return values
+	}
+	for i := range filter.Filters {
+		values = appendRegexValues(values, &filter.Filters[i])
+	}
+	return values
+}
+
+// highlightMatches renders every match of re in the visible text of s with
+// style. Escape sequences are kept as they are and are not matched against,
+// and the colors in effect are opened again after each match since the style
+// resets them, so s may be a line already rendered by lipgloss.
+func highlightMatches(s string, re *regexp.Regexp, style lipgloss.Style) string {
+	if re == nil || s == "" {
+		return s
+	}
+
+	// plain is the visible text of s, offsets[i] the index in s of plain[i]
+	var plain strings.Builder
+	offsets := make([]int, 0, len(s))
+	for i := 0; i < len(s); {
+		if j := escapeEnd(s, i); j > i {
+			i = j
+			continue
+		}
+		plain.WriteByte(s[i])
+		offsets = append(offsets, i)
+		i++
+	}
+	matches := re.FindAllStringIndex(plain.String(), -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	active := ""
+	next := 0 // Index in plain of the next byte to write
+	for i := 0; i < len(s); {
+		if j := escapeEnd(s, i); j > i {
+			b.WriteString(s[i:j])
+			active = activeSGR(active, s[i:j])
+			i = j
+			continue
+		}
+
+		// Write the visible run up to the next escape sequence or match boundary
+		for len(matches) > 0 && matches[0][1] <= next {
+			matches = matches[1:]
+		}
+		end := len(offsets)
+		inMatch := false
+		if len(matches) > 0 {
+			if matches[0][0] <= next {
+				inMatch, end = true, matches[0][1]
+			} else {
+				end = matches[0][0]
+			}
+		}
+		runEnd := next
+		for runEnd < end && offsets[runEnd] == i+(runEnd-next) {
+			runEnd++
+		}
+		run := s[i : i+(runEnd-next)]
+		if inMatch {
+			b.WriteString(style.Render(run))
+			b.WriteString(active)
+		} else {
+			b.WriteString(run)
+		}
+		i += runEnd - next
+		next = runEnd
+	}
+	return b.String()
+}
+
+// escapeEnd returns the index after the CSI escape sequence starting at i in
+// s, or i when there is none.
+func escapeEnd(s string, i int) int {
+	if s[i] != '\x1b' || i+1 >= len(s) || s[i+1] != '[' {
+		return i
+	}
+	j := i + 2
+	for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+		j++
+	}
+	if j >= len(s) {
+		return len(s)
+	}
+	return j + 1
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ansiStyle returns a renderer's style that always emits colors, unlike the
+// default one without a terminal.
+func ansiStyle() lipgloss.Style {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(termenv.ANSI)
+	return r.NewStyle().Reverse(true)
+}
+
+func TestHighlightPattern(t *testing.T) {
+	sb := NewSearchBar()
+	assert.Nil(t, sb.HighlightPattern())
+
+	sb.State.Chips = []Chip{
+		{Type: ChipTypeFreeText, Text: "Time"},
+		{Type: ChipTypeFreeText, Text: "timeout"},
+		sb.parseInput("message~=code [0-9]+"),
+		sb.parseInput("message~=("),   // does not compile
+		sb.parseInput("service!~=db"), // negated
+		sb.parseInput("level=ERROR"),
+		{Type: ChipTypeFilterGroup, GroupFilter: &client.Filter{Logic: client.LogicOr, Filters: []client.Filter{
+			{Field: "host", Op: "regex", Value: "web-[a-z]+"},
+			{Logic: client.LogicNot, Filters: []client.Filter{{Field: "host", Op: "regex", Value: "db"}}},
+		}}},
+	}
+	re := sb.HighlightPattern()
+	require.NotNil(t, re)
+	assert.Equal(t, []string{"TIMEOUT", "code 42", "web-api", "time"},
+		re.FindAllString("TIMEOUT code 42 on web-api db ERROR time", -1))
+}
+
+func TestHighlightMatches(t *testing.T) {
+	style := ansiStyle()
+	re := (&SearchBar{State: ChipSearchState{Chips: []Chip{
+		{Type: ChipTypeFreeText, Text: "timeout"},
+		{Type: ChipTypeFreeText, Text: "db"},
+	}}}).HighlightPattern()
+	hl := style.Render
+
+	assert.Equal(t, "plain", highlightMatches("plain", re, style))
+	assert.Equal(t, "a "+hl("timeout")+" on "+hl("db"), highlightMatches("a timeout on db", re, style),
+		"each match is highlighted")
+
+	// The colors in effect are restored after a match, and a match split by
+	// an escape sequence is highlighted on both sides of it
+	red := "\x1b[31m"
+	line := red + "time" + "\x1b[1m" + "out" + ansiReset + " db"
+	assert.Equal(t, red+hl("time")+red+"\x1b[1m"+hl("out")+red+"\x1b[1m"+ansiReset+" "+hl("db"),
+		highlightMatches(line, re, style))
+	assert.Equal(t, "timeout db", stripSGR(highlightMatches(line, re, style)))
+}
+
+func TestRenderLogEntry_HighlightsMatches(t *testing.T) {
+	entry := client.LogEntry{Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), Level: "ERROR",
+		Message: "request timeout after " + strings.Repeat("x", 200) + " timeout"}
+	m := newFacetTestModel([]client.LogEntry{entry})
+	m.Styles.LogMatch = ansiStyle()
+	m.SearchBar.State.Chips = []Chip{{Type: ChipTypeFreeText, Text: "timeout"}}
+	m.highlight = m.SearchBar.HighlightPattern()
+	marked := m.Styles.LogMatch.Render("timeout")
+
+	// No-wrap: the match cut by the truncation is not highlighted
+	line := m.renderLogEntry(entry, false, 80, m.Tabs[0])
+	assert.Equal(t, 1, strings.Count(line, marked))
+	assert.Equal(t, 80, lipgloss.Width(line))
+
+	// Wrap: every match is highlighted and wrapLine keeps the sequences whole
+	m.LineWrapping = true
+	line = m.renderLogEntry(entry, false, 80, m.Tabs[0])
+	assert.Equal(t, 2, strings.Count(line, marked))
+	var wrapped []string
+	for _, l := range strings.Split(line, "\n") {
+		wrapped = append(wrapped, wrapLine(l, 50)...)
+	}
+	assert.Equal(t, 2, strings.Count(strings.Join(wrapped, ""), marked))
+	for _, l := range wrapped {
+		assert.LessOrEqual(t, lipgloss.Width(l), 50)
+	}
+}
+
+// stripSGR removes the SGR sequences of s.
+func stripSGR(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if j := escapeEnd(s, i); j > i {
+			i = j
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
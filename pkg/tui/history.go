@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+)
+
+// maxHistory is the number of searches kept in the history file.
+const maxHistory = 100
+
+// DefaultHistoryPath returns ~/.logviewer/history.json.
+func DefaultHistoryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, config.DefaultConfigDir, "history.json"), nil
+}
+
+// LoadHistory reads the searches saved at path, oldest first. A missing file
+// returns an empty history.
+func LoadHistory(path string) ([][]Chip, error) {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var history [][]Chip
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("parsing history file %s: %w", path, err)
+	}
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+	return history, nil
+}
+
+// SaveHistory writes history to path.
+func SaveHistory(path string, history [][]Chip) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Chips may hold filter values, keep the file private like session.yaml
+	return os.WriteFile(path, data, 0600)
+}
+
+// LoadHistory sets the file the searches are saved to and reads the ones
+// already there. Errors leave the history empty but still saved to path.
+func (s *SearchBar) LoadHistory(path string) error {
+	s.HistoryPath = path
+	history, err := LoadHistory(path)
+	s.History = history
+	s.historyOffset = 0
+	return err
+}
+
+// RecordHistory appends the current chips, without the context chip tied to
+// the tab, to the history and saves it to HistoryPath if set. Empty searches
+// and repeats of the last search are not recorded.
+func (s *SearchBar) RecordHistory() {
+	s.historyOffset = 0
+	chips := historyChips(s.State.Chips)
+	if len(chips) == 0 {
+		return
+	}
+	if n := len(s.History); n > 0 && reflect.DeepEqual(s.History[n-1], chips) {
+		return
+	}
+
+	s.History = append(s.History, chips)
+	if len(s.History) > maxHistory {
+		s.History = s.History[len(s.History)-maxHistory:]
+	}
+	if s.HistoryPath == "" {
+		return
+	}
+	if err := SaveHistory(s.HistoryPath, s.History); err != nil {
+		log.Printf("[WARN] TUI RecordHistory: failed to write %s: %v", s.HistoryPath, err)
+	}
+}
+
+// recallHistory moves through the history, older for a negative step and
+// newer for a positive one, replacing the chips with the recalled search.
+// Going newer than the last search brings back the chips from before the
+// recall started. It returns false when there is nothing to move to.
+func (s *SearchBar) recallHistory(step int) bool {
+	offset := s.historyOffset - step
+	if offset < 0 || offset > len(s.History) {
+		return false
+	}
+	if s.historyOffset == 0 {
+		s.historyDraft = historyChips(s.State.Chips)
+	}
+	s.historyOffset = offset
+
+	recalled := s.historyDraft
+	if offset > 0 {
+		recalled = s.History[len(s.History)-offset]
+	}
+	chips := make([]Chip, 0, len(recalled)+1)
+	for _, chip := range s.State.Chips {
+		if chip.Type == ChipTypeContext {
+			chips = append(chips, chip)
+		}
+	}
+	s.State.Chips = append(chips, copyChips(recalled)...)
+	s.State.SelectedChip = -1
+	return true
+}
+
+// historyChips returns a copy of chips without the context chips.
+func historyChips(chips []Chip) []Chip {
+	var kept []Chip
+	for _, chip := range chips {
+		if chip.Type != ChipTypeContext {
+			kept = append(kept, chip)
+		}
+	}
+	return copyChips(kept)
+}
+
+// copyChips deep copies chips so the history doesn't share their filters.
+func copyChips(chips []Chip) []Chip {
+	if chips == nil {
+		return nil
+	}
+	copied := make([]Chip, len(chips))
+	copy(copied, chips)
+	for i := range copied {
+		if copied[i].GroupFilter != nil {
+			copied[i].GroupFilter = copied[i].GroupFilter.Clone()
+		}
+	}
+	return copied
+}
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchBar_HistoryRecordAndRecall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	up := tea.KeyMsg{Type: tea.KeyUp}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	press := func(sb SearchBar, key tea.KeyMsg) SearchBar {
+		sb, _ = sb.handleKey(key)
+		return sb
+	}
+	search := func(sb *SearchBar, inputs ...string) {
+		sb.State.Chips = []Chip{{Type: ChipTypeContext, Value: "prod"}}
+		for _, input := range inputs {
+			sb.State.AddChip(sb.parseInput(input))
+		}
+		sb.RecordHistory()
+	}
+
+	sb := NewSearchBar()
+	require.NoError(t, sb.LoadHistory(path))
+	search(&sb, "level=ERROR", "last:1h")
+	search(&sb, "level=ERROR", "last:1h") // consecutive repeat
+	search(&sb)                           // only the context chip
+	search(&sb, "app=api")
+	sb.State.Chips = append(sb.State.Chips, Chip{
+		Type: ChipTypeFilterGroup, GroupLogic: "OR",
+		GroupFilter: &client.Filter{Logic: client.LogicOr, Filters: []client.Filter{{Field: "app", Value: "web"}}},
+	})
+	sb.RecordHistory()
+	require.Len(t, sb.History, 3)
+
+	// Reloaded from disk
+	sb = NewSearchBar()
+	require.NoError(t, sb.LoadHistory(path))
+	require.Len(t, sb.History, 3)
+	sb.State.Chips = []Chip{{Type: ChipTypeContext, Value: "staging"}}
+	sb.State.AddChip(sb.parseInput("draft=1"))
+
+	sb = press(sb, up)
+	assert.Len(t, sb.State.Chips, 3)
+	assert.Equal(t, ChipTypeContext, sb.State.Chips[0].Type)
+	assert.Equal(t, "staging", sb.State.Chips[0].Value, "the tab's context is kept")
+	assert.Equal(t, "app", sb.State.Chips[2].GroupFilter.Filters[0].Field)
+
+	sb = press(sb, up)
+	sb = press(sb, up)
+	assert.Equal(t, "level=ERROR", sb.State.Chips[1].Display)
+	assert.Equal(t, "1h", sb.BuildSearchFromChips().Range.Last.Value)
+	sb = press(sb, up) // oldest
+	assert.Equal(t, "level=ERROR", sb.State.Chips[1].Display)
+
+	sb = press(sb, down)
+	sb = press(sb, down)
+	sb = press(sb, down)
+	require.Len(t, sb.State.Chips, 2, "back to the chips from before the recall")
+	assert.Equal(t, "draft=1", sb.State.Chips[1].Display)
+
+	// Recall doesn't happen while typing or with the autocomplete open
+	sb.State.CurrentInput = "lev"
+	sb = press(sb, up)
+	assert.Len(t, sb.State.Chips, 2)
+	sb.State.CurrentInput = ""
+	sb.State.AutocompleteOpen = true
+	sb = press(sb, up)
+	assert.Len(t, sb.State.Chips, 2)
+}
+
+func TestSearchBar_HistoryCap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	sb := NewSearchBar()
+	require.NoError(t, sb.LoadHistory(path))
+	for i := range maxHistory + 5 {
+		sb.State.Chips = []Chip{sb.parseInput(fmt.Sprintf("n=%d", i))}
+		sb.RecordHistory()
+	}
+	assert.Len(t, sb.History, maxHistory)
+	assert.Equal(t, "n=5", sb.History[0][0].Display)
+
+	history, err := LoadHistory(path)
+	require.NoError(t, err)
+	assert.Len(t, history, maxHistory)
+	assert.Equal(t, fmt.Sprintf("n=%d", maxHistory+4), history[maxHistory-1][0].Display)
+}
+
+func TestLoadHistory_Missing(t *testing.T) {
+	history, err := LoadHistory(filepath.Join(t.TempDir(), "none.json"))
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
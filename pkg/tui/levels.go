@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// levelField is the field the quick level toggles filter on.
+const levelField = "level"
+
+// quickLevels are the levels toggled by the 1-4 keys, in key order.
+var quickLevels = []string{"ERROR", "WARN", "INFO", "DEBUG"}
+
+// quickLevelForKey returns the level toggled by key, or "" for other keys.
+func quickLevelForKey(key string) string {
+	if len(key) != 1 || key[0] < '1' || int(key[0]-'1') >= len(quickLevels) {
+		return ""
+	}
+	return quickLevels[key[0]-'1']
+}
+
+// isLevelChip reports whether chip is the one maintained by the level toggles.
+// It is an OR group whose Field marks it, unlike groups typed in the search bar.
+func isLevelChip(chip Chip) bool {
+	return chip.Type == ChipTypeFilterGroup && chip.Field == levelField
+}
+
+// chipLevels returns the levels of the level chip in chips, in toggle order.
+func chipLevels(chips []Chip) []string {
+	for _, chip := range chips {
+		if !isLevelChip(chip) || chip.GroupFilter == nil {
+			continue
+		}
+		active := make(map[string]bool)
+		for _, f := range chip.GroupFilter.Filters {
+			active[f.Value] = true
+		}
+		var levels []string
+		for _, level := range quickLevels {
+			if active[level] {
+				levels = append(levels, level)
+			}
+		}
+		return levels
+	}
+	return nil
+}
+
+// newLevelChip returns the chip filtering on levels, matched with OR so it
+// composes with the other chips as "level in (...)".
+func newLevelChip(levels []string) Chip {
+	filter := &client.Filter{Logic: client.LogicOr}
+	for _, level := range levels {
+		filter.Filters = append(filter.Filters, client.Filter{Field: levelField, Op: operator.Equals, Value: level})
+	}
+	return Chip{
+		Type:        ChipTypeFilterGroup,
+		Field:       levelField,
+		Display:     levelField + " in (" + strings.Join(levels, ", ") + ")",
+		GroupLogic:  string(client.LogicOr),
+		GroupFilter: filter,
+	}
+}
+
+// syncLevelsFromChips sets Levels from the level chip of the search bar, so
+// the toggles follow the active tab.
+func (m *Model) syncLevelsFromChips() {
+	m.Levels = make(map[string]bool)
+	for _, level := range chipLevels(m.SearchBar.State.Chips) {
+		m.Levels[level] = true
+	}
+}
+
+// toggleLevel flips level in the active set and refreshes the current tab
+// with the matching level chip. Toggling every level off removes the chip,
+// which means no level filter: all levels are shown.
+func (m *Model) toggleLevel(level string) tea.Cmd {
+	tab := m.CurrentTab()
+	if tab == nil {
+		return nil
+	}
+	m.syncLevelsFromChips()
+	m.Levels[level] = !m.Levels[level]
+
+	var levels []string
+	for _, l := range quickLevels {
+		if m.Levels[l] {
+			levels = append(levels, l)
+		}
+	}
+
+	chips := make([]Chip, 0, len(m.SearchBar.State.Chips)+1)
+	replaced := false
+	for _, chip := range m.SearchBar.State.Chips {
+		if !isLevelChip(chip) {
+			chips = append(chips, chip)
+			continue
+		}
+		if len(levels) > 0 && !replaced {
+			chips = append(chips, newLevelChip(levels))
+			replaced = true
+		}
+	}
+	if len(levels) > 0 && !replaced {
+		chips = append(chips, newLevelChip(levels))
+	}
+	m.SearchBar.State.Chips = chips
+	m.saveSearchBarToTab(tab)
+
+	cmd := m.refreshCurrentTab()
+	m.StatusBar.UpdateFromTab(tab)
+	m.StatusBar.UpdateTimeRangeFromChips(m.SearchBar.State.Chips)
+
+	message := "Levels: all"
+	if len(levels) > 0 {
+		message = "Levels: " + strings.Join(levels, ", ")
+	}
+	return tea.Batch(cmd, m.showStatusMessage(message))
+}
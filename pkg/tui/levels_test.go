@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuickLevelToggles(t *testing.T) {
+	m := newFacetTestModel(nil, "level")
+	m.SearchBar.State.AddChip(Chip{Type: ChipTypeField, Field: "app", Operator: "=", Value: "api", Display: "app=api", Editable: true})
+
+	m = pressFacetKey(m, "1")
+	m = pressFacetKey(m, "2")
+	assert.Equal(t, map[string]bool{"ERROR": true, "WARN": true}, m.Levels)
+	require.Len(t, m.SearchBar.State.Chips, 2)
+	assert.Equal(t, "level in (ERROR, WARN)", m.SearchBar.State.Chips[1].Display)
+	assert.Equal(t, []string{"ERROR", "WARN"}, m.StatusBar.Levels)
+
+	// Composes with the other chips
+	search := m.Tabs[0].Search
+	require.NotNil(t, search.Filter)
+	assert.Equal(t, client.LogicAnd, search.Filter.Logic)
+	require.Len(t, search.Filter.Filters, 2)
+	assert.Equal(t, client.LogicOr, search.Filter.Filters[1].Logic)
+	assert.Len(t, search.Filter.Filters[1].Filters, 2)
+
+	m = pressFacetKey(m, "1")
+	assert.Equal(t, "level in (WARN)", m.SearchBar.State.Chips[1].Display)
+
+	// All off: no level filter
+	m = pressFacetKey(m, "2")
+	require.Len(t, m.SearchBar.State.Chips, 1)
+	assert.Equal(t, "app", m.SearchBar.State.Chips[0].Field)
+	assert.Empty(t, m.StatusBar.Levels)
+	assert.Equal(t, "app", m.Tabs[0].Search.Filter.Field)
+}
+
+func TestQuickLevelsFollowTab(t *testing.T) {
+	m := newFacetTestModel(nil)
+	m.Tabs = append(m.Tabs, &Tab{ID: "tab-2", ContextID: "ctx", SearchState: NewChipSearchState()})
+
+	m = pressFacetKey(m, "3")
+	assert.True(t, m.Levels["INFO"])
+
+	m.switchToTab(1)
+	assert.Empty(t, m.Levels)
+	m.switchToTab(0)
+	assert.Equal(t, map[string]bool{"INFO": true}, m.Levels)
+}
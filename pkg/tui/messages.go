@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// messageHistorySize is the number of status messages kept for the M key.
+const messageHistorySize = 20
+
+// statusMessage is a status bar message kept in the history.
+type statusMessage struct {
+	At   time.Time
+	Text string
+}
+
+// isError reports whether the message reports a failure, to style it apart.
+func (s statusMessage) isError() bool {
+	text := strings.ToLower(s.Text)
+	return strings.Contains(text, "error") || strings.Contains(text, "failed") || strings.Contains(text, "invalid")
+}
+
+// recordMessage adds a message to the history, dropping the oldest one once
+// messageHistorySize is reached.
+func (m *Model) recordMessage(text string) {
+	m.Messages = append(m.Messages, statusMessage{At: time.Now(), Text: text})
+	if over := len(m.Messages) - messageHistorySize; over > 0 {
+		m.Messages = append(m.Messages[:0], m.Messages[over:]...)
+	}
+}
+
+// messagesPageSize returns how many history rows fit in the overlay.
+func (m Model) messagesPageSize() int {
+	if n := m.Height - 12; n > 3 {
+		return n
+	}
+	return 3
+}
+
+// clampMessagesOffset keeps the history scroll offset within bounds.
+func (m *Model) clampMessagesOffset() {
+	maxOffset := len(m.Messages) - m.messagesPageSize()
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	if m.MessagesOffset > maxOffset {
+		m.MessagesOffset = maxOffset
+	}
+	if m.MessagesOffset < 0 {
+		m.MessagesOffset = 0
+	}
+}
+
+// handleMessages handles input when the message history overlay has focus.
+// Offset 0 shows the newest messages.
+func (m Model) handleMessages(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "M", "q":
+		m.Focus = FocusList
+		return m, nil
+	case "up", "k":
+		m.MessagesOffset++
+	case "down", "j":
+		m.MessagesOffset--
+	case "pgup":
+		m.MessagesOffset += m.messagesPageSize()
+	case "pgdown":
+		m.MessagesOffset -= m.messagesPageSize()
+	case "home", "g":
+		m.MessagesOffset = len(m.Messages)
+	case "end", "G":
+		m.MessagesOffset = 0
+	}
+	m.clampMessagesOffset()
+	return m, nil
+}
+
+// renderMessagesOverlay renders the status message history, oldest first.
+func (m Model) renderMessagesOverlay() string {
+	title := m.Styles.SidebarTitle.Render("Messages")
+
+	var items []string
+	if len(m.Messages) == 0 {
+		items = append(items, lipgloss.NewStyle().Foreground(ColorMuted).Render("  (no messages yet)"))
+	}
+
+	end := len(m.Messages) - m.MessagesOffset
+	start := end - m.messagesPageSize()
+	if start < 0 {
+		start = 0
+	}
+	if start > 0 {
+		items = append(items, lipgloss.NewStyle().Foreground(ColorMuted).Render(fmt.Sprintf("  ↑ %d older", start)))
+	}
+	for _, msg := range m.Messages[start:end] {
+		style := m.Styles.LogEntry
+		if msg.isError() {
+			style = lipgloss.NewStyle().Foreground(ColorError)
+		}
+		items = append(items, lipgloss.NewStyle().Foreground(ColorMuted).Render("  "+msg.At.Format("15:04:05")+" ")+style.Render(msg.Text))
+	}
+	if m.MessagesOffset > 0 {
+		items = append(items, lipgloss.NewStyle().Foreground(ColorMuted).Render(fmt.Sprintf("  ↓ %d newer", m.MessagesOffset)))
+	}
+
+	help := m.Styles.HelpBar.Render("↑↓/jk scroll • g/G oldest/newest • Esc close")
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		"",
+		strings.Join(items, "\n"),
+		"",
+		help,
+	)
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorPrimary).
+		Padding(1, 2).
+		Width(m.Width * 2 / 3).
+		Align(lipgloss.Left)
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height,
+		lipgloss.Center,
+		lipgloss.Center,
+		modalStyle.Render(content),
+	)
+}
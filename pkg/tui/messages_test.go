@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusMessageHistory(t *testing.T) {
+	m := newFacetTestModel(nil)
+	for i := 0; i < messageHistorySize+5; i++ {
+		m.showStatusMessage(fmt.Sprintf("message %d", i))
+	}
+	m.showStatusMessage("Clipboard error: no display")
+
+	require.Len(t, m.Messages, messageHistorySize)
+	assert.Equal(t, "message 6", m.Messages[0].Text)
+	assert.True(t, m.Messages[len(m.Messages)-1].isError())
+	assert.False(t, m.Messages[0].isError())
+
+	m.Height = 20 // 8 rows per page
+	m = pressFacetKey(m, "M")
+	assert.Equal(t, FocusMessages, m.Focus)
+	assert.Contains(t, m.View(), "Clipboard error: no display")
+	assert.NotContains(t, m.View(), "message 6")
+
+	m = pressFacetKey(m, "g")
+	assert.Equal(t, messageHistorySize-8, m.MessagesOffset)
+	assert.Contains(t, m.View(), "message 6")
+
+	m = pressFacetKey(m, "j")
+	assert.Equal(t, messageHistorySize-9, m.MessagesOffset)
+
+	m = pressFacetKey(m, "esc")
+	assert.Equal(t, FocusList, m.Focus)
+}
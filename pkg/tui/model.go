@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"regexp"
 	"sort"
 	"strings"
 	"text/template"
@@ -22,6 +23,7 @@ import (
 	"github.com/bascanada/logviewer/pkg/log/printer"
 	"github.com/bascanada/logviewer/pkg/ty"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -43,6 +45,16 @@ const (
 	FocusInheritSelect
 	// FocusConfirmation means a confirmation dialog has focus.
 	FocusConfirmation
+	// FocusFacet means the field facet overlay has focus.
+	FocusFacet
+	// FocusTrace means the trace timeline overlay has focus.
+	FocusTrace
+	// FocusMessages means the status message history overlay has focus.
+	FocusMessages
+	// FocusExport means the export dialog has focus.
+	FocusExport
+	// FocusTimeJump means the jump to time dialog has focus.
+	FocusTimeJump
 )
 
 // ConfirmationType represents what we are confirming
@@ -79,11 +91,21 @@ type Tab struct {
 	Inherits   []string // Search templates to inherit
 	Result     client.LogSearchResult
 	Template   *template.Template // Printer template for formatting entries
-	Fields     ty.UniSet[string]  // Available fields with their values from GetFields()
-	Loading    bool
-	Error      error
-	StreamChan <-chan []client.LogEntry // For live streaming
-	ErrorChan  <-chan error             // For async errors from backend
+	// RowTemplates are the printerOptions rules, tried before Template
+	RowTemplates []rowTemplate
+	Fields       ty.UniSet[string] // Available fields with their values from GetFields()
+	Loading      bool
+	Error        error
+	Partial      error                    // Timeout that cut the loaded entries short
+	StreamChan   <-chan []client.LogEntry // For live streaming
+	// Progressive is set while StreamChan still feeds the initial load of a
+	// search that doesn't follow; Loading stays set until it closes
+	Progressive bool
+	// Paused holds the streamed entries in Pending instead of showing them,
+	// until the p key resumes the tail
+	Paused     bool
+	Pending    []client.LogEntry
+	ErrorChan  <-chan error // For async errors from backend
 	CancelFunc context.CancelFunc
 	ClientType string // Backend client type (e.g. splunk, opensearch)
 
@@ -108,17 +130,20 @@ type LogEntryMsg struct {
 	Entries        []client.LogEntry
 	Result         client.LogSearchResult   // The search result (for printer config)
 	Template       *template.Template       // Compiled printer template
+	RowTemplates   []rowTemplate            // Compiled printer template rules
 	Fields         ty.UniSet[string]        // Available fields with values from GetFields()
 	StreamChan     <-chan []client.LogEntry // For live streaming (if applicable)
 	ErrorChan      <-chan error             // For async errors from backend
 	PaginationInfo *client.PaginationInfo   // Pagination info (HasMore, NextPageToken)
 	IsPagination   bool                     // True if this is a pagination response (prepend instead of append)
+	Partial        error                    // Set when the backend timed out after returning Entries
 }
 
 // StreamBatchMsg delivers streamed log entries
 type StreamBatchMsg struct {
 	TabID   string
 	Entries []client.LogEntry
+	Stream  <-chan []client.LogEntry // Channel read, to drop batches of a replaced load
 }
 
 // ErrorMsg is sent when an error occurs
@@ -131,6 +156,7 @@ type ErrorMsg struct {
 type LoadingMsg struct {
 	TabID   string
 	Loading bool
+	Stream  <-chan []client.LogEntry // Set when the stream of the tab closed
 }
 
 // AddTabMsg requests adding a new tab
@@ -161,8 +187,10 @@ type Model struct {
 	DetailsVisible bool
 	SidebarMode    SidebarMode // Entry details or Global fields
 	SplitRatio     float64     // 0.0 to 1.0, ratio for log list
+	DetailField    string      // Field highlighted in the entry details (for J/K and Y keys)
 	ShowHelp       bool
 	LineWrapping   bool // Enable/disable line wrapping for multiline logs
+	TableMode      bool // Show the entries as aligned columns instead of the template
 
 	// Context selection state (for Ctrl+T new tab)
 	AvailableContexts []string
@@ -173,6 +201,48 @@ type Model struct {
 	ActiveSearches    map[string]bool // Currently active inherited searches
 	InheritCursor     int             // Cursor for inherit selection
 
+	// Facet overlay state (a key): the field whose value counts are listed
+	// and the cursor over those values
+	FacetField  string // Field whose value counts are shown
+	FacetCursor int    // Cursor over the listed values
+
+	// Quick level filter state (for 1-4 keys): levels shown, none set means
+	// no level filter
+	Levels map[string]bool
+
+	// Error navigation state (for ]e and [e keys)
+	ErrorLevel    string  // Lowest level jumped to, DefaultErrorLevel when empty
+	PendingKey    string  // ] or [ waiting for the e of a chord, or S for a slot number
+	PendingKeySeq int     // Tells the timeout of the pending key from earlier ones
+	PendingRatio  float64 // Split ratio before the pending ] or [ resized the sidebar
+
+	// Trace timeline overlay state (for T key)
+	TraceField   string      // Field the trace ID was read from
+	TraceID      string      // Trace shown in the overlay
+	TraceSpans   []traceSpan // Entries of the trace, in time order
+	TraceCursor  int         // Cursor over the timeline rows
+	TraceOffset  int         // First visible row
+	TraceLoading bool        // True while the trace is re-queried
+
+	// Status message history (for M key), oldest first
+	Messages       []statusMessage
+	MessagesOffset int // Rows scrolled up from the newest message
+
+	// Export dialog state (for E key)
+	ExportInput  textinput.Model
+	ExportFormat ExportFormat
+
+	// Jump to time dialog state (for t key); JumpTarget is the time of a jump
+	// waiting for older pages, zero when there is none
+	JumpInput  textinput.Model
+	JumpTarget time.Time
+
+	// Text of the search chips highlighted in the list, compiled once per
+	// updateViewportContent
+	highlight *regexp.Regexp
+	// Columns of the table mode, picked once per updateViewportContent
+	table *tableLayout
+
 	// Components
 	SearchBar SearchBar
 	StatusBar StatusBar
@@ -191,10 +261,18 @@ type Model struct {
 	// Runtime
 	RuntimeVars map[string]string
 
+	// Field values shared by the tabs, for the value autocomplete
+	FieldCache *FieldValueCache
+
 	// Initial contexts to load (set before Init)
 	InitialContexts []string
 	InitialSearch   *client.LogSearch
 	InitialInherits []string
+
+	// Session persistence: open tabs are saved to SessionPath on quit (empty
+	// disables it) and InitialSession tabs are recreated on Init
+	SessionPath    string
+	InitialSession *Session
 }
 
 // New creates a new TUI model
@@ -223,7 +301,7 @@ func New(cfg *config.ContextConfig, clientFactory factory.LogBackendFactory, sea
 	searchBar := NewSearchBar()
 	statusBar := NewStatusBar()
 
-	return Model{
+	m := Model{
 		Width:             80,
 		Height:            24,
 		Tabs:              make([]*Tab, 0),
@@ -237,6 +315,7 @@ func New(cfg *config.ContextConfig, clientFactory factory.LogBackendFactory, sea
 		ContextCursor:     0,
 		AvailableSearches: searches,
 		ActiveSearches:    make(map[string]bool),
+		Levels:            make(map[string]bool),
 		InheritCursor:     0,
 		SearchBar:         searchBar,
 		StatusBar:         statusBar,
@@ -248,7 +327,12 @@ func New(cfg *config.ContextConfig, clientFactory factory.LogBackendFactory, sea
 		ClientFactory:     clientFactory,
 		SearchFactory:     searchFactory,
 		RuntimeVars:       make(map[string]string),
+		FieldCache:        NewFieldValueCache(DefaultFieldValueCacheTTL),
 	}
+	if cfg != nil {
+		m.ApplyTUIConfig(cfg.TUI)
+	}
+	return m
 }
 
 // Init initializes the TUI
@@ -399,11 +483,14 @@ func (m *Model) switchToTab(newIndex int) {
 	m.saveSearchBarToTab(m.CurrentTab())
 	// Switch tab
 	m.ActiveTab = newIndex
+	// Reuse values another tab fetched for the same context
+	m.fillFieldValuesFromCache(m.CurrentTab())
 	// Restore new tab's search bar state
 	m.restoreSearchBarFromTab(m.CurrentTab())
 	// Update status bar and viewport
 	m.StatusBar.UpdateFromTab(m.CurrentTab())
 	m.StatusBar.UpdateTimeRangeFromChips(m.SearchBar.State.Chips)
+	m.syncLevelsFromChips()
 	m.updateViewportContent()
 }
 
@@ -448,32 +535,19 @@ func (m *Model) loadTabLogsCmd(tab *Tab) tea.Cmd {
 		tab.Result = result
 
 		// Compile the printer template from the search result
-		printerOptions := result.GetSearch().PrinterOptions
-		templateConfig := printerOptions.Template
-		if templateConfig.Value == "" {
-			// Default template includes message
-			templateConfig.S("[{{FormatTimestamp .Timestamp \"15:04:05\"}}] [{{.ContextID}}] {{.Level}} {{.Message}}")
-		}
-
-		tmpl, tmplErr := template.New("tui_printer").Funcs(printer.GetTemplateFunctionsMap()).Parse(templateConfig.Value)
-		if tmplErr != nil {
-			log.Printf("[WARN] TUI loadTabLogsCmd: failed to parse template: %v, using default", tmplErr)
-			tmpl, _ = template.New("tui_printer").Funcs(printer.GetTemplateFunctionsMap()).Parse("[{{FormatTimestamp .Timestamp \"15:04:05\"}}] [{{.ContextID}}] {{.Level}} {{.Message}}")
-		}
+		tmpl, rowTemplates := compileRowTemplates(result.GetSearch().PrinterOptions)
 
 		log.Printf("[DEBUG] TUI loadTabLogsCmd: calling GetEntries, tabID=%s", tabID)
 		entries, entryChan, err := result.GetEntries(ctx)
-		if err != nil {
+		var partial error
+		if client.IsPartial(err) {
+			log.Printf("[WARN] TUI loadTabLogsCmd: partial results, tabID=%s, error=%v", tabID, err)
+			partial = err
+		} else if err != nil {
 			log.Printf("[ERROR] TUI loadTabLogsCmd: GetEntries failed, tabID=%s, error=%v", tabID, err)
 			return ErrorMsg{TabID: tabID, Err: err}
 		}
 
-		// Extract JSON fields from entries
-		searchConfig := result.GetSearch()
-		for i := range entries {
-			client.ExtractJSONFromEntry(&entries[i], searchConfig)
-		}
-
 		log.Printf("[DEBUG] TUI loadTabLogsCmd: got entries, tabID=%s, count=%d", tabID, len(entries))
 
 		// Get available fields for global fields view and autocomplete
@@ -494,11 +568,13 @@ func (m *Model) loadTabLogsCmd(tab *Tab) tea.Cmd {
 			Entries:        entries,
 			Result:         result,
 			Template:       tmpl,
+			RowTemplates:   rowTemplates,
 			Fields:         fields,
 			StreamChan:     entryChan,    // Will be handled by Update loop via subscription
 			ErrorChan:      result.Err(), // Monitor for async errors from backend
 			PaginationInfo: paginationInfo,
 			IsPagination:   false, // Initial load, not pagination
+			Partial:        partial,
 		}
 
 		return msg
@@ -553,31 +629,19 @@ func (m *Model) loadMoreLogsCmd(tab *Tab) tea.Cmd {
 		}
 
 		// Compile the printer template from the search result
-		printerOptions := result.GetSearch().PrinterOptions
-		templateConfig := printerOptions.Template
-		if templateConfig.Value == "" {
-			templateConfig.S("[{{FormatTimestamp .Timestamp \"15:04:05\"}}] [{{.ContextID}}] {{.Level}} {{.Message}}")
-		}
-
-		tmpl, tmplErr := template.New("tui_printer").Funcs(printer.GetTemplateFunctionsMap()).Parse(templateConfig.Value)
-		if tmplErr != nil {
-			log.Printf("[WARN] TUI loadMoreLogsCmd: failed to parse template: %v, using default", tmplErr)
-			tmpl, _ = template.New("tui_printer").Funcs(printer.GetTemplateFunctionsMap()).Parse("[{{FormatTimestamp .Timestamp \"15:04:05\"}}] [{{.ContextID}}] {{.Level}} {{.Message}}")
-		}
+		tmpl, rowTemplates := compileRowTemplates(result.GetSearch().PrinterOptions)
 
 		log.Printf("[DEBUG] TUI loadMoreLogsCmd: calling GetEntries, tabID=%s", tabID)
 		entries, _, err := result.GetEntries(ctx)
-		if err != nil {
+		var partial error
+		if client.IsPartial(err) {
+			log.Printf("[WARN] TUI loadMoreLogsCmd: partial results, tabID=%s, error=%v", tabID, err)
+			partial = err
+		} else if err != nil {
 			log.Printf("[ERROR] TUI loadMoreLogsCmd: GetEntries failed, tabID=%s, error=%v", tabID, err)
 			return ErrorMsg{TabID: tabID, Err: err}
 		}
 
-		// Extract JSON fields from entries
-		searchConfig := result.GetSearch()
-		for i := range entries {
-			client.ExtractJSONFromEntry(&entries[i], searchConfig)
-		}
-
 		log.Printf("[DEBUG] TUI loadMoreLogsCmd: got entries, tabID=%s, count=%d", tabID, len(entries))
 
 		// Get pagination info for next page
@@ -589,8 +653,10 @@ func (m *Model) loadMoreLogsCmd(tab *Tab) tea.Cmd {
 			Entries:        entries,
 			Result:         result,
 			Template:       tmpl,
+			RowTemplates:   rowTemplates,
 			PaginationInfo: paginationInfo,
 			IsPagination:   true, // This is a pagination response - prepend entries
+			Partial:        partial,
 		}
 
 		return msg
@@ -600,24 +666,20 @@ func (m *Model) loadMoreLogsCmd(tab *Tab) tea.Cmd {
 // waitForStreamBatch subscribes to a streaming channel and returns the next batch
 // This follows the Bubble Tea message-passing pattern for safe concurrent updates
 func waitForStreamBatch(tab *Tab) tea.Cmd {
+	// Read the channel of now, the tab's is replaced when it reloads
+	stream := tab.StreamChan
 	return func() tea.Msg {
-		if tab.StreamChan == nil {
+		if stream == nil {
 			return nil
 		}
 
-		entries, ok := <-tab.StreamChan
+		entries, ok := <-stream
 		if !ok {
 			// Channel closed - stop streaming
-			return LoadingMsg{TabID: tab.ID, Loading: false}
+			return LoadingMsg{TabID: tab.ID, Loading: false, Stream: stream}
 		}
 
-		// Extract JSON fields from streamed entries
-		searchConfig := tab.Result.GetSearch()
-		for i := range entries {
-			client.ExtractJSONFromEntry(&entries[i], searchConfig)
-		}
-
-		return StreamBatchMsg{TabID: tab.ID, Entries: entries}
+		return StreamBatchMsg{TabID: tab.ID, Entries: entries, Stream: stream}
 	}
 }
 
@@ -671,6 +733,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.Focus == FocusContextSelect {
 			return m.handleContextSelect(msg)
 		}
+		// Handle facet overlay mode
+		if m.Focus == FocusFacet {
+			return m.handleFacet(msg)
+		}
+		// Handle trace timeline mode
+		if m.Focus == FocusTrace {
+			return m.handleTrace(msg)
+		}
+		// Handle message history mode
+		if m.Focus == FocusMessages {
+			return m.handleMessages(msg)
+		}
+		// Handle export dialog mode
+		if m.Focus == FocusExport {
+			return m.handleExport(msg)
+		}
+		// Handle jump to time dialog mode
+		if m.Focus == FocusTimeJump {
+			return m.handleTimeJump(msg)
+		}
 		return m.handleKeyPress(msg)
 
 	case LogEntryMsg:
@@ -692,11 +774,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					// Append new entries to the end (newer logs or initial load)
 					tab.Entries = append(tab.Entries, msg.Entries...)
-					tab.Loading = false
+					// Without follow, the channel streams the rest of the results
+					tab.Progressive = msg.StreamChan != nil && (msg.Result == nil || msg.Result.GetSearch() == nil || !msg.Result.GetSearch().Follow)
+					tab.Loading = tab.Progressive
 					log.Printf("[DEBUG] TUI LogEntryMsg: appended entries, tabID=%s, totalEntries=%d", tab.ID, len(tab.Entries))
 				}
 				tab.Result = msg.Result
 				tab.Template = msg.Template
+				tab.RowTemplates = msg.RowTemplates
+				tab.Partial = msg.Partial
+				if msg.Partial != nil {
+					cmds = append(cmds, m.showStatusMessage(fmt.Sprintf("Loaded %d entries before error: %v", len(msg.Entries), msg.Partial)))
+				}
 
 				// Store pagination info
 				tab.PaginationInfo = msg.PaginationInfo
@@ -715,7 +804,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					for field, values := range tab.Fields {
 						tab.FieldValues[field] = values
 					}
+					if !msg.IsPagination {
+						m.FieldCache.Put(tab.ContextID, tabWindow(tab), tab.FieldValues)
+					}
 				}
+				m.fillFieldValuesFromCache(tab)
 
 				// Extract available fields from entries and store in tab
 				fieldSet := make(map[string]struct{})
@@ -787,6 +880,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.updateViewportSizes()
 				m.updateViewportContent()
 				m.updateSidebarContent()
+
+				// Retry a jump to time waiting for this page
+				if msg.IsPagination && !m.JumpTarget.IsZero() && m.Tabs[m.ActiveTab].ID == tab.ID {
+					var cmd tea.Cmd
+					m, cmd = m.jumpToTime(m.JumpTarget)
+					cmds = append(cmds, cmd)
+				}
 				found = true
 				break
 			}
@@ -798,8 +898,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ErrorMsg:
 		for _, tab := range m.Tabs {
 			if tab.ID == msg.TabID {
+				// A reconnecting backend keeps streaming, only tell the user
+				if client.IsReconnecting(msg.Err) {
+					cmds = append(cmds, m.showStatusMessage(msg.Err.Error()))
+					if tab.ErrorChan != nil {
+						cmds = append(cmds, waitForError(tab))
+					}
+					break
+				}
 				tab.Error = msg.Err
 				tab.Loading = false
+				m.JumpTarget = time.Time{}
 
 				// Update viewport to show the error
 				m.updateViewportContent()
@@ -815,7 +924,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case LoadingMsg:
 		for _, tab := range m.Tabs {
 			if tab.ID == msg.TabID {
+				if msg.Stream != nil {
+					if msg.Stream != tab.StreamChan {
+						break // Closed stream of a replaced load
+					}
+					tab.StreamChan = nil
+				}
 				tab.Loading = msg.Loading
+				if tab.Progressive && !msg.Loading {
+					tab.Progressive = false
+					cmds = append(cmds, m.showStatusMessage(fmt.Sprintf("Loaded %d entries", len(tab.Entries))))
+					if m.Tabs[m.ActiveTab].ID == tab.ID {
+						m.StatusBar.UpdateFromTab(tab)
+						m.updateViewportContent()
+					}
+				}
 				break
 			}
 		}
@@ -824,12 +947,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Handle streamed log entries (live streaming)
 		for _, tab := range m.Tabs {
 			if tab.ID == msg.TabID {
+				if msg.Stream != nil && msg.Stream != tab.StreamChan {
+					break // Batch of a replaced load
+				}
+				if tab.Paused {
+					// Keep reading the channel so the backend doesn't block
+					tab.Pending = append(tab.Pending, msg.Entries...)
+					if m.Tabs[m.ActiveTab].ID == tab.ID {
+						m.StatusBar.UpdateFromTab(tab)
+					}
+					cmds = append(cmds, waitForStreamBatch(tab))
+					break
+				}
+
 				// Append new entries
 				tab.Entries = append(tab.Entries, msg.Entries...)
 				log.Printf("[DEBUG] TUI StreamBatchMsg: appended %d entries, total=%d", len(msg.Entries), len(tab.Entries))
 
 				// Update display if this is the active tab
 				if m.Tabs[m.ActiveTab].ID == tab.ID {
+					if tab.Progressive {
+						m.StatusBar.UpdateFromTab(tab)
+					}
 					m.updateViewportContent()
 				}
 
@@ -843,11 +982,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.StatusBar.ClearMessage()
 		return m, nil
 
+	case PendingKeyTimeoutMsg:
+		if m.PendingKey != "" && msg.Seq == m.PendingKeySeq {
+			m.PendingKey = ""
+		}
+		return m, nil
+
+	case FieldValuesMsg:
+		m.handleFieldValues(msg)
+		return m, nil
+
+	case TraceQueryMsg:
+		return m, m.handleTraceQuery(msg)
+
 	case InitMsg:
 		// Load initial contexts
 		log.Printf("[DEBUG] TUI InitMsg received, initialContexts=%v", m.InitialContexts)
 
 		var initCmds []tea.Cmd
+		if m.InitialSession != nil {
+			for _, saved := range m.InitialSession.Tabs {
+				initCmds = append(initCmds, m.restoreTabCmd(saved))
+			}
+		}
 		for _, ctxID := range m.InitialContexts {
 			search := m.InitialSearch
 			if search == nil {
@@ -858,9 +1015,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			initCmds = append(initCmds, m.addTabCmd(ctxID, &tabSearch))
 		}
 
-		// Switch to first tab initially
+		// Switch to first tab initially, or the one active when the session was saved
 		if len(m.Tabs) > 0 {
-			m.switchToTab(0)
+			active := 0
+			if m.InitialSession != nil && m.InitialSession.ActiveTab < len(m.Tabs) {
+				active = m.InitialSession.ActiveTab
+			}
+			m.switchToTab(active)
 		}
 
 		log.Printf("[DEBUG] TUI InitMsg: created %d tabs", len(m.Tabs))
@@ -878,6 +1039,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 //
 //nolint:gocyclo // Keyboard handler with many keybindings
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Complete an S1-9 slot save
+	if m.PendingKey == "S" {
+		m.PendingKey = ""
+		if n := slotForKey(msg.String()); n > 0 {
+			return m, m.saveSlot(n)
+		}
+	}
+
+	// Complete a ]e or [e chord, undoing the resize of the ] or [ typed before
+	if m.PendingKey != "" {
+		if msg.String() == "e" {
+			dir := 1
+			if m.PendingKey == "[" {
+				dir = -1
+			}
+			m.undoPendingResize()
+			return m.jumpToError(dir)
+		}
+		m.PendingKey = ""
+	}
+
 	switch {
 	case key.Matches(msg, m.Keys.Quit):
 		m.cleanup()
@@ -927,17 +1109,13 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.updateSidebarContent()
 		return m, nil
 
-	case key.Matches(msg, m.Keys.ExpandSidebar):
-		if m.DetailsVisible && m.SplitRatio > 0.3 {
-			m.SplitRatio -= 0.05
-			m.updateViewportSizes()
-		}
-		return m, nil
-
-	case key.Matches(msg, m.Keys.ShrinkSidebar):
-		if m.DetailsVisible && m.SplitRatio < 0.9 {
-			m.SplitRatio += 0.05
-			m.updateViewportSizes()
+	case key.Matches(msg, m.Keys.ExpandSidebar), key.Matches(msg, m.Keys.ShrinkSidebar):
+		// ] and [ resize right away but may start a ]e or [e chord
+		ratio := m.SplitRatio
+		m.resizeSidebar(key.Matches(msg, m.Keys.ExpandSidebar))
+		if msg.String() == "]" || msg.String() == "[" {
+			m.PendingRatio = ratio
+			return m, m.startPendingKey(msg.String())
 		}
 		return m, nil
 
@@ -1031,6 +1209,20 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Handle J/K keys to move between entry detail fields and Y to copy one
+	if m.DetailsVisible && m.SidebarMode == SidebarModeEntry {
+		switch msg.String() {
+		case "J":
+			m.moveFieldCursor(1)
+			return m, nil
+		case "K":
+			m.moveFieldCursor(-1)
+			return m, nil
+		case "Y":
+			return m, m.copyFieldToClipboard()
+		}
+	}
+
 	// Handle I key for inherit selection
 	if msg.String() == "I" && len(m.AvailableSearches) > 0 {
 		m.Focus = FocusInheritSelect
@@ -1038,6 +1230,63 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Handle a key for the facet overlay
+	if msg.String() == "a" {
+		m.openFacet()
+		return m, nil
+	}
+
+	// Handle T key for the trace timeline of the selected entry
+	if msg.String() == "T" {
+		return m, m.openTrace()
+	}
+
+	// Handle 1-4 keys to toggle the quick level filters
+	if level := quickLevelForKey(msg.String()); level != "" {
+		return m, m.toggleLevel(level)
+	}
+
+	// Handle S then 1-9 to save the chips to a quick-slot, Alt+1-9 to recall one
+	if msg.String() == "S" {
+		return m, tea.Batch(m.startPendingKey("S"), m.showStatusMessage("Save to slot: press 1-9"))
+	}
+	if n := recallSlotForKey(msg.String()); n > 0 {
+		return m, m.recallSlot(n)
+	}
+
+	// Handle M key for the status message history
+	if msg.String() == "M" {
+		m.MessagesOffset = 0
+		m.Focus = FocusMessages
+		return m, nil
+	}
+
+	// Handle E key to export the filtered entries to a file
+	if msg.String() == "E" {
+		return m, m.openExport()
+	}
+
+	// Handle t key to jump to a time
+	if msg.String() == "t" {
+		return m, m.openTimeJump()
+	}
+
+	// Handle p key to pause or resume the live tail
+	if msg.String() == "p" {
+		return m, m.togglePause()
+	}
+
+	// Handle v key to toggle the table mode
+	if msg.String() == "v" {
+		return m, m.toggleTableMode()
+	}
+
+	// Handle X key to clear the field value cache
+	if msg.String() == "X" {
+		m.FieldCache.Clear()
+		return m, m.showStatusMessage("Field value cache cleared")
+	}
+
 	return m, nil
 }
 
@@ -1059,6 +1308,7 @@ func (m Model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.Focus = FocusList
 		m.SearchBar.Blur()
+		m.SearchBar.RecordHistory()
 		// Save search bar state to current tab
 		m.saveSearchBarToTab(m.CurrentTab())
 		// Update status bar with time range from chips
@@ -1076,6 +1326,10 @@ func (m Model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Don't update viewport content while typing - wait for Enter to apply changes
 	// Only update status bar with time range from chips (preview what will be applied)
 	m.StatusBar.UpdateTimeRangeFromChips(m.SearchBar.State.Chips)
+	// Fetch values of the field being compared if no tab has them yet
+	if fetchCmd := m.ensureFieldValues(); fetchCmd != nil {
+		return m, tea.Batch(cmd, fetchCmd)
+	}
 	return m, cmd
 }
 
@@ -1274,6 +1528,7 @@ func (m *Model) closeCurrentTab() tea.Cmd {
 	}
 
 	if len(m.Tabs) == 0 {
+		m.cleanup()
 		return tea.Quit
 	}
 
@@ -1296,14 +1551,22 @@ func (m *Model) refreshCurrentTab() tea.Cmd {
 	if tab.CancelFunc != nil {
 		tab.CancelFunc()
 	}
+	// Batches still coming from the previous load are dropped
+	tab.StreamChan = nil
+	tab.Progressive = false
+	tab.Paused = false
+	tab.Pending = nil
 
 	tab.Entries = make([]client.LogEntry, 0)
 	tab.Cursor = 0
 	tab.Loading = true
 	tab.Error = nil
+	tab.Partial = nil
 
 	// Clear JSON cache since entries will be reloaded
 	tab.JSONCache = nil
+	// Field values are fetched again with the new results
+	m.FieldCache.InvalidateContext(tab.ContextID)
 
 	// Rebuild search entirely from chips (time range, fields, etc.)
 	// This ensures removed chips are not included in the search
@@ -1364,6 +1627,36 @@ func (m *Model) refreshCurrentTab() tea.Cmd {
 	return m.loadTabLogsCmd(tab)
 }
 
+// filteredEntries returns the entries of tab shown in the list, filtered by
+// the SearchBar (chips + free text).
+func (m *Model) filteredEntries(tab *Tab) []client.LogEntry {
+	entries := tab.Entries
+	filter := m.SearchBar.BuildFilter()
+	if filter != nil {
+		filtered := make([]client.LogEntry, 0)
+		for _, entry := range entries {
+			if filter.Match(entry) {
+				filtered = append(filtered, entry)
+			}
+		}
+		return filtered
+	}
+
+	// Fallback to simple text search for current input
+	searchTerm := strings.ToLower(m.SearchBar.GetFreeTextSearch())
+	if searchTerm == "" {
+		return entries
+	}
+	filtered := make([]client.LogEntry, 0)
+	for _, entry := range entries {
+		if strings.Contains(strings.ToLower(entry.Message), searchTerm) ||
+			strings.Contains(strings.ToLower(entry.Level), searchTerm) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
 // copyJSONToClipboard copies JSON from the selected entry to the system clipboard
 func (m *Model) copyJSONToClipboard() tea.Cmd {
 	tab := m.CurrentTab()
@@ -1425,13 +1718,15 @@ func (m *Model) copyJSONToClipboard() tea.Cmd {
 // Returns a command that will clear the message after a delay
 func (m *Model) showStatusMessage(message string) tea.Cmd {
 	m.StatusBar.SetMessage(message)
+	m.recordMessage(message)
 	return tea.Tick(3*time.Second, func(_ time.Time) tea.Msg {
 		return ClearStatusMsg{}
 	})
 }
 
-// cleanup cancels all active goroutines
+// cleanup saves the session and cancels all active goroutines
 func (m *Model) cleanup() {
+	m.saveSession()
 	for _, tab := range m.Tabs {
 		if tab.CancelFunc != nil {
 			tab.CancelFunc()
@@ -1479,7 +1774,8 @@ func (m *Model) updateViewportContent() {
 		return
 	}
 
-	if tab.Loading {
+	// A progressive load shows the entries received so far
+	if tab.Loading && len(tab.Entries) == 0 {
 		m.Viewport.SetContent("Loading...")
 		return
 	}
@@ -1503,31 +1799,8 @@ func (m *Model) updateViewportContent() {
 		return
 	}
 
-	// Filter entries using SearchBar (chips + free text)
-	entries := tab.Entries
-	filter := m.SearchBar.BuildFilter()
-	if filter != nil {
-		filtered := make([]client.LogEntry, 0)
-		for _, entry := range entries {
-			if filter.Match(entry) {
-				filtered = append(filtered, entry)
-			}
-		}
-		entries = filtered
-	} else {
-		// Fallback to simple text search for current input
-		searchTerm := strings.ToLower(m.SearchBar.GetFreeTextSearch())
-		if searchTerm != "" {
-			filtered := make([]client.LogEntry, 0)
-			for _, entry := range entries {
-				if strings.Contains(strings.ToLower(entry.Message), searchTerm) ||
-					strings.Contains(strings.ToLower(entry.Level), searchTerm) {
-					filtered = append(filtered, entry)
-				}
-			}
-			entries = filtered
-		}
-	}
+	entries := m.filteredEntries(tab)
+	m.highlight = m.SearchBar.HighlightPattern()
 
 	// Update status bar with filtered count
 	m.StatusBar.SetFilteredCount(len(entries))
@@ -1564,8 +1837,17 @@ func (m *Model) updateViewportContent() {
 		visibleLines = 1
 	}
 
+	// The table mode keeps one line per entry under a header line
+	m.table = nil
+	if m.TableMode {
+		m.table = buildTableLayout(tab, entries, m.Viewport.Width)
+		if visibleLines > 1 {
+			visibleLines--
+		}
+	}
+
 	// Build content - handle multiline rendering differently based on wrap mode
-	if m.LineWrapping {
+	if m.LineWrapping && !m.TableMode {
 		// Wrap mode: more complex scrolling due to variable entry heights
 		// Strategy: Ensure cursor entry is visible, then render around it
 
@@ -1745,6 +2027,10 @@ func (m *Model) updateViewportContent() {
 			lines = append(lines, "")
 		}
 
+		if m.table != nil {
+			lines = append([]string{m.Styles.SidebarKey.Render(m.table.header())}, lines...)
+		}
+
 		// Prepend loading indicator if pagination is loading
 		if tab.LoadingMore {
 			loadingLine := m.Styles.SidebarKey.Foreground(ColorPrimary).Render("⏳ Loading more logs...")
@@ -1755,7 +2041,8 @@ func (m *Model) updateViewportContent() {
 	}
 }
 
-// updateSidebarContent refreshes the sidebar content
+// updateSidebarContent refreshes the sidebar content, wrapped to its width so
+// long values and JSON lines stay visible
 func (m *Model) updateSidebarContent() {
 	if !m.DetailsVisible {
 		return
@@ -1770,7 +2057,7 @@ func (m *Model) updateSidebarContent() {
 	// Render based on sidebar mode
 	switch m.SidebarMode {
 	case SidebarModeFields:
-		m.SidebarVP.SetContent(m.renderGlobalFields())
+		m.SidebarVP.SetContent(wrapContent(m.renderGlobalFields(), m.SidebarVP.Width))
 		return
 	case SidebarModeJSON:
 		if len(tab.Entries) == 0 || tab.Cursor >= len(tab.Entries) {
@@ -1778,7 +2065,7 @@ func (m *Model) updateSidebarContent() {
 			return
 		}
 		entry := tab.Entries[tab.Cursor]
-		m.SidebarVP.SetContent(m.renderEntryJSON(entry))
+		m.SidebarVP.SetContent(wrapContent(m.renderEntryJSON(entry), m.SidebarVP.Width))
 		return
 	case SidebarModeEntry:
 		// Entry details mode (default)
@@ -1790,7 +2077,7 @@ func (m *Model) updateSidebarContent() {
 			return
 		}
 		entry := tab.Entries[tab.Cursor]
-		m.SidebarVP.SetContent(m.renderEntryDetails(entry))
+		m.SidebarVP.SetContent(wrapContent(m.renderEntryDetails(entry), m.SidebarVP.Width))
 		return
 	}
 }
@@ -1800,23 +2087,30 @@ func (m *Model) renderLogEntry(entry client.LogEntry, selected bool, maxWidth in
 	if maxWidth < 20 {
 		maxWidth = 20
 	}
+	if m.TableMode && m.table != nil {
+		return m.renderTableRow(entry, selected, maxWidth)
+	}
 
 	var line string
 	var hasJSON bool
 	var jsonSummary string
 
-	// Use the tab's template if available
-	if tab != nil && tab.Template != nil {
+	// Use the tab's template, or the template of its first matching rule
+	var tmpl *template.Template
+	if tab != nil {
+		tmpl = tab.rowTemplateFor(entry)
+	}
+	if tmpl != nil {
 		var buf bytes.Buffer
-		if err := tab.Template.Execute(&buf, entry); err != nil {
+		if err := tmpl.Execute(&buf, entry); err != nil {
 			// Fallback to format with message on template error
-			line = fmt.Sprintf("[%s] %s %s", entry.Timestamp.Format("15:04:05"), entry.Level, entry.Message)
+			line = fmt.Sprintf("[%s] %s %s", printer.FormatTimestamp(entry.Timestamp, "15:04:05"), entry.Level, entry.Message)
 		} else {
 			line = buf.String()
 		}
 	} else {
 		// Default format with message if no template
-		line = fmt.Sprintf("[%s] [%s] %s %s", entry.Timestamp.Format("15:04:05"), entry.ContextID, entry.Level, entry.Message)
+		line = fmt.Sprintf("[%s] [%s] %s %s", printer.FormatTimestamp(entry.Timestamp, "15:04:05"), entry.ContextID, entry.Level, entry.Message)
 	}
 
 	// Detect JSON in the message (check cache or detect)
@@ -1851,9 +2145,9 @@ func (m *Model) renderLogEntry(entry client.LogEntry, selected bool, maxWidth in
 
 		// Apply selection or normal style (no width constraint for wrapping)
 		if selected {
-			return m.Styles.LogSelected.Render(line)
+			return highlightMatches(m.Styles.LogSelected.Render(line), m.highlight, m.Styles.LogMatch)
 		}
-		return m.Styles.LogEntry.Render(line)
+		return highlightMatches(m.Styles.EntryStyle(entry.Level).Render(line), m.highlight, m.Styles.LogMatch)
 	}
 
 	// No-wrap mode (default): Single line, truncate if needed
@@ -1906,11 +2200,12 @@ func (m *Model) renderLogEntry(entry client.LogEntry, selected bool, maxWidth in
 		}
 	}
 
-	// Apply selection or normal style
+	// Apply selection or the style of the level, then highlight the matches
+	// of what was left after truncation
 	if selected {
-		return m.Styles.LogSelected.Width(maxWidth).Render(line)
+		return highlightMatches(m.Styles.LogSelected.Width(maxWidth).Render(line), m.highlight, m.Styles.LogMatch)
 	}
-	return m.Styles.LogEntry.Width(maxWidth).Render(line)
+	return highlightMatches(m.Styles.EntryStyle(entry.Level).Width(maxWidth).Render(line), m.highlight, m.Styles.LogMatch)
 }
 
 // countVisualLines counts how many visual lines an entry will take when rendered
@@ -1992,6 +2287,99 @@ func wrapLine(line string, maxWidth int) []string {
 	return result
 }
 
+// wrapContent wraps each line of content to maxWidth with wrapLine. The
+// continuation lines keep the indentation of their line so nested JSON stays
+// readable, and the colors open at a break are closed on the line and opened
+// again on the next one, since the lines are laid out next to the log list.
+// maxWidth below 1 leaves content unchanged.
+func wrapContent(content string, maxWidth int) string {
+	if maxWidth < 1 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if lipgloss.Width(line) <= maxWidth {
+			out = append(out, line)
+			continue
+		}
+
+		lead, rest := splitIndent(line)
+		indent := lipgloss.Width(lead)
+		// A deep indent would leave no room for the content
+		if indent > maxWidth/2 {
+			lead, rest, indent = "", line, 0
+		}
+		sgr := activeSGR("", lead)
+		for i, chunk := range wrapLine(rest, maxWidth-indent) {
+			prefix := lead
+			if i > 0 {
+				prefix = strings.Repeat(" ", indent)
+				// The break may fall just before the colors are reset
+				if !strings.HasPrefix(chunk, ansiReset) {
+					prefix += sgr
+				}
+			}
+			sgr = activeSGR(sgr, chunk)
+			wrapped := prefix + chunk
+			if sgr != "" {
+				wrapped += ansiReset
+			}
+			out = append(out, wrapped)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// ansiReset resets every SGR attribute.
+const ansiReset = "\x1b[0m"
+
+// splitIndent splits line after its leading spaces, escape sequences
+// included.
+func splitIndent(line string) (lead, rest string) {
+	for i := 0; i < len(line); {
+		switch {
+		case line[i] == ' ':
+			i++
+		case line[i] == '\x1b' && i+1 < len(line) && line[i+1] == '[':
+			j := i + 2
+			for j < len(line) && (line[j] < 0x40 || line[j] > 0x7e) {
+				j++
+			}
+			i = j + 1
+		default:
+			return line[:i], line[i:]
+		}
+	}
+	return line, ""
+}
+
+// activeSGR returns the SGR sequences still in effect at the end of s, given
+// the ones in effect at its start.
+func activeSGR(active, s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\x1b' || i+1 >= len(s) || s[i+1] != '[' {
+			continue
+		}
+		j := i + 2
+		for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+			j++
+		}
+		if j >= len(s) {
+			break
+		}
+		if s[j] == 'm' {
+			if params := s[i+2 : j]; params == "" || params == "0" {
+				active = ""
+			} else {
+				active += s[i : j+1]
+			}
+		}
+		i = j
+	}
+	return active
+}
+
 // detectAndCacheJSON detects JSON in a log entry and caches the result.
 // Returns the cached JSON strings and whether JSON was found.
 func (m *Model) detectAndCacheJSON(tab *Tab, message string) ([]string, bool) {
@@ -2046,7 +2434,7 @@ func (m *Model) renderEntryDetails(entry client.LogEntry) string {
 		b.WriteString("\n")
 	}
 
-	writeField("Timestamp", entry.Timestamp.Format(time.RFC3339))
+	writeField("Timestamp", printer.FormatTimestamp(entry.Timestamp, time.RFC3339))
 	writeField("Level", entry.Level)
 	if entry.ContextID != "" {
 		writeField("Context", entry.ContextID)
@@ -2065,7 +2453,8 @@ func (m *Model) renderEntryDetails(entry client.LogEntry) string {
 		}
 		sort.Strings(fieldKeys)
 
-		// Render fields in sorted order
+		// Render fields in sorted order, marking the one Y copies
+		highlighted := m.highlightedField(entry)
 		for _, key := range fieldKeys {
 			val := entry.Fields[key]
 			valStr := fmt.Sprintf("%v", val)
@@ -2076,7 +2465,11 @@ func (m *Model) renderEntryDetails(entry client.LogEntry) string {
 					valStr = string(jsonBytes)
 				}
 			}
-			b.WriteString(m.Styles.SidebarKey.Render(key + ":"))
+			if key == highlighted {
+				b.WriteString(m.Styles.LogSelected.Render("▸ " + key + ":"))
+			} else {
+				b.WriteString(m.Styles.SidebarKey.Render(key + ":"))
+			}
 			b.WriteString("\n  ")
 			b.WriteString(m.Styles.SidebarValue.Render(valStr))
 			b.WriteString("\n")
@@ -2230,6 +2623,31 @@ func (m Model) View() string {
 		return m.renderConfirmationOverlay()
 	}
 
+	// Render facet overlay if active
+	if m.Focus == FocusFacet {
+		return m.renderFacetOverlay()
+	}
+
+	// Render trace timeline overlay if active
+	if m.Focus == FocusTrace {
+		return m.renderTraceOverlay()
+	}
+
+	// Render message history overlay if active
+	if m.Focus == FocusMessages {
+		return m.renderMessagesOverlay()
+	}
+
+	// Render export dialog if active
+	if m.Focus == FocusExport {
+		return m.renderExportOverlay()
+	}
+
+	// Render jump to time dialog if active
+	if m.Focus == FocusTimeJump {
+		return m.renderTimeJumpOverlay()
+	}
+
 	sections := make([]string, 0, 4)
 
 	// Header (tabs)
@@ -2401,6 +2819,47 @@ func (m Model) renderInheritSelectOverlay() string {
 	)
 }
 
+// maxTabNameLength is the length tab names are truncated to in the tab bar.
+const maxTabNameLength = 24
+
+// tabTitle returns the tab bar title of a tab: its name followed by the time
+// range of its chips and the number of filter chips, e.g. "prod [15m] (2)".
+func tabTitle(name string, chips []Chip) string {
+	title := truncateForDisplay(name, maxTabNameLength)
+
+	var last, from, to string
+	filters := 0
+	for _, chip := range chips {
+		switch chip.Type {
+		case ChipTypeTimeRange:
+			switch chip.Field {
+			case "last":
+				last = chip.Value
+			case "from":
+				from = chip.Value
+			case "to":
+				to = chip.Value
+			}
+		case ChipTypeField, ChipTypeFreeText, ChipTypeFilterGroup, ChipTypeNativeQuery:
+			filters++
+		}
+	}
+
+	switch {
+	case last != "":
+		title += " [" + last + "]"
+	case from != "" || to != "":
+		if to == "" {
+			to = "now"
+		}
+		title += " [" + truncateForDisplay(from, maxTabNameLength) + ".." + truncateForDisplay(to, maxTabNameLength) + "]"
+	}
+	if filters > 0 {
+		title += fmt.Sprintf(" (%d)", filters)
+	}
+	return title
+}
+
 // renderTabs renders the tab bar
 func (m Model) renderTabs() string {
 	if len(m.Tabs) == 0 {
@@ -2409,7 +2868,12 @@ func (m Model) renderTabs() string {
 
 	var tabs []string
 	for i, tab := range m.Tabs {
-		name := tab.Name
+		chips := tab.SearchState.Chips
+		if i == m.ActiveTab {
+			// The active tab's chips live in the search bar until it is switched away
+			chips = m.SearchBar.State.Chips
+		}
+		name := tabTitle(tab.Name, chips)
 		if tab.Loading {
 			name += " ⏳"
 		}
@@ -2492,9 +2956,9 @@ func (m Model) renderSearchFooter() string {
 	parts = append(parts, m.SearchBar.View())
 
 	// Help text
-	helpText := "↑↓ navigate • / search • w wrap • I inherits • Tab autocomplete • Enter sidebar • F fields • ? help • q quit"
+	helpText := "↑↓ navigate • / search • w wrap • I inherits • a facets • T trace • ]e/[e errors • 1-4 levels • S1-9/Alt+1-9 slots • X clear values • Tab autocomplete • Enter sidebar • F fields • J/K Y copy field • M messages • ? help • q quit"
 	if m.ShowHelp {
-		helpText = "↑↓/jk nav • PgUp/PgDn scroll • Tab/Shift+Tab tabs • Ctrl+T new • Ctrl+W close • w wrap • I inherits • [ ] resize • Enter sidebar • F fields • Esc clear • q quit"
+		helpText = "↑↓/jk nav • PgUp/PgDn scroll • Tab/Shift+Tab tabs • Ctrl+T new • Ctrl+W close • w wrap • I inherits • a facets • T trace • ]e/[e errors • 1-4 levels • S1-9 save slot • Alt+1-9 recall slot • X clear values • E export • t jump to time • p pause • v table • o OR chips • [ ] resize • Enter sidebar • F fields • J/K Y copy field • M messages • Esc clear • q quit"
 	}
 	parts = append(parts, m.Styles.HelpBar.Render(helpText))
 
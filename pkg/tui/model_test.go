@@ -3,10 +3,13 @@ package tui
 
 import (
 	"context"
+	"testing"
 
 	"github.com/bascanada/logviewer/pkg/log/client"
 	"github.com/bascanada/logviewer/pkg/ty"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockSearchResult implements client.LogSearchResult
@@ -40,3 +43,112 @@ func (m *MockSearchResult) Err() <-chan error {
 
 // Ensure Tea.Msg interface is satisfied (implied, but good practice)
 var _ tea.Msg = LogEntryMsg{}
+
+func TestModelUpdate_LogEntryMsg_Partial(t *testing.T) {
+	m := New(sessionTestConfig("prod"), nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	m.Width, m.Height = 160, 40
+	m.Tabs = []*Tab{{ID: "t1", ContextID: "prod", Search: &client.LogSearch{}, FieldValues: map[string][]string{}}}
+
+	partial := &client.PartialResultError{Err: context.DeadlineExceeded}
+	updated, _ := m.Update(LogEntryMsg{
+		TabID:   "t1",
+		Entries: []client.LogEntry{{Message: "first"}, {Message: "second"}},
+		Partial: partial,
+	})
+	m = updated.(Model)
+
+	tab := m.Tabs[0]
+	assert.Len(t, tab.Entries, 2, "entries received before the timeout are kept")
+	assert.Nil(t, tab.Error)
+	assert.Equal(t, partial, tab.Partial)
+	require.NotEmpty(t, m.Messages)
+	assert.Contains(t, m.Messages[len(m.Messages)-1].Text, "Loaded 2 entries before error")
+
+	m.StatusBar.ClearMessage()
+	assert.Contains(t, m.StatusBar.View(), "Partial")
+}
+
+func TestTabTitle(t *testing.T) {
+	chips := []Chip{
+		{Type: ChipTypeContext, Value: "prod"},
+		{Type: ChipTypeTimeRange, Field: "last", Value: "15m"},
+		{Type: ChipTypeField, Field: "level", Value: "ERROR"},
+		{Type: ChipTypeFreeText, Text: "timeout"},
+	}
+	assert.Equal(t, "prod [15m] (2)", tabTitle("prod", chips))
+	assert.Equal(t, "prod", tabTitle("prod", nil))
+	assert.Equal(t, "prod [2024-01-01..now]", tabTitle("prod", []Chip{{Type: ChipTypeTimeRange, Field: "from", Value: "2024-01-01"}}))
+	assert.Equal(t, "a-very-long-context-n... [15m]", tabTitle("a-very-long-context-name-for-prod", chips[:2]))
+}
+
+func TestRenderTabs_UsesActiveSearchBarChips(t *testing.T) {
+	m := New(sessionTestConfig("prod"), nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	m.Width = 120
+	m.Tabs = []*Tab{
+		{ID: "t1", Name: "prod", Error: context.DeadlineExceeded},
+		{ID: "t2", Name: "staging", SearchState: ChipSearchState{Chips: []Chip{{Type: ChipTypeTimeRange, Field: "last", Value: "1h"}}}},
+	}
+	m.ActiveTab = 0
+	m.SearchBar.State.Chips = []Chip{{Type: ChipTypeTimeRange, Field: "last", Value: "30m"}}
+
+	rendered := m.renderTabs()
+	assert.Contains(t, rendered, "prod [30m] ❌")
+	assert.Contains(t, rendered, "staging [1h]")
+}
+
+func TestModelUpdate_ProgressiveLoad(t *testing.T) {
+	m := New(sessionTestConfig("prod"), nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	m.Width, m.Height = 160, 40
+	m.Tabs = []*Tab{{ID: "t1", ContextID: "prod", Search: &client.LogSearch{}, FieldValues: map[string][]string{}, Loading: true}}
+	update := func(msg tea.Msg) {
+		t.Helper()
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	stream := make(chan []client.LogEntry, 1)
+	update(LogEntryMsg{
+		TabID:      "t1",
+		Entries:    []client.LogEntry{{Message: "first"}},
+		Result:     &MockSearchResult{Search: &client.LogSearch{}},
+		StreamChan: stream,
+	})
+	tab := m.Tabs[0]
+	assert.True(t, tab.Loading, "the initial load goes on while the channel is open")
+	assert.Contains(t, m.Viewport.View(), "first", "entries show while loading")
+
+	stream <- []client.LogEntry{{Message: "second"}}
+	update(waitForStreamBatch(tab)())
+	assert.Len(t, tab.Entries, 2)
+	assert.Contains(t, m.StatusBar.View(), "Loading 2...")
+
+	close(stream)
+	update(waitForStreamBatch(tab)())
+	assert.False(t, tab.Loading)
+	assert.False(t, tab.Progressive)
+	assert.Equal(t, "Loaded 2 entries", m.Messages[len(m.Messages)-1].Text)
+	assert.NotContains(t, m.StatusBar.View(), "Loading")
+
+	// Batches of a load replaced by a refresh are dropped
+	old := make(chan []client.LogEntry, 1)
+	tab.StreamChan, tab.Progressive, tab.Loading = old, true, true
+	old <- []client.LogEntry{{Message: "stale"}}
+	batch := waitForStreamBatch(tab)()
+	close(old)
+	done := waitForStreamBatch(tab)()
+	m.refreshCurrentTab()
+	update(batch)
+	update(done)
+	assert.Empty(t, tab.Entries)
+	assert.True(t, tab.Loading, "the closed stale stream doesn't end the new load")
+
+	// A followed search streams live entries once the initial load is shown
+	update(LogEntryMsg{
+		TabID:      "t1",
+		Entries:    []client.LogEntry{{Message: "live"}},
+		Result:     &MockSearchResult{Search: &client.LogSearch{Follow: true}},
+		StreamChan: make(chan []client.LogEntry),
+	})
+	assert.False(t, tab.Loading)
+	assert.False(t, tab.Progressive)
+}
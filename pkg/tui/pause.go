@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// togglePause pauses the live tail of the current tab, or resumes it and adds
+// the entries received in the meantime.
+func (m *Model) togglePause() tea.Cmd {
+	tab := m.CurrentTab()
+	if tab == nil {
+		return nil
+	}
+
+	if !tab.Paused {
+		if tab.StreamChan == nil {
+			return m.showStatusMessage("No live stream to pause")
+		}
+		tab.Paused = true
+		m.StatusBar.UpdateFromTab(tab)
+		return m.showStatusMessage("Live tail paused")
+	}
+
+	pending := len(tab.Pending)
+	tab.Entries = append(tab.Entries, tab.Pending...)
+	tab.Paused = false
+	tab.Pending = nil
+	m.StatusBar.UpdateFromTab(tab)
+	m.updateViewportContent()
+	return m.showStatusMessage(fmt.Sprintf("Live tail resumed, %d entries added", pending))
+}
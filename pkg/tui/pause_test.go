@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseLiveTail(t *testing.T) {
+	m := newFacetTestModel([]client.LogEntry{{Message: "first"}})
+	tab := m.Tabs[0]
+	tab.Result = &MockSearchResult{Search: &client.LogSearch{Follow: true}}
+
+	m = pressFacetKey(m, "p")
+	assert.False(t, tab.Paused)
+	assert.Equal(t, "No live stream to pause", m.Messages[len(m.Messages)-1].Text)
+
+	stream := make(chan []client.LogEntry, 1)
+	tab.StreamChan = stream
+	m = pressFacetKey(m, "p")
+	require.True(t, tab.Paused)
+
+	// The channel is still read while paused, the entries are held
+	stream <- []client.LogEntry{{Message: "second"}, {Message: "third"}}
+	cmd := waitForStreamBatch(tab)
+	updated, next := m.Update(cmd())
+	m = updated.(Model)
+	assert.NotNil(t, next, "waits for the next batch")
+	assert.Len(t, tab.Entries, 1)
+	assert.Len(t, tab.Pending, 2)
+	m.StatusBar.Width = 160
+	m.StatusBar.ClearMessage()
+	assert.Contains(t, m.StatusBar.View(), "PAUSED (2 pending)")
+	assert.NotContains(t, m.StatusBar.View(), "LIVE")
+
+	m = pressFacetKey(m, "p")
+	assert.False(t, tab.Paused)
+	assert.Empty(t, tab.Pending)
+	require.Len(t, tab.Entries, 3)
+	assert.Equal(t, "third", tab.Entries[2].Message)
+	assert.Equal(t, "Live tail resumed, 2 entries added", m.Messages[len(m.Messages)-1].Text)
+	m.StatusBar.ClearMessage()
+	assert.Contains(t, m.StatusBar.View(), "LIVE")
+	assert.NotContains(t, m.StatusBar.View(), "PAUSED")
+}
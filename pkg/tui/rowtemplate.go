@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"log"
+	"text/template"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/printer"
+)
+
+// defaultRowTemplate formats the log lines when printerOptions.template is
+// unset or doesn't parse.
+const defaultRowTemplate = "[{{FormatTimestamp .Timestamp \"15:04:05\"}}] [{{.ContextID}}] {{.Level}} {{.Message}}"
+
+// rowTemplate is a compiled printerOptions rule.
+type rowTemplate struct {
+	Rule     client.TemplateRule
+	Template *template.Template
+}
+
+// compileRowTemplates compiles the template of options and its rules. A
+// template that doesn't parse falls back to defaultRowTemplate, and a rule
+// that doesn't parse is skipped so the other rules still apply.
+func compileRowTemplates(options client.PrinterOptions) (*template.Template, []rowTemplate) {
+	text := options.Template.Value
+	if text == "" {
+		text = defaultRowTemplate
+	}
+	tmpl, err := parseRowTemplate(text)
+	if err != nil {
+		log.Printf("[WARN] TUI: failed to parse template: %v, using default", err)
+		tmpl, _ = parseRowTemplate(defaultRowTemplate)
+	}
+
+	var rules []rowTemplate
+	for i, rule := range options.Rules {
+		ruleTmpl, err := parseRowTemplate(rule.Template)
+		if err != nil {
+			log.Printf("[WARN] TUI: skipping printerOptions rule %d: %v", i, err)
+			continue
+		}
+		rules = append(rules, rowTemplate{Rule: rule, Template: ruleTmpl})
+	}
+	return tmpl, rules
+}
+
+func parseRowTemplate(text string) (*template.Template, error) {
+	return template.New("tui_printer").Funcs(printer.GetTemplateFunctionsMap()).Parse(text)
+}
+
+// rowTemplateFor returns the template of the first rule entry matches, or the
+// tab's template.
+func (t *Tab) rowTemplateFor(entry client.LogEntry) *template.Template {
+	for _, rule := range t.RowTemplates {
+		if rule.Rule.Matches(entry) {
+			return rule.Template
+		}
+	}
+	return t.Template
+}
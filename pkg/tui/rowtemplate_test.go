@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderLogEntry_TemplateRules(t *testing.T) {
+	tmpl, rules := compileRowTemplates(client.PrinterOptions{
+		Template: ty.OptWrap("default {{.Message}}"),
+		Rules: []client.TemplateRule{
+			{Level: "ERROR", Field: "stack", Template: "error {{.Message}} {{.Field \"stack\"}}"},
+			{Level: "ERROR", Template: "{{.Broken"},
+			{Level: "ERROR", Template: "plain error {{.Message}}"},
+			{Level: "INFO", Template: "{{.Message}}"},
+		},
+	})
+	require.Len(t, rules, 3, "the rule that doesn't parse is skipped")
+
+	m := newFacetTestModel(nil)
+	tab := m.Tabs[0]
+	tab.Template, tab.RowTemplates = tmpl, rules
+	render := func(entry client.LogEntry) string {
+		return m.renderLogEntry(entry, false, 80, tab)
+	}
+
+	assert.Contains(t, render(client.LogEntry{Level: "ERROR", Message: "boom", Fields: ty.MI{"stack": "at main"}}), "error boom at main")
+	assert.Contains(t, render(client.LogEntry{Level: "ERROR", Message: "boom"}), "plain error boom", "first matching rule wins")
+	line := render(client.LogEntry{Level: "INFO", Message: "ready"})
+	assert.Contains(t, line, "ready")
+	assert.NotContains(t, line, "default")
+	assert.Contains(t, render(client.LogEntry{Level: "WARN", Message: "slow"}), "default slow")
+
+	// A template that doesn't parse falls back to the default one
+	tmpl, _ = compileRowTemplates(client.PrinterOptions{Template: ty.OptWrap("{{.Broken")})
+	tab.Template, tab.RowTemplates = tmpl, nil
+	assert.Contains(t, render(client.LogEntry{Level: "WARN", Message: "slow", ContextID: "prod"}), "[prod] WARN slow")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -142,6 +143,17 @@ type SearchBar struct {
 	AvailableVariables []string            // Variables from config
 	VariableMetadata   map[string]string   // Variable name -> description
 	FieldValues        map[string][]string // Field -> possible values (cached)
+
+	// Search history, oldest first, recalled with up/down on an empty input
+	// and saved to HistoryPath when set
+	History       [][]Chip
+	HistoryPath   string
+	historyOffset int    // Searches back from the newest being shown, 0 when not recalling
+	historyDraft  []Chip // Chips from before the recall started
+
+	// Quick-slots of chips by number, saved to SlotsPath when set
+	Slots     map[int][]Chip
+	SlotsPath string
 }
 
 // NewSearchBar creates a new search bar with default settings
@@ -194,6 +206,11 @@ func (s SearchBar) Update(msg tea.Msg) (SearchBar, tea.Cmd) {
 //
 //nolint:gocyclo // Keyboard handler with many key combinations
 func (s SearchBar) handleKey(msg tea.KeyMsg) (SearchBar, tea.Cmd) {
+	// Any other key ends the recall, the next up starts from the newest search
+	if msg.Type != tea.KeyUp && msg.Type != tea.KeyDown {
+		s.historyOffset = 0
+	}
+
 	switch msg.Type {
 	case tea.KeyTab:
 		// Toggle/cycle autocomplete
@@ -219,12 +236,20 @@ func (s SearchBar) handleKey(msg tea.KeyMsg) (SearchBar, tea.Cmd) {
 			s.State.AutocompleteIndex = (s.State.AutocompleteIndex - 1 + len(s.State.AutocompleteSuggestions)) % len(s.State.AutocompleteSuggestions)
 			return s, nil
 		}
+		if !s.State.AutocompleteOpen && s.State.CurrentInput == "" {
+			s.recallHistory(-1)
+			return s, nil
+		}
 
 	case tea.KeyDown:
 		if s.State.AutocompleteOpen && len(s.State.AutocompleteSuggestions) > 0 {
 			s.State.AutocompleteIndex = (s.State.AutocompleteIndex + 1) % len(s.State.AutocompleteSuggestions)
 			return s, nil
 		}
+		if !s.State.AutocompleteOpen && s.State.CurrentInput == "" {
+			s.recallHistory(1)
+			return s, nil
+		}
 
 	case tea.KeyEnter:
 		if s.State.AutocompleteOpen && len(s.State.AutocompleteSuggestions) > 0 {
@@ -284,6 +309,13 @@ func (s SearchBar) handleKey(msg tea.KeyMsg) (SearchBar, tea.Cmd) {
 			return s, nil
 		}
 
+	case tea.KeyRunes:
+		// o on a selected chip ORs it with the previous one, or splits an OR group
+		if s.State.SelectedChip >= 0 && string(msg.Runes) == "o" {
+			s.toggleOrChip()
+			return s, nil
+		}
+
 	case tea.KeyEscape:
 		if s.State.AutocompleteOpen {
 			s.State.AutocompleteOpen = false
@@ -460,7 +492,7 @@ func (s *SearchBar) generateSuggestions() []Suggestion {
 	input := strings.TrimSpace(s.State.CurrentInput)
 
 	// Native query: no suggestions once typing the query
-	if strings.HasPrefix(input, "query:") {
+	if strings.HasPrefix(input, "query:") || strings.HasPrefix(input, "native:") {
 		return nil // Let user type their native query freely
 	}
 
@@ -550,6 +582,7 @@ func (s *SearchBar) generateSuggestions() []Suggestion {
 		{Text: "to:", Description: "end time", Context: AutocompleteContextField},
 		{Text: "size:", Description: "result limit (e.g., 100, 500)", Context: AutocompleteContextField},
 		{Text: "query:", Description: "native query (SPL, Lucene)", Context: AutocompleteContextField},
+		{Text: "native:", Description: "native query sent as-is, no filters", Context: AutocompleteContextField},
 	}
 
 	// Add top options for this client type
@@ -570,7 +603,7 @@ func (s *SearchBar) generateSuggestions() []Suggestion {
 func (s *SearchBar) getKnownOptions(clientType string) []string {
 	switch strings.ToLower(clientType) {
 	case "splunk":
-		return []string{"index", "fields"}
+		return []string{"index", "fields", "savedsearch"}
 	case "opensearch", "kibana", "elasticsearch":
 		return []string{"index"}
 	case "cloudwatch":
@@ -604,29 +637,49 @@ func (s *SearchBar) suggestOptions(prefix string) []Suggestion {
 
 // suggestFields suggests field names matching the prefix
 func (s *SearchBar) suggestFields(prefix string) []Suggestion {
-	var suggestions []Suggestion
 	prefix = strings.ToLower(prefix)
 
+	// Fields starting with the prefix first, then the fuzzy matches like
+	// trace_id for "trid" by score, then the other fields containing it
+	type match struct {
+		field string
+		tier  int
+		score int
+	}
+	var matches []match
 	for _, field := range s.AvailableFields {
-		if prefix == "" || strings.Contains(strings.ToLower(field), prefix) {
-			suggestions = append(suggestions, Suggestion{
-				Text:        field,
-				Description: "field",
-				Context:     AutocompleteContextField,
-			})
+		lower := strings.ToLower(field)
+		if strings.HasPrefix(lower, prefix) {
+			matches = append(matches, match{field: field})
+		} else if score, ok := fuzzyScore(prefix, field); ok && score > 0 {
+			matches = append(matches, match{field: field, tier: 1, score: score})
+		} else if strings.Contains(lower, prefix) {
+			matches = append(matches, match{field: field, tier: 2})
 		}
 	}
 
-	// Sort by relevance (prefix match first)
-	sort.Slice(suggestions, func(i, j int) bool {
-		iMatch := strings.HasPrefix(strings.ToLower(suggestions[i].Text), prefix)
-		jMatch := strings.HasPrefix(strings.ToLower(suggestions[j].Text), prefix)
-		if iMatch != jMatch {
-			return iMatch
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.tier != b.tier {
+			return a.tier < b.tier
+		}
+		if a.score != b.score {
+			return a.score > b.score
 		}
-		return suggestions[i].Text < suggestions[j].Text
+		if a.tier == 1 && len(a.field) != len(b.field) {
+			return len(a.field) < len(b.field) // Fewer unmatched characters
+		}
+		return a.field < b.field
 	})
 
+	suggestions := make([]Suggestion, 0, len(matches))
+	for _, m := range matches {
+		suggestions = append(suggestions, Suggestion{
+			Text:        m.field,
+			Description: "field",
+			Context:     AutocompleteContextField,
+		})
+	}
 	return suggestions
 }
 
@@ -813,6 +866,18 @@ func (s *SearchBar) parseInput(input string) Chip {
 		}
 	}
 
+	// Native-only query: native:index=main | stats count (filters are not appended)
+	if strings.HasPrefix(input, "native:") {
+		value := strings.TrimPrefix(input, "native:")
+		return Chip{
+			Type:     ChipTypeNativeQuery,
+			Field:    nativeOnlyChipField,
+			Value:    value,
+			Display:  input,
+			Editable: true,
+		}
+	}
+
 	// Time range: last:1h, from:2024-01-01, to:now
 	if strings.HasPrefix(input, "last:") {
 		return Chip{
@@ -889,6 +954,16 @@ func (s *SearchBar) parseInput(input string) Chip {
 		}
 	}
 
+	// Missing field: field!exists
+	if matches := regexp.MustCompile(`^([a-zA-Z0-9_.-]+)!exists$`).FindStringSubmatch(input); len(matches) == 2 {
+		return Chip{
+			Type:     ChipTypeField,
+			Field:    matches[1],
+			Operator: "!exists",
+			Display:  input,
+		}
+	}
+
 	// Field with operator: field=value, field!=value, field~=value, etc.
 	// Pattern: field{op}value where op is =, !=, ~=, >, >=, <, <=
 	opPattern := regexp.MustCompile(`^([a-zA-Z0-9_.-]+)(!=|~=|>=|<=|=|>|<)(.*)$`)
@@ -1041,7 +1116,15 @@ func mapClientOperatorToUI(op string, negate bool) string {
 	case operator.Lte:
 		return "<="
 	case operator.Exists:
+		if negate {
+			return "!exists"
+		}
 		return " exists"
+	case operator.NotExists:
+		if negate {
+			return " exists"
+		}
+		return "!exists"
 	case operator.Wildcard:
 		if negate {
 			return "!*="
@@ -1068,8 +1151,9 @@ func filterToChipsWithDepth(filter *client.Filter, depth int) []Chip {
 		return nil
 	}
 
-	// Prevent infinite recursion and collapse deeply nested filters
-	if depth > maxFilterDepth {
+	// Prevent infinite recursion and collapse deeply nested filters. A NOT of
+	// plain conditions has no nesting left and keeps its editable chip.
+	if depth > maxFilterDepth && !isFlatNotGroup(filter) {
 		return []Chip{{
 			Type:        ChipTypeFilterGroup,
 			Display:     "[Complex filter]",
@@ -1136,7 +1220,22 @@ func groupFilterToChips(filter *client.Filter, depth int) []Chip {
 	return nil
 }
 
-// createGroupChip creates a ChipTypeFilterGroup for OR/complex groups
+// isFlatNotGroup reports whether filter is a NOT group of conditions only,
+// with no nested group.
+func isFlatNotGroup(filter *client.Filter) bool {
+	if filter.Logic != client.LogicNot || len(filter.Filters) == 0 {
+		return false
+	}
+	for i := range filter.Filters {
+		if filter.Filters[i].Field == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// createGroupChip creates a ChipTypeFilterGroup for OR/complex groups. The
+// chip keeps its own copy of filter, which BuildSearchFromChips returns as is.
 func createGroupChip(filter *client.Filter) Chip {
 	display := formatFilterForDisplay(filter)
 
@@ -1144,8 +1243,8 @@ func createGroupChip(filter *client.Filter) Chip {
 		Type:        ChipTypeFilterGroup,
 		Display:     display,
 		GroupLogic:  string(filter.Logic),
-		GroupFilter: filter,
-		Editable:    false, // Complex groups are read-only in chip form
+		GroupFilter: filter.Clone(),
+		Editable:    isFlatNotGroup(filter), // Other complex groups are read-only in chip form
 	}
 }
 
@@ -1207,12 +1306,17 @@ func (s *SearchBar) PopulateFromSearch(search *client.LogSearch) {
 	// Add native query chip (if present)
 	if search.NativeQuery.Set && search.NativeQuery.Value != "" {
 		displayValue := search.NativeQuery.Value
-		s.State.Chips = append(s.State.Chips, Chip{
+		chip := Chip{
 			Type:     ChipTypeNativeQuery,
 			Value:    search.NativeQuery.Value,
 			Display:  "query:" + truncateForDisplay(displayValue, 40),
 			Editable: true,
-		})
+		}
+		if search.NativeQueryOnly {
+			chip.Field = nativeOnlyChipField
+			chip.Display = "native:" + truncateForDisplay(displayValue, 40)
+		}
+		s.State.Chips = append(s.State.Chips, chip)
 	}
 
 	// Add time range chips
@@ -1318,6 +1422,8 @@ func mapUIOperatorToClient(uiOp string) (string, bool) {
 		return operator.Lte, false
 	case " exists":
 		return operator.Exists, false
+	case "!exists":
+		return operator.NotExists, false
 	default:
 		return operator.Equals, false
 	}
@@ -1367,21 +1473,12 @@ func (s *SearchBar) BuildSearchFromChips() *client.LogSearch {
 
 		case ChipTypeNativeQuery:
 			search.NativeQuery.S(chip.Value)
+			search.NativeQueryOnly = chip.Field == nativeOnlyChipField
 
-		case ChipTypeField:
+		case ChipTypeField, ChipTypeFilterGroup:
 			// Convert to Filter node instead of legacy Fields map
-			op, negate := mapUIOperatorToClient(chip.Operator)
-			filterChips = append(filterChips, client.Filter{
-				Field:  chip.Field,
-				Op:     op,
-				Value:  chip.Value,
-				Negate: negate,
-			})
-
-		case ChipTypeFilterGroup:
-			// Preserve the original filter structure
-			if chip.GroupFilter != nil {
-				filterChips = append(filterChips, *chip.GroupFilter)
+			if filter, ok := chipFilter(chip); ok {
+				filterChips = append(filterChips, filter)
 			}
 
 		case ChipTypeOption:
@@ -1406,3 +1503,69 @@ func (s *SearchBar) BuildSearchFromChips() *client.LogSearch {
 
 	return search
 }
+
+// chipFilter returns the filter of a field chip, or the original filter of a
+// group chip, and false for the other chips.
+func chipFilter(chip Chip) (client.Filter, bool) {
+	switch chip.Type {
+	case ChipTypeField:
+		op, negate := mapUIOperatorToClient(chip.Operator)
+		return client.Filter{
+			Field:  chip.Field,
+			Op:     op,
+			Value:  chip.Value,
+			Negate: negate,
+		}, true
+	case ChipTypeFilterGroup:
+		// Preserve the original filter structure
+		if chip.GroupFilter != nil {
+			return *chip.GroupFilter, true
+		}
+	}
+	return client.Filter{}, false
+}
+
+// toggleOrChip splits the selected chip back into separate chips when it is
+// an OR group, and otherwise ORs it with the previous chip, extending the
+// previous chip when it already is an OR group. Chips that are not filters
+// are left alone.
+func (s *SearchBar) toggleOrChip() {
+	i := s.State.SelectedChip
+	if i < 0 || i >= len(s.State.Chips) {
+		return
+	}
+	chips := s.State.Chips
+	chip := chips[i]
+
+	if chip.Type == ChipTypeFilterGroup && chip.GroupLogic == string(client.LogicOr) && chip.GroupFilter != nil {
+		var split []Chip
+		for j := range chip.GroupFilter.Filters {
+			split = append(split, filterToChips(&chip.GroupFilter.Filters[j])...)
+		}
+		s.State.Chips = slices.Concat(chips[:i], split, chips[i+1:])
+		return
+	}
+
+	if i == 0 {
+		return
+	}
+	previous, ok := chipFilter(chips[i-1])
+	if !ok {
+		return
+	}
+	current, ok := chipFilter(chip)
+	if !ok {
+		return
+	}
+
+	group := &client.Filter{Logic: client.LogicOr}
+	for _, f := range []client.Filter{previous, current} {
+		if f.Logic == client.LogicOr {
+			group.Filters = append(group.Filters, f.Filters...)
+		} else {
+			group.Filters = append(group.Filters, f)
+		}
+	}
+	s.State.Chips = slices.Concat(chips[:i-1], []Chip{createGroupChip(group)}, chips[i+1:])
+	s.State.SelectedChip = i - 1
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/operator"
+	"github.com/bascanada/logviewer/pkg/ty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSearchBar_NativeOnlyChip verifies that the "native:" prefix round-trips
+// through chips as a native-only search.
+func TestSearchBar_NativeOnlyChip(t *testing.T) {
+	t.Run("native prefix sets NativeQueryOnly", func(t *testing.T) {
+		sb := NewSearchBar()
+		sb.State.AddChip(sb.parseInput("native:index=main | stats count"))
+
+		search := sb.BuildSearchFromChips()
+		assert.Equal(t, "index=main | stats count", search.NativeQuery.Value)
+		assert.True(t, search.NativeQueryOnly)
+	})
+
+	t.Run("query prefix keeps filters appended", func(t *testing.T) {
+		sb := NewSearchBar()
+		sb.State.AddChip(sb.parseInput("query:index=main"))
+
+		search := sb.BuildSearchFromChips()
+		assert.Equal(t, "index=main", search.NativeQuery.Value)
+		assert.False(t, search.NativeQueryOnly)
+	})
+
+	t.Run("populate from native-only search", func(t *testing.T) {
+		sb := NewSearchBar()
+		sb.PopulateFromSearch(&client.LogSearch{
+			NativeQuery:     ty.OptWrap("index=main"),
+			NativeQueryOnly: true,
+		})
+
+		assert.Len(t, sb.State.Chips, 1)
+		assert.Equal(t, "native:index=main", sb.State.Chips[0].Display)
+		assert.True(t, sb.BuildSearchFromChips().NativeQueryOnly)
+	})
+}
+
+// TestSearchBar_NotExistsChip verifies that "field!exists" round-trips through
+// chips as a not_exists filter.
+func TestSearchBar_NotExistsChip(t *testing.T) {
+	t.Run("input builds not_exists filter", func(t *testing.T) {
+		sb := NewSearchBar()
+		sb.State.AddChip(sb.parseInput("trace_id!exists"))
+
+		search := sb.BuildSearchFromChips()
+		assert.NotNil(t, search.Filter)
+		assert.Equal(t, "trace_id", search.Filter.Field)
+		assert.Equal(t, operator.NotExists, search.Filter.Op)
+	})
+
+	t.Run("negated exists displays as not exists", func(t *testing.T) {
+		sb := NewSearchBar()
+		sb.PopulateFromSearch(&client.LogSearch{
+			Filter: &client.Filter{Field: "trace_id", Op: operator.Exists, Negate: true},
+		})
+
+		assert.Len(t, sb.State.Chips, 1)
+		assert.Equal(t, "trace_id!exists", sb.State.Chips[0].Display)
+	})
+}
+
+// TestSearchBar_OrChips verifies that o ORs the selected chip with the
+// previous one and splits an OR group back.
+func TestSearchBar_OrChips(t *testing.T) {
+	press := func(sb SearchBar, key tea.KeyMsg) SearchBar {
+		sb, _ = sb.handleKey(key)
+		return sb
+	}
+	o := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")}
+
+	sb := NewSearchBar()
+	sb.State.AddChip(sb.parseInput("app=api"))
+	sb.State.AddChip(sb.parseInput("level=ERROR"))
+	sb.State.AddChip(sb.parseInput("level=WARN"))
+	sb.State.AddChip(sb.parseInput("last:1h"))
+	sb.State.SelectedChip = 2
+
+	sb = press(sb, o)
+	assert.Len(t, sb.State.Chips, 3)
+	assert.Equal(t, 1, sb.State.SelectedChip, "the group is selected")
+	group := sb.State.Chips[1]
+	assert.Equal(t, ChipTypeFilterGroup, group.Type)
+	assert.Equal(t, "(level=ERROR OR level=WARN)", group.Display)
+	assert.Empty(t, sb.State.CurrentInput, "o is not typed into the input")
+
+	search := sb.BuildSearchFromChips()
+	assert.Equal(t, "1h", search.Range.Last.Value)
+	assert.Equal(t, &client.Filter{Logic: client.LogicAnd, Filters: []client.Filter{
+		{Field: "app", Op: operator.Equals, Value: "api"},
+		{Logic: client.LogicOr, Filters: []client.Filter{
+			{Field: "level", Op: operator.Equals, Value: "ERROR"},
+			{Field: "level", Op: operator.Equals, Value: "WARN"},
+		}},
+	}}, search.Filter)
+
+	// Round-trips through filterToChips
+	chips := filterToChips(search.Filter)
+	assert.Len(t, chips, 2)
+	assert.Equal(t, "(level=ERROR OR level=WARN)", chips[1].Display)
+
+	// A chip after an OR group extends it
+	sb.State.Chips = append(sb.State.Chips[:2], sb.parseInput("level=FATAL"))
+	sb.State.SelectedChip = 2
+	sb = press(sb, o)
+	assert.Equal(t, "(level=ERROR OR level=WARN OR level=FATAL)", sb.State.Chips[1].Display)
+
+	// o on the group splits it again
+	sb = press(sb, o)
+	assert.Len(t, sb.State.Chips, 4)
+	assert.Equal(t, "level=WARN", sb.State.Chips[2].Display)
+	assert.Equal(t, ChipTypeField, sb.State.Chips[3].Type)
+
+	// Non-filter chips and the first chip are left alone
+	sb.State.AddChip(sb.parseInput("last:1h"))
+	sb.State.SelectedChip = 4
+	sb = press(sb, o)
+	sb.State.SelectedChip = 0
+	sb = press(sb, o)
+	assert.Len(t, sb.State.Chips, 5)
+}
+
+// TestSearchBar_NotGroupRoundTrip verifies that a NOT of several conditions
+// becomes an editable group chip and is rebuilt unchanged.
+func TestSearchBar_NotGroupRoundTrip(t *testing.T) {
+	original := &client.Filter{Logic: client.LogicAnd, Filters: []client.Filter{
+		{Field: "app", Op: operator.Equals, Value: "api"},
+		{Logic: client.LogicNot, Filters: []client.Filter{
+			{Field: "level", Op: operator.Equals, Value: "DEBUG"},
+			{Field: "path", Op: operator.Match, Value: "health"},
+		}},
+	}}
+
+	sb := NewSearchBar()
+	sb.PopulateFromSearch(&client.LogSearch{Filter: original.Clone()})
+	assert.Len(t, sb.State.Chips, 2)
+	group := sb.State.Chips[1]
+	assert.Equal(t, ChipTypeFilterGroup, group.Type)
+	assert.Equal(t, string(client.LogicNot), group.GroupLogic)
+	assert.Equal(t, "NOT(level=DEBUG AND path~=health)", group.Display)
+	assert.True(t, group.Editable)
+
+	assert.Equal(t, original, sb.BuildSearchFromChips().Filter)
+
+	// Past maxFilterDepth the NOT group keeps its chip, other groups collapse
+	deep := &client.Filter{Logic: client.LogicNot, Filters: original.Filters[1].Filters}
+	or := &client.Filter{Logic: client.LogicOr, Filters: original.Filters[1].Filters}
+	assert.True(t, filterToChipsWithDepth(deep, maxFilterDepth+1)[0].Editable)
+	assert.Equal(t, "[Complex filter]", filterToChipsWithDepth(or, maxFilterDepth+1)[0].Display)
+	assert.False(t, filterToChips(or)[0].Editable, "OR groups stay read-only")
+}
@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// Session is the set of open tabs saved on quit and restored on launch.
+// Entries are not persisted: restored tabs are queried again.
+type Session struct {
+	ActiveTab int          `yaml:"activeTab"`
+	Tabs      []SessionTab `yaml:"tabs"`
+}
+
+// SessionTab is the persisted state of one tab.
+type SessionTab struct {
+	Name      string   `yaml:"name"`
+	ContextID string   `yaml:"contextId"`
+	Inherits  []string `yaml:"inherits,omitempty"`
+	Chips     []Chip   `yaml:"chips,omitempty"`
+}
+
+// DefaultSessionPath returns ~/.logviewer/session.yaml.
+func DefaultSessionPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, config.DefaultConfigDir, "session.yaml"), nil
+}
+
+// LoadSession reads the session file at path. A missing file returns an empty
+// session. Tabs whose context no longer exists in cfg are dropped and their
+// context IDs returned so the caller can warn about them.
+func LoadSession(path string, cfg *config.ContextConfig) (*Session, []string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Session{}, nil, nil
+		}
+		return &Session{}, nil, err
+	}
+	var session Session
+	if err := yaml.Unmarshal(data, &session); err != nil {
+		return &Session{}, nil, fmt.Errorf("parsing session file %s: %w", path, err)
+	}
+
+	var skipped []string
+	valid := session.Tabs[:0]
+	for i, tab := range session.Tabs {
+		known := false
+		if cfg != nil {
+			_, known = cfg.Contexts[tab.ContextID]
+		}
+		if !known {
+			skipped = append(skipped, tab.ContextID)
+			if i < session.ActiveTab {
+				session.ActiveTab--
+			}
+			continue
+		}
+		valid = append(valid, tab)
+	}
+	session.Tabs = valid
+	if session.ActiveTab < 0 || session.ActiveTab >= len(session.Tabs) {
+		session.ActiveTab = 0
+	}
+	return &session, skipped, nil
+}
+
+// SaveSession writes session to path. An empty session removes the file so
+// the next launch starts fresh.
+func SaveSession(path string, session *Session) error {
+	if session == nil || len(session.Tabs) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(session)
+	if err != nil {
+		return err
+	}
+	// Chips may hold filter values, keep the file private like state.yaml
+	return os.WriteFile(path, data, 0600)
+}
+
+// buildSession captures the open tabs, without their entries.
+func (m *Model) buildSession() *Session {
+	m.saveSearchBarToTab(m.CurrentTab())
+
+	session := &Session{ActiveTab: m.ActiveTab}
+	for _, tab := range m.Tabs {
+		session.Tabs = append(session.Tabs, SessionTab{
+			Name:      tab.Name,
+			ContextID: tab.ContextID,
+			Inherits:  tab.Inherits,
+			Chips:     tab.SearchState.Chips,
+		})
+	}
+	return session
+}
+
+// saveSession writes the open tabs to SessionPath, if set.
+func (m *Model) saveSession() {
+	if m.SessionPath == "" {
+		return
+	}
+	if err := SaveSession(m.SessionPath, m.buildSession()); err != nil {
+		log.Printf("[WARN] TUI saveSession: failed to write %s: %v", m.SessionPath, err)
+	}
+}
+
+// restoreTabCmd recreates a tab from its saved state and returns a command to
+// query its logs again.
+func (m *Model) restoreTabCmd(saved SessionTab) tea.Cmd {
+	// The load command returned here is built from an empty search; the chips
+	// below replace it through refreshCurrentTab.
+	_ = m.addTabCmd(saved.ContextID, &client.LogSearch{})
+	tab := m.CurrentTab()
+	if saved.Name != "" {
+		tab.Name = saved.Name
+	}
+	tab.SearchState = NewChipSearchState()
+	tab.SearchState.Chips = saved.Chips
+	// Inherits picked from the I menu have no chip; add one so the refresh
+	// below keeps them
+	for _, inherit := range saved.Inherits {
+		if !hasInheritChip(saved.Chips, inherit) {
+			tab.SearchState.Chips = append(tab.SearchState.Chips, Chip{
+				Type:    ChipTypeInherit,
+				Value:   inherit,
+				Display: "inherit:" + inherit,
+			})
+		}
+	}
+	m.restoreSearchBarFromTab(tab)
+	m.StatusBar.UpdateTimeRangeFromChips(m.SearchBar.State.Chips)
+
+	log.Printf("[DEBUG] TUI restoreTabCmd: restored tab, contextID=%s, chips=%d", saved.ContextID, len(saved.Chips))
+	return m.refreshCurrentTab()
+}
+
+// hasInheritChip reports whether chips already include inherit.
+func hasInheritChip(chips []Chip, inherit string) bool {
+	for _, c := range chips {
+		if c.Type == ChipTypeInherit && c.Value == inherit {
+			return true
+		}
+	}
+	return false
+}
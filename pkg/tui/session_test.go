@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sessionTestConfig(contexts ...string) *config.ContextConfig {
+	cfg := &config.ContextConfig{Contexts: config.Contexts{}}
+	for _, id := range contexts {
+		cfg.Contexts[id] = config.SearchContext{}
+	}
+	return cfg
+}
+
+func TestSession_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.yaml")
+	cfg := sessionTestConfig("prod", "staging")
+
+	m := New(cfg, nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	m.SessionPath = path
+	m.Tabs = []*Tab{
+		{ID: "t1", Name: "prod", ContextID: "prod", Entries: []client.LogEntry{{Message: "not persisted"}}},
+		{
+			ID: "t2", Name: "staging", ContextID: "staging", Inherits: []string{"json"},
+			SearchState: ChipSearchState{Chips: []Chip{
+				{Type: ChipTypeField, Field: "level", Operator: "=", Value: "ERROR", Display: "level=ERROR"},
+				{Type: ChipTypeFilterGroup, GroupLogic: "OR", GroupFilter: &client.Filter{Logic: client.LogicOr, Filters: []client.Filter{{Field: "app", Value: "api"}}}},
+			}},
+		},
+	}
+	m.switchToTab(1)
+	m.cleanup()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "not persisted")
+
+	session, skipped, err := LoadSession(path, cfg)
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+	assert.Equal(t, 1, session.ActiveTab)
+	require.Len(t, session.Tabs, 2)
+	assert.Equal(t, "staging", session.Tabs[1].ContextID)
+	assert.Equal(t, []string{"json"}, session.Tabs[1].Inherits)
+	require.Len(t, session.Tabs[1].Chips, 2)
+	assert.Equal(t, "level=ERROR", session.Tabs[1].Chips[0].Display)
+	assert.Equal(t, "api", session.Tabs[1].Chips[1].GroupFilter.Filters[0].Value)
+}
+
+func TestLoadSession_SkipsDeletedContexts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.yaml")
+	require.NoError(t, SaveSession(path, &Session{
+		ActiveTab: 2,
+		Tabs: []SessionTab{
+			{ContextID: "gone"},
+			{ContextID: "prod"},
+			{ContextID: "staging"},
+		},
+	}))
+
+	session, skipped, err := LoadSession(path, sessionTestConfig("prod", "staging"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gone"}, skipped)
+	require.Len(t, session.Tabs, 2)
+	assert.Equal(t, 1, session.ActiveTab, "active tab follows the removed one")
+}
+
+func TestLoadSession_NilConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.yaml")
+	require.NoError(t, SaveSession(path, &Session{Tabs: []SessionTab{{ContextID: "prod"}}}))
+
+	session, skipped, err := LoadSession(path, nil)
+	require.NoError(t, err)
+	assert.Empty(t, session.Tabs)
+	assert.Equal(t, []string{"prod"}, skipped)
+}
+
+func TestLoadSession_MissingFile(t *testing.T) {
+	session, skipped, err := LoadSession(filepath.Join(t.TempDir(), "none.yaml"), sessionTestConfig())
+	require.NoError(t, err)
+	assert.Empty(t, session.Tabs)
+	assert.Empty(t, skipped)
+}
+
+func TestSaveSession_EmptyRemovesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.yaml")
+	require.NoError(t, SaveSession(path, &Session{Tabs: []SessionTab{{ContextID: "prod"}}}))
+	require.NoError(t, SaveSession(path, &Session{}))
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestInit_RestoresSessionTabs(t *testing.T) {
+	m := New(sessionTestConfig("prod", "staging"), nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	m.InitialSession = &Session{
+		ActiveTab: 1,
+		Tabs: []SessionTab{
+			{Name: "prod", ContextID: "prod"},
+			{Name: "errors", ContextID: "staging", Inherits: []string{"json"}, Chips: []Chip{
+				{Type: ChipTypeContext, Value: "staging", Display: "staging"},
+				{Type: ChipTypeField, Field: "level", Operator: "=", Value: "ERROR", Display: "level=ERROR"},
+			}},
+		},
+	}
+
+	updated, cmd := m.Update(InitMsg{})
+	m = updated.(Model)
+	assert.NotNil(t, cmd)
+	require.Len(t, m.Tabs, 2)
+	assert.Equal(t, 1, m.ActiveTab)
+
+	tab := m.Tabs[1]
+	assert.Equal(t, "errors", tab.Name)
+	assert.Empty(t, tab.Entries)
+	assert.True(t, tab.Loading)
+	assert.Equal(t, []string{"json"}, tab.Inherits)
+	require.NotNil(t, tab.Search)
+	assert.True(t, hasLeaf(tab.Search.Filter, "level", "ERROR"), "chip filter restored")
+}
+
+func hasLeaf(f *client.Filter, field, value string) bool {
+	if f == nil {
+		return false
+	}
+	if f.Field == field && f.Value == value {
+		return true
+	}
+	for i := range f.Filters {
+		if hasLeaf(&f.Filters[i], field, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"log"
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+)
+
+// Bounds of SplitRatio, the share of the width given to the log list.
+const (
+	minSplitRatio = 0.3
+	maxSplitRatio = 0.9
+)
+
+// sidebarModeNames maps the sidebarMode config values to the modes.
+var sidebarModeNames = map[string]SidebarMode{
+	"entry":  SidebarModeEntry,
+	"fields": SidebarModeFields,
+	"json":   SidebarModeJSON,
+}
+
+// ParseSidebarMode returns the mode named s (entry, fields or json), and
+// false along with SidebarModeEntry for unknown names.
+func ParseSidebarMode(s string) (SidebarMode, bool) {
+	mode, ok := sidebarModeNames[strings.ToLower(strings.TrimSpace(s))]
+	return mode, ok
+}
+
+// ApplyTUIConfig sets the sidebar defaults and level colors set in cfg.
+// Ratios out of bounds are clamped, unknown modes fall back to the entry
+// details and invalid colors are skipped.
+func (m *Model) ApplyTUIConfig(cfg config.TUIConfig) {
+	if cfg.DetailsVisible.Set {
+		m.DetailsVisible = cfg.DetailsVisible.Value
+	}
+	if cfg.SidebarMode.Set {
+		mode, ok := ParseSidebarMode(cfg.SidebarMode.Value)
+		if !ok {
+			log.Printf("[WARN] TUI: unknown sidebar mode %q, using entry", cfg.SidebarMode.Value)
+		}
+		m.SidebarMode = mode
+	}
+	if cfg.SplitRatio.Set {
+		m.SplitRatio = min(max(cfg.SplitRatio.Value, minSplitRatio), maxSplitRatio)
+	}
+	m.Styles.ApplyLevelColors(cfg.LevelColors)
+}
+
+// resizeSidebar widens the sidebar when expand is set, narrows it otherwise.
+func (m *Model) resizeSidebar(expand bool) {
+	if !m.DetailsVisible {
+		return
+	}
+	if expand && m.SplitRatio > minSplitRatio {
+		m.SplitRatio -= 0.05
+		m.updateViewportSizes()
+	} else if !expand && m.SplitRatio < maxSplitRatio {
+		m.SplitRatio += 0.05
+		m.updateViewportSizes()
+	}
+	m.updateSidebarContent()
+}
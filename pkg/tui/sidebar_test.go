@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_AppliesTUIConfig(t *testing.T) {
+	cfg := sessionTestConfig("prod")
+	cfg.TUI.DetailsVisible.S(true)
+	cfg.TUI.SidebarMode.S("JSON")
+	cfg.TUI.SplitRatio.S(0.6)
+
+	m := New(cfg, nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	assert.True(t, m.DetailsVisible)
+	assert.Equal(t, SidebarModeJSON, m.SidebarMode)
+	assert.InDelta(t, 0.6, m.SplitRatio, 1e-9)
+
+	// Defaults are kept without a tui section
+	m = New(sessionTestConfig("prod"), nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	assert.False(t, m.DetailsVisible)
+	assert.Equal(t, SidebarModeEntry, m.SidebarMode)
+	assert.InDelta(t, 0.7, m.SplitRatio, 1e-9)
+}
+
+func TestApplyTUIConfig(t *testing.T) {
+	m := New(nil, nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	m.SidebarMode = SidebarModeFields
+
+	var cfg config.TUIConfig
+	cfg.SidebarMode.S("tree")
+	cfg.SplitRatio.S(1.5)
+	m.ApplyTUIConfig(cfg)
+	assert.Equal(t, SidebarModeEntry, m.SidebarMode, "unknown modes fall back to entry")
+	assert.InDelta(t, maxSplitRatio, m.SplitRatio, 1e-9)
+
+	cfg = config.TUIConfig{}
+	cfg.SplitRatio.S(0.1)
+	cfg.DetailsVisible.S(true)
+	m.ApplyTUIConfig(cfg)
+	assert.InDelta(t, minSplitRatio, m.SplitRatio, 1e-9)
+	assert.True(t, m.DetailsVisible)
+
+	// Unset settings, like flags not given, leave the model alone
+	m.ApplyTUIConfig(config.TUIConfig{})
+	assert.True(t, m.DetailsVisible)
+	assert.InDelta(t, minSplitRatio, m.SplitRatio, 1e-9)
+}
+
+func TestApplyTUIConfig_LevelColors(t *testing.T) {
+	m := New(nil, nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	assert.Equal(t, m.Styles.LogEntry.GetForeground(), m.Styles.EntryStyle("INFO").GetForeground())
+	assert.Equal(t, ColorError, m.Styles.EntryStyle("error").GetForeground(), "levels match case-insensitively")
+	assert.Equal(t, ColorMuted, m.Styles.EntryStyle("DEBUG").GetForeground())
+
+	m.ApplyTUIConfig(config.TUIConfig{LevelColors: map[string]string{
+		"info":  "#00FF00",
+		"error": "default",
+		"warn":  "not-a-color",
+		"debug": "300",
+	}})
+	assert.Equal(t, lipgloss.Color("#00FF00"), m.Styles.EntryStyle("INFO").GetForeground())
+	assert.Equal(t, m.Styles.LogEntry.GetForeground(), m.Styles.EntryStyle("ERROR").GetForeground(), "default gives the normal row color back")
+	assert.Equal(t, ColorWarning, m.Styles.EntryStyle("WARN").GetForeground(), "invalid colors are skipped")
+	assert.Equal(t, ColorMuted, m.Styles.EntryStyle("DEBUG").GetForeground(), "ANSI numbers stop at 255")
+
+	// Other models keep the built-in colors
+	other := New(nil, nil, &MockSearchFactory{Store: NewInMemoryLogStore()})
+	assert.Equal(t, ColorError, other.Styles.EntryStyle("ERROR").GetForeground())
+}
+
+func TestWrapContent(t *testing.T) {
+	got := wrapContent("short\n    \"key\": \"abcdefghijkl\"", 14)
+	assert.Equal(t, "short\n    \"key\": \"ab\n    cdefghijkl\n    \"", got, "continuation lines keep the indentation")
+
+	// Colors open at a break are closed and opened again on the next line
+	red := "\x1b[31m"
+	got = wrapContent(red+"abcdefgh"+ansiReset+"ij", 4)
+	assert.Equal(t, []string{red + "abcd" + ansiReset, red + "efgh" + ansiReset, ansiReset + "ij"}, strings.Split(got, "\n"))
+
+	for _, line := range strings.Split(wrapContent("  "+red+strings.Repeat("x", 50), 10), "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), 10)
+	}
+	assert.Equal(t, "unchanged", wrapContent("unchanged", 0))
+}
+
+func TestSidebarContentFitsWidth(t *testing.T) {
+	entries := []client.LogEntry{{Message: "m", Fields: ty.MI{"payload": strings.Repeat("long-value ", 30)}}}
+	m := newFacetTestModel(entries)
+	m.DetailsVisible = true
+	m.updateViewportSizes()
+	m.updateSidebarContent()
+
+	width := m.SidebarVP.Width
+	raw := m.renderEntryDetails(entries[0])
+	assert.Greater(t, m.SidebarVP.TotalLineCount(), strings.Count(raw, "\n")+1, "wrapped lines are kept for scrolling")
+	for _, line := range strings.Split(m.SidebarVP.View(), "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), width)
+	}
+
+	// Narrowing the sidebar wraps it again
+	m.resizeSidebar(false)
+	assert.Less(t, m.SidebarVP.Width, width)
+	assert.Contains(t, m.SidebarVP.View(), "long-value")
+}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/log/client/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxSlots is the number of quick-slots, saved with S then 1-9 and recalled
+// with Alt+1-9.
+const maxSlots = 9
+
+// DefaultSlotsPath returns ~/.logviewer/slots.json.
+func DefaultSlotsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, config.DefaultConfigDir, "slots.json"), nil
+}
+
+// LoadSlots reads the quick-slots saved at path. A missing file returns no
+// slots.
+func LoadSlots(path string) (map[int][]Chip, error) {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[int][]Chip{}, nil
+		}
+		return map[int][]Chip{}, err
+	}
+	slots := map[int][]Chip{}
+	if err := json.Unmarshal(data, &slots); err != nil {
+		return map[int][]Chip{}, fmt.Errorf("parsing slots file %s: %w", path, err)
+	}
+	return slots, nil
+}
+
+// SaveSlots writes slots to path.
+func SaveSlots(path string, slots map[int][]Chip) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(slots, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Chips may hold filter values, keep the file private like history.json
+	return os.WriteFile(path, data, 0600)
+}
+
+// LoadSlots sets the file the quick-slots are saved to and reads the ones
+// already there. Errors leave the slots empty but still saved to path.
+func (s *SearchBar) LoadSlots(path string) error {
+	s.SlotsPath = path
+	slots, err := LoadSlots(path)
+	s.Slots = slots
+	return err
+}
+
+// SaveSlot stores the current chips, without the context chip tied to the
+// tab, in slot n and saves the slots to SlotsPath if set. It reports whether
+// the slot held chips before.
+func (s *SearchBar) SaveSlot(n int) bool {
+	if s.Slots == nil {
+		s.Slots = map[int][]Chip{}
+	}
+	_, overwritten := s.Slots[n]
+	s.Slots[n] = historyChips(s.State.Chips)
+	if s.SlotsPath != "" {
+		if err := SaveSlots(s.SlotsPath, s.Slots); err != nil {
+			log.Printf("[WARN] TUI SaveSlot: failed to write %s: %v", s.SlotsPath, err)
+		}
+	}
+	return overwritten
+}
+
+// RecallSlot replaces the chips, but the context chip, with the ones of slot
+// n. It returns false, leaving the chips alone, when the slot is empty.
+func (s *SearchBar) RecallSlot(n int) bool {
+	saved, ok := s.Slots[n]
+	if !ok {
+		return false
+	}
+	chips := make([]Chip, 0, len(saved)+1)
+	for _, chip := range s.State.Chips {
+		if chip.Type == ChipTypeContext {
+			chips = append(chips, chip)
+		}
+	}
+	s.State.Chips = append(chips, copyChips(saved)...)
+	s.State.SelectedChip = -1
+	s.historyOffset = 0
+	return true
+}
+
+// slotForKey returns the slot of a 1-9 key, 0 for other keys.
+func slotForKey(key string) int {
+	n, err := strconv.Atoi(key)
+	if err != nil || n < 1 || n > maxSlots {
+		return 0
+	}
+	return n
+}
+
+// recallSlotForKey returns the slot an Alt+1-9 key recalls, 0 for other keys.
+func recallSlotForKey(key string) int {
+	digit, ok := strings.CutPrefix(key, "alt+")
+	if !ok {
+		return 0
+	}
+	return slotForKey(digit)
+}
+
+// saveSlot saves the chips of the current tab to slot n.
+func (m *Model) saveSlot(n int) tea.Cmd {
+	if m.SearchBar.SaveSlot(n) {
+		return m.showStatusMessage(fmt.Sprintf("Slot %d overwritten", n))
+	}
+	return m.showStatusMessage(fmt.Sprintf("Saved to slot %d", n))
+}
+
+// recallSlot replaces the chips of the current tab with slot n and queries
+// the tab again. An empty slot changes nothing.
+func (m *Model) recallSlot(n int) tea.Cmd {
+	tab := m.CurrentTab()
+	if tab == nil {
+		return nil
+	}
+	if !m.SearchBar.RecallSlot(n) {
+		return m.showStatusMessage(fmt.Sprintf("Slot %d is empty, save one with S then %d", n, n))
+	}
+	m.syncLevelsFromChips()
+	m.saveSearchBarToTab(tab)
+	cmd := m.refreshCurrentTab()
+	m.StatusBar.UpdateFromTab(tab)
+	m.StatusBar.UpdateTimeRangeFromChips(m.SearchBar.State.Chips)
+	return tea.Batch(cmd, m.showStatusMessage(fmt.Sprintf("Recalled slot %d", n)))
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchBar_Slots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slots.json")
+	sb := NewSearchBar()
+	require.NoError(t, sb.LoadSlots(path))
+	sb.State.Chips = []Chip{{Type: ChipTypeContext, Value: "prod"}, sb.parseInput("level=ERROR"), sb.parseInput("last:1h")}
+	assert.False(t, sb.SaveSlot(3))
+	assert.True(t, sb.SaveSlot(3), "saving again overwrites")
+
+	// Reloaded from disk, recalled on another tab
+	sb = NewSearchBar()
+	require.NoError(t, sb.LoadSlots(path))
+	sb.State.Chips = []Chip{{Type: ChipTypeContext, Value: "staging"}, sb.parseInput("draft=1")}
+	assert.False(t, sb.RecallSlot(4), "empty slot")
+	assert.Len(t, sb.State.Chips, 2)
+
+	require.True(t, sb.RecallSlot(3))
+	require.Len(t, sb.State.Chips, 3)
+	assert.Equal(t, "staging", sb.State.Chips[0].Value, "the tab's context is kept")
+	assert.Equal(t, "level=ERROR", sb.State.Chips[1].Display)
+	assert.Equal(t, "1h", sb.BuildSearchFromChips().Range.Last.Value)
+
+	slots, err := LoadSlots(filepath.Join(t.TempDir(), "none.json"))
+	require.NoError(t, err)
+	assert.Empty(t, slots)
+}
+
+func TestQuickSlotKeys(t *testing.T) {
+	alt := func(m Model, key string) Model {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key), Alt: true})
+		return updated.(Model)
+	}
+	lastMessage := func(m Model) string { return m.Messages[len(m.Messages)-1].Text }
+
+	m := newFacetTestModel(nil)
+	m.SearchBar.State.AddChip(m.SearchBar.parseInput("app=api"))
+	m = pressFacetKey(m, "S")
+	m = pressFacetKey(m, "2")
+	assert.Equal(t, "Saved to slot 2", lastMessage(m))
+	assert.Empty(t, m.PendingKey)
+	m = pressFacetKey(m, "S")
+	m = pressFacetKey(m, "2")
+	assert.Equal(t, "Slot 2 overwritten", lastMessage(m))
+
+	// S then another key saves nothing and the key works as usual
+	wrapping := m.LineWrapping
+	m = pressFacetKey(m, "S")
+	m = pressFacetKey(m, "w")
+	assert.NotEqual(t, wrapping, m.LineWrapping)
+	assert.Len(t, m.SearchBar.Slots, 1)
+
+	m = pressFacetKey(m, "1")
+	require.True(t, m.Levels["ERROR"])
+
+	// Empty slots leave the chips alone
+	m = alt(m, "5")
+	assert.Len(t, m.SearchBar.State.Chips, 2)
+	assert.Contains(t, lastMessage(m), "Slot 5 is empty")
+
+	m = alt(m, "2")
+	require.Len(t, m.SearchBar.State.Chips, 1)
+	assert.Equal(t, "app=api", m.SearchBar.State.Chips[0].Display)
+	assert.Empty(t, m.Levels, "the level toggles follow the recalled chips")
+	assert.Equal(t, "app", m.Tabs[0].Search.Filter.Field, "recalling queries the tab again")
+	assert.Equal(t, "Recalled slot 2", lastMessage(m))
+}
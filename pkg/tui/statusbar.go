@@ -19,6 +19,8 @@ type StatusBarStyles struct {
 	FollowInactive lipgloss.Style
 	PaginationMore lipgloss.Style
 	Loading        lipgloss.Style
+	Partial        lipgloss.Style
+	Paused         lipgloss.Style
 }
 
 // DefaultStatusBarStyles returns the default styles for the status bar
@@ -46,6 +48,12 @@ func DefaultStatusBarStyles() StatusBarStyles {
 		Loading: lipgloss.NewStyle().
 			Foreground(ColorPrimary).
 			Bold(true),
+		Partial: lipgloss.NewStyle().
+			Foreground(ColorError).
+			Bold(true),
+		Paused: lipgloss.NewStyle().
+			Foreground(ColorWarning).
+			Bold(true),
 	}
 }
 
@@ -65,9 +73,13 @@ type StatusBar struct {
 	FilteredCount  int // Number of entries after client-side filtering
 	CursorPosition int
 	ContextID      string
-	Loading        bool   // Whether a request is in progress
-	LoadingMore    bool   // Whether pagination is loading more entries
-	Message        string // Temporary status message
+	Levels         []string // Levels of the quick level filter, empty for all
+	Loading        bool     // Whether a request is in progress
+	LoadingMore    bool     // Whether pagination is loading more entries
+	Message        string   // Temporary status message
+	Partial        error    // Timeout that cut the loaded entries short, if any
+	Paused         bool     // Whether the live tail is paused
+	PendingCount   int      // Streamed entries held while paused
 }
 
 // NewStatusBar creates a new status bar with default styles
@@ -99,6 +111,9 @@ func (s *StatusBar) UpdateFromTab(tab *Tab) {
 	s.EntryCount = len(tab.Entries)
 	s.CursorPosition = tab.Cursor
 	s.ContextID = tab.ContextID
+	s.Partial = tab.Partial
+	s.Paused = tab.Paused
+	s.PendingCount = len(tab.Pending)
 
 	// First, get values from the result (server response)
 	if tab.Result != nil {
@@ -166,6 +181,7 @@ func (s *StatusBar) UpdateTimeRangeFromChips(chips []Chip) {
 	if hasTimeChip {
 		s.TimeRange = timeRange
 	}
+	s.Levels = chipLevels(chips)
 }
 
 // View renders the status bar
@@ -221,8 +237,16 @@ func (s StatusBar) View() string {
 			s.Styles.Label.Render("Context: ")+s.Styles.Value.Render(s.ContextID))
 	}
 
+	if len(s.Levels) > 0 {
+		line1Parts = append(line1Parts,
+			s.Styles.Label.Render("Levels: ")+s.Styles.Value.Render(strings.Join(s.Levels, ",")))
+	}
+
 	// Line 2: Loading indicator, entries, pagination, follow mode, position
-	if s.Loading {
+	if s.Loading && s.EntryCount > 0 {
+		// Entries of a progressive load arriving
+		line2Parts = append(line2Parts, s.Styles.Loading.Render(fmt.Sprintf("⏳ Loading %d...", s.EntryCount)))
+	} else if s.Loading {
 		line2Parts = append(line2Parts, s.Styles.Loading.Render("⏳ Loading..."))
 	} else if s.LoadingMore {
 		line2Parts = append(line2Parts, s.Styles.Loading.Render("⏳ Loading more..."))
@@ -241,8 +265,15 @@ func (s StatusBar) View() string {
 			s.Styles.PaginationMore.Render("[More available]"))
 	}
 
+	if s.Partial != nil {
+		line2Parts = append(line2Parts,
+			s.Styles.Partial.Render("⚠ Partial: "+s.Partial.Error()))
+	}
+
 	// Follow mode indicator
-	if s.FollowMode {
+	if s.Paused {
+		line2Parts = append(line2Parts, s.Styles.Paused.Render(fmt.Sprintf("PAUSED (%d pending)", s.PendingCount)))
+	} else if s.FollowMode {
 		followText := "LIVE"
 		if s.RefreshRate != "" {
 			followText = fmt.Sprintf("LIVE (%s)", s.RefreshRate)
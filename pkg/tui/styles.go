@@ -3,7 +3,13 @@
 // Package tui provides the terminal user interface components.
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Color palette
 var (
@@ -30,6 +36,17 @@ var LogLevelColors = map[string]lipgloss.Color{
 	"TRACE":   ColorMuted,
 }
 
+// LogRowLevelColors are the default colors of the log rows by level; rows of
+// the other levels, INFO included, use the LogEntry style.
+var LogRowLevelColors = map[string]lipgloss.Color{
+	"FATAL":   ColorError,
+	"ERROR":   ColorError,
+	"WARN":    ColorWarning,
+	"WARNING": ColorWarning,
+	"DEBUG":   ColorMuted,
+	"TRACE":   ColorMuted,
+}
+
 // Styles contains all UI styles
 type Styles struct {
 	// Base styles
@@ -53,6 +70,10 @@ type Styles struct {
 	LogLevel     lipgloss.Style
 	LogMessage   lipgloss.Style
 	LogContext   lipgloss.Style
+	LogMatch     lipgloss.Style // Text matched by the free-text and regex chips
+	// LogLevelEntry is the row style of each upper case level, LogEntry for
+	// the levels it lacks
+	LogLevelEntry map[string]lipgloss.Style
 
 	// Sidebar styles
 	Sidebar       lipgloss.Style
@@ -141,6 +162,13 @@ func DefaultStyles() Styles {
 			Foreground(ColorSecondary).
 			Italic(true),
 
+		LogMatch: lipgloss.NewStyle().
+			Background(ColorWarning).
+			Foreground(ColorBg).
+			Bold(true),
+
+		LogLevelEntry: levelEntryStyles(LogRowLevelColors),
+
 		// Sidebar
 		Sidebar: lipgloss.NewStyle().
 			BorderLeft(true).
@@ -189,6 +217,63 @@ func DefaultStyles() Styles {
 	}
 }
 
+// levelEntryStyles returns the LogEntry style in each color of colors.
+func levelEntryStyles(colors map[string]lipgloss.Color) map[string]lipgloss.Style {
+	styles := make(map[string]lipgloss.Style, len(colors))
+	for level, color := range colors {
+		styles[level] = lipgloss.NewStyle().Foreground(color)
+	}
+	return styles
+}
+
+// EntryStyle returns the row style of level, case insensitive.
+func (s Styles) EntryStyle(level string) lipgloss.Style {
+	if style, ok := s.LogLevelEntry[strings.ToUpper(level)]; ok {
+		return style
+	}
+	return s.LogEntry
+}
+
+// ApplyLevelColors overrides the row colors of the levels of colors, a hex
+// color or an ANSI number each. "default" gives a level the LogEntry style
+// back; other values that aren't colors are logged and skipped.
+func (s *Styles) ApplyLevelColors(colors map[string]string) {
+	if len(colors) == 0 {
+		return
+	}
+	styles := make(map[string]lipgloss.Style, len(s.LogLevelEntry)+len(colors))
+	for level, style := range s.LogLevelEntry {
+		styles[level] = style
+	}
+	for level, color := range colors {
+		level = strings.ToUpper(strings.TrimSpace(level))
+		color = strings.TrimSpace(color)
+		switch {
+		case strings.EqualFold(color, "default"):
+			delete(styles, level)
+		case isColor(color):
+			styles[level] = lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+		default:
+			log.Printf("[WARN] TUI: invalid color %q for level %s, expected #RRGGBB or an ANSI number", color, level)
+		}
+	}
+	s.LogLevelEntry = styles
+}
+
+// isColor reports whether color is a #RGB or #RRGGBB hex color or an ANSI
+// color number from 0 to 255.
+func isColor(color string) bool {
+	if hex, ok := strings.CutPrefix(color, "#"); ok {
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(color)
+	return err == nil && n >= 0 && n <= 255
+}
+
 // GetLevelStyle returns a style for the given log level
 func GetLevelStyle(level string) lipgloss.Style {
 	color, ok := LogLevelColors[level]
@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/log/printer"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// tableFieldColumns is the number of field columns of the table mode,
+	// before the message
+	tableFieldColumns = 3
+	// tableMaxColumnWidth caps a field column, longer values are truncated
+	tableMaxColumnWidth = 24
+	// tableMinMessageWidth is kept for the message, field columns are dropped
+	// from the right when the window is too narrow
+	tableMinMessageWidth = 20
+	// tableSampleSize bounds how many of the newest entries pick the columns
+	// and their widths
+	tableSampleSize = 1000
+	tableSeparator  = "  "
+	tableMissing    = "-"
+)
+
+// tableColumn is a column of the table mode: the timestamp, the level, or an
+// entry field.
+type tableColumn struct {
+	Name  string
+	Width int
+	value func(client.LogEntry) string
+}
+
+// tableLayout is the set of columns of the table mode, then the message in
+// the width left.
+type tableLayout struct {
+	Columns      []tableColumn
+	MessageWidth int
+}
+
+// buildTableLayout picks the columns of entries for a line of width: the
+// timestamp, the level and the tableFieldColumns fields of tab.Fields found
+// in most entries, each as wide as its longest value up to
+// tableMaxColumnWidth.
+func buildTableLayout(tab *Tab, entries []client.LogEntry, width int) *tableLayout {
+	sample := entries
+	if len(sample) > tableSampleSize {
+		sample = sample[len(sample)-tableSampleSize:]
+	}
+
+	columns := []tableColumn{
+		{Name: "TIME", Width: len("15:04:05"), value: func(e client.LogEntry) string {
+			if e.Timestamp.IsZero() {
+				return ""
+			}
+			return printer.FormatTimestamp(e.Timestamp, "15:04:05")
+		}},
+		{Name: "LEVEL", value: func(e client.LogEntry) string { return e.Level }},
+	}
+	for _, field := range commonTableFields(tab, sample) {
+		columns = append(columns, tableColumn{Name: field, value: func(e client.LogEntry) string {
+			value, ok := e.Fields[field]
+			if !ok || value == nil {
+				return ""
+			}
+			return fmt.Sprint(value)
+		}})
+	}
+
+	for i := range columns {
+		column := &columns[i]
+		column.Width = max(column.Width, len([]rune(column.Name)))
+		for _, entry := range sample {
+			column.Width = max(column.Width, len([]rune(cleanCell(column.value(entry)))))
+		}
+		column.Width = min(column.Width, tableMaxColumnWidth)
+	}
+
+	// Drop field columns until the message keeps its minimum width
+	used := func() int {
+		total := 0
+		for _, column := range columns {
+			total += column.Width + len(tableSeparator)
+		}
+		return total
+	}
+	for len(columns) > 2 && width-used() < tableMinMessageWidth {
+		columns = columns[:len(columns)-1]
+	}
+	return &tableLayout{Columns: columns, MessageWidth: max(width-used(), 0)}
+}
+
+// commonTableFields returns the fields of tab.Fields set in most of entries,
+// at most tableFieldColumns of them, without the level and the message that
+// have their own column. Ties are sorted by name. The fields of the entries
+// are used when the backend listed none.
+func commonTableFields(tab *Tab, entries []client.LogEntry) []string {
+	names := make(map[string]bool, len(tab.Fields))
+	for name := range tab.Fields {
+		names[name] = true
+	}
+	if len(names) == 0 {
+		for _, entry := range entries {
+			for name := range entry.Fields {
+				names[name] = true
+			}
+		}
+	}
+
+	counts := make(map[string]int)
+	for name := range names {
+		switch strings.ToLower(name) {
+		case "level", "message", "timestamp":
+			continue
+		}
+		for _, entry := range entries {
+			if value, ok := entry.Fields[name]; ok && value != nil && value != "" {
+				counts[name]++
+			}
+		}
+	}
+
+	var fields []string
+	for name, count := range counts {
+		if count > 0 {
+			fields = append(fields, name)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if counts[fields[i]] != counts[fields[j]] {
+			return counts[fields[i]] > counts[fields[j]]
+		}
+		return fields[i] < fields[j]
+	})
+	if len(fields) > tableFieldColumns {
+		fields = fields[:tableFieldColumns]
+	}
+	return fields
+}
+
+// header returns the column names aligned over the rows.
+func (l *tableLayout) header() string {
+	cells := make([]string, 0, len(l.Columns)+1)
+	for _, column := range l.Columns {
+		cells = append(cells, fitCell(column.Name, column.Width))
+	}
+	cells = append(cells, fitCell("MESSAGE", l.MessageWidth))
+	return strings.Join(cells, tableSeparator)
+}
+
+// row returns entry as a line of the table, each value truncated to its
+// column and a dash for the values it lacks.
+func (l *tableLayout) row(entry client.LogEntry) string {
+	cells := make([]string, 0, len(l.Columns)+1)
+	for _, column := range l.Columns {
+		value := cleanCell(column.value(entry))
+		if value == "" {
+			value = tableMissing
+		}
+		cells = append(cells, fitCell(value, column.Width))
+	}
+	cells = append(cells, fitCell(cleanCell(entry.Message), l.MessageWidth))
+	return strings.Join(cells, tableSeparator)
+}
+
+// cleanCell keeps a value on one line.
+func cleanCell(s string) string {
+	s = strings.ReplaceAll(s, "\r", "")
+	s = strings.ReplaceAll(s, "\t", " ")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// fitCell pads or truncates s to width runes.
+func fitCell(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		if width > 3 {
+			return string(runes[:width-3]) + "..."
+		}
+		return string(runes[:width])
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// renderTableRow renders entry as a row of the table mode, styled like the
+// template lines.
+func (m *Model) renderTableRow(entry client.LogEntry, selected bool, maxWidth int) string {
+	line := m.table.row(entry)
+	if selected {
+		return highlightMatches(m.Styles.LogSelected.Width(maxWidth).Render(line), m.highlight, m.Styles.LogMatch)
+	}
+	return highlightMatches(m.Styles.EntryStyle(entry.Level).Width(maxWidth).Render(line), m.highlight, m.Styles.LogMatch)
+}
+
+// toggleTableMode switches the list between the template lines and the
+// table mode.
+func (m *Model) toggleTableMode() tea.Cmd {
+	m.TableMode = !m.TableMode
+	if tab := m.CurrentTab(); tab != nil {
+		tab.ViewOffset = 0
+	}
+	m.updateViewportContent()
+	if m.TableMode {
+		return m.showStatusMessage("Table: ON")
+	}
+	return m.showStatusMessage("Table: OFF")
+}
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableMode(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := newFacetTestModel([]client.LogEntry{
+		{Timestamp: at, Level: "INFO", Message: "started", Fields: ty.MI{"app": "api", "pod": "api-1", "user": "bob"}},
+		{Timestamp: at, Level: "ERROR", Message: "failed\nwith a stack", Fields: ty.MI{"app": "worker", "pod": "w-1"}},
+		{Timestamp: at, Level: "WARN", Message: "slow", Fields: ty.MI{"app": "api", "region": "eu"}},
+	})
+	m.Tabs[0].Fields = ty.UniSet[string]{"app": {"api", "worker"}, "pod": {"api-1", "w-1"}, "user": {"bob"}, "region": {"eu"}, "level": {"INFO"}}
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	m = pressFacetKey(m, "v")
+	require.True(t, m.TableMode)
+	lines := strings.Split(stripSGR(m.Viewport.View()), "\n")
+	require.GreaterOrEqual(t, len(lines), 4)
+
+	// The three fields set in most entries, ties by name
+	header := strings.Fields(lines[0])
+	assert.Equal(t, []string{"TIME", "LEVEL", "app", "pod", "region", "MESSAGE"}, header)
+
+	// Columns are aligned and a missing value is a dash
+	appColumn := strings.Index(lines[0], "app")
+	assert.Equal(t, "api", lines[1][appColumn:appColumn+3])
+	assert.Equal(t, "worker", lines[2][appColumn:appColumn+6])
+	regionColumn := strings.Index(lines[0], "region")
+	assert.Equal(t, "-", lines[1][regionColumn:regionColumn+1])
+	assert.Contains(t, lines[2], "failed with a stack", "one line per entry")
+	for _, line := range lines[1:4] {
+		assert.LessOrEqual(t, lipgloss.Width(line), m.Viewport.Width)
+	}
+
+	// A narrow window drops field columns from the right and keeps the message
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 50, Height: 40})
+	m = updated.(Model)
+	lines = strings.Split(stripSGR(m.Viewport.View()), "\n")
+	header = strings.Fields(lines[0])
+	assert.Equal(t, "MESSAGE", header[len(header)-1])
+	assert.NotContains(t, header, "region")
+	assert.GreaterOrEqual(t, m.table.MessageWidth, tableMinMessageWidth)
+
+	m = pressFacetKey(m, "v")
+	assert.False(t, m.TableMode)
+	assert.NotContains(t, stripSGR(m.Viewport.View()), "MESSAGE")
+}
@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/printer"
+	"github.com/bascanada/logviewer/pkg/ty"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// parseJumpTime parses the time typed in the jump dialog: a duration like
+// 15m is that long before now, other values are read like the from and to
+// chips, e.g. 14:30, 2024-01-02 14:30 or RFC3339.
+func parseJumpTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	normalized, _ := ty.NormalizeTimeValue(value)
+	if d, err := time.ParseDuration(normalized); err == nil {
+		if d < 0 {
+			d = -d
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, normalized); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, use a duration like 15m, HH:MM or an RFC3339 timestamp", value)
+}
+
+// openTimeJump opens the jump to time dialog for the current tab.
+func (m *Model) openTimeJump() tea.Cmd {
+	tab := m.CurrentTab()
+	if tab == nil || len(tab.Entries) == 0 {
+		return m.showStatusMessage("No entries to jump to")
+	}
+
+	ti := textinput.New()
+	ti.CharLimit = 64
+	ti.Prompt = "Time: "
+	ti.Placeholder = "15m, 14:30, 2024-01-02T14:30:00Z"
+	ti.Focus()
+	m.JumpInput = ti
+	m.Focus = FocusTimeJump
+	return textinput.Blink
+}
+
+// handleTimeJump handles input when the jump to time dialog has focus.
+func (m Model) handleTimeJump(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.Focus = FocusList
+		return m, nil
+
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.JumpInput.Value())
+		if value == "" {
+			return m, nil
+		}
+		target, err := parseJumpTime(value, time.Now())
+		if err != nil {
+			return m, m.showStatusMessage(err.Error())
+		}
+		m.Focus = FocusList
+		return m.jumpToTime(target)
+	}
+
+	var cmd tea.Cmd
+	m.JumpInput, cmd = m.JumpInput.Update(msg)
+	return m, cmd
+}
+
+// jumpToTime moves the cursor to the first entry at or after target, near
+// the top of the list. When target is before the oldest loaded entry, older
+// pages are loaded first while there are some: the jump is kept in JumpTarget
+// and retried when each page arrives.
+func (m Model) jumpToTime(target time.Time) (Model, tea.Cmd) {
+	m.JumpTarget = time.Time{}
+	tab := m.CurrentTab()
+	if tab == nil || len(tab.Entries) == 0 {
+		return m, m.showStatusMessage("No entries to jump to")
+	}
+
+	label := printer.FormatTimestamp(target, "2006-01-02 15:04:05")
+	if tab.Entries[0].Timestamp.After(target) {
+		if tab.PaginationInfo != nil && tab.PaginationInfo.HasMore {
+			m.JumpTarget = target
+			if tab.LoadingMore {
+				return m, nil // The page on its way retries the jump
+			}
+			tab.LoadingMore = true
+			m.StatusBar.UpdateFromTab(tab)
+			return m, tea.Batch(m.loadMoreLogsCmd(tab), m.showStatusMessage("Loading older entries to reach "+label))
+		}
+		m.setCursorTop(tab, 0)
+		return m, m.showStatusMessage("No entries before " + label + ", moved to the oldest entry")
+	}
+
+	for i, entry := range tab.Entries {
+		if !entry.Timestamp.Before(target) {
+			m.setCursorTop(tab, i)
+			return m, m.showStatusMessage("Jumped to " + printer.FormatTimestamp(entry.Timestamp, "2006-01-02 15:04:05"))
+		}
+	}
+	m.setCursorTop(tab, len(tab.Entries)-1)
+	return m, m.showStatusMessage("No entries after " + label + ", moved to the newest entry")
+}
+
+// setCursorTop moves the cursor of tab to index and scrolls it to the top of
+// the list, as far as the last page allows.
+func (m *Model) setCursorTop(tab *Tab, index int) {
+	tab.Cursor = index
+	tab.ViewOffset = index
+	m.updateViewportContent()
+	m.updateSidebarContent()
+}
+
+// renderTimeJumpOverlay renders the jump to time dialog.
+func (m Model) renderTimeJumpOverlay() string {
+	title := m.Styles.SidebarTitle.Render("Jump to time")
+
+	help := m.Styles.HelpBar.Render("Enter jump • Esc cancel")
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		"",
+		"First entry at or after a duration ago, a time of day or a date",
+		"",
+		m.JumpInput.View(),
+		"",
+		help,
+	)
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorPrimary).
+		Padding(1, 2).
+		Width(m.Width * 2 / 3).
+		Align(lipgloss.Left)
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height,
+		lipgloss.Center,
+		lipgloss.Center,
+		modalStyle.Render(content),
+	)
+}
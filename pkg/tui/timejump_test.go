@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJumpTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.Local)
+
+	got, err := parseJumpTime("15m", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-15*time.Minute), got)
+
+	got, err = parseJumpTime("-1h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-time.Hour), got)
+
+	got, err = parseJumpTime("2024-01-02T10:30:00Z", now)
+	require.NoError(t, err)
+	assert.True(t, got.Equal(time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC)))
+
+	got, err = parseJumpTime("2024-01-02 10:30", now)
+	require.NoError(t, err)
+	assert.True(t, got.Equal(time.Date(2024, 1, 2, 10, 30, 0, 0, time.Local)))
+
+	got, err = parseJumpTime("10:30", now)
+	require.NoError(t, err)
+	assert.Equal(t, 10, got.Hour())
+	assert.Equal(t, 30, got.Minute())
+
+	_, err = parseJumpTime("yesterday", now)
+	assert.Error(t, err)
+}
+
+func TestJumpToTime(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) client.LogEntry {
+		return client.LogEntry{Timestamp: base.Add(time.Duration(minutes) * time.Minute), Message: "m"}
+	}
+	m := newFacetTestModel([]client.LogEntry{at(10), at(20), at(30), at(40)})
+	tab := m.Tabs[0]
+	tab.Cursor = 3
+
+	jump := func(value string) {
+		m = pressFacetKey(m, "t")
+		require.Equal(t, FocusTimeJump, m.Focus)
+		m.JumpInput.SetValue(value)
+		m = pressFacetKey(m, "enter")
+		assert.Equal(t, FocusList, m.Focus)
+	}
+
+	jump("2024-01-01T12:25:00Z")
+	assert.Equal(t, 2, tab.Cursor, "first entry at or after the time")
+	assert.LessOrEqual(t, tab.ViewOffset, tab.Cursor)
+
+	jump("2024-01-01T12:20:00Z")
+	assert.Equal(t, 1, tab.Cursor, "an entry at the time itself")
+
+	jump("2024-01-01T13:00:00Z")
+	assert.Equal(t, 3, tab.Cursor)
+	assert.Contains(t, m.Messages[len(m.Messages)-1].Text, "moved to the newest entry")
+
+	jump("2024-01-01T12:00:00Z")
+	assert.Equal(t, 0, tab.Cursor, "no older pages")
+	assert.Contains(t, m.Messages[len(m.Messages)-1].Text, "moved to the oldest entry")
+
+	// Invalid input keeps the dialog open
+	m = pressFacetKey(m, "t")
+	m.JumpInput.SetValue("soon")
+	m = pressFacetKey(m, "enter")
+	assert.Equal(t, FocusTimeJump, m.Focus)
+	assert.Contains(t, m.Messages[len(m.Messages)-1].Text, "invalid time")
+	m = pressFacetKey(m, "esc")
+	assert.Equal(t, FocusList, m.Focus)
+}
+
+func TestJumpToTime_LoadsOlderPages(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) client.LogEntry {
+		return client.LogEntry{Timestamp: base.Add(time.Duration(minutes) * time.Minute), Message: "m"}
+	}
+	m := newFacetTestModel([]client.LogEntry{at(30), at(40)})
+	tab := m.Tabs[0]
+	tab.Cursor = 1
+	tab.PaginationInfo = &client.PaginationInfo{HasMore: true, NextPageToken: "p1"}
+
+	m = pressFacetKey(m, "t")
+	m.JumpInput.SetValue("2024-01-01T12:05:00Z")
+	m = pressFacetKey(m, "enter")
+	require.False(t, m.JumpTarget.IsZero())
+	assert.True(t, tab.LoadingMore)
+
+	// A page still after the target loads the next one
+	page := func(hasMore bool, entries ...client.LogEntry) {
+		updated, _ := m.Update(LogEntryMsg{TabID: tab.ID, Entries: entries, IsPagination: true,
+			PaginationInfo: &client.PaginationInfo{HasMore: hasMore, NextPageToken: "p2"}})
+		m = updated.(Model)
+	}
+	page(true, at(20))
+	assert.False(t, m.JumpTarget.IsZero())
+	assert.True(t, tab.LoadingMore)
+
+	page(true, at(0), at(10))
+	assert.True(t, m.JumpTarget.IsZero())
+	assert.Equal(t, 1, tab.Cursor, "the 12:10 entry")
+	assert.LessOrEqual(t, tab.ViewOffset, tab.Cursor)
+}
@@ -0,0 +1,467 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Field names tried, in order, to read the trace ID, the service of the lane
+// and the duration of an entry.
+var (
+	traceIDFields = []string{"trace_id", "traceId", "traceID", "trace.id"}
+	serviceFields = []string{"service", "service.name", "app"}
+	latencyFields = []string{"duration_ms", "latency_ms", "elapsed_ms", "duration", "latency", "elapsed"}
+)
+
+// traceLaneWidth bounds the width of one lane column in the timeline.
+const traceLaneWidth = 12
+
+// traceSpan is one entry of a trace placed on the timeline.
+type traceSpan struct {
+	Entry    client.LogEntry
+	Lane     string
+	Offset   time.Duration // From the first entry of the trace
+	Duration time.Duration // Zero when the entry has no latency field
+}
+
+// TraceQueryMsg delivers entries re-queried for the trace overlay.
+type TraceQueryMsg struct {
+	TraceID string
+	Entries []client.LogEntry
+	Err     error
+}
+
+// fieldString returns the first of names set on entry, as a string.
+func fieldString(entry client.LogEntry, names []string) (string, string) {
+	for _, name := range names {
+		if v, ok := entry.Fields[name]; ok && v != nil {
+			if s := fmt.Sprint(v); s != "" {
+				return name, s
+			}
+		}
+	}
+	return "", ""
+}
+
+// entryDuration reads the latency of entry. Plain numbers are milliseconds;
+// strings may also be Go durations like "152ms".
+func entryDuration(entry client.LogEntry) time.Duration {
+	for _, name := range latencyFields {
+		v, ok := entry.Fields[name]
+		if !ok {
+			continue
+		}
+		switch v := v.(type) {
+		case float64:
+			return time.Duration(v * float64(time.Millisecond))
+		case int:
+			return time.Duration(v) * time.Millisecond
+		case int64:
+			return time.Duration(v) * time.Millisecond
+		case string:
+			if d, err := time.ParseDuration(v); err == nil {
+				return d
+			}
+			if ms, err := strconv.ParseFloat(v, 64); err == nil {
+				return time.Duration(ms * float64(time.Millisecond))
+			}
+		}
+	}
+	return 0
+}
+
+// traceLane names the lane of an entry: its service, prefixed by its context
+// so the same service in two contexts gets two lanes.
+func traceLane(entry client.LogEntry) string {
+	_, service := fieldString(entry, serviceFields)
+	if service == "" {
+		service = "-"
+	}
+	if entry.ContextID == "" {
+		return service
+	}
+	return entry.ContextID + "/" + service
+}
+
+// buildTraceSpans returns the entries carrying traceID ordered by time.
+// Entries present more than once (e.g. loaded in two tabs) are kept once.
+func buildTraceSpans(entries []client.LogEntry, traceID string) []traceSpan {
+	seen := make(map[string]bool)
+	var matched []client.LogEntry
+	for _, entry := range entries {
+		if _, id := fieldString(entry, traceIDFields); id != traceID {
+			continue
+		}
+		key := entry.ContextID + "|" + client.EntryID(entry)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		matched = append(matched, entry)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Timestamp.Before(matched[j].Timestamp)
+	})
+
+	spans := make([]traceSpan, 0, len(matched))
+	for _, entry := range matched {
+		var offset time.Duration
+		if !entry.Timestamp.IsZero() && !matched[0].Timestamp.IsZero() {
+			offset = entry.Timestamp.Sub(matched[0].Timestamp)
+		}
+		spans = append(spans, traceSpan{
+			Entry:    entry,
+			Lane:     traceLane(entry),
+			Offset:   offset,
+			Duration: entryDuration(entry),
+		})
+	}
+	return spans
+}
+
+// traceLanes returns the lanes of spans in order of first appearance.
+func traceLanes(spans []traceSpan) []string {
+	var lanes []string
+	for _, s := range spans {
+		if indexOf(lanes, s.Lane) < 0 {
+			lanes = append(lanes, s.Lane)
+		}
+	}
+	return lanes
+}
+
+// allLoadedEntries returns the entries of every open tab, the current one first.
+func (m Model) allLoadedEntries() []client.LogEntry {
+	current := m.CurrentTab()
+	var entries []client.LogEntry
+	if current != nil {
+		entries = append(entries, withContextID(current.Entries, current.ContextID)...)
+	}
+	for _, tab := range m.Tabs {
+		if tab != current {
+			entries = append(entries, withContextID(tab.Entries, tab.ContextID)...)
+		}
+	}
+	return entries
+}
+
+// withContextID fills the context of entries that don't carry one.
+func withContextID(entries []client.LogEntry, contextID string) []client.LogEntry {
+	out := make([]client.LogEntry, len(entries))
+	for i, e := range entries {
+		if e.ContextID == "" {
+			e.ContextID = contextID
+		}
+		out[i] = e
+	}
+	return out
+}
+
+// openTrace shows the timeline of the selected entry's trace.
+func (m *Model) openTrace() tea.Cmd {
+	tab := m.CurrentTab()
+	if tab == nil || tab.Cursor >= len(tab.Entries) {
+		return nil
+	}
+	field, id := fieldString(tab.Entries[tab.Cursor], traceIDFields)
+	if id == "" {
+		return m.showStatusMessage("No trace ID on this entry")
+	}
+	m.TraceField = field
+	m.TraceID = id
+	m.TraceSpans = buildTraceSpans(m.allLoadedEntries(), id)
+	m.TraceCursor = 0
+	m.TraceOffset = 0
+	m.Focus = FocusTrace
+	return nil
+}
+
+// traceQueryCmd queries every context of the open tabs for the trace, to
+// find entries outside the loaded buffer.
+func (m *Model) traceQueryCmd() tea.Cmd {
+	searchFactory := m.SearchFactory
+	runtimeVars := m.RuntimeVars
+	traceID, field := m.TraceID, m.TraceField
+
+	type target struct {
+		contextID string
+		inherits  []string
+		search    client.LogSearch
+	}
+	var targets []target
+	seen := make(map[string]bool)
+	for _, tab := range m.Tabs {
+		if seen[tab.ContextID] {
+			continue
+		}
+		seen[tab.ContextID] = true
+		search := client.LogSearch{}
+		if tab.Search != nil {
+			search.Range = tab.Search.Range
+			search.FieldExtraction = tab.Search.FieldExtraction
+		}
+		search.Filter = &client.Filter{Field: field, Value: traceID}
+		targets = append(targets, target{contextID: tab.ContextID, inherits: tab.Inherits, search: search})
+	}
+
+	return func() tea.Msg {
+		if searchFactory == nil {
+			return TraceQueryMsg{TraceID: traceID, Err: fmt.Errorf("no search factory configured")}
+		}
+		var entries []client.LogEntry
+		for _, t := range targets {
+			log.Printf("[DEBUG] TUI traceQueryCmd: contextID=%s, %s=%s", t.contextID, field, traceID)
+			result, err := searchFactory.GetSearchResult(context.Background(), t.contextID, t.inherits, t.search, runtimeVars)
+			if err != nil {
+				return TraceQueryMsg{TraceID: traceID, Err: err}
+			}
+			found, _, err := result.GetEntries(context.Background())
+			if err != nil {
+				return TraceQueryMsg{TraceID: traceID, Err: err}
+			}
+			entries = append(entries, withContextID(found, t.contextID)...)
+		}
+		return TraceQueryMsg{TraceID: traceID, Entries: entries}
+	}
+}
+
+// handleTraceQuery merges re-queried entries into the open timeline.
+func (m *Model) handleTraceQuery(msg TraceQueryMsg) tea.Cmd {
+	if msg.TraceID != m.TraceID {
+		return nil
+	}
+	m.TraceLoading = false
+	if msg.Err != nil {
+		log.Printf("[WARN] TUI handleTraceQuery: %v", msg.Err)
+		return m.showStatusMessage("Trace query failed: " + msg.Err.Error())
+	}
+	all := make([]client.LogEntry, 0, len(m.TraceSpans)+len(msg.Entries))
+	for _, s := range m.TraceSpans {
+		all = append(all, s.Entry)
+	}
+	all = append(all, msg.Entries...)
+	m.TraceSpans = buildTraceSpans(all, m.TraceID)
+	return m.showStatusMessage(fmt.Sprintf("Trace has %d entries", len(m.TraceSpans)))
+}
+
+// traceVisibleRows returns how many timeline rows fit in the overlay.
+func (m Model) traceVisibleRows() int {
+	rows := m.Height - 14
+	if rows < 3 {
+		rows = 3
+	}
+	return rows
+}
+
+// moveTraceCursor moves the timeline cursor and scrolls to keep it visible.
+func (m *Model) moveTraceCursor(delta int) {
+	m.TraceCursor += delta
+	if m.TraceCursor >= len(m.TraceSpans) {
+		m.TraceCursor = len(m.TraceSpans) - 1
+	}
+	if m.TraceCursor < 0 {
+		m.TraceCursor = 0
+	}
+	visible := m.traceVisibleRows()
+	if m.TraceCursor < m.TraceOffset {
+		m.TraceOffset = m.TraceCursor
+	} else if m.TraceCursor >= m.TraceOffset+visible {
+		m.TraceOffset = m.TraceCursor - visible + 1
+	}
+}
+
+// handleTrace handles input when the trace overlay has focus
+func (m Model) handleTrace(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "T", "q":
+		m.Focus = FocusList
+		return m, nil
+
+	case "up", "k":
+		m.moveTraceCursor(-1)
+
+	case "down", "j":
+		m.moveTraceCursor(1)
+
+	case "pgup", "ctrl+u":
+		m.moveTraceCursor(-m.traceVisibleRows())
+
+	case "pgdown", "ctrl+d":
+		m.moveTraceCursor(m.traceVisibleRows())
+
+	case "home", "g":
+		m.moveTraceCursor(-len(m.TraceSpans))
+
+	case "end", "G":
+		m.moveTraceCursor(len(m.TraceSpans))
+
+	case "r":
+		m.TraceLoading = true
+		return m, m.traceQueryCmd()
+
+	case "enter":
+		// Jump to the entry when it is loaded in the current tab
+		if m.TraceCursor < len(m.TraceSpans) {
+			if tab := m.CurrentTab(); tab != nil {
+				target := client.EntryID(m.TraceSpans[m.TraceCursor].Entry)
+				for i, e := range tab.Entries {
+					if client.EntryID(e) == target {
+						tab.Cursor = i
+						break
+					}
+				}
+			}
+		}
+		m.Focus = FocusList
+		m.updateViewportContent()
+		m.updateSidebarContent()
+	}
+
+	return m, nil
+}
+
+// formatTraceDuration renders a duration compactly for the timeline.
+func formatTraceDuration(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	default:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+}
+
+// traceMark returns the mark of lane on row i: a start mark for the row's own
+// entry, a bar while an earlier span of the lane is still running.
+func traceMark(spans []traceSpan, i int, lane string) string {
+	if spans[i].Lane == lane {
+		if spans[i].Duration > 0 {
+			return "┳"
+		}
+		return "●"
+	}
+	for k := i - 1; k >= 0; k-- {
+		s := spans[k]
+		if s.Lane == lane && s.Duration > 0 && spans[i].Offset < s.Offset+s.Duration {
+			return "┃"
+		}
+	}
+	return "·"
+}
+
+// renderTraceOverlay renders the trace as a vertical gantt: time flows down,
+// each lane is a column and running spans draw a bar down their lane.
+func (m Model) renderTraceOverlay() string {
+	title := m.Styles.SidebarTitle.Render("Trace: " + m.TraceID)
+
+	spans := m.TraceSpans
+	lanes := traceLanes(spans)
+	var total time.Duration
+	for _, s := range spans {
+		if end := s.Offset + s.Duration; end > total {
+			total = end
+		}
+	}
+	status := fmt.Sprintf("%d entries across %d lanes, %s", len(spans), len(lanes), formatTraceDuration(total))
+	if m.TraceLoading {
+		status += " • querying..."
+	}
+	subtitle := lipgloss.NewStyle().Foreground(ColorMuted).Render(status)
+
+	laneWidth := 3
+	for _, lane := range lanes {
+		if w := len(lane); w > laneWidth {
+			laneWidth = w
+		}
+	}
+	if laneWidth > traceLaneWidth {
+		laneWidth = traceLaneWidth
+	}
+
+	width := m.Width * 3 / 4
+	headerCells := make([]string, 0, len(lanes))
+	for _, lane := range lanes {
+		headerCells = append(headerCells, fmt.Sprintf("%-*s", laneWidth, truncateForDisplay(lane, laneWidth)))
+	}
+	header := lipgloss.NewStyle().Foreground(ColorSecondary).Bold(true).
+		Render(fmt.Sprintf("  %9s %8s  %s", "offset", "duration", strings.Join(headerCells, " ")))
+
+	visible := m.traceVisibleRows()
+	start := m.TraceOffset
+	if start > len(spans) {
+		start = len(spans)
+	}
+	end := start + visible
+	if end > len(spans) {
+		end = len(spans)
+	}
+
+	messageWidth := width - 24 - len(lanes)*(laneWidth+1)
+	if messageWidth < 10 {
+		messageWidth = 10
+	}
+
+	rows := make([]string, 0, visible+1)
+	for i := start; i < end; i++ {
+		s := spans[i]
+		cells := make([]string, 0, len(lanes))
+		for _, lane := range lanes {
+			cells = append(cells, fmt.Sprintf("%-*s", laneWidth, traceMark(spans, i, lane)))
+		}
+		duration := "-"
+		if s.Duration > 0 {
+			duration = formatTraceDuration(s.Duration)
+		}
+		style := m.Styles.LogEntry
+		if i == m.TraceCursor {
+			style = m.Styles.LogSelected
+		}
+		message := truncateForDisplay(strings.ReplaceAll(s.Entry.Message, "\n", " "), messageWidth)
+		rows = append(rows, style.Render(fmt.Sprintf("  %9s %8s  %s %s",
+			"+"+formatTraceDuration(s.Offset), duration, strings.Join(cells, " "), message)))
+	}
+	if len(spans) == 0 {
+		rows = append(rows, lipgloss.NewStyle().Foreground(ColorMuted).Render("  (no entries for this trace)"))
+	}
+	if len(spans) > visible {
+		rows = append(rows, m.Styles.SidebarValue.Foreground(ColorMuted).
+			Render(fmt.Sprintf("  rows %d-%d of %d", start+1, end, len(spans))))
+	}
+
+	help := m.Styles.HelpBar.Render("↑↓/jk navigate • PgUp/PgDn scroll • r re-query • Enter go to entry • Esc close")
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		subtitle,
+		"",
+		header,
+		strings.Join(rows, "\n"),
+		"",
+		help,
+	)
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorPrimary).
+		Padding(1, 2).
+		Width(width).
+		Align(lipgloss.Left)
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height,
+		lipgloss.Center,
+		lipgloss.Center,
+		modalStyle.Render(content),
+	)
+}
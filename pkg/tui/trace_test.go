@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bascanada/logviewer/pkg/log/client"
+	"github.com/bascanada/logviewer/pkg/ty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTraceSpans(t *testing.T) {
+	base := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	entries := []client.LogEntry{
+		{Timestamp: base.Add(30 * time.Millisecond), Message: "db", ContextID: "db-ctx", Fields: ty.MI{"trace_id": "t1", "service": "db", "duration_ms": float64(20)}},
+		{Timestamp: base, Message: "start", ContextID: "api-ctx", Fields: ty.MI{"trace_id": "t1", "service": "api", "latency": "120ms"}},
+		{Timestamp: base.Add(10 * time.Millisecond), Message: "other", Fields: ty.MI{"trace_id": "t2"}},
+		{Timestamp: base.Add(50 * time.Millisecond), Message: "cache", ContextID: "api-ctx", Fields: ty.MI{"traceId": "t1", "service": "api"}},
+	}
+	// Loaded in two tabs: kept once
+	entries = append(entries, entries[1])
+
+	spans := buildTraceSpans(entries, "t1")
+	require.Len(t, spans, 3)
+	assert.Equal(t, "start", spans[0].Entry.Message)
+	assert.Equal(t, 120*time.Millisecond, spans[0].Duration)
+	assert.Equal(t, 30*time.Millisecond, spans[1].Offset)
+	assert.Equal(t, 20*time.Millisecond, spans[1].Duration)
+	assert.Zero(t, spans[2].Duration, "no latency field is an instantaneous mark")
+
+	assert.Equal(t, []string{"api-ctx/api", "db-ctx/db"}, traceLanes(spans))
+	assert.Equal(t, "┳", traceMark(spans, 0, "api-ctx/api"))
+	assert.Equal(t, "┃", traceMark(spans, 1, "api-ctx/api"), "api span still running")
+	assert.Equal(t, "●", traceMark(spans, 2, "api-ctx/api"))
+	assert.Equal(t, "·", traceMark(spans, 2, "db-ctx/db"), "db span ended")
+}
+
+func TestTraceOverlay(t *testing.T) {
+	base := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	var entries []client.LogEntry
+	for i := 0; i < 50; i++ {
+		entries = append(entries, client.LogEntry{
+			Timestamp: base.Add(time.Duration(i) * time.Millisecond),
+			Message:   "step",
+			Fields:    ty.MI{"trace_id": "abc", "app": "api"},
+		})
+	}
+	entries = append(entries, client.LogEntry{Message: "no trace"})
+
+	m := newFacetTestModel(entries, "trace_id", "app")
+	m.Height = 30
+
+	t.Run("opens on the selected trace and scrolls", func(t *testing.T) {
+		m := pressFacetKey(m, "T")
+		require.Equal(t, FocusTrace, m.Focus)
+		assert.Equal(t, "abc", m.TraceID)
+		assert.Len(t, m.TraceSpans, 50)
+		assert.Contains(t, m.View(), "Trace: abc")
+
+		m = pressFacetKey(m, "G")
+		assert.Equal(t, 49, m.TraceCursor)
+		assert.Equal(t, 50-m.traceVisibleRows(), m.TraceOffset)
+		assert.Contains(t, m.View(), "of 50")
+
+		m = pressFacetKey(m, "esc")
+		assert.Equal(t, FocusList, m.Focus)
+	})
+
+	t.Run("entry without trace id shows a message", func(t *testing.T) {
+		m := m
+		m.Tabs[0].Cursor = 50
+		m = pressFacetKey(m, "T")
+		assert.Equal(t, FocusList, m.Focus)
+		assert.True(t, strings.Contains(m.StatusBar.View(), "No trace ID"))
+	})
+}
+
+func TestTraceQuery(t *testing.T) {
+	store := NewInMemoryLogStore()
+	store.AddEntries("ctx", []client.LogEntry{
+		{Message: "loaded", Fields: ty.MI{"trace_id": "abc"}},
+		{Message: "older", Fields: ty.MI{"trace_id": "abc"}},
+		{Message: "unrelated", Fields: ty.MI{"trace_id": "zzz"}},
+	})
+	m := New(nil, nil, &MockSearchFactory{Store: store})
+	m.Tabs = []*Tab{{ID: "tab-1", ContextID: "ctx", Entries: store.Entries["ctx"][:1]}}
+
+	m = pressFacetKey(m, "T")
+	require.Len(t, m.TraceSpans, 1)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(Model)
+	require.NotNil(t, cmd)
+	assert.True(t, m.TraceLoading)
+
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	assert.False(t, m.TraceLoading)
+	assert.Len(t, m.TraceSpans, 2)
+}
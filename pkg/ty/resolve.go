@@ -1,10 +1,55 @@
 package ty
 
 import (
+	"fmt"
 	"os"
 	"regexp"
+	"strconv"
+	"strings"
 )
 
+// Variable types checked by ValidateVar. Any other type, including "string",
+// accepts every value.
+const (
+	VarTypeInt   = "int"
+	VarTypeBool  = "bool"
+	VarTypeEnum  = "enum"
+	VarTypeRegex = "regex"
+)
+
+// ValidateVar checks that value is valid for a variable of the given type.
+// When allowed is not empty the value must also be one of its entries.
+func ValidateVar(name, varType string, allowed []string, value string) error {
+	switch varType {
+	case VarTypeInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("variable %s: %q is not an int", name, value)
+		}
+	case VarTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("variable %s: %q is not a bool", name, value)
+		}
+	case VarTypeRegex:
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("variable %s: %q is not a valid regex: %w", name, value, err)
+		}
+	case VarTypeEnum:
+		if len(allowed) == 0 {
+			return fmt.Errorf("variable %s: enum type requires allowedValues", name)
+		}
+	}
+
+	if len(allowed) > 0 {
+		for _, a := range allowed {
+			if a == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("variable %s: %q is not one of %s", name, value, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
 // ResolveVars resolves shell-style variable references in the input string using the provided runtime variables and environment variables.
 func ResolveVars(input string, runtimeVars map[string]string) string {
 	re := regexp.MustCompile(`\$(\{([a-zA-Z_][a-zA-Z0-9_]*)(:-(.*))?\}|\$([a-zA-Z_][a-zA-Z0-9_]*))`)
@@ -96,3 +96,22 @@ func TestMS_ResolveVariablesWith_Priority(t *testing.T) {
 
 	assert.Equal(t, expected, resolvedMS)
 }
+
+func TestValidateVar(t *testing.T) {
+	assert.NoError(t, ValidateVar("size", VarTypeInt, nil, "42"))
+	assert.EqualError(t, ValidateVar("size", VarTypeInt, nil, "many"), `variable size: "many" is not an int`)
+
+	assert.NoError(t, ValidateVar("debug", VarTypeBool, nil, "true"))
+	assert.Error(t, ValidateVar("debug", VarTypeBool, nil, "maybe"))
+
+	assert.NoError(t, ValidateVar("pattern", VarTypeRegex, nil, `^req-\d+$`))
+	assert.ErrorContains(t, ValidateVar("pattern", VarTypeRegex, nil, "req-("), "not a valid regex")
+
+	envs := []string{"dev", "staging", "prod"}
+	assert.NoError(t, ValidateVar("env", VarTypeEnum, envs, "prod"))
+	assert.EqualError(t, ValidateVar("env", VarTypeEnum, envs, "qa"), `variable env: "qa" is not one of dev, staging, prod`)
+	assert.Error(t, ValidateVar("env", VarTypeEnum, nil, "prod"), "enum without allowed values")
+
+	assert.NoError(t, ValidateVar("name", "string", nil, "anything"))
+	assert.Error(t, ValidateVar("name", "", []string{"a"}, "b"), "allowed values apply to any type")
+}
@@ -37,3 +37,16 @@ func AddField(k string, v interface{}, fields *UniSet[string]) {
 		log.Println("invalid type for field " + k)
 	}
 }
+
+// AddFieldName records the field names AddField would add for v, without
+// keeping the values.
+func AddFieldName(k string, v interface{}, names map[string]struct{}) {
+	switch value := v.(type) {
+	case string:
+		names[k] = struct{}{}
+	case map[string]interface{}:
+		for kk, vv := range value {
+			AddFieldName(k+"."+kk, vv, names)
+		}
+	}
+}